@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// ciCmd generates a CI pipeline for the detected stack.
+var ciCmd = &cobra.Command{
+	Use:   "ci github|gitlab [path]",
+	Short: "Generate a GitHub Actions or GitLab CI pipeline for the detected stack",
+	Long: `ci generates a CI pipeline from the same detection as the dev environment:
+
+  github  .github/workflows/ci.yml (GitHub Actions)
+  gitlab  .gitlab-ci.yml (GitLab CI/CD)
+
+The test job runs PostgreSQL and Redis as service containers with DATABASE_URL
+and REDIS_URL pointing at them, caches the detected package manager's
+downloads, installs the dependencies, applies migrations, and runs the detected
+test command. The image job then builds the project's own Dockerfile (its final
+stage) or, without one, the generated .devcontainer/Dockerfile. With --prebuild,
+a prebuild job builds the dev container image and pushes it to prebuild.image
+from the default branch, for devcontainer.json's cacheFrom.`,
+	Args:      cobra.RangeArgs(1, 2),
+	ValidArgs: generator.CIProviders,
+	RunE:      runCI,
+}
+
+func init() {
+	ciCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview the pipeline without writing it")
+	ciCmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing pipeline file")
+	ciCmd.Flags().BoolVar(&prebuild, "prebuild", false, "Add a job that prebuilds the dev container image (prebuild.image) for caching")
+	rootCmd.AddCommand(ciCmd)
+}
+
+func runCI(cmd *cobra.Command, args []string) error {
+	provider := args[0]
+	file := generator.CIFile(provider)
+	if file == "" {
+		return fmt.Errorf("unknown CI provider %q: expected one of %s", provider, strings.Join(generator.CIProviders, ", "))
+	}
+
+	absPath, err := resolveProjectPath(args[1:])
+	if err != nil {
+		return err
+	}
+
+	projectName := filepath.Base(absPath)
+	fmt.Fprintf(out, "📂 Analyzing %s...\n", absPath)
+
+	detection, err := detectProject(absPath)
+	if err != nil {
+		return err
+	}
+
+	if !dryRun && !force {
+		if _, err := os.Stat(filepath.Join(absPath, filepath.FromSlash(file))); err == nil {
+			return &generator.ConflictError{Files: []string{file}}
+		}
+	}
+
+	fmt.Fprintf(out, "\n📝 Generating %s...\n", file)
+	gen := generator.NewCIGenerator()
+	if dryRun {
+		content, err := gen.GenerateContent(detection, projectName, provider)
+		if err != nil {
+			return err
+		}
+		previewFile(file, content)
+	} else {
+		action := fileAction(absPath, file)
+		if err := gen.Generate(detection, absPath, projectName, provider); err != nil {
+			return err
+		}
+		fileWritten(file, action)
+	}
+
+	for _, service := range gen.SkippedServices(detection) {
+		warn("%s isn't run in CI: add a service container for it if the tests need it", service)
+	}
+	if detection.TestCommand == "" {
+		warn("No test command detected: set testing.command in .dockstart.yml and regenerate")
+	}
+
+	fmt.Fprintln(out, "\n✨ Done!")
+	return nil
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/docker"
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// cleanDown stops the compose stack before removing files
+	cleanDown bool
+
+	// cleanVolumes also removes the compose project's named volumes
+	cleanVolumes bool
+
+	// assumeYes skips confirmation prompts
+	assumeYes bool
+)
+
+// cleanCmd removes generated files and, optionally, the running stack and its volumes.
+var cleanCmd = &cobra.Command{
+	Use:   "clean [path]",
+	Short: "Remove generated .devcontainer files and, optionally, the stack and its volumes",
+	Long: `clean removes the .devcontainer files dockstart generated. Files you added
+yourself, database backups, and uploaded files are kept.
+
+With --down, the compose stack is stopped first. With --volumes, the project's
+named volumes (database data, caches, model downloads, ...) are removed too,
+including volumes left behind by services no longer in docker-compose.yml.
+Every deletion is confirmed unless --yes is given.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runClean,
+}
+
+func init() {
+	cleanCmd.Flags().BoolVar(&cleanDown, "down", false, "Stop and remove the compose stack's containers and networks")
+	cleanCmd.Flags().BoolVar(&cleanVolumes, "volumes", false, "Also remove the project's named volumes (implies --down)")
+	cleanCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Don't ask for confirmation")
+	cleanCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be removed without removing anything")
+	rootCmd.AddCommand(cleanCmd)
+}
+
+func runClean(cmd *cobra.Command, args []string) error {
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return err
+	}
+
+	// Prompts can't be answered when stdout carries JSON
+	if jsonOutput() && !assumeYes && !dryRun {
+		return fmt.Errorf("--yes is required with --output json")
+	}
+
+	fmt.Fprintf(out, "📂 Cleaning %s...\n", absPath)
+	compose := newCompose(absPath)
+	if jsonOutput() {
+		compose.Stdout = os.Stderr
+	}
+
+	// Stop the stack first: docker compose needs the compose file
+	if cleanDown || cleanVolumes {
+		if err := cleanStack(compose); err != nil {
+			return err
+		}
+	}
+
+	files := generator.ManagedFiles(absPath)
+	if len(files) == 0 {
+		fmt.Fprintln(out, "\n   No dockstart-generated files found")
+		return nil
+	}
+
+	fmt.Fprintln(out, "\n🗑️  Generated files:")
+	for _, f := range files {
+		fmt.Fprintf(out, "   %s\n", f)
+	}
+	// The database volumes only accept the passwords they were created with
+	if !cleanVolumes && slices.Contains(files, filepath.FromSlash(generator.CredentialsFile)) {
+		fmt.Fprintf(out, "\n   ⚠️  The data volumes keep the passwords in %s; regenerating\n", generator.CredentialsFile)
+		fmt.Fprintln(out, "   creates new ones. Keep a copy, or remove the volumes with --volumes")
+	}
+	if dryRun {
+		for _, f := range files {
+			recordFile(filepath.ToSlash(f), "would-remove")
+		}
+		fmt.Fprintln(out, "\n   (dry run - nothing removed)")
+		return nil
+	}
+	if !confirm(fmt.Sprintf("Remove %d files?", len(files))) {
+		fmt.Fprintln(out, "   Skipped")
+		return nil
+	}
+
+	remaining, err := generator.RemoveManagedFiles(absPath)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		recordFile(filepath.ToSlash(f), "removed")
+	}
+	fmt.Fprintf(out, "   ✅ Removed %d files\n", len(files))
+
+	if len(remaining) > 0 {
+		fmt.Fprintln(out, "\n   Kept (not generated by dockstart):")
+		for _, f := range remaining {
+			fmt.Fprintf(out, "   %s\n", f)
+			recordFile(filepath.ToSlash(f), "kept")
+		}
+	}
+
+	fmt.Fprintln(out, "\n✨ Clean complete!")
+	return nil
+}
+
+// cleanStack stops the compose stack and, with --volumes, removes its named volumes.
+func cleanStack(compose *docker.Compose) error {
+	if err := docker.Available(); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(compose.File); err == nil {
+		fmt.Fprintf(out, "\n🛑 Stopping %s...\n", compose.ProjectName)
+		if dryRun {
+			fmt.Fprintln(out, "   (dry run - would run docker compose down)")
+		} else if err := compose.Down(); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintln(out, "\n   No docker-compose.yml - skipping docker compose down")
+	}
+
+	if !cleanVolumes {
+		return nil
+	}
+
+	volumes, err := compose.Volumes()
+	if err != nil {
+		return err
+	}
+	if len(volumes) == 0 {
+		fmt.Fprintln(out, "   No volumes to remove")
+		return nil
+	}
+
+	fmt.Fprintln(out, "\n💾 Volumes:")
+	for _, v := range volumes {
+		fmt.Fprintf(out, "   %s\n", v)
+	}
+	if dryRun {
+		return nil
+	}
+	if !confirm(fmt.Sprintf("Remove %d volumes? Their data is lost permanently.", len(volumes))) {
+		fmt.Fprintln(out, "   Skipped")
+		return nil
+	}
+	if err := docker.RemoveVolumes(volumes); err != nil {
+		return err
+	}
+	report.VolumesRemoved = volumes
+	fmt.Fprintf(out, "   ✅ Removed %d volumes\n", len(volumes))
+	return nil
+}
+
+// confirm asks a yes/no question on stdin. Returns true if --yes was given.
+func confirm(question string) bool {
+	if assumeYes {
+		return true
+	}
+
+	fmt.Fprintf(out, "\n   %s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
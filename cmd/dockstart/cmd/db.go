@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/docker"
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// dbSnapshotDelete deletes the snapshot instead of taking it
+var dbSnapshotDelete bool
+
+// dbCmd groups the commands for the development databases.
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Snapshot and restore the development PostgreSQL databases",
+	Long: `db takes named snapshots of the development PostgreSQL databases and resets
+them to a snapshot, to branch database state during feature work and get back
+to it quickly.
+
+A snapshot is a copy PostgreSQL makes of each database in the same server
+(CREATE DATABASE ... TEMPLATE), kept until it is deleted. The commands run the
+generated .devcontainer/scripts/snapshot.sh and restore-snapshot.sh against the
+running stack; both close the databases' open connections while they copy.`,
+}
+
+// dbSnapshotCmd takes or deletes a snapshot.
+var dbSnapshotCmd = &cobra.Command{
+	Use:   "snapshot <name> [path]",
+	Short: "Copy the development databases into a named snapshot",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  runDBSnapshot,
+}
+
+// dbRestoreCmd resets the databases to a snapshot.
+var dbRestoreCmd = &cobra.Command{
+	Use:   "restore <name> [path]",
+	Short: "Replace the development databases with a snapshot",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  runDBRestore,
+}
+
+// dbSnapshotsCmd lists the snapshots.
+var dbSnapshotsCmd = &cobra.Command{
+	Use:   "snapshots [path]",
+	Short: "List the snapshots and their sizes",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runDBSnapshots,
+}
+
+func init() {
+	dbSnapshotCmd.Flags().BoolVar(&dbSnapshotDelete, "delete", false, "Delete the snapshot instead of taking it")
+	dbCmd.AddCommand(dbSnapshotCmd, dbRestoreCmd, dbSnapshotsCmd)
+	rootCmd.AddCommand(dbCmd)
+}
+
+func runDBSnapshot(cmd *cobra.Command, args []string) error {
+	if dbSnapshotDelete {
+		fmt.Fprintf(out, "🗑️  Deleting snapshot %s...\n", args[0])
+		return runSnapshotScript(args[1:], generator.SnapshotScript, "delete", args[0])
+	}
+	fmt.Fprintf(out, "📸 Taking snapshot %s...\n", args[0])
+	return runSnapshotScript(args[1:], generator.SnapshotScript, args[0])
+}
+
+func runDBRestore(cmd *cobra.Command, args []string) error {
+	fmt.Fprintf(out, "⏪ Restoring snapshot %s...\n", args[0])
+	return runSnapshotScript(args[1:], generator.RestoreSnapshotScript, args[0])
+}
+
+func runDBSnapshots(cmd *cobra.Command, args []string) error {
+	return runSnapshotScript(args, generator.SnapshotScript, "list")
+}
+
+// runSnapshotScript runs one of the generated snapshot scripts for the project
+// at args' path, under the compose project dockstart up and VS Code use.
+func runSnapshotScript(args []string, script string, scriptArgs ...string) error {
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(absPath, filepath.FromSlash(script))
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("%s not found: run dockstart to generate it (it needs PostgreSQL in the stack)", script)
+	}
+	if err := docker.Available(); err != nil {
+		return err
+	}
+
+	logger.Debug("running script", "script", script, "args", scriptArgs)
+	run := exec.Command("bash", append([]string{path}, scriptArgs...)...)
+	run.Env = append(os.Environ(), "COMPOSE_PROJECT_NAME="+docker.ProjectName(absPath))
+	run.Stdout = os.Stdout
+	if jsonOutput() {
+		// Keep stdout for the JSON result
+		run.Stdout = os.Stderr
+	}
+	run.Stderr = os.Stderr
+	if err := run.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w", filepath.Base(script), err)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jpequegn/dockstart/internal/docker"
+	"github.com/jpequegn/dockstart/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// devcontainerRemoveExisting replaces a running dev container on up
+	devcontainerRemoveExisting bool
+
+	// devcontainerImageName and devcontainerPush tag and publish the built image
+	devcontainerImageName string
+	devcontainerPush      bool
+)
+
+// devcontainerCmd groups the commands that run the dev containers CLI.
+var devcontainerCmd = &cobra.Command{
+	Use:   "devcontainer",
+	Short: "Build and start the generated dev container with the devcontainers CLI",
+	Long: `devcontainer runs the devcontainers CLI (@devcontainers/cli) against the
+generated .devcontainer, the way VS Code's "Reopen in Container" does, without
+an editor: for CI jobs, and for editors without dev container support.
+
+The files are generated first when .devcontainer/devcontainer.json doesn't
+exist yet (existing files are kept unless --force is given). The CLI runs as
+devcontainer when installed, and through npx otherwise.`,
+}
+
+// devcontainerUpCmd builds and starts the dev container and checks it.
+var devcontainerUpCmd = &cobra.Command{
+	Use:   "up [path]",
+	Short: "Build and start the dev container, run its lifecycle commands, and wait until it is healthy",
+	Long: `up builds and starts the dev container with devcontainer up, which runs
+its lifecycle commands (onCreateCommand through postStartCommand) and fails
+when one of them does. It then waits for the dev container, and every service
+of a Compose dev container, to be running and healthy.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDevcontainerUp,
+}
+
+// devcontainerBuildCmd builds the dev container image.
+var devcontainerBuildCmd = &cobra.Command{
+	Use:   "build [path]",
+	Short: "Build the dev container image, tagged and pushed like a prebuild",
+	Long: `build builds the dev container image with devcontainer build. The image is
+tagged --image-name or, in prebuild mode, prebuild.image, and pushed with --push.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDevcontainerBuild,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{devcontainerUpCmd, devcontainerBuildCmd} {
+		cmd.Flags().BoolVar(&force, "force", false, "Regenerate and overwrite existing files")
+	}
+	devcontainerUpCmd.Flags().BoolVar(&devcontainerRemoveExisting, "remove-existing", false, "Replace the dev container if one is already running")
+	devcontainerUpCmd.Flags().DurationVar(&upTimeout, "timeout", 3*time.Minute, "How long to wait for the dev container and services to become healthy")
+	devcontainerBuildCmd.Flags().StringVar(&devcontainerImageName, "image-name", "", "Tag the image (default: prebuild.image in prebuild mode)")
+	devcontainerBuildCmd.Flags().BoolVar(&devcontainerPush, "push", false, "Push the image after building it")
+	devcontainerCmd.AddCommand(devcontainerUpCmd, devcontainerBuildCmd)
+	rootCmd.AddCommand(devcontainerCmd)
+}
+
+func runDevcontainerUp(cmd *cobra.Command, args []string) error {
+	absPath, cli, _, err := prepareDevcontainer(args)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\n🚀 Starting the dev container...")
+	start := time.Now()
+	result, err := cli.Up(devcontainerRemoveExisting)
+	logTiming("devcontainer up", start)
+	if err != nil {
+		return devcontainerError(result, err)
+	}
+	fmt.Fprintf(out, "   ✅ Lifecycle commands succeeded (%s in %s as %s)\n",
+		shortID(result.ContainerID), result.RemoteWorkspaceFolder, result.RemoteUser)
+
+	// A Compose dev container is the app service of its stack
+	if result.ComposeProjectName != "" {
+		compose := newCompose(absPath)
+		compose.ProjectName = result.ComposeProjectName
+		if err := waitForStack(compose); err != nil {
+			return err
+		}
+	} else if err := waitForContainer(result.ContainerID); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\n✨ Dev container is up!")
+	return nil
+}
+
+func runDevcontainerBuild(cmd *cobra.Command, args []string) error {
+	_, cli, detection, err := prepareDevcontainer(args)
+	if err != nil {
+		return err
+	}
+
+	image := devcontainerImageName
+	if image == "" && detection.Prebuild != nil {
+		image = detection.Prebuild.Image
+	}
+	if devcontainerPush && image == "" {
+		return fmt.Errorf("--push needs an image name: set --image-name or prebuild.image in .dockstart.yml")
+	}
+
+	fmt.Fprintln(out, "\n🏗️  Building the dev container image...")
+	start := time.Now()
+	result, err := cli.Build(image, devcontainerPush)
+	logTiming("devcontainer build", start)
+	if err != nil {
+		return devcontainerError(result, err)
+	}
+	if image != "" {
+		fmt.Fprintf(out, "   ✅ Built %s\n", image)
+		if devcontainerPush {
+			fmt.Fprintf(out, "   ✅ Pushed %s\n", image)
+		}
+	} else {
+		fmt.Fprintln(out, "   ✅ Built the dev container image")
+	}
+
+	fmt.Fprintln(out, "\n✨ Done!")
+	return nil
+}
+
+// prepareDevcontainer detects the project at args' path, generates its files
+// when they don't exist yet, and finds the devcontainers CLI.
+func prepareDevcontainer(args []string) (string, *docker.Devcontainer, *models.Detection, error) {
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	fmt.Fprintf(out, "📂 Analyzing %s...\n", absPath)
+	if err := docker.Available(); err != nil {
+		return "", nil, nil, err
+	}
+	cli, err := docker.NewDevcontainer(absPath)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	cli.Logger = logger
+
+	detection, err := detectProject(absPath)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	if _, err := os.Stat(filepath.Join(absPath, ".devcontainer", "devcontainer.json")); err == nil && !force {
+		logger.Info("generation skipped", "reason", "existing .devcontainer/devcontainer.json")
+		fmt.Fprintln(out, "\n📄 Using existing .devcontainer files (use --force to regenerate)")
+	} else if err := generateFiles(detection, absPath, filepath.Base(absPath)); err != nil {
+		return "", nil, nil, err
+	}
+	return absPath, cli, detection, nil
+}
+
+// waitForContainer waits until a dev container without Compose is running,
+// and healthy when its image has a healthcheck, recording it in the result.
+func waitForContainer(id string) error {
+	fmt.Fprintln(out, "\n⏳ Waiting for the dev container...")
+	deadline := time.Now().Add(upTimeout)
+	for {
+		status, err := docker.ContainerStatus(id, "devcontainer")
+		if err != nil {
+			return err
+		}
+		if status.IsReady() || status.IsFailed() || time.Now().After(deadline) {
+			recordContainer(status)
+			fmt.Fprintf(out, "   %s %s: %s\n", statusIcon(status), status.Service, status.Summary())
+			if !status.IsReady() {
+				return newExitError(ExitValidation, "unhealthy", fmt.Errorf("dev container is %s", status.Summary()))
+			}
+			return nil
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// devcontainerError maps a failed CLI run to an exit code: a failed outcome,
+// such as a failing lifecycle command, is a validation failure.
+func devcontainerError(result *docker.DevcontainerResult, err error) error {
+	if result == nil {
+		return err
+	}
+	return newExitError(ExitValidation, "devcontainer_failed", err)
+}
+
+// shortID returns the 12-character form docker prints container IDs in.
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/jpequegn/dockstart/internal/detector"
+	"github.com/jpequegn/dockstart/internal/doctor"
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/jpequegn/dockstart/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// skipImages disables registry lookups in `doctor`.
+var skipImages bool
+
+// doctorCmd checks the local environment for the planned stack.
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [path]",
+	Short: "Check that this machine can run the generated dev environment",
+	Long: `doctor checks Docker, Docker Compose, and Podman availability and versions,
+free disk space, port conflicts, image availability for this machine's
+architecture, and file-watcher limits, then prints a fix for each problem.
+
+When a supported project is detected, port and image checks cover the stack
+dockstart would generate for it. No .devcontainer files are written.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&ollama, "ollama", false, "Include the local Ollama sidecar in the planned stack")
+	doctorCmd.Flags().BoolVar(&wiremock, "wiremock", false, "Include the WireMock sidecar in the planned stack")
+	doctorCmd.Flags().BoolVar(&toxiproxy, "toxiproxy", false, "Include the Toxiproxy sidecar in the planned stack")
+	doctorCmd.Flags().BoolVar(&glitchtip, "glitchtip", false, "Include the GlitchTip sidecar in the planned stack")
+	doctorCmd.Flags().BoolVar(&gatus, "gatus", false, "Include the Gatus status page in the planned stack")
+	addSidecarFlags(doctorCmd)
+	doctorCmd.Flags().BoolVar(&skipImages, "skip-images", false, "Skip registry lookups for image platform availability")
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "📂 Analyzing %s...\n", absPath)
+
+	// Environment checks still run without a project; only the stack checks are skipped
+	detection, err := detectProject(absPath)
+	if err != nil && !errors.Is(err, detector.ErrNoProjectDetected) {
+		return err
+	}
+
+	plan, err := doctorPlan(detection, absPath, filepath.Base(absPath))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\n🩺 Checking environment...")
+	start := time.Now()
+	results := doctor.Run(plan)
+	logTiming("doctor checks", start)
+
+	warnings, failures := 0, 0
+	for _, r := range results {
+		report.Checks = append(report.Checks, checkResult{
+			Name:    r.Name,
+			Status:  string(r.Status),
+			Message: r.Message,
+			Fix:     r.Fix,
+		})
+
+		// Quiet runs hide the list below, so problems are logged as warnings
+		level := slog.LevelInfo
+		if quiet && r.Status != doctor.StatusOK {
+			level = slog.LevelWarn
+		}
+		logger.Log(context.Background(), level, "check", "name", r.Name, "status", r.Status, "message", r.Message)
+
+		switch r.Status {
+		case doctor.StatusWarn:
+			warnings++
+			fmt.Fprintf(out, "   ⚠️  %s: %s\n", r.Name, r.Message)
+		case doctor.StatusFail:
+			failures++
+			fmt.Fprintf(out, "   ❌ %s: %s\n", r.Name, r.Message)
+		default:
+			fmt.Fprintf(out, "   ✅ %s: %s\n", r.Name, r.Message)
+		}
+		if r.Fix != "" {
+			fmt.Fprintf(out, "      → %s\n", r.Fix)
+		}
+	}
+
+	fmt.Fprintf(out, "\n📊 %d checks, %d warnings, %d failures\n", len(results), warnings, failures)
+
+	if doctor.HasFailures(results) {
+		return newExitError(ExitValidation, "checks_failed", fmt.Errorf("environment is not ready: fix the failures above and rerun dockstart doctor"))
+	}
+
+	fmt.Fprintln(out, "\n✨ Environment looks good!")
+	return nil
+}
+
+// doctorPlan builds the ports and images to check for the stack dockstart would generate.
+func doctorPlan(detection *models.Detection, absPath, projectName string) (doctor.Plan, error) {
+	plan := doctor.Plan{
+		ProjectPath: absPath,
+		CheckImages: !skipImages,
+	}
+	if detection == nil {
+		return plan, nil
+	}
+
+	plan.Ports = generator.NewDevcontainerGenerator().ForwardPorts(detection)
+
+	images, err := stackImages(detection, projectName)
+	if err != nil {
+		return plan, err
+	}
+	plan.Images = images
+
+	return plan, nil
+}
+
+// stackImages returns the images the generated stack pulls: the Dockerfile
+// base image, then the images in docker-compose.yml.
+func stackImages(detection *models.Detection, projectName string) ([]string, error) {
+	var images []string
+
+	// The Dockerfile base image is pulled when the app container is built
+	if baseImage := generator.NewDockerfileGenerator().BaseImage(detection); baseImage != "" {
+		images = append(images, baseImage)
+	}
+
+	if detection.NeedsCompose() {
+		composeImages, err := generator.NewComposeGenerator().Images(detection, projectName)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, composeImages...)
+	}
+
+	return images, nil
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// driftHook runs the check from a git hook: it only warns, and is silent
+// unless the devcontainer is stale
+var driftHook bool
+
+// driftCmd compares the project with the detection its .devcontainer files
+// were generated from.
+var driftCmd = &cobra.Command{
+	Use:   "drift [path]",
+	Short: "Check whether the generated .devcontainer is stale",
+	Long: `drift re-runs detection and compares it with the detection recorded in
+.devcontainer/dockstart-report.json when the files were generated. When the
+project's language version, services, or libraries changed since (e.g., a
+Redis client was added to the dependencies), the devcontainer is stale and
+should be regenerated with dockstart --force.
+
+It exits with code 4 when the devcontainer is stale. --hook is for the git
+hooks installed by dockstart hooks install: it prints a warning instead,
+always exits 0, and says nothing when the project is up to date or was never
+generated.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDrift,
+}
+
+func init() {
+	driftCmd.Flags().BoolVar(&driftHook, "hook", false, "Only warn, for git hooks (always exits 0)")
+	rootCmd.AddCommand(driftCmd)
+}
+
+func runDrift(cmd *cobra.Command, args []string) error {
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return err
+	}
+
+	generated, err := generator.ReadReport(generator.OS, absPath)
+	if err != nil {
+		if driftHook {
+			return nil
+		}
+		return err
+	}
+	if generated == nil || generated.Detection == nil {
+		if driftHook {
+			return nil
+		}
+		return fmt.Errorf("no %s in %s: run dockstart to generate the .devcontainer files first", generator.ReportFile, absPath)
+	}
+
+	// Detect the way the files were generated, without the detection summary
+	if language == "" {
+		language = generated.Options.Language
+	}
+	lockfiles = lockfiles || generated.Options.Lockfiles
+	progress := out
+	out = io.Discard
+	current, err := detectProject(absPath)
+	out = progress
+	if err != nil {
+		if driftHook {
+			return nil
+		}
+		return err
+	}
+
+	drift := generator.DetectionDrift(generated.Detection, current)
+	for _, d := range drift {
+		report.Drift = append(report.Drift, d.String())
+	}
+
+	if !driftHook {
+		fmt.Fprintf(out, "📂 Checking %s against %s...\n", absPath, generator.ReportFile)
+		if changed := generated.ChangedFiles(absPath); len(changed) > 0 {
+			fmt.Fprintf(out, "   ℹ️  Edited or deleted since generation: %s\n", strings.Join(changed, ", "))
+		}
+	}
+	if len(drift) == 0 {
+		if !driftHook {
+			fmt.Fprintf(out, "   ✅ .devcontainer is up to date with the project (generated %s)\n",
+				generated.GeneratedAt.Local().Format("2006-01-02 15:04"))
+		}
+		return nil
+	}
+
+	fmt.Fprintln(out, "⚠️  dockstart: .devcontainer is stale, the project changed since it was generated:")
+	for _, d := range drift {
+		fmt.Fprintf(out, "   • %s\n", d)
+	}
+	fmt.Fprintf(out, "   Run `dockstart --force %s` to regenerate it\n", absPath)
+	if driftHook {
+		return nil
+	}
+	return newExitError(ExitValidation, "drift", fmt.Errorf(".devcontainer is stale: %d detected change(s) since it was generated", len(drift)))
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/docker"
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// editorCmd generates .envrc and .editorconfig for editors on the host.
+var editorCmd = &cobra.Command{
+	Use:   "editor [path]",
+	Short: "Generate .envrc (direnv) and .editorconfig for Neovim, Emacs, and other editors on the host",
+	Long: `editor generates files for editors without dev container support, which run
+on the host next to the dev environment's stack:
+
+  .envrc         direnv environment: COMPOSE_FILE and COMPOSE_PROJECT_NAME
+                 for the devcontainer's stack, and DATABASE_URL, REDIS_URL,
+                 and the other connection variables pointing at the services'
+                 ports published on localhost, with the generated passwords
+                 loaded from .devcontainer/.env
+  .editorconfig  line endings and the detected language's indentation
+
+Existing files are kept unless --force is given. Run "direnv allow" once to
+load .envrc; machine-specific settings go in .envrc.local.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runEditor,
+}
+
+func init() {
+	editorCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview the files without writing them")
+	editorCmd.Flags().BoolVar(&force, "force", false, "Overwrite existing files")
+	rootCmd.AddCommand(editorCmd)
+}
+
+func runEditor(cmd *cobra.Command, args []string) error {
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return err
+	}
+
+	projectName := filepath.Base(absPath)
+	fmt.Fprintf(out, "📂 Analyzing %s...\n", absPath)
+
+	detection, err := detectProject(absPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\n📝 Generating editor files...")
+	gen := generator.NewEditorGenerator()
+	composeProject := docker.ProjectName(absPath)
+	for _, file := range generator.EditorFiles {
+		if dryRun {
+			content, err := gen.GenerateContent(detection, projectName, composeProject, file)
+			if err != nil {
+				return err
+			}
+			previewFile(file, content)
+			continue
+		}
+
+		action := fileAction(absPath, file)
+		if action == "overwritten" && !force {
+			fmt.Fprintf(out, "   ⏭️  Kept %s (use --force to overwrite)\n", file)
+			recordFile(file, "kept")
+			continue
+		}
+		if err := gen.Generate(detection, absPath, projectName, composeProject, file); err != nil {
+			return err
+		}
+		fileWritten(file, action)
+	}
+
+	if detection.NeedsCompose() {
+		if _, err := os.Stat(newCompose(absPath).File); err != nil {
+			warn("No .devcontainer/docker-compose.yml yet: run dockstart to generate it, then dockstart up to start the services")
+		}
+	}
+
+	fmt.Fprintln(out, "\n✨ Done! Run \"direnv allow\" to load .envrc")
+	return nil
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jpequegn/dockstart/internal/golden"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// fixturesDir is the fixture corpus rendered by `generate --fixtures`
+	fixturesDir string
+
+	// updateGolden rewrites the corpus's golden files instead of comparing
+	updateGolden bool
+)
+
+// generateCmd renders the generators against a fixture corpus.
+var generateCmd = &cobra.Command{
+	Use:   "generate --fixtures <dir>",
+	Short: "Render the fixture projects and compare them with their golden files (for maintainers)",
+	Long: `generate --fixtures is a maintainer mode for reviewing template changes.
+It renders every generator against each project in <dir>/projects, in memory,
+and compares the output with the golden files in <dir>/golden/<project>.
+
+With --update the golden files are rewritten instead, so a template change
+shows up in git diff as the change to the files users would get. The corpus
+dockstart is tested against is internal/golden/testdata; go test
+./internal/golden -update does the same.
+
+It exits with code 4 when any project's output differs from its golden files.
+To generate files for a project, run dockstart <path>.`,
+	Args: cobra.NoArgs,
+	RunE: runGenerate,
+}
+
+func init() {
+	generateCmd.Flags().StringVar(&fixturesDir, "fixtures", "", "Fixture corpus with projects/ and golden/ directories")
+	generateCmd.Flags().BoolVar(&updateGolden, "update", false, "Rewrite the golden files with the current output")
+	_ = generateCmd.MarkFlagRequired("fixtures")
+	rootCmd.AddCommand(generateCmd)
+}
+
+func runGenerate(cmd *cobra.Command, args []string) error {
+	fixtures, err := golden.Fixtures(fixturesDir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "📂 Rendering %d fixture projects from %s...\n", len(fixtures), fixturesDir)
+	var differing int
+	for _, fixture := range fixtures {
+		files, err := fixture.Render()
+		if err != nil {
+			return err
+		}
+		result := fixtureResult{Name: fixture.Name, Files: len(files)}
+
+		if updateGolden {
+			if err := fixture.Update(files); err != nil {
+				return err
+			}
+			result.Updated = true
+			report.Fixtures = append(report.Fixtures, result)
+			fmt.Fprintf(out, "   📝 %s: wrote %d golden files\n", fixture.Name, len(files))
+			continue
+		}
+
+		diffs, err := fixture.Compare(files)
+		if err != nil {
+			return err
+		}
+		for _, diff := range diffs {
+			result.Diffs = append(result.Diffs, diff.String())
+		}
+		report.Fixtures = append(report.Fixtures, result)
+
+		if len(diffs) == 0 {
+			fmt.Fprintf(out, "   ✅ %s: %d files match\n", fixture.Name, len(files))
+			continue
+		}
+		differing++
+		fmt.Fprintf(out, "   ❌ %s: %d file(s) differ\n", fixture.Name, len(diffs))
+		for _, diff := range diffs {
+			fmt.Fprintf(out, "      %s\n", diff)
+		}
+	}
+
+	if differing > 0 {
+		return newExitError(ExitValidation, "golden_mismatch",
+			fmt.Errorf("%d of %d fixture projects differ from their golden files; rerun with --update if the changes are intended", differing, len(fixtures)))
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// graphFormat is the diagram format ("mermaid" or "dot")
+	graphFormat string
+
+	// graphFile is where the diagram is written (stdout when empty)
+	graphFile string
+)
+
+// graphCmd prints the service graph of the generated dev stack.
+var graphCmd = &cobra.Command{
+	Use:   "graph [path]",
+	Short: "Print the generated stack's services, dependencies, and volumes as a diagram",
+	Long: `graph renders the topology of the docker-compose.yml dockstart would generate:
+the app and worker, backing services, sidecars, and named volumes, connected by
+depends_on edges (solid) and volume mounts (dashed).
+
+The Mermaid format renders on GitHub and in most Markdown viewers; the same
+diagram is embedded in .devcontainer/README.devcontainer.md. The dot format
+is for Graphviz (e.g., dockstart graph --format dot | dot -Tsvg > stack.svg).
+The diagram is written to stdout unless --file is given. No .devcontainer
+files are written.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runGraph,
+}
+
+func init() {
+	graphCmd.Flags().StringVar(&graphFormat, "format", "mermaid", "Diagram format: mermaid or dot")
+	graphCmd.Flags().StringVarP(&graphFile, "file", "f", "", "Write the diagram to this file instead of stdout")
+	graphCmd.Flags().BoolVar(&ollama, "ollama", false, "Include the local Ollama sidecar in the planned stack")
+	graphCmd.Flags().BoolVar(&wiremock, "wiremock", false, "Include the WireMock sidecar in the planned stack")
+	graphCmd.Flags().BoolVar(&toxiproxy, "toxiproxy", false, "Include the Toxiproxy sidecar in the planned stack")
+	graphCmd.Flags().BoolVar(&glitchtip, "glitchtip", false, "Include the GlitchTip sidecar in the planned stack")
+	graphCmd.Flags().BoolVar(&gatus, "gatus", false, "Include the Gatus status page in the planned stack")
+	addSidecarFlags(graphCmd)
+	rootCmd.AddCommand(graphCmd)
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	if graphFormat != "mermaid" && graphFormat != "dot" {
+		return fmt.Errorf("invalid --format %q: must be mermaid or dot", graphFormat)
+	}
+
+	if graphFile == "" {
+		// stdout carries the diagram
+		if jsonOutput() {
+			return fmt.Errorf("--file is required with --output json")
+		}
+		out = os.Stderr
+	}
+
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return err
+	}
+
+	projectName := filepath.Base(absPath)
+	fmt.Fprintf(out, "📂 Analyzing %s...\n", absPath)
+
+	detection, err := detectProject(absPath)
+	if err != nil {
+		return err
+	}
+	if !detection.NeedsCompose() {
+		return fmt.Errorf("no docker-compose.yml is generated for this project: the app runs alone")
+	}
+
+	graph, err := generator.NewComposeGenerator().Graph(detection, projectName)
+	if err != nil {
+		return newExitError(ExitValidation, "invalid_config", fmt.Errorf("generated docker-compose.yml is invalid: %w", err))
+	}
+
+	diagram := graph.Mermaid()
+	if graphFormat == "dot" {
+		diagram = graph.Dot()
+	}
+
+	if graphFile == "" {
+		_, err := os.Stdout.WriteString(diagram)
+		return err
+	}
+
+	if err := os.WriteFile(graphFile, []byte(diagram), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", graphFile, err)
+	}
+	recordFile(graphFile, "created")
+	fmt.Fprintf(out, "\n   ✅ Created %s\n", graphFile)
+	return nil
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/jpequegn/dockstart/internal/hooks"
+	"github.com/spf13/cobra"
+)
+
+// hooksForce replaces existing hooks that dockstart didn't install
+var hooksForce bool
+
+// hooksCmd manages the git hooks that warn about a stale devcontainer.
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage git hooks that warn when the .devcontainer is stale",
+	Long: `hooks installs or removes git hooks that run dockstart drift --hook after
+each commit is prepared (pre-commit) and after each pull or merge (post-merge).
+The check compares the project with the detection recorded in
+.devcontainer/dockstart-report.json and warns when dependency changes left the
+devcontainer stale. It never blocks a commit, and is skipped on machines
+without dockstart.`,
+}
+
+// hooksInstallCmd writes the dockstart hooks.
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install [path]",
+	Short: "Install the pre-commit and post-merge drift hooks",
+	Long: `install writes pre-commit and post-merge hooks to the git hooks directory of
+the repository containing path (honoring core.hooksPath). Hooks installed by
+dockstart are updated in place; other existing hooks are left alone unless
+--force is given.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHooksInstall,
+}
+
+// hooksUninstallCmd removes the dockstart hooks.
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall [path]",
+	Short: "Remove the drift hooks installed by dockstart",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runHooksUninstall,
+}
+
+func init() {
+	hooksInstallCmd.Flags().BoolVar(&hooksForce, "force", false, "Replace existing hooks that dockstart didn't install")
+	hooksCmd.AddCommand(hooksInstallCmd, hooksUninstallCmd)
+	rootCmd.AddCommand(hooksCmd)
+}
+
+func runHooksInstall(cmd *cobra.Command, args []string) error {
+	dir, err := hooksDir(args)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "🪝 Installing git hooks in %s...\n", dir)
+	results, err := hooks.Install(dir, hooksForce)
+	printHookResults(results)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "\n✅ Commits and merges now warn when .devcontainer is stale")
+	return nil
+}
+
+func runHooksUninstall(cmd *cobra.Command, args []string) error {
+	dir, err := hooksDir(args)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "🪝 Removing git hooks from %s...\n", dir)
+	results, err := hooks.Uninstall(dir)
+	printHookResults(results)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		fmt.Fprintln(out, "   No dockstart hooks installed")
+	}
+	return nil
+}
+
+// hooksDir returns the git hooks directory for the project path argument.
+func hooksDir(args []string) (string, error) {
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return "", err
+	}
+	return hooks.Dir(absPath)
+}
+
+// printHookResults prints and records what was done with each hook.
+func printHookResults(results []hooks.Result) {
+	for _, r := range results {
+		report.Hooks = append(report.Hooks, fileResult{Path: r.Path, Action: string(r.Action)})
+		switch r.Action {
+		case hooks.Installed:
+			fmt.Fprintf(out, "   ✅ %s\n", r.Name)
+		case hooks.Removed:
+			fmt.Fprintf(out, "   🗑️  %s\n", r.Name)
+		case hooks.Skipped:
+			warn("%s: an existing hook that dockstart didn't install was left alone (use --force to replace it, or call `dockstart drift --hook` from it)", r.Name)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/docker"
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// jetbrainsCmd generates JetBrains run configurations for the compose stack.
+var jetbrainsCmd = &cobra.Command{
+	Use:   "jetbrains [path]",
+	Short: "Generate JetBrains run configurations that start the dev environment",
+	Long: `jetbrains generates Docker Compose run configurations in .run/, which
+IntelliJ IDEA, GoLand, PyCharm, WebStorm, and RustRover load as shared project
+run configurations:
+
+  Dev stack  start every service of .devcontainer/docker-compose.yml
+  Tests      run the test suite in the one-shot test service (when a test
+             command was detected)
+
+Both run under the compose project the dev container uses, so the IDE's
+Services tool window and "Reopen in Container" manage the same containers.
+
+JetBrains Gateway needs no extra files: the generated devcontainer.json names
+the IDE to open the dev container with (customizations.jetbrains.backend).`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runJetBrains,
+}
+
+func init() {
+	jetbrainsCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview the run configurations without writing them")
+	jetbrainsCmd.Flags().BoolVar(&force, "force", false, "Overwrite existing run configurations")
+	rootCmd.AddCommand(jetbrainsCmd)
+}
+
+func runJetBrains(cmd *cobra.Command, args []string) error {
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return err
+	}
+
+	projectName := filepath.Base(absPath)
+	fmt.Fprintf(out, "📂 Analyzing %s...\n", absPath)
+
+	detection, err := detectProject(absPath)
+	if err != nil {
+		return err
+	}
+	if !detection.NeedsCompose() {
+		return fmt.Errorf("%s runs as a single devcontainer without docker-compose.yml, so there are no compose run configurations to generate; Gateway opens it with %s", projectName, generator.JetBrainsBackend(detection.Language))
+	}
+
+	gen := generator.NewJetBrainsGenerator()
+	composeProject := docker.ProjectName(absPath)
+	configs := gen.RunConfigs(detection, composeProject)
+	if !dryRun && !force {
+		var existing []string
+		for _, config := range configs {
+			if _, err := os.Stat(filepath.Join(absPath, filepath.FromSlash(config.File))); err == nil {
+				existing = append(existing, config.File)
+			}
+		}
+		if len(existing) > 0 {
+			return &generator.ConflictError{Files: existing}
+		}
+	}
+
+	fmt.Fprintf(out, "\n📝 Generating %s/...\n", generator.JetBrainsRunDir)
+	if dryRun {
+		for _, config := range configs {
+			content, err := gen.GenerateContent(config)
+			if err != nil {
+				return err
+			}
+			previewFile(config.File, content)
+		}
+	} else {
+		actions := make(map[string]string)
+		for _, config := range configs {
+			actions[config.File] = fileAction(absPath, config.File)
+		}
+		if err := gen.Generate(detection, absPath, composeProject); err != nil {
+			return err
+		}
+		for _, config := range configs {
+			fileWritten(config.File, actions[config.File])
+		}
+	}
+
+	if _, err := os.Stat(newCompose(absPath).File); err != nil {
+		warn("No .devcontainer/docker-compose.yml yet: run dockstart to generate it before using the run configurations")
+	}
+
+	fmt.Fprintln(out, "\n✨ Done!")
+	return nil
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/jpequegn/dockstart/internal/lint"
+	"github.com/spf13/cobra"
+)
+
+// lintFailOn is the lowest severity that makes `lint` exit non-zero
+var lintFailOn string
+
+// lintCmd lints the Dockerfiles in .devcontainer.
+var lintCmd = &cobra.Command{
+	Use:   "lint [path]",
+	Short: "Lint the Dockerfiles in .devcontainer with hadolint's rules",
+	Long: `lint checks every Dockerfile in .devcontainer (Dockerfile, Dockerfile.backup,
+Dockerfile.processor, ...) against hadolint's rules, so files regenerated from
+custom templates, or edited by hand, can be checked before they are built.
+
+hadolint runs when it is installed; otherwise dockstart checks a built-in subset
+of its rules. Every finding is reported, including the version pinning rules
+that generation ignores.
+
+lint exits with code 4 when a Dockerfile has a finding at or above --fail-on.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLint,
+}
+
+func init() {
+	lintCmd.Flags().StringVar(&lintFailOn, "fail-on", "warning", "Exit non-zero on findings at or above this severity ("+strings.Join(lint.Severities, ", ")+")")
+	rootCmd.AddCommand(lintCmd)
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	if lint.SeverityRank(lintFailOn) < 0 {
+		return fmt.Errorf("invalid --fail-on %q: must be one of %s", lintFailOn, strings.Join(lint.Severities, ", "))
+	}
+	lintFailOn = strings.ToLower(lintFailOn)
+
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return err
+	}
+
+	devcontainerDir := filepath.Join(absPath, ".devcontainer")
+	entries, err := os.ReadDir(devcontainerDir)
+	if err != nil {
+		return fmt.Errorf("no .devcontainer directory in %s: run dockstart first", absPath)
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && lint.IsDockerfile(entry.Name()) {
+			files = append(files, filepath.Join(".devcontainer", entry.Name()))
+		}
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no Dockerfiles in %s", devcontainerDir)
+	}
+
+	linter := lint.New()
+	fmt.Fprintf(out, "🔍 Linting %d Dockerfiles with %s...\n", len(files), linter.Engine())
+	failing := 0
+	for _, rel := range files {
+		content, err := os.ReadFile(filepath.Join(absPath, rel))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+		findings, err := linter.Lint(filepath.ToSlash(rel), content)
+		if err != nil {
+			return err
+		}
+		report.Lint = append(report.Lint, findings...)
+
+		icon := "✅"
+		if len(lint.AtOrAbove(findings, lintFailOn)) > 0 {
+			icon = "❌"
+			failing++
+		} else if len(findings) > 0 {
+			icon = "⚠️ "
+		}
+		fmt.Fprintf(out, "   %s %s\n", icon, filepath.ToSlash(rel))
+		for _, f := range findings {
+			fmt.Fprintf(out, "      %d %s %s: %s\n", f.Line, f.Rule, f.Severity, f.Message)
+		}
+	}
+
+	if failing > 0 {
+		return newExitError(ExitValidation, "lint",
+			fmt.Errorf("%d of %d Dockerfiles have %s or higher findings", failing, len(files), lintFailOn))
+	}
+
+	fmt.Fprintln(out, "\n✨ Lint complete!")
+	return nil
+}
+
+// lintPlan lints the Dockerfiles a generation plan writes, before anything is
+// written: error findings fail generation, warnings are printed. The version
+// pinning rules are ignored, since development images skip them on purpose.
+func lintPlan(plan *generator.Plan, absPath string) error {
+	mem, err := plan.Render(absPath)
+	if err != nil {
+		return err
+	}
+
+	linter := lint.New()
+	linter.Ignore = lint.UnpinnedRules
+	var errs []string
+	for _, name := range mem.Files() {
+		if !lint.IsDockerfile(filepath.Base(name)) {
+			continue
+		}
+		rel, err := filepath.Rel(absPath, name)
+		if err != nil {
+			return err
+		}
+		content, err := mem.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		findings, err := linter.Lint(filepath.ToSlash(rel), content)
+		if err != nil {
+			return err
+		}
+		report.Lint = append(report.Lint, findings...)
+
+		for _, f := range findings {
+			switch f.Severity {
+			case "error":
+				errs = append(errs, f.String())
+			case "warning":
+				warn("%s", f)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return newExitError(ExitValidation, "lint",
+			fmt.Errorf("generated Dockerfiles fail %s (--no-lint skips the check):\n  %s", linter.Engine(), strings.Join(errs, "\n  ")))
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestGenerationPlan_RenderWritesNothing tests that rendering the generation
+// plan, as the Dockerfile lint does before every run, leaves the project
+// untouched, including the generation report.
+func TestGenerationPlan_RenderWritesNothing(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(`{"name": "shop"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Services: []string{"postgres", "redis"},
+		Capabilities: models.Capabilities{
+			QueueLibraries: []string{"bullmq"},
+		},
+		WorkerCommand: "npm run worker",
+	}
+
+	plan, err := generationPlan(detection, tmpDir, "shop")
+	if err != nil {
+		t.Fatalf("generationPlan() error = %v", err)
+	}
+	mem, err := plan.Render(tmpDir)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if _, err := mem.Stat(filepath.Join(tmpDir, filepath.FromSlash(generator.ReportFile))); err != nil {
+		t.Errorf("the report should be rendered in memory: %v", err)
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Errorf("Render() wrote to disk: project has %v, want only package.json", names)
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/jpequegn/dockstart/internal/docker"
+)
+
+var (
+	// verbosity is the number of -v flags: 1 logs what was detected, which
+	// generators ran, and which files were written or skipped; 2 also logs
+	// cache use, docker commands, and timings
+	verbosity int
+
+	// quiet hides progress output, leaving warnings and errors
+	quiet bool
+
+	// logger writes diagnostics to stderr at the level -v selects. It
+	// discards everything until setupLogging runs.
+	logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+)
+
+// setupLogging configures the logger for the -v count. Logs go to stderr so
+// they never mix with JSON output or previews on stdout.
+func setupLogging() {
+	level := slog.LevelWarn
+	switch {
+	case verbosity >= 2:
+		level = slog.LevelDebug
+	case verbosity == 1:
+		level = slog.LevelInfo
+	}
+
+	logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			// Timestamps only help when timing things at debug level
+			if attr.Key == slog.TimeKey && len(groups) == 0 && level > slog.LevelDebug {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
+}
+
+// newCompose returns the docker compose wrapper for a project, logging the
+// commands it runs at debug level.
+func newCompose(absPath string) *docker.Compose {
+	compose := docker.NewCompose(absPath)
+	compose.Logger = logger
+	return compose
+}
+
+// logTiming logs how long a phase took at debug level. Use it as
+// defer logTiming("detection", time.Now()).
+func logTiming(phase string, start time.Time) {
+	logger.Debug("timing", "phase", phase, "duration", time.Since(start).Round(time.Millisecond))
+}
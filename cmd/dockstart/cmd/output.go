@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jpequegn/dockstart/internal/detector"
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/jpequegn/dockstart/internal/lint"
+	"github.com/jpequegn/dockstart/internal/models"
+	"github.com/jpequegn/dockstart/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes. Scripts and CI jobs rely on these, so never renumber them.
+const (
+	// ExitOK means the command succeeded
+	ExitOK = 0
+
+	// ExitError is any failure without a more specific code
+	ExitError = 1
+
+	// ExitNoProject means no supported project was detected
+	ExitNoProject = 2
+
+	// ExitConflict means generation would overwrite existing files (use --force)
+	ExitConflict = 3
+
+	// ExitValidation means a check failed: doctor failures, unhealthy services, invalid generated config,
+	// vulnerabilities above the scan threshold, a stale .devcontainer, or policy violations
+	ExitValidation = 4
+)
+
+// exitError is an error that maps to a specific exit code.
+type exitError struct {
+	code int
+	kind string
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// newExitError wraps err with an exit code and a machine-readable kind.
+func newExitError(code int, kind string, err error) error {
+	return &exitError{code: code, kind: kind, err: err}
+}
+
+// typedErrors maps the detector's and generators' typed errors to exit codes
+// and kinds, so they don't need wrapping in an exitError.
+var typedErrors = []struct {
+	target error
+	code   int
+	kind   string
+}{
+	{detector.ErrNoProjectDetected, ExitNoProject, "no_project"},
+	{detector.ErrUnsupportedLanguage, ExitError, "unsupported_language"},
+	{generator.ErrConflictingFiles, ExitConflict, "conflict"},
+	{generator.ErrTemplateRender, ExitError, "template_render"},
+}
+
+// ExitCode returns the process exit code for an error returned by Execute.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	for _, typed := range typedErrors {
+		if errors.Is(err, typed.target) {
+			return typed.code
+		}
+	}
+	return ExitError
+}
+
+// errorKind returns the machine-readable kind of an error.
+func errorKind(err error) string {
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.kind
+	}
+	for _, typed := range typedErrors {
+		if errors.Is(err, typed.target) {
+			return typed.kind
+		}
+	}
+	return "error"
+}
+
+// errorHint returns what the user can do about an error, if it says.
+func errorHint(err error) string {
+	var hinted interface{ Hint() string }
+	if errors.As(err, &hinted) {
+		return hinted.Hint()
+	}
+	return ""
+}
+
+// printHint prints an error's remediation hint to stderr, below the error
+// cobra prints. JSON output carries the hint in the result instead.
+func printHint(err error) {
+	if hint := errorHint(err); hint != "" {
+		fmt.Fprintf(os.Stderr, "💡 %s\n", hint)
+	}
+}
+
+var (
+	// outputFormat is the --output flag value ("text" or "json")
+	outputFormat string
+
+	// jsonFlag is the --json shorthand for --output json
+	jsonFlag bool
+
+	// out receives human-readable progress output. It is discarded in JSON mode,
+	// so stdout only carries the JSON result.
+	out io.Writer = os.Stdout
+
+	// report collects the machine-readable result of the current command
+	report = &result{}
+)
+
+// result is the JSON document printed by every command with --output json.
+type result struct {
+	Command        string               `json:"command"`
+	Project        string               `json:"project,omitempty"`
+	Success        bool                 `json:"success"`
+	ExitCode       int                  `json:"exit_code"`
+	Error          *resultError         `json:"error,omitempty"`
+	Detection      *detectionResult     `json:"detection,omitempty"`
+	Files          []fileResult         `json:"files,omitempty"`
+	Services       []string             `json:"services,omitempty"`
+	Containers     []containerResult    `json:"containers,omitempty"`
+	URLs           []urlResult          `json:"urls,omitempty"`
+	Checks         []checkResult        `json:"checks,omitempty"`
+	Scans          []scanResult         `json:"scans,omitempty"`
+	Lint           []lint.Finding       `json:"lint,omitempty"`
+	Policy         []policy.Violation   `json:"policy_violations,omitempty"`
+	VolumesRemoved []string             `json:"volumes_removed,omitempty"`
+	Upgrade        *upgradeResult       `json:"upgrade,omitempty"`
+	Build          *generator.BuildInfo `json:"build,omitempty"`
+	Drift          []string             `json:"drift,omitempty"`
+	Hooks          []fileResult         `json:"hooks,omitempty"`
+	Fixtures       []fixtureResult      `json:"fixtures,omitempty"`
+	Warnings       []string             `json:"warnings,omitempty"`
+}
+
+// resultError describes why a command failed.
+type resultError struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+
+	// Hint says how to fix the error, when dockstart knows
+	Hint string `json:"hint,omitempty"`
+}
+
+// detectionResult summarizes what was detected in the project.
+type detectionResult struct {
+	Language   string   `json:"language"`
+	Version    string   `json:"version"`
+	Confidence float64  `json:"confidence"`
+	Services   []string `json:"services,omitempty"`
+
+	// ServiceVersions are the inferred or configured backing service versions
+	ServiceVersions map[string]string `json:"service_versions,omitempty"`
+
+	// Lockfile and PinnedVersions are set when lockfile parsing is enabled
+	Lockfile       string            `json:"lockfile,omitempty"`
+	PinnedVersions map[string]string `json:"pinned_versions,omitempty"`
+
+	// Capabilities are the detected libraries per capability
+	Capabilities models.Capabilities `json:"capabilities"`
+
+	// Reason explains why this language was chosen over the alternatives
+	Reason       string              `json:"reason,omitempty"`
+	Alternatives []alternativeResult `json:"alternatives,omitempty"`
+}
+
+// alternativeResult is a detected language that was not chosen.
+type alternativeResult struct {
+	Language   string  `json:"language"`
+	Version    string  `json:"version"`
+	Confidence float64 `json:"confidence"`
+}
+
+// fileResult is a file a command created, overwrote, updated, previewed, removed, or kept.
+type fileResult struct {
+	Path   string `json:"path"`
+	Action string `json:"action"`
+}
+
+// containerResult is the live state of a compose service container.
+type containerResult struct {
+	Service  string   `json:"service"`
+	State    string   `json:"state"`
+	Health   string   `json:"health,omitempty"`
+	Ready    bool     `json:"ready"`
+	Restarts int      `json:"restarts"`
+	Ports    []string `json:"ports,omitempty"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// urlResult is a browser-facing URL of the stack.
+type urlResult struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// checkResult is the outcome of a doctor check.
+type checkResult struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Fix     string `json:"fix,omitempty"`
+}
+
+// scanResult is the vulnerability scan of one image.
+type scanResult struct {
+	Image           string         `json:"image"`
+	Services        []string       `json:"services"`
+	Vulnerabilities map[string]int `json:"vulnerabilities"`
+}
+
+// upgradeResult is the outcome of `dockstart upgrade`.
+type upgradeResult struct {
+	Current  string `json:"current"`
+	Latest   string `json:"latest"`
+	Upgraded bool   `json:"upgraded"`
+}
+
+// fixtureResult is the comparison of one fixture project with its golden files.
+type fixtureResult struct {
+	Name    string   `json:"name"`
+	Files   int      `json:"files"`
+	Updated bool     `json:"updated,omitempty"`
+	Diffs   []string `json:"diffs,omitempty"`
+}
+
+// jsonOutput reports whether machine-readable output was requested.
+func jsonOutput() bool {
+	return outputFormat == "json"
+}
+
+// setupOutput validates --output and prepares the writers for the command about to run.
+func setupOutput(cmd *cobra.Command) error {
+	report.Command = cmd.Name()
+	if jsonFlag {
+		outputFormat = "json"
+	}
+
+	setupLogging()
+	switch outputFormat {
+	case "text":
+		out = os.Stdout
+		if quiet {
+			out = io.Discard
+		}
+	case "json":
+		out = io.Discard
+		// The error is part of the JSON result
+		cmd.Root().SilenceErrors = true
+	default:
+		return fmt.Errorf("invalid --output %q: must be text or json", outputFormat)
+	}
+
+	// Flags and arguments are valid; later errors are runtime failures, not usage mistakes
+	cmd.Root().SilenceUsage = true
+	return nil
+}
+
+// writeReport prints the JSON result for the finished command.
+func writeReport(err error) {
+	report.Success = err == nil
+	report.ExitCode = ExitCode(err)
+	if err != nil {
+		report.Error = &resultError{Kind: errorKind(err), Message: err.Error(), Hint: errorHint(err)}
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if encErr := enc.Encode(report); encErr != nil {
+		fmt.Fprintf(os.Stderr, "failed to write JSON output: %v\n", encErr)
+	}
+}
+
+// recordDetection adds the detection summary to the result.
+func recordDetection(detection *models.Detection) {
+	report.Detection = &detectionResult{
+		Language:   detection.Language,
+		Version:    detection.Version,
+		Confidence: detection.Confidence,
+		Services:   detection.Services,
+
+		ServiceVersions: detection.ServiceVersions,
+
+		Lockfile:       detection.Lockfile,
+		PinnedVersions: detection.PinnedVersions,
+
+		Capabilities: detection.Capabilities,
+	}
+}
+
+// recordResolution adds why the primary language was chosen, and the alternatives.
+func recordResolution(resolution *detector.Resolution) {
+	report.Detection.Reason = resolution.Reason
+	for _, alt := range resolution.Alternatives {
+		report.Detection.Alternatives = append(report.Detection.Alternatives, alternativeResult{
+			Language:   alt.Language,
+			Version:    alt.Version,
+			Confidence: alt.Confidence,
+		})
+	}
+}
+
+// recordFile adds a file to the result.
+func recordFile(path, action string) {
+	report.Files = append(report.Files, fileResult{Path: path, Action: action})
+}
+
+// warn prints a warning and adds it to the result.
+func warn(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	fmt.Fprintf(out, "   ⚠️  %s\n", msg)
+	if quiet {
+		// Progress output is hidden, but warnings still matter
+		logger.Warn(msg)
+	}
+	report.Warnings = append(report.Warnings, msg)
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/jpequegn/dockstart/internal/models"
+	"github.com/jpequegn/dockstart/internal/policy"
+)
+
+// Environment variables that set the organization policy, so platform teams
+// can apply it to every project on a machine or CI runner.
+const (
+	policyEnv    = "DOCKSTART_POLICY"
+	policyKeyEnv = "DOCKSTART_POLICY_KEY"
+)
+
+// loadPolicy loads the policy named by --policy or DOCKSTART_POLICY, verified
+// against --policy-key or DOCKSTART_POLICY_KEY. Returns nil without a policy.
+func loadPolicy() (*policy.Policy, error) {
+	source := policySource
+	if source == "" {
+		source = os.Getenv(policyEnv)
+	}
+	if source == "" {
+		return nil, nil
+	}
+	key := policyKey
+	if key == "" {
+		key = os.Getenv(policyKeyEnv)
+	}
+
+	p, err := policy.Load(source, key)
+	if errors.Is(err, policy.ErrSignature) {
+		return nil, newExitError(ExitValidation, "policy_signature", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	verified := "signature verified"
+	if !p.Verified {
+		verified = "unsigned"
+		warn("Policy %s was not verified: set --policy-key or %s to check its signature", p.Source, policyKeyEnv)
+	}
+	fmt.Fprintf(out, "\n🏛️  Policy: %s (%s, %s)\n", p.Name, p.Source, verified)
+	return p, nil
+}
+
+// checkPolicy checks a generation plan against the policy: violations fail
+// generation when the policy is enforced, and are warnings otherwise.
+func checkPolicy(p *policy.Policy, detection *models.Detection, projectName string) error {
+	var facts policy.Facts
+	if detection.ExistingDockerfile == nil {
+		facts.Images = append(facts.Images, generator.NewDockerfileGenerator().BaseImage(detection))
+	}
+	if detection.NeedsCompose() {
+		composeGen := generator.NewComposeGenerator()
+		images, err := composeGen.Images(detection, projectName)
+		if err != nil {
+			return fmt.Errorf("compose generation failed: %w", err)
+		}
+		for _, image := range images {
+			if !slices.Contains(facts.Images, image) {
+				facts.Images = append(facts.Images, image)
+			}
+		}
+		budget, err := composeGen.MemoryBudget(detection, projectName)
+		if err != nil {
+			return fmt.Errorf("compose generation failed: %w", err)
+		}
+		facts.Unlimited = budget.Unlimited
+	}
+
+	violations := p.Check(detection, facts)
+	report.Policy = violations
+	if len(violations) == 0 {
+		return nil
+	}
+	if !p.Enforce {
+		for _, v := range violations {
+			warn("Policy %s: %s", p.Name, v)
+		}
+		return nil
+	}
+
+	lines := make([]string, len(violations))
+	for i, v := range violations {
+		lines[i] = v.String()
+	}
+	return newExitError(ExitValidation, "policy_violation",
+		fmt.Errorf("the plan violates policy %s:\n  %s", p.Name, strings.Join(lines, "\n  ")))
+}
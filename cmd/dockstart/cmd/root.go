@@ -2,12 +2,23 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/jpequegn/dockstart/internal/config"
 	"github.com/jpequegn/dockstart/internal/detector"
+	"github.com/jpequegn/dockstart/internal/docker"
 	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/jpequegn/dockstart/internal/models"
+	"github.com/jpequegn/dockstart/internal/project"
 	"github.com/spf13/cobra"
 )
 
@@ -15,9 +26,41 @@ var (
 	// Version is set at build time
 	Version = "dev"
 
+	// Commit and BuildDate are set at build time with Version; see buildInfo
+	Commit    = ""
+	BuildDate = ""
+
 	// Flags
-	dryRun bool
-	force  bool
+	dryRun          bool
+	force           bool
+	forceDockerfile bool
+	ollama          bool
+	wiremock        bool
+	toxiproxy       bool
+	glitchtip       bool
+	gatus           bool
+	minimal         bool
+	withSidecars    []string
+	withoutSidecars []string
+	maxTotalMemory  string
+	noCache         bool
+	language        string
+	lockfiles       bool
+	nonRoot         bool
+	hardened        bool
+	windows         bool
+	remoteDocker    bool
+	prebuild        bool
+	target          string
+	composeVersion  string
+	environments    []string
+	forward         []string
+	noLint          bool
+	policySource    string
+	policyKey       string
+	persist         bool
+	workerFlags     config.Worker
+	processorFlags  config.FileProcessor
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -32,23 +75,163 @@ development environment files including:
   - .devcontainer/Dockerfile
 
 It detects the project's language (Node.js, Go, Python, Rust) and
-any services (PostgreSQL, Redis) to create an optimized dev environment.`,
+any services (PostgreSQL, Redis) to create an optimized dev environment.
+
+Exit codes:
+  0  success
+  1  unexpected error
+  2  no supported project detected
+  3  generation conflict (files exist; use --force)
+  4  validation failure (failed checks, unhealthy services, invalid config,
+     vulnerabilities at or above scan --fail-on)`,
 	Args: cobra.MaximumNArgs(1),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := setupOutput(cmd); err != nil {
+			return err
+		}
+		startUpdateCheck(cmd)
+		return nil
+	},
 	RunE: run,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Use ExitCode to map the returned error to the process exit code.
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	if jsonOutput() {
+		writeReport(err)
+	} else if err != nil {
+		printHint(err)
+	}
+	printUpdateNotice()
+	return err
 }
 
 func init() {
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text or json")
+	rootCmd.PersistentFlags().BoolVar(&jsonFlag, "json", false, "Shorthand for --output json")
+	rootCmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "Log to stderr what was detected, which generators ran, and which files were written (-vv: also cache use, docker commands, and timings)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Hide progress output; warnings and errors are still printed")
+	rootCmd.PersistentFlags().StringVar(&language, "language", "", "Force the primary language (node, go, python, rust) in multi-language repos")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Re-parse every manifest instead of using .dockstart/cache.json")
+	rootCmd.PersistentFlags().BoolVar(&lockfiles, "lockfiles", false, "Read lockfiles to detect transitive libraries and pin versions (slower)")
 	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview output without writing files")
 	rootCmd.Flags().BoolVar(&force, "force", false, "Overwrite existing files")
+	rootCmd.Flags().BoolVar(&forceDockerfile, "force-dockerfile", false, "Generate .devcontainer/Dockerfile even if the project has a reusable Dockerfile")
+	rootCmd.Flags().BoolVar(&ollama, "ollama", false, "Add a local Ollama sidecar for LLM-backed apps")
+	rootCmd.Flags().BoolVar(&wiremock, "wiremock", false, "Add a WireMock sidecar that stubs third-party APIs")
+	rootCmd.Flags().BoolVar(&toxiproxy, "toxiproxy", false, "Route database connections through a Toxiproxy sidecar to inject latency and failures")
+	rootCmd.Flags().BoolVar(&glitchtip, "glitchtip", false, "Add a self-hosted GlitchTip that receives the Sentry SDK's errors")
+	rootCmd.Flags().BoolVar(&gatus, "gatus", false, "Add a Gatus status page that checks the app, databases, and dashboards")
+	addSidecarFlags(rootCmd)
+	rootCmd.Flags().StringVar(&maxTotalMemory, "max-total-memory", "", "Warn when the services' memory limits add up to more than this (e.g., 8g; default: the memory available to Docker)")
+	rootCmd.Flags().BoolVar(&nonRoot, "non-root", false, "Run containers as a non-root user matching the host UID/GID (USER_UID/USER_GID)")
+	rootCmd.Flags().BoolVar(&hardened, "hardened", false, "Harden services: read-only root filesystem, no-new-privileges, cap_drop: ALL")
+	rootCmd.Flags().BoolVar(&windows, "windows", false, "Adapt files for Windows/WSL hosts: LF scripts, named volumes for dependencies (default on Windows)")
+	rootCmd.Flags().BoolVar(&remoteDocker, "remote-docker", false, "Sync the workspace into a volume instead of bind-mounting it, for a Docker engine on another machine (default when DOCKER_HOST is remote)")
+	rootCmd.Flags().BoolVar(&prebuild, "prebuild", false, "Install dependencies in their own Dockerfile layer and cache builds from a prebuilt image (prebuild.image)")
+	rootCmd.Flags().StringVar(&target, "target", "", "Environment to generate for: local (default) or codespaces (features, prebuild-friendly onCreateCommand, smaller limits)")
+	rootCmd.Flags().BoolVar(&noLint, "no-lint", false, "Skip linting the generated Dockerfiles with hadolint's rules")
+	rootCmd.Flags().StringVar(&policySource, "policy", "", "Organization policy file or URL to check the plan against (default: $"+policyEnv+")")
+	rootCmd.Flags().StringVar(&policyKey, "policy-key", "", "Base64 Ed25519 public key the policy's .sig signature must match (default: $"+policyKeyEnv+")")
+	rootCmd.Flags().StringVar(&composeVersion, "compose-version", "", "Compose implementation to generate docker-compose.yml for: v2 (default), v1 (docker-compose 1.x), or swarm (docker stack deploy)")
+	rootCmd.Flags().StringSliceVar(&environments, "env", nil, "Generate docker-compose.<env>.yml overrides for these environments: "+strings.Join(models.ComposeEnvironments, ", "))
+	rootCmd.Flags().StringSliceVar(&forward, "forward", nil, "Forward host credentials into the dev container: "+strings.Join(models.CredentialForwards, ", ")+" (SSH agent, ~/.gitconfig, gpg-agent)")
+	rootCmd.Flags().BoolVar(&persist, "persist", false, "Keep shell history and package/build caches in named volumes across rebuilds")
+	addWorkerFlags(rootCmd)
+	addProcessorFlags(rootCmd)
+}
+
+// addSidecarFlags registers --minimal, --with, and --without, which choose the
+// optional sidecars of the stack.
+func addSidecarFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&minimal, "minimal", false, "Generate only the app and its databases: no logging, metrics, tracing, backup, or file processor sidecars")
+	cmd.Flags().StringSliceVar(&withSidecars, "with", nil, "Add optional sidecars even if not detected: "+strings.Join(models.Sidecars, ", "))
+	cmd.Flags().StringSliceVar(&withoutSidecars, "without", nil, "Leave out optional sidecars even if detected: "+strings.Join(models.Sidecars, ", "))
+}
+
+// addWorkerFlags registers the --worker-* flags, which override the worker
+// settings in .dockstart.yml.
+func addWorkerFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&workerFlags.Concurrency, "worker-concurrency", 0, "Jobs each worker processes at once (default 2)")
+	cmd.Flags().IntVar(&workerFlags.Replicas, "worker-replicas", 0, "Number of worker containers to run")
+	cmd.Flags().StringVar(&workerFlags.Memory, "worker-memory", "", "Memory limit per worker (e.g., 512m)")
+	cmd.Flags().StringVar(&workerFlags.CPUs, "worker-cpus", "", "CPU limit per worker (e.g., 0.5)")
+	cmd.Flags().StringVar(&workerFlags.StopGracePeriod, "worker-stop-grace-period", "", "Time a worker gets to finish jobs on shutdown (e.g., 30s)")
+}
+
+// addProcessorFlags registers the --processor-* flags, which override the
+// file_processor settings in .dockstart.yml.
+func addProcessorFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&processorFlags.Types, "processor-types", nil, "File types to process: images, documents, video (default images)")
+	cmd.Flags().StringSliceVar(&processorFlags.ThumbnailSizes, "processor-thumbnail-sizes", nil, "Image thumbnail sizes (e.g., 200x200,800x600)")
+	cmd.Flags().StringSliceVar(&processorFlags.AllowedMIMETypes, "processor-mime-types", nil, "MIME types accepted for processing (e.g., image/*,application/pdf)")
+	cmd.Flags().StringVar(&processorFlags.MaxFileSize, "processor-max-file-size", "", "Largest file processed (e.g., 50m)")
+	cmd.Flags().StringVar(&processorFlags.Watch, "processor-watch", "", "How new files are found: poll or inotify (default poll)")
+	cmd.Flags().BoolVar(&processorFlags.Scan, "processor-scan", false, "Scan uploads with ClamAV and quarantine infected files")
+	cmd.Flags().BoolVar(&processorFlags.SanitizeImages, "processor-sanitize-images", false, "Strip EXIF/GPS metadata and convert images to safe formats")
+	cmd.Flags().StringVar(&processorFlags.Notify, "processor-notify", "", "How the app is told a file was processed: file, webhook, or queue (default file)")
+	cmd.Flags().StringVar(&processorFlags.NotifyURL, "processor-notify-url", "", "Webhook URL for --processor-notify webhook")
+	cmd.Flags().StringVar(&processorFlags.NotifyQueue, "processor-notify-queue", "", "Redis list for --processor-notify queue")
+	cmd.Flags().StringVar(&processorFlags.Storage, "processor-storage", "", "Where uploads are exchanged: volume or s3 (default s3 when an S3 SDK is detected)")
+}
+
+// workerSettingList formats the worker settings that differ from the defaults.
+func workerSettingList(detection *models.Detection) string {
+	parts := []string{fmt.Sprintf("concurrency %d", detection.GetWorkerConcurrency())}
+	if detection.Worker.Replicas > 0 {
+		parts = append(parts, fmt.Sprintf("%d replicas", detection.Worker.Replicas))
+	}
+	if detection.Worker.Memory != "" {
+		parts = append(parts, "memory "+detection.Worker.Memory)
+	}
+	if detection.Worker.CPUs != "" {
+		parts = append(parts, detection.Worker.CPUs+" CPUs")
+	}
+	if detection.Worker.StopGracePeriod != "" {
+		parts = append(parts, "stop grace period "+detection.Worker.StopGracePeriod)
+	}
+	if stage := detection.WorkerStage(); stage != "" {
+		parts = append(parts, "Dockerfile stage "+stage)
+	}
+	if detection.NeedsDeadLetter() {
+		parts = append(parts, fmt.Sprintf("dead-letter after %d retries", detection.GetMaxRetries()))
+	}
+	return strings.Join(parts, ", ")
 }
 
 func run(cmd *cobra.Command, args []string) error {
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return err
+	}
+
+	// Get project name from directory name
+	projectName := filepath.Base(absPath)
+	fmt.Fprintf(out, "📂 Analyzing %s...\n", absPath)
+
+	if dryRun {
+		fmt.Fprintln(out, "🔍 Dry run mode - no files will be written")
+	}
+
+	detection, err := detectProject(absPath)
+	if err != nil {
+		return err
+	}
+
+	if err := generateFiles(detection, absPath, projectName); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\n✨ Done!")
+	return nil
+}
+
+// resolveProjectPath returns the absolute project directory from the command arguments,
+// defaulting to the current directory.
+func resolveProjectPath(args []string) (string, error) {
 	// Default to current directory if no path provided
 	path := "."
 	if len(args) > 0 {
@@ -58,145 +241,691 @@ func run(cmd *cobra.Command, args []string) error {
 	// Resolve to absolute path
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		return fmt.Errorf("invalid path: %w", err)
+		return "", fmt.Errorf("invalid path: %w", err)
 	}
 
 	// Verify path exists and is a directory
 	info, err := os.Stat(absPath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return fmt.Errorf("path does not exist: %s", absPath)
+			return "", fmt.Errorf("path does not exist: %s", absPath)
 		}
-		return fmt.Errorf("cannot access path: %w", err)
+		return "", fmt.Errorf("cannot access path: %w", err)
 	}
 	if !info.IsDir() {
-		return fmt.Errorf("path is not a directory: %s", absPath)
+		return "", fmt.Errorf("path is not a directory: %s", absPath)
 	}
 
-	// Get project name from directory name
-	projectName := filepath.Base(absPath)
-	fmt.Printf("📂 Analyzing %s...\n", absPath)
+	report.Project = absPath
+	return absPath, nil
+}
 
-	if dryRun {
-		fmt.Println("🔍 Dry run mode - no files will be written")
+// languageList joins the languages of detections (e.g., "node, rust").
+func languageList(detections []*models.Detection) string {
+	languages := make([]string, 0, len(detections))
+	for _, d := range detections {
+		languages = append(languages, d.Language)
+	}
+	return strings.Join(languages, ", ")
+}
+
+// persistenceList describes what is kept in named volumes (e.g., "shell history, tool caches").
+func persistenceList(persistence models.PersistenceOptions) string {
+	var parts []string
+	if persistence.History {
+		parts = append(parts, "shell history")
+	}
+	if persistence.Caches {
+		parts = append(parts, "tool caches")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sidecarChanges compares the optional sidecars the sidecar policy generates
+// with the ones detection adds. It returns the sidecars left out, the sidecars
+// added, and those --with asks for that the stack has nothing to run them with.
+func sidecarChanges(detection *models.Detection) (removed, added, unusable []string) {
+	detected := *detection
+	detected.Sidecars = models.SidecarPolicy{}
+	for _, sidecar := range models.Sidecars {
+		wanted, generated := detected.NeedsSidecar(sidecar), detection.NeedsSidecar(sidecar)
+		switch {
+		case wanted && !generated:
+			removed = append(removed, sidecar)
+		case generated && !wanted:
+			added = append(added, sidecar)
+		case !generated && slices.Contains(detection.Sidecars.With, sidecar):
+			unusable = append(unusable, sidecar)
+		}
+	}
+	return removed, added, unusable
+}
+
+// serviceVersionList formats service versions as "postgres 15, redis 7".
+func serviceVersionList(versions map[string]string) string {
+	services := make([]string, 0, len(versions))
+	for service := range versions {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	parts := make([]string, len(services))
+	for i, service := range services {
+		parts[i] = service + " " + versions[service]
 	}
+	return strings.Join(parts, ", ")
+}
 
+// importedServiceList formats imported compose services, with the backing
+// service each one runs (e.g., "db (postgres), nginx").
+func importedServiceList(existing *models.ExistingCompose) string {
+	parts := make([]string, len(existing.Services))
+	for i, service := range existing.Services {
+		parts[i] = service.Name
+		if service.Role != "" && service.Role != service.Name {
+			parts[i] += " (" + service.Role + ")"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// buildID identifies this dockstart build. Detection caches written by another
+// build are discarded, since detection rules may differ. Version alone is "dev"
+// for `go install` builds, so the module version and VCS revision are included.
+func buildID() string {
+	id := Version
+	if info, ok := debug.ReadBuildInfo(); ok {
+		id += " " + info.Main.Version
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" || setting.Key == "vcs.modified" {
+				id += " " + setting.Value
+			}
+		}
+	}
+	return id
+}
+
+// commandFlags returns the command-line flags that override .dockstart.yml.
+func commandFlags() project.Flags {
+	return project.Flags{
+		Language:        language,
+		Lockfiles:       lockfiles,
+		NonRoot:         nonRoot,
+		Hardened:        hardened,
+		Target:          target,
+		ComposeVersion:  composeVersion,
+		Environments:    environments,
+		Minimal:         minimal,
+		WithSidecars:    withSidecars,
+		WithoutSidecars: withoutSidecars,
+		Windows:         windows,
+		RemoteDocker:    remoteDocker,
+		Prebuild:        prebuild,
+		Forward:         forward,
+		Persist:         persist,
+		ForceDockerfile: forceDockerfile,
+		Ollama:          ollama,
+		WireMock:        wiremock,
+		Toxiproxy:       toxiproxy,
+		GlitchTip:       glitchtip,
+		Gatus:           gatus,
+		Worker:          workerFlags,
+		FileProcessor:   processorFlags,
+		HostWindows:     runtime.GOOS == "windows",
+	}
+}
+
+// detectProject runs detection and prints a summary of the results.
+// Returns a *detector.NoProjectError when no supported language is detected.
+func detectProject(absPath string) (*models.Detection, error) {
 	// Step 1: Detect project language and services
-	fmt.Println("\n🔍 Detecting project configuration...")
+	fmt.Fprintln(out, "\n🔍 Detecting project configuration...")
+	defer logTiming("detection", time.Now())
 	registry := detector.NewRegistry()
-	detection, err := registry.DetectPrimary(absPath)
+	registry.UseLogger(logger)
+	if !noCache {
+		cache := detector.NewCache(absPath, buildID())
+		cache.ReadOnly = dryRun
+		registry.UseCache(cache)
+	}
+
+	cfg, err := config.Load(absPath)
 	if err != nil {
-		return fmt.Errorf("detection failed: %w", err)
+		return nil, err
+	}
+	dockerHost := "configured"
+	flags := commandFlags()
+	flags.RemoteHost = func() string {
+		if host, err := docker.Host(); err == nil && docker.IsRemote(host) {
+			dockerHost = host
+			return host
+		}
+		return ""
+	}
+	resolution, err := project.Resolve(registry, absPath, cfg, flags)
+	if err != nil {
+		return nil, fmt.Errorf("detection failed: %w", err)
 	}
 
-	if detection == nil {
-		fmt.Println("   ⚠️  No supported language detected")
-		fmt.Println("   Supported: Node.js (package.json), Go (go.mod), Python (pyproject.toml/requirements.txt), Rust (Cargo.toml)")
-		return nil
+	if resolution == nil {
+		fmt.Fprintln(out, "   ⚠️  No supported language detected")
+		return nil, &detector.NoProjectError{Path: absPath}
+	}
+	detection := resolution.Primary
+	existingDockerfile := detection.ExistingDockerfile
+	warnings, err := project.Configure(detection, cfg, filepath.Base(absPath), flags)
+	if err != nil {
+		return nil, newExitError(ExitValidation, "invalid_config", err)
+	}
+	for _, warning := range warnings {
+		warn("%s", warning)
+	}
+	recordDetection(detection)
+	recordResolution(resolution)
+	logger.Info("detected project", "language", detection.Language, "version", detection.Version,
+		"confidence", detection.Confidence, "services", strings.Join(detection.Services, ","))
+	for _, list := range detection.Capabilities.Lists() {
+		if len(list.Libraries) == 0 {
+			continue
+		}
+		logger.Info("detected libraries", "capability", list.Name, "libraries", strings.Join(list.Libraries, ","))
 	}
 
-	fmt.Printf("   ✅ Detected: %s %s (confidence: %.0f%%)\n",
+	fmt.Fprintf(out, "   ✅ Detected: %s %s (confidence: %.0f%%)\n",
 		detection.Language, detection.Version, detection.Confidence*100)
 
+	if len(resolution.Alternatives) > 0 {
+		fmt.Fprintf(out, "   🔀 Chosen over %s: %s\n", languageList(resolution.Alternatives), resolution.Reason)
+		for _, alt := range resolution.Alternatives {
+			fmt.Fprintf(out, "      also found %s %s (confidence: %.0f%%)\n", alt.Language, alt.Version, alt.Confidence*100)
+		}
+		if flags.Language == "" && cfg.Language == "" {
+			fmt.Fprintf(out, "      Use --language %s or \"language: %s\" in %s to choose another\n",
+				resolution.Alternatives[0].Language, resolution.Alternatives[0].Language, config.FileName)
+		}
+	}
+
 	if len(detection.Services) > 0 {
-		fmt.Printf("   📦 Services: %v\n", detection.Services)
+		fmt.Fprintf(out, "   📦 Services: %v\n", detection.Services)
 	}
 
-	// Step 2: Generate devcontainer.json
-	fmt.Println("\n📝 Generating devcontainer.json...")
-	gen := generator.NewDevcontainerGenerator()
+	if len(detection.ServiceVersions) > 0 {
+		fmt.Fprintf(out, "   🏷️  Service versions: %s\n", serviceVersionList(detection.ServiceVersions))
+	}
 
-	if dryRun {
-		// Preview mode - just show what would be generated
-		content, err := gen.GenerateContent(detection, projectName)
-		if err != nil {
-			return fmt.Errorf("generation failed: %w", err)
+	if detection.TimescaleDB {
+		fmt.Fprintln(out, "   📈 TimescaleDB: postgres runs the timescale/timescaledb image")
+	}
+
+	if detection.UsesPostGIS() {
+		fmt.Fprintln(out, "   🌍 PostGIS: postgres runs the postgis/postgis image")
+	} else if len(detection.GeoLibraries) > 0 && detection.HasService("postgres") {
+		warn("Geospatial libraries %s detected, but postgres runs an image without PostGIS (pgvector or TimescaleDB)",
+			strings.Join(detection.GeoLibraries, ", "))
+	}
+
+	if detection.ExistingCompose != nil {
+		fmt.Fprintf(out, "   📥 Importing %s: %s\n", detection.ExistingCompose.File, importedServiceList(detection.ExistingCompose))
+	}
+
+	if existing := existingDockerfile; existing != nil {
+		switch {
+		case detection.ExistingDockerfile == nil:
+			fmt.Fprintf(out, "   🐳 Dockerfile: ignoring %s (--force-dockerfile)\n", existing.File)
+			detection.ExistingDockerfile = nil
+		case existing.Problem != "":
+			fmt.Fprintf(out, "   🐳 Dockerfile: can't reuse %s: %s\n", existing.File, existing.Problem)
+		case existing.Target != "":
+			fmt.Fprintf(out, "   🐳 Dockerfile: reusing %s (target: %s)\n", existing.File, existing.Target)
+		default:
+			fmt.Fprintf(out, "   🐳 Dockerfile: reusing %s\n", existing.File)
 		}
-		fmt.Println("\n--- .devcontainer/devcontainer.json ---")
-		fmt.Println(string(content))
-		fmt.Println("--- end ---")
-	} else {
-		// Check if files already exist
-		devcontainerPath := filepath.Join(absPath, ".devcontainer", "devcontainer.json")
-		if _, err := os.Stat(devcontainerPath); err == nil && !force {
-			return fmt.Errorf("devcontainer.json already exists. Use --force to overwrite")
+	}
+
+	if detection.Lockfile != "" {
+		fmt.Fprintf(out, "   🔒 Lockfile: %s (%d dependencies pinned)\n", detection.Lockfile, len(detection.PinnedVersions))
+	}
+
+	if detection.InstallCommand != "" {
+		fmt.Fprintf(out, "   📥 Install: %s\n", detection.InstallCommand)
+	}
+	if detection.NeedsMigrations() {
+		fmt.Fprintf(out, "   🗃️  Migrations: %s (on container start, once %s is up)\n", detection.MigrateCommand, detection.MigrationDatabase())
+	}
+	if detection.TestCommand != "" {
+		if detection.CoverageCommand != "" {
+			fmt.Fprintf(out, "   🔬 Tests: %s (coverage: %s)\n", detection.TestCommand, detection.CoverageCommand)
+		} else {
+			fmt.Fprintf(out, "   🔬 Tests: %s\n", detection.TestCommand)
 		}
+	}
 
-		// Generate and write the file
-		if err := gen.Generate(detection, absPath, projectName); err != nil {
-			return fmt.Errorf("generation failed: %w", err)
+	if detection.TypeScript {
+		if detection.HasBuildStep() {
+			fmt.Fprintf(out, "   🔷 TypeScript: build with %s (output: %s/)\n", detection.BuildCommand, detection.GetBuildOutputDir())
+		} else {
+			fmt.Fprintln(out, "   🔷 TypeScript: no build script detected")
 		}
-		fmt.Println("   ✅ Created .devcontainer/devcontainer.json")
 	}
 
-	// Step 3: Generate docker-compose.yml (when services or sidecars are detected)
-	needsCompose := len(detection.Services) > 0 || detection.NeedsMetrics() || detection.NeedsWorker() || detection.NeedsFileProcessor()
-	if needsCompose {
-		fmt.Println("\n📝 Generating docker-compose.yml...")
-		composeGen := generator.NewComposeGenerator()
+	if detection.FrontendFramework != "" {
+		if detection.NeedsWebService() {
+			fmt.Fprintf(out, "   🌐 Frontend: %s in %s (dev server on port %d)\n", detection.FrontendFramework, detection.FrontendDir, detection.FrontendPort)
+		} else {
+			fmt.Fprintf(out, "   🌐 Frontend: %s\n", detection.FrontendFramework)
+		}
+	}
 
-		if dryRun {
-			content, err := composeGen.GenerateContent(detection, projectName)
-			if err != nil {
-				return fmt.Errorf("compose generation failed: %w", err)
-			}
-			fmt.Println("\n--- .devcontainer/docker-compose.yml ---")
-			fmt.Println(string(content))
-			fmt.Println("--- end ---")
+	if detection.NeedsWebsockets() {
+		fmt.Fprintf(out, "   🔌 WebSockets: %v\n", detection.WebsocketLibraries)
+	}
+	if detection.NeedsGRPC() {
+		fmt.Fprintf(out, "   📡 gRPC: %v (port %d)\n", detection.GRPCLibraries, detection.GetGRPCPort())
+	}
+	if detection.NeedsAuthProvider() {
+		fmt.Fprintf(out, "   🔐 Auth: %v\n", detection.AuthLibraries)
+	}
+	if detection.NeedsStripe() {
+		fmt.Fprintf(out, "   💳 Payments: %v\n", detection.PaymentLibraries)
+	}
+	if detection.NeedsSeleniumGrid() {
+		fmt.Fprintf(out, "   🧪 WebDriver: %v (Selenium Grid with Chrome and Firefox)\n", detection.WebDriverLibraries)
+	}
+	if len(detection.AWSServices) > 0 {
+		fmt.Fprintf(out, "   ☁️  AWS: %v\n", detection.AWSServices)
+	}
+	if detection.NeedsVectorStore() {
+		fmt.Fprintf(out, "   🧭 Vector store: %s %v\n", detection.GetVectorStore(), detection.VectorLibraries)
+	}
+	if detection.NeedsSearch() {
+		fmt.Fprintf(out, "   🔎 Search: %s %v\n", detection.GetSearchEngine(), detection.SearchLibraries)
+	}
+	if detection.NeedsMailExample() {
+		fmt.Fprintf(out, "   📧 Mail: %v (Mailpit; the worker sends mail too)\n", detection.MailLibraries)
+	} else if detection.NeedsMailpit() {
+		fmt.Fprintf(out, "   📧 Mail: %v (Mailpit)\n", detection.MailLibraries)
+	}
+	if detection.NeedsLLM() || detection.NeedsOllama() {
+		if detection.NeedsOllama() {
+			fmt.Fprintf(out, "   🦙 LLM: %v (local Ollama sidecar)\n", detection.LLMLibraries)
 		} else {
-			composePath := filepath.Join(absPath, ".devcontainer", "docker-compose.yml")
-			if _, err := os.Stat(composePath); err == nil && !force {
-				return fmt.Errorf("docker-compose.yml already exists. Use --force to overwrite")
-			}
+			fmt.Fprintf(out, "   🦙 LLM: %v (use --ollama to run models locally)\n", detection.LLMLibraries)
+		}
+	}
+	if detection.CallsExternalAPIs() || detection.NeedsWireMock() {
+		if detection.NeedsWireMock() {
+			fmt.Fprintf(out, "   🎭 External APIs: %v (stubbed by WireMock)\n", detection.ExternalAPIs)
+		} else {
+			fmt.Fprintf(out, "   🎭 External APIs: %v (use --wiremock to stub them)\n", detection.ExternalAPIs)
+		}
+	}
+	if detection.TracksErrors() || detection.NeedsGlitchTip() {
+		if detection.NeedsGlitchTip() {
+			fmt.Fprintf(out, "   🐛 Error tracking: %v (local GlitchTip)\n", detection.ErrorTrackingLibraries)
+		} else {
+			fmt.Fprintf(out, "   🐛 Error tracking: %v (use --glitchtip to receive errors locally)\n", detection.ErrorTrackingLibraries)
+		}
+	}
+	if detection.NeedsWorker() {
+		fmt.Fprintf(out, "   👷 Worker: %v (command: %s; %s)\n", detection.QueueLibraries, detection.WorkerCommand, workerSettingList(detection))
+	}
+	if detection.NeedsScheduler() {
+		fmt.Fprintf(out, "   ⏰ Scheduler: %v\n", detection.SchedulerLibraries)
+	}
+	removed, added, unusable := sidecarChanges(detection)
+	if len(removed) > 0 {
+		fmt.Fprintf(out, "   🪶 Leaving out sidecars: %s (add one back with --with)\n", strings.Join(removed, ", "))
+	}
+	if len(added) > 0 {
+		fmt.Fprintf(out, "   🧩 Adding sidecars: %s\n", strings.Join(added, ", "))
+	}
+	for _, sidecar := range unusable {
+		warn("--with %s: nothing in the stack for this sidecar to work with", sidecar)
+	}
+	if detection.NonRoot {
+		fmt.Fprintln(out, "   👤 Non-root: containers run as the host UID/GID (USER_UID/USER_GID)")
+	}
+	if detection.Hardened {
+		fmt.Fprintln(out, "   🔒 Hardened: read-only root filesystems, no-new-privileges, dropped capabilities")
+	}
+	if detection.Windows {
+		fmt.Fprintln(out, "   🪟 Windows: LF line endings, dependencies in named volumes")
+	}
+	if detection.Prebuild != nil {
+		fmt.Fprintf(out, "   🏗️  Prebuild: dependencies in their own layer, cache from %s\n", detection.Prebuild.Image)
+	}
+	if len(detection.Forward) > 0 {
+		fmt.Fprintf(out, "   🔑 Forwarding: %s\n", strings.Join(detection.Forward, ", "))
+	}
+	if detection.RemoteDocker {
+		fmt.Fprintf(out, "   🌐 Remote Docker (%s): workspace synced into the %s volume\n",
+			dockerHost, generator.RemoteWorkspaceVolume(filepath.Base(absPath)))
+	}
+	if detection.TargetsCodespaces() {
+		fmt.Fprintln(out, "   ☁️  Codespaces: client features, setup in onCreateCommand for prebuilds, smaller resource limits")
+	}
+	if detection.NeedsPersistentVolumes() {
+		fmt.Fprintf(out, "   💾 Persisted: %s\n", persistenceList(detection.Persistence))
+	}
+	if detection.Persistence.Dotfiles != "" {
+		fmt.Fprintf(out, "   🏠 Dotfiles: %s\n", detection.Persistence.Dotfiles)
+	}
+	if detection.NeedsTestDatabase() {
+		fmt.Fprintf(out, "   🧫 Test data: isolated by %s (TEST_DATABASE_URL, TEST_REDIS_URL)\n", detection.Testing.Isolation)
+	}
+
+	return detection, nil
+}
+
+// generateFiles writes (or previews, in dry-run mode) all .devcontainer files for the detection.
+func generateFiles(detection *models.Detection, absPath, projectName string) error {
+	// The organization policy sets its defaults before anything is planned
+	orgPolicy, err := loadPolicy()
+	if err != nil {
+		return err
+	}
+	if orgPolicy != nil {
+		orgPolicy.Apply(detection)
+	}
+
+	// Refuse to overwrite anything before writing the first file
+	if !dryRun && !force {
+		if err := checkConflicts(detection, absPath); err != nil {
+			return err
+		}
+	}
+
+	// Estimate the stack's footprint before anything is written or pulled
+	if detection.NeedsCompose() {
+		if err := printStackEstimate(detection, projectName); err != nil {
+			return err
+		}
+	}
 
-			if err := composeGen.Generate(detection, absPath, projectName); err != nil {
-				return fmt.Errorf("compose generation failed: %w", err)
+	plan, err := generationPlan(detection, absPath, projectName)
+	if err != nil {
+		return err
+	}
+	logger.Debug("generation plan", "steps", strings.Join(plan.Names(), ","))
+	if orgPolicy != nil {
+		if err := checkPolicy(orgPolicy, detection, projectName); err != nil {
+			return err
+		}
+	}
+	if !noLint {
+		if err := lintPlan(plan, absPath); err != nil {
+			return err
+		}
+	}
+	if dryRun {
+		return previewPlan(plan, absPath)
+	}
+
+	defer logTiming("generation", time.Now())
+	err = plan.Execute(absPath, func(step generator.Step, existed []bool) {
+		logger.Info("generator ran", "step", step.Name, "files", len(step.Files))
+		fmt.Fprintf(out, "\n📝 %s\n", step.Title)
+		for i, rel := range step.Files {
+			action := "created"
+			if existed[i] {
+				action = "overwritten"
+				if step.Updates {
+					action = "updated"
+				}
 			}
-			fmt.Println("   ✅ Created .devcontainer/docker-compose.yml")
+			fileWritten(rel, action)
+		}
+	})
+	var rollback *generator.RollbackError
+	if errors.As(err, &rollback) {
+		fmt.Fprintf(out, "\n↩️  %s failed; restored the files written before it\n", rollback.Step)
+		report.Files = nil
+	}
+	return err
+}
+
+// generationPlan checks the generated docker-compose.yml and returns the plan
+// of the generators that produce the project's files.
+func generationPlan(detection *models.Detection, absPath, projectName string) (*generator.Plan, error) {
+	if detection.NeedsCompose() {
+		if err := checkCompose(detection, projectName); err != nil {
+			return nil, err
+		}
+	}
+
+	var opts project.PlanOptions
+	if !dryRun {
+		opts.Report = func(fsys generator.FS, projectPath string, owners map[string]string) error {
+			return writeGenerationReport(fsys, detection, projectPath, owners)
 		}
 	}
+	plan, err := project.BuildPlan(detection, absPath, projectName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if detection.NeedsToxiproxy() && !generator.NewToxiproxyGenerator().ShouldGenerate(detection) {
+		fmt.Fprintln(out, "\n⚠️  --toxiproxy: no PostgreSQL or Redis to proxy")
+	}
+	if detection.ReusesDockerfile() {
+		logger.Info("file skipped", "file", ".devcontainer/Dockerfile", "reason", "reusing "+detection.ExistingDockerfile.File)
+		fmt.Fprintf(out, "\n🐳 Using %s (use --force-dockerfile to generate one)\n", detection.ExistingDockerfile.File)
+	}
+	return plan, nil
+}
+
+// checkCompose validates the generated docker-compose.yml, records its
+// services, and warns about its memory limits and image versions.
+func checkCompose(detection *models.Detection, projectName string) error {
+	composeGen := generator.NewComposeGenerator()
+
+	// Parsing the rendered file validates it and lists the generated services
+	services, err := composeGen.Services(detection, projectName)
+	if err != nil {
+		return newExitError(ExitValidation, "invalid_config", fmt.Errorf("generated docker-compose.yml is invalid: %w", err))
+	}
+	report.Services = services
+	if err := checkMemoryBudget(composeGen, detection, projectName); err != nil {
+		return err
+	}
+	versionWarnings, err := composeGen.VersionWarnings(detection, projectName)
+	if err != nil {
+		return fmt.Errorf("compose generation failed: %w", err)
+	}
+	for _, warning := range versionWarnings {
+		warn("%s", warning)
+	}
+	remoteWarnings, err := composeGen.RemoteWarnings(detection, projectName)
+	if err != nil {
+		return fmt.Errorf("compose generation failed: %w", err)
+	}
+	for _, warning := range remoteWarnings {
+		warn("%s", warning)
+	}
+	return nil
+}
 
-	// Step 3b: Generate metrics sidecar files (Prometheus + Grafana config)
-	metricsGen := generator.NewMetricsSidecarGenerator()
-	if metricsGen.ShouldGenerate(detection) {
-		fmt.Println("\n📝 Generating metrics stack configuration...")
-		if !dryRun {
-			if err := metricsGen.Generate(detection, absPath, projectName); err != nil {
-				return fmt.Errorf("metrics sidecar generation failed: %w", err)
+// previewPlan prints the files a plan would write, without writing them.
+// Every step runs in memory first, so a dry run fails where a real run would.
+func previewPlan(plan *generator.Plan, projectPath string) error {
+	steps, err := plan.Steps()
+	if err != nil {
+		return err
+	}
+	if _, err := plan.Render(projectPath); err != nil {
+		return err
+	}
+	for _, step := range steps {
+		fmt.Fprintf(out, "\n📝 %s\n", step.Title)
+		if step.Preview == nil {
+			if step.Summary != "" {
+				fmt.Fprintf(out, "   %s\n", step.Summary)
 			}
-			fmt.Println("   ✅ Created .devcontainer/prometheus/prometheus.yml")
-			fmt.Println("   ✅ Created .devcontainer/grafana/provisioning/datasources/prometheus.yml")
-			fmt.Println("   ✅ Created .devcontainer/grafana/provisioning/dashboards/provider.yml")
-			fmt.Println("   ✅ Created .devcontainer/grafana/provisioning/dashboards/app-metrics.json")
-		} else {
-			fmt.Println("   📊 Would create Prometheus and Grafana configuration files")
+			filesPreviewed(step.Files)
+			continue
+		}
+		for _, rel := range step.Files {
+			content, err := step.Preview(rel)
+			if err != nil {
+				return err
+			}
+			previewFile(rel, content)
 		}
 	}
+	return nil
+}
+
+// printStackEstimate prints the download size and idle memory of the planned
+// stack's images, so sidecars can be trimmed on constrained machines first.
+func printStackEstimate(detection *models.Detection, projectName string) error {
+	images, err := stackImages(detection, projectName)
+	if err != nil {
+		return newExitError(ExitValidation, "invalid_config", fmt.Errorf("generated docker-compose.yml is invalid: %w", err))
+	}
+	estimate := generator.EstimateStack(images, docker.ImageExists)
 
-	// Step 4: Generate Dockerfile
-	fmt.Println("\n📝 Generating Dockerfile...")
-	dockerfileGen := generator.NewDockerfileGenerator()
+	fmt.Fprintln(out, "\n📦 Estimating the stack size...")
+	for _, image := range estimate.Images {
+		var details []string
+		switch {
+		case !image.Known:
+			details = append(details, "no estimate")
+		case image.Pulled:
+			details = append(details, "already pulled")
+		default:
+			details = append(details, "~"+generator.FormatMB(image.DownloadMB)+" download")
+		}
+		if image.MemoryMB > 0 {
+			details = append(details, "~"+generator.FormatMB(image.MemoryMB)+" memory")
+		}
+		fmt.Fprintf(out, "   %-48s %s\n", image.Image, strings.Join(details, ", "))
+	}
+	fmt.Fprintf(out, "   Total: ~%s to download, ~%s of memory when idle, plus the app\n",
+		generator.FormatMB(estimate.DownloadMB), generator.FormatMB(estimate.MemoryMB))
+	return nil
+}
 
-	if dryRun {
-		content, err := dockerfileGen.GenerateContent(detection, projectName)
-		if err != nil {
-			return fmt.Errorf("dockerfile generation failed: %w", err)
+// checkMemoryBudget warns when the memory limits of the services "docker compose up"
+// starts add up to more than --max-total-memory, or the memory available to Docker.
+func checkMemoryBudget(composeGen *generator.ComposeGenerator, detection *models.Detection, projectName string) error {
+	budget, source := int64(0), "--max-total-memory"
+	if maxTotalMemory != "" {
+		parsed, err := generator.ParseMemory(maxTotalMemory)
+		if err != nil || parsed == 0 {
+			return newExitError(ExitValidation, "invalid_config", fmt.Errorf("invalid --max-total-memory %q: expected a size like \"8g\"", maxTotalMemory))
 		}
-		fmt.Println("\n--- .devcontainer/Dockerfile ---")
-		fmt.Println(string(content))
-		fmt.Println("--- end ---")
+		budget = parsed
 	} else {
-		dockerfilePath := filepath.Join(absPath, ".devcontainer", "Dockerfile")
-		if _, err := os.Stat(dockerfilePath); err == nil && !force {
-			return fmt.Errorf("Dockerfile already exists. Use --force to overwrite")
+		// Without Docker there is nothing to compare against
+		total, err := docker.MemTotal()
+		if err != nil {
+			return nil
+		}
+		budget, source = total, "memory available to Docker"
+	}
+
+	usage, err := composeGen.MemoryBudget(detection, projectName)
+	if err != nil {
+		return fmt.Errorf("compose generation failed: %w", err)
+	}
+	if usage.Total > budget {
+		warn("Memory limits add up to %s, over the %s %s; lower them with resources in %s",
+			generator.FormatMemory(usage.Total), generator.FormatMemory(budget), source, config.FileName)
+	}
+	return nil
+}
+
+// writeGenerationReport writes .devcontainer/dockstart-report.json through fsys
+// for the files written by generateFiles, with the generator owning each one.
+func writeGenerationReport(fsys generator.FS, detection *models.Detection, absPath string, owners map[string]string) error {
+	var files []string
+	for _, f := range report.Files {
+		if f.Action != "would-create" {
+			files = append(files, f.Path)
 		}
+	}
+
+	options := generator.ReportOptions{
+		Language:        language,
+		Lockfiles:       lockfiles,
+		NoCache:         noCache,
+		Force:           force,
+		ForceDockerfile: forceDockerfile,
+	}
+	genReport, err := generator.NewGenerationReport(fsys, absPath, buildID(), buildInfo(), options, detection, files)
+	if err != nil {
+		return fmt.Errorf("generation report failed: %w", err)
+	}
+
+	genReport.Owners = make(map[string]string, len(files))
+	for _, f := range files {
+		genReport.Owners[f] = owners[f]
+	}
+
+	if err := genReport.Write(fsys, absPath); err != nil {
+		return fmt.Errorf("generation report failed: %w", err)
+	}
+	return nil
+}
 
-		if err := dockerfileGen.Generate(detection, absPath, projectName); err != nil {
-			return fmt.Errorf("dockerfile generation failed: %w", err)
+// checkConflicts returns a conflict error if any primary file would be overwritten.
+// Sidecar config files are always regenerated alongside them, and devcontainer.json
+// is updated in place.
+func checkConflicts(detection *models.Detection, absPath string) error {
+	var files []string
+	if detection.NeedsCompose() {
+		files = append(files, "docker-compose.yml")
+	}
+	if !detection.ReusesDockerfile() {
+		files = append(files, "Dockerfile")
+	}
+
+	var existing []string
+	for _, name := range files {
+		if _, err := os.Stat(filepath.Join(absPath, ".devcontainer", name)); err == nil {
+			existing = append(existing, name)
 		}
-		fmt.Println("   ✅ Created .devcontainer/Dockerfile")
 	}
 
-	fmt.Println("\n✨ Done!")
+	if len(existing) > 0 {
+		return &generator.ConflictError{Files: existing}
+	}
 	return nil
 }
+
+// fileAction returns "overwritten" if a project file exists, "created" otherwise.
+func fileAction(absPath, rel string) string {
+	if _, err := os.Stat(filepath.Join(absPath, filepath.FromSlash(rel))); err == nil {
+		return "overwritten"
+	}
+	return "created"
+}
+
+// fileWritten prints and records a written file.
+func fileWritten(rel, action string) {
+	switch action {
+	case "overwritten":
+		fmt.Fprintf(out, "   ✅ Overwrote %s\n", rel)
+	case "updated":
+		fmt.Fprintf(out, "   ✅ Updated %s\n", rel)
+	default:
+		fmt.Fprintf(out, "   ✅ Created %s\n", rel)
+	}
+	logger.Info("file written", "file", rel, "action", action)
+	recordFile(rel, action)
+}
+
+// previewFile prints the content of a file that would be written in dry-run mode.
+func previewFile(rel string, content []byte) {
+	fmt.Fprintf(out, "\n--- %s ---\n", rel)
+	fmt.Fprintln(out, string(content))
+	fmt.Fprintln(out, "--- end ---")
+	recordFile(rel, "would-create")
+}
+
+// filesPreviewed records files that would be written in dry-run mode.
+func filesPreviewed(files []string) {
+	for _, f := range files {
+		recordFile(f, "would-create")
+	}
+}
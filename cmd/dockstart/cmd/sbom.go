@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/sbom"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// sbomFormat is the SBOM document format ("cyclonedx" or "spdx")
+	sbomFormat string
+
+	// sbomFile is where the SBOM is written (stdout when empty)
+	sbomFile string
+)
+
+// sbomCmd writes a software bill of materials for the generated dev stack.
+var sbomCmd = &cobra.Command{
+	Use:   "sbom [path]",
+	Short: "Write a CycloneDX or SPDX bill of materials for the generated stack",
+	Long: `sbom lists what the generated dev environment is made of: the dependencies
+declared in the project's manifest and every image the stack pulls (the
+Dockerfile base image and the sidecar images in docker-compose.yml).
+
+Dependency versions come from lockfiles, so pass --lockfiles (or set
+lockfiles: true in .dockstart.yml) to include them. The document is written
+to stdout unless --file is given. No .devcontainer files are written.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSBOM,
+}
+
+func init() {
+	sbomCmd.Flags().StringVar(&sbomFormat, "format", "cyclonedx", "Document format: cyclonedx or spdx")
+	sbomCmd.Flags().StringVarP(&sbomFile, "file", "f", "", "Write the SBOM to this file instead of stdout")
+	sbomCmd.Flags().BoolVar(&ollama, "ollama", false, "Include the local Ollama sidecar in the planned stack")
+	sbomCmd.Flags().BoolVar(&wiremock, "wiremock", false, "Include the WireMock sidecar in the planned stack")
+	sbomCmd.Flags().BoolVar(&toxiproxy, "toxiproxy", false, "Include the Toxiproxy sidecar in the planned stack")
+	sbomCmd.Flags().BoolVar(&glitchtip, "glitchtip", false, "Include the GlitchTip sidecar in the planned stack")
+	sbomCmd.Flags().BoolVar(&gatus, "gatus", false, "Include the Gatus status page in the planned stack")
+	addSidecarFlags(sbomCmd)
+	rootCmd.AddCommand(sbomCmd)
+}
+
+func runSBOM(cmd *cobra.Command, args []string) error {
+	if sbomFormat != "cyclonedx" && sbomFormat != "spdx" {
+		return fmt.Errorf("invalid --format %q: must be cyclonedx or spdx", sbomFormat)
+	}
+
+	if sbomFile == "" {
+		// stdout carries the SBOM
+		if jsonOutput() {
+			return fmt.Errorf("--file is required with --output json")
+		}
+		out = os.Stderr
+	}
+
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return err
+	}
+
+	projectName := filepath.Base(absPath)
+	fmt.Fprintf(out, "📂 Analyzing %s...\n", absPath)
+
+	detection, err := detectProject(absPath)
+	if err != nil {
+		return err
+	}
+
+	images, err := stackImages(detection, projectName)
+	if err != nil {
+		return err
+	}
+
+	bom := sbom.New(projectName, Version, detection, images)
+	var data []byte
+	if sbomFormat == "spdx" {
+		data, err = bom.SPDX()
+	} else {
+		data, err = bom.CycloneDX()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to render SBOM: %w", err)
+	}
+
+	fmt.Fprintf(out, "\n📦 %d dependencies, %d images\n", len(detection.Dependencies), len(images))
+	if detection.Lockfile == "" && len(detection.Dependencies) > 0 {
+		fmt.Fprintln(out, "   Dependency versions are omitted; pass --lockfiles to pin them")
+	}
+
+	if sbomFile == "" {
+		_, err := os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+
+	if err := os.WriteFile(sbomFile, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", sbomFile, err)
+	}
+	recordFile(sbomFile, "created")
+	fmt.Fprintf(out, "   ✅ Created %s\n", sbomFile)
+	return nil
+}
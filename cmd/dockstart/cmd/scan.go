@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/docker"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// scanFailOn is the lowest severity that makes `scan` exit non-zero
+	scanFailOn string
+
+	// scanIgnoreUnfixed skips vulnerabilities without a fixed version
+	scanIgnoreUnfixed bool
+)
+
+// scanCmd scans the images of the generated compose stack for vulnerabilities.
+var scanCmd = &cobra.Command{
+	Use:   "scan [path]",
+	Short: "Scan the generated stack's images for vulnerabilities with Trivy",
+	Long: `scan runs Trivy (in a container, no install needed) against every image in
+.devcontainer/docker-compose.yml, including the app images compose builds, and
+reports the vulnerabilities found in each by severity. Images that have not
+been built yet are built first.
+
+With --fail-on, scan exits with code 4 when any image has a vulnerability at
+or above that severity, so it can gate CI jobs.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runScan,
+}
+
+func init() {
+	scanCmd.Flags().StringVar(&scanFailOn, "fail-on", "", "Exit non-zero on vulnerabilities at or above this severity ("+strings.Join(docker.Severities, ", ")+")")
+	scanCmd.Flags().BoolVar(&scanIgnoreUnfixed, "ignore-unfixed", false, "Skip vulnerabilities without a fixed version")
+	rootCmd.AddCommand(scanCmd)
+}
+
+func runScan(cmd *cobra.Command, args []string) error {
+	if scanFailOn != "" && docker.SeverityRank(scanFailOn) < 0 {
+		return fmt.Errorf("invalid --fail-on %q: must be one of %s", scanFailOn, strings.Join(docker.Severities, ", "))
+	}
+	scanFailOn = strings.ToUpper(scanFailOn)
+
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return err
+	}
+
+	compose := newCompose(absPath)
+	if jsonOutput() {
+		// Keep stdout for the JSON result; docker compose progress goes to stderr
+		compose.Stdout = os.Stderr
+	}
+	if _, err := os.Stat(compose.File); err != nil {
+		return fmt.Errorf("no .devcontainer/docker-compose.yml in %s: run dockstart first", absPath)
+	}
+
+	if err := docker.Available(); err != nil {
+		return err
+	}
+
+	images, err := compose.Images()
+	if err != nil {
+		return err
+	}
+
+	// Trivy reads built images from the local image store, so build any that are missing
+	var unbuilt []string
+	for _, image := range images {
+		if image.Built && !docker.ImageExists(image.Image) {
+			unbuilt = append(unbuilt, image.Service)
+		}
+	}
+	if len(unbuilt) > 0 {
+		fmt.Fprintf(out, "🔨 Building %s...\n", strings.Join(unbuilt, ", "))
+		if err := compose.Build(unbuilt...); err != nil {
+			return err
+		}
+	}
+
+	// Services sharing an image are scanned once
+	var order []string
+	services := make(map[string][]string)
+	for _, image := range images {
+		if _, ok := services[image.Image]; !ok {
+			order = append(order, image.Image)
+		}
+		services[image.Image] = append(services[image.Image], image.Service)
+	}
+
+	fmt.Fprintf(out, "🔍 Scanning %d images with Trivy...\n", len(order))
+	totals := make(map[string]int)
+	failing := 0
+	for _, image := range order {
+		result, err := docker.Scan(image, scanIgnoreUnfixed)
+		if err != nil {
+			return err
+		}
+		for severity, n := range result.Counts {
+			totals[severity] += n
+		}
+
+		icon := "✅"
+		if scanFailOn != "" && result.AtOrAbove(scanFailOn) > 0 {
+			icon = "❌"
+			failing++
+		} else if result.Total() > 0 {
+			icon = "⚠️ "
+		}
+		fmt.Fprintf(out, "   %s %s (%s): %s\n", icon, image, strings.Join(services[image], ", "), severitySummary(result.Counts))
+
+		report.Scans = append(report.Scans, scanResult{
+			Image:           image,
+			Services:        services[image],
+			Vulnerabilities: result.Counts,
+		})
+	}
+
+	fmt.Fprintf(out, "\n📊 %s\n", severitySummary(totals))
+
+	if failing > 0 {
+		return newExitError(ExitValidation, "vulnerabilities",
+			fmt.Errorf("%d of %d images have %s or higher vulnerabilities", failing, len(order), scanFailOn))
+	}
+
+	fmt.Fprintln(out, "\n✨ Scan complete!")
+	return nil
+}
+
+// severitySummary formats vulnerability counts from the highest severity down
+// (e.g., "CRITICAL 1, HIGH 4").
+func severitySummary(counts map[string]int) string {
+	var parts []string
+	for i := len(docker.Severities) - 1; i >= 0; i-- {
+		if n := counts[docker.Severities[i]]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", docker.Severities[i], n))
+		}
+	}
+	if len(parts) == 0 {
+		return "no vulnerabilities"
+	}
+	return strings.Join(parts, ", ")
+}
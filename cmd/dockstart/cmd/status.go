@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/docker"
+	"github.com/spf13/cobra"
+)
+
+// statusLogLines is how many recent error lines `status` shows per service.
+var statusLogLines int
+
+// statusLogTail is how many log lines `status` scans per service for errors.
+const statusLogTail = 200
+
+// statusCmd shows the live state of a project's compose stack.
+var statusCmd = &cobra.Command{
+	Use:   "status [path]",
+	Short: "Show the live state of the generated compose stack",
+	Long: `status queries Docker for the project's compose services and shows each
+service's state, health, restart count, published ports, and its most recent
+error log lines.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runStatus,
+}
+
+func init() {
+	statusCmd.Flags().IntVar(&statusLogLines, "log-lines", 3, "Recent error log lines to show per service (0 to disable)")
+	rootCmd.AddCommand(statusCmd)
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return err
+	}
+
+	compose := newCompose(absPath)
+	if _, err := os.Stat(compose.File); err != nil {
+		warn("No .devcontainer/docker-compose.yml in %s", absPath)
+		fmt.Fprintln(out, "   Run `dockstart up` to generate and start the stack")
+		return nil
+	}
+
+	if err := docker.Available(); err != nil {
+		return err
+	}
+
+	statuses, err := compose.PS()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "📊 %s\n", compose.ProjectName)
+	if len(statuses) == 0 {
+		fmt.Fprintln(out, "   Stack is not running - start it with `dockstart up`")
+		return nil
+	}
+
+	names := make([]string, 0, len(statuses))
+	for _, s := range statuses {
+		names = append(names, s.Name)
+	}
+	restarts, err := docker.RestartCounts(names)
+	if err != nil {
+		// Restart counts are informational; keep going without them
+		warn("%v", err)
+		restarts = map[string]int{}
+	}
+
+	ready := 0
+	for _, s := range statuses {
+		if s.IsReady() {
+			ready++
+		}
+
+		line := fmt.Sprintf("   %s %-16s %s", statusIcon(s), s.Service, s.Summary())
+		if count := restarts[s.Name]; count > 0 {
+			line += fmt.Sprintf(", %d restarts", count)
+		}
+		if ports := s.Ports(); len(ports) > 0 {
+			line += "  [" + strings.Join(ports, ", ") + "]"
+		}
+		fmt.Fprintln(out, line)
+
+		container := containerResult{
+			Service:  s.Service,
+			State:    s.State,
+			Health:   s.Health,
+			Ready:    s.IsReady(),
+			Restarts: restarts[s.Name],
+			Ports:    s.Ports(),
+		}
+
+		if statusLogLines > 0 {
+			if logs, err := compose.Logs(s.Service, statusLogTail); err == nil {
+				container.Errors = docker.ErrorLines(logs, statusLogLines)
+				for _, errLine := range container.Errors {
+					fmt.Fprintf(out, "      │ %s\n", truncate(errLine, 120))
+				}
+			}
+		}
+
+		report.Containers = append(report.Containers, container)
+	}
+
+	fmt.Fprintf(out, "\n   %d/%d services ready\n", ready, len(statuses))
+	return nil
+}
+
+// truncate shortens s to at most max runes, marking the cut with an ellipsis.
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/docker"
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// tasksFormat is the task runner format ("just", "earthly", or "make")
+var tasksFormat string
+
+// tasksCmd generates a task file for the generated compose stack.
+var tasksCmd = &cobra.Command{
+	Use:   "tasks [path]",
+	Short: "Generate a Justfile, Earthfile, or Makefile with tasks for the dev environment",
+	Long: `tasks generates a task file at the project root with the same tasks for every
+detected language, each running docker compose against the devcontainer's
+compose file and project:
+
+  up          start the dev environment in the background
+  down        stop it (data volumes are kept)
+  logs        follow the logs of every service, or of the given ones
+  test        run the test suite in the one-shot test service
+  backup-now  back up the databases now
+  restore     restore a backup from .devcontainer/backups
+  psql        open psql on the development database
+  redis-cli   open redis-cli
+
+Tasks for services the project doesn't use are left out. --format just (the
+default) writes a Justfile; --format earthly writes an Earthfile.
+
+--format make writes a Makefile for teams that standardize on make, with
+.PHONY targets named the make way: dev, stop, rebuild, migrate, seed, backup,
+and dashboards (which prints the app and dashboard URLs). COMPOSE_PROJECT and
+COMPOSE_FILE can be overridden on the command line.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTasks,
+}
+
+func init() {
+	tasksCmd.Flags().StringVar(&tasksFormat, "format", generator.TasksJust, "Task runner: just, earthly, or make")
+	tasksCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview the task file without writing it")
+	tasksCmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing task file")
+	rootCmd.AddCommand(tasksCmd)
+}
+
+func runTasks(cmd *cobra.Command, args []string) error {
+	file := generator.TasksFile(tasksFormat)
+	if file == "" {
+		return fmt.Errorf("invalid --format %q: must be one of %s", tasksFormat, strings.Join(generator.TaskFormats, ", "))
+	}
+
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return err
+	}
+
+	projectName := filepath.Base(absPath)
+	fmt.Fprintf(out, "📂 Analyzing %s...\n", absPath)
+
+	detection, err := detectProject(absPath)
+	if err != nil {
+		return err
+	}
+	if !detection.NeedsCompose() {
+		return fmt.Errorf("%s runs as a single devcontainer without docker-compose.yml, so there are no compose tasks to generate", projectName)
+	}
+
+	if !dryRun && !force {
+		if _, err := os.Stat(filepath.Join(absPath, file)); err == nil {
+			return &generator.ConflictError{Files: []string{file}}
+		}
+	}
+
+	fmt.Fprintf(out, "\n📝 Generating %s...\n", file)
+	gen := generator.NewTasksGenerator()
+	composeProject := docker.ProjectName(absPath)
+	if dryRun {
+		content, err := gen.GenerateContent(detection, projectName, composeProject, tasksFormat)
+		if err != nil {
+			return err
+		}
+		previewFile(file, content)
+	} else {
+		action := fileAction(absPath, file)
+		if err := gen.Generate(detection, absPath, projectName, composeProject, tasksFormat); err != nil {
+			return err
+		}
+		fileWritten(file, action)
+	}
+
+	if _, err := os.Stat(newCompose(absPath).File); err != nil {
+		warn("No .devcontainer/docker-compose.yml yet: run dockstart to generate it before using the tasks")
+	}
+
+	fmt.Fprintln(out, "\n✨ Done!")
+	return nil
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/jpequegn/dockstart/internal/config"
+	"github.com/jpequegn/dockstart/internal/docker"
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// testCoverage runs the coverage command instead of the test command.
+var testCoverage bool
+
+// testCmd runs the test suite in the test compose profile.
+var testCmd = &cobra.Command{
+	Use:   "test [path]",
+	Short: "Run the test suite against the stack in a one-shot test container",
+	Long: `test runs the detected test command (npm test, pytest, go test ./...,
+cargo test) in the test service of the generated docker-compose.yml. The
+service is built like app, waits for the databases to be healthy, applies
+pending migrations, and uses the test databases when testing.isolation is set
+in .dockstart.yml. Files are generated first when they don't exist yet.
+
+The exit code is 4 when the tests fail.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTest,
+}
+
+func init() {
+	testCmd.Flags().BoolVar(&testCoverage, "coverage", false, "Run the detected coverage command instead (e.g., go test -coverprofile=coverage.out ./...)")
+	testCmd.Flags().BoolVar(&force, "force", false, "Regenerate and overwrite existing files")
+	rootCmd.AddCommand(testCmd)
+}
+
+func runTest(cmd *cobra.Command, args []string) error {
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return err
+	}
+
+	projectName := filepath.Base(absPath)
+	fmt.Fprintf(out, "📂 Analyzing %s...\n", absPath)
+
+	if err := docker.Available(); err != nil {
+		return err
+	}
+
+	detection, err := detectProject(absPath)
+	if err != nil {
+		return err
+	}
+
+	command := detection.TestCommand
+	if testCoverage {
+		command = detection.CoverageCommand
+		if command == "" {
+			return fmt.Errorf("no coverage tool detected: set testing.coverage in %s", config.FileName)
+		}
+	}
+	if command == "" {
+		return fmt.Errorf("no test command detected: set testing.command in %s", config.FileName)
+	}
+
+	// Generate files unless an existing stack should be kept
+	compose := newCompose(absPath)
+	if jsonOutput() {
+		// Keep stdout for the JSON result; test output goes to stderr
+		compose.Stdout = os.Stderr
+	}
+	if _, err := os.Stat(compose.File); err == nil && !force {
+		fmt.Fprintln(out, "\n📄 Using existing .devcontainer files (use --force to regenerate)")
+	} else {
+		if err := generateFiles(detection, absPath, projectName); err != nil {
+			return err
+		}
+	}
+
+	if _, err := os.Stat(compose.File); err != nil {
+		return fmt.Errorf("no docker-compose.yml for this project: it runs as a single devcontainer, so run %q inside it", command)
+	}
+
+	// Files generated before the test service existed don't have it
+	services, err := compose.Services(generator.TestProfile)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(services, generator.TestService) {
+		return fmt.Errorf("docker-compose.yml has no %s service: regenerate it with --force", generator.TestService)
+	}
+
+	fmt.Fprintf(out, "\n🔬 Running %s...\n", command)
+	var run []string
+	if testCoverage {
+		run = []string{"sh", "-c", generator.TestRunCommand(detection, command)}
+	}
+	if err := compose.Run(generator.TestProfile, generator.TestService, run...); err != nil {
+		return newExitError(ExitValidation, "tests-failed", err)
+	}
+
+	fmt.Fprintln(out, "\n✨ Tests passed!")
+	return nil
+}
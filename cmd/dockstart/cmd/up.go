@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jpequegn/dockstart/internal/docker"
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// upTimeout is how long `up` waits for services to become ready.
+var upTimeout time.Duration
+
+// upCmd generates the dev environment (if needed) and starts it with docker compose.
+var upCmd = &cobra.Command{
+	Use:   "up [path]",
+	Short: "Generate the dev environment and start it with docker compose",
+	Long: `up runs detection, generates .devcontainer files when they don't exist yet
+(existing files are kept unless --force is given), then starts the stack with
+docker compose and waits for every service to become ready.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUp,
+}
+
+func init() {
+	upCmd.Flags().BoolVar(&force, "force", false, "Regenerate and overwrite existing files")
+	upCmd.Flags().BoolVar(&forceDockerfile, "force-dockerfile", false, "Generate .devcontainer/Dockerfile even if the project has a reusable Dockerfile")
+	upCmd.Flags().BoolVar(&ollama, "ollama", false, "Add a local Ollama sidecar for LLM-backed apps")
+	upCmd.Flags().BoolVar(&wiremock, "wiremock", false, "Add a WireMock sidecar that stubs third-party APIs")
+	upCmd.Flags().BoolVar(&toxiproxy, "toxiproxy", false, "Route database connections through a Toxiproxy sidecar to inject latency and failures")
+	upCmd.Flags().BoolVar(&glitchtip, "glitchtip", false, "Add a self-hosted GlitchTip that receives the Sentry SDK's errors")
+	upCmd.Flags().BoolVar(&gatus, "gatus", false, "Add a Gatus status page that checks the app, databases, and dashboards")
+	addSidecarFlags(upCmd)
+	upCmd.Flags().StringVar(&maxTotalMemory, "max-total-memory", "", "Warn when the services' memory limits add up to more than this (e.g., 8g; default: the memory available to Docker)")
+	upCmd.Flags().BoolVar(&nonRoot, "non-root", false, "Run containers as a non-root user matching the host UID/GID (USER_UID/USER_GID)")
+	upCmd.Flags().BoolVar(&hardened, "hardened", false, "Harden services: read-only root filesystem, no-new-privileges, cap_drop: ALL")
+	upCmd.Flags().BoolVar(&windows, "windows", false, "Adapt files for Windows/WSL hosts: LF scripts, named volumes for dependencies (default on Windows)")
+	upCmd.Flags().StringVar(&target, "target", "", "Environment to generate for: local (default) or codespaces (features, prebuild-friendly onCreateCommand, smaller limits)")
+	upCmd.Flags().BoolVar(&persist, "persist", false, "Keep shell history and package/build caches in named volumes across rebuilds")
+	addWorkerFlags(upCmd)
+	upCmd.Flags().DurationVar(&upTimeout, "timeout", 3*time.Minute, "How long to wait for services to become ready")
+	rootCmd.AddCommand(upCmd)
+}
+
+func runUp(cmd *cobra.Command, args []string) error {
+	absPath, err := resolveProjectPath(args)
+	if err != nil {
+		return err
+	}
+
+	projectName := filepath.Base(absPath)
+	fmt.Fprintf(out, "📂 Analyzing %s...\n", absPath)
+
+	if err := docker.Available(); err != nil {
+		return err
+	}
+
+	detection, err := detectProject(absPath)
+	if err != nil {
+		return err
+	}
+
+	// Generate files unless an existing stack should be kept
+	compose := newCompose(absPath)
+	if jsonOutput() {
+		// Keep stdout for the JSON result; docker compose progress goes to stderr
+		compose.Stdout = os.Stderr
+	}
+	if _, err := os.Stat(compose.File); err == nil && !force {
+		logger.Info("generation skipped", "reason", "existing .devcontainer/docker-compose.yml")
+		fmt.Fprintln(out, "\n📄 Using existing .devcontainer files (use --force to regenerate)")
+	} else {
+		if err := generateFiles(detection, absPath, projectName); err != nil {
+			return err
+		}
+	}
+
+	if _, err := os.Stat(compose.File); err != nil {
+		fmt.Fprintln(out)
+		warn("No docker-compose.yml for this project - it runs as a single devcontainer")
+		fmt.Fprintln(out, "   Open the folder in VS Code and run \"Dev Containers: Reopen in Container\"")
+		return nil
+	}
+
+	// Start the stack
+	fmt.Fprintf(out, "\n🚀 Starting %s...\n", compose.ProjectName)
+	start := time.Now()
+	if err := compose.Up(); err != nil {
+		return err
+	}
+	logTiming("docker compose up", start)
+
+	if err := waitForStack(compose); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "\n🔗 URLs:")
+	for _, u := range generator.ServiceURLs(detection) {
+		fmt.Fprintf(out, "   %-12s %s\n", u.Name, u.URL)
+		report.URLs = append(report.URLs, urlResult{Name: u.Name, URL: u.URL})
+	}
+	fmt.Fprintln(out, "   (the app listens once you start it inside the devcontainer)")
+
+	fmt.Fprintln(out, "\n✨ Stack is up!")
+	return nil
+}
+
+// waitForStack streams per-service status changes until every service of the
+// stack is ready, recording the containers in the result.
+func waitForStack(compose *docker.Compose) error {
+	fmt.Fprintln(out, "\n⏳ Waiting for services...")
+	start := time.Now()
+	last := make(map[string]string)
+	statuses, waitErr := compose.Wait(upTimeout, 2*time.Second, func(statuses []docker.ServiceStatus) {
+		for _, s := range statuses {
+			summary := s.Summary()
+			if last[s.Service] == summary {
+				continue
+			}
+			last[s.Service] = summary
+			logger.Info("service status", "service", s.Service, "status", summary)
+			fmt.Fprintf(out, "   %s %s: %s\n", statusIcon(s), s.Service, summary)
+		}
+	})
+	logTiming("waiting for services", start)
+
+	ready := 0
+	for _, s := range statuses {
+		if s.IsReady() {
+			ready++
+		}
+		recordContainer(s)
+	}
+	fmt.Fprintf(out, "\n📊 %d/%d services ready\n", ready, len(statuses))
+
+	if waitErr != nil {
+		fmt.Fprintf(out, "   ⚠️  %v\n", waitErr)
+		fmt.Fprintf(out, "   Inspect logs with: docker compose -f %s -p %s logs\n", compose.File, compose.ProjectName)
+		return newExitError(ExitValidation, "unhealthy", waitErr)
+	}
+	return nil
+}
+
+// recordContainer adds a container's state to the result.
+func recordContainer(s docker.ServiceStatus) {
+	report.Containers = append(report.Containers, containerResult{
+		Service: s.Service,
+		State:   s.State,
+		Health:  s.Health,
+		Ready:   s.IsReady(),
+		Ports:   s.Ports(),
+	})
+}
+
+// statusIcon returns the emoji used for a service status line.
+func statusIcon(s docker.ServiceStatus) string {
+	switch {
+	case s.IsFailed():
+		return "❌"
+	case s.IsReady():
+		return "✅"
+	default:
+		return "⏳"
+	}
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/jpequegn/dockstart/internal/selfupdate"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// upgradeCheck only reports whether a newer release exists
+	upgradeCheck bool
+
+	// upgradeForce installs the latest release even if it isn't newer
+	upgradeForce bool
+)
+
+// updateCheckEnv opts out of the background "new version available" notice.
+const updateCheckEnv = "DOCKSTART_NO_UPDATE_CHECK"
+
+// updateNoticeWait is how long a finished command waits for the background
+// check before exiting without a notice.
+const updateNoticeWait = 500 * time.Millisecond
+
+// updateNotice receives the latest release version from the background check,
+// or is nil when no check is running.
+var updateNotice chan string
+
+// upgradeCmd replaces the dockstart binary with the latest release.
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade dockstart to the latest release",
+	Long: `upgrade checks the latest GitHub release of dockstart and, if it is newer,
+downloads the binary for this platform, verifies it against the release
+checksums (and their signature, for release builds), and replaces the running
+binary in place.
+
+Other commands print a notice when a newer release is available, checking
+GitHub at most once a day. Set ` + updateCheckEnv + `=1 to turn the notice off.`,
+	Args: cobra.NoArgs,
+	RunE: runUpgrade,
+}
+
+func init() {
+	upgradeCmd.Flags().BoolVar(&upgradeCheck, "check", false, "Only report whether a newer release is available")
+	upgradeCmd.Flags().BoolVar(&upgradeForce, "force", false, "Install the latest release even if this build is as new or is a development build")
+	rootCmd.AddCommand(upgradeCmd)
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	fmt.Fprintln(out, "🔍 Checking the latest dockstart release...")
+	client := selfupdate.NewClient()
+	release, err := client.Latest()
+	if err != nil {
+		return err
+	}
+	report.Upgrade = &upgradeResult{Current: Version, Latest: release.Version}
+
+	newer := selfupdate.Newer(Version, release.Version)
+	switch {
+	case newer:
+		fmt.Fprintf(out, "   ⬆️  %s is available (you have %s): %s\n", release.Version, Version, release.URL)
+	case Version == "dev":
+		fmt.Fprintf(out, "   ℹ️  This is a development build; the latest release is %s\n", release.Version)
+	default:
+		fmt.Fprintf(out, "   ✅ dockstart %s is up to date\n", Version)
+	}
+	if upgradeCheck || (!newer && !upgradeForce) {
+		if !newer && Version == "dev" && !upgradeCheck {
+			fmt.Fprintln(out, "   Pass --force to replace it with the release")
+		}
+		return nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the dockstart binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	fmt.Fprintf(out, "\n📥 Downloading %s...\n", selfupdate.BinaryName(runtime.GOOS, runtime.GOARCH))
+	binary, err := client.Download(release)
+	if err != nil {
+		return err
+	}
+	if selfupdate.PublicKey == "" {
+		warn("Checksum verified; this build has no release key, so the signature was not checked")
+	} else {
+		fmt.Fprintln(out, "   🔏 Checksum and signature verified")
+	}
+
+	if err := selfupdate.Replace(executable, binary); err != nil {
+		return fmt.Errorf("%w (reinstall manually from %s)", err, release.URL)
+	}
+	report.Upgrade.Upgraded = true
+	fmt.Fprintf(out, "\n✅ Upgraded %s to %s\n", executable, release.Version)
+	return nil
+}
+
+// startUpdateCheck looks for a newer release in the background while the
+// command runs. It is skipped for development builds, JSON output, CI, the
+// upgrade command itself, git hooks, and when DOCKSTART_NO_UPDATE_CHECK is set.
+func startUpdateCheck(cmd *cobra.Command) {
+	if Version == "dev" || jsonOutput() || cmd == upgradeCmd || driftHook ||
+		os.Getenv(updateCheckEnv) != "" || os.Getenv("CI") != "" {
+		return
+	}
+	checker, err := selfupdate.NewChecker()
+	if err != nil {
+		return
+	}
+
+	updateNotice = make(chan string, 1)
+	go func() {
+		// The notice is best-effort; failed checks are silent
+		if latest, err := checker.Latest(); err == nil {
+			updateNotice <- latest
+		}
+	}()
+}
+
+// printUpdateNotice prints the "new version available" notice on stderr when
+// the background check found a newer release in time.
+func printUpdateNotice() {
+	if updateNotice == nil {
+		return
+	}
+	select {
+	case latest := <-updateNotice:
+		if selfupdate.Newer(Version, latest) {
+			fmt.Fprintf(os.Stderr, "\n💡 dockstart %s is available (you have %s): run `dockstart upgrade`\n", latest, Version)
+		}
+	case <-time.After(updateNoticeWait):
+	}
+}
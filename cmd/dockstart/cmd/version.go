@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// versionShort prints only the version number
+var versionShort bool
+
+// versionCmd prints the version and build metadata of this dockstart binary.
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show the dockstart version and build metadata",
+	Long: `version prints the dockstart version with the git commit and date it was
+built from, the Go version, the platform, and the version of the pinned sidecar
+image catalog. The same metadata is recorded in .devcontainer/dockstart-report.json,
+so generated files can be traced back to the build that wrote them.
+
+--short prints only the version, as does dockstart --version.`,
+	Args: cobra.NoArgs,
+	RunE: runVersion,
+}
+
+func init() {
+	versionCmd.Flags().BoolVar(&versionShort, "short", false, "Print only the version number")
+	rootCmd.AddCommand(versionCmd)
+
+	// dockstart --version prints "dockstart version <version>", the line
+	// Homebrew and Scoop test their installs against
+	rootCmd.Version = Version
+	rootCmd.SetVersionTemplate("dockstart version {{.Version}}\n")
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	info := buildInfo()
+	report.Build = &info
+
+	if versionShort {
+		fmt.Fprintln(out, info.Version)
+		return nil
+	}
+	fmt.Fprintf(out, "dockstart %s\n", info.Version)
+	fmt.Fprintf(out, "  commit:        %s\n", valueOr(info.Commit, "unknown"))
+	fmt.Fprintf(out, "  built:         %s\n", valueOr(info.Date, "unknown"))
+	fmt.Fprintf(out, "  go:            %s\n", info.GoVersion)
+	fmt.Fprintf(out, "  platform:      %s\n", info.Platform)
+	fmt.Fprintf(out, "  image catalog: %s\n", info.ImageCatalog)
+	return nil
+}
+
+// buildInfo describes this binary. Version, Commit, and BuildDate come from
+// -ldflags in release builds; go install builds fall back to the module version
+// and the VCS stamp Go embeds.
+func buildInfo() generator.BuildInfo {
+	info := generator.BuildInfo{
+		Version:      Version,
+		Commit:       Commit,
+		Date:         BuildDate,
+		GoVersion:    runtime.Version(),
+		Platform:     runtime.GOOS + "/" + runtime.GOARCH,
+		ImageCatalog: generator.CatalogVersion(),
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if info.Version == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	var modified bool
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.Date == "" {
+				info.Date = setting.Value
+			}
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if modified && Commit == "" && info.Commit != "" {
+		info.Commit += "-dirty"
+	}
+	return info
+}
+
+// valueOr returns value, or fallback when it is empty.
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,894 @@
+// Package config loads the optional .dockstart.yml project configuration.
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // validates timezones on hosts without a zoneinfo database
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the project configuration file.
+const FileName = ".dockstart.yml"
+
+// Config is the user configuration read from .dockstart.yml.
+// Every field is optional; zero values keep the detected defaults.
+type Config struct {
+	// Language forces the primary language ("node", "go", "python", "rust")
+	// when a repository contains manifests for several languages
+	Language string `yaml:"language"`
+
+	// Lockfiles enables reading lockfiles (package-lock.json, yarn.lock, go.sum,
+	// poetry.lock, Cargo.lock) to detect transitive libraries and pin versions.
+	// Off by default because lockfiles can be large.
+	Lockfiles bool `yaml:"lockfiles"`
+
+	// Versions pins backing service versions (e.g., postgres: "15"),
+	// overriding versions inferred from existing compose files
+	Versions map[string]string `yaml:"versions"`
+
+	// Worker configures the background worker sidecar
+	Worker Worker `yaml:"worker"`
+
+	// FileProcessor configures the file processor sidecar's pipeline
+	FileProcessor FileProcessor `yaml:"file_processor"`
+
+	// NonRoot runs the generated containers as a non-root user matching the
+	// host UID/GID, so files written to bind mounts keep the host owner
+	NonRoot bool `yaml:"non_root"`
+
+	// Hardened gives generated services read-only root filesystems,
+	// no-new-privileges, and dropped capabilities
+	Hardened bool `yaml:"hardened"`
+
+	// Windows adapts the generated files to Windows and WSL hosts; it is on
+	// by default when dockstart runs on Windows
+	Windows bool `yaml:"windows"`
+
+	// RemoteDocker syncs the workspace into a named volume instead of
+	// bind-mounting it, for a Docker engine on another machine; it is on by
+	// default when DOCKER_HOST or the docker context points at a remote host
+	RemoteDocker bool `yaml:"remote_docker"`
+
+	// Target is the environment the files are generated for: "local" (the
+	// default) or "codespaces"
+	Target string `yaml:"target"`
+
+	// Prebuild installs the dependencies in their own Dockerfile layer and
+	// caches builds from an image CI prebuilds
+	Prebuild Prebuild `yaml:"prebuild"`
+
+	// ComposeVersion is the compose implementation docker-compose.yml is
+	// generated for: "v2" (the default), "v1", or "swarm"
+	ComposeVersion string `yaml:"compose_version"`
+
+	// Environments are the environments docker-compose.<env>.yml overrides
+	// are generated for: "dev", "test", and "staging"
+	Environments []string `yaml:"environments"`
+
+	// Forward are the host credentials mounted into the dev container: "ssh"
+	// (the SSH agent), "gitconfig", and "gpg" (the gpg-agent, for signing)
+	Forward []string `yaml:"forward"`
+
+	// Minimal generates only the app and its databases, leaving out the optional
+	// sidecars (logging, metrics, tracing, backups, file processing) even when
+	// their libraries are detected
+	Minimal bool `yaml:"minimal"`
+
+	// Lifecycle overrides the devcontainer.json lifecycle commands
+	Lifecycle Lifecycle `yaml:"lifecycle"`
+
+	// Persistence keeps shell history and caches across rebuilds and sets up dotfiles
+	Persistence Persistence `yaml:"persistence"`
+
+	// Registries points the package managers at private registries
+	Registries Registries `yaml:"registries"`
+
+	// Proxy is the corporate HTTP proxy the containers and image builds use
+	Proxy Proxy `yaml:"proxy"`
+
+	// Timezone is the IANA timezone of the app, worker, and scheduled
+	// containers, instead of UTC (e.g., "Europe/Paris")
+	Timezone string `yaml:"timezone"`
+
+	// Locale is the dev container's locale, generated in its image
+	// (e.g., "fr_FR.UTF-8")
+	Locale string `yaml:"locale"`
+
+	// Testing keeps test suites away from development data and sets the test commands
+	Testing Testing `yaml:"testing"`
+
+	// Postgres configures the generated PostgreSQL service
+	Postgres Postgres `yaml:"postgres"`
+
+	// Resources overrides the memory and CPU limits of generated services,
+	// by service name (e.g., postgres: {memory: 1g})
+	Resources map[string]Resource `yaml:"resources"`
+
+	// Logging sets how much log output the generated services keep
+	Logging Logging `yaml:"logging"`
+
+	// StatusPage configures the Gatus status page (same as --gatus when enabled)
+	StatusPage StatusPage `yaml:"status_page"`
+}
+
+// Prebuild holds the prebuilt dev container image settings.
+type Prebuild struct {
+	// Enabled turns on prebuild mode (same as --prebuild)
+	Enabled bool `yaml:"enabled"`
+
+	// Image is the image CI pushes and builds cache from (e.g.,
+	// "ghcr.io/acme/shop-devcontainer"); it defaults to a local
+	// <project>-devcontainer image
+	Image string `yaml:"image"`
+}
+
+// StatusPage holds the Gatus status page settings.
+type StatusPage struct {
+	// Enabled adds the status page
+	Enabled bool `yaml:"enabled"`
+
+	// HealthPath is the app's health endpoint, checked for a 200 response
+	// (e.g., "/health"). Without it, any response short of a 5xx counts as up
+	HealthPath string `yaml:"health_path"`
+}
+
+// Logging holds the log rotation settings. Zero values keep the defaults
+// (10m files, 3 per service).
+type Logging struct {
+	// MaxSize is the size a log file is rotated at (e.g., "10m", "1g")
+	MaxSize string `yaml:"max_size"`
+
+	// MaxFiles is the number of log files kept per service, the current one included
+	MaxFiles int `yaml:"max_files"`
+
+	// FileOutput writes the logs collected by the log sidecar to per-service
+	// files in the fluent-bit-logs volume
+	FileOutput bool `yaml:"file_output"`
+}
+
+// Resource holds the limits of a generated service. Empty values keep the defaults.
+type Resource struct {
+	// Memory is the memory limit (e.g., "512m", "1g")
+	Memory string `yaml:"memory"`
+
+	// CPUs is the CPU limit (e.g., "0.5", "2")
+	CPUs string `yaml:"cpus"`
+}
+
+// ownLimits are the services sized by their own settings instead of resources.
+var ownLimits = map[string]string{
+	"worker":         "set its limits with worker.memory and worker.cpus",
+	"memcached":      "its limit is sized from the cache",
+	"file-processor": "its limits are sized from the enabled processors",
+}
+
+// Postgres holds the PostgreSQL service settings.
+type Postgres struct {
+	// TimescaleDB runs the TimescaleDB image, for projects whose migrations
+	// don't reveal that they use it
+	TimescaleDB bool `yaml:"timescaledb"`
+
+	// Databases are created next to the development database, each with its
+	// own <NAME>_DATABASE_URL (e.g., ["analytics", "audit"])
+	Databases []string `yaml:"databases"`
+
+	// Extensions are created in every database on first start (e.g.,
+	// ["uuid-ossp", "pg_trgm"]); "vector" and "postgis" switch to the
+	// pgvector and PostGIS images, which ship them
+	Extensions []string `yaml:"extensions"`
+
+	// Roles are created next to the default postgres user on first start
+	Roles []PostgresRole `yaml:"roles"`
+
+	// ProdImport generates a script importing an anonymized copy of the
+	// production database into the development one
+	ProdImport ProdImport `yaml:"prod_import"`
+}
+
+// ProdImport holds the opt-in production data import settings.
+type ProdImport struct {
+	// Enabled generates .devcontainer/scripts/import-prod.sh and the
+	// prod-import service that runs it
+	Enabled bool `yaml:"enabled"`
+
+	// SourceEnv is the host environment variable holding the production
+	// database URL; it defaults to PROD_DATABASE_URL
+	SourceEnv string `yaml:"source_env"`
+
+	// Anonymize maps the columns holding personal data, as "table.column" or
+	// "schema.table.column", to how they are rewritten (e.g.,
+	// users.email: email). See AnonymizeStrategies
+	Anonymize map[string]string `yaml:"anonymize"`
+
+	// ExcludeData are tables copied without their rows, as pg_dump
+	// --exclude-table-data patterns (e.g., ["audit_log", "sessions"])
+	ExcludeData []string `yaml:"exclude_data"`
+}
+
+// AnonymizeStrategies are the ways an anonymized column can be rewritten.
+var AnonymizeStrategies = []string{"email", "name", "phone", "redact", "hash", "clear"}
+
+// PostgresRole is an extra PostgreSQL login role, such as the one the app
+// connects as in production or a read-only reporting user.
+type PostgresRole struct {
+	// Name is the role name (e.g., "reporting")
+	Name string `yaml:"name"`
+
+	// Password is the role's development password; without one, a random
+	// password is generated in .devcontainer/.env
+	Password string `yaml:"password"`
+
+	// Superuser makes the role a superuser
+	Superuser bool `yaml:"superuser"`
+
+	// ReadOnly grants only SELECT on the tables, instead of every privilege
+	ReadOnly bool `yaml:"read_only"`
+}
+
+// Testing holds the test database and test runner settings.
+type Testing struct {
+	// Isolation is "service" (separate postgres-test and redis-test services)
+	// or "database" (a <project>_test database in the development PostgreSQL)
+	Isolation string `yaml:"isolation"`
+
+	// Command replaces the detected test command run by the test service
+	// (e.g., "npm run test:ci")
+	Command string `yaml:"command"`
+
+	// Coverage replaces the detected coverage command run by `dockstart test --coverage`
+	Coverage string `yaml:"coverage"`
+}
+
+// targets are the valid generation targets.
+var targets = []string{"local", "codespaces"}
+
+// composeVersions are the valid compose versions.
+var composeVersions = []string{"v2", "v1", "swarm"}
+
+// environments are the valid compose override environments.
+var environments = []string{"dev", "test", "staging"}
+
+// forwards are the valid host credential forwards.
+var forwards = []string{"ssh", "gitconfig", "gpg"}
+
+// isolationModes are the valid test database isolation modes.
+var isolationModes = []string{"service", "database"}
+
+// Persistence holds the opt-in settings for what survives a dev container rebuild.
+type Persistence struct {
+	// History keeps the shell history in a named volume
+	History bool `yaml:"history"`
+
+	// Caches keeps package manager and build caches in named volumes
+	Caches bool `yaml:"caches"`
+
+	// Dotfiles is a dotfiles repository to clone, as a git URL or a GitHub
+	// "owner/repo" shorthand
+	Dotfiles string `yaml:"dotfiles"`
+
+	// DotfilesInstall runs in the cloned repository instead of its install
+	// script (e.g., "make install"). Requires dotfiles
+	DotfilesInstall string `yaml:"dotfiles_install"`
+}
+
+// Registries holds the private package registries the dev container's
+// package managers use. Credentials don't belong here: they are read from the
+// host environment when the container starts.
+type Registries struct {
+	// NPM is the npm registry URL (e.g., "https://npm.acme.dev/")
+	NPM string `yaml:"npm"`
+
+	// PyPI is the pip index URL (e.g., "https://pypi.acme.dev/simple")
+	PyPI string `yaml:"pypi"`
+
+	// GoPrivate are the module path patterns fetched directly instead of
+	// through the proxy and checksum database (e.g., "github.com/acme/*")
+	GoPrivate []string `yaml:"go_private"`
+
+	// GoProxy is the Go module proxy URL (e.g., "https://goproxy.acme.dev")
+	GoProxy string `yaml:"go_proxy"`
+
+	// Cargo are alternate Cargo registries, by name, as index URLs
+	// (e.g., acme: "sparse+https://cargo.acme.dev/index/")
+	Cargo map[string]string `yaml:"cargo"`
+}
+
+// cargoRegistryRe matches Cargo registry names.
+var cargoRegistryRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Validate checks the registry URLs, which must not embed credentials.
+func (r Registries) Validate() error {
+	urls := map[string]string{"npm": r.NPM, "pypi": r.PyPI, "go_proxy": r.GoProxy}
+	for name, index := range r.Cargo {
+		if !cargoRegistryRe.MatchString(name) {
+			return fmt.Errorf("invalid cargo registry name %q: expected lowercase letters, digits, - and _", name)
+		}
+		urls["cargo."+name] = strings.TrimPrefix(index, "sparse+")
+	}
+	for _, key := range slices.Sorted(maps.Keys(urls)) {
+		value := urls[key]
+		if value == "" {
+			continue
+		}
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("invalid %s URL %q: expected an http(s) URL", key, value)
+		}
+		if u.User != nil {
+			return fmt.Errorf("%s URL %q embeds credentials: set them in the host environment instead", key, value)
+		}
+	}
+	for _, pattern := range r.GoPrivate {
+		if pattern == "" || strings.ContainsAny(pattern, ", \t\n\"'$`") {
+			return fmt.Errorf("invalid go_private pattern %q: expected a module path pattern like \"github.com/acme/*\"", pattern)
+		}
+	}
+	return nil
+}
+
+// Proxy holds the corporate HTTP proxy settings. The compose services reach
+// each other directly: their names are added to no_proxy.
+type Proxy struct {
+	// HTTP is the proxy URL for HTTP requests (e.g., "http://proxy.acme.dev:3128")
+	HTTP string `yaml:"http"`
+
+	// HTTPS is the proxy URL for HTTPS requests; it defaults to HTTP
+	HTTPS string `yaml:"https"`
+
+	// NoProxy are extra hosts, domains, and networks reached directly
+	// (e.g., ".acme.dev", "10.0.0.0/8")
+	NoProxy []string `yaml:"no_proxy"`
+}
+
+// Validate checks the proxy URLs, which must not embed credentials.
+func (p Proxy) Validate() error {
+	for _, value := range []struct{ key, url string }{{"http", p.HTTP}, {"https", p.HTTPS}} {
+		if value.url == "" {
+			continue
+		}
+		u, err := url.Parse(value.url)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") || u.Host == "" {
+			return fmt.Errorf("invalid %s proxy %q: expected a URL like \"http://proxy.example.com:3128\"", value.key, value.url)
+		}
+		if u.User != nil {
+			return fmt.Errorf("%s proxy %q embeds credentials: set HTTP_PROXY and HTTPS_PROXY in the host environment instead", value.key, value.url)
+		}
+	}
+	for _, host := range p.NoProxy {
+		if host == "" || strings.ContainsAny(host, ", \t\n\"'$`") {
+			return fmt.Errorf("invalid no_proxy entry %q: expected a host, domain, or network", host)
+		}
+	}
+	if len(p.NoProxy) > 0 && p.HTTP == "" && p.HTTPS == "" {
+		return fmt.Errorf("no_proxy requires http or https")
+	}
+	return nil
+}
+
+// Lifecycle holds devcontainer.json lifecycle command overrides. Empty values
+// keep the commands generated from the detected package manager and migration tool.
+type Lifecycle struct {
+	// Install replaces the detected dependency install command (e.g., "npm ci")
+	Install string `yaml:"install"`
+
+	// Migrate replaces the detected migration command (e.g., "npm run db:migrate")
+	Migrate string `yaml:"migrate"`
+
+	// PostCreate replaces the whole postCreateCommand
+	PostCreate string `yaml:"post_create"`
+
+	// PostStart replaces the whole postStartCommand
+	PostStart string `yaml:"post_start"`
+}
+
+// Worker holds worker sidecar scaling settings. Zero values keep the defaults.
+type Worker struct {
+	// Concurrency is the number of jobs each worker processes at once
+	Concurrency int `yaml:"concurrency"`
+
+	// Replicas is the number of worker containers to run
+	Replicas int `yaml:"replicas"`
+
+	// Memory is the memory limit per worker (e.g., "512m", "1g")
+	Memory string `yaml:"memory"`
+
+	// CPUs is the CPU limit per worker (e.g., "0.5", "2")
+	CPUs string `yaml:"cpus"`
+
+	// StopGracePeriod is how long a worker gets to finish jobs on shutdown (e.g., "30s")
+	StopGracePeriod string `yaml:"stop_grace_period"`
+
+	// Target builds the worker from its own "worker" stage of the generated
+	// Dockerfile, next to an "app" stage
+	Target bool `yaml:"target"`
+
+	// Command overrides the detected worker command
+	Command string `yaml:"command"`
+
+	// Env sets variables in the worker stage, such as build flags
+	// (e.g., GOFLAGS: "-tags=worker"). Requires Target.
+	Env map[string]string `yaml:"env"`
+
+	// DeadLetter scaffolds a retry policy and dead-letter queue for failed jobs
+	DeadLetter DeadLetter `yaml:"dead_letter"`
+}
+
+// DeadLetter holds retry and dead-letter queue settings. Off unless Enabled.
+type DeadLetter struct {
+	// Enabled generates the dead-letter consumer (or RabbitMQ exchanges) and retry env vars
+	Enabled bool `yaml:"enabled"`
+
+	// MaxRetries is how many times a job is retried before it is dead-lettered (default 3)
+	MaxRetries int `yaml:"max_retries"`
+
+	// Backoff is the delay before a failed job is retried (default "5s"); libraries
+	// with exponential backoff use it as the base delay
+	Backoff string `yaml:"backoff"`
+
+	// Command starts the dead-letter consumer (default: the worker command)
+	Command string `yaml:"command"`
+}
+
+// FileProcessor holds file processor pipeline settings. Zero values keep the
+// defaults (images only, one 200x200 thumbnail, any type up to 50MB, polling).
+type FileProcessor struct {
+	// Types are the kinds of files processed: "images", "documents", "video"
+	Types []string `yaml:"types"`
+
+	// ThumbnailSizes are the image thumbnail sizes (e.g., "200x200", "800x600")
+	ThumbnailSizes []string `yaml:"thumbnail_sizes"`
+
+	// AllowedMIMETypes restricts accepted uploads (e.g., "image/png", "image/*");
+	// other files are moved to failed/. Empty accepts any type
+	AllowedMIMETypes []string `yaml:"allowed_mime_types"`
+
+	// MaxFileSize is the largest file processed (e.g., "50m", "1g")
+	MaxFileSize string `yaml:"max_file_size"`
+
+	// Watch is how new files are found: "poll" or "inotify"
+	Watch string `yaml:"watch"`
+
+	// PollInterval is the polling interval in seconds
+	PollInterval int `yaml:"poll_interval"`
+
+	// Scan runs each upload through ClamAV before processing; infected files
+	// are moved to quarantine/
+	Scan bool `yaml:"scan"`
+
+	// SanitizeImages strips EXIF/GPS metadata, normalizes orientation, and
+	// converts images other than jpg, png, and webp to PNG
+	SanitizeImages bool `yaml:"sanitize_images"`
+
+	// Notify is how the app learns a file was processed: "file" (a .done file
+	// next to the output), "webhook", or "queue" (a Redis list)
+	Notify string `yaml:"notify"`
+
+	// NotifyURL is the webhook the processor POSTs to (default:
+	// http://app:<port>/webhooks/file-processed). Requires notify: webhook
+	NotifyURL string `yaml:"notify_url"`
+
+	// NotifyQueue is the Redis list jobs are pushed onto (default:
+	// "file-processed"). Requires notify: queue
+	NotifyQueue string `yaml:"notify_queue"`
+
+	// Storage is where the app and processor exchange uploads: "volume" (a
+	// shared uploads volume) or "s3" (a bucket). Defaults to "s3" when an S3
+	// SDK is detected
+	Storage string `yaml:"storage"`
+}
+
+// processorTypes are the valid file processor types.
+var processorTypes = []string{"images", "documents", "video"}
+
+// notifyMethods are the valid file processor notification methods.
+var notifyMethods = []string{"file", "webhook", "queue"}
+
+// thumbnailSizeRe matches a thumbnail size such as "200x200".
+var thumbnailSizeRe = regexp.MustCompile(`^[1-9]\d*x[1-9]\d*$`)
+
+// mimeTypeRe matches a MIME type or wildcard such as "image/png" or "image/*".
+var mimeTypeRe = regexp.MustCompile(`^[a-z0-9][a-z0-9.+-]*/([a-z0-9][a-z0-9.+-]*|\*)$`)
+
+// versionRe matches a numeric version such as "15", "7.2", or "15.4".
+var versionRe = regexp.MustCompile(`^\d+(\.\d+)*$`)
+
+// memoryRe matches a compose memory size such as "512m", "1g", or "1.5gb".
+var memoryRe = regexp.MustCompile(`(?i)^\d+(\.\d+)?([bkmg]b?)?$`)
+
+// envNameRe matches an environment variable name.
+var envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// githubRepoRe matches a GitHub "owner/repo" shorthand.
+var githubRepoRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+// databaseNameRe matches a PostgreSQL database name that is also usable in an
+// environment variable name once upper-cased.
+var databaseNameRe = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// imageRe matches an image reference without a digest, such as
+// "ghcr.io/acme/app-devcontainer" or "registry.acme.dev:5000/app:main".
+var imageRe = regexp.MustCompile(`^[a-z0-9][a-z0-9._/-]*(:[0-9]+/[a-z0-9._/-]+)?(:[A-Za-z0-9_][A-Za-z0-9_.-]*)?$`)
+
+// tablePatternRe matches a pg_dump table pattern such as "public.audit_*".
+var tablePatternRe = regexp.MustCompile(`^[a-z_*][a-z0-9_.*]*$`)
+
+// extensionNameRe matches a PostgreSQL extension name such as "uuid-ossp".
+var extensionNameRe = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// cpusRe matches a compose CPU count such as "0.5" or "2".
+var cpusRe = regexp.MustCompile(`^(\d+(\.\d*)?|\.\d+)$`)
+
+// localeRe matches a UTF-8 locale the image can generate, such as
+// "fr_FR.UTF-8", "sr_RS.UTF-8@latin", or the built-in "C.UTF-8".
+var localeRe = regexp.MustCompile(`^([a-z]{2,3}_[A-Z]{2}|C)\.UTF-8(@[a-z]+)?$`)
+
+// Load reads .dockstart.yml from the project directory.
+// Returns an empty Config if the file does not exist.
+func Load(projectPath string) (*Config, error) {
+	path := filepath.Join(projectPath, FileName)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Config{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", FileName, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", FileName, err)
+	}
+
+	if cfg.Target != "" && !containsString(targets, cfg.Target) {
+		return nil, fmt.Errorf("invalid target %q in %s: expected one of %s", cfg.Target, FileName, strings.Join(targets, ", "))
+	}
+	if image := cfg.Prebuild.Image; image != "" && !imageRe.MatchString(image) {
+		return nil, fmt.Errorf("invalid prebuild.image %q in %s: expected an image like \"ghcr.io/acme/app-devcontainer\"", image, FileName)
+	}
+	if cfg.ComposeVersion != "" && !containsString(composeVersions, cfg.ComposeVersion) {
+		return nil, fmt.Errorf("invalid compose_version %q in %s: expected one of %s", cfg.ComposeVersion, FileName, strings.Join(composeVersions, ", "))
+	}
+	for _, env := range cfg.Environments {
+		if !containsString(environments, env) {
+			return nil, fmt.Errorf("invalid environment %q in %s: expected one of %s", env, FileName, strings.Join(environments, ", "))
+		}
+	}
+	for _, forward := range cfg.Forward {
+		if !containsString(forwards, forward) {
+			return nil, fmt.Errorf("invalid forward %q in %s: expected one of %s", forward, FileName, strings.Join(forwards, ", "))
+		}
+	}
+
+	for service, version := range cfg.Versions {
+		if !versionRe.MatchString(version) {
+			return nil, fmt.Errorf("invalid version %q for %s in %s: expected a number like \"15\" or \"7.2\"", version, service, FileName)
+		}
+	}
+
+	if err := cfg.Worker.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid worker settings in %s: %w", FileName, err)
+	}
+
+	if err := cfg.FileProcessor.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid file_processor settings in %s: %w", FileName, err)
+	}
+
+	if err := cfg.Persistence.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid persistence settings in %s: %w", FileName, err)
+	}
+
+	if err := cfg.Proxy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid proxy in %s: %w", FileName, err)
+	}
+
+	if cfg.Timezone != "" {
+		if _, err := time.LoadLocation(cfg.Timezone); err != nil || cfg.Timezone == "Local" {
+			return nil, fmt.Errorf("invalid timezone %q in %s: expected an IANA timezone like \"Europe/Paris\"", cfg.Timezone, FileName)
+		}
+	}
+
+	if cfg.Locale != "" && !localeRe.MatchString(cfg.Locale) {
+		return nil, fmt.Errorf("invalid locale %q in %s: expected a UTF-8 locale like \"fr_FR.UTF-8\"", cfg.Locale, FileName)
+	}
+
+	if err := cfg.Registries.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid registries in %s: %w", FileName, err)
+	}
+
+	if err := cfg.Testing.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid testing settings in %s: %w", FileName, err)
+	}
+
+	if err := cfg.Postgres.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid postgres settings in %s: %w", FileName, err)
+	}
+
+	if path := cfg.StatusPage.HealthPath; path != "" && !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("invalid status_page.health_path %q in %s: expected a path like \"/health\"", path, FileName)
+	}
+
+	if err := cfg.Logging.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid logging settings in %s: %w", FileName, err)
+	}
+
+	for service, resource := range cfg.Resources {
+		if reason, ok := ownLimits[service]; ok {
+			return nil, fmt.Errorf("invalid resources in %s: %s can't be set here, %s", FileName, service, reason)
+		}
+		if err := resource.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid resources for %s in %s: %w", service, FileName, err)
+		}
+	}
+
+	return &cfg, nil
+}
+
+// Validate checks that the test database isolation mode is known.
+func (t Testing) Validate() error {
+	if t.Isolation != "" && !containsString(isolationModes, t.Isolation) {
+		return fmt.Errorf("invalid isolation %q: expected one of %s", t.Isolation, strings.Join(isolationModes, ", "))
+	}
+	return nil
+}
+
+// Validate checks that the additional databases, extensions, and roles have
+// usable, distinct names, and that the extensions can share an image.
+func (p Postgres) Validate() error {
+	for i, name := range p.Databases {
+		if len(name) > 63 || !databaseNameRe.MatchString(name) {
+			return fmt.Errorf("invalid database %q: expected lowercase letters, digits, and underscores, at most 63 characters", name)
+		}
+		if containsString(p.Databases[:i], name) {
+			return fmt.Errorf("database %q is listed twice", name)
+		}
+	}
+
+	for i, name := range p.Extensions {
+		if !extensionNameRe.MatchString(name) {
+			return fmt.Errorf("invalid extension %q: expected an extension name like \"uuid-ossp\"", name)
+		}
+		if containsString(p.Extensions[:i], name) {
+			return fmt.Errorf("extension %q is listed twice", name)
+		}
+	}
+	if containsString(p.Extensions, "postgis") && (containsString(p.Extensions, "vector") || p.TimescaleDB) {
+		return fmt.Errorf("postgis can't be combined with vector or timescaledb: each runs its own PostgreSQL image")
+	}
+
+	for i, role := range p.Roles {
+		if len(role.Name) > 63 || !databaseNameRe.MatchString(role.Name) {
+			return fmt.Errorf("invalid role %q: expected lowercase letters, digits, and underscores, at most 63 characters", role.Name)
+		}
+		if role.Name == "postgres" || role.Name == "public" || strings.HasPrefix(role.Name, "pg_") {
+			return fmt.Errorf("role %q is reserved by PostgreSQL", role.Name)
+		}
+		if slices.ContainsFunc(p.Roles[:i], func(r PostgresRole) bool { return r.Name == role.Name }) {
+			return fmt.Errorf("role %q is listed twice", role.Name)
+		}
+		if strings.ContainsAny(role.Password, "'\\\n") {
+			return fmt.Errorf("invalid password for role %s: quotes, backslashes, and newlines aren't supported", role.Name)
+		}
+		if role.Superuser && role.ReadOnly {
+			return fmt.Errorf("role %s can't be both superuser and read_only", role.Name)
+		}
+	}
+
+	if err := p.ProdImport.Validate(); err != nil {
+		return fmt.Errorf("prod_import: %w", err)
+	}
+	return nil
+}
+
+// Validate checks that the source variable, anonymized columns, and excluded
+// tables can be written into the import script.
+func (p ProdImport) Validate() error {
+	if p.SourceEnv != "" && !envNameRe.MatchString(p.SourceEnv) {
+		return fmt.Errorf("invalid source_env %q: expected an environment variable name", p.SourceEnv)
+	}
+	for column, strategy := range p.Anonymize {
+		parts := strings.Split(column, ".")
+		if len(parts) < 2 || len(parts) > 3 || slices.ContainsFunc(parts, func(part string) bool { return !databaseNameRe.MatchString(part) }) {
+			return fmt.Errorf("invalid anonymized column %q: expected \"table.column\" or \"schema.table.column\" in lowercase", column)
+		}
+		if !containsString(AnonymizeStrategies, strategy) {
+			return fmt.Errorf("invalid strategy %q for %s: expected one of %s", strategy, column, strings.Join(AnonymizeStrategies, ", "))
+		}
+	}
+	for _, table := range p.ExcludeData {
+		if !tablePatternRe.MatchString(table) {
+			return fmt.Errorf("invalid exclude_data table %q: expected a table name or pattern like \"audit_*\"", table)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the rotation settings can be rendered into docker-compose.yml.
+func (l Logging) Validate() error {
+	if _, err := l.MaxSizeBytes(); err != nil {
+		return err
+	}
+	if l.MaxFiles < 0 {
+		return fmt.Errorf("max_files must not be negative, got %d", l.MaxFiles)
+	}
+	return nil
+}
+
+// MaxSizeBytes returns MaxSize in bytes, or 0 when it is not set.
+func (l Logging) MaxSizeBytes() (int64, error) {
+	return sizeBytes("max_size", l.MaxSize)
+}
+
+// Validate checks that the limits can be rendered into docker-compose.yml.
+func (r Resource) Validate() error {
+	if r.Memory != "" && !memoryRe.MatchString(r.Memory) {
+		return fmt.Errorf("invalid memory %q: expected a size like \"512m\" or \"1g\"", r.Memory)
+	}
+	if r.CPUs != "" && (!cpusRe.MatchString(r.CPUs) || strings.Trim(r.CPUs, "0.") == "") {
+		return fmt.Errorf("invalid cpus %q: expected a positive number like \"0.5\" or \"2\"", r.CPUs)
+	}
+	return nil
+}
+
+// Validate checks that persistence settings can be rendered into the dotfiles script.
+func (p Persistence) Validate() error {
+	if p.Dotfiles != "" && (strings.ContainsAny(p.Dotfiles, " \t\n\"'$`") ||
+		!strings.Contains(p.Dotfiles, "://") && !strings.HasPrefix(p.Dotfiles, "git@") && !githubRepoRe.MatchString(p.Dotfiles)) {
+		return fmt.Errorf("invalid dotfiles %q: expected a git URL or a GitHub \"owner/repo\"", p.Dotfiles)
+	}
+	if p.DotfilesInstall != "" && p.Dotfiles == "" {
+		return fmt.Errorf("dotfiles_install requires dotfiles")
+	}
+	return nil
+}
+
+// DotfilesURL returns the git URL of the dotfiles repository, expanding the
+// GitHub "owner/repo" shorthand. Empty when no repository is configured.
+func (p Persistence) DotfilesURL() string {
+	if githubRepoRe.MatchString(p.Dotfiles) {
+		return "https://github.com/" + p.Dotfiles + ".git"
+	}
+	return p.Dotfiles
+}
+
+// Validate checks that worker settings can be rendered into docker-compose.yml.
+func (w Worker) Validate() error {
+	if w.Concurrency < 0 {
+		return fmt.Errorf("concurrency must not be negative, got %d", w.Concurrency)
+	}
+	if w.Replicas < 0 {
+		return fmt.Errorf("replicas must not be negative, got %d", w.Replicas)
+	}
+	if w.Memory != "" && !memoryRe.MatchString(w.Memory) {
+		return fmt.Errorf("invalid memory %q: expected a size like \"512m\" or \"1g\"", w.Memory)
+	}
+	if w.CPUs != "" && (!cpusRe.MatchString(w.CPUs) || strings.Trim(w.CPUs, "0.") == "") {
+		return fmt.Errorf("invalid cpus %q: expected a positive number like \"0.5\" or \"2\"", w.CPUs)
+	}
+	if w.StopGracePeriod != "" {
+		if d, err := time.ParseDuration(w.StopGracePeriod); err != nil || d < 0 {
+			return fmt.Errorf("invalid stop_grace_period %q: expected a duration like \"30s\" or \"1m30s\"", w.StopGracePeriod)
+		}
+	}
+	if len(w.Env) > 0 && !w.Target {
+		return fmt.Errorf("env requires target: true (it is set in the worker's Dockerfile stage)")
+	}
+	for name := range w.Env {
+		if !envNameRe.MatchString(name) {
+			return fmt.Errorf("invalid env name %q", name)
+		}
+	}
+	if w.DeadLetter.MaxRetries < 0 {
+		return fmt.Errorf("dead_letter.max_retries must not be negative, got %d", w.DeadLetter.MaxRetries)
+	}
+	if w.DeadLetter.Backoff != "" {
+		if d, err := time.ParseDuration(w.DeadLetter.Backoff); err != nil || d < 0 {
+			return fmt.Errorf("invalid dead_letter.backoff %q: expected a duration like \"5s\" or \"1m\"", w.DeadLetter.Backoff)
+		}
+	}
+	return nil
+}
+
+// Validate checks that file processor settings can be rendered into the sidecar.
+func (p FileProcessor) Validate() error {
+	for _, t := range p.Types {
+		if !containsString(processorTypes, t) {
+			return fmt.Errorf("invalid type %q: expected one of %s", t, strings.Join(processorTypes, ", "))
+		}
+	}
+	for _, size := range p.ThumbnailSizes {
+		if !thumbnailSizeRe.MatchString(size) {
+			return fmt.Errorf("invalid thumbnail size %q: expected WIDTHxHEIGHT like \"200x200\"", size)
+		}
+	}
+	for _, mime := range p.AllowedMIMETypes {
+		if !mimeTypeRe.MatchString(mime) {
+			return fmt.Errorf("invalid MIME type %q: expected a type like \"image/png\" or \"image/*\"", mime)
+		}
+	}
+	if _, err := p.MaxFileSizeBytes(); err != nil {
+		return err
+	}
+	if p.Watch != "" && p.Watch != "poll" && p.Watch != "inotify" {
+		return fmt.Errorf("invalid watch %q: expected \"poll\" or \"inotify\"", p.Watch)
+	}
+	if p.PollInterval < 0 {
+		return fmt.Errorf("poll_interval must not be negative, got %d", p.PollInterval)
+	}
+	if p.Storage != "" && p.Storage != "volume" && p.Storage != "s3" {
+		return fmt.Errorf("invalid storage %q: expected \"volume\" or \"s3\"", p.Storage)
+	}
+	if p.Notify != "" && !containsString(notifyMethods, p.Notify) {
+		return fmt.Errorf("invalid notify %q: expected one of %s", p.Notify, strings.Join(notifyMethods, ", "))
+	}
+	if p.NotifyURL != "" {
+		if p.Notify != "webhook" {
+			return fmt.Errorf("notify_url requires notify: webhook")
+		}
+		if !strings.HasPrefix(p.NotifyURL, "http://") && !strings.HasPrefix(p.NotifyURL, "https://") {
+			return fmt.Errorf("invalid notify_url %q: expected an http:// or https:// URL", p.NotifyURL)
+		}
+	}
+	if p.NotifyQueue != "" {
+		if p.Notify != "queue" {
+			return fmt.Errorf("notify_queue requires notify: queue")
+		}
+		if strings.ContainsAny(p.NotifyQueue, " \t\n") {
+			return fmt.Errorf("invalid notify_queue %q: must not contain whitespace", p.NotifyQueue)
+		}
+	}
+	return nil
+}
+
+// MaxFileSizeBytes returns MaxFileSize in bytes, or 0 when it is not set.
+func (p FileProcessor) MaxFileSizeBytes() (int64, error) {
+	return sizeBytes("max_file_size", p.MaxFileSize)
+}
+
+// sizeBytes converts a size setting such as "50m" or "1g" to bytes, or 0 when
+// it is empty. field names the setting in errors.
+func sizeBytes(field, value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if !memoryRe.MatchString(value) {
+		return 0, fmt.Errorf("invalid %s %q: expected a size like \"50m\" or \"1g\"", field, value)
+	}
+
+	number := strings.TrimRight(strings.ToLower(value), "bkmg")
+	unit := strings.TrimSuffix(strings.ToLower(value[len(number):]), "b")
+	size, err := strconv.ParseFloat(number, 64)
+	if err != nil || size <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: expected a positive size", field, value)
+	}
+
+	switch unit {
+	case "k":
+		size *= 1 << 10
+	case "m":
+		size *= 1 << 20
+	case "g":
+		size *= 1 << 30
+	}
+	return int64(size), nil
+}
+
+// containsString checks if a string is in the list.
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestAuthDetection tests OIDC/OAuth library detection across languages.
+func TestAuthDetection(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		wantLibs []string
+	}{
+		{
+			name:     "node next-auth",
+			filename: "package.json",
+			content:  `{"name": "web", "dependencies": {"next": "^14.0.0", "next-auth": "^4.24.0"}}`,
+			wantLibs: []string{"next-auth"},
+		},
+		{
+			name:     "node passport with openid connect strategy",
+			filename: "package.json",
+			content:  `{"name": "api", "dependencies": {"express": "^4.18.0", "passport": "^0.7.0", "passport-openidconnect": "^0.1.0"}}`,
+			wantLibs: []string{"passport", "passport-openidconnect"},
+		},
+		{
+			name:     "node without auth",
+			filename: "package.json",
+			content:  `{"name": "api", "dependencies": {"express": "^4.18.0"}}`,
+			wantLibs: nil,
+		},
+		{
+			name:     "go oauth2 and go-oidc",
+			filename: "go.mod",
+			content: `module github.com/user/api
+
+go 1.22
+
+require (
+	github.com/coreos/go-oidc/v3 v3.10.0
+	golang.org/x/oauth2 v0.18.0
+)
+`,
+			wantLibs: []string{"go-oidc", "oauth2"},
+		},
+		{
+			name:     "python authlib",
+			filename: "requirements.txt",
+			content:  "fastapi\nAuthlib>=1.3\n",
+			wantLibs: []string{"authlib"},
+		},
+		{
+			name:     "rust openidconnect",
+			filename: "Cargo.toml",
+			content: `[package]
+name = "api"
+edition = "2021"
+
+[dependencies]
+axum = "0.7"
+openidconnect = "3"
+`,
+			wantLibs: []string{"openidconnect"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-auth-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			if err := os.WriteFile(filepath.Join(tmpDir, tt.filename), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.filename, err)
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+
+			if len(detection.AuthLibraries) != len(tt.wantLibs) {
+				t.Errorf("AuthLibraries = %v, want %v", detection.AuthLibraries, tt.wantLibs)
+			}
+			for _, lib := range tt.wantLibs {
+				if !detection.HasAuthLibrary(lib) {
+					t.Errorf("Expected auth library %q, got %v", lib, detection.AuthLibraries)
+				}
+			}
+
+			if detection.NeedsAuthProvider() != (len(tt.wantLibs) > 0) {
+				t.Errorf("NeedsAuthProvider() = %v, want %v", detection.NeedsAuthProvider(), len(tt.wantLibs) > 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package detector
+
+// awsServiceNames lists the AWS services dockstart can emulate with LocalStack,
+// in the order they are reported. The names match the SDK package suffixes used
+// across languages (e.g., "@aws-sdk/client-sqs", "aws-sdk-go-v2/service/sqs", "aws-sdk-sqs").
+var awsServiceNames = []string{
+	"sqs",
+	"sns",
+	"dynamodb",
+	"s3",
+	"secretsmanager",
+	"kinesis",
+	"lambda",
+}
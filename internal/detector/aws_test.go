@@ -0,0 +1,113 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestAWSServiceDetection tests AWS SDK service client detection across languages.
+func TestAWSServiceDetection(t *testing.T) {
+	tests := []struct {
+		name           string
+		filename       string
+		content        string
+		wantServices   []string
+		wantLocalStack bool
+	}{
+		{
+			name:           "node sdk v3 clients",
+			filename:       "package.json",
+			content:        `{"name": "orders", "dependencies": {"@aws-sdk/client-sqs": "^3.500.0", "@aws-sdk/client-dynamodb": "^3.500.0"}}`,
+			wantServices:   []string{"sqs", "dynamodb"},
+			wantLocalStack: true,
+		},
+		{
+			name:           "node s3 only",
+			filename:       "package.json",
+			content:        `{"name": "uploads", "dependencies": {"@aws-sdk/client-s3": "^3.500.0"}}`,
+			wantServices:   []string{"s3"},
+			wantLocalStack: false,
+		},
+		{
+			name:     "go sdk v2 service packages",
+			filename: "go.mod",
+			content: `module github.com/user/orders
+
+go 1.22
+
+require (
+	github.com/aws/aws-sdk-go-v2 v1.25.0
+	github.com/aws/aws-sdk-go-v2/service/sns v1.29.0
+	github.com/aws/aws-sdk-go-v2/service/sqs v1.31.0
+)
+`,
+			wantServices:   []string{"sqs", "sns"},
+			wantLocalStack: true,
+		},
+		{
+			name:           "python boto3 stubs",
+			filename:       "requirements.txt",
+			content:        "boto3\nmypy-boto3-dynamodb\ntypes-boto3-sqs\n",
+			wantServices:   []string{"sqs", "dynamodb"},
+			wantLocalStack: true,
+		},
+		{
+			name:           "python plain boto3",
+			filename:       "requirements.txt",
+			content:        "boto3\n",
+			wantServices:   nil,
+			wantLocalStack: false,
+		},
+		{
+			name:     "rust aws-sdk crates",
+			filename: "Cargo.toml",
+			content: `[package]
+name = "orders"
+edition = "2021"
+
+[dependencies]
+aws-config = "1"
+aws-sdk-dynamodb = "1"
+aws-sdk-s3 = "1"
+`,
+			wantServices:   []string{"dynamodb", "s3"},
+			wantLocalStack: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-aws-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			if err := os.WriteFile(filepath.Join(tmpDir, tt.filename), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.filename, err)
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+
+			if len(detection.AWSServices) != len(tt.wantServices) {
+				t.Errorf("AWSServices = %v, want %v", detection.AWSServices, tt.wantServices)
+			}
+			for i, service := range tt.wantServices {
+				if i < len(detection.AWSServices) && detection.AWSServices[i] != service {
+					t.Errorf("AWSServices[%d] = %q, want %q", i, detection.AWSServices[i], service)
+				}
+			}
+
+			if detection.NeedsLocalStack() != tt.wantLocalStack {
+				t.Errorf("NeedsLocalStack() = %v, want %v", detection.NeedsLocalStack(), tt.wantLocalStack)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package detector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Synthetic monorepo size: largeRepoPackages workspace packages with
+// largeRepoFilesPerPackage source files each, plus a node_modules tree
+// largeRepoModulesDepth levels deep.
+const (
+	largeRepoPackages        = 100
+	largeRepoFilesPerPackage = 100
+	largeRepoModulesDepth    = 30
+)
+
+// detectionBudget is the most a full detection of the synthetic monorepo may
+// take. Typical runs take about 10ms; the budget leaves room for slow
+// CI machines while still catching a walk that descends into node_modules or
+// a scan that reads every source file.
+const detectionBudget = 500 * time.Millisecond
+
+// writeLargeMonorepo creates a Node.js workspace monorepo of about 10,000
+// files with a deep node_modules tree, and returns its path.
+func writeLargeMonorepo(tb testing.TB) string {
+	tb.Helper()
+	root := tb.TempDir()
+	write := func(rel, content string) {
+		name := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			tb.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+
+	write("package.json", `{
+  "name": "monorepo",
+  "private": true,
+  "workspaces": ["packages/*"],
+  "engines": {"node": ">=20"},
+  "dependencies": {"express": "^4.19.0", "pg": "^8.11.0", "redis": "^4.6.0", "bullmq": "^5.1.0", "winston": "^3.11.0"}
+}`)
+	write(".gitignore", "coverage/\n*.log\n")
+	for p := 0; p < largeRepoPackages; p++ {
+		pkg := fmt.Sprintf("packages/pkg-%03d", p)
+		write(pkg+"/package.json", fmt.Sprintf(`{"name": "@monorepo/pkg-%03d", "dependencies": {"lodash": "^4.17.21"}}`, p))
+		for f := 0; f < largeRepoFilesPerPackage; f++ {
+			write(fmt.Sprintf("%s/src/module-%03d.js", pkg, f), "module.exports = () => 42;\n")
+		}
+	}
+
+	modules := "node_modules"
+	for d := 0; d < largeRepoModulesDepth; d++ {
+		modules += fmt.Sprintf("/dep-%02d", d)
+		write(modules+"/package.json", fmt.Sprintf(`{"name": "dep-%02d", "dependencies": {"pg": "*"}}`, d))
+		write(modules+"/index.js", strings.Repeat("// vendored\n", 100))
+		modules += "/node_modules"
+	}
+	return root
+}
+
+// BenchmarkDetectAll_LargeMonorepo measures a full detection, as run by
+// dockstart without the cache, of a 10,000-file monorepo.
+func BenchmarkDetectAll_LargeMonorepo(b *testing.B) {
+	root := writeLargeMonorepo(b)
+	registry := NewRegistry()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := registry.DetectAll(root); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkDetectAll_Cached measures a detection of the same monorepo
+// answered from the detection cache.
+func BenchmarkDetectAll_Cached(b *testing.B) {
+	root := writeLargeMonorepo(b)
+	registry := NewRegistry()
+	registry.UseCache(NewCache(root, "bench"))
+	if _, err := registry.DetectAll(root); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := registry.DetectAll(root); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkParseVersionConstraint measures the version parsing run for every
+// manifest.
+func BenchmarkParseVersionConstraint(b *testing.B) {
+	python := NewPythonDetector()
+	for i := 0; i < b.N; i++ {
+		parseVersionConstraint("^20.11.0")
+		python.parseVersionConstraint(">=3.12,<4.0")
+		python.extractPackageName("psycopg2-binary>=2.9.0")
+	}
+}
+
+// TestDetectionBudget enforces the performance budget for detecting a large
+// monorepo. Skipped with -short.
+func TestDetectionBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a 10,000-file repository")
+	}
+	root := writeLargeMonorepo(t)
+
+	registry := NewRegistry()
+	start := time.Now()
+	detections, err := registry.DetectAll(root)
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("DetectAll() error = %v", err)
+	}
+	if len(detections) == 0 || detections[0].Language != "node" {
+		t.Fatalf("DetectAll() = %v, want the Node.js monorepo", detections)
+	}
+	if elapsed > detectionBudget {
+		t.Errorf("detecting a 10,000-file monorepo took %v, over the %v budget", elapsed, detectionBudget)
+	}
+}
@@ -0,0 +1,182 @@
+package detector
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// cacheSchema is bumped when the cache file format changes.
+const cacheSchema = 2
+
+// CacheDir is the directory (relative to the project) holding dockstart state.
+const CacheDir = ".dockstart"
+
+// Cacheable is implemented by detectors whose results can be cached.
+type Cacheable interface {
+	// Inputs returns every file and directory, relative to the project path,
+	// that the detector reads or probes. A cached result is reused only
+	// while all of them are unchanged.
+	Inputs(path string) []string
+}
+
+// Cache stores detection results in .dockstart/cache.json, keyed by the
+// content hashes of each detector's input files.
+type Cache struct {
+	// ReadOnly uses cached results without writing new ones (e.g., in dry-run mode)
+	ReadOnly bool
+
+	path    string
+	version string
+	file    cacheFile
+	dirty   bool
+}
+
+// cacheFile is the on-disk cache format.
+type cacheFile struct {
+	Schema  int                   `json:"schema"`
+	Version string                `json:"version"`
+	Entries map[string]cacheEntry `json:"entries"`
+
+	// DetectionSchema is the models.DetectionSchema the entries were written with
+	DetectionSchema int `json:"detection_schema"`
+}
+
+// cacheEntry is the cached result of one detector.
+type cacheEntry struct {
+	// Inputs maps each input path to its fingerprint
+	Inputs map[string]string `json:"inputs"`
+
+	// Detection is the cached result (null when the language wasn't detected)
+	Detection *models.Detection `json:"detection"`
+}
+
+// NewCache loads the detection cache for a project. Entries written by a
+// different dockstart version, or with a different detection schema, are
+// discarded, since detection rules or the Detection fields may have changed.
+// A missing or unreadable cache starts empty.
+func NewCache(projectPath, version string) *Cache {
+	c := &Cache{
+		path:    filepath.Join(projectPath, CacheDir, "cache.json"),
+		version: version,
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err == nil && json.Unmarshal(data, &c.file) == nil &&
+		c.file.Schema == cacheSchema && c.file.Version == version &&
+		c.file.DetectionSchema == models.DetectionSchema {
+		return c
+	}
+
+	c.file = cacheFile{
+		Schema:          cacheSchema,
+		Version:         version,
+		Entries:         make(map[string]cacheEntry),
+		DetectionSchema: models.DetectionSchema,
+	}
+	return c
+}
+
+// Get returns the cached detection for a detector if its inputs are unchanged.
+// The second return value reports whether the cache was hit.
+func (c *Cache) Get(name string, inputs map[string]string) (*models.Detection, bool) {
+	entry, ok := c.file.Entries[name]
+	if !ok || !sameInputs(entry.Inputs, inputs) {
+		return nil, false
+	}
+	if entry.Detection == nil {
+		return nil, true
+	}
+	copied := *entry.Detection
+	return &copied, true
+}
+
+// Put stores a detector's result. The detection is copied, so later changes
+// to it (e.g., applying frontend detection) don't leak into the cache.
+func (c *Cache) Put(name string, inputs map[string]string, detection *models.Detection) {
+	var stored *models.Detection
+	if detection != nil {
+		copied := *detection
+		stored = &copied
+	}
+	c.file.Entries[name] = cacheEntry{Inputs: inputs, Detection: stored}
+	c.dirty = true
+}
+
+// Save writes the cache to disk if it changed.
+func (c *Cache) Save() error {
+	if !c.dirty || c.ReadOnly {
+		return nil
+	}
+
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Keep dockstart state out of version control
+	gitignore := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(gitignore); os.IsNotExist(err) {
+		if err := os.WriteFile(gitignore, []byte("*\n"), 0644); err != nil {
+			return fmt.Errorf("failed to write cache .gitignore: %w", err)
+		}
+	}
+
+	data, err := json.MarshalIndent(c.file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cache: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+
+	c.dirty = false
+	return nil
+}
+
+// fingerprintInputs returns the fingerprint of each input path.
+// Files are fingerprinted by content hash, directories by existence.
+// Missing paths are recorded too, so creating one invalidates the entry.
+func fingerprintInputs(projectPath string, inputs []string) map[string]string {
+	fingerprints := make(map[string]string, len(inputs))
+	for _, input := range inputs {
+		fingerprints[input] = fingerprint(filepath.Join(projectPath, input))
+	}
+	return fingerprints
+}
+
+// fingerprint returns "sha256:<hex>" for a file, "dir" for a directory, and "missing" otherwise.
+func fingerprint(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "missing"
+	}
+	if info.IsDir() {
+		return "dir"
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "unreadable"
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// sameInputs reports whether two fingerprint maps are identical.
+func sameInputs(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,196 @@
+package detector
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// countingDetector counts Detect calls to observe cache hits.
+type countingDetector struct {
+	calls int
+}
+
+func (d *countingDetector) Name() string                { return "counting" }
+func (d *countingDetector) Inputs(path string) []string { return []string{"app.manifest", "uploads"} }
+
+func (d *countingDetector) Detect(path string) (*models.Detection, error) {
+	d.calls++
+	data, err := os.ReadFile(filepath.Join(path, "app.manifest"))
+	if err != nil {
+		return nil, nil
+	}
+	return &models.Detection{Language: "counting", Version: string(data), Confidence: 0.9}, nil
+}
+
+// TestDetectionCache tests that unchanged inputs skip detection and changed inputs don't.
+func TestDetectionCache(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "dockstart-cache-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	manifest := filepath.Join(tmpDir, "app.manifest")
+	if err := os.WriteFile(manifest, []byte("1.0"), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+
+	d := &countingDetector{}
+	detect := func(version string) *models.Detection {
+		t.Helper()
+		registry := &DetectorRegistry{detectors: []Detector{d}}
+		registry.UseCache(NewCache(tmpDir, version))
+		detection, err := registry.DetectPrimary(tmpDir)
+		if err != nil {
+			t.Fatalf("DetectPrimary() error = %v", err)
+		}
+		return detection
+	}
+
+	steps := []struct {
+		name        string
+		change      func()
+		version     string
+		wantCalls   int
+		wantVersion string
+	}{
+		{name: "first run parses the manifest", version: "v1", wantCalls: 1, wantVersion: "1.0"},
+		{name: "unchanged inputs hit the cache", version: "v1", wantCalls: 1, wantVersion: "1.0"},
+		{
+			name:        "changed manifest re-runs detection",
+			change:      func() { os.WriteFile(manifest, []byte("2.0"), 0644) },
+			version:     "v1",
+			wantCalls:   2,
+			wantVersion: "2.0",
+		},
+		{
+			name:        "new probed directory re-runs detection",
+			change:      func() { os.Mkdir(filepath.Join(tmpDir, "uploads"), 0755) },
+			version:     "v1",
+			wantCalls:   3,
+			wantVersion: "2.0",
+		},
+		{name: "different dockstart build discards the cache", version: "v2", wantCalls: 4, wantVersion: "2.0"},
+		{name: "cache written by the new build is reused", version: "v2", wantCalls: 4, wantVersion: "2.0"},
+	}
+
+	for _, step := range steps {
+		if step.change != nil {
+			step.change()
+		}
+		detection := detect(step.version)
+		if d.calls != step.wantCalls {
+			t.Errorf("%s: Detect() called %d times, want %d", step.name, d.calls, step.wantCalls)
+		}
+		if detection == nil || detection.Version != step.wantVersion {
+			t.Errorf("%s: detection = %+v, want version %s", step.name, detection, step.wantVersion)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, CacheDir, ".gitignore")); err != nil {
+		t.Errorf("cache directory should contain a .gitignore: %v", err)
+	}
+}
+
+// TestDetectionCacheSchema tests that entries written with another detection
+// schema are discarded.
+func TestDetectionCacheSchema(t *testing.T) {
+	tmpDir := t.TempDir()
+	cache := NewCache(tmpDir, "v1")
+	cache.Put("counting", map[string]string{"app.manifest": "missing"}, &models.Detection{Language: "counting"})
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	path := filepath.Join(tmpDir, CacheDir, "cache.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read cache: %v", err)
+	}
+	current := fmt.Sprintf(`"detection_schema": %d`, models.DetectionSchema)
+	if !strings.Contains(string(data), current) {
+		t.Fatalf("cache should record %s:\n%s", current, data)
+	}
+
+	if _, ok := NewCache(tmpDir, "v1").Get("counting", map[string]string{"app.manifest": "missing"}); !ok {
+		t.Error("Get() missed an entry with the current detection schema")
+	}
+
+	old := strings.Replace(string(data), current, `"detection_schema": 1`, 1)
+	if err := os.WriteFile(path, []byte(old), 0644); err != nil {
+		t.Fatalf("Failed to write cache: %v", err)
+	}
+	if _, ok := NewCache(tmpDir, "v1").Get("counting", map[string]string{"app.manifest": "missing"}); ok {
+		t.Error("Get() hit an entry written with an older detection schema")
+	}
+}
+
+// TestDetectionCacheIsolation tests that changes to a returned detection don't leak into the cache.
+func TestDetectionCacheIsolation(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "dockstart-cache-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte("module example.com/api\n\ngo 1.23\n"), 0644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+
+	registry := NewRegistry()
+	registry.UseCache(NewCache(tmpDir, "test"))
+	first, err := registry.DetectPrimary(tmpDir)
+	if err != nil || first == nil {
+		t.Fatalf("DetectPrimary() = %v, %v", first, err)
+	}
+	first.LocalLLM = true
+
+	registry = NewRegistry()
+	registry.UseCache(NewCache(tmpDir, "test"))
+	second, err := registry.DetectPrimary(tmpDir)
+	if err != nil || second == nil {
+		t.Fatalf("DetectPrimary() = %v, %v", second, err)
+	}
+	if second.Language != "go" || second.Version != "1.23" {
+		t.Errorf("cached detection = %s %s, want go 1.23", second.Language, second.Version)
+	}
+	if second.LocalLLM {
+		t.Error("caller changes to a detection should not be cached")
+	}
+}
+
+// TestDetectionCacheLogging tests that cache hits and misses are logged at debug level.
+func TestDetectionCacheLogging(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "app.manifest"), []byte("1.0"), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	for i := 0; i < 2; i++ {
+		registry := &DetectorRegistry{detectors: []Detector{&countingDetector{}}}
+		registry.UseCache(NewCache(tmpDir, "v1"))
+		registry.UseLogger(logger)
+		if _, err := registry.DetectPrimary(tmpDir); err != nil {
+			t.Fatalf("DetectPrimary() error = %v", err)
+		}
+	}
+
+	for _, want := range []string{
+		`msg="detection cache miss" detector=counting`,
+		`msg="detection cache hit" detector=counting`,
+		`msg="detector matched" detector=counting version=1.0`,
+	} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("logs should contain %s, got:\n%s", want, logs.String())
+		}
+	}
+}
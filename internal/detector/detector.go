@@ -2,6 +2,10 @@
 package detector
 
 import (
+	"io"
+	"log/slog"
+	"sort"
+
 	"github.com/jpequegn/dockstart/internal/models"
 )
 
@@ -19,6 +23,11 @@ type Detector interface {
 // DetectorRegistry holds all registered detectors and orchestrates detection.
 type DetectorRegistry struct {
 	detectors []Detector
+	cache     *Cache
+	logger    *slog.Logger
+
+	// serviceVersions override inferred backing service versions
+	serviceVersions map[string]string
 }
 
 // NewRegistry creates a new detector registry with default detectors.
@@ -38,51 +47,123 @@ func (r *DetectorRegistry) Register(d Detector) {
 	r.detectors = append(r.detectors, d)
 }
 
+// UseCache enables result caching for detectors that implement Cacheable.
+func (r *DetectorRegistry) UseCache(c *Cache) {
+	r.cache = c
+}
+
+// UseLogger makes the registry log, at debug level, each detector's result,
+// cache hits and misses, and detectors that failed.
+func (r *DetectorRegistry) UseLogger(logger *slog.Logger) {
+	r.logger = logger
+}
+
+// log returns the registry's logger, which discards everything by default.
+func (r *DetectorRegistry) log() *slog.Logger {
+	if r.logger == nil {
+		return slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	return r.logger
+}
+
+// UseLockfiles makes detectors that implement LockfileReader read lockfiles.
+func (r *DetectorRegistry) UseLockfiles() {
+	for _, detector := range r.detectors {
+		if reader, ok := detector.(LockfileReader); ok {
+			reader.EnableLockfiles()
+		}
+	}
+}
+
+// UseServiceVersions overrides the inferred versions of backing services
+// (e.g., "postgres": "15"), typically from .dockstart.yml.
+func (r *DetectorRegistry) UseServiceVersions(versions map[string]string) {
+	r.serviceVersions = versions
+}
+
 // DetectAll runs all registered detectors and returns all detections.
-// Results are sorted by confidence (highest first).
+// Results are ordered by the resolution policy (best first, see Resolve).
 func (r *DetectorRegistry) DetectAll(path string) ([]*models.Detection, error) {
 	var detections []*models.Detection
 
 	for _, detector := range r.detectors {
-		detection, err := detector.Detect(path)
+		detection, err := r.detect(detector, path)
 		if err != nil {
 			// Log error but continue with other detectors
+			r.log().Debug("detector failed", "detector", detector.Name(), "error", err)
 			continue
 		}
 		if detection != nil {
+			r.log().Debug("detector matched", "detector", detector.Name(),
+				"version", detection.Version, "confidence", detection.Confidence)
+			// Frontends often live in a subdirectory next to the backend
+			applyFrontend(detection, path)
+			// Existing configs are read on every run, so they aren't cache inputs
+			applyServiceVersions(detection, path, r.serviceVersions)
+			applyTimescaleDB(detection, path)
+			applyExternalAPIs(detection, path)
+			applyExistingCompose(detection, path)
+			applyExistingDockerfile(detection, path)
+			applyProductionDockerfile(detection, path)
+			applyLifecycle(detection, path)
 			detections = append(detections, detection)
 		}
 	}
 
-	// Sort by confidence (highest first)
-	sortByConfidence(detections)
+	rankDetections(detections)
+
+	if r.cache != nil {
+		// A cache that can't be written only costs speed on the next run
+		if err := r.cache.Save(); err != nil {
+			r.log().Debug("detection cache not saved", "error", err)
+		}
+	}
 
 	return detections, nil
 }
 
-// DetectPrimary runs all detectors and returns the most confident detection.
-// Returns nil if no language is detected.
-func (r *DetectorRegistry) DetectPrimary(path string) (*models.Detection, error) {
-	detections, err := r.DetectAll(path)
+// detect runs a detector, reusing the cached result when its inputs are unchanged.
+func (r *DetectorRegistry) detect(detector Detector, path string) (*models.Detection, error) {
+	cacheable, ok := detector.(Cacheable)
+	if r.cache == nil || !ok {
+		return detector.Detect(path)
+	}
+
+	inputs := fingerprintInputs(path, cacheable.Inputs(path))
+	if detection, hit := r.cache.Get(detector.Name(), inputs); hit {
+		r.log().Debug("detection cache hit", "detector", detector.Name())
+		return detection, nil
+	}
+	r.log().Debug("detection cache miss", "detector", detector.Name())
+
+	detection, err := detector.Detect(path)
 	if err != nil {
 		return nil, err
 	}
+	r.cache.Put(detector.Name(), inputs, detection)
+	return detection, nil
+}
 
-	if len(detections) == 0 {
-		return nil, nil
+// DetectPrimary runs all detectors and returns the best-ranked detection.
+// Returns nil if no language is detected.
+func (r *DetectorRegistry) DetectPrimary(path string) (*models.Detection, error) {
+	resolution, err := r.Resolve(path, "")
+	if err != nil || resolution == nil {
+		return nil, err
 	}
-
-	return detections[0], nil
+	return resolution.Primary, nil
 }
 
-// sortByConfidence sorts detections by confidence score (highest first).
-func sortByConfidence(detections []*models.Detection) {
-	// Simple bubble sort (good enough for small lists)
-	for i := 0; i < len(detections); i++ {
-		for j := i + 1; j < len(detections); j++ {
-			if detections[j].Confidence > detections[i].Confidence {
-				detections[i], detections[j] = detections[j], detections[i]
-			}
+// directDependencies returns the unique names in deps, sorted.
+func directDependencies(deps []string) []string {
+	seen := make(map[string]bool, len(deps))
+	var unique []string
+	for _, dep := range deps {
+		if !seen[dep] {
+			seen[dep] = true
+			unique = append(unique, dep)
 		}
 	}
+	sort.Strings(unique)
+	return unique
 }
@@ -0,0 +1,206 @@
+package detector
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// dockerfiles lists Dockerfiles a project may already have, in order of preference.
+// Dev-specific files come first; .devcontainer/Dockerfile counts only when
+// dockstart didn't generate it.
+var dockerfiles = []string{
+	".devcontainer/Dockerfile",
+	"Dockerfile.dev",
+	"dev.Dockerfile",
+	"Dockerfile",
+}
+
+// devStages lists build stage names that mark a dev target, in order of preference.
+var devStages = []string{
+	"dev",
+	"development",
+	"devcontainer",
+	"develop",
+	"local",
+}
+
+// productionStages lists build stage names that mark a production target, in
+// order of preference.
+var productionStages = []string{
+	"production",
+	"prod",
+	"release",
+	"runtime",
+}
+
+// shellessImages lists base image prefixes without a shell or package manager,
+// which can't host a devcontainer.
+var shellessImages = []string{
+	"scratch",
+	"gcr.io/distroless/",
+	"cgr.dev/chainguard/static",
+}
+
+// generatedMarker identifies files written by dockstart.
+const generatedMarker = "Generated by dockstart"
+
+// dockerfileStage is a build stage of a Dockerfile.
+type dockerfileStage struct {
+	name string
+	from string
+	user string
+}
+
+// applyExistingDockerfile records the first Dockerfile the project already has,
+// with the stage to build for development or the reason it can't be used.
+func applyExistingDockerfile(detection *models.Detection, projectPath string) {
+	detection.ExistingDockerfile = nil
+
+	for _, name := range dockerfiles {
+		data, err := os.ReadFile(filepath.Join(projectPath, filepath.FromSlash(name)))
+		if err != nil || strings.Contains(string(data), generatedMarker) {
+			continue
+		}
+
+		stages := parseDockerfile(string(data))
+		existing := devTarget(stages, name != "Dockerfile")
+		existing.File = name
+		for _, stage := range stages {
+			if stage.name == "worker" {
+				existing.WorkerTarget = stage.name
+			}
+		}
+		detection.ExistingDockerfile = existing
+		return
+	}
+}
+
+// applyProductionDockerfile records the Dockerfile at the project root, with
+// the stage that builds the production image.
+func applyProductionDockerfile(detection *models.Detection, projectPath string) {
+	detection.ProductionDockerfile = nil
+
+	data, err := os.ReadFile(filepath.Join(projectPath, "Dockerfile"))
+	if err != nil || strings.Contains(string(data), generatedMarker) {
+		return
+	}
+	stages := parseDockerfile(string(data))
+	if len(stages) == 0 {
+		return
+	}
+
+	production := &models.ProductionDockerfile{File: "Dockerfile"}
+	for _, name := range productionStages {
+		if slices.ContainsFunc(stages, func(stage dockerfileStage) bool { return stage.name == name }) {
+			production.Target = name
+			break
+		}
+	}
+	detection.ProductionDockerfile = production
+}
+
+// parseDockerfile returns the build stages of a Dockerfile, in order.
+func parseDockerfile(content string) []dockerfileStage {
+	var stages []dockerfileStage
+	var instruction string
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Join continuation lines into one instruction
+		if strings.HasSuffix(line, "\\") {
+			instruction += strings.TrimSuffix(line, "\\") + " "
+			continue
+		}
+		fields := strings.Fields(instruction + line)
+		instruction = ""
+
+		switch strings.ToUpper(fields[0]) {
+		case "FROM":
+			stage := dockerfileStage{}
+			for i := 1; i < len(fields); i++ {
+				switch {
+				case strings.HasPrefix(fields[i], "--"):
+					continue
+				case stage.from == "":
+					stage.from = fields[i]
+				case strings.EqualFold(fields[i], "AS") && i+1 < len(fields):
+					stage.name = strings.ToLower(fields[i+1])
+					i++
+				}
+			}
+			// A stage built on another inherits its user
+			for _, previous := range stages {
+				if previous.name != "" && previous.name == strings.ToLower(stage.from) {
+					stage.user = previous.user
+				}
+			}
+			stages = append(stages, stage)
+		case "USER":
+			if len(stages) > 0 && len(fields) > 1 {
+				user, _, _ := strings.Cut(fields[1], ":")
+				stages[len(stages)-1].user = user
+			}
+		}
+	}
+
+	return stages
+}
+
+// devTarget picks the stage to build for development. A stage named like a dev
+// target wins; otherwise the last stage is used when the file is dev-specific or
+// has a single stage. Production multi-stage builds and shell-less images are
+// reported as problems.
+func devTarget(stages []dockerfileStage, devFile bool) *models.ExistingDockerfile {
+	if len(stages) == 0 {
+		return &models.ExistingDockerfile{Problem: "no FROM instruction"}
+	}
+
+	for _, name := range devStages {
+		for _, stage := range stages {
+			if stage.name == name {
+				return &models.ExistingDockerfile{Target: stage.name, User: stage.user}
+			}
+		}
+	}
+
+	last := stages[len(stages)-1]
+	if !devFile && len(stages) > 1 {
+		return &models.ExistingDockerfile{Problem: "multi-stage build has no dev stage (name one \"dev\")"}
+	}
+	if base := baseImage(stages, last); isShellless(base) {
+		return &models.ExistingDockerfile{Problem: "final stage is based on " + base + ", which has no shell"}
+	}
+
+	return &models.ExistingDockerfile{User: last.user}
+}
+
+// baseImage resolves a stage's base image through the stages it builds on.
+func baseImage(stages []dockerfileStage, stage dockerfileStage) string {
+	from := stage.from
+	for i := len(stages) - 1; i >= 0; i-- {
+		if stages[i].name != "" && stages[i].name == strings.ToLower(from) {
+			from = stages[i].from
+		}
+	}
+	return from
+}
+
+// isShellless returns true if an image has no shell to run a devcontainer in.
+func isShellless(image string) bool {
+	for _, prefix := range shellessImages {
+		if image == prefix || strings.HasPrefix(image, prefix) {
+			return true
+		}
+	}
+	return false
+}
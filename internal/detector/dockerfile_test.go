@@ -0,0 +1,228 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestExistingDockerfile tests detecting a reusable dev target in a project's Dockerfile.
+func TestExistingDockerfile(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		wantFile    string
+		wantTarget  string
+		wantUser    string
+		wantWorker  string
+		wantProblem bool
+	}{
+		{
+			name: "multi-stage with dev stage",
+			files: map[string]string{
+				"Dockerfile": `FROM golang:1.22 AS base
+WORKDIR /src
+
+FROM base AS dev
+RUN go install github.com/air-verse/air@latest
+
+FROM base AS build
+RUN go build -o /app .
+
+FROM gcr.io/distroless/static
+COPY --from=build /app /app
+`,
+			},
+			wantFile:   "Dockerfile",
+			wantTarget: "dev",
+		},
+		{
+			name: "dev and worker stages",
+			files: map[string]string{
+				"Dockerfile": "FROM golang:1.22 AS dev\n\nFROM dev AS worker\nCMD [\"go\", \"run\", \"./cmd/worker\"]\n",
+			},
+			wantFile:   "Dockerfile",
+			wantTarget: "dev",
+			wantWorker: "worker",
+		},
+		{
+			name: "dev stage inherits the user of its base stage",
+			files: map[string]string{
+				"Dockerfile": `FROM --platform=$BUILDPLATFORM node:20 as Base
+USER node
+
+FROM base as development
+CMD ["npm", "run", "dev"]
+`,
+			},
+			wantFile:   "Dockerfile",
+			wantTarget: "development",
+			wantUser:   "node",
+		},
+		{
+			name: "single stage",
+			files: map[string]string{
+				"Dockerfile": "FROM python:3.12-slim\nUSER app:app\nCMD [\"python\", \"app.py\"]\n",
+			},
+			wantFile: "Dockerfile",
+			wantUser: "app",
+		},
+		{
+			name: "dev Dockerfile preferred over production one",
+			files: map[string]string{
+				"Dockerfile":     "FROM golang:1.22 AS build\nRUN go build\n\nFROM scratch\nCOPY --from=build /app /app\n",
+				"Dockerfile.dev": "FROM golang:1.22\n",
+			},
+			wantFile: "Dockerfile.dev",
+		},
+		{
+			name: "production multi-stage build without dev stage",
+			files: map[string]string{
+				"Dockerfile": "FROM rust:1.75 AS builder\nRUN cargo build --release\n\nFROM debian:bookworm-slim\nCOPY --from=builder /app /app\n",
+			},
+			wantFile:    "Dockerfile",
+			wantProblem: true,
+		},
+		{
+			name: "shell-less final image",
+			files: map[string]string{
+				"Dockerfile": "FROM scratch\nCOPY app /app\n",
+			},
+			wantFile:    "Dockerfile",
+			wantProblem: true,
+		},
+		{
+			name: "generated Dockerfile is not reused",
+			files: map[string]string{
+				".devcontainer/Dockerfile": "# Generated by dockstart - https://github.com/jpequegn/dockstart\n\nFROM golang:1.22\n",
+			},
+		},
+		{
+			name: "hand-written devcontainer Dockerfile",
+			files: map[string]string{
+				".devcontainer/Dockerfile": "FROM mcr.microsoft.com/devcontainers/go:1.22\nUSER vscode\n",
+				"Dockerfile":               "FROM golang:1.22\n",
+			},
+			wantFile: ".devcontainer/Dockerfile",
+			wantUser: "vscode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-dockerfile-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			files := map[string]string{"go.mod": "module github.com/user/app\n\ngo 1.22\n"}
+			for name, content := range tt.files {
+				files[name] = content
+			}
+			for name, content := range files {
+				path := filepath.Join(tmpDir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatalf("Failed to create directory for %s: %v", name, err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write %s: %v", name, err)
+				}
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+
+			existing := detection.ExistingDockerfile
+			if tt.wantFile == "" {
+				if existing != nil {
+					t.Errorf("ExistingDockerfile = %+v, want nil", existing)
+				}
+				return
+			}
+			if existing == nil {
+				t.Fatal("Expected ExistingDockerfile, got nil")
+			}
+			if existing.File != tt.wantFile {
+				t.Errorf("File = %q, want %q", existing.File, tt.wantFile)
+			}
+			if (existing.Problem != "") != tt.wantProblem {
+				t.Errorf("Problem = %q, want problem: %v", existing.Problem, tt.wantProblem)
+			}
+			if existing.Target != tt.wantTarget {
+				t.Errorf("Target = %q, want %q", existing.Target, tt.wantTarget)
+			}
+			if existing.WorkerTarget != tt.wantWorker {
+				t.Errorf("WorkerTarget = %q, want %q", existing.WorkerTarget, tt.wantWorker)
+			}
+			if existing.User != tt.wantUser {
+				t.Errorf("User = %q, want %q", existing.User, tt.wantUser)
+			}
+			if detection.ReusesDockerfile() == tt.wantProblem {
+				t.Errorf("ReusesDockerfile() = %v, want %v", detection.ReusesDockerfile(), !tt.wantProblem)
+			}
+		})
+	}
+}
+
+// TestProductionDockerfile tests finding the production stage of the
+// Dockerfile at the project root.
+func TestProductionDockerfile(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerfile string
+		wantFound  bool
+		wantTarget string
+	}{
+		{
+			name:       "named production stage",
+			dockerfile: "FROM node:20 AS dev\n\nFROM node:20 AS build\nRUN npm run build\n\nFROM node:20-slim AS production\nCMD [\"node\", \"dist/index.js\"]\n\nFROM production AS debug\n",
+			wantFound:  true,
+			wantTarget: "production",
+		},
+		{
+			name:       "last stage",
+			dockerfile: "FROM golang:1.22 AS build\nRUN go build -o /app .\n\nFROM gcr.io/distroless/static\nCOPY --from=build /app /app\n",
+			wantFound:  true,
+		},
+		{
+			name:       "generated Dockerfile",
+			dockerfile: "# Generated by dockstart - https://github.com/jpequegn/dockstart\n\nFROM golang:1.22\n",
+		},
+		{
+			name: "no Dockerfile",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.dockerfile != "" {
+				if err := os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte(tt.dockerfile), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			detection := &models.Detection{}
+			applyProductionDockerfile(detection, dir)
+
+			production := detection.ProductionDockerfile
+			if !tt.wantFound {
+				if production != nil {
+					t.Errorf("ProductionDockerfile = %+v, want nil", production)
+				}
+				return
+			}
+			if production == nil {
+				t.Fatal("ProductionDockerfile = nil")
+			}
+			if production.File != "Dockerfile" || production.Target != tt.wantTarget {
+				t.Errorf("ProductionDockerfile = %+v, want target %q", production, tt.wantTarget)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package detector
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNoProjectDetected means no detector recognized the project
+	ErrNoProjectDetected = errors.New("no supported project detected")
+
+	// ErrUnsupportedLanguage means a forced language has no detector, or
+	// its project files weren't found
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+)
+
+// NoProjectError is returned when no supported project is found in a directory.
+type NoProjectError struct {
+	// Path is the directory that was searched
+	Path string
+}
+
+func (e *NoProjectError) Error() string {
+	return ErrNoProjectDetected.Error()
+}
+
+// Is makes errors.Is(err, ErrNoProjectDetected) match.
+func (e *NoProjectError) Is(target error) bool {
+	return target == ErrNoProjectDetected
+}
+
+// Hint tells the user how to get a project detected.
+func (e *NoProjectError) Hint() string {
+	return "Run dockstart in a directory with package.json (Node.js), go.mod (Go), " +
+		"pyproject.toml or requirements.txt (Python), or Cargo.toml (Rust), or pass that directory as an argument"
+}
+
+// LanguageError is returned when a language is forced (by --language or
+// .dockstart.yml) that dockstart can't generate for.
+type LanguageError struct {
+	// Language is the forced language
+	Language string
+
+	// Supported are the languages dockstart detects
+	Supported []string
+
+	// Detected are the languages found in the project, if the forced
+	// language is supported but wasn't found
+	Detected []string
+}
+
+func (e *LanguageError) Error() string {
+	if e.Detected != nil {
+		return fmt.Sprintf("language %q is forced but no %s project was detected", e.Language, e.Language)
+	}
+	return fmt.Sprintf("unknown language %q (supported: %s)", e.Language, strings.Join(e.Supported, ", "))
+}
+
+// Is makes errors.Is(err, ErrUnsupportedLanguage) match.
+func (e *LanguageError) Is(target error) bool {
+	return target == ErrUnsupportedLanguage
+}
+
+// Hint tells the user which languages they can force.
+func (e *LanguageError) Hint() string {
+	if e.Detected != nil {
+		return fmt.Sprintf("Detected %s; set --language or language: in .dockstart.yml to one of them, or remove it to choose automatically",
+			strings.Join(e.Detected, ", "))
+	}
+	return fmt.Sprintf("Set --language or language: in .dockstart.yml to one of %s", strings.Join(e.Supported, ", "))
+}
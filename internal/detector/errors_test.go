@@ -0,0 +1,24 @@
+package detector
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestNoProjectError tests that the no-project error matches its sentinel and
+// names the manifests dockstart looks for.
+func TestNoProjectError(t *testing.T) {
+	var err error = &NoProjectError{Path: "/workspace/empty"}
+	if !errors.Is(err, ErrNoProjectDetected) {
+		t.Errorf("errors.Is(%v, ErrNoProjectDetected) = false", err)
+	}
+	if errors.Is(err, ErrUnsupportedLanguage) {
+		t.Errorf("errors.Is(%v, ErrUnsupportedLanguage) = true", err)
+	}
+	for _, manifest := range []string{"package.json", "go.mod", "pyproject.toml", "Cargo.toml"} {
+		if hint := err.(*NoProjectError).Hint(); !strings.Contains(hint, manifest) {
+			t.Errorf("Hint() = %q, want it to mention %s", hint, manifest)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestErrorTrackingDetection tests Sentry SDK detection across languages.
+func TestErrorTrackingDetection(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		wantLibs []string
+	}{
+		{
+			name:     "node sentry",
+			filename: "package.json",
+			content:  `{"name": "shop", "dependencies": {"express": "^4.18.0", "@sentry/node": "^8.0.0"}}`,
+			wantLibs: []string{"@sentry/node"},
+		},
+		{
+			name:     "python sentry-sdk with extras",
+			filename: "requirements.txt",
+			content:  "django\nsentry-sdk[django]>=2.0\n",
+			wantLibs: []string{"sentry-sdk"},
+		},
+		{
+			name:     "go sentry-go",
+			filename: "go.mod",
+			content: `module github.com/user/shop
+
+go 1.22
+
+require (
+	github.com/getsentry/sentry-go v0.28.0
+)
+`,
+			wantLibs: []string{"sentry-go"},
+		},
+		{
+			name:     "rust sentry crate",
+			filename: "Cargo.toml",
+			content: `[package]
+name = "shop"
+edition = "2021"
+
+[dependencies]
+sentry = "0.34"
+`,
+			wantLibs: []string{"sentry"},
+		},
+		{
+			name:     "no sentry sdk",
+			filename: "requirements.txt",
+			content:  "flask\n",
+			wantLibs: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, tt.filename), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.filename, err)
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+
+			if !reflect.DeepEqual(detection.ErrorTrackingLibraries, tt.wantLibs) {
+				t.Errorf("ErrorTrackingLibraries = %v, want %v", detection.ErrorTrackingLibraries, tt.wantLibs)
+			}
+			if detection.TracksErrors() != (len(tt.wantLibs) > 0) {
+				t.Errorf("TracksErrors() = %v, want %v", detection.TracksErrors(), len(tt.wantLibs) > 0)
+			}
+			// GlitchTip is opt-in
+			if detection.NeedsGlitchTip() {
+				t.Error("NeedsGlitchTip() = true without --glitchtip")
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package detector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// applyExistingCompose imports the first compose file found at the project root.
+// Backing services it already runs (e.g., a "db" service on a postgres image) are
+// added to the detection, so sidecars are generated around them.
+func applyExistingCompose(detection *models.Detection, projectPath string) {
+	detection.ExistingCompose = nil
+
+	for _, name := range composeFiles {
+		existing, err := parseExistingCompose(filepath.Join(projectPath, name))
+		if err != nil || existing == nil {
+			continue
+		}
+		existing.File = name
+		detection.ExistingCompose = existing
+
+		// Services may be shared with the cached detection, so copy before adding
+		detection.Services = append([]string(nil), detection.Services...)
+		for _, service := range existing.Services {
+			if service.Role != "" {
+				detection.AddService(service.Role)
+			}
+		}
+		return
+	}
+}
+
+// parseExistingCompose reads a compose file's services and top-level definitions.
+// Returns nil if the file does not exist.
+func parseExistingCompose(path string) (*models.ExistingCompose, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// Decoding through yaml.Node keeps the service order; decoding each service
+	// into a map resolves anchors and merge keys (<<: *defaults)
+	var file struct {
+		Services yaml.Node              `yaml:"services"`
+		Volumes  map[string]interface{} `yaml:"volumes"`
+		Networks map[string]interface{} `yaml:"networks"`
+		Configs  map[string]interface{} `yaml:"configs"`
+		Secrets  map[string]interface{} `yaml:"secrets"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+
+	existing := &models.ExistingCompose{
+		Volumes:  file.Volumes,
+		Networks: file.Networks,
+		Configs:  file.Configs,
+		Secrets:  file.Secrets,
+	}
+
+	roles := make(map[string]bool)
+	for i := 1; i < len(file.Services.Content); i += 2 {
+		service := models.ExistingService{Name: file.Services.Content[i-1].Value}
+		if err := file.Services.Content[i].Decode(&service.Definition); err != nil {
+			return nil, fmt.Errorf("failed to parse service %s: %w", service.Name, err)
+		}
+
+		// The first service running a backing service stands in for it
+		if image, ok := service.Definition["image"].(string); ok {
+			if role, _ := imageService(image); role != "" && !roles[role] {
+				service.Role = role
+				roles[role] = true
+			}
+		}
+		existing.Services = append(existing.Services, service)
+	}
+
+	return existing, nil
+}
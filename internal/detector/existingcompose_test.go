@@ -0,0 +1,139 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestExistingCompose tests importing services from a project's compose file.
+func TestExistingCompose(t *testing.T) {
+	tests := []struct {
+		name         string
+		files        map[string]string
+		wantFile     string
+		wantServices []string
+		wantRoles    map[string]string
+		wantDetected []string
+	}{
+		{
+			name: "database and cache under custom names",
+			files: map[string]string{
+				"go.mod": "module github.com/user/app\n\ngo 1.22\n",
+				"docker-compose.yml": `services:
+  web:
+    build: .
+  db:
+    image: postgres:15
+  cache:
+    image: redis:7-alpine
+  nginx:
+    image: nginx:1.25
+`,
+			},
+			wantFile:     "docker-compose.yml",
+			wantServices: []string{"web", "db", "cache", "nginx"},
+			wantRoles:    map[string]string{"db": "postgres", "cache": "redis"},
+			wantDetected: []string{"postgres", "redis"},
+		},
+		{
+			name: "first service per backing service gets the role",
+			files: map[string]string{
+				"go.mod": "module github.com/user/app\n\ngo 1.22\n",
+				"compose.yaml": `services:
+  primary:
+    image: postgres:16
+  replica:
+    image: bitnami/postgresql:16
+`,
+			},
+			wantFile:     "compose.yaml",
+			wantServices: []string{"primary", "replica"},
+			wantRoles:    map[string]string{"primary": "postgres"},
+			wantDetected: []string{"postgres"},
+		},
+		{
+			name: "anchors and merge keys are resolved",
+			files: map[string]string{
+				"go.mod": "module github.com/user/app\n\ngo 1.22\n",
+				"docker-compose.yml": `x-db: &db
+  image: postgres:15
+services:
+  database:
+    <<: *db
+    restart: always
+`,
+			},
+			wantFile:     "docker-compose.yml",
+			wantServices: []string{"database"},
+			wantRoles:    map[string]string{"database": "postgres"},
+			wantDetected: []string{"postgres"},
+		},
+		{
+			name: "no compose file",
+			files: map[string]string{
+				"go.mod": "module github.com/user/app\n\ngo 1.22\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-existingcompose-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write %s: %v", name, err)
+				}
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+
+			if tt.wantFile == "" {
+				if detection.ExistingCompose != nil {
+					t.Errorf("ExistingCompose = %+v, want nil", detection.ExistingCompose)
+				}
+				return
+			}
+			if detection.ExistingCompose == nil {
+				t.Fatal("Expected ExistingCompose, got nil")
+			}
+			if detection.ExistingCompose.File != tt.wantFile {
+				t.Errorf("File = %q, want %q", detection.ExistingCompose.File, tt.wantFile)
+			}
+
+			var names []string
+			for _, service := range detection.ExistingCompose.Services {
+				names = append(names, service.Name)
+				if service.Role != tt.wantRoles[service.Name] {
+					t.Errorf("Role of %s = %q, want %q", service.Name, service.Role, tt.wantRoles[service.Name])
+				}
+				if service.Definition["image"] == nil && service.Definition["build"] == nil {
+					t.Errorf("Definition of %s is missing image and build: %v", service.Name, service.Definition)
+				}
+			}
+			if len(names) != len(tt.wantServices) {
+				t.Fatalf("Services = %v, want %v", names, tt.wantServices)
+			}
+			for i := range names {
+				if names[i] != tt.wantServices[i] {
+					t.Errorf("Services = %v, want %v", names, tt.wantServices)
+					break
+				}
+			}
+
+			for _, service := range tt.wantDetected {
+				if !detection.HasService(service) {
+					t.Errorf("Services = %v, want %s", detection.Services, service)
+				}
+			}
+		})
+	}
+}
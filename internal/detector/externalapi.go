@@ -0,0 +1,92 @@
+package detector
+
+import (
+	"bufio"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// envFiles are the dotenv files that document a project's configuration.
+// Only variable names and URL hosts are used, never the values themselves.
+var envFiles = []string{".env.example", ".env.sample", ".env.template", ".env.dist", ".env"}
+
+// externalAPISuffixes are the endings of variable names that hold an API address.
+var externalAPISuffixes = []string{"_URL", "_URI", "_ENDPOINT", "_API"}
+
+// managedAPIVars are address variables the generated stack already points at
+// a sidecar (LocalStack, Ollama, Jaeger, Selenium Grid).
+var managedAPIVars = []string{"AWS_ENDPOINT_URL", "OPENAI_BASE_URL", "OTEL_EXPORTER_OTLP_ENDPOINT", "SELENIUM_REMOTE_URL"}
+
+// applyExternalAPIs records the variables in the project's dotenv files that
+// point at third-party HTTP APIs, e.g. PAYMENTS_API_URL=https://api.example.com.
+// Together with an HTTP client, they suggest the WireMock sidecar.
+func applyExternalAPIs(detection *models.Detection, projectPath string) {
+	detection.ExternalAPIs = nil
+	for _, name := range envFiles {
+		for _, variable := range externalAPIVars(filepath.Join(projectPath, name)) {
+			if !containsService(detection.ExternalAPIs, variable) {
+				detection.ExternalAPIs = append(detection.ExternalAPIs, variable)
+			}
+		}
+	}
+}
+
+// externalAPIVars returns the variables of a dotenv file whose value is an
+// http(s) URL on a public host. Returns nil if the file is missing.
+func externalAPIVars(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var variables []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		line = strings.TrimPrefix(line, "export ")
+		name, value, ok := strings.Cut(line, "=")
+		if !ok || strings.HasPrefix(name, "#") {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		if !hasAnySuffix(name, externalAPISuffixes) || containsService(managedAPIVars, name) {
+			continue
+		}
+		if isExternalURL(value) {
+			variables = append(variables, name)
+		}
+	}
+	return variables
+}
+
+// isExternalURL reports whether value is an http(s) URL on a public host, as
+// opposed to localhost, an IP address, or a compose service name.
+func isExternalURL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	host := u.Hostname()
+	if net.ParseIP(host) != nil || !strings.Contains(host, ".") {
+		return false
+	}
+	return !strings.HasSuffix(host, ".localhost") && !strings.HasSuffix(host, ".local") &&
+		!strings.HasSuffix(host, ".internal")
+}
+
+// hasAnySuffix reports whether s ends with any of suffixes.
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
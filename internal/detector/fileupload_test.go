@@ -787,3 +787,75 @@ func TestFileUploadDetection_ModelHelpers(t *testing.T) {
 		t.Error("NeedsFileProcessor should return true when upload libraries exist")
 	}
 }
+
+// TestNestedUploadPath tests that upload directories are found below the project
+// root while vendored and git-ignored trees are skipped.
+func TestNestedUploadPath(t *testing.T) {
+	tests := []struct {
+		name      string
+		dirs      []string
+		gitignore string
+		want      string
+	}{
+		{
+			name: "root uploads wins over nested",
+			dirs: []string{"uploads", "apps/api/uploads"},
+			want: "uploads",
+		},
+		{
+			name: "nested uploads in monorepo service",
+			dirs: []string{"apps/api/uploads"},
+			want: "apps/api/uploads",
+		},
+		{
+			name:      "git-ignored uploads directory is still found",
+			dirs:      []string{"services/api/upload"},
+			gitignore: "upload/\n",
+			want:      "services/api/upload",
+		},
+		{
+			name: "vendored uploads are ignored",
+			dirs: []string{"node_modules/pkg/uploads", "vendor/lib/uploads", ".venv/lib/uploads"},
+			want: "",
+		},
+		{
+			name:      "uploads inside git-ignored tree are ignored",
+			dirs:      []string{"tmp/cache/uploads"},
+			gitignore: "/tmp\n",
+			want:      "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-upload-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			packageJSON := `{"name": "test-app", "dependencies": {"multer": "^1.4.5"}}`
+			if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(packageJSON), 0644); err != nil {
+				t.Fatalf("Failed to write package.json: %v", err)
+			}
+			if tt.gitignore != "" {
+				if err := os.WriteFile(filepath.Join(tmpDir, ".gitignore"), []byte(tt.gitignore), 0644); err != nil {
+					t.Fatalf("Failed to write .gitignore: %v", err)
+				}
+			}
+			for _, dir := range tt.dirs {
+				if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0755); err != nil {
+					t.Fatalf("Failed to create %s: %v", dir, err)
+				}
+			}
+
+			detection, err := NewNodeDetector().Detect(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection.UploadPath != tt.want {
+				t.Errorf("UploadPath = %q, want %q", detection.UploadPath, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package detector
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"github.com/jpequegn/dockstart/internal/walker"
+)
+
+// frontendDirs lists common directories holding a frontend next to a backend.
+// "." covers a root package.json in a repo whose primary language is not Node.js.
+var frontendDirs = []string{
+	".",
+	"frontend",
+	"web",
+	"client",
+	"ui",
+	"apps/web",
+}
+
+// frontendScanDepth is how deep monorepos are searched when no common frontend
+// directory matches (e.g., "packages/site").
+const frontendScanDepth = 2
+
+// backendFrameworks lists Node.js server frameworks. When one of these shares
+// a package.json with a frontend framework, the frontend runs as its own service.
+var backendFrameworks = []string{
+	"express",
+	"fastify",
+	"koa",
+	"@nestjs/core",
+	"@hapi/hapi",
+}
+
+// detectFrontendFramework identifies a frontend framework from dependencies.
+// Returns an empty string if no frontend framework was found.
+func detectFrontendFramework(deps map[string]string) string {
+	// Priority order: meta-frameworks first, since they depend on vite/react themselves
+	frameworks := []struct {
+		dep  string
+		name string
+	}{
+		{"next", "nextjs"},
+		{"@sveltejs/kit", "sveltekit"},
+		{"astro", "astro"},
+		{"react-scripts", "cra"},
+		{"vite", "vite"},
+	}
+
+	for _, fw := range frameworks {
+		if _, exists := deps[fw.dep]; exists {
+			return fw.name
+		}
+	}
+
+	return ""
+}
+
+// frontendDefaultPort returns the default dev server port for a frontend framework.
+func frontendDefaultPort(framework string) int {
+	switch framework {
+	case "nextjs", "cra":
+		return 3000
+	case "vite", "sveltekit":
+		return 5173
+	case "astro":
+		return 4321
+	default:
+		return 3000
+	}
+}
+
+// frontendPort returns the dev server port for a frontend framework, moving it
+// off the backend port (and Grafana's 3001) when the defaults collide.
+func frontendPort(framework string, backendPort int) int {
+	return models.FreePort(frontendDefaultPort(framework), models.ReservedHostPorts(backendPort))
+}
+
+// detectFrontendDir looks for a frontend package.json in common frontend directories,
+// then in the rest of the tree up to frontendScanDepth, skipping vendored and
+// git-ignored directories. Returns the framework and its directory, or empty strings.
+func detectFrontendDir(projectPath string, language string) (string, string) {
+	for _, dir := range frontendDirs {
+		// The Node.js detector already handles the root package.json
+		if dir == "." && language == "node" {
+			continue
+		}
+
+		if framework := frontendFrameworkIn(projectPath, dir); framework != "" {
+			return framework, dir
+		}
+	}
+
+	for _, dir := range walker.Dirs(projectPath, frontendScanDepth) {
+		if containsService(frontendDirs, dir) {
+			continue
+		}
+
+		if framework := frontendFrameworkIn(projectPath, dir); framework != "" {
+			return framework, dir
+		}
+	}
+
+	return "", ""
+}
+
+// frontendFrameworkIn returns the frontend framework of the package.json in dir,
+// or an empty string if there is none.
+func frontendFrameworkIn(projectPath string, dir string) string {
+	data, err := os.ReadFile(filepath.Join(projectPath, filepath.FromSlash(dir), "package.json"))
+	if err != nil {
+		return ""
+	}
+
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return ""
+	}
+
+	return detectFrontendFramework(mergeDeps(pkg))
+}
+
+// applyFrontend fills in frontend fields for a detection from frontend subdirectories.
+// Detections that already found a frontend (e.g., in a root package.json) are left alone.
+func applyFrontend(detection *models.Detection, projectPath string) {
+	if detection.FrontendFramework != "" {
+		return
+	}
+
+	framework, dir := detectFrontendDir(projectPath, detection.Language)
+	if framework == "" {
+		return
+	}
+
+	detection.FrontendFramework = framework
+	detection.FrontendDir = dir
+	detection.FrontendPort = frontendPort(framework, detection.GetAppPort())
+}
+
+// mergeDeps merges dependencies and devDependencies from a package.json.
+func mergeDeps(pkg packageJSON) map[string]string {
+	allDeps := make(map[string]string)
+	for k, v := range pkg.Dependencies {
+		allDeps[k] = v
+	}
+	for k, v := range pkg.DevDependencies {
+		allDeps[k] = v
+	}
+	return allDeps
+}
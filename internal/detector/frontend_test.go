@@ -0,0 +1,171 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestFrontendDetection tests frontend framework detection across project layouts.
+func TestFrontendDetection(t *testing.T) {
+	tests := []struct {
+		name           string
+		files          map[string]string
+		wantFramework  string
+		wantDir        string
+		wantPort       int
+		wantWebService bool
+	}{
+		{
+			name: "next.js app on its own",
+			files: map[string]string{
+				"package.json": `{"name": "web", "dependencies": {"next": "^14.0.0", "react": "^18.0.0"}}`,
+			},
+			wantFramework:  "nextjs",
+			wantDir:        "",
+			wantPort:       3000,
+			wantWebService: false,
+		},
+		{
+			name: "vite with express backend in same package.json",
+			files: map[string]string{
+				"package.json": `{"name": "app", "dependencies": {"express": "^4.18.0"}, "devDependencies": {"vite": "^5.0.0"}}`,
+			},
+			wantFramework:  "vite",
+			wantDir:        ".",
+			wantPort:       5173,
+			wantWebService: true,
+		},
+		{
+			name: "next.js with express moves off the backend port",
+			files: map[string]string{
+				"package.json": `{"name": "app", "dependencies": {"express": "^4.18.0", "next": "^14.0.0"}}`,
+			},
+			wantFramework:  "nextjs",
+			wantDir:        ".",
+			wantPort:       3002,
+			wantWebService: true,
+		},
+		{
+			name: "go backend with vite frontend directory",
+			files: map[string]string{
+				"go.mod":                "module github.com/user/app\n\ngo 1.22\n",
+				"frontend/package.json": `{"name": "frontend", "devDependencies": {"vite": "^5.0.0"}}`,
+			},
+			wantFramework:  "vite",
+			wantDir:        "frontend",
+			wantPort:       5173,
+			wantWebService: true,
+		},
+		{
+			name: "python backend with sveltekit web directory",
+			files: map[string]string{
+				"requirements.txt": "fastapi\nuvicorn\n",
+				"web/package.json": `{"name": "web", "devDependencies": {"@sveltejs/kit": "^2.0.0", "vite": "^5.0.0"}}`,
+			},
+			wantFramework:  "sveltekit",
+			wantDir:        "web",
+			wantPort:       5173,
+			wantWebService: true,
+		},
+		{
+			name: "rust backend with astro client directory",
+			files: map[string]string{
+				"Cargo.toml":          "[package]\nname = \"app\"\nedition = \"2021\"\n",
+				"client/package.json": `{"name": "client", "dependencies": {"astro": "^4.0.0"}}`,
+			},
+			wantFramework:  "astro",
+			wantDir:        "client",
+			wantPort:       4321,
+			wantWebService: true,
+		},
+		{
+			name: "node backend with create-react-app client directory",
+			files: map[string]string{
+				"package.json":        `{"name": "api", "dependencies": {"express": "^4.18.0"}}`,
+				"client/package.json": `{"name": "client", "dependencies": {"react-scripts": "5.0.1"}}`,
+			},
+			wantFramework:  "cra",
+			wantDir:        "client",
+			wantPort:       3002,
+			wantWebService: true,
+		},
+		{
+			name: "go backend with monorepo frontend package",
+			files: map[string]string{
+				"go.mod":                      "module github.com/user/app\n\ngo 1.22\n",
+				"packages/site/package.json":  `{"name": "site", "devDependencies": {"astro": "^4.0.0"}}`,
+				"node_modules/x/package.json": `{"name": "x", "dependencies": {"next": "^14.0.0"}}`,
+			},
+			wantFramework:  "astro",
+			wantDir:        "packages/site",
+			wantPort:       4321,
+			wantWebService: true,
+		},
+		{
+			name: "git-ignored and vendored frontends are skipped",
+			files: map[string]string{
+				"go.mod":                  "module github.com/user/app\n\ngo 1.22\n",
+				".gitignore":              "scratch/\n",
+				"scratch/package.json":    `{"name": "scratch", "devDependencies": {"vite": "^5.0.0"}}`,
+				"vendor/ui/package.json":  `{"name": "ui", "devDependencies": {"vite": "^5.0.0"}}`,
+				"examples/demo/README.md": "demo",
+			},
+			wantFramework:  "",
+			wantDir:        "",
+			wantPort:       0,
+			wantWebService: false,
+		},
+		{
+			name: "backend only",
+			files: map[string]string{
+				"go.mod": "module github.com/user/app\n\ngo 1.22\n",
+			},
+			wantFramework:  "",
+			wantDir:        "",
+			wantPort:       0,
+			wantWebService: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-frontend-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			for name, content := range tt.files {
+				path := filepath.Join(tmpDir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatalf("Failed to create directory for %s: %v", name, err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write %s: %v", name, err)
+				}
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+
+			if detection.FrontendFramework != tt.wantFramework {
+				t.Errorf("FrontendFramework = %q, want %q", detection.FrontendFramework, tt.wantFramework)
+			}
+			if detection.FrontendDir != tt.wantDir {
+				t.Errorf("FrontendDir = %q, want %q", detection.FrontendDir, tt.wantDir)
+			}
+			if detection.FrontendPort != tt.wantPort {
+				t.Errorf("FrontendPort = %d, want %d", detection.FrontendPort, tt.wantPort)
+			}
+			if detection.NeedsWebService() != tt.wantWebService {
+				t.Errorf("NeedsWebService() = %v, want %v", detection.NeedsWebService(), tt.wantWebService)
+			}
+		})
+	}
+}
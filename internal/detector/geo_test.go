@@ -0,0 +1,84 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestGeoDetection tests geospatial library detection across languages.
+func TestGeoDetection(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		wantLibs []string
+	}{
+		{
+			name:     "node turf and knex-postgis",
+			filename: "package.json",
+			content:  `{"name": "maps", "dependencies": {"express": "^4.18.0", "knex-postgis": "^0.14.0", "@turf/turf": "^7.0.0"}}`,
+			wantLibs: []string{"@turf/turf", "knex-postgis"},
+		},
+		{
+			name:     "python geopandas and GDAL",
+			filename: "requirements.txt",
+			content:  "geopandas>=0.14\nShapely==2.0.4\nGDAL==3.6.2\n",
+			wantLibs: []string{"geopandas", "shapely", "gdal"},
+		},
+		{
+			name:     "go orb",
+			filename: "go.mod",
+			content: `module github.com/user/maps
+
+go 1.22
+
+require (
+	github.com/paulmach/orb v0.11.1
+)
+`,
+			wantLibs: []string{"orb"},
+		},
+		{
+			name:     "rust geo and postgis crates",
+			filename: "Cargo.toml",
+			content: `[package]
+name = "maps"
+edition = "2021"
+
+[dependencies]
+geo = "0.28"
+postgis = "0.9"
+`,
+			wantLibs: []string{"geo", "postgis"},
+		},
+		{
+			name:     "no geospatial libraries",
+			filename: "requirements.txt",
+			content:  "flask\n",
+			wantLibs: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, tt.filename), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.filename, err)
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+
+			if !reflect.DeepEqual(detection.GeoLibraries, tt.wantLibs) {
+				t.Errorf("GeoLibraries = %v, want %v", detection.GeoLibraries, tt.wantLibs)
+			}
+		})
+	}
+}
@@ -11,7 +11,10 @@ import (
 )
 
 // GoDetector detects Go projects by analyzing go.mod files.
-type GoDetector struct{}
+type GoDetector struct {
+	// lockfiles enables pinning module versions from go.sum
+	lockfiles bool
+}
 
 // NewGoDetector creates a new Go detector.
 func NewGoDetector() *GoDetector {
@@ -23,6 +26,17 @@ func (d *GoDetector) Name() string {
 	return "go"
 }
 
+// Inputs returns the files and directories the Go detector reads.
+func (d *GoDetector) Inputs(path string) []string {
+	inputs := append([]string{"go.mod"}, lockfileInputs(d.lockfiles, goLockfiles)...)
+	return append(inputs, uploadInputs(path, []string{"go.mod"}, goUploadDirs)...)
+}
+
+// EnableLockfiles makes the detector read go.sum.
+func (d *GoDetector) EnableLockfiles() {
+	d.lockfiles = true
+}
+
 // goMod represents parsed information from a go.mod file.
 type goMod struct {
 	Module   string
@@ -46,33 +60,98 @@ func (d *GoDetector) Detect(path string) (*models.Detection, error) {
 		return nil, err
 	}
 
+	var lockfile string
+	var pinned map[string]string
+	if d.lockfiles {
+		lockfile, pinned = d.pinVersions(mod, path)
+	}
+
 	loggingLibs, logFormat := d.detectLogging(mod)
 	queueLibs, workerCmd := d.detectQueue(mod)
 	uploadLibs, uploadPath := d.detectFileUpload(mod, path)
 	metricsLibs, metricsPort, metricsPath := d.detectMetrics(mod)
 	tracingLibs, tracingProtocol := d.detectTracing(mod)
+	grpcLibs := d.detectGRPC(mod)
+	authLibs := d.detectAuth(mod)
+	paymentLibs := d.detectPayments(mod)
+	webDriverLibs := d.detectWebDriver(mod)
+	awsServices := d.detectAWSServices(mod)
+	vectorLibs := d.detectVectorStores(mod)
+	llmLibs := d.detectLLM(mod)
+	httpLibs := d.detectHTTPClients(mod)
+	errorTrackingLibs := d.detectErrorTracking(mod)
+	geoLibs := d.detectGeo(mod)
+	searchLibs := d.detectSearch(mod)
+	mailLibs := d.detectMail(mod)
+	websocketLibs := d.detectWebsockets(mod)
+	schedulerLibs := d.detectScheduler(mod)
 
 	detection := &models.Detection{
-		Language:            "go",
-		Version:             mod.Version,
-		Services:            d.detectServices(mod),
-		Confidence:          d.calculateConfidence(mod),
-		LoggingLibraries:    loggingLibs,
-		LogFormat:           logFormat,
-		QueueLibraries:      queueLibs,
-		WorkerCommand:       workerCmd,
-		FileUploadLibraries: uploadLibs,
-		UploadPath:          uploadPath,
-		MetricsLibraries:    metricsLibs,
-		MetricsPort:         metricsPort,
-		MetricsPath:         metricsPath,
-		TracingLibraries:    tracingLibs,
-		TracingProtocol:     tracingProtocol,
+		Language:       "go",
+		Version:        mod.Version,
+		Services:       d.detectServices(mod),
+		Confidence:     d.calculateConfidence(mod),
+		Lockfile:       lockfile,
+		PinnedVersions: pinned,
+		Dependencies:   directDependencies(mod.Requires),
+		Capabilities: models.Capabilities{
+			LoggingLibraries:       loggingLibs,
+			QueueLibraries:         queueLibs,
+			FileUploadLibraries:    uploadLibs,
+			MetricsLibraries:       metricsLibs,
+			TracingLibraries:       tracingLibs,
+			WebsocketLibraries:     websocketLibs,
+			GRPCLibraries:          grpcLibs,
+			AuthLibraries:          authLibs,
+			PaymentLibraries:       paymentLibs,
+			WebDriverLibraries:     webDriverLibs,
+			AWSServices:            awsServices,
+			VectorLibraries:        vectorLibs,
+			LLMLibraries:           llmLibs,
+			HTTPClientLibraries:    httpLibs,
+			ErrorTrackingLibraries: errorTrackingLibs,
+			GeoLibraries:           geoLibs,
+			SearchLibraries:        searchLibs,
+			MailLibraries:          mailLibs,
+			SchedulerLibraries:     schedulerLibs,
+		},
+		LogFormat:       logFormat,
+		WorkerCommand:   workerCmd,
+		UploadPath:      uploadPath,
+		MetricsPort:     metricsPort,
+		MetricsPath:     metricsPath,
+		TracingProtocol: tracingProtocol,
+		LocalLLM:        containsService(llmLibs, "ollama"),
 	}
 
 	return detection, nil
 }
 
+// pinVersions returns go.sum and the locked versions of the modules go.mod requires.
+// Unlike other lockfiles, go.sum adds no libraries: since Go 1.17, go.mod already
+// lists indirect dependencies, while go.sum also holds modules pruned from the build.
+func (d *GoDetector) pinVersions(mod *goMod, path string) (string, map[string]string) {
+	lockfile, locked := readLockfile(path, goLockfiles)
+	if locked == nil {
+		return "", nil
+	}
+
+	pinned := make(map[string]string)
+	for _, req := range mod.Requires {
+		if version, ok := locked[req]; ok {
+			pinned[req] = version
+		}
+	}
+	return lockfile, pinned
+}
+
+// go.mod line patterns
+var (
+	moduleRe    = regexp.MustCompile(`^module\s+(.+)$`)
+	goVersionRe = regexp.MustCompile(`^go\s+(\d+\.\d+)`)
+	requireRe   = regexp.MustCompile(`^\s*([a-zA-Z0-9._/-]+)\s+v`)
+)
+
 // parseGoMod reads and parses a go.mod file.
 // go.mod format:
 //
@@ -92,11 +171,6 @@ func (d *GoDetector) parseGoMod(path string) (*goMod, error) {
 		Version: "1.21", // Default version
 	}
 
-	// Regex patterns
-	moduleRe := regexp.MustCompile(`^module\s+(.+)$`)
-	goVersionRe := regexp.MustCompile(`^go\s+(\d+\.\d+)`)
-	requireRe := regexp.MustCompile(`^\s*([a-zA-Z0-9._/-]+)\s+v`)
-
 	inRequireBlock := false
 	scanner := bufio.NewScanner(file)
 
@@ -175,6 +249,28 @@ func (d *GoDetector) detectServices(mod *goMod) []string {
 		"github.com/gomodule/redigo",
 	}
 
+	// ClickHouse indicators
+	clickhousePatterns := []string{
+		"github.com/ClickHouse/clickhouse-go",
+		"github.com/uptrace/go-clickhouse",
+	}
+
+	// Memcached indicators
+	memcachedPatterns := []string{
+		"github.com/bradfitz/gomemcache",
+	}
+
+	// NATS indicators
+	natsPatterns := []string{
+		"github.com/nats-io/nats.go",
+	}
+
+	// InfluxDB indicators
+	influxPatterns := []string{
+		"github.com/influxdata/influxdb-client-go",
+		"github.com/InfluxCommunity/influxdb3-go",
+	}
+
 	for _, req := range mod.Requires {
 		// Check PostgreSQL
 		for _, pattern := range postgresPatterns {
@@ -195,6 +291,46 @@ func (d *GoDetector) detectServices(mod *goMod) []string {
 				break
 			}
 		}
+
+		// Check ClickHouse
+		for _, pattern := range clickhousePatterns {
+			if strings.HasPrefix(req, pattern) {
+				if !containsService(services, "clickhouse") {
+					services = append(services, "clickhouse")
+				}
+				break
+			}
+		}
+
+		// Check Memcached
+		for _, pattern := range memcachedPatterns {
+			if strings.HasPrefix(req, pattern) {
+				if !containsService(services, "memcached") {
+					services = append(services, "memcached")
+				}
+				break
+			}
+		}
+
+		// Check NATS
+		for _, pattern := range natsPatterns {
+			if strings.HasPrefix(req, pattern) {
+				if !containsService(services, "nats") {
+					services = append(services, "nats")
+				}
+				break
+			}
+		}
+
+		// Check InfluxDB
+		for _, pattern := range influxPatterns {
+			if strings.HasPrefix(req, pattern) {
+				if !containsService(services, "influxdb") {
+					services = append(services, "influxdb")
+				}
+				break
+			}
+		}
 	}
 
 	return services
@@ -305,6 +441,8 @@ func (d *GoDetector) detectQueue(mod *goMod) ([]string, string) {
 		"github.com/gocraft/work":            "gocraft-work",
 		"github.com/adjust/rmq":              "rmq",
 		"github.com/gocelery/gocelery":       "gocelery",
+		// Temporal workers poll task queues on the Temporal server
+		"go.temporal.io/sdk": "temporal",
 	}
 
 	for _, req := range mod.Requires {
@@ -318,7 +456,10 @@ func (d *GoDetector) detectQueue(mod *goMod) ([]string, string) {
 
 	// If queue libraries detected, set default worker command
 	// Go workers typically use the same binary with a flag or subcommand
-	if len(libraries) > 0 {
+	if containsService(libraries, "temporal") {
+		// Temporal's samples keep the worker's main package in worker/
+		workerCmd = "go run ./worker"
+	} else if len(libraries) > 0 {
 		// Extract binary name from module path
 		binaryName := "app"
 		if mod.Module != "" {
@@ -385,26 +526,26 @@ func (d *GoDetector) detectFileUpload(mod *goMod, projectPath string) ([]string,
 	return libraries, uploadPath
 }
 
+// goUploadDirs are the common upload directory names for Go projects.
+var goUploadDirs = []string{
+	"uploads",
+	"upload",
+	"files",
+	"static/uploads",
+	"public/uploads",
+	"assets/uploads",
+}
+
 // findUploadPath attempts to find the upload directory for Go projects.
 func (d *GoDetector) findUploadPath(projectPath string) string {
-	// Common upload directory names
-	commonDirs := []string{
-		"uploads",
-		"upload",
-		"files",
-		"static/uploads",
-		"public/uploads",
-		"assets/uploads",
-	}
-
-	for _, dir := range commonDirs {
+	for _, dir := range goUploadDirs {
 		fullPath := filepath.Join(projectPath, dir)
 		if info, err := os.Stat(fullPath); err == nil && info.IsDir() {
 			return dir
 		}
 	}
 
-	return ""
+	return findNestedUploadDir(projectPath)
 }
 
 // detectTracing identifies distributed tracing libraries from Go dependencies.
@@ -549,3 +690,330 @@ func (d *GoDetector) detectMetrics(mod *goMod) ([]string, int, string) {
 
 	return libraries, metricsPort, metricsPath
 }
+
+// detectWebsockets identifies WebSocket libraries from Go dependencies.
+func (d *GoDetector) detectWebsockets(mod *goMod) []string {
+	var libraries []string
+
+	// WebSocket libraries (module prefix -> library name)
+	websocketPatterns := []struct {
+		pattern string
+		name    string
+	}{
+		{"github.com/gorilla/websocket", "gorilla/websocket"},
+		{"nhooyr.io/websocket", "nhooyr-websocket"},
+		{"github.com/coder/websocket", "coder-websocket"},
+		{"github.com/gobwas/ws", "gobwas-ws"},
+		{"github.com/olahol/melody", "melody"},
+	}
+
+	for _, req := range mod.Requires {
+		for _, ws := range websocketPatterns {
+			if strings.HasPrefix(req, ws.pattern) && !containsService(libraries, ws.name) {
+				libraries = append(libraries, ws.name)
+				break
+			}
+		}
+	}
+
+	return libraries
+}
+
+// detectScheduler identifies cron/scheduled-job libraries from Go dependencies.
+// Go schedulers run in-process, so no separate scheduler command is inferred.
+func (d *GoDetector) detectScheduler(mod *goMod) []string {
+	var libraries []string
+
+	// Scheduler libraries (module prefix -> library name)
+	schedulerPatterns := []struct {
+		pattern string
+		name    string
+	}{
+		{"github.com/robfig/cron", "robfig/cron"},
+		{"github.com/go-co-op/gocron", "gocron"},
+		{"github.com/reugn/go-quartz", "go-quartz"},
+	}
+
+	for _, req := range mod.Requires {
+		for _, sched := range schedulerPatterns {
+			if strings.HasPrefix(req, sched.pattern) && !containsService(libraries, sched.name) {
+				libraries = append(libraries, sched.name)
+				break
+			}
+		}
+	}
+
+	return libraries
+}
+
+// detectGRPC identifies gRPC server libraries from Go dependencies.
+func (d *GoDetector) detectGRPC(mod *goMod) []string {
+	var libraries []string
+
+	// gRPC libraries (module prefix -> library name)
+	grpcPatterns := []struct {
+		pattern string
+		name    string
+	}{
+		{"google.golang.org/grpc", "grpc-go"},
+		{"connectrpc.com/connect", "connect-go"},
+	}
+
+	for _, req := range mod.Requires {
+		for _, g := range grpcPatterns {
+			if strings.HasPrefix(req, g.pattern) && !containsService(libraries, g.name) {
+				libraries = append(libraries, g.name)
+				break
+			}
+		}
+	}
+
+	return libraries
+}
+
+// detectAuth identifies OIDC/OAuth client libraries from Go dependencies.
+func (d *GoDetector) detectAuth(mod *goMod) []string {
+	var libraries []string
+
+	// Auth libraries (module prefix -> library name)
+	authPatterns := []struct {
+		pattern string
+		name    string
+	}{
+		{"golang.org/x/oauth2", "oauth2"},
+		{"github.com/coreos/go-oidc", "go-oidc"},
+		{"github.com/zitadel/oidc", "zitadel-oidc"},
+		{"github.com/Nerzal/gocloak", "gocloak"},
+	}
+
+	for _, req := range mod.Requires {
+		for _, auth := range authPatterns {
+			if strings.HasPrefix(req, auth.pattern) && !containsService(libraries, auth.name) {
+				libraries = append(libraries, auth.name)
+				break
+			}
+		}
+	}
+
+	return libraries
+}
+
+// detectPayments identifies Stripe SDKs from Go dependencies.
+func (d *GoDetector) detectPayments(mod *goMod) []string {
+	var libraries []string
+
+	for _, req := range mod.Requires {
+		if strings.HasPrefix(req, "github.com/stripe/stripe-go") && !containsService(libraries, "stripe-go") {
+			libraries = append(libraries, "stripe-go")
+		}
+	}
+
+	return libraries
+}
+
+// detectWebDriver identifies Selenium/WebDriver clients from Go dependencies.
+func (d *GoDetector) detectWebDriver(mod *goMod) []string {
+	var libraries []string
+
+	// WebDriver modules (module path -> library name)
+	webDriverModules := map[string]string{
+		"github.com/tebeka/selenium": "tebeka/selenium",
+		"github.com/sclevine/agouti": "agouti",
+	}
+
+	for _, req := range mod.Requires {
+		if name, ok := webDriverModules[req]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// detectAWSServices identifies AWS services from AWS SDK for Go service packages.
+func (d *GoDetector) detectAWSServices(mod *goMod) []string {
+	var services []string
+
+	for _, service := range awsServiceNames {
+		for _, req := range mod.Requires {
+			if req == "github.com/aws/aws-sdk-go-v2/service/"+service {
+				services = append(services, service)
+				break
+			}
+		}
+	}
+
+	return services
+}
+
+// detectVectorStores identifies vector database clients from Go dependencies.
+func (d *GoDetector) detectVectorStores(mod *goMod) []string {
+	var libraries []string
+
+	// Vector database clients (module prefix -> library name)
+	vectorPatterns := []struct {
+		pattern string
+		name    string
+	}{
+		{"github.com/qdrant/go-client", "qdrant-client"},
+		{"github.com/pgvector/pgvector-go", "pgvector-go"},
+		{"github.com/amikos-tech/chroma-go", "chroma-go"},
+		{"github.com/pinecone-io/go-pinecone", "pinecone"},
+	}
+
+	for _, req := range mod.Requires {
+		for _, v := range vectorPatterns {
+			if strings.HasPrefix(req, v.pattern) && !containsService(libraries, v.name) {
+				libraries = append(libraries, v.name)
+				break
+			}
+		}
+	}
+
+	return libraries
+}
+
+// detectLLM identifies LLM client libraries from Go dependencies.
+func (d *GoDetector) detectLLM(mod *goMod) []string {
+	var libraries []string
+
+	// LLM client modules (module prefix -> provider name)
+	llmPatterns := []struct {
+		pattern string
+		name    string
+	}{
+		{"github.com/sashabaranov/go-openai", "openai"},
+		{"github.com/openai/openai-go", "openai"},
+		{"github.com/anthropics/anthropic-sdk-go", "anthropic"},
+		{"github.com/ollama/ollama", "ollama"},
+	}
+
+	for _, req := range mod.Requires {
+		for _, llm := range llmPatterns {
+			if strings.HasPrefix(req, llm.pattern) && !containsService(libraries, llm.name) {
+				libraries = append(libraries, llm.name)
+				break
+			}
+		}
+	}
+
+	return libraries
+}
+
+// detectHTTPClients identifies HTTP client libraries from Go dependencies.
+// net/http is always available, so only client modules are a signal.
+func (d *GoDetector) detectHTTPClients(mod *goMod) []string {
+	var libraries []string
+
+	// HTTP client modules (module prefix -> library name)
+	httpPatterns := []struct {
+		pattern string
+		name    string
+	}{
+		{"github.com/go-resty/resty", "resty"},
+		{"resty.dev/v3", "resty"},
+		{"github.com/hashicorp/go-retryablehttp", "go-retryablehttp"},
+		{"github.com/imroc/req", "req"},
+	}
+
+	for _, req := range mod.Requires {
+		for _, client := range httpPatterns {
+			if strings.HasPrefix(req, client.pattern) && !containsService(libraries, client.name) {
+				libraries = append(libraries, client.name)
+				break
+			}
+		}
+	}
+
+	return libraries
+}
+
+// goGeoModules maps Go modules to the geospatial libraries they provide.
+var goGeoModules = map[string]string{
+	"github.com/twpayne/go-geom":      "go-geom",
+	"github.com/paulmach/orb":         "orb",
+	"github.com/cridenour/go-postgis": "go-postgis",
+	"github.com/lukeroth/gdal":        "gdal",
+	"github.com/airbusgeo/godal":      "godal",
+}
+
+// detectGeo identifies geospatial libraries and PostGIS adapters from Go dependencies.
+func (d *GoDetector) detectGeo(mod *goMod) []string {
+	var libraries []string
+
+	for _, req := range mod.Requires {
+		for module, library := range goGeoModules {
+			if strings.HasPrefix(req, module) && !containsService(libraries, library) {
+				libraries = append(libraries, library)
+			}
+		}
+	}
+
+	return libraries
+}
+
+// detectSearch identifies Meilisearch and Typesense clients from Go dependencies.
+func (d *GoDetector) detectSearch(mod *goMod) []string {
+	var libraries []string
+
+	// Search engine clients (module prefix -> library name)
+	searchPatterns := []struct {
+		pattern string
+		name    string
+	}{
+		{"github.com/meilisearch/meilisearch-go", "meilisearch-go"},
+		{"github.com/typesense/typesense-go", "typesense-go"},
+	}
+
+	for _, req := range mod.Requires {
+		for _, s := range searchPatterns {
+			if strings.HasPrefix(req, s.pattern) && !containsService(libraries, s.name) {
+				libraries = append(libraries, s.name)
+				break
+			}
+		}
+	}
+
+	return libraries
+}
+
+// detectMail identifies SMTP mail libraries from Go dependencies.
+func (d *GoDetector) detectMail(mod *goMod) []string {
+	var libraries []string
+
+	// SMTP mail modules (module prefix -> library name)
+	mailPatterns := []struct {
+		pattern string
+		name    string
+	}{
+		{"github.com/wneessen/go-mail", "go-mail"},
+		{"gopkg.in/gomail.v2", "gomail"},
+		{"github.com/go-gomail/gomail", "gomail"},
+		{"github.com/jordan-wright/email", "jordan-wright/email"},
+		{"github.com/xhit/go-simple-mail", "go-simple-mail"},
+	}
+
+	for _, req := range mod.Requires {
+		for _, m := range mailPatterns {
+			if strings.HasPrefix(req, m.pattern) && !containsService(libraries, m.name) {
+				libraries = append(libraries, m.name)
+				break
+			}
+		}
+	}
+
+	return libraries
+}
+
+// detectErrorTracking identifies Sentry SDKs from Go dependencies.
+func (d *GoDetector) detectErrorTracking(mod *goMod) []string {
+	var libraries []string
+
+	for _, req := range mod.Requires {
+		if strings.HasPrefix(req, "github.com/getsentry/sentry-go") && !containsService(libraries, "sentry-go") {
+			libraries = append(libraries, "sentry-go")
+		}
+	}
+
+	return libraries
+}
@@ -0,0 +1,99 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGRPCDetection tests gRPC library detection across languages.
+func TestGRPCDetection(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		wantLibs []string
+	}{
+		{
+			name:     "node grpc-js",
+			filename: "package.json",
+			content:  `{"name": "svc", "dependencies": {"@grpc/grpc-js": "^1.9.0", "@grpc/proto-loader": "^0.7.0"}}`,
+			wantLibs: []string{"@grpc/grpc-js"},
+		},
+		{
+			name:     "node without grpc",
+			filename: "package.json",
+			content:  `{"name": "api", "dependencies": {"express": "^4.18.0"}}`,
+			wantLibs: nil,
+		},
+		{
+			name:     "go grpc",
+			filename: "go.mod",
+			content: `module github.com/user/svc
+
+go 1.22
+
+require (
+	google.golang.org/grpc v1.62.0
+	google.golang.org/protobuf v1.33.0
+)
+`,
+			wantLibs: []string{"grpc-go"},
+		},
+		{
+			name:     "python grpcio",
+			filename: "requirements.txt",
+			content:  "grpcio>=1.62\ngrpcio-tools>=1.62\n",
+			wantLibs: []string{"grpcio"},
+		},
+		{
+			name:     "rust tonic",
+			filename: "Cargo.toml",
+			content: `[package]
+name = "svc"
+edition = "2021"
+
+[dependencies]
+tokio = "1"
+tonic = "0.11"
+prost = "0.12"
+`,
+			wantLibs: []string{"tonic"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-grpc-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			if err := os.WriteFile(filepath.Join(tmpDir, tt.filename), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.filename, err)
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+
+			if len(detection.GRPCLibraries) != len(tt.wantLibs) {
+				t.Errorf("GRPCLibraries = %v, want %v", detection.GRPCLibraries, tt.wantLibs)
+			}
+			for _, lib := range tt.wantLibs {
+				if !detection.HasGRPCLibrary(lib) {
+					t.Errorf("Expected gRPC library %q, got %v", lib, detection.GRPCLibraries)
+				}
+			}
+
+			if detection.NeedsGRPC() != (len(tt.wantLibs) > 0) {
+				t.Errorf("NeedsGRPC() = %v, want %v", detection.NeedsGRPC(), len(tt.wantLibs) > 0)
+			}
+		})
+	}
+}
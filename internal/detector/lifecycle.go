@@ -0,0 +1,170 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// packageManager is a package manager picked when its lockfile is present.
+type packageManager struct {
+	lockfile string
+	name     string
+	install  string
+}
+
+// packageManagers lists each language's package managers, in preference order.
+// The last entry of a language is used when no lockfile matches.
+var packageManagers = map[string][]packageManager{
+	"node": {
+		{"pnpm-lock.yaml", "pnpm", "pnpm install --frozen-lockfile"},
+		{"yarn.lock", "yarn", "yarn install --frozen-lockfile"},
+		{"package-lock.json", "npm", "npm ci"},
+		{"npm-shrinkwrap.json", "npm", "npm ci"},
+		{"", "npm", "npm install"},
+	},
+	"python": {
+		{"poetry.lock", "poetry", "poetry install"},
+		{"uv.lock", "uv", "uv sync"},
+		{"Pipfile.lock", "pipenv", "pipenv install --dev"},
+		{"requirements.txt", "pip", "pip install -r requirements.txt"},
+		{"", "pip", "pip install -e ."},
+	},
+	"go": {
+		{"", "go", "go mod download"},
+	},
+	"rust": {
+		{"", "cargo", "cargo fetch"},
+	},
+}
+
+// pythonRunners prefix Python commands so they run in the package manager's environment.
+var pythonRunners = map[string]string{
+	"poetry": "poetry run ",
+	"uv":     "uv run ",
+	"pipenv": "pipenv run ",
+}
+
+// migrationTool is a database migration tool, detected from a dependency
+// (matched as a prefix for Go module paths) and, when set, a marker file.
+type migrationTool struct {
+	dependency string
+	marker     string
+	name       string
+	command    string
+}
+
+// migrationTools lists each language's migration tools, in preference order.
+var migrationTools = map[string][]migrationTool{
+	"node": {
+		{"prisma", "prisma/schema.prisma", "prisma", "npx prisma migrate deploy"},
+		{"knex", "", "knex", "npx knex migrate:latest"},
+		{"sequelize-cli", "", "sequelize", "npx sequelize-cli db:migrate"},
+		{"node-pg-migrate", "", "node-pg-migrate", "npx node-pg-migrate up"},
+	},
+	"python": {
+		{"django", "manage.py", "django", "python manage.py migrate --noinput"},
+		{"alembic", "alembic.ini", "alembic", "alembic upgrade head"},
+	},
+	"go": {
+		{"github.com/golang-migrate/migrate", "", "golang-migrate", `migrate -path migrations -database "$DATABASE_URL" up`},
+		{"github.com/pressly/goose", "", "goose", `goose -dir migrations postgres "$DATABASE_URL" up`},
+	},
+	"rust": {
+		{"diesel", "", "diesel", "diesel migration run"},
+		{"sqlx", "migrations", "sqlx", "sqlx migrate run"},
+	},
+}
+
+// testRunner is a test framework, detected from a dependency (or a marker
+// file when dependency is empty), with its test and coverage commands.
+type testRunner struct {
+	dependency string
+	marker     string
+	test       string
+	coverage   string
+}
+
+// testRunners lists each language's test frameworks, in preference order.
+// The last entry of a language is used when none matches. An empty test command
+// runs the package manager's test script, as Node.js frameworks are run through package.json.
+var testRunners = map[string][]testRunner{
+	"node": {
+		{"vitest", "", "", "npx vitest run --coverage"},
+		{"jest", "", "", "npx jest --coverage"},
+		{"", "", "", ""},
+	},
+	"python": {
+		{"pytest-cov", "", "pytest", "pytest --cov --cov-report=term --cov-report=xml"},
+		{"pytest", "", "pytest", ""},
+		{"", "manage.py", "python manage.py test", ""},
+		{"", "", "python -m unittest", ""},
+	},
+	"go": {
+		{"", "", "go test ./...", "go test -coverprofile=coverage.out ./..."},
+	},
+	"rust": {
+		{"", "", "cargo test", ""},
+	},
+}
+
+// applyLifecycle sets the package manager, test runner, and migration tool,
+// which the generated lifecycle commands and test service run. Lockfiles are only
+// checked for existence, so this runs whether or not lockfile parsing is enabled.
+func applyLifecycle(detection *models.Detection, path string) {
+	for _, pm := range packageManagers[detection.Language] {
+		if pm.lockfile == "" || fileExists(filepath.Join(path, pm.lockfile)) {
+			detection.PackageManager = pm.name
+			detection.InstallCommand = pm.install
+			break
+		}
+	}
+
+	for _, runner := range testRunners[detection.Language] {
+		if runner.dependency != "" && !hasDependency(detection.Dependencies, runner.dependency) {
+			continue
+		}
+		if runner.marker != "" && !fileExists(filepath.Join(path, runner.marker)) {
+			continue
+		}
+		detection.TestCommand = pythonRunners[detection.PackageManager] + runner.test
+		if runner.test == "" {
+			detection.TestCommand = detection.PackageManager + " test"
+		}
+		if runner.coverage != "" {
+			detection.CoverageCommand = pythonRunners[detection.PackageManager] + runner.coverage
+		}
+		break
+	}
+
+	for _, tool := range migrationTools[detection.Language] {
+		if !hasDependency(detection.Dependencies, tool.dependency) {
+			continue
+		}
+		if tool.marker != "" && !fileExists(filepath.Join(path, tool.marker)) {
+			continue
+		}
+		detection.MigrationTool = tool.name
+		detection.MigrateCommand = pythonRunners[detection.PackageManager] + tool.command
+		break
+	}
+}
+
+// hasDependency reports whether deps includes dep, or a Go module under it
+// (e.g., "github.com/pressly/goose/v3" for "github.com/pressly/goose").
+func hasDependency(deps []string, dep string) bool {
+	for _, d := range deps {
+		if d == dep || strings.HasPrefix(d, dep+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// fileExists reports whether a file or directory exists.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
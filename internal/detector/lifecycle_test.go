@@ -0,0 +1,174 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLifecycleDetection tests package manager, test runner, and migration tool detection.
+func TestLifecycleDetection(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		wantManager string
+		wantInstall string
+		wantTool    string
+		wantMigrate string
+		wantTest    string
+		wantCover   string
+	}{
+		{
+			name: "npm with package-lock.json and prisma",
+			files: map[string]string{
+				"package.json":         `{"name": "api", "dependencies": {"@prisma/client": "^5.0.0"}, "devDependencies": {"prisma": "^5.0.0"}}`,
+				"package-lock.json":    `{"lockfileVersion": 3}`,
+				"prisma/schema.prisma": "datasource db {}\n",
+			},
+			wantManager: "npm",
+			wantInstall: "npm ci",
+			wantTool:    "prisma",
+			wantMigrate: "npx prisma migrate deploy",
+			wantTest:    "npm test",
+		},
+		{
+			name: "pnpm wins over package-lock.json",
+			files: map[string]string{
+				"package.json":      `{"name": "api", "dependencies": {"knex": "^3.0.0", "pg": "^8.0.0"}}`,
+				"package-lock.json": `{"lockfileVersion": 3}`,
+				"pnpm-lock.yaml":    "lockfileVersion: '9.0'\n",
+			},
+			wantManager: "pnpm",
+			wantInstall: "pnpm install --frozen-lockfile",
+			wantTool:    "knex",
+			wantMigrate: "npx knex migrate:latest",
+			wantTest:    "pnpm test",
+		},
+		{
+			name: "prisma without a schema is not a migration tool",
+			files: map[string]string{
+				"package.json": `{"name": "api", "dependencies": {"prisma": "^5.0.0"}}`,
+			},
+			wantManager: "npm",
+			wantInstall: "npm install",
+			wantTest:    "npm test",
+		},
+		{
+			name: "poetry with alembic runs in the poetry environment",
+			files: map[string]string{
+				"pyproject.toml": "[tool.poetry]\nname = \"api\"\n\n[tool.poetry.dependencies]\npython = \"^3.12\"\nalembic = \"^1.13\"\nsqlalchemy = \"^2.0\"\n",
+				"poetry.lock":    "",
+				"alembic.ini":    "[alembic]\n",
+			},
+			wantManager: "poetry",
+			wantInstall: "poetry install",
+			wantTool:    "alembic",
+			wantMigrate: "poetry run alembic upgrade head",
+			wantTest:    "poetry run python -m unittest",
+		},
+		{
+			name: "pip with django",
+			files: map[string]string{
+				"requirements.txt": "django>=5.0\npsycopg2-binary\n",
+				"manage.py":        "",
+			},
+			wantManager: "pip",
+			wantInstall: "pip install -r requirements.txt",
+			wantTool:    "django",
+			wantMigrate: "python manage.py migrate --noinput",
+			wantTest:    "python manage.py test",
+		},
+		{
+			name: "go with goose",
+			files: map[string]string{
+				"go.mod": "module github.com/user/app\n\ngo 1.22\n\nrequire (\n\tgithub.com/lib/pq v1.10.9\n\tgithub.com/pressly/goose/v3 v3.20.0\n)\n",
+			},
+			wantManager: "go",
+			wantInstall: "go mod download",
+			wantTool:    "goose",
+			wantMigrate: `goose -dir migrations postgres "$DATABASE_URL" up`,
+			wantTest:    "go test ./...",
+			wantCover:   "go test -coverprofile=coverage.out ./...",
+		},
+		{
+			name: "cargo with sqlx migrations",
+			files: map[string]string{
+				"Cargo.toml":               "[package]\nname = \"app\"\nedition = \"2021\"\n\n[dependencies]\nsqlx = \"0.7\"\n",
+				"migrations/0001_init.sql": "",
+			},
+			wantManager: "cargo",
+			wantInstall: "cargo fetch",
+			wantTool:    "sqlx",
+			wantMigrate: "sqlx migrate run",
+			wantTest:    "cargo test",
+		},
+		{
+			name: "yarn with vitest coverage",
+			files: map[string]string{
+				"package.json": `{"name": "web", "devDependencies": {"vitest": "^2.0.0"}}`,
+				"yarn.lock":    "",
+			},
+			wantManager: "yarn",
+			wantInstall: "yarn install --frozen-lockfile",
+			wantTest:    "yarn test",
+			wantCover:   "npx vitest run --coverage",
+		},
+		{
+			name: "pip with pytest-cov",
+			files: map[string]string{
+				"requirements.txt": "fastapi\npytest\npytest-cov\n",
+			},
+			wantManager: "pip",
+			wantInstall: "pip install -r requirements.txt",
+			wantTest:    "pytest",
+			wantCover:   "pytest --cov --cov-report=term --cov-report=xml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-lifecycle-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			for name, content := range tt.files {
+				path := filepath.Join(tmpDir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatalf("Failed to create directory for %s: %v", name, err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write %s: %v", name, err)
+				}
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+
+			if detection.PackageManager != tt.wantManager {
+				t.Errorf("PackageManager = %q, want %q", detection.PackageManager, tt.wantManager)
+			}
+			if detection.InstallCommand != tt.wantInstall {
+				t.Errorf("InstallCommand = %q, want %q", detection.InstallCommand, tt.wantInstall)
+			}
+			if detection.MigrationTool != tt.wantTool {
+				t.Errorf("MigrationTool = %q, want %q", detection.MigrationTool, tt.wantTool)
+			}
+			if detection.MigrateCommand != tt.wantMigrate {
+				t.Errorf("MigrateCommand = %q, want %q", detection.MigrateCommand, tt.wantMigrate)
+			}
+			if detection.TestCommand != tt.wantTest {
+				t.Errorf("TestCommand = %q, want %q", detection.TestCommand, tt.wantTest)
+			}
+			if detection.CoverageCommand != tt.wantCover {
+				t.Errorf("CoverageCommand = %q, want %q", detection.CoverageCommand, tt.wantCover)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLLMDetection tests LLM client library detection across languages.
+func TestLLMDetection(t *testing.T) {
+	tests := []struct {
+		name         string
+		filename     string
+		content      string
+		wantLibs     []string
+		wantLocalLLM bool
+	}{
+		{
+			name:         "node openai and anthropic",
+			filename:     "package.json",
+			content:      `{"name": "chat", "dependencies": {"openai": "^4.0.0", "@anthropic-ai/sdk": "^0.20.0"}}`,
+			wantLibs:     []string{"openai", "anthropic"},
+			wantLocalLLM: false,
+		},
+		{
+			name:         "node ollama client enables local llm",
+			filename:     "package.json",
+			content:      `{"name": "chat", "dependencies": {"ollama": "^0.5.0"}}`,
+			wantLibs:     []string{"ollama"},
+			wantLocalLLM: true,
+		},
+		{
+			name:         "python langchain integrations map to providers",
+			filename:     "requirements.txt",
+			content:      "langchain-openai\nlangchain-ollama\n",
+			wantLibs:     []string{"openai", "ollama"},
+			wantLocalLLM: true,
+		},
+		{
+			name:     "go openai",
+			filename: "go.mod",
+			content: `module github.com/user/chat
+
+go 1.22
+
+require (
+	github.com/sashabaranov/go-openai v1.20.0
+)
+`,
+			wantLibs:     []string{"openai"},
+			wantLocalLLM: false,
+		},
+		{
+			name:     "rust ollama-rs",
+			filename: "Cargo.toml",
+			content: `[package]
+name = "chat"
+edition = "2021"
+
+[dependencies]
+ollama-rs = "0.1"
+`,
+			wantLibs:     []string{"ollama"},
+			wantLocalLLM: true,
+		},
+		{
+			name:         "no llm clients",
+			filename:     "requirements.txt",
+			content:      "flask\n",
+			wantLibs:     nil,
+			wantLocalLLM: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-llm-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			if err := os.WriteFile(filepath.Join(tmpDir, tt.filename), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.filename, err)
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+
+			if len(detection.LLMLibraries) != len(tt.wantLibs) {
+				t.Errorf("LLMLibraries = %v, want %v", detection.LLMLibraries, tt.wantLibs)
+			}
+			for _, lib := range tt.wantLibs {
+				if !detection.HasLLMLibrary(lib) {
+					t.Errorf("Expected LLM library %q, got %v", lib, detection.LLMLibraries)
+				}
+			}
+
+			if detection.NeedsOllama() != tt.wantLocalLLM {
+				t.Errorf("NeedsOllama() = %v, want %v", detection.NeedsOllama(), tt.wantLocalLLM)
+			}
+		})
+	}
+}
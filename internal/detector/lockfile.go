@@ -0,0 +1,245 @@
+package detector
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Lockfile names read by each detector, in preference order.
+var (
+	nodeLockfiles   = []string{"package-lock.json", "yarn.lock"}
+	goLockfiles     = []string{"go.sum"}
+	pythonLockfiles = []string{"poetry.lock"}
+	rustLockfiles   = []string{"Cargo.lock"}
+)
+
+// LockfileReader is implemented by detectors that can refine dependency
+// detection from lockfiles. Parsing lockfiles is opt-in because they can be
+// many times larger than the manifest.
+type LockfileReader interface {
+	// EnableLockfiles makes the detector read lockfiles on later Detect calls.
+	EnableLockfiles()
+}
+
+// lockedPackages maps package names to their resolved versions.
+type lockedPackages map[string]string
+
+// readLockfile parses the first lockfile from names that exists in projectPath.
+// Returns the lockfile name and its packages, or an empty name and nil if none
+// could be read.
+func readLockfile(projectPath string, names []string) (string, lockedPackages) {
+	for _, name := range names {
+		path := filepath.Join(projectPath, name)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+
+		var locked lockedPackages
+		var err error
+		switch name {
+		case "package-lock.json":
+			locked, err = parsePackageLock(path)
+		case "yarn.lock":
+			locked, err = parseYarnLock(path)
+		case "go.sum":
+			locked, err = parseGoSum(path)
+		case "poetry.lock", "Cargo.lock":
+			locked, err = parseTOMLLock(path)
+		}
+		if err != nil || len(locked) == 0 {
+			continue
+		}
+		return name, locked
+	}
+
+	return "", nil
+}
+
+// parsePackageLock reads the hoisted packages from an npm package-lock.json.
+// Lockfile v2/v3 lists them under "packages" as "node_modules/<name>"; v1 lists
+// them under "dependencies".
+func parsePackageLock(path string) (lockedPackages, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	type lockedPackage struct {
+		Version string `json:"version"`
+	}
+	var lock struct {
+		Packages     map[string]lockedPackage `json:"packages"`
+		Dependencies map[string]lockedPackage `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	locked := make(lockedPackages)
+	for key, pkg := range lock.Packages {
+		name, ok := strings.CutPrefix(key, "node_modules/")
+		// Nested node_modules hold conflicting versions, not hoisted packages
+		if !ok || strings.Contains(name, "/node_modules/") || pkg.Version == "" {
+			continue
+		}
+		locked[name] = pkg.Version
+	}
+	if len(locked) == 0 {
+		for name, pkg := range lock.Dependencies {
+			if pkg.Version != "" {
+				locked[name] = pkg.Version
+			}
+		}
+	}
+
+	return locked, nil
+}
+
+// parseYarnLock reads packages from a yarn.lock (classic or Berry format).
+// Each entry starts with an unindented line of descriptors such as
+// `"express@^4.18.0", express@^4.17.1:` followed by an indented version line.
+func parseYarnLock(path string) (lockedPackages, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	locked := make(lockedPackages)
+	current := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if !strings.HasPrefix(line, " ") {
+			current = yarnDescriptorName(strings.TrimSuffix(trimmed, ":"))
+			continue
+		}
+
+		if current == "" {
+			continue
+		}
+		if version, ok := strings.CutPrefix(trimmed, "version"); ok {
+			version = strings.Trim(strings.TrimSpace(strings.TrimPrefix(version, ":")), `"`)
+			if version != "" {
+				locked[current] = version
+			}
+			current = ""
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return locked, nil
+}
+
+// yarnDescriptorName extracts the package name from a yarn.lock entry header,
+// e.g. `"@types/node@npm:^20.0.0", "@types/node@^20"` -> "@types/node".
+func yarnDescriptorName(header string) string {
+	first, _, _ := strings.Cut(header, ",")
+	first = strings.Trim(strings.TrimSpace(first), `"`)
+	if first == "__metadata" {
+		return ""
+	}
+
+	// Skip the leading @ of scoped packages when looking for the version separator
+	at := strings.LastIndex(first, "@")
+	if at <= 0 {
+		return ""
+	}
+	return first[:at]
+}
+
+// parseGoSum reads module versions from go.sum. Lines look like
+// "<module> <version>[/go.mod] <hash>"; go.sum is sorted by version, so the
+// last version listed for a module is the highest.
+func parseGoSum(path string) (lockedPackages, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	locked := make(lockedPackages)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		locked[fields[0]] = strings.TrimSuffix(fields[1], "/go.mod")
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return locked, nil
+}
+
+// parseTOMLLock reads packages from lockfiles made of [[package]] tables with
+// name and version keys (poetry.lock, Cargo.lock).
+func parseTOMLLock(path string) (lockedPackages, error) {
+	var lock struct {
+		Package []struct {
+			Name    string `toml:"name"`
+			Version string `toml:"version"`
+		} `toml:"package"`
+	}
+	if _, err := toml.DecodeFile(path, &lock); err != nil {
+		return nil, err
+	}
+
+	locked := make(lockedPackages)
+	for _, pkg := range lock.Package {
+		if pkg.Name != "" {
+			locked[pkg.Name] = pkg.Version
+		}
+	}
+	return locked, nil
+}
+
+// mergeLocked appends locked packages that deps doesn't list yet (transitive
+// dependencies) and returns the locked versions of the packages deps already
+// listed. normalize maps both sides to a comparable name.
+func mergeLocked(deps []string, locked lockedPackages, normalize func(string) string) ([]string, map[string]string) {
+	listed := make(map[string]string, len(deps))
+	for _, dep := range deps {
+		listed[normalize(dep)] = dep
+	}
+
+	names := make([]string, 0, len(locked))
+	for name := range locked {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pinned := make(map[string]string)
+	for _, name := range names {
+		if dep, ok := listed[normalize(name)]; ok {
+			pinned[dep] = locked[name]
+			continue
+		}
+		deps = append(deps, name)
+	}
+
+	return deps, pinned
+}
+
+// lockfileInputs returns the lockfile cache inputs for a detector.
+func lockfileInputs(enabled bool, names []string) []string {
+	if !enabled {
+		return nil
+	}
+	return names
+}
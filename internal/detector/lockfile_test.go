@@ -0,0 +1,304 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestReadLockfile tests parsing each supported lockfile format.
+func TestReadLockfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		names    []string
+		want     map[string]string
+		wantNone []string
+	}{
+		{
+			name: "package-lock.json v3 hoisted packages",
+			file: "package-lock.json",
+			content: `{
+				"lockfileVersion": 3,
+				"packages": {
+					"": {"name": "app"},
+					"node_modules/express": {"version": "4.18.2"},
+					"node_modules/@types/node": {"version": "20.11.5"},
+					"node_modules/express/node_modules/debug": {"version": "2.6.9"}
+				}
+			}`,
+			names:    nodeLockfiles,
+			want:     map[string]string{"express": "4.18.2", "@types/node": "20.11.5"},
+			wantNone: []string{"debug", "express/node_modules/debug"},
+		},
+		{
+			name: "package-lock.json v1",
+			file: "package-lock.json",
+			content: `{
+				"lockfileVersion": 1,
+				"dependencies": {"pino": {"version": "8.17.2"}}
+			}`,
+			names: nodeLockfiles,
+			want:  map[string]string{"pino": "8.17.2"},
+		},
+		{
+			name: "yarn.lock classic",
+			file: "yarn.lock",
+			content: `# THIS IS AN AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.
+# yarn lockfile v1
+
+
+"@babel/code-frame@^7.0.0", "@babel/code-frame@^7.10.4":
+  version "7.23.5"
+  resolved "https://registry.yarnpkg.com/@babel/code-frame/-/code-frame-7.23.5.tgz"
+
+express@^4.18.0:
+  version "4.18.2"
+  dependencies:
+    debug "2.6.9"
+`,
+			names:    nodeLockfiles,
+			want:     map[string]string{"@babel/code-frame": "7.23.5", "express": "4.18.2"},
+			wantNone: []string{"debug"},
+		},
+		{
+			name: "yarn.lock berry",
+			file: "yarn.lock",
+			content: `__metadata:
+  version: 8
+
+"express@npm:^4.18.0":
+  version: 4.18.2
+  resolution: "express@npm:4.18.2"
+`,
+			names:    nodeLockfiles,
+			want:     map[string]string{"express": "4.18.2"},
+			wantNone: []string{"__metadata"},
+		},
+		{
+			name: "go.sum keeps the highest version",
+			file: "go.sum",
+			content: `github.com/lib/pq v1.10.7/go.mod h1:AlVN5x4E4T544tWzH6hKfbfQvm3HdbOxrmggDNAPY9o=
+github.com/lib/pq v1.10.9 h1:YXG7RB+JIjhP29X+OtkiDnYaXQwpS4JEWq7dtCCRUEw=
+github.com/lib/pq v1.10.9/go.mod h1:AlVN5x4E4T544tWzH6hKfbfQvm3HdbOxrmggDNAPY9o=
+`,
+			names: goLockfiles,
+			want:  map[string]string{"github.com/lib/pq": "v1.10.9"},
+		},
+		{
+			name: "poetry.lock",
+			file: "poetry.lock",
+			content: `[[package]]
+name = "celery"
+version = "5.3.6"
+
+[[package]]
+name = "kombu"
+version = "5.3.4"
+`,
+			names: pythonLockfiles,
+			want:  map[string]string{"celery": "5.3.6", "kombu": "5.3.4"},
+		},
+		{
+			name: "Cargo.lock",
+			file: "Cargo.lock",
+			content: `version = 3
+
+[[package]]
+name = "tokio"
+version = "1.35.1"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`,
+			names: rustLockfiles,
+			want:  map[string]string{"tokio": "1.35.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-lockfile-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			if err := os.WriteFile(filepath.Join(tmpDir, tt.file), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.file, err)
+			}
+
+			name, locked := readLockfile(tmpDir, tt.names)
+			if name != tt.file {
+				t.Errorf("readLockfile() name = %q, want %q", name, tt.file)
+			}
+			for pkg, version := range tt.want {
+				if locked[pkg] != version {
+					t.Errorf("locked[%q] = %q, want %q", pkg, locked[pkg], version)
+				}
+			}
+			for _, pkg := range tt.wantNone {
+				if _, ok := locked[pkg]; ok {
+					t.Errorf("locked[%q] should not be set", pkg)
+				}
+			}
+		})
+	}
+}
+
+// TestLockfileDetection tests that lockfiles refine detection only when enabled.
+func TestLockfileDetection(t *testing.T) {
+	tests := []struct {
+		name         string
+		detector     func() Detector
+		files        map[string]string
+		wantLockfile string
+		wantPinned   map[string]string
+		wantDeps     []string
+		wantLibrary  string
+	}{
+		{
+			name:     "node transitive logger and pinned express",
+			detector: func() Detector { return NewNodeDetector() },
+			files: map[string]string{
+				"package.json":      `{"name": "api", "dependencies": {"express": "^4.18.0", "@company/logging": "^1.0.0"}}`,
+				"package-lock.json": `{"lockfileVersion": 3, "packages": {"node_modules/express": {"version": "4.18.2"}, "node_modules/@company/logging": {"version": "1.2.0"}, "node_modules/pino": {"version": "8.17.2"}}}`,
+			},
+			wantLockfile: "package-lock.json",
+			wantPinned:   map[string]string{"express": "4.18.2", "@company/logging": "1.2.0"},
+			wantDeps:     []string{"@company/logging", "express"},
+			wantLibrary:  "pino",
+		},
+		{
+			name:     "python names normalized against poetry.lock",
+			detector: func() Detector { return NewPythonDetector() },
+			files: map[string]string{
+				"pyproject.toml": "[tool.poetry]\nname = \"app\"\n\n[tool.poetry.dependencies]\npython = \"^3.11\"\nFlask_Login = \"^0.6\"\nmytasks = \"^1.0\"\n",
+				"poetry.lock":    "[[package]]\nname = \"flask-login\"\nversion = \"0.6.3\"\n\n[[package]]\nname = \"mytasks\"\nversion = \"1.0.0\"\n\n[[package]]\nname = \"structlog\"\nversion = \"24.1.0\"\n",
+			},
+			wantLockfile: "poetry.lock",
+			wantPinned:   map[string]string{"Flask_Login": "0.6.3", "mytasks": "1.0.0"},
+			wantDeps:     []string{"Flask_Login", "mytasks"},
+			wantLibrary:  "structlog",
+		},
+		{
+			name:     "rust transitive crate",
+			detector: func() Detector { return NewRustDetector() },
+			files: map[string]string{
+				"Cargo.toml": "[package]\nname = \"app\"\nedition = \"2021\"\n\n[dependencies]\ntokio = \"1\"\n",
+				"Cargo.lock": "version = 3\n\n[[package]]\nname = \"app\"\nversion = \"0.1.0\"\n\n[[package]]\nname = \"tokio\"\nversion = \"1.35.1\"\n\n[[package]]\nname = \"tracing\"\nversion = \"0.1.40\"\n",
+			},
+			wantLockfile: "Cargo.lock",
+			wantPinned:   map[string]string{"tokio": "1.35.1"},
+			wantDeps:     []string{"tokio"},
+			wantLibrary:  "tracing",
+		},
+		{
+			name:     "go pins required modules only",
+			detector: func() Detector { return NewGoDetector() },
+			files: map[string]string{
+				"go.mod": "module github.com/user/app\n\ngo 1.22\n\nrequire github.com/lib/pq v1.10.9\n",
+				"go.sum": "github.com/lib/pq v1.10.9 h1:abc=\ngo.uber.org/zap v1.26.0 h1:def=\n",
+			},
+			wantLockfile: "go.sum",
+			wantPinned:   map[string]string{"github.com/lib/pq": "v1.10.9"},
+			wantDeps:     []string{"github.com/lib/pq"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-lockfile-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write %s: %v", name, err)
+				}
+			}
+
+			// Disabled: the lockfile is ignored
+			detection, err := tt.detector().Detect(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection.Lockfile != "" || detection.PinnedVersions != nil {
+				t.Errorf("lockfile used while disabled: %q %v", detection.Lockfile, detection.PinnedVersions)
+			}
+			if tt.wantLibrary != "" && containsService(detectedLibraries(detection.LoggingLibraries, detection.TracingLibraries), tt.wantLibrary) {
+				t.Errorf("%s detected without lockfile", tt.wantLibrary)
+			}
+
+			d := tt.detector()
+			d.(LockfileReader).EnableLockfiles()
+			detection, err = d.Detect(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection.Lockfile != tt.wantLockfile {
+				t.Errorf("Lockfile = %q, want %q", detection.Lockfile, tt.wantLockfile)
+			}
+			if len(detection.PinnedVersions) != len(tt.wantPinned) {
+				t.Errorf("PinnedVersions = %v, want %v", detection.PinnedVersions, tt.wantPinned)
+			}
+			for dep, version := range tt.wantPinned {
+				if detection.PinnedVersions[dep] != version {
+					t.Errorf("PinnedVersions[%q] = %q, want %q", dep, detection.PinnedVersions[dep], version)
+				}
+			}
+			// Transitive packages are not the manifest's dependencies
+			if strings.Join(detection.Dependencies, " ") != strings.Join(tt.wantDeps, " ") {
+				t.Errorf("Dependencies = %v, want %v", detection.Dependencies, tt.wantDeps)
+			}
+			if tt.wantLibrary != "" && !containsService(detectedLibraries(detection.LoggingLibraries, detection.TracingLibraries), tt.wantLibrary) {
+				t.Errorf("%s not detected from lockfile (logging: %v, tracing: %v)",
+					tt.wantLibrary, detection.LoggingLibraries, detection.TracingLibraries)
+			}
+		})
+	}
+}
+
+// TestLockfileKeepsFrameworkFromManifest tests that transitive packages don't
+// change the project shape: CRA pulls in express through webpack-dev-server.
+func TestLockfileKeepsFrameworkFromManifest(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "dockstart-lockfile-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	files := map[string]string{
+		"package.json":      `{"name": "web", "dependencies": {"react-scripts": "5.0.1", "react": "^18.0.0"}}`,
+		"package-lock.json": `{"lockfileVersion": 3, "packages": {"node_modules/react-scripts": {"version": "5.0.1"}, "node_modules/express": {"version": "4.18.2"}}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	d := NewNodeDetector()
+	d.EnableLockfiles()
+	detection, err := d.Detect(tmpDir)
+	if err != nil {
+		t.Fatalf("Detection failed: %v", err)
+	}
+	if detection.NeedsWebService() {
+		t.Error("transitive express should not split the frontend into its own service")
+	}
+	if !detection.Auxiliary {
+		t.Error("a CRA app should stay auxiliary when its lockfile contains express")
+	}
+}
+
+// detectedLibraries joins library lists for assertions.
+func detectedLibraries(lists ...[]string) []string {
+	var all []string
+	for _, list := range lists {
+		all = append(all, list...)
+	}
+	return all
+}
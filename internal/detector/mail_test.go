@@ -0,0 +1,83 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestMailDetection tests SMTP mail library detection across languages.
+func TestMailDetection(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		wantLibs []string
+	}{
+		{
+			name:     "node nodemailer",
+			filename: "package.json",
+			content:  `{"name": "shop", "dependencies": {"express": "^4.18.0", "nodemailer": "^6.9.0"}}`,
+			wantLibs: []string{"nodemailer"},
+		},
+		{
+			name:     "python fastapi-mail",
+			filename: "requirements.txt",
+			content:  "fastapi\nfastapi_mail==1.4.1\n",
+			wantLibs: []string{"fastapi-mail"},
+		},
+		{
+			name:     "go gomail",
+			filename: "go.mod",
+			content: `module github.com/user/shop
+
+go 1.22
+
+require (
+	gopkg.in/gomail.v2 v2.0.0-20160411212932-81ebce5c23df
+)
+`,
+			wantLibs: []string{"gomail"},
+		},
+		{
+			name:     "rust lettre",
+			filename: "Cargo.toml",
+			content: `[package]
+name = "shop"
+edition = "2021"
+
+[dependencies]
+lettre = "0.11"
+`,
+			wantLibs: []string{"lettre"},
+		},
+		{
+			name:     "no mail library",
+			filename: "requirements.txt",
+			content:  "flask\n",
+			wantLibs: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, tt.filename), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.filename, err)
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+
+			if !reflect.DeepEqual(detection.MailLibraries, tt.wantLibs) {
+				t.Errorf("MailLibraries = %v, want %v", detection.MailLibraries, tt.wantLibs)
+			}
+		})
+	}
+}
@@ -11,7 +11,10 @@ import (
 )
 
 // NodeDetector detects Node.js projects by analyzing package.json.
-type NodeDetector struct{}
+type NodeDetector struct {
+	// lockfiles enables refining dependencies from package-lock.json or yarn.lock
+	lockfiles bool
+}
 
 // NewNodeDetector creates a new Node.js detector.
 func NewNodeDetector() *NodeDetector {
@@ -23,6 +26,17 @@ func (d *NodeDetector) Name() string {
 	return "node"
 }
 
+// Inputs returns the files and directories the Node.js detector reads.
+func (d *NodeDetector) Inputs(path string) []string {
+	inputs := append([]string{"package.json", "tsconfig.json"}, lockfileInputs(d.lockfiles, nodeLockfiles)...)
+	return append(inputs, uploadInputs(path, []string{"package.json"}, nodeUploadDirs)...)
+}
+
+// EnableLockfiles makes the detector read package-lock.json or yarn.lock.
+func (d *NodeDetector) EnableLockfiles() {
+	d.lockfiles = true
+}
+
 // packageJSON represents the structure of a package.json file.
 // We only parse the fields we care about.
 type packageJSON struct {
@@ -37,6 +51,14 @@ type engines struct {
 	Node string `json:"node"`
 }
 
+// tsConfig represents the structure of a tsconfig.json file.
+// We only parse the fields we care about.
+type tsConfig struct {
+	CompilerOptions struct {
+		OutDir string `json:"outDir"`
+	} `json:"compilerOptions"`
+}
+
 // Detect analyzes the path for a Node.js project.
 // It looks for package.json and extracts version and service information.
 func (d *NodeDetector) Detect(path string) (*models.Detection, error) {
@@ -58,33 +80,132 @@ func (d *NodeDetector) Detect(path string) (*models.Detection, error) {
 		return nil, err
 	}
 
-	loggingLibs, logFormat := d.detectLogging(pkg)
-	queueLibs, workerCmd := d.detectQueue(pkg)
-	uploadLibs, uploadPath := d.detectFileUpload(pkg, path)
-	metricsLibs, metricsPort, metricsPath := d.detectMetrics(pkg)
-	tracingLibs, tracingProtocol := d.detectTracing(pkg)
+	// Locked packages refine library detection only; frameworks and project
+	// shape come from package.json, since tooling pulls in servers like express
+	libs := pkg
+	var lockfile string
+	var pinned map[string]string
+	if d.lockfiles {
+		libs, lockfile, pinned = d.applyLockfile(pkg, path)
+	}
+
+	isTypeScript, buildCmd, buildOutputDir, devRunner := d.detectTypeScript(pkg, path)
+	loggingLibs, logFormat := d.detectLogging(libs)
+	queueLibs, workerCmd := d.detectQueue(libs)
+	uploadLibs, uploadPath := d.detectFileUpload(libs, path)
+	metricsLibs, metricsPort, metricsPath := d.detectMetrics(libs)
+	tracingLibs, tracingProtocol := d.detectTracing(libs)
+	frontendFramework, frontendDir, frontendPort := d.detectFrontend(pkg)
+	grpcLibs := d.detectGRPC(libs)
+	authLibs := d.detectAuth(libs)
+	paymentLibs := d.detectPayments(libs)
+	webDriverLibs := d.detectWebDriver(libs)
+	awsServices := d.detectAWSServices(libs)
+	vectorLibs := d.detectVectorStores(libs)
+	llmLibs := d.detectLLM(libs)
+	httpLibs := d.detectHTTPClients(libs)
+	errorTrackingLibs := d.detectErrorTracking(libs)
+	geoLibs := d.detectGeo(libs)
+	searchLibs := d.detectSearch(libs)
+	mailLibs := d.detectMail(libs)
+	websocketLibs := d.detectWebsockets(libs)
+	schedulerLibs, schedulerCmd := d.detectScheduler(libs)
+
+	// TypeScript workers without an explicit worker script must point at
+	// the compiled output (or a TS runner) rather than a nonexistent worker.js
+	if isTypeScript && workerCmd == defaultNodeWorkerCommand {
+		workerCmd = d.typeScriptWorkerCommand(buildCmd, buildOutputDir, devRunner)
+	}
 
 	detection := &models.Detection{
-		Language:            "node",
-		Version:             d.extractVersion(pkg),
-		Services:            d.detectServices(pkg),
-		Confidence:          d.calculateConfidence(pkg),
-		LoggingLibraries:    loggingLibs,
-		LogFormat:           logFormat,
-		QueueLibraries:      queueLibs,
-		WorkerCommand:       workerCmd,
-		FileUploadLibraries: uploadLibs,
-		UploadPath:          uploadPath,
-		MetricsLibraries:    metricsLibs,
-		MetricsPort:         metricsPort,
-		MetricsPath:         metricsPath,
-		TracingLibraries:    tracingLibs,
-		TracingProtocol:     tracingProtocol,
+		Language:   "node",
+		Version:    d.extractVersion(pkg),
+		Services:   d.detectServices(libs),
+		Confidence: d.calculateConfidence(pkg),
+		Capabilities: models.Capabilities{
+			LoggingLibraries:       loggingLibs,
+			QueueLibraries:         queueLibs,
+			FileUploadLibraries:    uploadLibs,
+			MetricsLibraries:       metricsLibs,
+			TracingLibraries:       tracingLibs,
+			WebsocketLibraries:     websocketLibs,
+			GRPCLibraries:          grpcLibs,
+			AuthLibraries:          authLibs,
+			PaymentLibraries:       paymentLibs,
+			WebDriverLibraries:     webDriverLibs,
+			AWSServices:            awsServices,
+			VectorLibraries:        vectorLibs,
+			LLMLibraries:           llmLibs,
+			HTTPClientLibraries:    httpLibs,
+			ErrorTrackingLibraries: errorTrackingLibs,
+			GeoLibraries:           geoLibs,
+			SearchLibraries:        searchLibs,
+			MailLibraries:          mailLibs,
+			SchedulerLibraries:     schedulerLibs,
+		},
+		LogFormat:         logFormat,
+		WorkerCommand:     workerCmd,
+		UploadPath:        uploadPath,
+		MetricsPort:       metricsPort,
+		MetricsPath:       metricsPath,
+		TracingProtocol:   tracingProtocol,
+		TypeScript:        isTypeScript,
+		BuildCommand:      buildCmd,
+		BuildOutputDir:    buildOutputDir,
+		DevRunner:         devRunner,
+		FrontendFramework: frontendFramework,
+		FrontendDir:       frontendDir,
+		FrontendPort:      frontendPort,
+		Auxiliary:         d.isAuxiliary(pkg, frontendFramework, frontendDir),
+		Lockfile:          lockfile,
+		PinnedVersions:    pinned,
+		Dependencies:      d.directDependencies(pkg),
+		LocalLLM:          containsService(llmLibs, "ollama"),
+		SchedulerCommand:  schedulerCmd,
 	}
 
 	return detection, nil
 }
 
+// applyLockfile returns a copy of pkg with locked packages the manifest doesn't list
+// added as devDependencies, the lockfile name, and the locked versions of the
+// manifest's own dependencies. pkg is returned unchanged if no lockfile is found.
+func (d *NodeDetector) applyLockfile(pkg packageJSON, path string) (packageJSON, string, map[string]string) {
+	lockfile, locked := readLockfile(path, nodeLockfiles)
+	if locked == nil {
+		return pkg, "", nil
+	}
+
+	manifest := mergeDeps(pkg)
+	deps := make([]string, 0, len(manifest))
+	for dep := range manifest {
+		deps = append(deps, dep)
+	}
+	deps, pinned := mergeLocked(deps, locked, strings.ToLower)
+
+	libs := pkg
+	libs.DevDependencies = make(map[string]string, len(pkg.DevDependencies)+len(deps))
+	for k, v := range pkg.DevDependencies {
+		libs.DevDependencies[k] = v
+	}
+	for _, dep := range deps {
+		if _, listed := manifest[dep]; !listed {
+			libs.DevDependencies[dep] = locked[dep]
+		}
+	}
+
+	return libs, lockfile, pinned
+}
+
+// directDependencies returns the packages package.json lists, sorted.
+func (d *NodeDetector) directDependencies(pkg packageJSON) []string {
+	deps := make([]string, 0, len(pkg.Dependencies)+len(pkg.DevDependencies))
+	for dep := range mergeDeps(pkg) {
+		deps = append(deps, dep)
+	}
+	return directDependencies(deps)
+}
+
 // extractVersion extracts the Node.js version from package.json.
 // Priority: engines.node > inferred from dependencies > default
 func (d *NodeDetector) extractVersion(pkg packageJSON) string {
@@ -97,12 +218,14 @@ func (d *NodeDetector) extractVersion(pkg packageJSON) string {
 	return "20"
 }
 
+// majorNumberRe matches the first number in a semver constraint.
+var majorNumberRe = regexp.MustCompile(`\d+`)
+
 // parseVersionConstraint extracts the major version from a semver constraint.
 // Examples: ">=18" -> "18", "^20.0.0" -> "20", "20.x" -> "20"
 func parseVersionConstraint(constraint string) string {
 	// Match the first number in the constraint
-	re := regexp.MustCompile(`\d+`)
-	match := re.FindString(constraint)
+	match := majorNumberRe.FindString(constraint)
 	if match != "" {
 		return match
 	}
@@ -134,6 +257,30 @@ func (d *NodeDetector) detectServices(pkg packageJSON) []string {
 		services = append(services, "redis")
 	}
 
+	// ClickHouse indicators
+	clickhousePackages := []string{"@clickhouse/client", "@clickhouse/client-web", "clickhouse"}
+	if hasAnyDep(allDeps, clickhousePackages) {
+		services = append(services, "clickhouse")
+	}
+
+	// Memcached indicators
+	memcachedPackages := []string{"memcached", "memjs", "memcache-client"}
+	if hasAnyDep(allDeps, memcachedPackages) {
+		services = append(services, "memcached")
+	}
+
+	// NATS indicators
+	natsPackages := []string{"nats", "nats.ws", "@nats-io/transport-node", "@nats-io/nats-core", "@nats-io/jetstream"}
+	if hasAnyDep(allDeps, natsPackages) {
+		services = append(services, "nats")
+	}
+
+	// InfluxDB indicators
+	influxPackages := []string{"@influxdata/influxdb-client", "@influxdata/influxdb3-client", "influx"}
+	if hasAnyDep(allDeps, influxPackages) {
+		services = append(services, "influxdb")
+	}
+
 	return services
 }
 
@@ -297,6 +444,8 @@ func (d *NodeDetector) detectQueue(pkg packageJSON) ([]string, string) {
 		"agenda":    "agenda",
 		"kue":       "kue",
 		"pg-boss":   "pg-boss",
+		// Temporal workers poll task queues on the Temporal server
+		"@temporalio/worker": "temporal",
 	}
 
 	// Check for queue libraries
@@ -342,7 +491,143 @@ func (d *NodeDetector) findWorkerCommand(pkg packageJSON) string {
 	}
 
 	// Default fallback - assume worker.js exists
-	return "node worker.js"
+	return defaultNodeWorkerCommand
+}
+
+// defaultNodeWorkerCommand is the worker command used when no worker script is found.
+const defaultNodeWorkerCommand = "node worker.js"
+
+// detectTypeScript identifies TypeScript projects and how they are built.
+// Returns whether TypeScript is used, the build command, the compiled output
+// directory, and the dev runner (tsx/ts-node) if one is installed.
+func (d *NodeDetector) detectTypeScript(pkg packageJSON, projectPath string) (bool, string, string, string) {
+	// Merge all dependencies for checking
+	allDeps := make(map[string]string)
+	for k, v := range pkg.Dependencies {
+		allDeps[k] = v
+	}
+	for k, v := range pkg.DevDependencies {
+		allDeps[k] = v
+	}
+
+	tsconfigPath := filepath.Join(projectPath, "tsconfig.json")
+	_, err := os.Stat(tsconfigPath)
+	hasTSConfig := err == nil
+	_, hasTypeScript := allDeps["typescript"]
+
+	if !hasTSConfig && !hasTypeScript {
+		return false, "", "", ""
+	}
+
+	// Build command: only when a build script exists
+	buildCmd := ""
+	if _, exists := pkg.Scripts["build"]; exists {
+		buildCmd = "npm run build"
+	}
+
+	// Output directory from tsconfig.json, defaulting to "dist"
+	outputDir := "dist"
+	if hasTSConfig {
+		if dir := d.readTSConfigOutDir(tsconfigPath); dir != "" {
+			outputDir = dir
+		}
+	}
+
+	// Dev runners for running .ts files directly (priority order)
+	devRunner := ""
+	for _, runner := range []string{"tsx", "ts-node"} {
+		if _, exists := allDeps[runner]; exists {
+			devRunner = runner
+			break
+		}
+	}
+
+	return true, buildCmd, outputDir, devRunner
+}
+
+// readTSConfigOutDir reads compilerOptions.outDir from tsconfig.json.
+// Returns an empty string if the file cannot be parsed (e.g., it contains comments).
+func (d *NodeDetector) readTSConfigOutDir(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	var config tsConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return ""
+	}
+
+	outDir := strings.TrimPrefix(config.CompilerOptions.OutDir, "./")
+	return strings.TrimSuffix(outDir, "/")
+}
+
+// detectFrontend identifies a frontend framework in the root package.json.
+// Returns the framework, the frontend directory ("." when a backend framework
+// shares the package.json, empty when the frontend is the app itself), and the dev port.
+func (d *NodeDetector) detectFrontend(pkg packageJSON) (string, string, int) {
+	allDeps := mergeDeps(pkg)
+
+	framework := detectFrontendFramework(allDeps)
+	if framework == "" {
+		return "", "", 0
+	}
+
+	// Frontend alongside a backend server: run the dev server as a separate service
+	if hasAnyDep(allDeps, backendFrameworks) {
+		return framework, ".", frontendPort(framework, 3000)
+	}
+
+	return framework, "", frontendDefaultPort(framework)
+}
+
+// docsTools lists Node.js documentation site generators. A package.json built
+// around one of these usually serves docs next to a backend in another language.
+var docsTools = []string{
+	"@docusaurus/core",
+	"vitepress",
+	"vuepress",
+	"@11ty/eleventy",
+	"docsify-cli",
+	"typedoc",
+}
+
+// isAuxiliary reports whether package.json looks like supporting tooling
+// (a frontend app, docs site, or dev-only tooling) rather than a backend.
+func (d *NodeDetector) isAuxiliary(pkg packageJSON, frontendFramework, frontendDir string) bool {
+	allDeps := mergeDeps(pkg)
+	if hasAnyDep(allDeps, backendFrameworks) {
+		return false
+	}
+
+	// The frontend is the whole app
+	if frontendFramework != "" && frontendDir == "" {
+		return true
+	}
+
+	if hasAnyDep(allDeps, docsTools) {
+		return true
+	}
+
+	// Only devDependencies: linters, formatters, git hooks
+	return len(pkg.Dependencies) == 0
+}
+
+// typeScriptWorkerCommand returns the worker command for TypeScript projects.
+// Priority: compiled output (when a build script exists) > dev runner > plain node.
+func (d *NodeDetector) typeScriptWorkerCommand(buildCmd, outputDir, devRunner string) string {
+	if buildCmd != "" {
+		return "node " + outputDir + "/worker.js"
+	}
+
+	switch devRunner {
+	case "tsx":
+		return "npx tsx worker.ts"
+	case "ts-node":
+		return "npx ts-node worker.ts"
+	}
+
+	return defaultNodeWorkerCommand
 }
 
 // detectFileUpload identifies file upload libraries from dependencies.
@@ -385,27 +670,26 @@ func (d *NodeDetector) detectFileUpload(pkg packageJSON, projectPath string) ([]
 	return libraries, uploadPath
 }
 
+// nodeUploadDirs are the common upload directory names for Node.js projects.
+var nodeUploadDirs = []string{
+	"uploads",
+	"upload",
+	"files",
+	"public/uploads",
+	"static/uploads",
+	"tmp/uploads",
+}
+
 // findUploadPath attempts to find the upload directory.
 func (d *NodeDetector) findUploadPath(projectPath string) string {
-	// Common upload directory names
-	commonDirs := []string{
-		"uploads",
-		"upload",
-		"files",
-		"public/uploads",
-		"static/uploads",
-		"tmp/uploads",
-	}
-
-	for _, dir := range commonDirs {
+	for _, dir := range nodeUploadDirs {
 		fullPath := filepath.Join(projectPath, dir)
 		if info, err := os.Stat(fullPath); err == nil && info.IsDir() {
 			return dir
 		}
 	}
 
-	// Default to "uploads" if no directory found
-	return ""
+	return findNestedUploadDir(projectPath)
 }
 
 // detectTracing identifies distributed tracing libraries from dependencies.
@@ -530,3 +814,299 @@ func (d *NodeDetector) detectMetrics(pkg packageJSON) ([]string, int, string) {
 
 	return libraries, metricsPort, metricsPath
 }
+
+// detectWebsockets identifies WebSocket libraries from dependencies.
+func (d *NodeDetector) detectWebsockets(pkg packageJSON) []string {
+	var libraries []string
+	allDeps := mergeDeps(pkg)
+
+	// WebSocket server libraries (checked in order for stable output)
+	websocketPackages := []string{
+		"socket.io",
+		"ws",
+		"express-ws",
+		"@fastify/websocket",
+		"@nestjs/websockets",
+		"uWebSockets.js",
+	}
+
+	for _, dep := range websocketPackages {
+		if _, exists := allDeps[dep]; exists {
+			libraries = append(libraries, dep)
+		}
+	}
+
+	return libraries
+}
+
+// detectScheduler identifies cron/scheduled-job libraries from dependencies.
+// Returns the list of detected libraries and the scheduler process command, if any.
+func (d *NodeDetector) detectScheduler(pkg packageJSON) ([]string, string) {
+	var libraries []string
+	allDeps := mergeDeps(pkg)
+
+	// Scheduler libraries (checked in order for stable output)
+	schedulerPackages := []string{
+		"node-cron",
+		"cron",
+		"node-schedule",
+		"bree",
+		"agenda",
+	}
+
+	for _, dep := range schedulerPackages {
+		if _, exists := allDeps[dep]; exists {
+			libraries = append(libraries, dep)
+		}
+	}
+
+	if len(libraries) == 0 {
+		return nil, ""
+	}
+
+	// A dedicated scheduler script means the schedule runs as its own process
+	schedulerScripts := []string{
+		"scheduler",
+		"start:scheduler",
+		"scheduler:start",
+		"cron",
+		"start:cron",
+		"clock",
+	}
+
+	for _, script := range schedulerScripts {
+		if _, exists := pkg.Scripts[script]; exists {
+			return libraries, "npm run " + script
+		}
+	}
+
+	return libraries, ""
+}
+
+// detectGRPC identifies gRPC server libraries from dependencies.
+func (d *NodeDetector) detectGRPC(pkg packageJSON) []string {
+	var libraries []string
+	allDeps := mergeDeps(pkg)
+
+	// gRPC libraries (checked in order for stable output)
+	grpcPackages := []string{
+		"@grpc/grpc-js",
+		"grpc",
+		"nice-grpc",
+		"@connectrpc/connect-node",
+	}
+
+	for _, dep := range grpcPackages {
+		if _, exists := allDeps[dep]; exists {
+			libraries = append(libraries, dep)
+		}
+	}
+
+	return libraries
+}
+
+// detectAuth identifies OIDC/OAuth client libraries from dependencies.
+func (d *NodeDetector) detectAuth(pkg packageJSON) []string {
+	var libraries []string
+	allDeps := mergeDeps(pkg)
+
+	// Auth libraries (checked in order for stable output)
+	authPackages := []string{
+		"next-auth",
+		"@auth/core",
+		"passport",
+		"passport-openidconnect",
+		"passport-oauth2",
+		"openid-client",
+		"keycloak-connect",
+	}
+
+	for _, dep := range authPackages {
+		if _, exists := allDeps[dep]; exists {
+			libraries = append(libraries, dep)
+		}
+	}
+
+	return libraries
+}
+
+// detectPayments identifies Stripe SDKs from dependencies.
+func (d *NodeDetector) detectPayments(pkg packageJSON) []string {
+	var libraries []string
+	allDeps := mergeDeps(pkg)
+
+	// Server-side Stripe packages (@stripe/stripe-js is browser-only and never receives webhooks)
+	paymentPackages := []string{
+		"stripe",
+	}
+
+	for _, dep := range paymentPackages {
+		if _, exists := allDeps[dep]; exists {
+			libraries = append(libraries, dep)
+		}
+	}
+
+	return libraries
+}
+
+// detectWebDriver identifies Selenium/WebDriver clients from dependencies.
+func (d *NodeDetector) detectWebDriver(pkg packageJSON) []string {
+	var libraries []string
+	allDeps := mergeDeps(pkg)
+
+	// WebDriver clients that can drive browsers on a remote Selenium Grid
+	webDriverPackages := []string{
+		"selenium-webdriver",
+		"webdriverio",
+		"nightwatch",
+	}
+
+	for _, dep := range webDriverPackages {
+		if _, exists := allDeps[dep]; exists {
+			libraries = append(libraries, dep)
+		}
+	}
+
+	return libraries
+}
+
+// detectAWSServices identifies AWS services from modular AWS SDK v3 clients.
+func (d *NodeDetector) detectAWSServices(pkg packageJSON) []string {
+	var services []string
+	allDeps := mergeDeps(pkg)
+
+	for _, service := range awsServiceNames {
+		if _, exists := allDeps["@aws-sdk/client-"+service]; exists {
+			services = append(services, service)
+		}
+	}
+
+	return services
+}
+
+// detectVectorStores identifies vector database clients from dependencies.
+func (d *NodeDetector) detectVectorStores(pkg packageJSON) []string {
+	var libraries []string
+	allDeps := mergeDeps(pkg)
+
+	// Vector database clients (checked in order for stable output)
+	vectorPackages := []string{
+		"@qdrant/js-client-rest",
+		"chromadb",
+		"@pinecone-database/pinecone",
+		"pgvector",
+		"@langchain/qdrant",
+		"@langchain/pinecone",
+	}
+
+	for _, dep := range vectorPackages {
+		if _, exists := allDeps[dep]; exists {
+			libraries = append(libraries, dep)
+		}
+	}
+
+	return libraries
+}
+
+// detectLLM identifies LLM client libraries from dependencies.
+// LangChain integrations are reported under the provider they talk to.
+func (d *NodeDetector) detectLLM(pkg packageJSON) []string {
+	var libraries []string
+	allDeps := mergeDeps(pkg)
+
+	// LLM client packages (checked in order for stable output)
+	llmPackages := []struct {
+		pkg  string
+		name string
+	}{
+		{"openai", "openai"},
+		{"@langchain/openai", "openai"},
+		{"@anthropic-ai/sdk", "anthropic"},
+		{"@langchain/anthropic", "anthropic"},
+		{"ollama", "ollama"},
+		{"@langchain/ollama", "ollama"},
+	}
+
+	for _, llm := range llmPackages {
+		if _, exists := allDeps[llm.pkg]; exists && !containsService(libraries, llm.name) {
+			libraries = append(libraries, llm.name)
+		}
+	}
+
+	return libraries
+}
+
+// detectHTTPClients identifies HTTP client libraries from dependencies.
+func (d *NodeDetector) detectHTTPClients(pkg packageJSON) []string {
+	var libraries []string
+	allDeps := mergeDeps(pkg)
+
+	// HTTP client packages (checked in order for stable output)
+	for _, client := range []string{"axios", "node-fetch", "got", "undici", "ky", "superagent"} {
+		if _, exists := allDeps[client]; exists {
+			libraries = append(libraries, client)
+		}
+	}
+
+	return libraries
+}
+
+// detectGeo identifies geospatial libraries and PostGIS adapters from dependencies.
+func (d *NodeDetector) detectGeo(pkg packageJSON) []string {
+	var libraries []string
+	allDeps := mergeDeps(pkg)
+
+	// Checked in order for stable output
+	for _, lib := range []string{"@turf/turf", "knex-postgis", "wkx", "gdal-async"} {
+		if _, exists := allDeps[lib]; exists {
+			libraries = append(libraries, lib)
+		}
+	}
+
+	return libraries
+}
+
+// detectSearch identifies Meilisearch and Typesense clients from dependencies.
+func (d *NodeDetector) detectSearch(pkg packageJSON) []string {
+	var libraries []string
+	allDeps := mergeDeps(pkg)
+
+	// Search engine clients (checked in order for stable output)
+	for _, lib := range []string{"meilisearch", "typesense", "typesense-instantsearch-adapter"} {
+		if _, exists := allDeps[lib]; exists {
+			libraries = append(libraries, lib)
+		}
+	}
+
+	return libraries
+}
+
+// detectMail identifies SMTP mail libraries from dependencies.
+func (d *NodeDetector) detectMail(pkg packageJSON) []string {
+	var libraries []string
+	allDeps := mergeDeps(pkg)
+
+	// SMTP mail packages (checked in order for stable output)
+	for _, lib := range []string{"nodemailer", "@nestjs-modules/mailer"} {
+		if _, exists := allDeps[lib]; exists {
+			libraries = append(libraries, lib)
+		}
+	}
+
+	return libraries
+}
+
+// detectErrorTracking identifies Sentry SDKs from dependencies.
+func (d *NodeDetector) detectErrorTracking(pkg packageJSON) []string {
+	var libraries []string
+	allDeps := mergeDeps(pkg)
+
+	// Server-side Sentry SDKs (checked in order for stable output)
+	for _, sdk := range []string{"@sentry/node", "@sentry/nextjs", "@sentry/nestjs", "@sentry/bun"} {
+		if _, exists := allDeps[sdk]; exists {
+			libraries = append(libraries, sdk)
+		}
+	}
+
+	return libraries
+}
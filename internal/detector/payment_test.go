@@ -0,0 +1,96 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestPaymentDetection tests Stripe SDK detection across languages.
+func TestPaymentDetection(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		wantLibs []string
+	}{
+		{
+			name:     "node stripe",
+			filename: "package.json",
+			content:  `{"name": "shop", "dependencies": {"express": "^4.18.0", "stripe": "^14.0.0"}}`,
+			wantLibs: []string{"stripe"},
+		},
+		{
+			name:     "node browser-only stripe.js",
+			filename: "package.json",
+			content:  `{"name": "web", "dependencies": {"@stripe/stripe-js": "^2.0.0"}}`,
+			wantLibs: nil,
+		},
+		{
+			name:     "go stripe-go",
+			filename: "go.mod",
+			content: `module github.com/user/shop
+
+go 1.22
+
+require (
+	github.com/stripe/stripe-go/v76 v76.0.0
+)
+`,
+			wantLibs: []string{"stripe-go"},
+		},
+		{
+			name:     "python stripe",
+			filename: "requirements.txt",
+			content:  "django>=5.0\nstripe>=8.0\n",
+			wantLibs: []string{"stripe"},
+		},
+		{
+			name:     "rust async-stripe",
+			filename: "Cargo.toml",
+			content: `[package]
+name = "shop"
+edition = "2021"
+
+[dependencies]
+async-stripe = { version = "0.34", features = ["runtime-tokio-hyper"] }
+`,
+			wantLibs: []string{"async-stripe"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-payment-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			if err := os.WriteFile(filepath.Join(tmpDir, tt.filename), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.filename, err)
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+
+			if len(detection.PaymentLibraries) != len(tt.wantLibs) {
+				t.Errorf("PaymentLibraries = %v, want %v", detection.PaymentLibraries, tt.wantLibs)
+			}
+			for _, lib := range tt.wantLibs {
+				if !detection.HasPaymentLibrary(lib) {
+					t.Errorf("Expected payment library %q, got %v", lib, detection.PaymentLibraries)
+				}
+			}
+
+			if detection.NeedsStripe() != (len(tt.wantLibs) > 0) {
+				t.Errorf("NeedsStripe() = %v, want %v", detection.NeedsStripe(), len(tt.wantLibs) > 0)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -12,7 +13,10 @@ import (
 )
 
 // PythonDetector detects Python projects by analyzing pyproject.toml or requirements.txt.
-type PythonDetector struct{}
+type PythonDetector struct {
+	// lockfiles enables refining dependencies from poetry.lock
+	lockfiles bool
+}
 
 // NewPythonDetector creates a new Python detector.
 func NewPythonDetector() *PythonDetector {
@@ -24,6 +28,17 @@ func (d *PythonDetector) Name() string {
 	return "python"
 }
 
+// Inputs returns the files and directories the Python detector reads.
+func (d *PythonDetector) Inputs(path string) []string {
+	inputs := append([]string{"pyproject.toml", "requirements.txt"}, lockfileInputs(d.lockfiles, pythonLockfiles)...)
+	return append(inputs, uploadInputs(path, []string{"pyproject.toml", "requirements.txt"}, pythonUploadDirs)...)
+}
+
+// EnableLockfiles makes the detector read poetry.lock.
+func (d *PythonDetector) EnableLockfiles() {
+	d.lockfiles = true
+}
+
 // pyprojectTOML represents the structure of a pyproject.toml file.
 // We only parse the fields we care about.
 type pyprojectTOML struct {
@@ -89,44 +104,114 @@ func (d *PythonDetector) detectFromPyproject(path string) (*models.Detection, er
 		deps = append(deps, dep)
 	}
 
+	direct := directDependencies(deps)
+	deps, lockfile, pinned := d.applyLockfile(deps, filepath.Dir(path))
+
 	loggingLibs, logFormat := d.detectLogging(deps)
 	queueLibs, workerCmd := d.detectQueue(deps, config.Project.Name, config.Tool.Poetry.Name)
+	schedulerLibs, schedulerCmd := d.detectScheduler(deps, config.Project.Name, config.Tool.Poetry.Name)
 	uploadLibs, uploadPath := d.detectFileUpload(deps, filepath.Dir(path))
 	metricsLibs, metricsPort, metricsPath := d.detectMetrics(deps)
 	tracingLibs, tracingProtocol := d.detectTracing(deps)
+	grpcLibs := d.detectGRPC(deps)
+	authLibs := d.detectAuth(deps)
+	paymentLibs := d.detectPayments(deps)
+	webDriverLibs := d.detectWebDriver(deps)
+	awsServices := d.detectAWSServices(deps)
+	vectorLibs := d.detectVectorStores(deps)
+	llmLibs := d.detectLLM(deps)
+	httpLibs := d.detectHTTPClients(deps)
+	errorTrackingLibs := d.detectErrorTracking(deps)
+	geoLibs := d.detectGeo(deps)
+	searchLibs := d.detectSearch(deps)
+	mailLibs := d.detectMail(deps)
+	websocketLibs := d.detectWebsockets(deps)
 
 	detection := &models.Detection{
-		Language:            "python",
-		Version:             d.extractVersion(config),
-		Services:            d.detectServicesFromDeps(deps),
-		Confidence:          d.calculateConfidencePyproject(config),
-		LoggingLibraries:    loggingLibs,
-		LogFormat:           logFormat,
-		QueueLibraries:      queueLibs,
-		WorkerCommand:       workerCmd,
-		FileUploadLibraries: uploadLibs,
-		UploadPath:          uploadPath,
-		MetricsLibraries:    metricsLibs,
-		MetricsPort:         metricsPort,
-		MetricsPath:         metricsPath,
-		TracingLibraries:    tracingLibs,
-		TracingProtocol:     tracingProtocol,
+		Language:       "python",
+		Version:        d.extractVersion(config),
+		Services:       d.detectServicesFromDeps(deps),
+		Confidence:     d.calculateConfidencePyproject(config),
+		Lockfile:       lockfile,
+		PinnedVersions: pinned,
+		Dependencies:   direct,
+		Capabilities: models.Capabilities{
+			LoggingLibraries:       loggingLibs,
+			QueueLibraries:         queueLibs,
+			FileUploadLibraries:    uploadLibs,
+			MetricsLibraries:       metricsLibs,
+			TracingLibraries:       tracingLibs,
+			WebsocketLibraries:     websocketLibs,
+			GRPCLibraries:          grpcLibs,
+			AuthLibraries:          authLibs,
+			PaymentLibraries:       paymentLibs,
+			WebDriverLibraries:     webDriverLibs,
+			AWSServices:            awsServices,
+			VectorLibraries:        vectorLibs,
+			LLMLibraries:           llmLibs,
+			HTTPClientLibraries:    httpLibs,
+			ErrorTrackingLibraries: errorTrackingLibs,
+			GeoLibraries:           geoLibs,
+			SearchLibraries:        searchLibs,
+			MailLibraries:          mailLibs,
+			SchedulerLibraries:     schedulerLibs,
+		},
+		LogFormat:        logFormat,
+		WorkerCommand:    workerCmd,
+		UploadPath:       uploadPath,
+		MetricsPort:      metricsPort,
+		MetricsPath:      metricsPath,
+		TracingProtocol:  tracingProtocol,
+		LocalLLM:         containsService(llmLibs, "ollama"),
+		SchedulerCommand: schedulerCmd,
 	}
 
 	return detection, nil
 }
 
+// packageNameRe matches the package name at the start of a requirement,
+// before any extras or version specifier.
+var packageNameRe = regexp.MustCompile(`^([a-zA-Z0-9_-]+)`)
+
+// pythonVersionRe matches a major.minor Python version.
+var pythonVersionRe = regexp.MustCompile(`(\d+\.\d+)`)
+
 // extractPackageName extracts the package name from a dependency string.
 // Examples: "redis>=4.0.0" -> "redis", "psycopg2-binary" -> "psycopg2-binary"
 func (d *PythonDetector) extractPackageName(dep string) string {
 	// Match package name (before any version specifier)
-	re := regexp.MustCompile(`^([a-zA-Z0-9_-]+)`)
-	if matches := re.FindStringSubmatch(dep); matches != nil {
+	if matches := packageNameRe.FindStringSubmatch(dep); matches != nil {
 		return strings.ToLower(matches[1])
 	}
 	return strings.ToLower(dep)
 }
 
+// applyLockfile adds locked packages that deps doesn't list and returns the
+// lockfile name and the locked versions of deps. deps is returned unchanged
+// when lockfiles are disabled or poetry.lock is missing.
+func (d *PythonDetector) applyLockfile(deps []string, projectPath string) ([]string, string, map[string]string) {
+	if !d.lockfiles {
+		return deps, "", nil
+	}
+
+	lockfile, locked := readLockfile(projectPath, pythonLockfiles)
+	if locked == nil {
+		return deps, "", nil
+	}
+
+	deps, pinned := mergeLocked(deps, locked, normalizePythonName)
+	return deps, lockfile, pinned
+}
+
+// normalizePythonName normalizes a package name as in PEP 503, so "Flask_Login"
+// in a manifest matches "flask-login" in poetry.lock.
+func normalizePythonName(name string) string {
+	return strings.ToLower(pythonNameSeparators.ReplaceAllString(name, "-"))
+}
+
+// pythonNameSeparators matches runs of characters PEP 503 treats as equivalent.
+var pythonNameSeparators = regexp.MustCompile(`[-_.]+`)
+
 // detectFromRequirements parses requirements.txt for Python project info.
 func (d *PythonDetector) detectFromRequirements(path string) (*models.Detection, error) {
 	file, err := os.Open(path)
@@ -147,8 +232,7 @@ func (d *PythonDetector) detectFromRequirements(path string) (*models.Detection,
 
 		// Extract package name (before any version specifier)
 		// e.g., "psycopg2>=2.9.0" -> "psycopg2"
-		re := regexp.MustCompile(`^([a-zA-Z0-9_-]+)`)
-		if matches := re.FindStringSubmatch(line); matches != nil {
+		if matches := packageNameRe.FindStringSubmatch(line); matches != nil {
 			deps = append(deps, strings.ToLower(matches[1]))
 		}
 	}
@@ -157,28 +241,66 @@ func (d *PythonDetector) detectFromRequirements(path string) (*models.Detection,
 		return nil, err
 	}
 
+	direct := directDependencies(deps)
+	deps, lockfile, pinned := d.applyLockfile(deps, filepath.Dir(path))
+
 	loggingLibs, logFormat := d.detectLogging(deps)
 	queueLibs, workerCmd := d.detectQueue(deps, "", "")
+	schedulerLibs, schedulerCmd := d.detectScheduler(deps, "", "")
 	uploadLibs, uploadPath := d.detectFileUpload(deps, filepath.Dir(path))
 	metricsLibs, metricsPort, metricsPath := d.detectMetrics(deps)
 	tracingLibs, tracingProtocol := d.detectTracing(deps)
+	grpcLibs := d.detectGRPC(deps)
+	authLibs := d.detectAuth(deps)
+	paymentLibs := d.detectPayments(deps)
+	webDriverLibs := d.detectWebDriver(deps)
+	awsServices := d.detectAWSServices(deps)
+	vectorLibs := d.detectVectorStores(deps)
+	llmLibs := d.detectLLM(deps)
+	httpLibs := d.detectHTTPClients(deps)
+	errorTrackingLibs := d.detectErrorTracking(deps)
+	geoLibs := d.detectGeo(deps)
+	searchLibs := d.detectSearch(deps)
+	mailLibs := d.detectMail(deps)
+	websocketLibs := d.detectWebsockets(deps)
 
 	detection := &models.Detection{
-		Language:            "python",
-		Version:             "3.11", // Default when not specified
-		Services:            d.detectServicesFromDeps(deps),
-		Confidence:          0.6, // Lower confidence without pyproject.toml
-		LoggingLibraries:    loggingLibs,
-		LogFormat:           logFormat,
-		QueueLibraries:      queueLibs,
-		WorkerCommand:       workerCmd,
-		FileUploadLibraries: uploadLibs,
-		UploadPath:          uploadPath,
-		MetricsLibraries:    metricsLibs,
-		MetricsPort:         metricsPort,
-		MetricsPath:         metricsPath,
-		TracingLibraries:    tracingLibs,
-		TracingProtocol:     tracingProtocol,
+		Language:       "python",
+		Version:        "3.11", // Default when not specified
+		Services:       d.detectServicesFromDeps(deps),
+		Confidence:     0.6, // Lower confidence without pyproject.toml
+		Lockfile:       lockfile,
+		PinnedVersions: pinned,
+		Dependencies:   direct,
+		Capabilities: models.Capabilities{
+			LoggingLibraries:       loggingLibs,
+			QueueLibraries:         queueLibs,
+			FileUploadLibraries:    uploadLibs,
+			MetricsLibraries:       metricsLibs,
+			TracingLibraries:       tracingLibs,
+			WebsocketLibraries:     websocketLibs,
+			GRPCLibraries:          grpcLibs,
+			AuthLibraries:          authLibs,
+			PaymentLibraries:       paymentLibs,
+			WebDriverLibraries:     webDriverLibs,
+			AWSServices:            awsServices,
+			VectorLibraries:        vectorLibs,
+			LLMLibraries:           llmLibs,
+			HTTPClientLibraries:    httpLibs,
+			ErrorTrackingLibraries: errorTrackingLibs,
+			GeoLibraries:           geoLibs,
+			SearchLibraries:        searchLibs,
+			MailLibraries:          mailLibs,
+			SchedulerLibraries:     schedulerLibs,
+		},
+		LogFormat:        logFormat,
+		WorkerCommand:    workerCmd,
+		UploadPath:       uploadPath,
+		MetricsPort:      metricsPort,
+		MetricsPath:      metricsPath,
+		TracingProtocol:  tracingProtocol,
+		LocalLLM:         containsService(llmLibs, "ollama"),
+		SchedulerCommand: schedulerCmd,
 	}
 
 	return detection, nil
@@ -206,8 +328,7 @@ func (d *PythonDetector) extractVersion(config pyprojectTOML) string {
 // Examples: ">=3.10" -> "3.10", "^3.11" -> "3.11", ">=3.9,<4.0" -> "3.9"
 func (d *PythonDetector) parseVersionConstraint(constraint string) string {
 	// Match version pattern like 3.10, 3.11, etc.
-	re := regexp.MustCompile(`(\d+\.\d+)`)
-	match := re.FindString(constraint)
+	match := pythonVersionRe.FindString(constraint)
 	if match != "" {
 		return match
 	}
@@ -231,6 +352,24 @@ func (d *PythonDetector) detectServicesFromDeps(deps []string) []string {
 		"celery", "rq", "dramatiq",
 	}
 
+	// ClickHouse indicators
+	clickhousePackages := []string{
+		"clickhouse-connect", "clickhouse-driver",
+		"aiochclient", "asynch",
+	}
+
+	// Memcached indicators
+	memcachedPackages := []string{
+		"pymemcache", "python-memcached", "pylibmc",
+		"aiomcache", "emcache",
+	}
+
+	// NATS indicators
+	natsPackages := []string{"nats-py"}
+
+	// InfluxDB indicators
+	influxPackages := []string{"influxdb-client", "influxdb3-python", "influxdb"}
+
 	for _, dep := range deps {
 		depLower := strings.ToLower(dep)
 
@@ -253,6 +392,46 @@ func (d *PythonDetector) detectServicesFromDeps(deps []string) []string {
 				break
 			}
 		}
+
+		// Check ClickHouse
+		for _, pkg := range clickhousePackages {
+			if depLower == pkg {
+				if !containsService(services, "clickhouse") {
+					services = append(services, "clickhouse")
+				}
+				break
+			}
+		}
+
+		// Check Memcached
+		for _, pkg := range memcachedPackages {
+			if depLower == pkg {
+				if !containsService(services, "memcached") {
+					services = append(services, "memcached")
+				}
+				break
+			}
+		}
+
+		// Check NATS
+		for _, pkg := range natsPackages {
+			if depLower == pkg {
+				if !containsService(services, "nats") {
+					services = append(services, "nats")
+				}
+				break
+			}
+		}
+
+		// Check InfluxDB
+		for _, pkg := range influxPackages {
+			if depLower == pkg {
+				if !containsService(services, "influxdb") {
+					services = append(services, "influxdb")
+				}
+				break
+			}
+		}
 	}
 
 	return services
@@ -383,6 +562,8 @@ func (d *PythonDetector) detectQueue(deps []string, projectName, poetryName stri
 		"huey":     "huey",
 		"arq":      "arq",
 		"taskiq":   "taskiq",
+		// Temporal workers poll task queues on the Temporal server
+		"temporalio": "temporal",
 	}
 
 	for _, dep := range deps {
@@ -407,7 +588,12 @@ func (d *PythonDetector) detectQueue(deps []string, projectName, poetryName stri
 		}
 
 		// Set worker command based on detected library
-		// Priority: celery > dramatiq > rq > huey > arq > taskiq
+		// Priority: temporal > celery > dramatiq > rq > huey > arq > taskiq
+		if containsService(libraries, "temporal") {
+			// Temporal's samples start the worker from a worker module
+			workerCmd = "python -m " + strings.ReplaceAll(appName, "-", "_") + ".worker"
+			return libraries, workerCmd
+		}
 		for _, lib := range libraries {
 			switch lib {
 			case "celery":
@@ -488,26 +674,26 @@ func (d *PythonDetector) detectFileUpload(deps []string, projectPath string) ([]
 	return libraries, uploadPath
 }
 
+// pythonUploadDirs are the common upload directory names for Python projects.
+var pythonUploadDirs = []string{
+	"uploads",
+	"upload",
+	"files",
+	"media",
+	"media/uploads",
+	"static/uploads",
+}
+
 // findUploadPath attempts to find the upload directory for Python projects.
 func (d *PythonDetector) findUploadPath(projectPath string) string {
-	// Common upload directory names
-	commonDirs := []string{
-		"uploads",
-		"upload",
-		"files",
-		"media",
-		"media/uploads",
-		"static/uploads",
-	}
-
-	for _, dir := range commonDirs {
+	for _, dir := range pythonUploadDirs {
 		fullPath := filepath.Join(projectPath, dir)
 		if info, err := os.Stat(fullPath); err == nil && info.IsDir() {
 			return dir
 		}
 	}
 
-	return ""
+	return findNestedUploadDir(projectPath)
 }
 
 // detectTracing identifies distributed tracing libraries from Python dependencies.
@@ -668,3 +854,317 @@ func (d *PythonDetector) detectMetrics(deps []string) ([]string, int, string) {
 
 	return libraries, metricsPort, metricsPath
 }
+
+// detectWebsockets identifies WebSocket libraries from Python dependencies.
+func (d *PythonDetector) detectWebsockets(deps []string) []string {
+	var libraries []string
+
+	// WebSocket packages (normalized name -> library name)
+	websocketPackages := map[string]string{
+		"channels":        "channels",
+		"websockets":      "websockets",
+		"python-socketio": "python-socketio",
+		"flask-socketio":  "flask-socketio",
+		"flask-sock":      "flask-sock",
+	}
+
+	for _, dep := range deps {
+		depNormalized := strings.ReplaceAll(strings.ToLower(dep), "_", "-")
+		if name, ok := websocketPackages[depNormalized]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// detectScheduler identifies cron/scheduled-job libraries from Python dependencies.
+// Returns the list of detected libraries and the scheduler process command, if any.
+func (d *PythonDetector) detectScheduler(deps []string, projectName, poetryName string) ([]string, string) {
+	var libraries []string
+
+	// Scheduler packages (normalized name -> library name)
+	schedulerPackages := map[string]string{
+		"apscheduler":        "apscheduler",
+		"django-celery-beat": "celery-beat",
+		"celery-redbeat":     "celery-beat",
+		"rq-scheduler":       "rq-scheduler",
+		"schedule":           "schedule",
+	}
+
+	for _, dep := range deps {
+		depNormalized := strings.ReplaceAll(strings.ToLower(dep), "_", "-")
+		if name, ok := schedulerPackages[depNormalized]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	// Determine app name for the scheduler command (same rules as the worker)
+	appName := "app"
+	if projectName != "" {
+		appName = projectName
+	} else if poetryName != "" {
+		appName = poetryName
+	}
+
+	// Celery beat and rq-scheduler run as their own process
+	// Priority: celery beat > rq-scheduler
+	for _, lib := range libraries {
+		if lib == "celery-beat" {
+			return libraries, "celery -A " + appName + " beat"
+		}
+	}
+	for _, lib := range libraries {
+		if lib == "rq-scheduler" {
+			return libraries, "rqscheduler"
+		}
+	}
+
+	return libraries, ""
+}
+
+// detectGRPC identifies gRPC server libraries from Python dependencies.
+func (d *PythonDetector) detectGRPC(deps []string) []string {
+	var libraries []string
+
+	// gRPC packages (normalized name -> library name)
+	grpcPackages := map[string]string{
+		"grpcio":      "grpcio",
+		"grpclib":     "grpclib",
+		"betterproto": "betterproto",
+	}
+
+	for _, dep := range deps {
+		depNormalized := strings.ReplaceAll(strings.ToLower(dep), "_", "-")
+		if name, ok := grpcPackages[depNormalized]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// detectAuth identifies OIDC/OAuth client libraries from Python dependencies.
+func (d *PythonDetector) detectAuth(deps []string) []string {
+	var libraries []string
+
+	// Auth packages (normalized name -> library name)
+	authPackages := map[string]string{
+		"authlib":             "authlib",
+		"python-keycloak":     "python-keycloak",
+		"mozilla-django-oidc": "mozilla-django-oidc",
+		"django-allauth":      "django-allauth",
+		"flask-oidc":          "flask-oidc",
+	}
+
+	for _, dep := range deps {
+		depNormalized := strings.ReplaceAll(strings.ToLower(dep), "_", "-")
+		if name, ok := authPackages[depNormalized]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// detectPayments identifies Stripe SDKs from Python dependencies.
+func (d *PythonDetector) detectPayments(deps []string) []string {
+	var libraries []string
+
+	// Stripe packages (normalized name -> library name)
+	paymentPackages := map[string]string{
+		"stripe":        "stripe",
+		"dj-stripe":     "dj-stripe",
+		"django-stripe": "dj-stripe",
+	}
+
+	for _, dep := range deps {
+		depNormalized := strings.ReplaceAll(strings.ToLower(dep), "_", "-")
+		if name, ok := paymentPackages[depNormalized]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// detectWebDriver identifies Selenium/WebDriver clients from Python dependencies.
+func (d *PythonDetector) detectWebDriver(deps []string) []string {
+	var libraries []string
+
+	// WebDriver packages (normalized name -> library name)
+	webDriverPackages := map[string]string{
+		"selenium":        "selenium",
+		"pytest-selenium": "pytest-selenium",
+		"seleniumbase":    "seleniumbase",
+		"splinter":        "splinter",
+	}
+
+	for _, dep := range deps {
+		depNormalized := strings.ReplaceAll(strings.ToLower(dep), "_", "-")
+		if name, ok := webDriverPackages[depNormalized]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// detectAWSServices identifies AWS services from boto3 type stub packages
+// (e.g., "mypy-boto3-sqs", "types-boto3-dynamodb"). Plain boto3 doesn't reveal
+// which services are used.
+func (d *PythonDetector) detectAWSServices(deps []string) []string {
+	var services []string
+
+	for _, service := range awsServiceNames {
+		for _, dep := range deps {
+			depNormalized := strings.ReplaceAll(strings.ToLower(dep), "_", "-")
+			if depNormalized == "mypy-boto3-"+service || depNormalized == "types-boto3-"+service || depNormalized == "types-aiobotocore-"+service {
+				services = append(services, service)
+				break
+			}
+		}
+	}
+
+	return services
+}
+
+// detectVectorStores identifies vector database clients from Python dependencies.
+func (d *PythonDetector) detectVectorStores(deps []string) []string {
+	var libraries []string
+
+	// Vector database packages (normalized name -> library name)
+	vectorPackages := map[string]string{
+		"qdrant-client":      "qdrant-client",
+		"chromadb":           "chromadb",
+		"chromadb-client":    "chromadb",
+		"pgvector":           "pgvector",
+		"pinecone-client":    "pinecone",
+		"pinecone":           "pinecone",
+		"langchain-qdrant":   "langchain-qdrant",
+		"langchain-chroma":   "langchain-chroma",
+		"langchain-postgres": "langchain-postgres",
+		"langchain-pinecone": "langchain-pinecone",
+	}
+
+	for _, dep := range deps {
+		depNormalized := strings.ReplaceAll(strings.ToLower(dep), "_", "-")
+		if name, ok := vectorPackages[depNormalized]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// detectLLM identifies LLM client libraries from Python dependencies.
+// LangChain integrations are reported under the provider they talk to.
+func (d *PythonDetector) detectLLM(deps []string) []string {
+	var libraries []string
+
+	// LLM client packages (normalized name -> provider name)
+	llmPackages := map[string]string{
+		"openai":              "openai",
+		"langchain-openai":    "openai",
+		"anthropic":           "anthropic",
+		"langchain-anthropic": "anthropic",
+		"ollama":              "ollama",
+		"langchain-ollama":    "ollama",
+	}
+
+	for _, dep := range deps {
+		depNormalized := strings.ReplaceAll(strings.ToLower(dep), "_", "-")
+		if name, ok := llmPackages[depNormalized]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// detectHTTPClients identifies HTTP client libraries from Python dependencies.
+func (d *PythonDetector) detectHTTPClients(deps []string) []string {
+	var libraries []string
+
+	// HTTP client packages (normalized name)
+	httpPackages := []string{"requests", "httpx", "aiohttp", "urllib3"}
+
+	for _, dep := range deps {
+		depNormalized := strings.ReplaceAll(strings.ToLower(dep), "_", "-")
+		if containsService(httpPackages, depNormalized) && !containsService(libraries, depNormalized) {
+			libraries = append(libraries, depNormalized)
+		}
+	}
+
+	return libraries
+}
+
+// pythonGeoLibraries are the geospatial libraries and PostGIS adapters
+// detected in Python dependencies.
+var pythonGeoLibraries = []string{"geoalchemy2", "geopandas", "shapely", "fiona", "rasterio", "pyproj", "gdal"}
+
+// detectGeo identifies geospatial libraries and PostGIS adapters from Python dependencies.
+func (d *PythonDetector) detectGeo(deps []string) []string {
+	var libraries []string
+
+	for _, dep := range deps {
+		depNormalized := strings.ReplaceAll(strings.ToLower(dep), "_", "-")
+		if slices.Contains(pythonGeoLibraries, depNormalized) && !containsService(libraries, depNormalized) {
+			libraries = append(libraries, depNormalized)
+		}
+	}
+
+	return libraries
+}
+
+// detectSearch identifies Meilisearch and Typesense clients from Python dependencies.
+func (d *PythonDetector) detectSearch(deps []string) []string {
+	var libraries []string
+
+	// Search engine packages (normalized name -> library name)
+	searchPackages := map[string]string{
+		"meilisearch":            "meilisearch",
+		"meilisearch-python-sdk": "meilisearch",
+		"typesense":              "typesense",
+	}
+
+	for _, dep := range deps {
+		depNormalized := strings.ReplaceAll(strings.ToLower(dep), "_", "-")
+		if name, ok := searchPackages[depNormalized]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// pythonMailLibraries are the SMTP mail libraries detected in Python dependencies.
+var pythonMailLibraries = []string{"fastapi-mail", "flask-mail", "flask-mailman", "aiosmtplib", "yagmail", "redmail"}
+
+// detectMail identifies SMTP mail libraries from Python dependencies.
+func (d *PythonDetector) detectMail(deps []string) []string {
+	var libraries []string
+
+	for _, dep := range deps {
+		depNormalized := strings.ReplaceAll(strings.ToLower(dep), "_", "-")
+		if slices.Contains(pythonMailLibraries, depNormalized) && !containsService(libraries, depNormalized) {
+			libraries = append(libraries, depNormalized)
+		}
+	}
+
+	return libraries
+}
+
+// detectErrorTracking identifies Sentry SDKs from Python dependencies.
+func (d *PythonDetector) detectErrorTracking(deps []string) []string {
+	var libraries []string
+
+	for _, dep := range deps {
+		depNormalized := strings.ReplaceAll(strings.ToLower(dep), "_", "-")
+		if depNormalized == "sentry-sdk" && !containsService(libraries, depNormalized) {
+			libraries = append(libraries, depNormalized)
+		}
+	}
+
+	return libraries
+}
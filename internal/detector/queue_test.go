@@ -79,6 +79,16 @@ func TestQueueDetection_Node(t *testing.T) {
 			wantLibraries: []string{"pg-boss"},
 			wantWorkerCmd: "node worker.js",
 		},
+		{
+			name: "temporal with worker script",
+			packageJSON: `{
+				"name": "test-app",
+				"dependencies": {"@temporalio/client": "^1.11.0", "@temporalio/worker": "^1.11.0"},
+				"scripts": {"worker": "node lib/worker.js"}
+			}`,
+			wantLibraries: []string{"temporal"},
+			wantWorkerCmd: "npm run worker",
+		},
 		{
 			name: "no queue library",
 			packageJSON: `{
@@ -180,6 +190,17 @@ require github.com/RichardKnop/machinery/v2 v2.0.0
 			wantLibraries: []string{"machinery"},
 			wantWorkerCmd: "./taskrunner worker",
 		},
+		{
+			name: "temporal",
+			goMod: `module github.com/user/orders
+
+go 1.21
+
+require go.temporal.io/sdk v1.29.1
+`,
+			wantLibraries: []string{"temporal"},
+			wantWorkerCmd: "go run ./worker",
+		},
 		{
 			name: "gocraft-work",
 			goMod: `module github.com/user/jobprocessor
@@ -303,6 +324,15 @@ dependencies = ["dramatiq>=1.0.0"]
 			wantLibraries: []string{"dramatiq"},
 			wantWorkerCmd: "dramatiq dramaapp",
 		},
+		{
+			name: "temporal",
+			pyprojectTOML: `[project]
+name = "order-flows"
+dependencies = ["temporalio>=1.7.0"]
+`,
+			wantLibraries: []string{"temporal"},
+			wantWorkerCmd: "python -m order_flows.worker",
+		},
 		{
 			name: "huey",
 			pyprojectTOML: `[project]
@@ -654,6 +684,19 @@ apalis = "0.4"
 			wantLibraries: []string{"apalis"},
 			wantWorkerCmd: "./jobrunner worker",
 		},
+		{
+			name: "temporal",
+			cargoTOML: `[package]
+name = "flows"
+version = "0.1.0"
+edition = "2021"
+
+[dependencies]
+temporal-sdk = "0.1"
+`,
+			wantLibraries: []string{"temporal"},
+			wantWorkerCmd: "./flows worker",
+		},
 		{
 			name: "lapin (RabbitMQ)",
 			cargoTOML: `[package]
@@ -0,0 +1,157 @@
+package detector
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// confidenceEpsilon is the difference below which confidences count as tied.
+const confidenceEpsilon = 0.001
+
+// Resolution is the primary language chosen among all detected languages.
+type Resolution struct {
+	// Primary is the detection dockstart generates files for
+	Primary *models.Detection
+
+	// Alternatives are the other detected languages, best first
+	Alternatives []*models.Detection
+
+	// Reason explains why Primary was chosen
+	Reason string
+}
+
+// Resolve runs all detectors and chooses the primary language.
+// When forced is non-empty (from .dockstart.yml or --language), that language
+// is chosen regardless of scores; a *LanguageError is returned if it isn't
+// supported or wasn't detected. Returns nil if no language is detected.
+//
+// Otherwise detections are ranked by:
+//  1. backend languages before auxiliary ones (a frontend app, docs site, or
+//     dev tooling package.json next to, e.g., a Python API)
+//  2. confidence
+//  3. the number of detected services and libraries
+//  4. registry order (node, go, python, rust)
+func (r *DetectorRegistry) Resolve(path, forced string) (*Resolution, error) {
+	if forced != "" && !r.hasDetector(forced) {
+		return nil, &LanguageError{Language: forced, Supported: r.names()}
+	}
+
+	detections, err := r.DetectAll(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(detections) == 0 {
+		return nil, nil
+	}
+
+	if forced != "" {
+		for i, d := range detections {
+			if d.Language == forced {
+				alternatives := append(append([]*models.Detection{}, detections[:i]...), detections[i+1:]...)
+				return &Resolution{Primary: d, Alternatives: alternatives, Reason: "forced by configuration"}, nil
+			}
+		}
+		detected := make([]string, len(detections))
+		for i, d := range detections {
+			detected[i] = d.Language
+		}
+		return nil, &LanguageError{Language: forced, Supported: r.names(), Detected: detected}
+	}
+
+	return &Resolution{
+		Primary:      detections[0],
+		Alternatives: detections[1:],
+		Reason:       resolutionReason(detections),
+	}, nil
+}
+
+// rankDetections orders detections by the resolution policy (best first).
+// The sort is stable, so registry order breaks any remaining ties.
+func rankDetections(detections []*models.Detection) {
+	sort.SliceStable(detections, func(i, j int) bool {
+		return outranks(detections[i], detections[j])
+	})
+}
+
+// outranks reports whether a should be preferred over b.
+func outranks(a, b *models.Detection) bool {
+	if a.Auxiliary != b.Auxiliary {
+		return !a.Auxiliary
+	}
+	if diff := a.Confidence - b.Confidence; diff > confidenceEpsilon || diff < -confidenceEpsilon {
+		return diff > 0
+	}
+	return signalCount(a) > signalCount(b)
+}
+
+// resolutionReason explains why the first of the ranked detections won.
+func resolutionReason(detections []*models.Detection) string {
+	if len(detections) == 1 {
+		return "only language detected"
+	}
+
+	primary, next := detections[0], detections[1]
+	switch {
+	case !primary.Auxiliary && next.Auxiliary:
+		return fmt.Sprintf("%s looks like a frontend, docs site, or tooling package", next.Language)
+	case outranks(primary, next) && primary.Confidence-next.Confidence > confidenceEpsilon:
+		return "highest confidence"
+	case outranks(primary, next):
+		return "tied on confidence; more services and libraries detected"
+	default:
+		return "tied on confidence and detected libraries; first in detector order"
+	}
+}
+
+// signalCount returns how many services and libraries a detection found.
+// More signals suggest the manifest describes the app that actually runs.
+func signalCount(d *models.Detection) int {
+	lists := [][]string{
+		d.Services,
+		d.LoggingLibraries,
+		d.QueueLibraries,
+		d.FileUploadLibraries,
+		d.MetricsLibraries,
+		d.TracingLibraries,
+		d.WebsocketLibraries,
+		d.SchedulerLibraries,
+		d.GRPCLibraries,
+		d.AuthLibraries,
+		d.PaymentLibraries,
+		d.AWSServices,
+		d.VectorLibraries,
+		d.LLMLibraries,
+		d.WebDriverLibraries,
+		d.ErrorTrackingLibraries,
+		d.GeoLibraries,
+		d.SearchLibraries,
+		d.MailLibraries,
+	}
+
+	count := 0
+	for _, list := range lists {
+		count += len(list)
+	}
+	return count
+}
+
+// hasDetector reports whether a detector with the given name is registered.
+func (r *DetectorRegistry) hasDetector(name string) bool {
+	for _, d := range r.detectors {
+		if d.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// names returns the names of all registered detectors.
+func (r *DetectorRegistry) names() []string {
+	names := make([]string, 0, len(r.detectors))
+	for _, d := range r.detectors {
+		names = append(names, d.Name())
+	}
+	return names
+}
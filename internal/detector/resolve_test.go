@@ -0,0 +1,147 @@
+package detector
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestResolve tests primary language selection in multi-language repositories.
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name             string
+		files            map[string]string
+		forced           string
+		wantLanguage     string
+		wantAlternatives []string
+		wantReason       string
+		wantErr          string
+	}{
+		{
+			name: "single language",
+			files: map[string]string{
+				"go.mod": "module github.com/user/app\n\ngo 1.22\n",
+			},
+			wantLanguage: "go",
+			wantReason:   "only language detected",
+		},
+		{
+			name: "python api with docs site package.json",
+			files: map[string]string{
+				"requirements.txt": "fastapi\npsycopg2-binary\n",
+				"package.json":     `{"name": "docs", "dependencies": {"@docusaurus/core": "^3.0.0", "react": "^18.0.0"}}`,
+			},
+			wantLanguage:     "python",
+			wantAlternatives: []string{"node"},
+			wantReason:       "node looks like a frontend, docs site, or tooling package",
+		},
+		{
+			name: "go api with dev-only tooling package.json",
+			files: map[string]string{
+				"go.mod":       "module github.com/user/app\n\ngo 1.22\n",
+				"package.json": `{"name": "tooling", "devDependencies": {"prettier": "^3.0.0", "husky": "^9.0.0"}}`,
+			},
+			wantLanguage:     "go",
+			wantAlternatives: []string{"node"},
+		},
+		{
+			name: "node backend outranks requirements.txt scripts",
+			files: map[string]string{
+				"package.json":     `{"name": "api", "engines": {"node": "20"}, "dependencies": {"express": "^4.18.0", "pg": "^8.0.0"}}`,
+				"requirements.txt": "requests\n",
+			},
+			wantLanguage:     "node",
+			wantAlternatives: []string{"python"},
+			wantReason:       "highest confidence",
+		},
+		{
+			name: "tie broken by detected services",
+			files: map[string]string{
+				"pyproject.toml": "[project]\nname = \"tools\"\nrequires-python = \">=3.12\"\ndependencies = [\"click\"]\n",
+				"Cargo.toml":     "[package]\nname = \"api\"\nedition = \"2021\"\n\n[dependencies]\nsqlx = { version = \"0.7\", features = [\"postgres\"] }\nredis = \"0.24\"\n",
+			},
+			wantLanguage:     "rust",
+			wantAlternatives: []string{"python"},
+			wantReason:       "tied on confidence; more services and libraries detected",
+		},
+		{
+			name: "forced language wins",
+			files: map[string]string{
+				"requirements.txt": "fastapi\n",
+				"package.json":     `{"name": "docs", "dependencies": {"@docusaurus/core": "^3.0.0"}}`,
+			},
+			forced:           "node",
+			wantLanguage:     "node",
+			wantAlternatives: []string{"python"},
+			wantReason:       "forced by configuration",
+		},
+		{
+			name: "forced language not present",
+			files: map[string]string{
+				"go.mod": "module github.com/user/app\n\ngo 1.22\n",
+			},
+			forced:  "rust",
+			wantErr: `language "rust" is forced but no rust project was detected`,
+		},
+		{
+			name: "unknown forced language",
+			files: map[string]string{
+				"go.mod": "module github.com/user/app\n\ngo 1.22\n",
+			},
+			forced:  "cobol",
+			wantErr: `unknown language "cobol"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-resolve-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write %s: %v", name, err)
+				}
+			}
+
+			resolution, err := NewRegistry().Resolve(tmpDir, tt.forced)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Resolve() error = %v, want %q", err, tt.wantErr)
+				}
+				var langErr *LanguageError
+				if !errors.Is(err, ErrUnsupportedLanguage) || !errors.As(err, &langErr) || langErr.Hint() == "" {
+					t.Errorf("Resolve() error = %#v, want a *LanguageError with a hint", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if resolution == nil {
+				t.Fatal("Resolve() returned nil")
+			}
+
+			if resolution.Primary.Language != tt.wantLanguage {
+				t.Errorf("Primary = %s, want %s", resolution.Primary.Language, tt.wantLanguage)
+			}
+
+			var alternatives []string
+			for _, d := range resolution.Alternatives {
+				alternatives = append(alternatives, d.Language)
+			}
+			if strings.Join(alternatives, ",") != strings.Join(tt.wantAlternatives, ",") {
+				t.Errorf("Alternatives = %v, want %v", alternatives, tt.wantAlternatives)
+			}
+
+			if tt.wantReason != "" && resolution.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", resolution.Reason, tt.wantReason)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package detector
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -10,7 +11,10 @@ import (
 )
 
 // RustDetector detects Rust projects by analyzing Cargo.toml.
-type RustDetector struct{}
+type RustDetector struct {
+	// lockfiles enables refining dependencies from Cargo.lock
+	lockfiles bool
+}
 
 // NewRustDetector creates a new Rust detector.
 func NewRustDetector() *RustDetector {
@@ -22,6 +26,17 @@ func (d *RustDetector) Name() string {
 	return "rust"
 }
 
+// Inputs returns the files and directories the Rust detector reads.
+func (d *RustDetector) Inputs(path string) []string {
+	inputs := append([]string{"Cargo.toml"}, lockfileInputs(d.lockfiles, rustLockfiles)...)
+	return append(inputs, uploadInputs(path, []string{"Cargo.toml"}, rustUploadDirs)...)
+}
+
+// EnableLockfiles makes the detector read Cargo.lock.
+func (d *RustDetector) EnableLockfiles() {
+	d.lockfiles = true
+}
+
 // cargoTOML represents the structure of a Cargo.toml file.
 // We only parse the fields we care about.
 type cargoTOML struct {
@@ -53,29 +68,70 @@ func (d *RustDetector) Detect(path string) (*models.Detection, error) {
 
 	// Collect all dependencies
 	deps := d.collectDependencies(config)
+	direct := directDependencies(deps)
+
+	var lockfile string
+	var pinned map[string]string
+	if d.lockfiles {
+		deps, lockfile, pinned = d.applyLockfile(deps, config.Package.Name, path)
+	}
 
 	loggingLibs, logFormat := d.detectLogging(deps)
 	queueLibs, workerCmd := d.detectQueue(deps, config.Package.Name)
 	uploadLibs, uploadPath := d.detectFileUpload(deps, path)
 	metricsLibs, metricsPort, metricsPath := d.detectMetrics(deps)
 	tracingLibs, tracingProtocol := d.detectTracing(deps)
+	grpcLibs := d.detectGRPC(deps)
+	authLibs := d.detectAuth(deps)
+	paymentLibs := d.detectPayments(deps)
+	webDriverLibs := d.detectWebDriver(deps)
+	awsServices := d.detectAWSServices(deps)
+	vectorLibs := d.detectVectorStores(deps)
+	llmLibs := d.detectLLM(deps)
+	httpLibs := d.detectHTTPClients(deps)
+	errorTrackingLibs := d.detectErrorTracking(deps)
+	geoLibs := d.detectGeo(deps)
+	searchLibs := d.detectSearch(deps)
+	mailLibs := d.detectMail(deps)
+	websocketLibs := d.detectWebsockets(deps)
+	schedulerLibs := d.detectScheduler(deps)
 
 	detection := &models.Detection{
-		Language:            "rust",
-		Version:             d.extractVersion(config),
-		Services:            d.detectServices(deps),
-		Confidence:          d.calculateConfidence(config),
-		LoggingLibraries:    loggingLibs,
-		LogFormat:           logFormat,
-		QueueLibraries:      queueLibs,
-		WorkerCommand:       workerCmd,
-		FileUploadLibraries: uploadLibs,
-		UploadPath:          uploadPath,
-		MetricsLibraries:    metricsLibs,
-		MetricsPort:         metricsPort,
-		MetricsPath:         metricsPath,
-		TracingLibraries:    tracingLibs,
-		TracingProtocol:     tracingProtocol,
+		Language:       "rust",
+		Version:        d.extractVersion(config),
+		Services:       d.detectServices(deps),
+		Confidence:     d.calculateConfidence(config),
+		Lockfile:       lockfile,
+		PinnedVersions: pinned,
+		Dependencies:   direct,
+		Capabilities: models.Capabilities{
+			LoggingLibraries:       loggingLibs,
+			QueueLibraries:         queueLibs,
+			FileUploadLibraries:    uploadLibs,
+			MetricsLibraries:       metricsLibs,
+			TracingLibraries:       tracingLibs,
+			WebsocketLibraries:     websocketLibs,
+			GRPCLibraries:          grpcLibs,
+			AuthLibraries:          authLibs,
+			PaymentLibraries:       paymentLibs,
+			WebDriverLibraries:     webDriverLibs,
+			AWSServices:            awsServices,
+			VectorLibraries:        vectorLibs,
+			LLMLibraries:           llmLibs,
+			HTTPClientLibraries:    httpLibs,
+			ErrorTrackingLibraries: errorTrackingLibs,
+			GeoLibraries:           geoLibs,
+			SearchLibraries:        searchLibs,
+			MailLibraries:          mailLibs,
+			SchedulerLibraries:     schedulerLibs,
+		},
+		LogFormat:       logFormat,
+		WorkerCommand:   workerCmd,
+		UploadPath:      uploadPath,
+		MetricsPort:     metricsPort,
+		MetricsPath:     metricsPath,
+		TracingProtocol: tracingProtocol,
+		LocalLLM:        containsService(llmLibs, "ollama"),
 	}
 
 	return detection, nil
@@ -98,6 +154,19 @@ func (d *RustDetector) collectDependencies(config cargoTOML) []string {
 	return deps
 }
 
+// applyLockfile adds crates from Cargo.lock that deps doesn't list and returns the
+// lockfile name and the locked versions of deps. The crate itself is skipped.
+func (d *RustDetector) applyLockfile(deps []string, crate string, path string) ([]string, string, map[string]string) {
+	lockfile, locked := readLockfile(path, rustLockfiles)
+	if locked == nil {
+		return deps, "", nil
+	}
+	delete(locked, crate)
+
+	deps, pinned := mergeLocked(deps, locked, strings.ToLower)
+	return deps, lockfile, pinned
+}
+
 // extractVersion extracts the Rust version from Cargo.toml.
 // Priority: rust-version > edition mapping > default
 func (d *RustDetector) extractVersion(config cargoTOML) string {
@@ -145,6 +214,31 @@ func (d *RustDetector) detectServices(deps []string) []string {
 		"bb8-redis",
 	}
 
+	// ClickHouse indicators
+	clickhousePackages := []string{
+		"clickhouse",
+		"clickhouse-rs",
+		"klickhouse",
+	}
+
+	// Memcached indicators
+	memcachedPackages := []string{
+		"memcache",
+		"async-memcached",
+	}
+
+	// NATS indicators
+	natsPackages := []string{
+		"async-nats",
+		"nats",
+	}
+
+	// InfluxDB indicators
+	influxPackages := []string{
+		"influxdb2",
+		"influxdb",
+	}
+
 	for _, dep := range deps {
 		depLower := strings.ToLower(dep)
 
@@ -167,6 +261,46 @@ func (d *RustDetector) detectServices(deps []string) []string {
 				break
 			}
 		}
+
+		// Check ClickHouse
+		for _, pkg := range clickhousePackages {
+			if depLower == pkg {
+				if !containsService(services, "clickhouse") {
+					services = append(services, "clickhouse")
+				}
+				break
+			}
+		}
+
+		// Check Memcached
+		for _, pkg := range memcachedPackages {
+			if depLower == pkg {
+				if !containsService(services, "memcached") {
+					services = append(services, "memcached")
+				}
+				break
+			}
+		}
+
+		// Check NATS
+		for _, pkg := range natsPackages {
+			if depLower == pkg {
+				if !containsService(services, "nats") {
+					services = append(services, "nats")
+				}
+				break
+			}
+		}
+
+		// Check InfluxDB
+		for _, pkg := range influxPackages {
+			if depLower == pkg {
+				if !containsService(services, "influxdb") {
+					services = append(services, "influxdb")
+				}
+				break
+			}
+		}
 	}
 
 	return services
@@ -282,6 +416,8 @@ func (d *RustDetector) detectQueue(deps []string, packageName string) ([]string,
 		"apalis":     "apalis",
 		"faktory":    "faktory",
 		"background": "background-jobs",
+		// Temporal workers poll task queues on the Temporal server
+		"temporal-sdk": "temporal",
 	}
 
 	for _, dep := range deps {
@@ -365,26 +501,26 @@ func (d *RustDetector) detectFileUpload(deps []string, projectPath string) ([]st
 	return libraries, uploadPath
 }
 
+// rustUploadDirs are the common upload directory names for Rust projects.
+var rustUploadDirs = []string{
+	"uploads",
+	"upload",
+	"files",
+	"static/uploads",
+	"public/uploads",
+	"assets/uploads",
+}
+
 // findUploadPath attempts to find the upload directory for Rust projects.
 func (d *RustDetector) findUploadPath(projectPath string) string {
-	// Common upload directory names
-	commonDirs := []string{
-		"uploads",
-		"upload",
-		"files",
-		"static/uploads",
-		"public/uploads",
-		"assets/uploads",
-	}
-
-	for _, dir := range commonDirs {
+	for _, dir := range rustUploadDirs {
 		fullPath := filepath.Join(projectPath, dir)
 		if info, err := os.Stat(fullPath); err == nil && info.IsDir() {
 			return dir
 		}
 	}
 
-	return ""
+	return findNestedUploadDir(projectPath)
 }
 
 // detectTracing identifies distributed tracing libraries from Rust dependencies.
@@ -534,3 +670,256 @@ func (d *RustDetector) detectMetrics(deps []string) ([]string, int, string) {
 
 	return libraries, metricsPort, metricsPath
 }
+
+// detectWebsockets identifies WebSocket libraries from Rust dependencies.
+func (d *RustDetector) detectWebsockets(deps []string) []string {
+	var libraries []string
+
+	// WebSocket crates
+	websocketCrates := map[string]string{
+		"tokio-tungstenite": "tokio-tungstenite",
+		"tungstenite":       "tungstenite",
+		"async-tungstenite": "async-tungstenite",
+		"actix-ws":          "actix-ws",
+		"actix-web-actors":  "actix-web-actors",
+	}
+
+	for _, dep := range deps {
+		if name, ok := websocketCrates[strings.ToLower(dep)]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// detectScheduler identifies cron/scheduled-job crates from Rust dependencies.
+// Rust schedulers run in-process, so no separate scheduler command is inferred.
+func (d *RustDetector) detectScheduler(deps []string) []string {
+	var libraries []string
+
+	// Scheduler crates
+	schedulerCrates := map[string]string{
+		"tokio-cron-scheduler": "tokio-cron-scheduler",
+		"job_scheduler":        "job_scheduler",
+		"job_scheduler_ng":     "job_scheduler",
+		"clokwerk":             "clokwerk",
+	}
+
+	for _, dep := range deps {
+		if name, ok := schedulerCrates[strings.ToLower(dep)]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// detectGRPC identifies gRPC server crates from Rust dependencies.
+func (d *RustDetector) detectGRPC(deps []string) []string {
+	var libraries []string
+
+	// gRPC crates
+	grpcCrates := map[string]string{
+		"tonic":  "tonic",
+		"grpcio": "grpcio",
+	}
+
+	for _, dep := range deps {
+		if name, ok := grpcCrates[strings.ToLower(dep)]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// detectAuth identifies OIDC/OAuth client crates from Rust dependencies.
+func (d *RustDetector) detectAuth(deps []string) []string {
+	var libraries []string
+
+	// Auth crates
+	authCrates := map[string]string{
+		"openidconnect": "openidconnect",
+		"oauth2":        "oauth2",
+		"axum-oidc":     "axum-oidc",
+	}
+
+	for _, dep := range deps {
+		if name, ok := authCrates[strings.ToLower(dep)]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// detectPayments identifies Stripe crates from Rust dependencies.
+func (d *RustDetector) detectPayments(deps []string) []string {
+	var libraries []string
+
+	// Stripe crates
+	paymentCrates := map[string]string{
+		"async-stripe": "async-stripe",
+		"stripe-rust":  "stripe-rust",
+	}
+
+	for _, dep := range deps {
+		if name, ok := paymentCrates[strings.ToLower(dep)]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// detectWebDriver identifies WebDriver client crates from Rust dependencies.
+func (d *RustDetector) detectWebDriver(deps []string) []string {
+	var libraries []string
+
+	// WebDriver crates
+	webDriverCrates := map[string]string{
+		"thirtyfour": "thirtyfour",
+		"fantoccini": "fantoccini",
+	}
+
+	for _, dep := range deps {
+		if name, ok := webDriverCrates[strings.ToLower(dep)]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// detectAWSServices identifies AWS services from AWS SDK for Rust crates.
+func (d *RustDetector) detectAWSServices(deps []string) []string {
+	var services []string
+
+	for _, service := range awsServiceNames {
+		for _, dep := range deps {
+			if strings.ToLower(dep) == "aws-sdk-"+service {
+				services = append(services, service)
+				break
+			}
+		}
+	}
+
+	return services
+}
+
+// detectVectorStores identifies vector database clients from Rust dependencies.
+func (d *RustDetector) detectVectorStores(deps []string) []string {
+	var libraries []string
+
+	// Vector database crates
+	vectorCrates := map[string]string{
+		"qdrant-client": "qdrant-client",
+		"pgvector":      "pgvector",
+		"chromadb":      "chromadb",
+	}
+
+	for _, dep := range deps {
+		if name, ok := vectorCrates[strings.ToLower(dep)]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// detectLLM identifies LLM client crates from Rust dependencies.
+func (d *RustDetector) detectLLM(deps []string) []string {
+	var libraries []string
+
+	// LLM client crates (crate name -> provider name)
+	llmCrates := map[string]string{
+		"async-openai": "openai",
+		"ollama-rs":    "ollama",
+	}
+
+	for _, dep := range deps {
+		if name, ok := llmCrates[strings.ToLower(dep)]; ok && !containsService(libraries, name) {
+			libraries = append(libraries, name)
+		}
+	}
+
+	return libraries
+}
+
+// detectHTTPClients identifies HTTP client crates from Rust dependencies.
+func (d *RustDetector) detectHTTPClients(deps []string) []string {
+	var libraries []string
+
+	// HTTP client crates
+	httpCrates := []string{"reqwest", "ureq", "surf", "isahc"}
+
+	for _, dep := range deps {
+		dep = strings.ToLower(dep)
+		if containsService(httpCrates, dep) && !containsService(libraries, dep) {
+			libraries = append(libraries, dep)
+		}
+	}
+
+	return libraries
+}
+
+// rustGeoCrates are the geospatial crates and PostGIS adapters detected in
+// Rust dependencies.
+var rustGeoCrates = []string{"geo", "geo-types", "geozero", "postgis", "gdal"}
+
+// detectGeo identifies geospatial crates and PostGIS adapters from Rust dependencies.
+func (d *RustDetector) detectGeo(deps []string) []string {
+	var libraries []string
+
+	// Checked in crate order, as deps come from maps
+	for _, crate := range rustGeoCrates {
+		if slices.ContainsFunc(deps, func(dep string) bool { return strings.EqualFold(dep, crate) }) {
+			libraries = append(libraries, crate)
+		}
+	}
+
+	return libraries
+}
+
+// detectSearch identifies Meilisearch and Typesense crates from Rust dependencies.
+func (d *RustDetector) detectSearch(deps []string) []string {
+	var libraries []string
+
+	// Checked in crate order, as deps come from maps
+	for _, crate := range []string{"meilisearch-sdk", "typesense"} {
+		if slices.ContainsFunc(deps, func(dep string) bool { return strings.EqualFold(dep, crate) }) {
+			libraries = append(libraries, crate)
+		}
+	}
+
+	return libraries
+}
+
+// detectMail identifies SMTP mail crates from Rust dependencies.
+func (d *RustDetector) detectMail(deps []string) []string {
+	var libraries []string
+
+	// Checked in crate order, as deps come from maps
+	for _, crate := range []string{"lettre", "mail-send"} {
+		if slices.ContainsFunc(deps, func(dep string) bool { return strings.EqualFold(dep, crate) }) {
+			libraries = append(libraries, crate)
+		}
+	}
+
+	return libraries
+}
+
+// detectErrorTracking identifies Sentry crates from Rust dependencies.
+func (d *RustDetector) detectErrorTracking(deps []string) []string {
+	var libraries []string
+
+	for _, dep := range deps {
+		dep = strings.ToLower(dep)
+		if dep == "sentry" && !containsService(libraries, dep) {
+			libraries = append(libraries, dep)
+		}
+	}
+
+	return libraries
+}
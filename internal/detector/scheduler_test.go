@@ -0,0 +1,126 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSchedulerDetection tests cron/scheduled-job library detection across languages.
+func TestSchedulerDetection(t *testing.T) {
+	tests := []struct {
+		name        string
+		filename    string
+		content     string
+		wantLibs    []string
+		wantCommand string
+	}{
+		{
+			name:     "node-cron in process",
+			filename: "package.json",
+			content:  `{"name": "api", "dependencies": {"express": "^4.18.0", "node-cron": "^3.0.0"}}`,
+			wantLibs: []string{"node-cron"},
+		},
+		{
+			name:        "agenda with scheduler script",
+			filename:    "package.json",
+			content:     `{"name": "api", "scripts": {"scheduler": "node scheduler.js"}, "dependencies": {"agenda": "^5.0.0"}}`,
+			wantLibs:    []string{"agenda"},
+			wantCommand: "npm run scheduler",
+		},
+		{
+			name:     "node without scheduler",
+			filename: "package.json",
+			content:  `{"name": "api", "dependencies": {"express": "^4.18.0"}}`,
+			wantLibs: nil,
+		},
+		{
+			name:     "python apscheduler",
+			filename: "requirements.txt",
+			content:  "fastapi\nAPScheduler>=3.10\n",
+			wantLibs: []string{"apscheduler"},
+		},
+		{
+			name:     "python celery beat",
+			filename: "pyproject.toml",
+			content: `[project]
+name = "shop"
+dependencies = ["celery>=5.3", "django-celery-beat>=2.5"]
+`,
+			wantLibs:    []string{"celery-beat"},
+			wantCommand: "celery -A shop beat",
+		},
+		{
+			name:        "python rq-scheduler",
+			filename:    "requirements.txt",
+			content:     "rq\nrq-scheduler\n",
+			wantLibs:    []string{"rq-scheduler"},
+			wantCommand: "rqscheduler",
+		},
+		{
+			name:     "go robfig cron",
+			filename: "go.mod",
+			content: `module github.com/user/jobs
+
+go 1.22
+
+require (
+	github.com/robfig/cron/v3 v3.0.1
+)
+`,
+			wantLibs: []string{"robfig/cron"},
+		},
+		{
+			name:     "rust tokio-cron-scheduler",
+			filename: "Cargo.toml",
+			content: `[package]
+name = "jobs"
+edition = "2021"
+
+[dependencies]
+tokio = "1"
+tokio-cron-scheduler = "0.10"
+`,
+			wantLibs: []string{"tokio-cron-scheduler"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-scheduler-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			if err := os.WriteFile(filepath.Join(tmpDir, tt.filename), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.filename, err)
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+
+			if len(detection.SchedulerLibraries) != len(tt.wantLibs) {
+				t.Errorf("SchedulerLibraries = %v, want %v", detection.SchedulerLibraries, tt.wantLibs)
+			}
+			for _, lib := range tt.wantLibs {
+				if !detection.HasSchedulerLibrary(lib) {
+					t.Errorf("Expected scheduler library %q, got %v", lib, detection.SchedulerLibraries)
+				}
+			}
+
+			if detection.SchedulerCommand != tt.wantCommand {
+				t.Errorf("SchedulerCommand = %q, want %q", detection.SchedulerCommand, tt.wantCommand)
+			}
+
+			if detection.NeedsScheduler() != (len(tt.wantLibs) > 0) {
+				t.Errorf("NeedsScheduler() = %v, want %v", detection.NeedsScheduler(), len(tt.wantLibs) > 0)
+			}
+		})
+	}
+}
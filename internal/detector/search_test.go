@@ -0,0 +1,92 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestSearchDetection tests Meilisearch and Typesense client detection across languages.
+func TestSearchDetection(t *testing.T) {
+	tests := []struct {
+		name       string
+		filename   string
+		content    string
+		wantLibs   []string
+		wantEngine string
+	}{
+		{
+			name:       "node meilisearch",
+			filename:   "package.json",
+			content:    `{"name": "shop", "dependencies": {"express": "^4.18.0", "meilisearch": "^0.44.0"}}`,
+			wantLibs:   []string{"meilisearch"},
+			wantEngine: "meilisearch",
+		},
+		{
+			name:       "python async meilisearch sdk",
+			filename:   "requirements.txt",
+			content:    "fastapi\nmeilisearch-python-sdk>=3.0\n",
+			wantLibs:   []string{"meilisearch"},
+			wantEngine: "meilisearch",
+		},
+		{
+			name:     "go typesense",
+			filename: "go.mod",
+			content: `module github.com/user/shop
+
+go 1.22
+
+require (
+	github.com/typesense/typesense-go/v2 v2.0.0
+)
+`,
+			wantLibs:   []string{"typesense-go"},
+			wantEngine: "typesense",
+		},
+		{
+			name:     "rust meilisearch-sdk",
+			filename: "Cargo.toml",
+			content: `[package]
+name = "shop"
+edition = "2021"
+
+[dependencies]
+meilisearch-sdk = "0.27"
+`,
+			wantLibs:   []string{"meilisearch-sdk"},
+			wantEngine: "meilisearch",
+		},
+		{
+			name:       "no search client",
+			filename:   "requirements.txt",
+			content:    "flask\n",
+			wantLibs:   nil,
+			wantEngine: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(tmpDir, tt.filename), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.filename, err)
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+
+			if !reflect.DeepEqual(detection.SearchLibraries, tt.wantLibs) {
+				t.Errorf("SearchLibraries = %v, want %v", detection.SearchLibraries, tt.wantLibs)
+			}
+			if got := detection.GetSearchEngine(); got != tt.wantEngine {
+				t.Errorf("GetSearchEngine() = %q, want %q", got, tt.wantEngine)
+			}
+		})
+	}
+}
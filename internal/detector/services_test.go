@@ -254,6 +254,139 @@ celery>=5.3.0
 	}
 }
 
+// TestServiceDetection_OtherServices tests ClickHouse, Memcached, NATS, and
+// InfluxDB client detection in every language.
+func TestServiceDetection_OtherServices(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		service  string
+	}{
+		{
+			name:     "node @clickhouse/client",
+			filename: "package.json",
+			content:  `{"name": "test-app", "dependencies": {"@clickhouse/client": "^1.4.0"}}`,
+			service:  "clickhouse",
+		},
+		{
+			name:     "python clickhouse-connect",
+			filename: "requirements.txt",
+			content:  "clickhouse-connect>=0.7.0\n",
+			service:  "clickhouse",
+		},
+		{
+			name:     "go clickhouse-go",
+			filename: "go.mod",
+			content:  "module test-app\ngo 1.21\nrequire github.com/ClickHouse/clickhouse-go/v2 v2.26.0\n",
+			service:  "clickhouse",
+		},
+		{
+			name:     "rust clickhouse",
+			filename: "Cargo.toml",
+			content:  "[package]\nname = \"test-app\"\nversion = \"0.1.0\"\n\n[dependencies]\nclickhouse = \"0.12\"\n",
+			service:  "clickhouse",
+		},
+		{
+			name:     "node memjs",
+			filename: "package.json",
+			content:  `{"name": "test-app", "dependencies": {"memjs": "^1.3.0"}}`,
+			service:  "memcached",
+		},
+		{
+			name:     "python pymemcache",
+			filename: "requirements.txt",
+			content:  "pymemcache==4.0.0\n",
+			service:  "memcached",
+		},
+		{
+			name:     "go gomemcache",
+			filename: "go.mod",
+			content:  "module test-app\ngo 1.21\nrequire github.com/bradfitz/gomemcache v0.0.0-20230905024940-24af94b03874\n",
+			service:  "memcached",
+		},
+		{
+			name:     "rust memcache",
+			filename: "Cargo.toml",
+			content:  "[package]\nname = \"test-app\"\nversion = \"0.1.0\"\n\n[dependencies]\nmemcache = \"0.17\"\n",
+			service:  "memcached",
+		},
+		{
+			name:     "node @nats-io/transport-node",
+			filename: "package.json",
+			content:  `{"name": "test-app", "dependencies": {"@nats-io/transport-node": "^3.0.0"}}`,
+			service:  "nats",
+		},
+		{
+			name:     "python nats-py",
+			filename: "requirements.txt",
+			content:  "nats-py[nkeys]>=2.7\n",
+			service:  "nats",
+		},
+		{
+			name:     "go nats.go",
+			filename: "go.mod",
+			content:  "module test-app\ngo 1.21\nrequire github.com/nats-io/nats.go v1.37.0\n",
+			service:  "nats",
+		},
+		{
+			name:     "rust async-nats",
+			filename: "Cargo.toml",
+			content:  "[package]\nname = \"test-app\"\nversion = \"0.1.0\"\n\n[dependencies]\nasync-nats = \"0.37\"\n",
+			service:  "nats",
+		},
+		{
+			name:     "node @influxdata/influxdb-client",
+			filename: "package.json",
+			content:  `{"name": "test-app", "dependencies": {"@influxdata/influxdb-client": "^1.35.0"}}`,
+			service:  "influxdb",
+		},
+		{
+			name:     "python influxdb-client",
+			filename: "requirements.txt",
+			content:  "influxdb-client[async]==1.46.0\n",
+			service:  "influxdb",
+		},
+		{
+			name:     "go influxdb-client-go",
+			filename: "go.mod",
+			content:  "module test-app\ngo 1.21\nrequire github.com/influxdata/influxdb-client-go/v2 v2.14.0\n",
+			service:  "influxdb",
+		},
+		{
+			name:     "rust influxdb2",
+			filename: "Cargo.toml",
+			content:  "[package]\nname = \"test-app\"\nversion = \"0.1.0\"\n\n[dependencies]\ninfluxdb2 = \"0.5\"\n",
+			service:  "influxdb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-service-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			if err := os.WriteFile(filepath.Join(tmpDir, tt.filename), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.filename, err)
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+			if !containsService(detection.Services, tt.service) {
+				t.Errorf("%s not detected (services: %v)", tt.service, detection.Services)
+			}
+		})
+	}
+}
+
 // TestServiceDetection_NoServices tests that projects without service dependencies
 // return empty services list.
 func TestServiceDetection_NoServices(t *testing.T) {
@@ -0,0 +1,247 @@
+package detector
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// composeFiles lists existing compose files whose images pin service versions.
+// .devcontainer is not searched: it holds dockstart's own output.
+var composeFiles = []string{
+	"docker-compose.yml",
+	"docker-compose.yaml",
+	"compose.yml",
+	"compose.yaml",
+}
+
+// workflowDir holds GitHub Actions workflows, whose service containers
+// usually run the same database version as production.
+const workflowDir = ".github/workflows"
+
+// serviceImages maps image repositories to the backing service they run.
+var serviceImages = map[string]string{
+	"postgres":                     "postgres",
+	"postgis/postgis":              "postgres",
+	"pgvector/pgvector":            "postgres",
+	"bitnami/postgresql":           "postgres",
+	"timescale/timescaledb":        "postgres",
+	"timescale/timescaledb-ha":     "postgres",
+	"redis":                        "redis",
+	"bitnami/redis":                "redis",
+	"clickhouse/clickhouse-server": "clickhouse",
+	"yandex/clickhouse-server":     "clickhouse",
+	"bitnami/clickhouse":           "clickhouse",
+	"memcached":                    "memcached",
+	"bitnami/memcached":            "memcached",
+	"nats":                         "nats",
+	"bitnami/nats":                 "nats",
+	"temporalio/auto-setup":        "temporal",
+	"temporalio/server":            "temporal",
+	"influxdb":                     "influxdb",
+	"bitnami/influxdb":             "influxdb",
+}
+
+// toolVersionServices maps asdf/mise plugin names in .tool-versions to services.
+var toolVersionServices = map[string]string{
+	"postgres":   "postgres",
+	"postgresql": "postgres",
+	"redis":      "redis",
+	"clickhouse": "clickhouse",
+	"memcached":  "memcached",
+}
+
+// minorVersionServices are the services whose releases are numbered by
+// major.minor (e.g., ClickHouse's year.month "24.8", Temporal's "1.25"), so a
+// major-only tag would float across releases.
+var minorVersionServices = map[string]bool{
+	"clickhouse": true,
+	"temporal":   true,
+}
+
+// minorVersionRe matches the major.minor version at the start of an image
+// tag or tool version (e.g., "24.8-alpine", "24.8.4"), or a lone major version.
+var minorVersionRe = regexp.MustCompile(`^(\d+(?:\.\d+)?)(?:[.\-_]|$)`)
+
+// majorVersionRe matches the major version at the start of an image tag or
+// tool version, after an optional "pg" prefix (e.g., "15-alpine", "pg15", "7.2.4").
+var majorVersionRe = regexp.MustCompile(`^(?:pg)?(\d+)(?:[.\-_]|$)`)
+
+// applyServiceVersions sets the versions of backing services from existing
+// project files, then applies overrides (from .dockstart.yml) on top.
+func applyServiceVersions(detection *models.Detection, projectPath string, overrides map[string]string) {
+	versions := inferServiceVersions(projectPath)
+	for service, version := range overrides {
+		versions[service] = version
+	}
+
+	if len(versions) == 0 {
+		detection.ServiceVersions = nil
+		return
+	}
+	detection.ServiceVersions = versions
+}
+
+// inferServiceVersions returns the major version of each backing service found
+// in existing compose files, then CI workflows, then .tool-versions. The first
+// source that mentions a service wins.
+func inferServiceVersions(projectPath string) map[string]string {
+	versions := make(map[string]string)
+	add := func(service, version string) {
+		if _, exists := versions[service]; !exists {
+			versions[service] = version
+		}
+	}
+
+	for _, name := range composeFiles {
+		for _, image := range yamlImages(filepath.Join(projectPath, name)) {
+			if service, version := imageVersion(image); service != "" {
+				add(service, version)
+			}
+		}
+	}
+
+	workflows, _ := filepath.Glob(filepath.Join(projectPath, filepath.FromSlash(workflowDir), "*.y*ml"))
+	sort.Strings(workflows)
+	for _, workflow := range workflows {
+		for _, image := range yamlImages(workflow) {
+			if service, version := imageVersion(image); service != "" {
+				add(service, version)
+			}
+		}
+	}
+
+	for service, version := range toolVersions(filepath.Join(projectPath, ".tool-versions")) {
+		add(service, version)
+	}
+
+	return versions
+}
+
+// yamlImages returns every "image:" value in a YAML file, at any depth.
+// This covers compose services and GitHub Actions service containers alike.
+// Returns nil if the file is missing or not valid YAML.
+func yamlImages(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil
+	}
+
+	var images []string
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key, value := node.Content[i], node.Content[i+1]
+				if key.Value == "image" && value.Kind == yaml.ScalarNode {
+					images = append(images, value.Value)
+				}
+			}
+		}
+		for _, child := range node.Content {
+			walk(child)
+		}
+	}
+	walk(&root)
+
+	return images
+}
+
+// imageVersion returns the backing service an image runs and its version,
+// e.g. "postgres:15-alpine" -> ("postgres", "15"), or major.minor for
+// minorVersionServices. Returns empty strings for unknown images and tags
+// without a version (like "latest").
+func imageVersion(image string) (string, string) {
+	service, tag := imageService(image)
+	version := serviceVersion(service, tag)
+	if matches := pgSuffixRe.FindStringSubmatch(tag); service == "postgres" && matches != nil {
+		version = matches[1]
+	}
+	if service == "" || version == "" {
+		return "", ""
+	}
+	return service, version
+}
+
+// imageService returns the backing service an image runs and the image tag,
+// e.g. "postgres:15-alpine" -> ("postgres", "15-alpine"). Returns an empty
+// service for unknown images.
+func imageService(image string) (string, string) {
+	image, _, _ = strings.Cut(image, "@")
+	repo, tag := image, ""
+	// Only a colon after the last slash separates the tag; earlier ones are a registry port
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		repo, tag = image[:i], image[i+1:]
+	}
+
+	repo = strings.TrimPrefix(repo, "docker.io/")
+	repo = strings.TrimPrefix(repo, "library/")
+	return serviceImages[repo], tag
+}
+
+// toolVersions reads service versions from an asdf/mise .tool-versions file,
+// e.g. "postgres 15.4" -> {"postgres": "15"}. Returns nil if the file is missing.
+func toolVersions(path string) map[string]string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	versions := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		service, known := toolVersionServices[fields[0]]
+		if !known {
+			continue
+		}
+		// Later versions on the line are fallbacks; the first one is used
+		if version := serviceVersion(service, fields[1]); version != "" {
+			versions[service] = version
+		}
+	}
+
+	return versions
+}
+
+// pgSuffixRe matches the PostgreSQL major version at the end of an extension
+// image tag (e.g., "2.17.2-pg16", "latest-pg15"), which starts with the extension's version.
+var pgSuffixRe = regexp.MustCompile(`-pg(\d+)(?:[.\-_]|$)`)
+
+// serviceVersion returns the version of a service to pin from a tag or
+// version string: major.minor for minorVersionServices, otherwise the major version.
+func serviceVersion(service, version string) string {
+	if !minorVersionServices[service] {
+		return majorVersion(version)
+	}
+	matches := minorVersionRe.FindStringSubmatch(version)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+// majorVersion returns the major version at the start of a tag or version string.
+func majorVersion(version string) string {
+	matches := majorVersionRe.FindStringSubmatch(version)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
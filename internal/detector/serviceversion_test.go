@@ -0,0 +1,150 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestInferServiceVersions tests reading service versions from existing configs.
+func TestInferServiceVersions(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		overrides map[string]string
+		want      map[string]string
+	}{
+		{
+			name: "existing docker-compose.yml",
+			files: map[string]string{
+				"docker-compose.yml": "services:\n  db:\n    image: postgres:15.4-alpine\n  cache:\n    image: docker.io/library/redis:6.2\n",
+			},
+			want: map[string]string{"postgres": "15", "redis": "6"},
+		},
+		{
+			name: "postgis and pgvector images",
+			files: map[string]string{
+				"compose.yaml": "services:\n  db:\n    image: postgis/postgis:14-3.4\n",
+			},
+			want: map[string]string{"postgres": "14"},
+		},
+		{
+			name: "github actions service containers",
+			files: map[string]string{
+				".github/workflows/ci.yml": "jobs:\n  test:\n    services:\n      postgres:\n        image: pgvector/pgvector:pg17\n",
+			},
+			want: map[string]string{"postgres": "17"},
+		},
+		{
+			name: ".tool-versions",
+			files: map[string]string{
+				".tool-versions": "nodejs 20.11.0\npostgres 13.14\nredis 7.2.4 6.2.14\n",
+			},
+			want: map[string]string{"postgres": "13", "redis": "7"},
+		},
+		{
+			name: "compose wins over workflows and .tool-versions",
+			files: map[string]string{
+				"docker-compose.yml":        "services:\n  db:\n    image: postgres:15\n",
+				".github/workflows/ci.yaml": "jobs:\n  test:\n    services:\n      db:\n        image: postgres:14\n      redis:\n        image: redis:6\n",
+				".tool-versions":            "postgres 12.1\nredis 5.0.0\n",
+			},
+			want: map[string]string{"postgres": "15", "redis": "6"},
+		},
+		{
+			name: "timescaledb image tags carry the postgres version last",
+			files: map[string]string{
+				"docker-compose.yml": "services:\n  db:\n    image: timescale/timescaledb:2.17.2-pg15\n",
+			},
+			want: map[string]string{"postgres": "15"},
+		},
+		{
+			name: "clickhouse image",
+			files: map[string]string{
+				"docker-compose.yml": "services:\n  olap:\n    image: clickhouse/clickhouse-server:23.8-alpine\n",
+			},
+			want: map[string]string{"clickhouse": "23.8"},
+		},
+		{
+			name: "clickhouse keeps its year.month release",
+			files: map[string]string{
+				".github/workflows/ci.yml": "jobs:\n  test:\n    services:\n      olap:\n        image: clickhouse/clickhouse-server:24.8.4.13\n",
+				".tool-versions":           "clickhouse 23.3.1\n",
+			},
+			want: map[string]string{"clickhouse": "24.8"},
+		},
+		{
+			name: "clickhouse tool version keeps major.minor",
+			files: map[string]string{
+				".tool-versions": "clickhouse 23.3.1\n",
+			},
+			want: map[string]string{"clickhouse": "23.3"},
+		},
+		{
+			name: "temporal keeps major.minor",
+			files: map[string]string{
+				"docker-compose.yml": "services:\n  temporal:\n    image: temporalio/auto-setup:1.25.2\n",
+			},
+			want: map[string]string{"temporal": "1.25"},
+		},
+		{
+			name: "unversioned and unknown images are ignored",
+			files: map[string]string{
+				"docker-compose.yml": "services:\n  db:\n    image: postgres:latest\n  api:\n    image: myregistry:5000/postgres-tools:1.0\n  mq:\n    image: rabbitmq:3\n",
+			},
+			want: map[string]string{},
+		},
+		{
+			name: "generated devcontainer compose is not read",
+			files: map[string]string{
+				".devcontainer/docker-compose.yml": "services:\n  postgres:\n    image: postgres:16-alpine\n",
+			},
+			want: map[string]string{},
+		},
+		{
+			name: "overrides win",
+			files: map[string]string{
+				"docker-compose.yml": "services:\n  db:\n    image: postgres:15\n",
+			},
+			overrides: map[string]string{"postgres": "16.2"},
+			want:      map[string]string{"postgres": "16.2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-versions-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			tt.files["package.json"] = `{"name": "app", "dependencies": {"pg": "^8.11.0", "redis": "^4.6.0"}}`
+			for name, content := range tt.files {
+				path := filepath.Join(tmpDir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatalf("Failed to create directory for %s: %v", name, err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatalf("Failed to write %s: %v", name, err)
+				}
+			}
+
+			registry := NewRegistry()
+			registry.UseServiceVersions(tt.overrides)
+			detection, err := registry.DetectPrimary(tmpDir)
+			if err != nil || detection == nil {
+				t.Fatalf("DetectPrimary() = %v, %v", detection, err)
+			}
+
+			if len(detection.ServiceVersions) != len(tt.want) {
+				t.Errorf("ServiceVersions = %v, want %v", detection.ServiceVersions, tt.want)
+			}
+			for service, version := range tt.want {
+				if got := detection.GetServiceVersion(service); got != version {
+					t.Errorf("GetServiceVersion(%q) = %q, want %q", service, got, version)
+				}
+			}
+		})
+	}
+}
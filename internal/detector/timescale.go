@@ -0,0 +1,120 @@
+package detector
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"github.com/jpequegn/dockstart/internal/walker"
+)
+
+// timescaleScanDepth is how many levels below the root are searched for migrations.
+const timescaleScanDepth = 4
+
+// timescaleMaxFiles caps how many migration files are read, so large histories stay fast.
+const timescaleMaxFiles = 500
+
+// timescaleMaxFileSize skips files too large to be hand-written migrations (e.g., dumps).
+const timescaleMaxFileSize = 1 << 20
+
+// migrationDirNames are path segments of directories holding schema migrations.
+var migrationDirNames = []string{"migrations", "migration", "migrate", "alembic", "sql", "schema", "db"}
+
+// migrationExtensions are the file types migrations are written in.
+var migrationExtensions = []string{".sql", ".py", ".js", ".ts", ".go", ".rs"}
+
+// timescaleHints are SQL fragments only a TimescaleDB schema contains.
+var timescaleHints = []string{"timescaledb", "create_hypertable"}
+
+// timescaleImage is the repository of the TimescaleDB images.
+const timescaleImage = "timescale/timescaledb"
+
+// applyTimescaleDB flags projects that use TimescaleDB: their migrations create
+// the extension or hypertables, or an existing compose file runs a TimescaleDB
+// image. Their PostgreSQL service then runs the TimescaleDB image.
+func applyTimescaleDB(detection *models.Detection, projectPath string) {
+	detection.TimescaleDB = false
+	if !composeRunsTimescaleDB(projectPath) && !migrationsUseTimescaleDB(projectPath) {
+		return
+	}
+
+	detection.TimescaleDB = true
+	if !detection.HasService("postgres") {
+		detection.Services = append(detection.Services, "postgres")
+	}
+}
+
+// composeRunsTimescaleDB reports whether an existing compose file runs a TimescaleDB image.
+func composeRunsTimescaleDB(projectPath string) bool {
+	for _, name := range composeFiles {
+		for _, image := range yamlImages(filepath.Join(projectPath, name)) {
+			image = strings.TrimPrefix(image, "docker.io/")
+			if strings.HasPrefix(image, timescaleImage) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// migrationsUseTimescaleDB reports whether a migration or schema file under a
+// migrations directory mentions TimescaleDB.
+func migrationsUseTimescaleDB(projectPath string) bool {
+	read := 0
+	for _, dir := range walker.Dirs(projectPath, timescaleScanDepth) {
+		if !isMigrationDir(dir) {
+			continue
+		}
+		entries, err := os.ReadDir(filepath.Join(projectPath, filepath.FromSlash(dir)))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !slices.Contains(migrationExtensions, path.Ext(entry.Name())) {
+				continue
+			}
+			if read >= timescaleMaxFiles {
+				return false
+			}
+			read++
+			if fileMentions(filepath.Join(projectPath, filepath.FromSlash(dir), entry.Name()), timescaleHints) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isMigrationDir reports whether any segment of a relative directory path names a
+// migrations directory (e.g., "db/migrations", "prisma/migrations/20240101_init").
+func isMigrationDir(dir string) bool {
+	for _, segment := range strings.Split(dir, "/") {
+		if slices.Contains(migrationDirNames, strings.ToLower(segment)) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileMentions reports whether a file contains any of the lowercase fragments,
+// ignoring case. Files over timescaleMaxFileSize are skipped.
+func fileMentions(file string, fragments []string) bool {
+	info, err := os.Stat(file)
+	if err != nil || info.Size() > timescaleMaxFileSize {
+		return false
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return false
+	}
+	content := strings.ToLower(string(data))
+	for _, fragment := range fragments {
+		if strings.Contains(content, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestApplyTimescaleDB tests detecting TimescaleDB from migrations and compose images.
+func TestApplyTimescaleDB(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  bool
+	}{
+		{
+			name: "sql migration creates the extension",
+			files: map[string]string{
+				"db/migrations/001_init.sql": "CREATE EXTENSION IF NOT EXISTS timescaledb;\n",
+			},
+			want: true,
+		},
+		{
+			name: "alembic migration creates a hypertable",
+			files: map[string]string{
+				"alembic/versions/3f2a_metrics.py": "op.execute(\"SELECT create_hypertable('metrics', 'time')\")\n",
+			},
+			want: true,
+		},
+		{
+			name: "prisma migration",
+			files: map[string]string{
+				"prisma/migrations/20240101_init/migration.sql": "SELECT Create_Hypertable('readings', 'ts');\n",
+			},
+			want: true,
+		},
+		{
+			name: "existing compose runs the timescale image",
+			files: map[string]string{
+				"docker-compose.yml": "services:\n  db:\n    image: timescale/timescaledb:2.17.2-pg16\n",
+			},
+			want: true,
+		},
+		{
+			name: "mention outside migrations is ignored",
+			files: map[string]string{
+				"docs/notes.sql":             "-- maybe try timescaledb later\n",
+				"db/migrations/001_init.sql": "CREATE TABLE users (id serial);\n",
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for name, content := range tt.files {
+				file := filepath.Join(tmpDir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			detection := &models.Detection{Language: "python"}
+			applyTimescaleDB(detection, tmpDir)
+			if detection.TimescaleDB != tt.want {
+				t.Errorf("TimescaleDB = %v, want %v", detection.TimescaleDB, tt.want)
+			}
+			if tt.want && !detection.HasService("postgres") {
+				t.Errorf("Services = %v, want postgres added", detection.Services)
+			}
+		})
+	}
+}
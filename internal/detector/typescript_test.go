@@ -0,0 +1,158 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestTypeScriptDetection_Node tests TypeScript detection for Node.js projects.
+func TestTypeScriptDetection_Node(t *testing.T) {
+	tests := []struct {
+		name           string
+		packageJSON    string
+		tsconfig       string
+		wantTypeScript bool
+		wantBuildCmd   string
+		wantOutputDir  string
+		wantDevRunner  string
+		wantWorkerCmd  string
+	}{
+		{
+			name: "plain javascript project",
+			packageJSON: `{
+				"name": "test-app",
+				"dependencies": {"express": "^4.18.0"}
+			}`,
+			wantTypeScript: false,
+		},
+		{
+			name: "typescript dependency without tsconfig",
+			packageJSON: `{
+				"name": "test-app",
+				"devDependencies": {"typescript": "^5.3.0"}
+			}`,
+			wantTypeScript: true,
+			wantOutputDir:  "dist",
+		},
+		{
+			name: "tsconfig without typescript dependency",
+			packageJSON: `{
+				"name": "test-app",
+				"scripts": {"build": "tsc"}
+			}`,
+			tsconfig:       `{"compilerOptions": {"outDir": "./build/"}}`,
+			wantTypeScript: true,
+			wantBuildCmd:   "npm run build",
+			wantOutputDir:  "build",
+		},
+		{
+			name: "tsconfig with comments falls back to dist",
+			packageJSON: `{
+				"name": "test-app",
+				"devDependencies": {"typescript": "^5.3.0"}
+			}`,
+			tsconfig: `{
+				// compiler settings
+				"compilerOptions": {"outDir": "lib"}
+			}`,
+			wantTypeScript: true,
+			wantOutputDir:  "dist",
+		},
+		{
+			name: "worker points at compiled output when build script exists",
+			packageJSON: `{
+				"name": "test-app",
+				"scripts": {"build": "tsc"},
+				"dependencies": {"bullmq": "^4.0.0"},
+				"devDependencies": {"typescript": "^5.3.0", "tsx": "^4.0.0"}
+			}`,
+			wantTypeScript: true,
+			wantBuildCmd:   "npm run build",
+			wantOutputDir:  "dist",
+			wantDevRunner:  "tsx",
+			wantWorkerCmd:  "node dist/worker.js",
+		},
+		{
+			name: "worker uses tsx when there is no build script",
+			packageJSON: `{
+				"name": "test-app",
+				"dependencies": {"bull": "^4.0.0"},
+				"devDependencies": {"typescript": "^5.3.0", "tsx": "^4.0.0"}
+			}`,
+			wantTypeScript: true,
+			wantOutputDir:  "dist",
+			wantDevRunner:  "tsx",
+			wantWorkerCmd:  "npx tsx worker.ts",
+		},
+		{
+			name: "worker uses ts-node when there is no build script",
+			packageJSON: `{
+				"name": "test-app",
+				"dependencies": {"bull": "^4.0.0"},
+				"devDependencies": {"typescript": "^5.3.0", "ts-node": "^10.9.0"}
+			}`,
+			wantTypeScript: true,
+			wantOutputDir:  "dist",
+			wantDevRunner:  "ts-node",
+			wantWorkerCmd:  "npx ts-node worker.ts",
+		},
+		{
+			name: "explicit worker script takes precedence",
+			packageJSON: `{
+				"name": "test-app",
+				"scripts": {"build": "tsc", "worker": "node dist/jobs/worker.js"},
+				"dependencies": {"bullmq": "^4.0.0"},
+				"devDependencies": {"typescript": "^5.3.0"}
+			}`,
+			wantTypeScript: true,
+			wantBuildCmd:   "npm run build",
+			wantOutputDir:  "dist",
+			wantWorkerCmd:  "npm run worker",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-typescript-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(tt.packageJSON), 0644); err != nil {
+				t.Fatalf("Failed to write package.json: %v", err)
+			}
+			if tt.tsconfig != "" {
+				if err := os.WriteFile(filepath.Join(tmpDir, "tsconfig.json"), []byte(tt.tsconfig), 0644); err != nil {
+					t.Fatalf("Failed to write tsconfig.json: %v", err)
+				}
+			}
+
+			d := NewNodeDetector()
+			detection, err := d.Detect(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+
+			if detection.TypeScript != tt.wantTypeScript {
+				t.Errorf("TypeScript = %v, want %v", detection.TypeScript, tt.wantTypeScript)
+			}
+			if detection.BuildCommand != tt.wantBuildCmd {
+				t.Errorf("BuildCommand = %q, want %q", detection.BuildCommand, tt.wantBuildCmd)
+			}
+			if detection.BuildOutputDir != tt.wantOutputDir {
+				t.Errorf("BuildOutputDir = %q, want %q", detection.BuildOutputDir, tt.wantOutputDir)
+			}
+			if detection.DevRunner != tt.wantDevRunner {
+				t.Errorf("DevRunner = %q, want %q", detection.DevRunner, tt.wantDevRunner)
+			}
+			if detection.WorkerCommand != tt.wantWorkerCmd {
+				t.Errorf("WorkerCommand = %q, want %q", detection.WorkerCommand, tt.wantWorkerCmd)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package detector
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/walker"
+)
+
+// nestedUploadDirNames are directory names that hold uploads wherever they appear.
+// Generic names like "files" and "media" are only trusted at the project root.
+var nestedUploadDirNames = []string{"uploads", "upload"}
+
+// uploadScanDepth is how many levels below the root are searched for upload directories.
+const uploadScanDepth = 3
+
+// findNestedUploadDir returns the shallowest upload directory below the project root
+// (e.g., "apps/api/uploads"). Vendored and git-ignored trees are not searched, but the
+// upload directory itself may be git-ignored, since it usually holds user content.
+func findNestedUploadDir(projectPath string) string {
+	for _, dir := range walker.Dirs(projectPath, uploadScanDepth) {
+		for _, name := range nestedUploadDirNames {
+			candidate := path.Join(dir, name)
+			if info, err := os.Stat(filepath.Join(projectPath, filepath.FromSlash(candidate))); err == nil && info.IsDir() {
+				return candidate
+			}
+		}
+	}
+	return ""
+}
+
+// uploadInputs returns the cache inputs for upload path detection: the common
+// root-level directories plus the nested upload directory currently present, if any.
+// The tree is only searched when one of the detector's manifests exists: without
+// one the detector finds no project, and a manifest appearing changes its inputs.
+func uploadInputs(projectPath string, manifests, commonDirs []string) []string {
+	inputs := append([]string{}, commonDirs...)
+	if !anyExists(projectPath, manifests) {
+		return inputs
+	}
+	if nested := findNestedUploadDir(projectPath); nested != "" {
+		inputs = append(inputs, nested)
+	}
+	return inputs
+}
+
+// anyExists reports whether any of the files exists in projectPath.
+func anyExists(projectPath string, files []string) bool {
+	for _, file := range files {
+		if _, err := os.Stat(filepath.Join(projectPath, file)); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestVectorStoreDetection tests vector database client detection across languages.
+func TestVectorStoreDetection(t *testing.T) {
+	tests := []struct {
+		name      string
+		filename  string
+		content   string
+		wantLibs  []string
+		wantStore string
+	}{
+		{
+			name:      "python qdrant-client",
+			filename:  "requirements.txt",
+			content:   "fastapi\nqdrant-client>=1.8\n",
+			wantLibs:  []string{"qdrant-client"},
+			wantStore: "qdrant",
+		},
+		{
+			name:      "python chromadb",
+			filename:  "requirements.txt",
+			content:   "chromadb\nlangchain\n",
+			wantLibs:  []string{"chromadb"},
+			wantStore: "chroma",
+		},
+		{
+			name:     "python pgvector wins over pinecone",
+			filename: "pyproject.toml",
+			content: `[project]
+name = "rag"
+dependencies = ["pinecone-client>=3.0", "pgvector>=0.2", "psycopg[binary]>=3.1"]
+`,
+			wantLibs:  []string{"pinecone", "pgvector"},
+			wantStore: "pgvector",
+		},
+		{
+			name:      "node qdrant client",
+			filename:  "package.json",
+			content:   `{"name": "rag", "dependencies": {"@qdrant/js-client-rest": "^1.8.0", "openai": "^4.0.0"}}`,
+			wantLibs:  []string{"@qdrant/js-client-rest"},
+			wantStore: "qdrant",
+		},
+		{
+			name:     "go pgvector-go",
+			filename: "go.mod",
+			content: `module github.com/user/rag
+
+go 1.22
+
+require (
+	github.com/jackc/pgx/v5 v5.5.0
+	github.com/pgvector/pgvector-go v0.1.1
+)
+`,
+			wantLibs:  []string{"pgvector-go"},
+			wantStore: "pgvector",
+		},
+		{
+			name:     "rust qdrant-client",
+			filename: "Cargo.toml",
+			content: `[package]
+name = "rag"
+edition = "2021"
+
+[dependencies]
+qdrant-client = "1.8"
+`,
+			wantLibs:  []string{"qdrant-client"},
+			wantStore: "qdrant",
+		},
+		{
+			name:      "no vector store",
+			filename:  "requirements.txt",
+			content:   "flask\n",
+			wantLibs:  nil,
+			wantStore: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-vector-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			if err := os.WriteFile(filepath.Join(tmpDir, tt.filename), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.filename, err)
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+
+			if len(detection.VectorLibraries) != len(tt.wantLibs) {
+				t.Errorf("VectorLibraries = %v, want %v", detection.VectorLibraries, tt.wantLibs)
+			}
+			for _, lib := range tt.wantLibs {
+				if !detection.HasVectorLibrary(lib) {
+					t.Errorf("Expected vector library %q, got %v", lib, detection.VectorLibraries)
+				}
+			}
+
+			if got := detection.GetVectorStore(); got != tt.wantStore {
+				t.Errorf("GetVectorStore() = %q, want %q", got, tt.wantStore)
+			}
+		})
+	}
+}
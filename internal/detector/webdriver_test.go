@@ -0,0 +1,91 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestWebDriverDetection tests Selenium/WebDriver client detection across languages.
+func TestWebDriverDetection(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		wantLibs []string
+	}{
+		{
+			name:     "node selenium-webdriver",
+			filename: "package.json",
+			content:  `{"name": "shop", "dependencies": {"express": "^4.18.0"}, "devDependencies": {"selenium-webdriver": "^4.20.0"}}`,
+			wantLibs: []string{"selenium-webdriver"},
+		},
+		{
+			name:     "node playwright is not webdriver",
+			filename: "package.json",
+			content:  `{"name": "shop", "devDependencies": {"@playwright/test": "^1.45.0"}}`,
+			wantLibs: nil,
+		},
+		{
+			name:     "go tebeka/selenium",
+			filename: "go.mod",
+			content: `module github.com/user/shop
+
+go 1.22
+
+require (
+	github.com/tebeka/selenium v0.9.9
+)
+`,
+			wantLibs: []string{"tebeka/selenium"},
+		},
+		{
+			name:     "python selenium",
+			filename: "requirements.txt",
+			content:  "django>=5.0\nselenium>=4.20\npytest_selenium\n",
+			wantLibs: []string{"selenium", "pytest-selenium"},
+		},
+		{
+			name:     "rust thirtyfour",
+			filename: "Cargo.toml",
+			content: `[package]
+name = "shop"
+edition = "2021"
+
+[dev-dependencies]
+thirtyfour = "0.32"
+`,
+			wantLibs: []string{"thirtyfour"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-webdriver-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			if err := os.WriteFile(filepath.Join(tmpDir, tt.filename), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.filename, err)
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+
+			if !reflect.DeepEqual(detection.WebDriverLibraries, tt.wantLibs) {
+				t.Errorf("WebDriverLibraries = %v, want %v", detection.WebDriverLibraries, tt.wantLibs)
+			}
+			if detection.NeedsSeleniumGrid() != (len(tt.wantLibs) > 0) {
+				t.Errorf("NeedsSeleniumGrid() = %v, want %v", detection.NeedsSeleniumGrid(), len(tt.wantLibs) > 0)
+			}
+		})
+	}
+}
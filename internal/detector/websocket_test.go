@@ -0,0 +1,112 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWebsocketDetection tests WebSocket library detection across languages.
+func TestWebsocketDetection(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		content  string
+		wantLibs []string
+	}{
+		{
+			name:     "node socket.io",
+			filename: "package.json",
+			content:  `{"name": "chat", "dependencies": {"express": "^4.18.0", "socket.io": "^4.7.0"}}`,
+			wantLibs: []string{"socket.io"},
+		},
+		{
+			name:     "node ws",
+			filename: "package.json",
+			content:  `{"name": "chat", "dependencies": {"ws": "^8.14.0"}}`,
+			wantLibs: []string{"ws"},
+		},
+		{
+			name:     "node without websockets",
+			filename: "package.json",
+			content:  `{"name": "api", "dependencies": {"express": "^4.18.0"}}`,
+			wantLibs: nil,
+		},
+		{
+			name:     "go gorilla websocket",
+			filename: "go.mod",
+			content: `module github.com/user/chat
+
+go 1.22
+
+require (
+	github.com/gorilla/websocket v1.5.1
+)
+`,
+			wantLibs: []string{"gorilla/websocket"},
+		},
+		{
+			name:     "python django channels",
+			filename: "requirements.txt",
+			content:  "django>=5.0\nchannels>=4.0\nchannels-redis>=4.1\n",
+			wantLibs: []string{"channels"},
+		},
+		{
+			name:     "python websockets in pyproject",
+			filename: "pyproject.toml",
+			content: `[project]
+name = "chat"
+dependencies = ["websockets>=12.0", "python-socketio>=5.10"]
+`,
+			wantLibs: []string{"websockets", "python-socketio"},
+		},
+		{
+			name:     "rust tokio-tungstenite",
+			filename: "Cargo.toml",
+			content: `[package]
+name = "chat"
+edition = "2021"
+
+[dependencies]
+tokio = "1"
+tokio-tungstenite = "0.21"
+`,
+			wantLibs: []string{"tokio-tungstenite"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-websocket-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			if err := os.WriteFile(filepath.Join(tmpDir, tt.filename), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.filename, err)
+			}
+
+			detection, err := NewRegistry().DetectPrimary(tmpDir)
+			if err != nil {
+				t.Fatalf("Detection failed: %v", err)
+			}
+			if detection == nil {
+				t.Fatal("Expected detection, got nil")
+			}
+
+			if len(detection.WebsocketLibraries) != len(tt.wantLibs) {
+				t.Errorf("WebsocketLibraries = %v, want %v", detection.WebsocketLibraries, tt.wantLibs)
+			}
+			for _, lib := range tt.wantLibs {
+				if !detection.HasWebsocketLibrary(lib) {
+					t.Errorf("Expected WebSocket library %q, got %v", lib, detection.WebsocketLibraries)
+				}
+			}
+
+			if detection.NeedsWebsockets() != (len(tt.wantLibs) > 0) {
+				t.Errorf("NeedsWebsockets() = %v, want %v", detection.NeedsWebsockets(), len(tt.wantLibs) > 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+// Package docker wraps the docker CLI to run and inspect generated compose stacks.
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Publisher is a port published by a compose service container.
+type Publisher struct {
+	// URL is the host address the port is bound to (e.g., "0.0.0.0")
+	URL string `json:"URL"`
+
+	// TargetPort is the port inside the container
+	TargetPort int `json:"TargetPort"`
+
+	// PublishedPort is the port on the host (0 when not published)
+	PublishedPort int `json:"PublishedPort"`
+
+	// Protocol is the transport protocol ("tcp" or "udp")
+	Protocol string `json:"Protocol"`
+}
+
+// ServiceStatus is the state of a single compose service container.
+type ServiceStatus struct {
+	// Name is the container name
+	Name string `json:"Name"`
+
+	// Service is the compose service name (e.g., "app", "postgres")
+	Service string `json:"Service"`
+
+	// State is the container state (e.g., "running", "exited", "restarting")
+	State string `json:"State"`
+
+	// Health is the healthcheck status ("healthy", "unhealthy", "starting", or empty)
+	Health string `json:"Health"`
+
+	// ExitCode is the exit code of an exited container
+	ExitCode int `json:"ExitCode"`
+
+	// Publishers lists the container's published ports
+	Publishers []Publisher `json:"Publishers"`
+}
+
+// IsReady returns true if the service is running (and healthy, when it has a healthcheck),
+// or is a one-shot container that completed successfully.
+func (s ServiceStatus) IsReady() bool {
+	switch s.State {
+	case "running":
+		return s.Health == "" || s.Health == "healthy"
+	case "exited":
+		return s.ExitCode == 0
+	default:
+		return false
+	}
+}
+
+// IsFailed returns true if the service exited with an error or is unhealthy.
+func (s ServiceStatus) IsFailed() bool {
+	return (s.State == "exited" && s.ExitCode != 0) || s.State == "dead" || s.Health == "unhealthy"
+}
+
+// Summary returns a short human-readable status (e.g., "running (healthy)").
+func (s ServiceStatus) Summary() string {
+	switch {
+	case s.State == "exited":
+		return fmt.Sprintf("exited (%d)", s.ExitCode)
+	case s.Health != "":
+		return fmt.Sprintf("%s (%s)", s.State, s.Health)
+	default:
+		return s.State
+	}
+}
+
+// Compose runs docker compose commands against a generated compose file.
+type Compose struct {
+	// File is the path to docker-compose.yml
+	File string
+
+	// ProjectName is the compose project name
+	ProjectName string
+
+	// Stdout receives streamed command output (default: os.Stdout)
+	Stdout io.Writer
+
+	// Stderr receives streamed command errors (default: os.Stderr)
+	Stderr io.Writer
+
+	// Logger, when set, logs each docker command at debug level
+	Logger *slog.Logger
+}
+
+// NewCompose creates a Compose for the .devcontainer/docker-compose.yml of a project.
+func NewCompose(projectPath string) *Compose {
+	return &Compose{
+		File:        filepath.Join(projectPath, ".devcontainer", "docker-compose.yml"),
+		ProjectName: ProjectName(projectPath),
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+	}
+}
+
+// ProjectName returns the compose project name used for a project directory.
+// It matches the name VS Code Dev Containers uses ("<folder>_devcontainer"),
+// so `dockstart up` and "Reopen in Container" share the same stack.
+func ProjectName(projectPath string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(filepath.Base(projectPath)) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String() + "_devcontainer"
+}
+
+// Available returns an error if the docker CLI or the compose plugin is missing.
+func Available() error {
+	if _, err := exec.LookPath("docker"); err != nil {
+		return fmt.Errorf("docker CLI not found in PATH: %w", err)
+	}
+	if err := exec.Command("docker", "compose", "version").Run(); err != nil {
+		return fmt.Errorf("docker compose plugin not available: %w", err)
+	}
+	return nil
+}
+
+// command returns a docker command, logging it when the Compose has a logger.
+func (c *Compose) command(args ...string) *exec.Cmd {
+	if c.Logger != nil {
+		c.Logger.Debug("running docker", "args", strings.Join(args, " "))
+	}
+	return exec.Command("docker", args...)
+}
+
+// args builds the docker compose argument list for a subcommand.
+func (c *Compose) args(subcommand ...string) []string {
+	return append([]string{"compose", "-f", c.File, "-p", c.ProjectName}, subcommand...)
+}
+
+// Up starts the stack in the background, building images as needed.
+// Output from docker compose is streamed to Stdout/Stderr.
+func (c *Compose) Up() error {
+	cmd := c.command(c.args("up", "-d", "--build")...)
+	cmd.Env = hostUserEnv(os.Environ(), os.Getuid(), os.Getgid())
+	cmd.Stdout = c.Stdout
+	cmd.Stderr = c.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("docker compose up failed: %w", err)
+	}
+	return nil
+}
+
+// Run runs a one-shot service of a profile and removes its container afterwards.
+// docker compose starts the service's dependencies first and waits for their
+// depends_on conditions. A non-empty command replaces the service's command.
+// Output is streamed to Stdout/Stderr; a failing command returns an error.
+func (c *Compose) Run(profile, service string, command ...string) error {
+	args := c.args("--profile", profile, "run", "--rm", "--build", service)
+	cmd := c.command(append(args, command...)...)
+	cmd.Env = hostUserEnv(os.Environ(), os.Getuid(), os.Getgid())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = c.Stdout
+	cmd.Stderr = c.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("docker compose run %s failed: %w", service, err)
+	}
+	return nil
+}
+
+// hostUserEnv adds USER_UID/USER_GID for the host user to env, unless they
+// are already set. Non-root compose files pass them to image builds so files
+// written to bind mounts keep the host owner. Negative IDs (Windows) are skipped.
+func hostUserEnv(env []string, uid, gid int) []string {
+	if uid < 0 || gid < 0 {
+		return env
+	}
+	for _, v := range []struct {
+		name string
+		id   int
+	}{{"USER_UID", uid}, {"USER_GID", gid}} {
+		if !hasEnv(env, v.name) {
+			env = append(env, fmt.Sprintf("%s=%d", v.name, v.id))
+		}
+	}
+	return env
+}
+
+// hasEnv reports whether env sets the variable name.
+func hasEnv(env []string, name string) bool {
+	for _, entry := range env {
+		if strings.HasPrefix(entry, name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// Services returns the services of the compose file, including those of profile.
+func (c *Compose) Services(profile string) ([]string, error) {
+	var stderr bytes.Buffer
+	cmd := c.command(c.args("--profile", profile, "config", "--services")...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker compose config failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// PS returns the status of every service container in the stack, including stopped ones.
+func (c *Compose) PS() ([]ServiceStatus, error) {
+	var stderr bytes.Buffer
+	cmd := c.command(c.args("ps", "-a", "--format", "json")...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker compose ps failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return ParsePS(out)
+}
+
+// Wait polls the stack until every service is ready, one fails, or the timeout expires.
+// report is called with the current statuses after every poll.
+func (c *Compose) Wait(timeout, interval time.Duration, report func([]ServiceStatus)) ([]ServiceStatus, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		statuses, err := c.PS()
+		if err != nil {
+			return nil, err
+		}
+		if report != nil {
+			report(statuses)
+		}
+
+		ready := len(statuses) > 0
+		for _, s := range statuses {
+			if s.IsFailed() {
+				return statuses, fmt.Errorf("service %s is %s", s.Service, s.Summary())
+			}
+			if !s.IsReady() {
+				ready = false
+			}
+		}
+		if ready {
+			return statuses, nil
+		}
+
+		if time.Now().After(deadline) {
+			return statuses, fmt.Errorf("timed out after %s waiting for services to become ready", timeout)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// ParsePS parses `docker compose ps --format json` output.
+// Older Compose releases print a JSON array; newer ones print one object per line.
+func ParsePS(data []byte) ([]ServiceStatus, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var statuses []ServiceStatus
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &statuses); err != nil {
+			return nil, fmt.Errorf("failed to parse compose ps output: %w", err)
+		}
+		return statuses, nil
+	}
+
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var status ServiceStatus
+		if err := json.Unmarshal(line, &status); err != nil {
+			return nil, fmt.Errorf("failed to parse compose ps output: %w", err)
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,323 @@
+package docker
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestParsePS tests parsing of both docker compose ps JSON formats.
+func TestParsePS(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantCount int
+		wantFirst ServiceStatus
+	}{
+		{
+			name:      "empty output",
+			input:     "",
+			wantCount: 0,
+		},
+		{
+			name: "json array (compose < 2.21)",
+			input: `[{"Name":"api_devcontainer-postgres-1","Service":"postgres","State":"running","Health":"healthy","ExitCode":0,
+				"Publishers":[{"URL":"0.0.0.0","TargetPort":5432,"PublishedPort":5432,"Protocol":"tcp"}]},
+				{"Name":"api_devcontainer-app-1","Service":"app","State":"running","Health":"","ExitCode":0,"Publishers":null}]`,
+			wantCount: 2,
+			wantFirst: ServiceStatus{Service: "postgres", State: "running", Health: "healthy"},
+		},
+		{
+			name: "one object per line (compose >= 2.21)",
+			input: `{"Name":"api_devcontainer-app-1","Service":"app","State":"running","Health":"","ExitCode":0,"Publishers":[]}
+{"Name":"api_devcontainer-ollama-pull-1","Service":"ollama-pull","State":"exited","Health":"","ExitCode":0,"Publishers":[]}
+`,
+			wantCount: 2,
+			wantFirst: ServiceStatus{Service: "app", State: "running"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statuses, err := ParsePS([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("ParsePS() error = %v", err)
+			}
+			if len(statuses) != tt.wantCount {
+				t.Fatalf("ParsePS() returned %d statuses, want %d", len(statuses), tt.wantCount)
+			}
+			if tt.wantCount == 0 {
+				return
+			}
+
+			first := statuses[0]
+			if first.Service != tt.wantFirst.Service || first.State != tt.wantFirst.State || first.Health != tt.wantFirst.Health {
+				t.Errorf("first status = %+v, want service=%s state=%s health=%s",
+					first, tt.wantFirst.Service, tt.wantFirst.State, tt.wantFirst.Health)
+			}
+		})
+	}
+
+	if _, err := ParsePS([]byte("not json")); err == nil {
+		t.Error("ParsePS() should fail on invalid output")
+	}
+}
+
+// TestServiceStatus tests readiness and failure classification.
+func TestServiceStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      ServiceStatus
+		wantReady   bool
+		wantFailed  bool
+		wantSummary string
+	}{
+		{"running without healthcheck", ServiceStatus{State: "running"}, true, false, "running"},
+		{"running and healthy", ServiceStatus{State: "running", Health: "healthy"}, true, false, "running (healthy)"},
+		{"starting healthcheck", ServiceStatus{State: "running", Health: "starting"}, false, false, "running (starting)"},
+		{"unhealthy", ServiceStatus{State: "running", Health: "unhealthy"}, false, true, "running (unhealthy)"},
+		{"one-shot completed", ServiceStatus{State: "exited", ExitCode: 0}, true, false, "exited (0)"},
+		{"crashed", ServiceStatus{State: "exited", ExitCode: 1}, false, true, "exited (1)"},
+		{"restarting", ServiceStatus{State: "restarting"}, false, false, "restarting"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.status.IsReady(); got != tt.wantReady {
+				t.Errorf("IsReady() = %v, want %v", got, tt.wantReady)
+			}
+			if got := tt.status.IsFailed(); got != tt.wantFailed {
+				t.Errorf("IsFailed() = %v, want %v", got, tt.wantFailed)
+			}
+			if got := tt.status.Summary(); got != tt.wantSummary {
+				t.Errorf("Summary() = %q, want %q", got, tt.wantSummary)
+			}
+		})
+	}
+}
+
+// TestProjectName tests compose project naming.
+func TestProjectName(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/home/user/my-api", "my-api_devcontainer"},
+		{"/home/user/My App", "myapp_devcontainer"},
+		{"/src/shop.v2", "shopv2_devcontainer"},
+	}
+
+	for _, tt := range tests {
+		if got := ProjectName(tt.path); got != tt.want {
+			t.Errorf("ProjectName(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestParseRestartCounts tests parsing restart counts from docker inspect output.
+func TestParseRestartCounts(t *testing.T) {
+	input := `[
+		{"Name": "/api_devcontainer-app-1", "RestartCount": 0, "State": {"Status": "running"}},
+		{"Name": "/api_devcontainer-worker-1", "RestartCount": 4, "State": {"Status": "restarting"}}
+	]`
+
+	counts, err := ParseRestartCounts([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseRestartCounts() error = %v", err)
+	}
+	if counts["api_devcontainer-app-1"] != 0 {
+		t.Errorf("app restarts = %d, want 0", counts["api_devcontainer-app-1"])
+	}
+	if counts["api_devcontainer-worker-1"] != 4 {
+		t.Errorf("worker restarts = %d, want 4", counts["api_devcontainer-worker-1"])
+	}
+
+	if _, err := ParseRestartCounts([]byte("not json")); err == nil {
+		t.Error("ParseRestartCounts() should fail on invalid output")
+	}
+}
+
+// TestErrorLines tests extraction of recent error lines from logs.
+func TestErrorLines(t *testing.T) {
+	logs := `2024-05-01 10:00:00 UTC LOG:  database system is ready to accept connections
+2024-05-01 10:00:01 UTC ERROR:  relation "users" does not exist
+worker started
+Traceback (most recent call last):
+panic: runtime error: invalid memory address
+terrible terror
+`
+
+	got := ErrorLines(logs, 2)
+	want := []string{
+		"Traceback (most recent call last):",
+		"panic: runtime error: invalid memory address",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ErrorLines() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ErrorLines()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if got := ErrorLines("all good\n", 3); len(got) != 0 {
+		t.Errorf("ErrorLines() = %v, want none", got)
+	}
+}
+
+// TestServiceStatusPorts tests formatting of published ports.
+func TestServiceStatusPorts(t *testing.T) {
+	s := ServiceStatus{
+		Publishers: []Publisher{
+			{URL: "0.0.0.0", TargetPort: 5432, PublishedPort: 5432, Protocol: "tcp"},
+			{URL: "::", TargetPort: 5432, PublishedPort: 5432, Protocol: "tcp"},
+			{URL: "", TargetPort: 9000, PublishedPort: 0, Protocol: "tcp"},
+			{URL: "0.0.0.0", TargetPort: 8125, PublishedPort: 8125, Protocol: "udp"},
+		},
+	}
+
+	got := s.Ports()
+	want := []string{"5432→5432", "8125→8125/udp"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Ports() = %v, want %v", got, want)
+	}
+}
+
+// TestHostUserEnv tests that the host UID/GID are passed to compose builds.
+func TestHostUserEnv(t *testing.T) {
+	got := hostUserEnv([]string{"PATH=/usr/bin"}, 1001, 1002)
+	want := []string{"PATH=/usr/bin", "USER_UID=1001", "USER_GID=1002"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("hostUserEnv() = %v, want %v", got, want)
+	}
+
+	// Values from the environment win
+	got = hostUserEnv([]string{"USER_UID=2000"}, 1001, 1002)
+	want = []string{"USER_UID=2000", "USER_GID=1002"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("hostUserEnv() = %v, want %v", got, want)
+	}
+
+	// Windows has no UID/GID
+	if got := hostUserEnv(nil, -1, -1); len(got) != 0 {
+		t.Errorf("hostUserEnv() = %v, want none", got)
+	}
+}
+
+// TestParseComposeImages tests reading service images from compose config output.
+func TestParseComposeImages(t *testing.T) {
+	data := []byte(`{
+  "name": "myapp_devcontainer",
+  "services": {
+    "postgres": {"image": "postgres:16-alpine"},
+    "app": {"build": {"context": "/src", "dockerfile": ".devcontainer/Dockerfile"}},
+    "db-backup": {"build": {"context": "/src/.devcontainer"}, "image": "backup:dev"}
+  }
+}`)
+
+	images, err := ParseComposeImages(data, "myapp_devcontainer")
+	if err != nil {
+		t.Fatalf("ParseComposeImages() error = %v", err)
+	}
+
+	want := []ComposeImage{
+		{Service: "app", Image: "myapp_devcontainer-app", Built: true},
+		{Service: "db-backup", Image: "backup:dev", Built: true},
+		{Service: "postgres", Image: "postgres:16-alpine"},
+	}
+	if len(images) != len(want) {
+		t.Fatalf("ParseComposeImages() = %+v, want %+v", images, want)
+	}
+	for i := range want {
+		if images[i] != want[i] {
+			t.Errorf("images[%d] = %+v, want %+v", i, images[i], want[i])
+		}
+	}
+
+	if _, err := ParseComposeImages([]byte("not json"), "x"); err == nil {
+		t.Error("ParseComposeImages() expected error for invalid output")
+	}
+}
+
+// TestParseTrivy tests counting vulnerabilities per severity in Trivy output.
+func TestParseTrivy(t *testing.T) {
+	data := []byte(`{
+  "Results": [
+    {"Target": "alpine", "Vulnerabilities": [
+      {"VulnerabilityID": "CVE-1", "PkgName": "openssl", "InstalledVersion": "3.0", "Severity": "CRITICAL"},
+      {"VulnerabilityID": "CVE-2", "PkgName": "zlib", "InstalledVersion": "1.2", "Severity": "HIGH"},
+      {"VulnerabilityID": "CVE-3", "PkgName": "busybox", "InstalledVersion": "1.36", "Severity": "low"}
+    ]},
+    {"Target": "app.jar", "Vulnerabilities": [
+      {"VulnerabilityID": "CVE-2", "PkgName": "zlib", "InstalledVersion": "1.2", "Severity": "HIGH"},
+      {"VulnerabilityID": "CVE-4", "PkgName": "log4j", "InstalledVersion": "2.14", "Severity": "NEGLIGIBLE"}
+    ]},
+    {"Target": "node_modules", "Vulnerabilities": null}
+  ]
+}`)
+
+	counts, err := ParseTrivy(data)
+	if err != nil {
+		t.Fatalf("ParseTrivy() error = %v", err)
+	}
+
+	want := map[string]int{"CRITICAL": 1, "HIGH": 1, "LOW": 1, "UNKNOWN": 1}
+	if len(counts) != len(want) {
+		t.Errorf("ParseTrivy() = %v, want %v", counts, want)
+	}
+	for severity, n := range want {
+		if counts[severity] != n {
+			t.Errorf("counts[%s] = %d, want %d", severity, counts[severity], n)
+		}
+	}
+
+	result := &ScanResult{Image: "alpine", Counts: counts}
+	if got := result.Total(); got != 4 {
+		t.Errorf("Total() = %d, want 4", got)
+	}
+	if got := result.AtOrAbove("high"); got != 2 {
+		t.Errorf("AtOrAbove(high) = %d, want 2", got)
+	}
+	if got := result.AtOrAbove("UNKNOWN"); got != 4 {
+		t.Errorf("AtOrAbove(UNKNOWN) = %d, want 4", got)
+	}
+
+	if SeverityRank("bogus") != -1 {
+		t.Error("SeverityRank(bogus) should be -1")
+	}
+}
+
+// TestParseMemTotal tests parsing the engine memory printed by docker info.
+func TestParseMemTotal(t *testing.T) {
+	total, err := ParseMemTotal([]byte("8241008640\n"))
+	if err != nil {
+		t.Fatalf("ParseMemTotal() error = %v", err)
+	}
+	if total != 8241008640 {
+		t.Errorf("ParseMemTotal() = %d, want 8241008640", total)
+	}
+
+	for _, input := range []string{"", "<no value>", "0"} {
+		if _, err := ParseMemTotal([]byte(input)); err == nil {
+			t.Errorf("ParseMemTotal(%q) should fail", input)
+		}
+	}
+}
+
+// TestComposeCommandLogging tests that docker commands are logged when a logger is set.
+func TestComposeCommandLogging(t *testing.T) {
+	var logs bytes.Buffer
+	compose := NewCompose("/src/shop")
+	compose.Logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	cmd := compose.command(compose.args("ps")...)
+	if got := strings.Join(cmd.Args, " "); !strings.HasSuffix(got, "-p shop_devcontainer ps") {
+		t.Errorf("command args = %q", got)
+	}
+	want := `msg="running docker" args="compose -f /src/shop/.devcontainer/docker-compose.yml -p shop_devcontainer ps"`
+	if !strings.Contains(logs.String(), want) {
+		t.Errorf("logs = %q, want %s", logs.String(), want)
+	}
+}
@@ -0,0 +1,197 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// DevcontainerCLIPackage is the npm package of the dev containers CLI, run
+// with npx when the devcontainer command isn't installed.
+const DevcontainerCLIPackage = "@devcontainers/cli"
+
+// Devcontainer runs the dev containers CLI against a project's .devcontainer,
+// the way VS Code's "Reopen in Container" builds and starts it.
+type Devcontainer struct {
+	// WorkspaceFolder is the project root
+	WorkspaceFolder string
+
+	// Command runs the CLI: devcontainer, or npx with DevcontainerCLIPackage
+	Command []string
+
+	// Stderr receives the CLI's progress logs (default: os.Stderr)
+	Stderr io.Writer
+
+	// Logger, when set, logs each CLI command at debug level
+	Logger *slog.Logger
+}
+
+// NewDevcontainer creates a Devcontainer for a project, with the installed
+// devcontainer command or, without it, npx.
+func NewDevcontainer(projectPath string) (*Devcontainer, error) {
+	command, err := devcontainerCommand(exec.LookPath)
+	if err != nil {
+		return nil, err
+	}
+	return &Devcontainer{
+		WorkspaceFolder: projectPath,
+		Command:         command,
+		Stderr:          os.Stderr,
+	}, nil
+}
+
+// devcontainerCommand returns the command that runs the CLI, looking up
+// executables with lookPath.
+func devcontainerCommand(lookPath func(string) (string, error)) ([]string, error) {
+	if _, err := lookPath("devcontainer"); err == nil {
+		return []string{"devcontainer"}, nil
+	}
+	if _, err := lookPath("npx"); err == nil {
+		return []string{"npx", "--yes", DevcontainerCLIPackage}, nil
+	}
+	return nil, fmt.Errorf("devcontainer CLI not found in PATH: install it with npm install -g %s", DevcontainerCLIPackage)
+}
+
+// DevcontainerResult is the outcome the CLI prints as JSON on stdout.
+type DevcontainerResult struct {
+	// Outcome is "success" or "error"
+	Outcome string `json:"outcome"`
+
+	// Message and Description explain an error
+	Message     string `json:"message"`
+	Description string `json:"description"`
+
+	// ContainerID is the dev container started by up
+	ContainerID string `json:"containerId"`
+
+	// ComposeProjectName is the stack's compose project, for Compose dev containers
+	ComposeProjectName string `json:"composeProjectName"`
+
+	// RemoteUser and RemoteWorkspaceFolder are where the dev container's tools run
+	RemoteUser            string `json:"remoteUser"`
+	RemoteWorkspaceFolder string `json:"remoteWorkspaceFolder"`
+}
+
+// Up builds and starts the dev container and runs its lifecycle commands up
+// to postStartCommand. removeExisting replaces a dev container already running.
+func (d *Devcontainer) Up(removeExisting bool) (*DevcontainerResult, error) {
+	args := []string{"--workspace-folder", d.WorkspaceFolder}
+	if removeExisting {
+		args = append(args, "--remove-existing-container")
+	}
+	return d.run("up", args...)
+}
+
+// Build builds the dev container's image, tagged imageName when set, and
+// pushes it when push is set.
+func (d *Devcontainer) Build(imageName string, push bool) (*DevcontainerResult, error) {
+	args := []string{"--workspace-folder", d.WorkspaceFolder}
+	if imageName != "" {
+		args = append(args, "--image-name", imageName)
+	}
+	if push {
+		args = append(args, "--push")
+	}
+	return d.run("build", args...)
+}
+
+// run runs a CLI subcommand and returns its outcome, or an error when the
+// subcommand, or a lifecycle command it ran, failed.
+func (d *Devcontainer) run(subcommand string, args ...string) (*DevcontainerResult, error) {
+	args = append(append(append([]string{}, d.Command[1:]...), subcommand), args...)
+	if d.Logger != nil {
+		d.Logger.Debug("running devcontainer", "command", d.Command[0], "args", strings.Join(args, " "))
+	}
+	cmd := exec.Command(d.Command[0], args...)
+	// Compose dev containers pass the host UID/GID to non-root image builds
+	cmd.Env = hostUserEnv(os.Environ(), os.Getuid(), os.Getgid())
+	cmd.Stderr = d.Stderr
+	stdout, runErr := cmd.Output()
+
+	result, err := ParseDevcontainerResult(stdout)
+	if err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("devcontainer %s failed: %w", subcommand, runErr)
+		}
+		return nil, err
+	}
+	if result.Outcome != "success" {
+		return result, fmt.Errorf("devcontainer %s failed: %s", subcommand, result.Reason())
+	}
+	return result, nil
+}
+
+// Reason returns the CLI's explanation of a failed outcome.
+func (r *DevcontainerResult) Reason() string {
+	if r.Description != "" && r.Description != r.Message {
+		return r.Message + ": " + r.Description
+	}
+	return r.Message
+}
+
+// ParseDevcontainerResult parses the CLI's stdout, whose last JSON line is the outcome.
+func ParseDevcontainerResult(data []byte) (*DevcontainerResult, error) {
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var result DevcontainerResult
+		if err := json.Unmarshal([]byte(line), &result); err == nil && result.Outcome != "" {
+			return &result, nil
+		}
+	}
+	return nil, fmt.Errorf("no outcome in devcontainer output: %q", strings.TrimSpace(string(data)))
+}
+
+// ContainerStatus returns the state of a container by ID, as a ServiceStatus
+// of service.
+func ContainerStatus(id, service string) (ServiceStatus, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("docker", "inspect", id)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return ServiceStatus{}, fmt.Errorf("docker inspect failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	status, err := ParseContainerStatus(out)
+	status.Service = service
+	return status, err
+}
+
+// ParseContainerStatus parses the `docker inspect` output of one container.
+func ParseContainerStatus(data []byte) (ServiceStatus, error) {
+	var containers []struct {
+		Name  string `json:"Name"`
+		State struct {
+			Status   string `json:"Status"`
+			ExitCode int    `json:"ExitCode"`
+			Health   *struct {
+				Status string `json:"Status"`
+			} `json:"Health"`
+		} `json:"State"`
+	}
+	if err := json.Unmarshal(data, &containers); err != nil {
+		return ServiceStatus{}, fmt.Errorf("failed to parse docker inspect output: %w", err)
+	}
+	if len(containers) != 1 {
+		return ServiceStatus{}, fmt.Errorf("docker inspect returned %d containers, want 1", len(containers))
+	}
+
+	c := containers[0]
+	status := ServiceStatus{
+		Name:     strings.TrimPrefix(c.Name, "/"),
+		State:    c.State.Status,
+		ExitCode: c.State.ExitCode,
+	}
+	if c.State.Health != nil {
+		status.Health = c.State.Health.Status
+	}
+	return status, nil
+}
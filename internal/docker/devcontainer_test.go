@@ -0,0 +1,120 @@
+package docker
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestParseDevcontainerResult tests reading the outcome from the CLI's stdout.
+func TestParseDevcontainerResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantErr    bool
+		want       DevcontainerResult
+		wantReason string
+	}{
+		{
+			name:  "up success",
+			input: `{"outcome":"success","containerId":"3f2a9c1b7d4e","composeProjectName":"shop_devcontainer","remoteUser":"node","remoteWorkspaceFolder":"/workspace"}`,
+			want: DevcontainerResult{
+				Outcome:               "success",
+				ContainerID:           "3f2a9c1b7d4e",
+				ComposeProjectName:    "shop_devcontainer",
+				RemoteUser:            "node",
+				RemoteWorkspaceFolder: "/workspace",
+			},
+		},
+		{
+			name: "lifecycle command failure after other output",
+			input: "npm notice New version available\n" +
+				`{"outcome":"error","message":"Command failed: /bin/sh -c npm ci","description":"The postCreateCommand in the devcontainer.json failed."}` + "\n",
+			want: DevcontainerResult{
+				Outcome:     "error",
+				Message:     "Command failed: /bin/sh -c npm ci",
+				Description: "The postCreateCommand in the devcontainer.json failed.",
+			},
+			wantReason: "Command failed: /bin/sh -c npm ci: The postCreateCommand in the devcontainer.json failed.",
+		},
+		{
+			name:    "no outcome",
+			input:   "Error: unknown command\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDevcontainerResult([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDevcontainerResult() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if *got != tt.want {
+				t.Errorf("ParseDevcontainerResult() = %+v, want %+v", *got, tt.want)
+			}
+			if tt.wantReason != "" && got.Reason() != tt.wantReason {
+				t.Errorf("Reason() = %q, want %q", got.Reason(), tt.wantReason)
+			}
+		})
+	}
+}
+
+// TestParseContainerStatus tests reading a dev container's state from docker inspect.
+func TestParseContainerStatus(t *testing.T) {
+	status, err := ParseContainerStatus([]byte(`[{"Name":"/shop-devcontainer","State":{"Status":"running","ExitCode":0,"Health":{"Status":"starting"}}}]`))
+	if err != nil {
+		t.Fatalf("ParseContainerStatus() error = %v", err)
+	}
+	if status.Name != "shop-devcontainer" || status.State != "running" || status.Health != "starting" || status.IsReady() {
+		t.Errorf("ParseContainerStatus() = %+v, want a running container still starting", status)
+	}
+
+	status, err = ParseContainerStatus([]byte(`[{"Name":"/shop","State":{"Status":"running","ExitCode":0,"Health":null}}]`))
+	if err != nil {
+		t.Fatalf("ParseContainerStatus() error = %v", err)
+	}
+	if !status.IsReady() {
+		t.Errorf("a running container without a healthcheck should be ready, got %+v", status)
+	}
+
+	if _, err := ParseContainerStatus([]byte(`[]`)); err == nil {
+		t.Error("ParseContainerStatus() should fail without a container")
+	}
+}
+
+// TestDevcontainerCommand tests falling back to npx without an installed CLI.
+func TestDevcontainerCommand(t *testing.T) {
+	lookPath := func(installed ...string) func(string) (string, error) {
+		return func(file string) (string, error) {
+			if slices.Contains(installed, file) {
+				return "/usr/local/bin/" + file, nil
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	tests := []struct {
+		name      string
+		installed []string
+		want      []string
+	}{
+		{name: "installed", installed: []string{"devcontainer", "npx"}, want: []string{"devcontainer"}},
+		{name: "npx", installed: []string{"npx"}, want: []string{"npx", "--yes", DevcontainerCLIPackage}},
+		{name: "neither"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := devcontainerCommand(lookPath(tt.installed...))
+			if (err != nil) != (tt.want == nil) {
+				t.Fatalf("devcontainerCommand() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("devcontainerCommand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
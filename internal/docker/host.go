@@ -0,0 +1,47 @@
+package docker
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Host returns the endpoint of the Docker engine the docker CLI talks to:
+// DOCKER_HOST, or the endpoint of the current docker context (DOCKER_CONTEXT,
+// or the one selected with "docker context use").
+func Host() (string, error) {
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		return host, nil
+	}
+	out, err := exec.Command("docker", "context", "inspect", "--format", "{{.Endpoints.docker.Host}}").Output()
+	if err != nil {
+		return "", fmt.Errorf("docker context inspect failed: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// IsRemote reports whether an engine endpoint is on another machine, where
+// bind mounts of local paths don't work. Unix sockets, Windows named pipes,
+// and TCP on a loopback address are local; SSH and other TCP hosts are remote.
+func IsRemote(host string) bool {
+	u, err := url.Parse(host)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "ssh":
+		return true
+	case "tcp", "http", "https":
+		name := u.Hostname()
+		if name == "localhost" {
+			return false
+		}
+		ip := net.ParseIP(name)
+		return ip == nil || !ip.IsLoopback()
+	default:
+		return false
+	}
+}
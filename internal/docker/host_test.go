@@ -0,0 +1,29 @@
+package docker
+
+import "testing"
+
+// TestIsRemote tests which engine endpoints are on another machine.
+func TestIsRemote(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"", false},
+		{"unix:///var/run/docker.sock", false},
+		{"unix:///Users/dev/.docker/run/docker.sock", false},
+		{"npipe:////./pipe/docker_engine", false},
+		{"tcp://localhost:2375", false},
+		{"tcp://127.0.0.1:2376", false},
+		{"tcp://[::1]:2375", false},
+		{"tcp://192.168.1.20:2376", true},
+		{"tcp://build-box.internal:2376", true},
+		{"ssh://dev@build-box", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := IsRemote(tt.host); got != tt.want {
+				t.Errorf("IsRemote(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package docker
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// MemTotal returns the memory available to the Docker engine, in bytes: the
+// host's RAM on Linux, or the Docker Desktop VM's on macOS and Windows.
+func MemTotal() (int64, error) {
+	out, err := exec.Command("docker", "info", "--format", "{{.MemTotal}}").Output()
+	if err != nil {
+		return 0, fmt.Errorf("docker info failed: %w", err)
+	}
+	return ParseMemTotal(out)
+}
+
+// ParseMemTotal parses the MemTotal printed by docker info.
+func ParseMemTotal(data []byte) (int64, error) {
+	total, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil || total <= 0 {
+		return 0, fmt.Errorf("unexpected docker info MemTotal %q", strings.TrimSpace(string(data)))
+	}
+	return total, nil
+}
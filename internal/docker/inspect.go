@@ -0,0 +1,103 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// errorLinePattern matches log lines that look like errors.
+var errorLinePattern = regexp.MustCompile(`(?i)\b(error|err|fatal|panic|exception|traceback|failed|critical)\b`)
+
+// RestartCounts returns the restart count of each named container, keyed by container name.
+func RestartCounts(containers []string) (map[string]int, error) {
+	if len(containers) == 0 {
+		return map[string]int{}, nil
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("docker", append([]string{"inspect"}, containers...)...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker inspect failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return ParseRestartCounts(out)
+}
+
+// ParseRestartCounts parses `docker inspect` output into restart counts keyed by container name.
+func ParseRestartCounts(data []byte) (map[string]int, error) {
+	var containers []struct {
+		Name         string `json:"Name"`
+		RestartCount int    `json:"RestartCount"`
+	}
+	if err := json.Unmarshal(data, &containers); err != nil {
+		return nil, fmt.Errorf("failed to parse docker inspect output: %w", err)
+	}
+
+	counts := make(map[string]int, len(containers))
+	for _, c := range containers {
+		counts[strings.TrimPrefix(c.Name, "/")] = c.RestartCount
+	}
+	return counts, nil
+}
+
+// Logs returns the last tail lines of a service's logs, without colors or prefixes.
+func (c *Compose) Logs(service string, tail int) (string, error) {
+	var stderr bytes.Buffer
+	cmd := c.command(c.args("logs", "--no-color", "--no-log-prefix", "--tail", fmt.Sprint(tail), service)...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("docker compose logs %s failed: %w: %s", service, err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
+
+// ErrorLines returns up to max of the most recent lines in logs that look like errors.
+func ErrorLines(logs string, max int) []string {
+	var matches []string
+	for _, line := range strings.Split(logs, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && errorLinePattern.MatchString(line) {
+			matches = append(matches, line)
+		}
+	}
+
+	if len(matches) > max {
+		matches = matches[len(matches)-max:]
+	}
+	return matches
+}
+
+// Ports returns the published ports of a service as "host→container" strings.
+func (s ServiceStatus) Ports() []string {
+	var ports []string
+	for _, p := range s.Publishers {
+		if p.PublishedPort == 0 {
+			continue
+		}
+		port := fmt.Sprintf("%d→%d", p.PublishedPort, p.TargetPort)
+		if p.Protocol != "" && p.Protocol != "tcp" {
+			port += "/" + p.Protocol
+		}
+		if !containsPort(ports, port) {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+// containsPort checks if a port string is already in the list.
+// Compose lists IPv4 and IPv6 bindings separately, so ports repeat.
+func containsPort(ports []string, port string) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// TrivyImage is the Trivy scanner image run by Scan.
+const TrivyImage = "aquasec/trivy:latest"
+
+// trivyCacheVolume keeps Trivy's vulnerability database between scans.
+const trivyCacheVolume = "dockstart-trivy-cache"
+
+// Severities are the Trivy severity levels, from lowest to highest.
+var Severities = []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+// SeverityRank returns the position of a severity in Severities, or -1 if it
+// is not a Trivy severity. Matching is case-insensitive.
+func SeverityRank(severity string) int {
+	for i, s := range Severities {
+		if strings.EqualFold(s, severity) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ComposeImage is an image used by a compose service.
+type ComposeImage struct {
+	// Service is the compose service name
+	Service string
+
+	// Image is the image reference
+	Image string
+
+	// Built is true when compose builds the image instead of pulling it
+	Built bool
+}
+
+// Images returns the image of every service in the compose file, including
+// the names compose gives images it builds.
+func (c *Compose) Images() ([]ComposeImage, error) {
+	var stderr bytes.Buffer
+	cmd := c.command(c.args("config", "--format", "json")...)
+	cmd.Env = hostUserEnv(os.Environ(), os.Getuid(), os.Getgid())
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker compose config failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return ParseComposeImages(out, c.ProjectName)
+}
+
+// ParseComposeImages parses `docker compose config --format json` output into
+// the image of each service, sorted by service name. Built services without an
+// image name get compose's default "<project>-<service>".
+func ParseComposeImages(data []byte, projectName string) ([]ComposeImage, error) {
+	var config struct {
+		Services map[string]struct {
+			Image string          `json:"image"`
+			Build json.RawMessage `json:"build"`
+		} `json:"services"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse compose config output: %w", err)
+	}
+
+	images := make([]ComposeImage, 0, len(config.Services))
+	for name, service := range config.Services {
+		image := ComposeImage{
+			Service: name,
+			Image:   service.Image,
+			Built:   len(service.Build) > 0 && string(service.Build) != "null",
+		}
+		if image.Image == "" {
+			image.Image = projectName + "-" + name
+		}
+		images = append(images, image)
+	}
+
+	sort.Slice(images, func(i, j int) bool { return images[i].Service < images[j].Service })
+	return images, nil
+}
+
+// Build builds the images of the given services (all built services if none).
+// Output from docker compose is streamed to Stdout/Stderr.
+func (c *Compose) Build(services ...string) error {
+	cmd := c.command(c.args(append([]string{"build"}, services...)...)...)
+	cmd.Env = hostUserEnv(os.Environ(), os.Getuid(), os.Getgid())
+	cmd.Stdout = c.Stdout
+	cmd.Stderr = c.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("docker compose build failed: %w", err)
+	}
+	return nil
+}
+
+// ImageExists reports whether an image is present in the local image store.
+func ImageExists(image string) bool {
+	return exec.Command("docker", "image", "inspect", image).Run() == nil
+}
+
+// ScanResult is the outcome of a Trivy scan of one image.
+type ScanResult struct {
+	// Image is the scanned image reference
+	Image string
+
+	// Counts is the number of vulnerabilities found per severity
+	Counts map[string]int
+}
+
+// Total returns the number of vulnerabilities found.
+func (r *ScanResult) Total() int {
+	total := 0
+	for _, n := range r.Counts {
+		total += n
+	}
+	return total
+}
+
+// AtOrAbove returns the number of vulnerabilities at or above a severity.
+func (r *ScanResult) AtOrAbove(severity string) int {
+	rank := SeverityRank(severity)
+	total := 0
+	for s, n := range r.Counts {
+		if SeverityRank(s) >= rank {
+			total += n
+		}
+	}
+	return total
+}
+
+// Scan runs Trivy in a container against an image. Local images are read
+// through the Docker socket; others are pulled from their registry. With
+// ignoreUnfixed, vulnerabilities without a fixed version are skipped.
+func Scan(image string, ignoreUnfixed bool) (*ScanResult, error) {
+	args := []string{
+		"run", "--rm",
+		"-v", "/var/run/docker.sock:/var/run/docker.sock",
+		"-v", trivyCacheVolume + ":/root/.cache/",
+		TrivyImage,
+		"image", "--quiet", "--format", "json", "--scanners", "vuln",
+	}
+	if ignoreUnfixed {
+		args = append(args, "--ignore-unfixed")
+	}
+	args = append(args, image)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("docker", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("trivy scan of %s failed: %w: %s", image, err, strings.TrimSpace(stderr.String()))
+	}
+
+	counts, err := ParseTrivy(out)
+	if err != nil {
+		return nil, err
+	}
+	return &ScanResult{Image: image, Counts: counts}, nil
+}
+
+// ParseTrivy parses `trivy image --format json` output into vulnerability
+// counts per severity. A vulnerability reported for several files of the same
+// package (e.g., two copies of a jar) is counted once.
+func ParseTrivy(data []byte) (map[string]int, error) {
+	var report struct {
+		Results []struct {
+			Vulnerabilities []struct {
+				VulnerabilityID  string `json:"VulnerabilityID"`
+				PkgName          string `json:"PkgName"`
+				InstalledVersion string `json:"InstalledVersion"`
+				Severity         string `json:"Severity"`
+			} `json:"Vulnerabilities"`
+		} `json:"Results"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse trivy output: %w", err)
+	}
+
+	counts := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, result := range report.Results {
+		for _, v := range result.Vulnerabilities {
+			key := v.VulnerabilityID + " " + v.PkgName + " " + v.InstalledVersion
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			severity := strings.ToUpper(v.Severity)
+			if SeverityRank(severity) < 0 {
+				severity = "UNKNOWN"
+			}
+			counts[severity]++
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,49 @@
+package docker
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Down stops and removes the stack's containers and networks, including
+// containers for services no longer in the compose file. Volumes are kept.
+func (c *Compose) Down() error {
+	cmd := c.command(c.args("down", "--remove-orphans")...)
+	cmd.Stdout = c.Stdout
+	cmd.Stderr = c.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("docker compose down failed: %w", err)
+	}
+	return nil
+}
+
+// Volumes returns the named volumes created for the compose project.
+// Volumes are matched by compose project label, so volumes from services
+// that were since removed from docker-compose.yml are included.
+func (c *Compose) Volumes() ([]string, error) {
+	var stderr bytes.Buffer
+	cmd := c.command("volume", "ls", "--quiet", "--filter", "label=com.docker.compose.project="+c.ProjectName)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker volume ls failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// RemoveVolumes deletes the named volumes.
+func RemoveVolumes(volumes []string) error {
+	if len(volumes) == 0 {
+		return nil
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("docker", append([]string{"volume", "rm"}, volumes...)...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("docker volume rm failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
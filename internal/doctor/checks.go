@@ -0,0 +1,280 @@
+package doctor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// minDockerMajor is the oldest Docker Engine major version known to work
+	minDockerMajor = 20
+
+	// minComposeMinor is the oldest Compose v2 minor version known to work
+	// (healthcheck-based depends_on conditions and "ps --format json")
+	minComposeMinor = 17
+
+	// recommendedInotifyWatches is the inotify watch limit recommended for file watchers
+	recommendedInotifyWatches = 524288
+
+	// warnFreeBytes and failFreeBytes are the free disk space thresholds
+	warnFreeBytes = 10 << 30
+	failFreeBytes = 2 << 30
+)
+
+// CheckDocker checks that the Docker daemon is reachable and recent enough.
+func CheckDocker() Result {
+	result := Result{Name: "Docker"}
+
+	version, err := runCommand(10*time.Second, "docker", "version", "--format", "{{.Server.Version}}")
+	if err != nil {
+		if _, lookErr := runCommand(5*time.Second, "docker", "--version"); lookErr != nil {
+			result.Status = StatusFail
+			result.Message = "docker CLI not found"
+			result.Fix = "Install Docker Desktop (macOS/Windows) or Docker Engine: https://docs.docker.com/get-docker/"
+			return result
+		}
+		result.Status = StatusFail
+		result.Message = "Docker daemon is not reachable"
+		result.Fix = "Start Docker Desktop, or run: sudo systemctl start docker"
+		return result
+	}
+
+	if major, _, ok := parseVersion(version); ok && major < minDockerMajor {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("Docker %s is older than %d.0", version, minDockerMajor)
+		result.Fix = "Upgrade Docker to a current release"
+		return result
+	}
+
+	result.Status = StatusOK
+	result.Message = "Docker " + version
+	return result
+}
+
+// CheckCompose checks that the Compose v2 plugin is installed and recent enough.
+func CheckCompose() Result {
+	result := Result{Name: "Docker Compose"}
+
+	version, err := runCommand(10*time.Second, "docker", "compose", "version", "--short")
+	if err != nil {
+		if _, v1Err := runCommand(5*time.Second, "docker-compose", "--version"); v1Err == nil {
+			result.Status = StatusFail
+			result.Message = "only the legacy docker-compose (v1) is installed"
+			result.Fix = "Install the Compose v2 plugin: https://docs.docker.com/compose/install/"
+			return result
+		}
+		result.Status = StatusFail
+		result.Message = "docker compose plugin not found"
+		result.Fix = "Install the Compose v2 plugin: https://docs.docker.com/compose/install/"
+		return result
+	}
+
+	if !versionAtLeast(version, 2, minComposeMinor) {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("Compose %s is older than 2.%d", version, minComposeMinor)
+		result.Fix = "Upgrade Docker Compose to 2." + strconv.Itoa(minComposeMinor) + " or newer"
+		return result
+	}
+
+	result.Status = StatusOK
+	result.Message = "Compose " + version
+	return result
+}
+
+// CheckPodman reports whether Podman is installed. Podman is only an alternative,
+// so a missing Podman is never a failure.
+func CheckPodman(dockerOK bool) Result {
+	result := Result{Name: "Podman", Status: StatusOK}
+
+	version, err := runCommand(5*time.Second, "podman", "--version")
+	if err != nil {
+		result.Message = "not installed (optional)"
+		return result
+	}
+
+	result.Message = strings.TrimPrefix(version, "podman version ")
+	if !dockerOK {
+		result.Status = StatusWarn
+		result.Message = "Podman " + result.Message + " found but Docker is unavailable"
+		result.Fix = "Enable the Docker-compatible socket: systemctl --user enable --now podman.socket, then export DOCKER_HOST=unix://$XDG_RUNTIME_DIR/podman/podman.sock"
+	}
+	return result
+}
+
+// CheckDiskSpace checks free space on the filesystem holding the project.
+func CheckDiskSpace(path string) Result {
+	result := Result{Name: "Disk space"}
+
+	free, err := freeDiskSpace(path)
+	if err != nil {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("could not determine free space: %v", err)
+		return result
+	}
+
+	freeGB := float64(free) / (1 << 30)
+	switch {
+	case free < failFreeBytes:
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("%.1f GB free", freeGB)
+		result.Fix = "Free up space, e.g. run: docker system prune"
+	case free < warnFreeBytes:
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("%.1f GB free (images for a full stack can need several GB)", freeGB)
+		result.Fix = "Free up space, e.g. run: docker system prune"
+	default:
+		result.Status = StatusOK
+		result.Message = fmt.Sprintf("%.1f GB free", freeGB)
+	}
+	return result
+}
+
+// CheckInotify checks the inotify watch limit used by file watchers (HMR, nodemon, air).
+// Only Linux has this limit; other platforms always pass.
+func CheckInotify() Result {
+	result := Result{Name: "File watchers", Status: StatusOK}
+
+	if runtime.GOOS != "linux" {
+		result.Message = "no inotify limit on " + runtime.GOOS
+		return result
+	}
+
+	data, err := os.ReadFile("/proc/sys/fs/inotify/max_user_watches")
+	if err != nil {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("could not read inotify limit: %v", err)
+		return result
+	}
+
+	return inotifyResult(string(data))
+}
+
+// inotifyResult evaluates the contents of /proc/sys/fs/inotify/max_user_watches.
+func inotifyResult(value string) Result {
+	result := Result{Name: "File watchers"}
+
+	watches, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("unexpected inotify limit %q", strings.TrimSpace(value))
+		return result
+	}
+
+	if watches < recommendedInotifyWatches {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("max_user_watches is %d; file watchers may miss changes in large projects", watches)
+		result.Fix = fmt.Sprintf("Run: echo fs.inotify.max_user_watches=%d | sudo tee /etc/sysctl.d/90-inotify.conf && sudo sysctl --system", recommendedInotifyWatches)
+		return result
+	}
+
+	result.Status = StatusOK
+	result.Message = fmt.Sprintf("max_user_watches is %d", watches)
+	return result
+}
+
+// CheckPorts checks that each host port the stack needs is free.
+func CheckPorts(ports []int) []Result {
+	var results []Result
+	for _, port := range ports {
+		result := Result{Name: fmt.Sprintf("Port %d", port)}
+
+		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+		if err != nil {
+			result.Status = StatusWarn
+			result.Message = "already in use"
+			result.Fix = fmt.Sprintf("Stop the process using it (lsof -i :%d) or change the port mapping in docker-compose.yml", port)
+		} else {
+			listener.Close()
+			result.Status = StatusOK
+			result.Message = "available"
+		}
+
+		results = append(results, result)
+	}
+	return results
+}
+
+// CheckImagePlatforms checks that each image is published for the Docker host's architecture.
+func CheckImagePlatforms(images []string) []Result {
+	arch, err := runCommand(10*time.Second, "docker", "version", "--format", "{{.Server.Arch}}")
+	if err != nil || arch == "" {
+		arch = runtime.GOARCH
+	}
+
+	var results []Result
+	for _, image := range images {
+		result := Result{Name: "Image " + image}
+
+		manifest, err := runCommand(30*time.Second, "docker", "manifest", "inspect", image)
+		if err != nil {
+			result.Status = StatusWarn
+			result.Message = "could not inspect manifest (offline or private registry?)"
+			result.Fix = "Check network access, or run `docker login` for private registries"
+			results = append(results, result)
+			continue
+		}
+
+		platforms, err := manifestArchitectures([]byte(manifest))
+		switch {
+		case err != nil:
+			result.Status = StatusWarn
+			result.Message = fmt.Sprintf("could not parse manifest: %v", err)
+		case len(platforms) == 0:
+			// Single-platform manifest: the platform isn't listed, assume it matches
+			result.Status = StatusOK
+			result.Message = "single-platform image"
+		case containsArch(platforms, arch):
+			result.Status = StatusOK
+			result.Message = "available for " + arch
+		default:
+			result.Status = StatusWarn
+			result.Message = fmt.Sprintf("not published for %s (available: %s)", arch, strings.Join(platforms, ", "))
+			result.Fix = "The image will run under emulation; set platform: linux/amd64 on the service or pick a multi-arch image"
+		}
+
+		results = append(results, result)
+	}
+	return results
+}
+
+// manifestArchitectures returns the architectures listed in a manifest list.
+// Returns an empty list for single-platform manifests.
+func manifestArchitectures(data []byte) ([]string, error) {
+	var manifest struct {
+		Manifests []struct {
+			Platform struct {
+				Architecture string `json:"architecture"`
+				OS           string `json:"os"`
+			} `json:"platform"`
+		} `json:"manifests"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+
+	var archs []string
+	for _, m := range manifest.Manifests {
+		arch := m.Platform.Architecture
+		if m.Platform.OS != "linux" || arch == "" || arch == "unknown" || containsArch(archs, arch) {
+			continue
+		}
+		archs = append(archs, arch)
+	}
+	return archs, nil
+}
+
+// containsArch checks if an architecture is in the list.
+func containsArch(archs []string, arch string) bool {
+	for _, a := range archs {
+		if a == arch {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+// Package doctor checks the local environment before a dev stack is generated and started.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Status is the outcome of a single check.
+type Status string
+
+const (
+	// StatusOK means the check passed
+	StatusOK Status = "ok"
+
+	// StatusWarn means the stack will likely work but something should be fixed
+	StatusWarn Status = "warn"
+
+	// StatusFail means the stack will not work until the problem is fixed
+	StatusFail Status = "fail"
+)
+
+// Result is the outcome of one diagnostic check.
+type Result struct {
+	// Name is the check name (e.g., "Docker", "Port 5432")
+	Name string
+
+	// Status is the check outcome
+	Status Status
+
+	// Message describes what was found
+	Message string
+
+	// Fix is an actionable suggestion when Status is not OK
+	Fix string
+}
+
+// Plan describes the stack the checks are run against.
+type Plan struct {
+	// ProjectPath is the project directory (used for the disk space check)
+	ProjectPath string
+
+	// Ports are the host ports the stack publishes or forwards
+	Ports []int
+
+	// Images are the images the stack pulls
+	Images []string
+
+	// CheckImages enables registry lookups for image platform availability
+	CheckImages bool
+}
+
+// runCommand runs a command and returns its trimmed combined output.
+// It is a variable so tests can stub out the docker and podman CLIs.
+var runCommand = func(timeout time.Duration, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	return strings.TrimSpace(string(out)), err
+}
+
+// Run executes every check for the plan, in display order.
+func Run(plan Plan) []Result {
+	var results []Result
+
+	docker := CheckDocker()
+	results = append(results, docker)
+	results = append(results, CheckCompose())
+	results = append(results, CheckPodman(docker.Status == StatusOK))
+	results = append(results, CheckDiskSpace(plan.ProjectPath))
+	results = append(results, CheckInotify())
+	results = append(results, CheckPorts(plan.Ports)...)
+
+	if plan.CheckImages && docker.Status == StatusOK {
+		results = append(results, CheckImagePlatforms(plan.Images)...)
+	}
+
+	return results
+}
+
+// HasFailures returns true if any result failed.
+func HasFailures(results []Result) bool {
+	for _, r := range results {
+		if r.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// parseVersion extracts the major and minor numbers from a version string
+// such as "v2.24.6", "26.1.0", or "2.24.6-desktop.1".
+func parseVersion(version string) (int, int, bool) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	var major, minor int
+	if _, err := fmt.Sscanf(version, "%d.%d", &major, &minor); err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// versionAtLeast reports whether version is at least major.minor.
+func versionAtLeast(version string, major, minor int) bool {
+	gotMajor, gotMinor, ok := parseVersion(version)
+	if !ok {
+		return false
+	}
+	return gotMajor > major || (gotMajor == major && gotMinor >= minor)
+}
@@ -0,0 +1,261 @@
+package doctor
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubCommands replaces runCommand with canned outputs keyed by the full command line.
+func stubCommands(t *testing.T, outputs map[string]string) {
+	t.Helper()
+	original := runCommand
+	runCommand = func(timeout time.Duration, name string, args ...string) (string, error) {
+		key := strings.Join(append([]string{name}, args...), " ")
+		if out, ok := outputs[key]; ok {
+			return out, nil
+		}
+		return "", errors.New("command not found")
+	}
+	t.Cleanup(func() { runCommand = original })
+}
+
+// TestCheckDocker tests Docker daemon detection.
+func TestCheckDocker(t *testing.T) {
+	tests := []struct {
+		name       string
+		outputs    map[string]string
+		wantStatus Status
+		wantFix    string
+	}{
+		{
+			name:       "daemon running",
+			outputs:    map[string]string{"docker version --format {{.Server.Version}}": "26.1.0"},
+			wantStatus: StatusOK,
+		},
+		{
+			name:       "old daemon",
+			outputs:    map[string]string{"docker version --format {{.Server.Version}}": "19.03.12"},
+			wantStatus: StatusWarn,
+			wantFix:    "Upgrade Docker",
+		},
+		{
+			name:       "daemon stopped",
+			outputs:    map[string]string{"docker --version": "Docker version 26.1.0"},
+			wantStatus: StatusFail,
+			wantFix:    "Start Docker Desktop",
+		},
+		{
+			name:       "not installed",
+			outputs:    map[string]string{},
+			wantStatus: StatusFail,
+			wantFix:    "https://docs.docker.com/get-docker/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubCommands(t, tt.outputs)
+
+			result := CheckDocker()
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q (message: %s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if !strings.Contains(result.Fix, tt.wantFix) {
+				t.Errorf("Fix = %q, want it to contain %q", result.Fix, tt.wantFix)
+			}
+		})
+	}
+}
+
+// TestCheckCompose tests Compose v2 plugin detection.
+func TestCheckCompose(t *testing.T) {
+	tests := []struct {
+		name       string
+		outputs    map[string]string
+		wantStatus Status
+		wantMsg    string
+	}{
+		{
+			name:       "compose v2",
+			outputs:    map[string]string{"docker compose version --short": "2.24.6"},
+			wantStatus: StatusOK,
+			wantMsg:    "Compose 2.24.6",
+		},
+		{
+			name:       "old compose v2",
+			outputs:    map[string]string{"docker compose version --short": "v2.12.2"},
+			wantStatus: StatusWarn,
+			wantMsg:    "older than 2.17",
+		},
+		{
+			name:       "legacy v1 only",
+			outputs:    map[string]string{"docker-compose --version": "docker-compose version 1.29.2"},
+			wantStatus: StatusFail,
+			wantMsg:    "legacy docker-compose (v1)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubCommands(t, tt.outputs)
+
+			result := CheckCompose()
+			if result.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", result.Status, tt.wantStatus)
+			}
+			if !strings.Contains(result.Message, tt.wantMsg) {
+				t.Errorf("Message = %q, want it to contain %q", result.Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+// TestCheckPodman tests that Podman is suggested only when Docker is unavailable.
+func TestCheckPodman(t *testing.T) {
+	stubCommands(t, map[string]string{"podman --version": "podman version 5.0.2"})
+
+	if result := CheckPodman(true); result.Status != StatusOK || result.Fix != "" {
+		t.Errorf("CheckPodman(true) = %+v, want OK without a fix", result)
+	}
+
+	result := CheckPodman(false)
+	if result.Status != StatusWarn {
+		t.Errorf("CheckPodman(false).Status = %q, want %q", result.Status, StatusWarn)
+	}
+	if !strings.Contains(result.Fix, "podman.socket") {
+		t.Errorf("CheckPodman(false).Fix = %q, want podman socket instructions", result.Fix)
+	}
+}
+
+// TestInotifyResult tests parsing of the inotify watch limit.
+func TestInotifyResult(t *testing.T) {
+	tests := []struct {
+		value      string
+		wantStatus Status
+	}{
+		{"524288\n", StatusOK},
+		{"1048576", StatusOK},
+		{"8192\n", StatusWarn},
+		{"garbage", StatusWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.TrimSpace(tt.value), func(t *testing.T) {
+			result := inotifyResult(tt.value)
+			if result.Status != tt.wantStatus {
+				t.Errorf("inotifyResult(%q).Status = %q, want %q", tt.value, result.Status, tt.wantStatus)
+			}
+		})
+	}
+
+	if fix := inotifyResult("8192").Fix; !strings.Contains(fix, "fs.inotify.max_user_watches=524288") {
+		t.Errorf("Fix = %q, want sysctl instructions", fix)
+	}
+}
+
+// TestCheckPorts tests port conflict detection.
+func TestCheckPorts(t *testing.T) {
+	listener, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+	busy := listener.Addr().(*net.TCPAddr).Port
+
+	results := CheckPorts([]int{busy})
+	if len(results) != 1 {
+		t.Fatalf("CheckPorts() returned %d results, want 1", len(results))
+	}
+	if results[0].Status != StatusWarn {
+		t.Errorf("Status for busy port = %q, want %q", results[0].Status, StatusWarn)
+	}
+	if !strings.Contains(results[0].Fix, "lsof") {
+		t.Errorf("Fix = %q, want lsof hint", results[0].Fix)
+	}
+}
+
+// TestManifestArchitectures tests parsing of docker manifest inspect output.
+func TestManifestArchitectures(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+	}{
+		{
+			name: "multi-arch manifest list",
+			manifest: `{"manifests": [
+				{"platform": {"architecture": "amd64", "os": "linux"}},
+				{"platform": {"architecture": "arm64", "os": "linux", "variant": "v8"}},
+				{"platform": {"architecture": "unknown", "os": "unknown"}},
+				{"platform": {"architecture": "amd64", "os": "windows"}}
+			]}`,
+			want: []string{"amd64", "arm64"},
+		},
+		{
+			name:     "single-platform manifest",
+			manifest: `{"schemaVersion": 2, "config": {"digest": "sha256:abc"}}`,
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := manifestArchitectures([]byte(tt.manifest))
+			if err != nil {
+				t.Fatalf("manifestArchitectures() error = %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("manifestArchitectures() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCheckImagePlatforms tests that images missing the host architecture are flagged.
+func TestCheckImagePlatforms(t *testing.T) {
+	stubCommands(t, map[string]string{
+		"docker version --format {{.Server.Arch}}": "arm64",
+		"docker manifest inspect postgres:16":      `{"manifests": [{"platform": {"architecture": "amd64", "os": "linux"}}, {"platform": {"architecture": "arm64", "os": "linux"}}]}`,
+		"docker manifest inspect legacy/tool:1":    `{"manifests": [{"platform": {"architecture": "amd64", "os": "linux"}}]}`,
+	})
+
+	results := CheckImagePlatforms([]string{"postgres:16", "legacy/tool:1", "private/image:latest"})
+	want := []Status{StatusOK, StatusWarn, StatusWarn}
+	if len(results) != len(want) {
+		t.Fatalf("CheckImagePlatforms() returned %d results, want %d", len(results), len(want))
+	}
+	for i, r := range results {
+		if r.Status != want[i] {
+			t.Errorf("%s: Status = %q, want %q (message: %s)", r.Name, r.Status, want[i], r.Message)
+		}
+	}
+	if !strings.Contains(results[1].Fix, "platform: linux/amd64") {
+		t.Errorf("Fix = %q, want emulation hint", results[1].Fix)
+	}
+}
+
+// TestVersionAtLeast tests version comparison.
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version string
+		major   int
+		minor   int
+		want    bool
+	}{
+		{"2.24.6", 2, 17, true},
+		{"v2.17.0", 2, 17, true},
+		{"2.16.0", 2, 17, false},
+		{"2.29.1-desktop.1", 2, 17, true},
+		{"3.0.0", 2, 17, true},
+		{"", 2, 17, false},
+	}
+
+	for _, tt := range tests {
+		if got := versionAtLeast(tt.version, tt.major, tt.minor); got != tt.want {
+			t.Errorf("versionAtLeast(%q, %d, %d) = %v, want %v", tt.version, tt.major, tt.minor, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Admin UI images, started on demand in the tools profile.
+const (
+	adminerImage      = "adminer:4.8.1"
+	redisInsightImage = "redis/redisinsight:2.58"
+)
+
+// Host ports of the admin UIs, chosen to avoid the app ports and the other sidecars.
+const (
+	AdminerPort      = 8083
+	RedisInsightPort = 5540
+)
+
+// AdminToolsComposeConfig holds configuration for the database admin UIs,
+// which "docker compose up" leaves out until the tools profile is enabled.
+type AdminToolsComposeConfig struct {
+	// Enabled indicates whether any admin UI is generated
+	Enabled bool
+
+	// Postgres is the PostgreSQL service Adminer connects to, or empty without PostgreSQL
+	Postgres string
+
+	// AdminerURL opens Adminer with the development database's login prefilled
+	AdminerURL string
+
+	// Redis is the Redis service RedisInsight connects to, or empty without Redis
+	Redis string
+
+	AdminerImage      string
+	AdminerPort       int
+	RedisInsightImage string
+	RedisInsightPort  int
+}
+
+// adminToolsConfig returns the admin UIs matching the stack's databases: Adminer
+// for PostgreSQL and RedisInsight for Redis. Imported services are reached under
+// the generated service's hostname, so their UIs point there too.
+func adminToolsConfig(config *ComposeConfig) AdminToolsComposeConfig {
+	tools := AdminToolsComposeConfig{
+		AdminerImage:      adminerImage,
+		AdminerPort:       AdminerPort,
+		RedisInsightImage: redisInsightImage,
+		RedisInsightPort:  RedisInsightPort,
+	}
+	for _, service := range config.Services {
+		switch service.Name {
+		case "postgres":
+			tools.Postgres = service.ComposeName()
+			login := url.Values{"pgsql": {"postgres"}}
+			if service.Existing == "" {
+				login.Set("username", config.Postgres.User)
+				login.Set("db", config.Postgres.Database)
+			}
+			tools.AdminerURL = fmt.Sprintf("http://localhost:%d/?%s", AdminerPort, login.Encode())
+		case "redis":
+			tools.Redis = service.ComposeName()
+		}
+	}
+	tools.Enabled = tools.Postgres != "" || tools.Redis != ""
+	return tools
+}
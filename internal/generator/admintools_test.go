@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestComposeGenerator_AdminTools tests the database admin UIs in the tools profile.
+func TestComposeGenerator_AdminTools(t *testing.T) {
+	tests := []struct {
+		name      string
+		detection *models.Detection
+		wantParts []string
+		dontWant  []string
+	}{
+		{
+			name: "postgres and redis",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Services: []string{"postgres", "redis"},
+			},
+			wantParts: []string{
+				"  adminer:\n    image: adminer:4.8.1\n    profiles: [\"tools\"]\n",
+				"      - ADMINER_DEFAULT_SERVER=postgres\n",
+				`"8083:8080"`,
+				"# then sign in at http://localhost:8083/?db=shop_dev&pgsql=postgres&username=postgres\n",
+				"  redisinsight:\n    image: redis/redisinsight:2.58\n    profiles: [\"tools\"]\n",
+				"      - RI_REDIS_HOST=redis\n",
+				"      - RI_REDIS_ALIAS=shop\n",
+				`"5540:5540"`,
+			},
+		},
+		{
+			name: "redis only",
+			detection: &models.Detection{
+				Language: "go",
+				Version:  "1.23",
+				Services: []string{"redis"},
+			},
+			wantParts: []string{"  redisinsight:\n"},
+			dontWant:  []string{"adminer"},
+		},
+		{
+			name: "pgvector runs in postgres",
+			detection: &models.Detection{
+				Language: "python",
+				Version:  "3.12",
+				Capabilities: models.Capabilities{
+					VectorLibraries: []string{"pgvector"},
+				},
+			},
+			wantParts: []string{"  adminer:\n"},
+			dontWant:  []string{"redisinsight"},
+		},
+		{
+			name: "imported postgres",
+			detection: &models.Detection{
+				Language: "go",
+				Version:  "1.23",
+				Services: []string{"postgres"},
+				ExistingCompose: &models.ExistingCompose{
+					File: "docker-compose.yml",
+					Services: []models.ExistingService{{
+						Name:       "db",
+						Role:       "postgres",
+						Definition: map[string]interface{}{"image": "postgres:15"},
+					}},
+				},
+			},
+			wantParts: []string{
+				"# then sign in at http://localhost:8083/?pgsql=postgres\n",
+				"    depends_on:\n      - db\n",
+			},
+		},
+		{
+			name: "no databases",
+			detection: &models.Detection{
+				Language: "go",
+				Version:  "1.23",
+				Services: []string{"nats"},
+			},
+			dontWant: []string{"adminer", "redisinsight"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := NewComposeGenerator().GenerateContent(tt.detection, "shop")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+			compose := string(content)
+
+			for _, want := range tt.wantParts {
+				if !strings.Contains(compose, want) {
+					t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+				}
+			}
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(compose, dontWant) {
+					t.Errorf("docker-compose.yml should NOT contain %q", dontWant)
+				}
+			}
+
+			var parsed map[string]interface{}
+			if err := yaml.Unmarshal(content, &parsed); err != nil {
+				t.Errorf("Generated YAML is invalid: %v", err)
+			}
+		})
+	}
+}
@@ -2,58 +2,36 @@
 package generator
 
 import (
-	"bytes"
-	"embed"
 	"fmt"
-	"os"
 	"path/filepath"
-	"text/template"
 
 	"github.com/jpequegn/dockstart/internal/models"
 )
 
-//go:embed templates/backup/*.tmpl
-var backupTemplates embed.FS
-
 // BackupGenerator generates database backup scripts.
 type BackupGenerator struct {
-	templates *template.Template
+	output
 }
 
 // NewBackupGenerator creates a new backup script generator.
 func NewBackupGenerator() *BackupGenerator {
-	tmpl := template.Must(template.ParseFS(backupTemplates, "templates/backup/*.tmpl"))
-	return &BackupGenerator{templates: tmpl}
+	return &BackupGenerator{}
 }
 
 // GenerateBackupScript generates the backup script for the given database type.
 func (g *BackupGenerator) GenerateBackupScript(config *models.BackupConfig) ([]byte, error) {
-	templateName := fmt.Sprintf("%s-backup.sh.tmpl", config.DatabaseType)
-
-	var buf bytes.Buffer
-	if err := g.templates.ExecuteTemplate(&buf, templateName, config); err != nil {
-		return nil, fmt.Errorf("failed to execute backup template: %w", err)
-	}
-
-	return buf.Bytes(), nil
+	return renderTemplate(fmt.Sprintf("backup/%s-backup.sh.tmpl", config.DatabaseType), config)
 }
 
 // GenerateRestoreScript generates the restore script for the given database type.
 func (g *BackupGenerator) GenerateRestoreScript(config *models.BackupConfig) ([]byte, error) {
-	templateName := fmt.Sprintf("%s-restore.sh.tmpl", config.DatabaseType)
-
-	var buf bytes.Buffer
-	if err := g.templates.ExecuteTemplate(&buf, templateName, config); err != nil {
-		return nil, fmt.Errorf("failed to execute restore template: %w", err)
-	}
-
-	return buf.Bytes(), nil
+	return renderTemplate(fmt.Sprintf("backup/%s-restore.sh.tmpl", config.DatabaseType), config)
 }
 
 // Generate writes the backup and restore scripts to the target directory.
 func (g *BackupGenerator) Generate(config *models.BackupConfig, targetDir string) error {
 	scriptsDir := filepath.Join(targetDir, "scripts")
-	if err := os.MkdirAll(scriptsDir, 0755); err != nil {
+	if err := g.fs().MkdirAll(scriptsDir, 0755); err != nil {
 		return fmt.Errorf("failed to create scripts directory: %w", err)
 	}
 
@@ -64,7 +42,7 @@ func (g *BackupGenerator) Generate(config *models.BackupConfig, targetDir string
 	}
 
 	backupPath := filepath.Join(scriptsDir, fmt.Sprintf("backup-%s.sh", config.DatabaseType))
-	if err := os.WriteFile(backupPath, backupContent, 0755); err != nil {
+	if err := g.fs().WriteFile(backupPath, backupContent, 0755); err != nil {
 		return fmt.Errorf("failed to write backup script: %w", err)
 	}
 
@@ -75,7 +53,7 @@ func (g *BackupGenerator) Generate(config *models.BackupConfig, targetDir string
 	}
 
 	restorePath := filepath.Join(scriptsDir, fmt.Sprintf("restore-%s.sh", config.DatabaseType))
-	if err := os.WriteFile(restorePath, restoreContent, 0755); err != nil {
+	if err := g.fs().WriteFile(restorePath, restoreContent, 0755); err != nil {
 		return fmt.Errorf("failed to write restore script: %w", err)
 	}
 
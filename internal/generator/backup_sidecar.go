@@ -2,9 +2,7 @@
 package generator
 
 import (
-	"bytes"
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"github.com/jpequegn/dockstart/internal/models"
@@ -15,6 +13,10 @@ type BackupSidecarConfig struct {
 	// HasPostgres indicates if PostgreSQL backup is needed
 	HasPostgres bool
 
+	// PostgresDatabases are the additional PostgreSQL databases, each backed up
+	// to its own postgres-<database>-<timestamp>.sql.gz
+	PostgresDatabases []string
+
 	// HasMySQL indicates if MySQL backup is needed
 	HasMySQL bool
 
@@ -32,10 +34,42 @@ type BackupSidecarConfig struct {
 
 	// ProjectName is the name of the project
 	ProjectName string
+
+	// BaseImage is the Alpine image the sidecar is built from
+	BaseImage string
+
+	// PostgresClient is the Alpine package providing pg_dump (e.g., "postgresql16-client")
+	PostgresClient string
+
+	// User is the non-root user backups run as after the entrypoint hands it
+	// the backup directory. Empty to run as root
+	User string
+
+	// Timezone is the TZ docker-compose.yml sets, which the image needs
+	// tzdata for; backups are scheduled and named in it. Empty for UTC
+	Timezone string
+}
+
+// postgresBackupClient returns the Alpine base image and PostgreSQL client package
+// for a server version. pg_dump refuses servers newer than itself but dumps older
+// ones, so servers older than 15 use the oldest client Alpine 3.19 ships.
+// An unknown version gets the client for the default server (16).
+func postgresBackupClient(postgresVersion string) (string, string) {
+	major := majorVersion(postgresVersion)
+	switch {
+	case major >= 17:
+		return "alpine:3.21", "postgresql17-client"
+	case major == 16 || major == 0:
+		return "alpine:3.19", "postgresql16-client"
+	default:
+		return "alpine:3.19", "postgresql15-client"
+	}
 }
 
 // BackupSidecarGenerator generates backup sidecar container files.
-type BackupSidecarGenerator struct{}
+type BackupSidecarGenerator struct {
+	output
+}
 
 // NewBackupSidecarGenerator creates a new backup sidecar generator.
 func NewBackupSidecarGenerator() *BackupSidecarGenerator {
@@ -44,62 +78,45 @@ func NewBackupSidecarGenerator() *BackupSidecarGenerator {
 
 // GenerateDockerfile generates the Dockerfile.backup content.
 func (g *BackupSidecarGenerator) GenerateDockerfile(config *BackupSidecarConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("Dockerfile.backup.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load template: %w", err)
-	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
+	// Configs built without a PostgreSQL version get the default client
+	data := *config
+	if data.BaseImage == "" || data.PostgresClient == "" {
+		data.BaseImage, data.PostgresClient = postgresBackupClient("")
 	}
 
-	return buf.Bytes(), nil
+	return renderTemplate("Dockerfile.backup.tmpl", data)
 }
 
 // GenerateBackupScript generates the main backup.sh script.
 func (g *BackupSidecarGenerator) GenerateBackupScript(config *BackupSidecarConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("backup.sh.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load template: %w", err)
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
-	}
-
-	return buf.Bytes(), nil
+	return renderTemplate("backup.sh.tmpl", config)
 }
 
 // GenerateCrontab generates the crontab file.
 func (g *BackupSidecarGenerator) GenerateCrontab(config *BackupSidecarConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("crontab.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load template: %w", err)
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
-	}
-
-	return buf.Bytes(), nil
+	return renderTemplate("crontab.tmpl", config)
 }
 
 // GenerateEntrypoint generates the entrypoint.sh script.
 func (g *BackupSidecarGenerator) GenerateEntrypoint(config *BackupSidecarConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("entrypoint.backup.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load template: %w", err)
-	}
+	return renderTemplate("entrypoint.backup.tmpl", config)
+}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
+// Files returns the files Generate writes, relative to the project root.
+func (g *BackupSidecarGenerator) Files(detection *models.Detection) []string {
+	files := []string{
+		".devcontainer/Dockerfile.backup",
+		".devcontainer/scripts/backup.sh",
+		".devcontainer/crontab",
+		".devcontainer/entrypoint.sh",
+	}
+	for _, db := range []string{"postgres", "mysql", "redis"} {
+		if detection.HasService(db) {
+			files = append(files, ".devcontainer/scripts/backup-"+db+".sh", ".devcontainer/scripts/restore-"+db+".sh")
+		}
 	}
-
-	return buf.Bytes(), nil
+	return append(files, ".devcontainer/backups/.gitkeep")
 }
 
 // Generate writes all backup sidecar files to the target directory.
@@ -108,7 +125,7 @@ func (g *BackupSidecarGenerator) Generate(detection *models.Detection, projectPa
 	scriptsDir := filepath.Join(devcontainerDir, "scripts")
 
 	// Create directories
-	if err := os.MkdirAll(scriptsDir, 0755); err != nil {
+	if err := g.fs().MkdirAll(scriptsDir, 0755); err != nil {
 		return fmt.Errorf("failed to create scripts directory: %w", err)
 	}
 
@@ -121,6 +138,14 @@ func (g *BackupSidecarGenerator) Generate(detection *models.Detection, projectPa
 		Schedule:      "0 3 * * *",
 		RetentionDays: 7,
 		ProjectName:   projectName,
+		Timezone:      detection.Timezone,
+	}
+	if config.HasPostgres {
+		config.PostgresDatabases = detection.PostgresDatabases
+	}
+	config.BaseImage, config.PostgresClient = postgresBackupClient(detection.GetServiceVersion("postgres"))
+	if detection.NonRoot {
+		config.User = models.SidecarUser
 	}
 
 	// If no databases, skip backup sidecar generation
@@ -133,7 +158,7 @@ func (g *BackupSidecarGenerator) Generate(detection *models.Detection, projectPa
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(devcontainerDir, "Dockerfile.backup"), dockerfile, 0644); err != nil {
+	if err := g.fs().WriteFile(filepath.Join(devcontainerDir, "Dockerfile.backup"), dockerfile, 0644); err != nil {
 		return fmt.Errorf("failed to write Dockerfile.backup: %w", err)
 	}
 
@@ -142,7 +167,7 @@ func (g *BackupSidecarGenerator) Generate(detection *models.Detection, projectPa
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(scriptsDir, "backup.sh"), backupScript, 0755); err != nil {
+	if err := g.fs().WriteFile(filepath.Join(scriptsDir, "backup.sh"), backupScript, 0755); err != nil {
 		return fmt.Errorf("failed to write backup.sh: %w", err)
 	}
 
@@ -151,7 +176,7 @@ func (g *BackupSidecarGenerator) Generate(detection *models.Detection, projectPa
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(devcontainerDir, "crontab"), crontab, 0644); err != nil {
+	if err := g.fs().WriteFile(filepath.Join(devcontainerDir, "crontab"), crontab, 0644); err != nil {
 		return fmt.Errorf("failed to write crontab: %w", err)
 	}
 
@@ -160,18 +185,21 @@ func (g *BackupSidecarGenerator) Generate(detection *models.Detection, projectPa
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(devcontainerDir, "entrypoint.sh"), entrypoint, 0755); err != nil {
+	if err := g.fs().WriteFile(filepath.Join(devcontainerDir, "entrypoint.sh"), entrypoint, 0755); err != nil {
 		return fmt.Errorf("failed to write entrypoint.sh: %w", err)
 	}
 
-	// Generate database-specific backup/restore scripts using BackupGenerator
+	// Generate database-specific backup/restore scripts using BackupGenerator.
+	// The passwords come from DB_PASSWORD in the db-backup service, or from
+	// .devcontainer/.env's variables when the scripts run elsewhere.
 	backupGen := NewBackupGenerator()
+	backupGen.SetFS(g.fs())
 
 	if config.HasPostgres {
 		pgConfig := models.DefaultBackupConfig("postgres", "postgres")
 		pgConfig.DatabaseName = projectName + "_dev"
 		pgConfig.DatabaseUser = "postgres"
-		pgConfig.DatabasePassword = "postgres"
+		pgConfig.DatabasePassword = "$POSTGRES_PASSWORD"
 		if err := backupGen.Generate(pgConfig, devcontainerDir); err != nil {
 			return fmt.Errorf("failed to generate postgres backup scripts: %w", err)
 		}
@@ -181,7 +209,7 @@ func (g *BackupSidecarGenerator) Generate(detection *models.Detection, projectPa
 		mysqlConfig := models.DefaultBackupConfig("mysql", "mysql")
 		mysqlConfig.DatabaseName = projectName + "_dev"
 		mysqlConfig.DatabaseUser = "root"
-		mysqlConfig.DatabasePassword = "mysql"
+		mysqlConfig.DatabasePassword = "$MYSQL_ROOT_PASSWORD"
 		if err := backupGen.Generate(mysqlConfig, devcontainerDir); err != nil {
 			return fmt.Errorf("failed to generate mysql backup scripts: %w", err)
 		}
@@ -196,22 +224,21 @@ func (g *BackupSidecarGenerator) Generate(detection *models.Detection, projectPa
 
 	// Create backups directory
 	backupsDir := filepath.Join(devcontainerDir, "backups")
-	if err := os.MkdirAll(backupsDir, 0755); err != nil {
+	if err := g.fs().MkdirAll(backupsDir, 0755); err != nil {
 		return fmt.Errorf("failed to create backups directory: %w", err)
 	}
 
 	// Create .gitkeep in backups directory
 	gitkeep := filepath.Join(backupsDir, ".gitkeep")
-	if err := os.WriteFile(gitkeep, []byte{}, 0644); err != nil {
+	if err := g.fs().WriteFile(gitkeep, []byte{}, 0644); err != nil {
 		return fmt.Errorf("failed to write .gitkeep: %w", err)
 	}
 
 	return nil
 }
 
-// ShouldGenerate checks if backup sidecar should be generated based on detection.
+// ShouldGenerate checks if backup sidecar should be generated based on detection
+// and the sidecar policy.
 func (g *BackupSidecarGenerator) ShouldGenerate(detection *models.Detection) bool {
-	return detection.HasService("postgres") ||
-		detection.HasService("mysql") ||
-		detection.HasService("redis")
+	return detection.NeedsSidecar(models.SidecarBackup)
 }
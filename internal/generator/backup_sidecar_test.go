@@ -3,6 +3,8 @@ package generator
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
@@ -164,6 +166,37 @@ func TestBackupSidecarGenerator_GenerateBackupScript(t *testing.T) {
 	}
 }
 
+// TestPostgresBackupClient tests matching pg_dump to the PostgreSQL server version.
+func TestPostgresBackupClient(t *testing.T) {
+	tests := []struct {
+		version    string
+		wantBase   string
+		wantClient string
+	}{
+		{"", "alpine:3.19", "postgresql16-client"},
+		{"16", "alpine:3.19", "postgresql16-client"},
+		{"15.4", "alpine:3.19", "postgresql15-client"},
+		{"12", "alpine:3.19", "postgresql15-client"},
+		{"17", "alpine:3.21", "postgresql17-client"},
+	}
+
+	for _, tt := range tests {
+		base, client := postgresBackupClient(tt.version)
+		if base != tt.wantBase || client != tt.wantClient {
+			t.Errorf("postgresBackupClient(%q) = %q, %q; want %q, %q", tt.version, base, client, tt.wantBase, tt.wantClient)
+		}
+	}
+
+	config := &BackupSidecarConfig{HasPostgres: true, BaseImage: "alpine:3.21", PostgresClient: "postgresql17-client"}
+	content, err := NewBackupSidecarGenerator().GenerateDockerfile(config)
+	if err != nil {
+		t.Fatalf("GenerateDockerfile failed: %v", err)
+	}
+	if !strings.Contains(string(content), "FROM alpine:3.21") || !strings.Contains(string(content), "apk add --no-cache postgresql17-client") {
+		t.Errorf("Dockerfile should use the configured base image and client:\n%s", content)
+	}
+}
+
 func TestBackupSidecarGenerator_GenerateCrontab(t *testing.T) {
 	g := NewBackupSidecarGenerator()
 
@@ -225,6 +258,22 @@ func TestBackupSidecarGenerator_GenerateEntrypoint(t *testing.T) {
 				"redis-cli",
 			},
 		},
+		{
+			name: "non-root user",
+			config: &BackupSidecarConfig{
+				HasRedis: true,
+				User:     "dockstart",
+			},
+			wantParts: []string{
+				`chown -R "$(id -u dockstart):$(id -g dockstart)" "${BACKUP_DIR:-/backup}"`,
+				"stat -c %g /var/run/docker.sock",
+				`RUN_AS="su-exec dockstart"`,
+				`exec $RUN_AS "$@"`,
+			},
+			dontWant: []string{
+				"exec \"$@\"",
+			},
+		},
 	}
 
 	g := NewBackupSidecarGenerator()
@@ -446,3 +495,20 @@ func TestComposeConfig_BackupSidecar(t *testing.T) {
 		})
 	}
 }
+
+// TestBackupSidecarGenerator_Files tests that Files lists exactly the files Generate writes.
+func TestBackupSidecarGenerator_Files(t *testing.T) {
+	tmpDir := t.TempDir()
+	detection := &models.Detection{Language: "node", Services: []string{"postgres", "redis"}}
+
+	g := NewBackupSidecarGenerator()
+	if err := g.Generate(detection, tmpDir, "shop"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	files := g.Files(detection)
+	sort.Strings(files)
+	if written := writtenFiles(t, tmpDir); !reflect.DeepEqual(files, written) {
+		t.Errorf("Files() = %v, want the written files %v", files, written)
+	}
+}
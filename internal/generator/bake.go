@@ -0,0 +1,245 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// BakeFile is the generated buildx bake file, relative to the project root.
+const BakeFile = ".devcontainer/docker-bake.hcl"
+
+// bakeVersionArgs are the build args that select the language version of the
+// generated Dockerfile's base image, by language.
+var bakeVersionArgs = map[string]string{
+	"node":   "NODE_VERSION",
+	"go":     "GO_VERSION",
+	"python": "PYTHON_VERSION",
+	"rust":   "RUST_VERSION",
+}
+
+// BakeTarget is an image built by docker-bake.hcl.
+type BakeTarget struct {
+	// Name is the compose service the image is built for (e.g., "app", "db-backup")
+	Name string
+
+	// Context is the build context, relative to the project root
+	Context string
+
+	// Dockerfile is the Dockerfile, relative to Context
+	Dockerfile string
+
+	// Stage is the Dockerfile stage to build (e.g., "worker"), or empty for the last
+	Stage string
+
+	// Tags are the image names docker compose uses for the services built
+	// from the image, so it finds them instead of building them again
+	Tags []string
+
+	// VersionArg passes the language version; set for the generated
+	// .devcontainer/Dockerfile
+	VersionArg bool
+
+	// UserArgs passes the host USER_UID and USER_GID
+	UserArgs bool
+
+	// ProxyArgs passes the proxy variables
+	ProxyArgs bool
+
+	// Sidecar is true for images built from a sidecar's Dockerfile in .devcontainer
+	Sidecar bool
+}
+
+// TagList returns the quoted tags, for the target's tags list.
+func (t BakeTarget) TagList() string {
+	quoted := make([]string, len(t.Tags))
+	for i, tag := range t.Tags {
+		quoted[i] = fmt.Sprintf("%q", tag)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// HasArgs returns true if the target passes any build args.
+func (t BakeTarget) HasArgs() bool {
+	return t.VersionArg || t.UserArgs || t.ProxyArgs
+}
+
+// BakeConfig holds the configuration for generating docker-bake.hcl.
+type BakeConfig struct {
+	// Name is the project name
+	Name string
+
+	// VersionArg and Version are the language version build arg and its
+	// default (e.g., "NODE_VERSION" and "20"), or empty when the app is built
+	// from the project's own Dockerfile
+	VersionArg string
+	Version    string
+
+	// UserArgs declares the USER_UID and USER_GID variables
+	UserArgs bool
+
+	// Proxy are the proxy variables and their defaults, which the host's
+	// environment overrides
+	Proxy []EnvVar
+
+	// Targets are the images to build, in docker-compose.yml's service order
+	Targets []BakeTarget
+}
+
+// Group returns the quoted names of the targets, all of them or only the
+// sidecars, for a group's targets list.
+func (c *BakeConfig) Group(sidecars bool) string {
+	var names []string
+	for _, target := range c.Targets {
+		if !sidecars || target.Sidecar {
+			names = append(names, fmt.Sprintf("%q", target.Name))
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// HasSidecars returns true if some, but not all, targets are sidecars, which
+// makes a group of their own useful.
+func (c *BakeConfig) HasSidecars() bool {
+	var sidecars int
+	for _, target := range c.Targets {
+		if target.Sidecar {
+			sidecars++
+		}
+	}
+	return sidecars > 0 && sidecars < len(c.Targets)
+}
+
+// BakeGenerator generates .devcontainer/docker-bake.hcl, which builds every
+// image of the compose stack in one docker buildx bake call.
+type BakeGenerator struct {
+	output
+}
+
+// NewBakeGenerator creates a new bake file generator.
+func NewBakeGenerator() *BakeGenerator {
+	return &BakeGenerator{}
+}
+
+// ShouldGenerate returns true if the compose stack builds more than one image.
+func (g *BakeGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.BuildsMultipleImages()
+}
+
+// Generate creates .devcontainer/docker-bake.hcl. composeProject is the
+// compose project name the devcontainer runs under (e.g., "myapp_devcontainer"),
+// which the image tags are derived from.
+func (g *BakeGenerator) Generate(detection *models.Detection, projectPath, projectName, composeProject string) error {
+	content, err := g.GenerateContent(detection, projectName, composeProject)
+	if err != nil {
+		return err
+	}
+
+	devcontainerDir := filepath.Join(projectPath, ".devcontainer")
+	if err := g.fs().MkdirAll(devcontainerDir, 0755); err != nil {
+		return fmt.Errorf("failed to create .devcontainer directory: %w", err)
+	}
+	if err := g.fs().WriteFile(filepath.Join(projectPath, filepath.FromSlash(BakeFile)), content, 0644); err != nil {
+		return fmt.Errorf("failed to write docker-bake.hcl: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateContent returns the docker-bake.hcl content without writing to disk.
+func (g *BakeGenerator) GenerateContent(detection *models.Detection, projectName, composeProject string) ([]byte, error) {
+	config, err := g.buildConfig(detection, projectName, composeProject)
+	if err != nil {
+		return nil, err
+	}
+	return renderTemplate("docker-bake.hcl.tmpl", config)
+}
+
+// composeBuild is the build section of a compose service.
+type composeBuild struct {
+	Context    string    `yaml:"context"`
+	Dockerfile string    `yaml:"dockerfile"`
+	Target     string    `yaml:"target"`
+	Args       yaml.Node `yaml:"args"`
+}
+
+// buildConfig creates a BakeConfig with a target for each image the
+// generated docker-compose.yml builds. Services built from the same
+// Dockerfile stage (e.g., the app and a worker without a stage of its own)
+// share one target, tagged with each service's image name.
+func (g *BakeGenerator) buildConfig(detection *models.Detection, projectName, composeProject string) (*BakeConfig, error) {
+	services, err := NewComposeGenerator().services(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &BakeConfig{Name: projectName}
+	if arg, ok := bakeVersionArgs[detection.Language]; ok && !detection.ReusesDockerfile() {
+		config.VersionArg = arg
+		config.Version = detection.Version
+	}
+
+	// The compose build args take the proxy from the host's environment, as bake variables do
+	if detection.Proxy.Enabled() {
+		names := make([]string, 0, len(services))
+		for _, service := range services {
+			names = append(names, service.Name)
+		}
+		for _, v := range proxyEnv(detection.Proxy, noProxy(detection.Proxy, names), func(_, value string) string { return value }) {
+			if v.Name == strings.ToUpper(v.Name) {
+				config.Proxy = append(config.Proxy, v)
+			}
+		}
+	}
+
+	built := make(map[string]int)
+	for _, service := range services {
+		var build composeBuild
+		switch service.Build.Kind {
+		case yaml.ScalarNode:
+			build.Context = service.Build.Value
+		case yaml.MappingNode:
+			if err := service.Build.Decode(&build); err != nil {
+				return nil, fmt.Errorf("failed to parse build of service %s: %w", service.Name, err)
+			}
+		default:
+			continue
+		}
+		if build.Dockerfile == "" {
+			build.Dockerfile = "Dockerfile"
+		}
+
+		// Compose resolves the context against .devcontainer, bake against
+		// the directory it runs in: the project root
+		context := path.Join(".devcontainer", build.Context)
+		tag := composeProject + "-" + service.Name
+		key := path.Join(context, build.Dockerfile) + "@" + build.Target
+		if i, ok := built[key]; ok {
+			config.Targets[i].Tags = append(config.Targets[i].Tags, tag)
+			continue
+		}
+		built[key] = len(config.Targets)
+
+		userArgs := mappingValue(&build.Args, "USER_UID") != nil
+		config.UserArgs = config.UserArgs || userArgs
+		proxyArgs := hasEnv(&build.Args, "NO_PROXY")
+		config.Targets = append(config.Targets, BakeTarget{
+			Name:       service.Name,
+			Context:    context,
+			Dockerfile: build.Dockerfile,
+			Stage:      build.Target,
+			Tags:       []string{tag},
+			VersionArg: config.VersionArg != "" && context == "." && build.Dockerfile == ".devcontainer/Dockerfile",
+			UserArgs:   userArgs,
+			ProxyArgs:  proxyArgs,
+			Sidecar:    context == ".devcontainer",
+		})
+	}
+
+	return config, nil
+}
@@ -0,0 +1,140 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestBakeGenerator_ShouldGenerate tests that the bake file is only generated
+// when the stack builds more than one image.
+func TestBakeGenerator_ShouldGenerate(t *testing.T) {
+	tests := []struct {
+		name      string
+		detection *models.Detection
+		want      bool
+	}{
+		{
+			name:      "no compose",
+			detection: &models.Detection{Language: "go", Version: "1.23"},
+		},
+		{
+			name:      "app only",
+			detection: &models.Detection{Language: "go", Version: "1.23", Sidecars: models.SidecarPolicy{Minimal: true}, Services: []string{"postgres"}},
+		},
+		{
+			name:      "worker sharing the app image",
+			detection: &models.Detection{Language: "go", Version: "1.23", Capabilities: models.Capabilities{QueueLibraries: []string{"asynq"}}, Sidecars: models.SidecarPolicy{Minimal: true}},
+		},
+		{
+			name: "worker stage",
+			detection: &models.Detection{
+				Language:     "go",
+				Version:      "1.23",
+				Capabilities: models.Capabilities{QueueLibraries: []string{"asynq"}},
+				Worker:       models.WorkerOptions{Target: true},
+				Sidecars:     models.SidecarPolicy{Minimal: true},
+			},
+			want: true,
+		},
+		{
+			name:      "backup sidecar",
+			detection: &models.Detection{Language: "go", Version: "1.23", Services: []string{"postgres"}},
+			want:      true,
+		},
+	}
+
+	gen := NewBakeGenerator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gen.ShouldGenerate(tt.detection); got != tt.want {
+				t.Errorf("ShouldGenerate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestBakeGenerator_GenerateContent tests the targets, shared args, and
+// groups of the bake file.
+func TestBakeGenerator_GenerateContent(t *testing.T) {
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Services: []string{"postgres"},
+		Capabilities: models.Capabilities{
+			QueueLibraries:      []string{"bullmq"},
+			FileUploadLibraries: []string{"multer"},
+		},
+		Worker:  models.WorkerOptions{Target: true},
+		NonRoot: true,
+	}
+
+	content, err := NewBakeGenerator().GenerateContent(detection, "shop", "shop_devcontainer")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	output := string(content)
+
+	wantParts := []string{
+		"variable \"USER_UID\" {\n  default = \"1000\"\n}\n",
+		"variable \"NODE_VERSION\" {\n  default = \"20\"\n}\n",
+		"variable \"CACHE_DIR\" {\n  default = \"\"\n}\n",
+		`group "default" {` + "\n" + `  targets = ["app", "worker", "file-processor", "db-backup"]`,
+		`group "sidecars" {` + "\n" + `  targets = ["file-processor", "db-backup"]`,
+		"target \"worker\" {\n  context = \".\"\n  dockerfile = \".devcontainer/Dockerfile\"\n  target = \"worker\"\n" +
+			"  tags = [\"shop_devcontainer-worker\"]\n" +
+			"  args = {\n    NODE_VERSION = NODE_VERSION\n    USER_UID = USER_UID\n    USER_GID = USER_GID\n  }\n",
+		"target \"db-backup\" {\n  context = \".devcontainer\"\n  dockerfile = \"Dockerfile.backup\"\n  tags = [\"shop_devcontainer-db-backup\"]\n" +
+			"  args = {\n    USER_UID = USER_UID\n    USER_GID = USER_GID\n  }\n",
+		`cache-from = CACHE_DIR == "" ? [] : ["type=local,src=${CACHE_DIR}/app"]`,
+		`cache-to = CACHE_DIR == "" ? [] : ["type=local,dest=${CACHE_DIR}/app,mode=max"]`,
+	}
+	for _, part := range wantParts {
+		if !strings.Contains(output, part) {
+			t.Errorf("bake file should contain %q:\n%s", part, output)
+		}
+	}
+
+	// The generated Dockerfile takes the version the bake file passes
+	dockerfile, err := NewDockerfileGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("Dockerfile GenerateContent() error = %v", err)
+	}
+	if !strings.Contains(string(dockerfile), "ARG NODE_VERSION=20\nFROM node:${NODE_VERSION} AS app\n") {
+		t.Errorf("Dockerfile should build FROM the NODE_VERSION arg:\n%s", dockerfile)
+	}
+}
+
+// TestBakeGenerator_ExistingDockerfile tests that a project's own Dockerfile
+// is built without the language version arg.
+func TestBakeGenerator_ExistingDockerfile(t *testing.T) {
+	detection := &models.Detection{
+		Language:           "rust",
+		Version:            "1.75",
+		Capabilities:       models.Capabilities{QueueLibraries: []string{"apalis"}},
+		WorkerCommand:      "cargo run --bin worker",
+		ExistingDockerfile: &models.ExistingDockerfile{File: "Dockerfile", Target: "dev", WorkerTarget: "worker"},
+		Sidecars:           models.SidecarPolicy{Minimal: true},
+	}
+
+	content, err := NewBakeGenerator().GenerateContent(detection, "jobs", "jobs_devcontainer")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	output := string(content)
+
+	for _, part := range []string{
+		"target \"app\" {\n  context = \".\"\n  dockerfile = \"Dockerfile\"\n  target = \"dev\"\n  tags = [\"jobs_devcontainer-app\"]\n  cache-from",
+		"target \"worker\" {\n  context = \".\"\n  dockerfile = \"Dockerfile\"\n  target = \"worker\"\n",
+	} {
+		if !strings.Contains(output, part) {
+			t.Errorf("bake file should contain %q:\n%s", part, output)
+		}
+	}
+	for _, part := range []string{"RUST_VERSION", `group "sidecars"`, "USER_UID"} {
+		if strings.Contains(output, part) {
+			t.Errorf("bake file should not contain %q:\n%s", part, output)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// clickHouseMetricsPort is the port of ClickHouse's built-in Prometheus endpoint.
+const clickHouseMetricsPort = 9363
+
+// ClickHouseComposeConfig holds configuration for the ClickHouse service.
+type ClickHouseComposeConfig struct {
+	// User is the ClickHouse user the app connects as
+	User string
+
+	// Password is the user's password
+	Password string
+
+	// Database is the database created on first start
+	Database string
+
+	// NativePort is the host port of the native protocol (9000, or 19000 next to MinIO)
+	NativePort int
+
+	// Metrics exposes the Prometheus endpoint and mounts its configuration
+	Metrics bool
+
+	// MetricsPort is the Prometheus endpoint port in the container
+	MetricsPort int
+}
+
+// clickHouseNativePort returns the host port of ClickHouse's native protocol.
+// MinIO publishes its S3 API on 9000, so ClickHouse moves aside when both run.
+func clickHouseNativePort(detection *models.Detection) int {
+	if detection.NeedsMinIO() {
+		return 19000
+	}
+	return 9000
+}
+
+// clickHouseDatabase returns the development database name for a project.
+// The image creates it with an unquoted CREATE DATABASE, so anything but
+// letters, digits, and underscores becomes an underscore.
+func clickHouseDatabase(projectName string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, projectName) + "_dev"
+}
+
+// ClickHouseConfig holds the configuration for generating the ClickHouse settings files.
+type ClickHouseConfig struct {
+	// ProjectName is the name of the project
+	ProjectName string
+
+	// MaxExecutionTime is the query timeout in seconds
+	MaxExecutionTime int
+
+	// MaxMemoryUsage is the memory limit of a single query in bytes
+	MaxMemoryUsage int64
+
+	// MetricsPort is the Prometheus endpoint port
+	MetricsPort int
+}
+
+// ClickHouseGenerator generates .devcontainer/clickhouse/users.xml with
+// development query defaults and, with the metrics stack, prometheus.xml.
+type ClickHouseGenerator struct {
+	output
+}
+
+// NewClickHouseGenerator creates a new ClickHouse settings generator.
+func NewClickHouseGenerator() *ClickHouseGenerator {
+	return &ClickHouseGenerator{}
+}
+
+// Generate creates the ClickHouse settings files in .devcontainer/clickhouse/.
+func (g *ClickHouseGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	config := g.buildConfig(projectName)
+
+	clickhouseDir := filepath.Join(projectPath, ".devcontainer", "clickhouse")
+	if err := g.fs().MkdirAll(clickhouseDir, 0755); err != nil {
+		return fmt.Errorf("failed to create clickhouse directory: %w", err)
+	}
+
+	users, err := g.GenerateUsers(config)
+	if err != nil {
+		return err
+	}
+	if err := g.fs().WriteFile(filepath.Join(clickhouseDir, "users.xml"), users, 0644); err != nil {
+		return fmt.Errorf("failed to write users.xml: %w", err)
+	}
+
+	if detection.NeedsMetrics() {
+		prometheus, err := g.GeneratePrometheus(config)
+		if err != nil {
+			return err
+		}
+		if err := g.fs().WriteFile(filepath.Join(clickhouseDir, "prometheus.xml"), prometheus, 0644); err != nil {
+			return fmt.Errorf("failed to write prometheus.xml: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GenerateUsers generates the users.xml content with the default profile settings.
+func (g *ClickHouseGenerator) GenerateUsers(config *ClickHouseConfig) ([]byte, error) {
+	return g.render("clickhouse/users.xml.tmpl", config)
+}
+
+// GeneratePrometheus generates the prometheus.xml content enabling the metrics endpoint.
+func (g *ClickHouseGenerator) GeneratePrometheus(config *ClickHouseConfig) ([]byte, error) {
+	return g.render("clickhouse/prometheus.xml.tmpl", config)
+}
+
+// render executes a ClickHouse template.
+func (g *ClickHouseGenerator) render(name string, config *ClickHouseConfig) ([]byte, error) {
+	return renderTemplate(name, config)
+}
+
+// buildConfig creates a ClickHouseConfig with development defaults.
+func (g *ClickHouseGenerator) buildConfig(projectName string) *ClickHouseConfig {
+	return &ClickHouseConfig{
+		ProjectName:      projectName,
+		MaxExecutionTime: 300,
+		MaxMemoryUsage:   4 << 30, // 4GB
+		MetricsPort:      clickHouseMetricsPort,
+	}
+}
+
+// ShouldGenerate returns true if a ClickHouse service is generated.
+func (g *ClickHouseGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.HasService("clickhouse") && detection.ExistingServiceFor("clickhouse") == ""
+}
@@ -0,0 +1,146 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestComposeGenerator_ClickHouse tests the ClickHouse service in docker-compose.yml.
+func TestComposeGenerator_ClickHouse(t *testing.T) {
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Services: []string{"clickhouse"},
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "event-log")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+
+	var parsed map[string]interface{}
+	if err := yaml.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, compose)
+	}
+
+	for _, want := range []string{
+		"  clickhouse:\n    image: clickhouse/clickhouse-server:24.8\n",
+		"      - clickhouse-data:/var/lib/clickhouse\n",
+		"      - ./clickhouse/users.xml:/etc/clickhouse-server/users.d/dockstart.xml:ro\n",
+		"CLICKHOUSE_DB: event_log_dev",
+		`"8123:8123"`,
+		`"9000:9000"`,
+		"soft: 262144",
+		"      - CLICKHOUSE_URL=http://clickhouse:8123\n",
+		"      - CLICKHOUSE_USER=default\n",
+		"      - CLICKHOUSE_PASSWORD=${CLICKHOUSE_PASSWORD}\n",
+		"      - CLICKHOUSE_DB=event_log_dev\n",
+		"\n  clickhouse-data:\n",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+		}
+	}
+	if strings.Contains(compose, "prometheus.xml") {
+		t.Errorf("docker-compose.yml should not mount prometheus.xml without metrics, got:\n%s", compose)
+	}
+}
+
+// TestComposeGenerator_ClickHouseWithMinIOAndMetrics tests the native port
+// moving aside for MinIO and the Prometheus endpoint joining the metrics stack.
+func TestComposeGenerator_ClickHouseWithMinIOAndMetrics(t *testing.T) {
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Services: []string{"clickhouse"},
+		Capabilities: models.Capabilities{
+			MetricsLibraries:    []string{"prometheus-client"},
+			FileUploadLibraries: []string{"boto3"},
+		},
+		FileProcessor: models.FileProcessorOptions{Storage: "s3"},
+	}
+	if !detection.NeedsMinIO() {
+		t.Fatal("test detection should need MinIO")
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "events")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+	for _, want := range []string{
+		`"19000:9000"`,
+		"./clickhouse/prometheus.xml:/etc/clickhouse-server/config.d/prometheus.xml:ro",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+		}
+	}
+
+	metricsGen := NewMetricsSidecarGenerator()
+	prometheus, err := metricsGen.GeneratePrometheusConfig(metricsGen.buildConfig(detection, "events"))
+	if err != nil {
+		t.Fatalf("GeneratePrometheusConfig() error = %v", err)
+	}
+	if !strings.Contains(string(prometheus), "targets: ['clickhouse:9363']") {
+		t.Errorf("prometheus.yml should scrape ClickHouse, got:\n%s", prometheus)
+	}
+}
+
+// TestClickHouseGenerator tests the ClickHouse settings files.
+func TestClickHouseGenerator(t *testing.T) {
+	gen := NewClickHouseGenerator()
+	if gen.ShouldGenerate(&models.Detection{Language: "go", Services: []string{"postgres"}}) {
+		t.Error("ShouldGenerate() = true without ClickHouse, want false")
+	}
+	imported := &models.Detection{
+		Language: "go",
+		Services: []string{"clickhouse"},
+		ExistingCompose: &models.ExistingCompose{
+			Services: []models.ExistingService{{Name: "olap", Role: "clickhouse"}},
+		},
+	}
+	if gen.ShouldGenerate(imported) {
+		t.Error("ShouldGenerate() = true for an imported ClickHouse, want false")
+	}
+
+	tmpDir := t.TempDir()
+	detection := &models.Detection{
+		Language: "go",
+		Services: []string{"clickhouse"},
+		Capabilities: models.Capabilities{
+			MetricsLibraries: []string{"prometheus/client_golang"},
+		},
+	}
+	if err := gen.Generate(detection, tmpDir, "events"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	users, err := os.ReadFile(filepath.Join(tmpDir, ".devcontainer", "clickhouse", "users.xml"))
+	if err != nil {
+		t.Fatalf("users.xml not written: %v", err)
+	}
+	for _, want := range []string{
+		"<log_queries>1</log_queries>",
+		"<max_execution_time>300</max_execution_time>",
+		"<max_memory_usage>4294967296</max_memory_usage>",
+	} {
+		if !strings.Contains(string(users), want) {
+			t.Errorf("users.xml should contain %q, got:\n%s", want, users)
+		}
+	}
+
+	prometheus, err := os.ReadFile(filepath.Join(tmpDir, ".devcontainer", "clickhouse", "prometheus.xml"))
+	if err != nil {
+		t.Fatalf("prometheus.xml not written: %v", err)
+	}
+	if !strings.Contains(string(prometheus), "<port>9363</port>") {
+		t.Errorf("prometheus.xml should expose port 9363, got:\n%s", prometheus)
+	}
+}
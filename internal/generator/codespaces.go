@@ -0,0 +1,130 @@
+package generator
+
+import (
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// Dev container features added for GitHub Codespaces.
+const (
+	// githubCLIFeature installs gh, which manages the codespace's port
+	// visibility (gh codespace ports visibility 3000:public)
+	githubCLIFeature = "ghcr.io/devcontainers/features/github-cli:1"
+
+	// postgresClientFeature installs psql in place of the Adminer sidecar
+	postgresClientFeature = "ghcr.io/robbert229/devcontainer-features/postgresql-client:1"
+)
+
+// codespacesPorts labels forwarded ports in the Codespaces Ports view. Backing
+// services speak their own protocols rather than HTTP, so they are forwarded
+// silently instead of offering to open a browser tab.
+var codespacesPorts = map[int]PortAttributes{
+	5432:           {Label: "PostgreSQL", OnAutoForward: "silent"},
+	5433:           {Label: "PostgreSQL (tests)", OnAutoForward: "silent"},
+	6379:           {Label: "Redis", OnAutoForward: "silent"},
+	6380:           {Label: "Redis (tests)", OnAutoForward: "silent"},
+	8123:           {Label: "ClickHouse HTTP", OnAutoForward: "silent"},
+	11211:          {Label: "Memcached", OnAutoForward: "silent"},
+	4222:           {Label: "NATS", OnAutoForward: "silent"},
+	8222:           {Label: "NATS monitoring"},
+	8086:           {Label: "InfluxDB"},
+	7233:           {Label: "Temporal", OnAutoForward: "silent"},
+	TemporalUIPort: {Label: "Temporal UI"},
+	WireMockPort:   {Label: "WireMock"},
+	GlitchTipPort:  {Label: "GlitchTip"},
+	GatusPort:      {Label: "Status page"},
+	24224:          {Label: "Fluent Bit", OnAutoForward: "silent"},
+	9090:           {Label: "Prometheus"},
+	3001:           {Label: "Grafana"},
+	15672:          {Label: "RabbitMQ management"},
+	8180:           {Label: "Keycloak"},
+	4566:           {Label: "LocalStack", OnAutoForward: "silent"},
+	9001:           {Label: "MinIO console"},
+	6333:           {Label: "Qdrant"},
+	8001:           {Label: "Chroma", OnAutoForward: "silent"},
+	7700:           {Label: "Meilisearch"},
+	8108:           {Label: "Typesense", OnAutoForward: "silent"},
+	8025:           {Label: "Mailpit"},
+	11434:          {Label: "Ollama", OnAutoForward: "silent"},
+	16686:          {Label: "Jaeger UI"},
+}
+
+// codespacesResourceLimits replace the default limits in Codespaces, where the
+// smallest machine has 2 cores and 8 GB of memory for the whole stack.
+// Limits set in .dockstart.yml still win.
+var codespacesResourceLimits = map[string]models.ResourceLimits{
+	"postgres":      {Memory: "256M", CPUs: "0.5"},
+	"postgres-test": {Memory: "128M", CPUs: "0.5"},
+	"redis":         {Memory: "128M", CPUs: "0.25"},
+	"redis-test":    {Memory: "64M", CPUs: "0.25"},
+	"clickhouse":    {Memory: "512M", CPUs: "1"},
+	"influxdb":      {Memory: "256M", CPUs: "0.5"},
+	"nats":          {Memory: "128M", CPUs: "0.25"},
+	"temporal":      {Memory: "384M", CPUs: "0.5"},
+	"rabbitmq":      {Memory: "384M", CPUs: "0.5"},
+	"prometheus":    {Memory: "256M", CPUs: "0.25"},
+	"grafana":       {Memory: "128M", CPUs: "0.25"},
+	"jaeger":        {Memory: "256M", CPUs: "0.25"},
+	"keycloak":      {Memory: "768M", CPUs: "0.5"},
+	"selenium-hub":  {Memory: "128M", CPUs: "0.25"},
+	"chrome":        {Memory: "1G", CPUs: "0.5"},
+	"firefox":       {Memory: "1G", CPUs: "0.5"},
+	"qdrant":        {Memory: "256M", CPUs: "0.5"},
+	"chroma":        {Memory: "256M", CPUs: "0.5"},
+	"meilisearch":   {Memory: "256M", CPUs: "0.5"},
+	"typesense":     {Memory: "256M", CPUs: "0.5"},
+	"mailpit":       {Memory: "64M", CPUs: "0.25"},
+	"ollama":        {Memory: "2G", CPUs: "1"},
+	"wiremock":      {Memory: "128M", CPUs: "0.25"},
+	"localstack":    {Memory: "512M", CPUs: "0.5"},
+	"minio":         {Memory: "256M", CPUs: "0.25"},
+	"redisinsight":  {Memory: "128M", CPUs: "0.25"},
+	"prod-import":   {Memory: "256M", CPUs: "0.5"},
+	"db-backup":     {Memory: "128M", CPUs: "0.25"},
+}
+
+// applyCodespaces adapts a devcontainer.json configuration to GitHub Codespaces:
+// client features instead of the admin sidecar, labeled ports, and the setup
+// moved to onCreateCommand, which Codespaces prebuilds run ahead of time.
+func applyCodespaces(config *DevcontainerConfig, detection *models.Detection) {
+	config.Features = append(config.Features, githubCLIFeature)
+	if detection.HasService("postgres") && !detection.NeedsSidecar(models.SidecarAdmin) {
+		config.Features = append(config.Features, postgresClientFeature)
+	}
+
+	appPort := detection.GetAppPort()
+	for _, port := range config.ForwardPorts {
+		if hasPortAttributes(config.PortsAttributes, port) {
+			continue
+		}
+		attributes, ok := codespacesPorts[port]
+		switch {
+		case port == appPort:
+			attributes = PortAttributes{Label: "App"}
+		case port == clickHouseNativePort(detection) && detection.HasService("clickhouse"):
+			attributes = PortAttributes{Label: "ClickHouse native", OnAutoForward: "silent"}
+		case port == 9000 && detection.NeedsMinIO():
+			attributes = PortAttributes{Label: "MinIO API", OnAutoForward: "silent"}
+		case !ok:
+			continue
+		}
+		attributes.Port = port
+		config.PortsAttributes = append(config.PortsAttributes, attributes)
+	}
+
+	// The install and build are the slow part of creating the container; in
+	// onCreateCommand they are baked into prebuilds instead of run per codespace.
+	// A post_create set in .dockstart.yml stays where it was asked for
+	if detection.Lifecycle.PostCreate == "" {
+		config.OnCreateCommand, config.PostCreateCommand = config.PostCreateCommand, ""
+	}
+}
+
+// hasPortAttributes checks if a port already has attributes.
+func hasPortAttributes(attributes []PortAttributes, port int) bool {
+	for _, attr := range attributes {
+		if attr.Port == port {
+			return true
+		}
+	}
+	return false
+}
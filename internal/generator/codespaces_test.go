@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// codespacesDetection is a Python app with PostgreSQL, Redis, and logging, for Codespaces.
+func codespacesDetection() *models.Detection {
+	return &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Services: []string{"postgres", "redis"},
+		Capabilities: models.Capabilities{
+			LoggingLibraries: []string{"structlog"},
+		},
+		Target:   models.TargetCodespaces,
+		Sidecars: models.SidecarPolicy{Codespaces: true},
+	}
+}
+
+// TestDevcontainerGenerator_Codespaces tests the devcontainer.json generated for Codespaces.
+func TestDevcontainerGenerator_Codespaces(t *testing.T) {
+	content, err := NewDevcontainerGenerator().GenerateContent(codespacesDetection(), "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	var result struct {
+		Features          map[string]interface{}            `json:"features"`
+		PortsAttributes   map[string]map[string]interface{} `json:"portsAttributes"`
+		OnCreateCommand   string                            `json:"onCreateCommand"`
+		PostCreateCommand string                            `json:"postCreateCommand"`
+	}
+	if err := json.Unmarshal(content, &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, content)
+	}
+
+	for _, feature := range []string{githubCLIFeature, postgresClientFeature} {
+		if _, ok := result.Features[feature]; !ok {
+			t.Errorf("features = %v, should include %s", result.Features, feature)
+		}
+	}
+
+	if result.PortsAttributes["8000"]["label"] != "App" {
+		t.Errorf("port 8000 attributes = %v, want the App label", result.PortsAttributes["8000"])
+	}
+	if result.PortsAttributes["5432"]["onAutoForward"] != "silent" {
+		t.Errorf("port 5432 attributes = %v, want silent forwarding", result.PortsAttributes["5432"])
+	}
+	if _, ok := result.PortsAttributes["24224"]; ok {
+		t.Error("Fluent Bit should be left out in Codespaces")
+	}
+
+	if !strings.Contains(result.OnCreateCommand, "pip install") {
+		t.Errorf("onCreateCommand = %q, should install the dependencies for prebuilds", result.OnCreateCommand)
+	}
+	if result.PostCreateCommand != "" {
+		t.Errorf("postCreateCommand = %q, want the setup in onCreateCommand", result.PostCreateCommand)
+	}
+}
+
+// TestDevcontainerGenerator_CodespacesPostCreate tests that a configured post_create isn't moved.
+func TestDevcontainerGenerator_CodespacesPostCreate(t *testing.T) {
+	detection := codespacesDetection()
+	detection.Lifecycle.PostCreate = "make setup"
+
+	config := NewDevcontainerGenerator().buildConfig(detection, "shop")
+	if config.PostCreateCommand != "make setup" || config.OnCreateCommand != "" {
+		t.Errorf("onCreateCommand = %q, postCreateCommand = %q; want post_create kept", config.OnCreateCommand, config.PostCreateCommand)
+	}
+}
+
+// TestComposeGenerator_Codespaces tests the sidecars and limits of the Codespaces stack.
+func TestComposeGenerator_Codespaces(t *testing.T) {
+	detection := codespacesDetection()
+	services, err := NewComposeGenerator().Services(detection, "shop")
+	if err != nil {
+		t.Fatalf("Services() error = %v", err)
+	}
+	for _, sidecar := range []string{"fluent-bit", "db-backup", "adminer", "redisinsight"} {
+		if containsString(services, sidecar) {
+			t.Errorf("Services() = %v, should not include %s in Codespaces", services, sidecar)
+		}
+	}
+
+	limits := resourceLimits(detection)
+	if limits["postgres"].Memory != "256M" {
+		t.Errorf("postgres memory = %q, want the Codespaces limit 256M", limits["postgres"].Memory)
+	}
+
+	// .dockstart.yml limits still win, and --with brings sidecars back
+	detection.Resources = map[string]models.ResourceLimits{"postgres": {Memory: "1G"}}
+	detection.Sidecars.With = []string{models.SidecarBackup}
+	if limits := resourceLimits(detection); limits["postgres"].Memory != "1G" {
+		t.Errorf("postgres memory = %q, want the configured 1G", limits["postgres"].Memory)
+	}
+	services, err = NewComposeGenerator().Services(detection, "shop")
+	if err != nil {
+		t.Fatalf("Services() error = %v", err)
+	}
+	if !containsString(services, "db-backup") {
+		t.Errorf("Services() = %v, want db-backup with --with backup", services)
+	}
+}
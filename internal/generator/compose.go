@@ -1,18 +1,60 @@
 package generator
 
 import (
-	"bytes"
 	"fmt"
-	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
 )
 
 // ServiceConfig holds configuration for a single Docker Compose service.
 type ServiceConfig struct {
 	// Name is the service name (e.g., "postgres", "redis")
 	Name string
+
+	// Version is the image version to run (e.g., "16", "15.4")
+	Version string
+
+	// Existing is the name of an imported service that runs this one (e.g., "db"),
+	// in which case no service is generated for it
+	Existing string
+}
+
+// ComposeName returns the compose service to depend on: the imported one, if any.
+func (s ServiceConfig) ComposeName() string {
+	if s.Existing != "" {
+		return s.Existing
+	}
+	return s.Name
+}
+
+// Major returns the major version of the service (e.g., "15" for "15.4").
+func (s ServiceConfig) Major() string {
+	major, _, _ := strings.Cut(s.Version, ".")
+	return major
+}
+
+// majorVersion returns the major version number of a version string, or 0.
+func majorVersion(version string) int {
+	major, _, _ := strings.Cut(version, ".")
+	n, err := strconv.Atoi(major)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// postgresExporterImage returns a postgres-exporter release that supports the
+// PostgreSQL major version. PostgreSQL 17 moved checkpoint statistics out of
+// pg_stat_bgwriter, which exporters before v0.16 still query.
+func postgresExporterImage(postgresVersion string) string {
+	if majorVersion(postgresVersion) >= 17 {
+		return "quay.io/prometheuscommunity/postgres-exporter:v0.16.0"
+	}
+	return "quay.io/prometheuscommunity/postgres-exporter:v0.15.0"
 }
 
 // LogSidecarComposeConfig holds configuration for the log aggregator sidecar.
@@ -25,6 +67,24 @@ type LogSidecarComposeConfig struct {
 
 	// LoggingLibraries is the list of detected logging libraries
 	LoggingLibraries []string
+
+	// ParsersPath mounts fluent-bit-parsers.conf at this path when text logs
+	// are parsed, or is empty
+	ParsersPath string
+
+	// FileOutput writes the collected logs to the fluent-bit-logs volume,
+	// rotated by the log-rotate service
+	FileOutput bool
+}
+
+// LogRotationConfig holds the json-file logging driver limits of the services
+// that don't log through the log sidecar.
+type LogRotationConfig struct {
+	// MaxSize is the size a container log is rotated at (e.g., "10m")
+	MaxSize string
+
+	// MaxFiles is the number of log files kept per container
+	MaxFiles int
 }
 
 // WorkerSidecarConfig holds configuration for the background worker sidecar.
@@ -37,6 +97,95 @@ type WorkerSidecarConfig struct {
 
 	// QueueLibraries is the list of detected queue libraries
 	QueueLibraries []string
+
+	// Concurrency is the number of jobs each worker processes at once
+	Concurrency int
+
+	// Replicas is the number of worker containers (deploy.replicas), or 0 for one
+	Replicas int
+
+	// Memory and CPUs are per-worker resource limits (deploy.resources.limits), or empty
+	Memory string
+	CPUs   string
+
+	// StopGracePeriod is how long a worker gets to finish jobs on shutdown, or empty
+	StopGracePeriod string
+
+	// Target is the Dockerfile stage the worker is built from, or empty for the last stage
+	Target string
+
+	// Dedicated indicates the worker has its own Dockerfile stage, whose CMD
+	// starts it, rather than sharing the app's image with a different command
+	Dedicated bool
+
+	// DeadLetter enables the retry policy env vars and dead-letter queue scaffolding
+	DeadLetter bool
+
+	// MaxRetries is how many times a failed job is retried before it is dead-lettered
+	MaxRetries int
+
+	// Backoff is the delay before a failed job is retried (e.g., "5s")
+	Backoff string
+
+	// QueueName is the queue the worker consumes (e.g., "myapp")
+	QueueName string
+
+	// DeadLetterQueue is the queue failed jobs are moved to (e.g., "myapp-dlq")
+	DeadLetterQueue string
+
+	// DeadLetterCommand starts the worker-dlq consumer, or empty for the worker stage's CMD
+	DeadLetterCommand string
+
+	// RabbitMQ indicates the queue library uses RabbitMQ, whose dead-letter exchange
+	// is declared by rabbitmq/init-dlx.sh instead of running a worker-dlq consumer
+	RabbitMQ bool
+}
+
+// HasDeploy returns true if the worker needs a deploy section (replicas or limits).
+func (w WorkerSidecarConfig) HasDeploy() bool {
+	return w.Replicas > 0 || w.Memory != "" || w.CPUs != ""
+}
+
+// QueueDashboardComposeConfig holds configuration for the queue management UI sidecar.
+type QueueDashboardComposeConfig struct {
+	// Enabled indicates whether to include the queue dashboard
+	Enabled bool
+
+	// Dashboard is the UI to run ("bull-board", "flower", or "asynqmon"), also its service name
+	Dashboard string
+
+	// Port is the external port for the dashboard UI
+	Port int
+
+	// BullVersion selects the queue API Bull Board reads ("BULLMQ" or "BULL")
+	BullVersion string
+
+	// HasRedis indicates the queues are in the Redis service; otherwise Flower
+	// connects to the broker in the host's CELERY_BROKER_URL
+	HasRedis bool
+}
+
+// queueDashboardNames are the display names of each queue dashboard.
+var queueDashboardNames = map[string]string{
+	"bull-board": "Bull Board",
+	"flower":     "Flower",
+	"asynqmon":   "Asynqmon",
+}
+
+// SchedulerSidecarComposeConfig holds configuration for the cron scheduler sidecar.
+type SchedulerSidecarComposeConfig struct {
+	// Enabled indicates whether to include the scheduler sidecar
+	Enabled bool
+
+	// Command is the dedicated scheduler process command (e.g., "celery -A app beat").
+	// When empty, a supercronic sidecar built from Dockerfile.scheduler is used instead
+	Command string
+
+	// SchedulerLibraries is the list of detected scheduler libraries
+	SchedulerLibraries []string
+
+	// AppURL is the base URL supercronic jobs use to reach the application
+	AppURL string
 }
 
 // BackupSidecarComposeConfig holds configuration for the backup sidecar.
@@ -91,6 +240,63 @@ type FileProcessorSidecarComposeConfig struct {
 
 	// CPULimit is the CPU limit for the processor container (e.g., "0.5")
 	CPULimit string
+
+	// PollInterval is the polling interval in seconds
+	PollInterval int
+
+	// MaxFileSize is the largest file processed, in bytes
+	MaxFileSize int64
+
+	// ThumbnailSize and ExtraThumbnailSizes are the image thumbnail sizes
+	ThumbnailSize       string
+	ExtraThumbnailSizes []string
+
+	// AllowedMIMETypes restricts accepted files; empty accepts any type
+	AllowedMIMETypes []string
+
+	// ScanEnabled scans uploads with ClamAV and quarantines infected files
+	ScanEnabled bool
+
+	// SanitizeImages strips image metadata and converts unsafe image formats
+	SanitizeImages bool
+
+	// NotifyMethod, NotifyURL, and NotifyQueue configure notify.sh
+	NotifyMethod string
+	NotifyURL    string
+	NotifyQueue  string
+
+	// SharedVolume mounts the uploads volume in the app and processor
+	SharedVolume bool
+
+	// FilesPath is where the processor keeps the pipeline directories
+	// ("/uploads" on the shared volume, "/files" inside the container with S3)
+	FilesPath string
+
+	// S3 exchanges uploads through a bucket instead of the shared volume
+	S3 bool
+
+	// S3Endpoint, S3AccessKey, and S3SecretKey reach MinIO or LocalStack
+	S3Endpoint  string
+	S3AccessKey string
+	S3SecretKey string
+
+	// S3Bucket holds the pending/, processed/, and failed/ prefixes
+	S3Bucket string
+}
+
+// MinIOComposeConfig holds configuration for the MinIO S3-compatible object store.
+type MinIOComposeConfig struct {
+	// Enabled indicates whether to include the MinIO sidecar
+	Enabled bool
+
+	// Bucket is created by minio-init on startup
+	Bucket string
+
+	// APIPort is the S3 API port (default: 9000)
+	APIPort int
+
+	// ConsolePort is the web console port (default: 9001)
+	ConsolePort int
 }
 
 // MetricsSidecarComposeConfig holds configuration for the Prometheus + Grafana metrics stack.
@@ -119,6 +325,9 @@ type MetricsSidecarComposeConfig struct {
 	// HasPostgres indicates if Postgres exporter should be included
 	HasPostgres bool
 
+	// PostgresExporterImage is the exporter image compatible with the PostgreSQL version
+	PostgresExporterImage string
+
 	// HasRedis indicates if Redis exporter should be included
 	HasRedis bool
 
@@ -159,6 +368,180 @@ type TracingSidecarComposeConfig struct {
 	ServiceName string
 }
 
+// GRPCSidecarComposeConfig holds configuration for gRPC settings and the grpcui sidecar.
+type GRPCSidecarComposeConfig struct {
+	// Enabled indicates whether gRPC was detected
+	Enabled bool
+
+	// GRPCLibraries is the list of detected gRPC libraries
+	GRPCLibraries []string
+
+	// GRPCPort is the port where the app serves gRPC (default: 50051)
+	GRPCPort int
+
+	// UIEnabled indicates whether to include the grpcui sidecar
+	UIEnabled bool
+
+	// UIPort is the external port for grpcui (default: 8082)
+	UIPort int
+}
+
+// KeycloakSidecarComposeConfig holds configuration for the Keycloak auth provider sidecar.
+type KeycloakSidecarComposeConfig struct {
+	// Enabled indicates whether to include the Keycloak sidecar
+	Enabled bool
+
+	// AuthLibraries is the list of detected OIDC/OAuth libraries
+	AuthLibraries []string
+
+	// Port is the external port for Keycloak (default: 8180)
+	Port int
+
+	// Realm is the name of the imported realm
+	Realm string
+
+	// ClientID is the OIDC client the app authenticates as
+	ClientID string
+
+	// ClientSecret is the OIDC client secret (development only)
+	ClientSecret string
+}
+
+// SeleniumGridComposeConfig holds configuration for the Selenium Grid hub and browser nodes.
+type SeleniumGridComposeConfig struct {
+	// Enabled indicates whether to include the Selenium Grid
+	Enabled bool
+
+	// WebDriverLibraries is the list of detected WebDriver clients
+	WebDriverLibraries []string
+
+	// Version is the Selenium Grid image tag, shared by the hub and nodes
+	Version string
+
+	// Port is the hub's WebDriver port, published on the host
+	Port int
+
+	// Nodes are the browser nodes registered with the hub
+	Nodes []SeleniumNode
+}
+
+// SeleniumNode is a browser node of the Selenium Grid.
+type SeleniumNode struct {
+	// Browser is the browser and compose service name (e.g., "chrome")
+	Browser string
+
+	// Name is the browser's display name (e.g., "Chrome")
+	Name string
+
+	// VNCPort is the host port of the node's noVNC viewer
+	VNCPort int
+}
+
+// seleniumVersion is the Selenium Grid image tag.
+const seleniumVersion = "4.25"
+
+// seleniumNodes are the browser nodes of the generated Selenium Grid.
+var seleniumNodes = []SeleniumNode{
+	{Browser: "chrome", Name: "Chrome", VNCPort: 7900},
+	{Browser: "firefox", Name: "Firefox", VNCPort: 7901},
+}
+
+// StripeSidecarComposeConfig holds configuration for the stripe-cli webhook forwarding sidecar.
+type StripeSidecarComposeConfig struct {
+	// Enabled indicates whether to include the stripe-cli sidecar
+	Enabled bool
+
+	// PaymentLibraries is the list of detected Stripe SDKs
+	PaymentLibraries []string
+
+	// ForwardTo is the in-network URL webhooks are forwarded to (e.g., "app:3000/webhooks")
+	ForwardTo string
+
+	// SecretFile is the path where the webhook signing secret is written for the app
+	SecretFile string
+}
+
+// LocalStackSidecarComposeConfig holds configuration for the LocalStack AWS emulator.
+type LocalStackSidecarComposeConfig struct {
+	// Enabled indicates whether to include the LocalStack sidecar
+	Enabled bool
+
+	// Services is the comma-separated list of AWS services to start (e.g., "sqs,sns,dynamodb")
+	Services string
+
+	// Port is the LocalStack edge port (default: 4566)
+	Port int
+
+	// Region is the AWS region used by the app and init script (default: us-east-1)
+	Region string
+
+	// ResourcePrefix is the name used for created queues, tables, and buckets
+	ResourcePrefix string
+
+	// HasSQS indicates if an SQS queue URL should be injected
+	HasSQS bool
+
+	// HasSNS indicates if an SNS topic ARN should be injected
+	HasSNS bool
+
+	// HasDynamoDB indicates if a DynamoDB table name should be injected
+	HasDynamoDB bool
+
+	// HasS3 indicates if an S3 bucket name should be injected
+	HasS3 bool
+}
+
+// VectorStoreComposeConfig holds configuration for the local vector database.
+type VectorStoreComposeConfig struct {
+	// Enabled indicates whether a vector store is configured
+	Enabled bool
+
+	// Store is the vector store to run ("qdrant", "chroma", or "pgvector")
+	Store string
+
+	// VectorLibraries is the list of detected vector database clients
+	VectorLibraries []string
+}
+
+// OllamaSidecarComposeConfig holds configuration for the local Ollama LLM sidecar.
+type OllamaSidecarComposeConfig struct {
+	// Enabled indicates whether to include the Ollama sidecar
+	Enabled bool
+
+	// LLMLibraries is the list of detected LLM client libraries
+	LLMLibraries []string
+
+	// Model is the model pulled on startup and exposed to the app (default: llama3.2)
+	Model string
+
+	// Port is the external port for the Ollama API (default: 11434)
+	Port int
+}
+
+// WebServiceConfig holds configuration for the frontend dev server service.
+type WebServiceConfig struct {
+	// Enabled indicates whether to include the web service
+	Enabled bool
+
+	// Framework is the display name of the frontend framework (e.g., "Next.js", "Vite")
+	Framework string
+
+	// Image is the Node.js image used to run the dev server (e.g., "node:20")
+	Image string
+
+	// WorkingDir is the frontend directory inside the container (e.g., "/workspace/frontend")
+	WorkingDir string
+
+	// Port is the dev server port, forwarded 1:1 so the HMR websocket reaches it
+	Port int
+
+	// Command is the command that installs dependencies and starts the dev server
+	Command string
+
+	// Environment is the list of KEY=value entries for file watching and HMR
+	Environment []string
+}
+
 // ComposeConfig holds the configuration for generating docker-compose.yml.
 type ComposeConfig struct {
 	// Name is the project name (used for database names, etc.)
@@ -167,12 +550,60 @@ type ComposeConfig struct {
 	// Services is a list of additional services to include
 	Services []ServiceConfig
 
+	// Dockerfile is the app's Dockerfile, relative to the project root
+	// (".devcontainer/Dockerfile" unless the project's own is reused)
+	Dockerfile string
+
+	// DockerfileTarget is the build stage of Dockerfile to use, or empty for the last stage
+	DockerfileTarget string
+
+	// PrebuildImage is the prebuilt image the app's build reuses layers from,
+	// in prebuild mode
+	PrebuildImage string
+
+	// NonRoot passes the host UID/GID (USER_UID/USER_GID) to every generated
+	// Dockerfile as build args, so containers don't write root-owned files
+	NonRoot bool
+
+	// User is the non-root user services built from the app's Dockerfile run as.
+	// Empty when running as root or when the project's own Dockerfile is reused
+	User string
+
+	// Hardened locks down generated services: read-only root filesystem with
+	// tmpfs scratch paths, no-new-privileges, and cap_drop: ALL (see Hardening)
+	Hardened bool
+
+	// Windows mounts the workspace with long syntax and keeps DependencyDirs in
+	// named volumes instead of the bind mount (see WorkspaceVolumes)
+	Windows bool
+
+	// DependencyDirs are the workspace directories kept in named volumes in
+	// Windows mode (e.g., "node_modules")
+	DependencyDirs []string
+
+	// PersistentVolumes keep the app container's shell history and tool caches
+	// across rebuilds
+	PersistentVolumes []PersistentVolume
+
+	// RemoteWorkspace is the named volume mounted as the workspace instead of
+	// the bind mount, on a remote Docker engine (see SyncWorkspaceScript)
+	RemoteWorkspace string
+
 	// LogSidecar holds configuration for the log aggregator sidecar
 	LogSidecar LogSidecarComposeConfig
 
+	// LogRotation bounds the container logs of the other services
+	LogRotation LogRotationConfig
+
 	// WorkerSidecar holds configuration for the background worker sidecar
 	WorkerSidecar WorkerSidecarConfig
 
+	// QueueDashboard holds configuration for the queue management UI sidecar
+	QueueDashboard QueueDashboardComposeConfig
+
+	// SchedulerSidecar holds configuration for the cron scheduler sidecar
+	SchedulerSidecar SchedulerSidecarComposeConfig
+
 	// BackupSidecar holds configuration for the database backup sidecar
 	BackupSidecar BackupSidecarComposeConfig
 
@@ -184,10 +615,142 @@ type ComposeConfig struct {
 
 	// TracingSidecar holds configuration for the Jaeger distributed tracing stack
 	TracingSidecar TracingSidecarComposeConfig
+
+	// GRPCSidecar holds configuration for gRPC settings and the grpcui sidecar
+	GRPCSidecar GRPCSidecarComposeConfig
+
+	// KeycloakSidecar holds configuration for the Keycloak auth provider
+	KeycloakSidecar KeycloakSidecarComposeConfig
+
+	// StripeSidecar holds configuration for the stripe-cli webhook forwarder
+	StripeSidecar StripeSidecarComposeConfig
+
+	// SeleniumGrid holds configuration for the Selenium Grid hub and browser nodes
+	SeleniumGrid SeleniumGridComposeConfig
+
+	// LocalStackSidecar holds configuration for the LocalStack AWS emulator
+	LocalStackSidecar LocalStackSidecarComposeConfig
+
+	// MinIO holds configuration for the MinIO object store used for S3 uploads
+	MinIO MinIOComposeConfig
+
+	// VectorStore holds configuration for the local vector database
+	VectorStore VectorStoreComposeConfig
+
+	// OllamaSidecar holds configuration for the local Ollama LLM sidecar
+	OllamaSidecar OllamaSidecarComposeConfig
+
+	// WebService holds configuration for the frontend dev server
+	WebService WebServiceConfig
+
+	// Postgres holds the credentials services use to connect to PostgreSQL
+	Postgres PostgresConnection
+
+	// PostgresDatabases are the additional databases next to Postgres.Database
+	PostgresDatabases []PostgresDatabase
+
+	// ClickHouse holds configuration for the ClickHouse analytics database
+	ClickHouse ClickHouseComposeConfig
+
+	// Memcached holds configuration for the Memcached cache
+	Memcached MemcachedComposeConfig
+
+	// NATS holds configuration for the NATS server and its nats-box toolbox
+	NATS NATSComposeConfig
+
+	// Temporal holds configuration for the Temporal server and Web UI
+	Temporal TemporalComposeConfig
+
+	// ProdImport holds configuration for the anonymized production import in the tools profile
+	ProdImport ProdImportComposeConfig
+
+	// AdminTools holds configuration for the database admin UIs in the tools profile
+	AdminTools AdminToolsComposeConfig
+
+	// WireMock holds configuration for the WireMock API-mocking sidecar
+	WireMock WireMockComposeConfig
+
+	// GlitchTip holds configuration for the GlitchTip error-tracking sidecar
+	GlitchTip GlitchTipComposeConfig
+
+	// Search holds configuration for the Meilisearch or Typesense search engine
+	Search SearchComposeConfig
+
+	// Mailpit holds configuration for the Mailpit mail catcher
+	Mailpit MailpitComposeConfig
+
+	// Gatus holds configuration for the Gatus status page sidecar
+	Gatus GatusComposeConfig
+
+	// Toxiproxy holds configuration for the Toxiproxy chaos proxy
+	Toxiproxy ToxiproxyComposeConfig
+
+	// ResourceLimits are the memory and CPU limits of the generated services
+	ResourceLimits map[string]models.ResourceLimits
+
+	// InfluxDB holds the InfluxDB bootstrap settings and credentials
+	InfluxDB InfluxDBComposeConfig
+
+	// TimescaleDB runs the TimescaleDB image for PostgreSQL
+	TimescaleDB bool
+
+	// PGVector runs the pgvector image for PostgreSQL, for the pgvector vector
+	// store or the vector extension
+	PGVector bool
+
+	// PostGIS runs the PostGIS image for PostgreSQL, for the postgis extension
+	// or the detected geospatial libraries
+	PostGIS bool
+
+	// PostgresSetup mounts setup.sh, which creates the extensions and roles,
+	// into the generated PostgreSQL services
+	PostgresSetup bool
+
+	// PostgresRoles are the roles setup.sh creates, whose passwords the
+	// PostgreSQL services pass it from .devcontainer/.env
+	PostgresRoles []models.PostgresRole
+
+	// TestDatabase holds configuration for the databases test suites use
+	TestDatabase TestDatabaseComposeConfig
+
+	// TestRunner holds configuration for the one-shot test service
+	TestRunner TestRunnerComposeConfig
+
+	// Imported holds services imported from the project's own compose file
+	Imported ImportedComposeConfig
+}
+
+// ServiceName returns the compose service running a backing service, which is
+// an imported service when the project's compose file already defines it.
+func (c *ComposeConfig) ServiceName(name string) string {
+	for _, s := range c.Services {
+		if s.Name == name {
+			return s.ComposeName()
+		}
+	}
+	return name
+}
+
+// OwnsServices reports whether any backing service is generated rather than imported.
+func (c *ComposeConfig) OwnsServices() bool {
+	for _, s := range c.Services {
+		if s.Existing == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Credential returns the reference to a generated password in
+// .devcontainer/.env (e.g., "${POSTGRES_PASSWORD}").
+func (c *ComposeConfig) Credential(name string) string {
+	return credentialRef(name)
 }
 
 // ComposeGenerator generates docker-compose.yml files.
-type ComposeGenerator struct{}
+type ComposeGenerator struct {
+	output
+}
 
 // NewComposeGenerator creates a new compose generator.
 func NewComposeGenerator() *ComposeGenerator {
@@ -197,23 +760,29 @@ func NewComposeGenerator() *ComposeGenerator {
 // Generate creates a docker-compose.yml file from a Detection.
 // The file is written to .devcontainer/docker-compose.yml.
 func (g *ComposeGenerator) Generate(detection *models.Detection, projectPath string, projectName string) error {
-	config := g.buildConfig(detection, projectName)
+	config, err := g.config(detection, projectName)
+	if err != nil {
+		return err
+	}
 
 	// Create .devcontainer directory (may already exist)
 	devcontainerDir := filepath.Join(projectPath, ".devcontainer")
-	if err := os.MkdirAll(devcontainerDir, 0755); err != nil {
+	if err := g.fs().MkdirAll(devcontainerDir, 0755); err != nil {
 		return fmt.Errorf("failed to create .devcontainer directory: %w", err)
 	}
 
 	// Generate docker-compose.yml content
 	content, err := g.render(config)
 	if err != nil {
-		return fmt.Errorf("failed to render template: %w", err)
+		return err
+	}
+	if content, _, err = adaptCompose(content, detection.ComposeVersion); err != nil {
+		return err
 	}
 
 	// Write to file
 	outputPath := filepath.Join(devcontainerDir, "docker-compose.yml")
-	if err := os.WriteFile(outputPath, content, 0644); err != nil {
+	if err := g.fs().WriteFile(outputPath, content, 0644); err != nil {
 		return fmt.Errorf("failed to write docker-compose.yml: %w", err)
 	}
 
@@ -223,15 +792,188 @@ func (g *ComposeGenerator) Generate(detection *models.Detection, projectPath str
 // GenerateContent returns the generated docker-compose.yml content without writing to disk.
 // Useful for dry-run mode.
 func (g *ComposeGenerator) GenerateContent(detection *models.Detection, projectName string) ([]byte, error) {
+	config, err := g.config(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+	content, err := g.render(config)
+	if err != nil {
+		return nil, err
+	}
+	content, _, err = adaptCompose(content, detection.ComposeVersion)
+	if err != nil {
+		return nil, err
+	}
+	if !detection.Proxy.Enabled() && detection.Timezone == "" {
+		return content, nil
+	}
+	imported := make([]string, 0, len(config.Imported.Services))
+	for _, service := range config.Imported.Services {
+		imported = append(imported, service.Name)
+	}
+	return editCompose(content, func(_, services *yaml.Node) {
+		addProxy(services, detection.Proxy, imported)
+		addTimezone(services, detection.Timezone)
+	})
+}
+
+// VersionWarnings returns what the compose version the files are generated
+// for (detection.ComposeVersion) lacks or handles differently than Docker
+// Compose v2, such as services left out or keys it ignores.
+func (g *ComposeGenerator) VersionWarnings(detection *models.Detection, projectName string) ([]string, error) {
+	config, err := g.config(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+	content, err := g.render(config)
+	if err != nil {
+		return nil, err
+	}
+	_, warnings, err := adaptCompose(content, detection.ComposeVersion)
+	return warnings, err
+}
+
+// config builds the ComposeConfig for a Detection, importing the services of the
+// project's existing compose file when there is one.
+func (g *ComposeGenerator) config(detection *models.Detection, projectName string) (*ComposeConfig, error) {
 	config := g.buildConfig(detection, projectName)
-	return g.render(config)
+	if detection.ExistingCompose != nil {
+		if err := g.importCompose(config, detection.ExistingCompose); err != nil {
+			return nil, fmt.Errorf("failed to import %s: %w", detection.ExistingCompose.File, err)
+		}
+	}
+	return config, nil
+}
+
+// Services returns the service names in the generated docker-compose.yml, in order.
+func (g *ComposeGenerator) Services(detection *models.Detection, projectName string) ([]string, error) {
+	services, err := g.services(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(services))
+	for _, s := range services {
+		names = append(names, s.Name)
+	}
+	return names, nil
+}
+
+// Images returns the images pulled by the generated docker-compose.yml, in service order.
+// Services built from a local Dockerfile are not included.
+func (g *ComposeGenerator) Images(detection *models.Detection, projectName string) ([]string, error) {
+	services, err := g.services(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []string
+	for _, s := range services {
+		if s.Image != "" && !containsString(images, s.Image) {
+			images = append(images, s.Image)
+		}
+	}
+	return images, nil
+}
+
+// composeService is the subset of a rendered compose service used for inspection.
+// Imported services may use any compose syntax, so the rest is kept as raw nodes.
+type composeService struct {
+	Name        string
+	Image       string    `yaml:"image"`
+	Build       yaml.Node `yaml:"build"`
+	Ports       yaml.Node `yaml:"ports"`
+	Environment yaml.Node `yaml:"environment"`
+	DependsOn   yaml.Node `yaml:"depends_on"`
+	Volumes     yaml.Node `yaml:"volumes"`
+	Extends     yaml.Node `yaml:"extends"`
+	Deploy      yaml.Node `yaml:"deploy"`
+	Profiles    yaml.Node `yaml:"profiles"`
+}
+
+// extends returns the service a compose service extends, or an empty string.
+func (s composeService) extends() string {
+	switch s.Extends.Kind {
+	case yaml.ScalarNode:
+		return s.Extends.Value
+	case yaml.MappingNode:
+		var extends struct {
+			Service string `yaml:"service"`
+		}
+		if err := s.Extends.Decode(&extends); err == nil {
+			return extends.Service
+		}
+	}
+	return ""
+}
+
+// services renders docker-compose.yml and parses its services in template order.
+func (g *ComposeGenerator) services(detection *models.Detection, projectName string) ([]composeService, error) {
+	content, err := g.GenerateContent(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+	return parseComposeServices(content)
+}
+
+// parseComposeServices parses the services of a rendered docker-compose.yml in file order.
+func parseComposeServices(content []byte) ([]composeService, error) {
+	var compose struct {
+		Services yaml.Node `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(content, &compose); err != nil {
+		return nil, fmt.Errorf("failed to parse docker-compose.yml: %w", err)
+	}
+
+	// Walk the mapping node to keep the template's service order
+	var services []composeService
+	for i := 1; i < len(compose.Services.Content); i += 2 {
+		service := composeService{Name: compose.Services.Content[i-1].Value}
+		if err := compose.Services.Content[i].Decode(&service); err != nil {
+			return nil, fmt.Errorf("failed to parse service %s: %w", service.Name, err)
+		}
+		services = append(services, service)
+	}
+
+	return services, nil
 }
 
 // buildConfig creates a ComposeConfig from a Detection.
 func (g *ComposeGenerator) buildConfig(detection *models.Detection, projectName string) *ComposeConfig {
 	config := &ComposeConfig{
-		Name:     projectName,
-		Services: make([]ServiceConfig, 0, len(detection.Services)),
+		Name:       projectName,
+		Services:   make([]ServiceConfig, 0, len(detection.Services)),
+		Dockerfile: ".devcontainer/Dockerfile",
+		Postgres: PostgresConnection{
+			User:     "postgres",
+			Password: credentialRef("POSTGRES_PASSWORD"),
+			Database: projectName + "_dev",
+		},
+	}
+
+	// Build the app from the project's own Dockerfile when it has a dev target
+	if detection.ReusesDockerfile() {
+		config.Dockerfile = detection.ExistingDockerfile.File
+		config.DockerfileTarget = detection.ExistingDockerfile.Target
+	} else if detection.WorkerStage() != "" {
+		// The generated Dockerfile has separate app and worker stages
+		config.DockerfileTarget = "app"
+	}
+	if detection.Prebuild != nil {
+		config.PrebuildImage = detection.Prebuild.Image
+	}
+
+	// Run as the host's UID/GID instead of root
+	config.NonRoot = detection.NonRoot
+	if !detection.ReusesDockerfile() {
+		config.User = detection.GetContainerUser()
+	}
+	config.Hardened = detection.Hardened
+	config.Windows = detection.Windows
+	config.DependencyDirs = detection.DependencyVolumeDirs()
+	config.PersistentVolumes = persistentVolumes(detection)
+	if detection.RemoteDocker {
+		config.RemoteWorkspace = RemoteWorkspaceVolume(projectName)
 	}
 
 	// Convert detected services to ServiceConfig
@@ -242,20 +984,41 @@ func (g *ComposeGenerator) buildConfig(detection *models.Detection, projectName
 	}
 
 	// Configure log sidecar if structured logging is detected
-	if detection.HasStructuredLogging() {
+	if detection.NeedsLogSidecar() {
 		config.LogSidecar = LogSidecarComposeConfig{
 			Enabled:          true,
 			LogFormat:        detection.LogFormat,
 			LoggingLibraries: detection.LoggingLibraries,
 		}
+		if len(textLogParsers(detection)) > 0 {
+			config.LogSidecar.ParsersPath = logParsersPath
+		}
+		config.LogSidecar.FileOutput = detection.Logging.FileOutput
+	}
+	config.LogRotation = LogRotationConfig{
+		MaxSize:  detection.GetLogMaxSize(),
+		MaxFiles: detection.GetLogMaxFiles(),
 	}
 
 	// Configure worker sidecar if queue libraries are detected
 	if detection.NeedsWorker() {
 		config.WorkerSidecar = WorkerSidecarConfig{
-			Enabled:        true,
-			Command:        detection.WorkerCommand,
-			QueueLibraries: detection.QueueLibraries,
+			Enabled:         true,
+			Command:         detection.WorkerCommand,
+			QueueLibraries:  detection.QueueLibraries,
+			Concurrency:     detection.GetWorkerConcurrency(),
+			Replicas:        detection.Worker.Replicas,
+			Memory:          detection.Worker.Memory,
+			CPUs:            detection.Worker.CPUs,
+			StopGracePeriod: detection.Worker.StopGracePeriod,
+			Target:          config.DockerfileTarget,
+		}
+		if stage := detection.WorkerStage(); stage != "" {
+			config.WorkerSidecar.Target = stage
+			config.WorkerSidecar.Dedicated = true
+		}
+		if detection.NeedsDeadLetter() {
+			configureDeadLetter(&config.WorkerSidecar, detection, projectName)
 		}
 
 		// Auto-add Redis if a Redis-based queue library is detected
@@ -265,6 +1028,126 @@ func (g *ComposeGenerator) buildConfig(detection *models.Detection, projectName
 				Name: "redis",
 			})
 		}
+
+		// Configure a queue management UI for queue libraries that have one
+		if dashboard := detection.GetQueueDashboard(); dashboard != "" {
+			config.QueueDashboard = QueueDashboardComposeConfig{
+				Enabled:   true,
+				Dashboard: dashboard,
+				Port:      detection.GetQueueDashboardPort(),
+			}
+			if dashboard == "bull-board" {
+				config.QueueDashboard.BullVersion = "BULLMQ"
+				if !detection.HasQueueLibrary("bullmq") {
+					config.QueueDashboard.BullVersion = "BULL"
+				}
+			}
+
+			// Bull and Asynq always auto-add Redis; Celery only uses it when detected
+			config.QueueDashboard.HasRedis = hasService(config.Services, "redis")
+		}
+	}
+
+	// Configure scheduler sidecar if cron/scheduled-job libraries are detected
+	if detection.NeedsScheduler() {
+		config.SchedulerSidecar = SchedulerSidecarComposeConfig{
+			Enabled:            true,
+			Command:            detection.SchedulerCommand,
+			SchedulerLibraries: detection.SchedulerLibraries,
+			AppURL:             fmt.Sprintf("http://app:%d", detection.GetAppPort()),
+		}
+
+		// Celery beat and rq-scheduler need a broker even without a worker
+		if (detection.HasSchedulerLibrary("celery-beat") || detection.HasSchedulerLibrary("rq-scheduler")) && !hasService(config.Services, "redis") {
+			config.Services = append(config.Services, ServiceConfig{
+				Name: "redis",
+			})
+		}
+	}
+
+	// Configure vector store if vector database clients are detected
+	if detection.NeedsVectorStore() {
+		config.VectorStore = VectorStoreComposeConfig{
+			Enabled:         true,
+			Store:           detection.GetVectorStore(),
+			VectorLibraries: detection.VectorLibraries,
+		}
+
+		// pgvector runs inside the postgres service
+		if config.VectorStore.Store == "pgvector" && !hasService(config.Services, "postgres") {
+			config.Services = append(config.Services, ServiceConfig{
+				Name: "postgres",
+			})
+		}
+	}
+
+	// Temporal workflows need a Temporal server, which stores its state in PostgreSQL
+	if detection.NeedsTemporal() {
+		for _, service := range []string{"temporal", "postgres"} {
+			if !hasService(config.Services, service) {
+				config.Services = append(config.Services, ServiceConfig{Name: service})
+			}
+		}
+	}
+
+	// Run the versions inferred from existing configs, or the defaults
+	for i := range config.Services {
+		config.Services[i].Version = detection.GetServiceVersion(config.Services[i].Name)
+		config.Services[i].Existing = detection.ExistingServiceFor(config.Services[i].Name)
+	}
+
+	// Connect to ClickHouse with development credentials
+	if NewClickHouseGenerator().ShouldGenerate(detection) {
+		config.ClickHouse = ClickHouseComposeConfig{
+			User:        "default",
+			Password:    credentialRef("CLICKHOUSE_PASSWORD"),
+			Database:    clickHouseDatabase(projectName),
+			NativePort:  clickHouseNativePort(detection),
+			Metrics:     detection.NeedsMetrics(),
+			MetricsPort: clickHouseMetricsPort,
+		}
+	}
+
+	config.Memcached = memcachedConfig(detection)
+	config.NATS = natsConfig(detection)
+	config.InfluxDB = influxDBConfig(detection, projectName)
+	config.TimescaleDB = detection.TimescaleDB
+	config.PGVector = config.VectorStore.Store == "pgvector" || detection.HasPostgresExtension("vector")
+	config.PostGIS = detection.UsesPostGIS()
+	config.PostgresSetup = NewPostgresSetupGenerator().ShouldGenerate(detection)
+	if config.PostgresSetup {
+		config.PostgresRoles = detection.PostgresRoles
+	}
+	config.PostgresDatabases = postgresDatabases(detection)
+	if detection.NeedsTemporal() && detection.ExistingServiceFor("temporal") == "" {
+		postgres := detection.ExistingServiceFor("postgres")
+		config.Temporal = TemporalComposeConfig{
+			Enabled:         true,
+			PostgresHost:    "postgres",
+			PostgresHealthy: postgres == "",
+			UIImage:         temporalUIImage,
+			UIPort:          TemporalUIPort,
+		}
+		if postgres != "" {
+			config.Temporal.PostgresHost = postgres
+		}
+	}
+
+	config.ProdImport = prodImportConfig(config, detection)
+	if detection.Sidecars.Allows(models.SidecarAdmin, true) {
+		config.AdminTools = adminToolsConfig(config)
+	}
+	config.Toxiproxy = toxiproxyConfig(config, detection)
+	config.ResourceLimits = resourceLimits(detection)
+
+	// Keep test suites away from development data
+	if detection.Testing.Isolation != "" {
+		configureTestDatabase(config, detection)
+	}
+
+	// Run the test suite against the stack with the test profile
+	if detection.TestCommand != "" {
+		configureTestRunner(config, detection)
 	}
 
 	// Configure backup sidecar if any database services are detected
@@ -272,7 +1155,7 @@ func (g *ComposeGenerator) buildConfig(detection *models.Detection, projectName
 	hasMySQL := hasService(config.Services, "mysql")
 	hasRedis := hasService(config.Services, "redis")
 
-	if hasPostgres || hasMySQL || hasRedis {
+	if (hasPostgres || hasMySQL || hasRedis) && detection.Sidecars.Allows(models.SidecarBackup, true) {
 		config.BackupSidecar = BackupSidecarComposeConfig{
 			Enabled:           true,
 			Schedule:          "0 3 * * *", // Daily at 3 AM
@@ -292,15 +1175,62 @@ func (g *ComposeGenerator) buildConfig(detection *models.Detection, projectName
 			uploadPath = "/uploads"
 		}
 
+		processor := processorConfig(detection, projectName)
+
+		// ClamAV keeps its signature database in memory
+		memoryLimit := "512M"
+		if processor.ScanEnabled {
+			memoryLimit = "2G"
+		}
+
 		config.FileProcessorSidecar = FileProcessorSidecarComposeConfig{
 			Enabled:             true,
 			FileUploadLibraries: detection.FileUploadLibraries,
 			UploadPath:          uploadPath,
-			ProcessImages:       true,  // Enable by default
-			ProcessDocuments:    false, // Disabled by default
-			ProcessVideo:        false, // Disabled by default
-			MemoryLimit:         "512M",
+			ProcessImages:       processor.ProcessImages,
+			ProcessDocuments:    processor.ProcessDocuments,
+			ProcessVideo:        processor.ProcessVideo,
+			MemoryLimit:         memoryLimit,
 			CPULimit:            "0.5",
+			PollInterval:        processor.PollInterval,
+			MaxFileSize:         processor.MaxFileSize,
+			ThumbnailSize:       processor.ThumbnailSize,
+			ExtraThumbnailSizes: processor.ExtraThumbnailSizes,
+			AllowedMIMETypes:    processor.AllowedMIMETypes,
+			ScanEnabled:         processor.ScanEnabled,
+			SanitizeImages:      processor.SanitizeImages,
+			NotifyMethod:        processor.NotifyMethod,
+			NotifyURL:           processor.NotifyURL,
+			NotifyQueue:         processor.NotifyQueue,
+			SharedVolume:        !processor.S3,
+			FilesPath:           "/uploads",
+		}
+
+		// S3 uploads go through a bucket on MinIO, or LocalStack if it emulates S3
+		if processor.S3 {
+			endpoint, accessKey, secretKey := s3UploadEndpoint(detection)
+			config.FileProcessorSidecar.S3 = true
+			config.FileProcessorSidecar.FilesPath = "/files"
+			config.FileProcessorSidecar.S3Endpoint = endpoint
+			config.FileProcessorSidecar.S3AccessKey = accessKey
+			config.FileProcessorSidecar.S3SecretKey = secretKey
+			config.FileProcessorSidecar.S3Bucket = processor.S3Bucket
+
+			if detection.NeedsMinIO() {
+				config.MinIO = MinIOComposeConfig{
+					Enabled:     true,
+					Bucket:      processor.S3Bucket,
+					APIPort:     9000,
+					ConsolePort: 9001,
+				}
+			}
+		}
+
+		// Queue notifications are pushed onto a Redis list
+		if processor.NotifyMethod == "queue" && !hasService(config.Services, "redis") {
+			config.Services = append(config.Services, ServiceConfig{
+				Name: "redis",
+			})
 		}
 	}
 
@@ -327,6 +1257,8 @@ func (g *ComposeGenerator) buildConfig(detection *models.Detection, projectName
 			HasPostgres:      hasPostgres,
 			HasRedis:         hasRedis,
 			RetentionDays:    7,
+
+			PostgresExporterImage: postgresExporterImage(detection.GetServiceVersion("postgres")),
 		}
 	}
 
@@ -346,9 +1278,169 @@ func (g *ComposeGenerator) buildConfig(detection *models.Detection, projectName
 		}
 	}
 
+	// Configure gRPC settings and grpcui sidecar if gRPC libraries are detected
+	if detection.NeedsGRPC() {
+		config.GRPCSidecar = GRPCSidecarComposeConfig{
+			Enabled:       true,
+			GRPCLibraries: detection.GRPCLibraries,
+			GRPCPort:      detection.GetGRPCPort(),
+			UIEnabled:     detection.Sidecars.Allows(models.SidecarGRPCUI, true),
+			UIPort:        8082,
+		}
+	}
+
+	// Configure Keycloak if OIDC/OAuth libraries are detected
+	if detection.NeedsAuthProvider() {
+		keycloak := DefaultKeycloakConfig()
+		config.KeycloakSidecar = KeycloakSidecarComposeConfig{
+			Enabled:       true,
+			AuthLibraries: detection.AuthLibraries,
+			Port:          8180,
+			Realm:         keycloak.Realm,
+			ClientID:      keycloak.ClientID,
+			ClientSecret:  keycloak.ClientSecret,
+		}
+	}
+
+	// Configure stripe-cli webhook forwarding if a Stripe SDK is detected
+	if detection.NeedsStripe() {
+		config.StripeSidecar = StripeSidecarComposeConfig{
+			Enabled:          true,
+			PaymentLibraries: detection.PaymentLibraries,
+			ForwardTo:        fmt.Sprintf("app:%d/webhooks", detection.GetAppPort()),
+			SecretFile:       "/run/stripe/webhook-secret",
+		}
+	}
+
+	// Configure a Selenium Grid if a WebDriver client is detected
+	if detection.NeedsSeleniumGrid() {
+		config.SeleniumGrid = SeleniumGridComposeConfig{
+			Enabled:            true,
+			WebDriverLibraries: detection.WebDriverLibraries,
+			Version:            seleniumVersion,
+			Port:               4444,
+			Nodes:              seleniumNodes,
+		}
+	}
+
+	// Configure LocalStack if AWS services beyond S3 are detected
+	if detection.NeedsLocalStack() {
+		config.LocalStackSidecar = LocalStackSidecarComposeConfig{
+			Enabled:        true,
+			Services:       strings.Join(detection.AWSServices, ","),
+			Port:           4566,
+			Region:         DefaultLocalStackConfig().Region,
+			ResourcePrefix: awsResourceName(projectName),
+			HasSQS:         detection.HasAWSService("sqs"),
+			HasSNS:         detection.HasAWSService("sns"),
+			HasDynamoDB:    detection.HasAWSService("dynamodb"),
+			HasS3:          detection.HasAWSService("s3"),
+		}
+	}
+
+	// Configure Ollama if local LLM inference is requested
+	if detection.NeedsOllama() {
+		config.OllamaSidecar = OllamaSidecarComposeConfig{
+			Enabled:      true,
+			LLMLibraries: detection.LLMLibraries,
+			Model:        "llama3.2",
+			Port:         11434,
+		}
+	}
+
+	// Configure WireMock if third-party APIs should be stubbed
+	config.WireMock = wireMockConfig(detection)
+
+	// Configure GlitchTip if Sentry SDK errors should be received locally
+	config.GlitchTip = glitchTipConfig(detection)
+
+	// Configure the search engine if Meilisearch or Typesense clients are detected
+	config.Search = searchConfig(detection)
+
+	// Configure Mailpit if mail libraries are detected, so mail is caught locally
+	config.Mailpit = mailpitConfig(detection)
+
+	// Configure Gatus if a status page for the stack was requested
+	config.Gatus = gatusComposeConfig(detection)
+
+	// Configure frontend dev server if a frontend coexists with the backend
+	if detection.NeedsWebService() {
+		config.WebService = buildWebServiceConfig(detection)
+	}
+
 	return config
 }
 
+// buildWebServiceConfig creates the frontend dev server configuration from a Detection.
+func buildWebServiceConfig(detection *models.Detection) WebServiceConfig {
+	nodeVersion := "20"
+	if detection.Language == "node" && detection.Version != "" {
+		nodeVersion = detection.Version
+	}
+
+	workingDir := "/workspace"
+	if detection.FrontendDir != "." {
+		workingDir = "/workspace/" + detection.FrontendDir
+	}
+
+	port := detection.FrontendPort
+	web := WebServiceConfig{
+		Enabled:    true,
+		Image:      "node:" + nodeVersion,
+		WorkingDir: workingDir,
+		Port:       port,
+	}
+
+	// Dev servers must bind 0.0.0.0 to be reachable from outside the container.
+	// Polling is required for file watching on bind mounts (macOS/Windows hosts).
+	switch detection.FrontendFramework {
+	case "nextjs":
+		web.Framework = "Next.js"
+		web.Command = fmt.Sprintf(`sh -c "npm install && npm run dev -- --hostname 0.0.0.0 --port %d"`, port)
+		web.Environment = []string{"WATCHPACK_POLLING=true"}
+	case "cra":
+		web.Framework = "Create React App"
+		web.Command = `sh -c "npm install && npm start"`
+		web.Environment = []string{
+			"HOST=0.0.0.0",
+			fmt.Sprintf("PORT=%d", port),
+			fmt.Sprintf("WDS_SOCKET_PORT=%d", port), // HMR websocket port as seen by the browser
+			"CHOKIDAR_USEPOLLING=true",
+		}
+	default:
+		// Vite-based dev servers (Vite, SvelteKit, Astro) serve HMR on the dev server port
+		switch detection.FrontendFramework {
+		case "sveltekit":
+			web.Framework = "SvelteKit"
+		case "astro":
+			web.Framework = "Astro"
+		default:
+			web.Framework = "Vite"
+		}
+		web.Command = fmt.Sprintf(`sh -c "npm install && npm run dev -- --host 0.0.0.0 --port %d"`, port)
+		web.Environment = []string{"CHOKIDAR_USEPOLLING=true"}
+	}
+
+	return web
+}
+
+// configureDeadLetter adds the retry policy and dead-letter queue to a worker.
+func configureDeadLetter(worker *WorkerSidecarConfig, detection *models.Detection, projectName string) {
+	dl := DeadLetterConfig(detection, projectName)
+	worker.DeadLetter = true
+	worker.MaxRetries = dl.MaxRetries
+	worker.Backoff = dl.Backoff
+	worker.QueueName = dl.QueueName
+	worker.DeadLetterQueue = dl.DeadLetterQueue
+	worker.RabbitMQ = usesRabbitMQ(detection.QueueLibraries)
+
+	// A dedicated worker stage's CMD already starts the worker
+	worker.DeadLetterCommand = detection.Worker.DeadLetter.Command
+	if worker.DeadLetterCommand == "" && !worker.Dedicated {
+		worker.DeadLetterCommand = worker.Command
+	}
+}
+
 // redisBasedQueueLibraries contains queue libraries that require Redis as a broker.
 var redisBasedQueueLibraries = map[string]bool{
 	// Node.js
@@ -376,6 +1468,22 @@ func needsRedis(queueLibraries []string) bool {
 	return false
 }
 
+// rabbitMQQueueLibraries contains queue libraries that use RabbitMQ as their broker.
+var rabbitMQQueueLibraries = map[string]bool{
+	// Rust
+	"lapin": true,
+}
+
+// usesRabbitMQ checks if any detected queue library uses RabbitMQ.
+func usesRabbitMQ(queueLibraries []string) bool {
+	for _, lib := range queueLibraries {
+		if rabbitMQQueueLibraries[lib] {
+			return true
+		}
+	}
+	return false
+}
+
 // hasService checks if a service is already in the list.
 func hasService(services []ServiceConfig, name string) bool {
 	for _, s := range services {
@@ -388,15 +1496,15 @@ func hasService(services []ServiceConfig, name string) bool {
 
 // render executes the template with the given config.
 func (g *ComposeGenerator) render(config *ComposeConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("docker-compose.yml.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load template: %w", err)
-	}
+	return renderTemplate("docker-compose.yml.tmpl", config)
+}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
+// containsString checks if a string is in the list.
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
 	}
-
-	return buf.Bytes(), nil
+	return false
 }
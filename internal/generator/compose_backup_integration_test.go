@@ -41,7 +41,7 @@ func TestComposeGenerator_BackupSidecar_Integration(t *testing.T) {
 			wantInEnv: []string{
 				"DB_HOST=postgres",
 				"DB_USER=postgres",
-				"DB_PASSWORD=postgres",
+				"DB_PASSWORD=${POSTGRES_PASSWORD}",
 				"DB_NAME=myapp_dev",
 				"RETENTION_DAYS=7",
 			},
@@ -126,7 +126,7 @@ func TestComposeGenerator_BackupSidecar_Integration(t *testing.T) {
 			wantInEnv: []string{
 				"DB_HOST=mysql",
 				"DB_USER=root",
-				"DB_PASSWORD=mysql",
+				"DB_PASSWORD=${MYSQL_ROOT_PASSWORD}",
 				"DB_NAME=rust-api_dev",
 			},
 			dontWant: []string{
@@ -391,11 +391,13 @@ func TestBackupSidecar_WithWorkerSidecar_Integration(t *testing.T) {
 	gen := NewComposeGenerator()
 
 	detection := &models.Detection{
-		Language:       "node",
-		Version:        "20",
-		Services:       []string{"postgres", "redis"},
-		QueueLibraries: []string{"bullmq"},
-		WorkerCommand:  "npm run worker",
+		Language: "node",
+		Version:  "20",
+		Services: []string{"postgres", "redis"},
+		Capabilities: models.Capabilities{
+			QueueLibraries: []string{"bullmq"},
+		},
+		WorkerCommand: "npm run worker",
 	}
 
 	content, err := gen.GenerateContent(detection, "fullstack-app")
@@ -437,11 +439,13 @@ func TestBackupSidecar_WithLogSidecar_Integration(t *testing.T) {
 	gen := NewComposeGenerator()
 
 	detection := &models.Detection{
-		Language:         "go",
-		Version:          "1.23",
-		Services:         []string{"postgres"},
-		LoggingLibraries: []string{"zap"},
-		LogFormat:        "json",
+		Language: "go",
+		Version:  "1.23",
+		Services: []string{"postgres"},
+		Capabilities: models.Capabilities{
+			LoggingLibraries: []string{"zap"},
+		},
+		LogFormat: "json",
 	}
 
 	content, err := gen.GenerateContent(detection, "observability-app")
@@ -482,13 +486,15 @@ func TestBackupSidecar_AllSidecars_Integration(t *testing.T) {
 	gen := NewComposeGenerator()
 
 	detection := &models.Detection{
-		Language:         "node",
-		Version:          "20",
-		Services:         []string{"postgres", "redis"},
-		LoggingLibraries: []string{"pino"},
-		LogFormat:        "json",
-		QueueLibraries:   []string{"bullmq"},
-		WorkerCommand:    "npm run worker",
+		Language: "node",
+		Version:  "20",
+		Services: []string{"postgres", "redis"},
+		Capabilities: models.Capabilities{
+			LoggingLibraries: []string{"pino"},
+			QueueLibraries:   []string{"bullmq"},
+		},
+		LogFormat:     "json",
+		WorkerCommand: "npm run worker",
 	}
 
 	content, err := gen.GenerateContent(detection, "mega-app")
@@ -27,12 +27,14 @@ func TestComposeGenerator_FileProcessorSidecar_Integration(t *testing.T) {
 		{
 			name: "node multer project - full file processor",
 			detection: &models.Detection{
-				Language:            "node",
-				Version:             "20",
-				Services:            []string{},
-				FileUploadLibraries: []string{"multer"},
-				UploadPath:          "/uploads",
-				Confidence:          1.0,
+				Language: "node",
+				Version:  "20",
+				Services: []string{},
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: []string{"multer"},
+				},
+				UploadPath: "/uploads",
+				Confidence: 1.0,
 			},
 			projectName:   "upload-app",
 			wantProcessor: true,
@@ -56,11 +58,13 @@ func TestComposeGenerator_FileProcessorSidecar_Integration(t *testing.T) {
 		{
 			name: "python fastapi with python-multipart",
 			detection: &models.Detection{
-				Language:            "python",
-				Version:             "3.11",
-				Services:            []string{},
-				FileUploadLibraries: []string{"python-multipart"},
-				Confidence:          1.0,
+				Language: "python",
+				Version:  "3.11",
+				Services: []string{},
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: []string{"python-multipart"},
+				},
+				Confidence: 1.0,
 			},
 			projectName:   "py-api",
 			wantProcessor: true,
@@ -77,12 +81,14 @@ func TestComposeGenerator_FileProcessorSidecar_Integration(t *testing.T) {
 		{
 			name: "go with upload directory detected",
 			detection: &models.Detection{
-				Language:            "go",
-				Version:             "1.23",
-				Services:            []string{"postgres"},
-				FileUploadLibraries: []string{"multipart"},
-				UploadPath:          "uploads",
-				Confidence:          1.0,
+				Language: "go",
+				Version:  "1.23",
+				Services: []string{"postgres"},
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: []string{"multipart"},
+				},
+				UploadPath: "uploads",
+				Confidence: 1.0,
 			},
 			projectName:   "go-upload",
 			wantProcessor: true,
@@ -96,11 +102,13 @@ func TestComposeGenerator_FileProcessorSidecar_Integration(t *testing.T) {
 		{
 			name: "rust with actix-multipart",
 			detection: &models.Detection{
-				Language:            "rust",
-				Version:             "1.75",
-				Services:            []string{"redis"},
-				FileUploadLibraries: []string{"actix-multipart"},
-				Confidence:          1.0,
+				Language: "rust",
+				Version:  "1.75",
+				Services: []string{"redis"},
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: []string{"actix-multipart"},
+				},
+				Confidence: 1.0,
 			},
 			projectName:   "rust-upload",
 			wantProcessor: true,
@@ -113,11 +121,13 @@ func TestComposeGenerator_FileProcessorSidecar_Integration(t *testing.T) {
 		{
 			name: "no upload library - no file processor",
 			detection: &models.Detection{
-				Language:            "node",
-				Version:             "20",
-				Services:            []string{"postgres"},
-				FileUploadLibraries: []string{},
-				Confidence:          1.0,
+				Language: "node",
+				Version:  "20",
+				Services: []string{"postgres"},
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: []string{},
+				},
+				Confidence: 1.0,
 			},
 			projectName:   "regular-app",
 			wantProcessor: false,
@@ -133,11 +143,13 @@ func TestComposeGenerator_FileProcessorSidecar_Integration(t *testing.T) {
 		{
 			name: "multiple upload libraries",
 			detection: &models.Detection{
-				Language:            "node",
-				Version:             "20",
-				Services:            []string{},
-				FileUploadLibraries: []string{"multer", "formidable", "sharp"},
-				Confidence:          1.0,
+				Language: "node",
+				Version:  "20",
+				Services: []string{},
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: []string{"multer", "formidable", "sharp"},
+				},
+				Confidence: 1.0,
 			},
 			projectName:   "multi-upload",
 			wantProcessor: true,
@@ -149,13 +161,15 @@ func TestComposeGenerator_FileProcessorSidecar_Integration(t *testing.T) {
 		{
 			name: "file processor with worker sidecar",
 			detection: &models.Detection{
-				Language:            "node",
-				Version:             "20",
-				Services:            []string{"redis"},
-				FileUploadLibraries: []string{"multer"},
-				QueueLibraries:      []string{"bullmq"},
-				WorkerCommand:       "npm run worker",
-				Confidence:          1.0,
+				Language: "node",
+				Version:  "20",
+				Services: []string{"redis"},
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: []string{"multer"},
+					QueueLibraries:      []string{"bullmq"},
+				},
+				WorkerCommand: "npm run worker",
+				Confidence:    1.0,
 			},
 			projectName:   "full-app",
 			wantProcessor: true,
@@ -170,11 +184,13 @@ func TestComposeGenerator_FileProcessorSidecar_Integration(t *testing.T) {
 		{
 			name: "file processor with backup sidecar",
 			detection: &models.Detection{
-				Language:            "node",
-				Version:             "20",
-				Services:            []string{"postgres"},
-				FileUploadLibraries: []string{"multer"},
-				Confidence:          1.0,
+				Language: "node",
+				Version:  "20",
+				Services: []string{"postgres"},
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: []string{"multer"},
+				},
+				Confidence: 1.0,
 			},
 			projectName:   "backup-upload",
 			wantProcessor: true,
@@ -246,9 +262,11 @@ func TestComposeGenerator_FileProcessorSidecar_Integration(t *testing.T) {
 func TestComposeGenerator_FileProcessorSidecar_ResourceLimits(t *testing.T) {
 	gen := NewComposeGenerator()
 	detection := &models.Detection{
-		Language:            "node",
-		Version:             "20",
-		FileUploadLibraries: []string{"multer"},
+		Language: "node",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"multer"},
+		},
 	}
 
 	content, err := gen.GenerateContent(detection, "limits-test")
@@ -283,12 +301,14 @@ func TestEndToEndFileProcessorGeneration(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	detection := &models.Detection{
-		Language:            "node",
-		Version:             "20",
-		Services:            []string{"postgres"},
-		FileUploadLibraries: []string{"multer"},
-		UploadPath:          "/uploads",
-		Confidence:          1.0,
+		Language: "node",
+		Version:  "20",
+		Services: []string{"postgres"},
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"multer"},
+		},
+		UploadPath: "/uploads",
+		Confidence: 1.0,
 	}
 
 	// Generate compose file
@@ -472,15 +492,17 @@ func TestDockerfileProcessorContent(t *testing.T) {
 func TestFileProcessorWithAllSidecars(t *testing.T) {
 	gen := NewComposeGenerator()
 	detection := &models.Detection{
-		Language:            "node",
-		Version:             "20",
-		Services:            []string{"postgres", "redis"},
-		FileUploadLibraries: []string{"multer"},
-		QueueLibraries:      []string{"bullmq"},
-		WorkerCommand:       "npm run worker",
-		LoggingLibraries:    []string{"pino"},
-		LogFormat:           "json",
-		Confidence:          1.0,
+		Language: "node",
+		Version:  "20",
+		Services: []string{"postgres", "redis"},
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"multer"},
+			QueueLibraries:      []string{"bullmq"},
+			LoggingLibraries:    []string{"pino"},
+		},
+		WorkerCommand: "npm run worker",
+		LogFormat:     "json",
+		Confidence:    1.0,
 	}
 
 	content, err := gen.GenerateContent(detection, "full-stack")
@@ -527,9 +549,11 @@ func TestFileProcessorWithAllSidecars(t *testing.T) {
 func TestFileProcessorAppEnvironmentVariables(t *testing.T) {
 	gen := NewComposeGenerator()
 	detection := &models.Detection{
-		Language:            "node",
-		Version:             "20",
-		FileUploadLibraries: []string{"multer"},
+		Language: "node",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"multer"},
+		},
 	}
 
 	content, err := gen.GenerateContent(detection, "env-app")
@@ -0,0 +1,209 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestComposeGenerator_FileProcessorS3 tests the S3-backed upload mode of the file processor.
+func TestComposeGenerator_FileProcessorS3(t *testing.T) {
+	tests := []struct {
+		name      string
+		detection *models.Detection
+		wantParts []string
+		dontWant  []string
+	}{
+		{
+			name: "s3 sdk uses a minio bucket",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					AWSServices:         []string{"s3"},
+					FileUploadLibraries: []string{"multer"},
+				},
+			},
+			wantParts: []string{
+				"  minio:",
+				"image: minio/minio:latest",
+				`"9001:9001"`,
+				"  minio-init:",
+				"mc mb --ignore-existing local/upload-app",
+				"minio-init:\n        condition: service_completed_successfully",
+				"AWS_ENDPOINT_URL=http://minio:9000",
+				"S3_BUCKET=upload-app",
+				"UPLOAD_PREFIX=pending/",
+				"S3_ENDPOINT=http://minio:9000",
+				"PENDING_PATH=/files/pending",
+				"minio-data:",
+			},
+			dontWant: []string{"uploads:/uploads", "UPLOAD_PATH=", "  uploads:", "localstack:"},
+		},
+		{
+			name: "localstack already emulates s3",
+			detection: &models.Detection{
+				Language: "python",
+				Version:  "3.12",
+				Capabilities: models.Capabilities{
+					AWSServices:         []string{"s3", "sqs"},
+					FileUploadLibraries: []string{"python-multipart"},
+				},
+			},
+			wantParts: []string{
+				"S3_ENDPOINT=http://localstack:4566",
+				"AWS_ACCESS_KEY_ID=test",
+				"localstack:\n        condition: service_healthy",
+				"UPLOAD_PREFIX=pending/",
+			},
+			dontWant: []string{"  minio:", "minio-data:", "uploads:/uploads"},
+		},
+		{
+			name: "storage volume keeps the shared volume",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					AWSServices:         []string{"s3"},
+					FileUploadLibraries: []string{"multer"},
+				},
+				FileProcessor: models.FileProcessorOptions{Storage: "volume"},
+			},
+			wantParts: []string{"uploads:/uploads", "PENDING_PATH=/uploads/pending"},
+			dontWant:  []string{"  minio:", "S3_ENDPOINT="},
+		},
+		{
+			name: "storage s3 without an s3 sdk",
+			detection: &models.Detection{
+				Language: "go",
+				Version:  "1.23",
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: []string{"multipart"},
+				},
+				FileProcessor: models.FileProcessorOptions{Storage: "s3"},
+			},
+			wantParts: []string{"  minio:", "S3_ENDPOINT=http://minio:9000"},
+			dontWant:  []string{"uploads:/uploads"},
+		},
+	}
+
+	gen := NewComposeGenerator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := gen.GenerateContent(tt.detection, "upload-app")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+
+			yamlContent := string(content)
+
+			for _, want := range tt.wantParts {
+				if !strings.Contains(yamlContent, want) {
+					t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, yamlContent)
+				}
+			}
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(yamlContent, dontWant) {
+					t.Errorf("docker-compose.yml should NOT contain %q", dontWant)
+				}
+			}
+
+			var parsed map[string]interface{}
+			if err := yaml.Unmarshal(content, &parsed); err != nil {
+				t.Errorf("Generated YAML is invalid: %v", err)
+			}
+		})
+	}
+}
+
+// TestFileProcessorS3_DevcontainerPorts tests that the MinIO ports are forwarded.
+func TestFileProcessorS3_DevcontainerPorts(t *testing.T) {
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			AWSServices:         []string{"s3"},
+			FileUploadLibraries: []string{"multer"},
+		},
+	}
+
+	content, err := NewDevcontainerGenerator().GenerateContent(detection, "upload-app")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	var parsed struct {
+		ForwardPorts []int `json:"forwardPorts"`
+	}
+	if err := json.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v\n%s", err, content)
+	}
+
+	for _, port := range []int{9000, 9001} {
+		if !containsPort(parsed.ForwardPorts, port) {
+			t.Errorf("Expected port %d in forwardPorts, got %v", port, parsed.ForwardPorts)
+		}
+	}
+}
+
+// TestProcessorS3Bridge tests the s3-bridge.sh script and S3 processor image.
+func TestProcessorS3Bridge(t *testing.T) {
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			AWSServices:         []string{"s3"},
+			FileUploadLibraries: []string{"multer"},
+		},
+	}
+	config := processorConfig(detection, "upload-app")
+	if !config.S3 || config.S3Bucket != "upload-app" || config.S3Endpoint != "http://minio:9000" {
+		t.Fatalf("processorConfig() S3 = %v, bucket %q, endpoint %q", config.S3, config.S3Bucket, config.S3Endpoint)
+	}
+
+	g := NewProcessorSidecarGenerator()
+
+	bridge, err := g.GenerateS3BridgeScript(config)
+	if err != nil {
+		t.Fatalf("GenerateS3BridgeScript() error = %v", err)
+	}
+	for _, want := range []string{
+		`S3_BUCKET="${S3_BUCKET:-upload-app}"`,
+		`mc find "s3/$S3_BUCKET/pending"`,
+		`mc mirror --watch --overwrite --quiet "$PROCESSED_DIR" "s3/$S3_BUCKET/processed"`,
+	} {
+		if !strings.Contains(string(bridge), want) {
+			t.Errorf("s3-bridge.sh should contain %q", want)
+		}
+	}
+
+	dockerfile, err := g.GenerateDockerfile(config)
+	if err != nil {
+		t.Fatalf("GenerateDockerfile() error = %v", err)
+	}
+	for _, want := range []string{"COPY --from=minio/mc:latest", "COPY scripts/s3-bridge.sh"} {
+		if !strings.Contains(string(dockerfile), want) {
+			t.Errorf("Dockerfile.processor should contain %q", want)
+		}
+	}
+
+	entrypoint, err := g.GenerateEntrypoint(config)
+	if err != nil {
+		t.Fatalf("GenerateEntrypoint() error = %v", err)
+	}
+	if !strings.Contains(string(entrypoint), "/usr/local/bin/s3-bridge.sh &") {
+		t.Error("entrypoint should start s3-bridge.sh in the background")
+	}
+
+	notify, err := g.GenerateNotifyScript(config)
+	if err != nil {
+		t.Fatalf("GenerateNotifyScript() error = %v", err)
+	}
+	if !strings.Contains(string(notify), `OUTPUT_PATH="s3://$S3_BUCKET/processed/$FILENAME"`) {
+		t.Error("notify.sh should report the S3 location of processed files")
+	}
+}
@@ -11,11 +11,13 @@ import (
 func TestComposeGenerator_FileProcessorSidecar(t *testing.T) {
 	gen := NewComposeGenerator()
 	detection := &models.Detection{
-		Language:            "node",
-		Version:             "20",
-		Services:            []string{},
-		FileUploadLibraries: []string{"multer"},
-		UploadPath:          "/uploads",
+		Language: "node",
+		Version:  "20",
+		Services: []string{},
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"multer"},
+		},
+		UploadPath: "/uploads",
 	}
 
 	content, err := gen.GenerateContent(detection, "upload-app")
@@ -59,11 +61,13 @@ func TestComposeGenerator_FileProcessorSidecar(t *testing.T) {
 func TestComposeGenerator_FileProcessorSidecar_AppVolume(t *testing.T) {
 	gen := NewComposeGenerator()
 	detection := &models.Detection{
-		Language:            "python",
-		Version:             "3.11",
-		Services:            []string{},
-		FileUploadLibraries: []string{"python-multipart"},
-		UploadPath:          "/data/uploads",
+		Language: "python",
+		Version:  "3.11",
+		Services: []string{},
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"python-multipart"},
+		},
+		UploadPath: "/data/uploads",
 	}
 
 	content, err := gen.GenerateContent(detection, "py-upload")
@@ -83,10 +87,12 @@ func TestComposeGenerator_FileProcessorSidecar_AppVolume(t *testing.T) {
 func TestComposeGenerator_FileProcessorSidecar_EnvironmentVars(t *testing.T) {
 	gen := NewComposeGenerator()
 	detection := &models.Detection{
-		Language:            "node",
-		Version:             "20",
-		Services:            []string{},
-		FileUploadLibraries: []string{"formidable"},
+		Language: "node",
+		Version:  "20",
+		Services: []string{},
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"formidable"},
+		},
 	}
 
 	content, err := gen.GenerateContent(detection, "env-test")
@@ -114,10 +120,12 @@ func TestComposeGenerator_FileProcessorSidecar_EnvironmentVars(t *testing.T) {
 func TestComposeGenerator_FileProcessorSidecar_NotGenerated(t *testing.T) {
 	gen := NewComposeGenerator()
 	detection := &models.Detection{
-		Language:            "go",
-		Version:             "1.23",
-		Services:            []string{"postgres"},
-		FileUploadLibraries: []string{}, // No file upload libraries
+		Language: "go",
+		Version:  "1.23",
+		Services: []string{"postgres"},
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{}, // No file upload libraries
+		},
 	}
 
 	content, err := gen.GenerateContent(detection, "no-uploads")
@@ -148,11 +156,13 @@ func TestComposeGenerator_FileProcessorSidecar_NotGenerated(t *testing.T) {
 func TestComposeGenerator_FileProcessorSidecar_WithOtherServices(t *testing.T) {
 	gen := NewComposeGenerator()
 	detection := &models.Detection{
-		Language:            "node",
-		Version:             "20",
-		Services:            []string{"postgres", "redis"},
-		FileUploadLibraries: []string{"multer"},
-		UploadPath:          "/uploads",
+		Language: "node",
+		Version:  "20",
+		Services: []string{"postgres", "redis"},
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"multer"},
+		},
+		UploadPath: "/uploads",
 	}
 
 	content, err := gen.GenerateContent(detection, "full-stack")
@@ -194,12 +204,14 @@ func TestComposeGenerator_FileProcessorSidecar_WithOtherServices(t *testing.T) {
 func TestComposeGenerator_FileProcessorSidecar_WithWorker(t *testing.T) {
 	gen := NewComposeGenerator()
 	detection := &models.Detection{
-		Language:            "node",
-		Version:             "20",
-		Services:            []string{"redis"},
-		FileUploadLibraries: []string{"multer"},
-		QueueLibraries:      []string{"bullmq"},
-		WorkerCommand:       "npm run worker",
+		Language: "node",
+		Version:  "20",
+		Services: []string{"redis"},
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"multer"},
+			QueueLibraries:      []string{"bullmq"},
+		},
+		WorkerCommand: "npm run worker",
 	}
 
 	content, err := gen.GenerateContent(detection, "worker-upload")
@@ -237,11 +249,13 @@ func TestBuildComposeConfig_FileProcessorSidecar(t *testing.T) {
 	gen := NewComposeGenerator()
 
 	detection := &models.Detection{
-		Language:            "node",
-		Version:             "20",
-		Services:            []string{},
-		FileUploadLibraries: []string{"multer", "sharp"},
-		UploadPath:          "/custom/uploads",
+		Language: "node",
+		Version:  "20",
+		Services: []string{},
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"multer", "sharp"},
+		},
+		UploadPath: "/custom/uploads",
 	}
 
 	config := gen.buildConfig(detection, "upload-app")
@@ -272,10 +286,12 @@ func TestBuildComposeConfig_FileProcessorSidecar_DefaultUploadPath(t *testing.T)
 	gen := NewComposeGenerator()
 
 	detection := &models.Detection{
-		Language:            "python",
-		Version:             "3.11",
-		FileUploadLibraries: []string{"python-multipart"},
-		UploadPath:          "", // Empty, should default to /uploads
+		Language: "python",
+		Version:  "3.11",
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"python-multipart"},
+		},
+		UploadPath: "", // Empty, should default to /uploads
 	}
 
 	config := gen.buildConfig(detection, "default-path-app")
@@ -290,10 +306,12 @@ func TestBuildComposeConfig_NoFileProcessorSidecar(t *testing.T) {
 	gen := NewComposeGenerator()
 
 	detection := &models.Detection{
-		Language:            "rust",
-		Version:             "1.75",
-		Services:            []string{"redis"},
-		FileUploadLibraries: []string{},
+		Language: "rust",
+		Version:  "1.75",
+		Services: []string{"redis"},
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{},
+		},
 	}
 
 	config := gen.buildConfig(detection, "no-upload-app")
@@ -308,10 +326,12 @@ func TestBuildComposeConfig_NoFileProcessorSidecar(t *testing.T) {
 func TestComposeGenerator_FileProcessorSidecar_DependsOnApp(t *testing.T) {
 	gen := NewComposeGenerator()
 	detection := &models.Detection{
-		Language:            "node",
-		Version:             "20",
-		Services:            []string{},
-		FileUploadLibraries: []string{"multer"},
+		Language: "node",
+		Version:  "20",
+		Services: []string{},
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"multer"},
+		},
 	}
 
 	content, err := gen.GenerateContent(detection, "deps-test")
@@ -326,3 +346,145 @@ func TestComposeGenerator_FileProcessorSidecar_DependsOnApp(t *testing.T) {
 		t.Error("file-processor should have depends_on")
 	}
 }
+
+// TestComposeGenerator_FileProcessorSidecar_PipelineSettings tests that file_processor settings reach the sidecar environment.
+func TestComposeGenerator_FileProcessorSidecar_PipelineSettings(t *testing.T) {
+	gen := NewComposeGenerator()
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"multer"},
+		},
+		FileProcessor: models.FileProcessorOptions{
+			ThumbnailSizes:   []string{"320x240", "1024x768"},
+			AllowedMIMETypes: []string{"image/*"},
+			MaxFileSize:      10485760,
+			PollInterval:     30,
+		},
+	}
+
+	content, err := gen.GenerateContent(detection, "upload-app")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	yaml := string(content)
+
+	for _, part := range []string{
+		"POLL_INTERVAL=30",
+		"MAX_FILE_SIZE=10485760",
+		"THUMBNAIL_SIZE=320x240",
+		"EXTRA_THUMBNAIL_SIZES=1024x768",
+		"ALLOWED_MIME_TYPES=image/*",
+	} {
+		if !strings.Contains(yaml, part) {
+			t.Errorf("YAML should contain %q, got:\n%s", part, yaml)
+		}
+	}
+}
+
+// TestComposeGenerator_FileProcessorSidecar_VirusScan tests the ClamAV scanning and image sanitization environment.
+func TestComposeGenerator_FileProcessorSidecar_VirusScan(t *testing.T) {
+	gen := NewComposeGenerator()
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"multer"},
+		},
+		FileProcessor: models.FileProcessorOptions{Scan: true, SanitizeImages: true},
+	}
+
+	content, err := gen.GenerateContent(detection, "upload-app")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	yaml := string(content)
+
+	for _, part := range []string{
+		"SANITIZE_IMAGES=true",
+		"SAFE_IMAGE_FORMATS=jpg jpeg png webp",
+		"SCAN_ENABLED=true",
+		"QUARANTINE_PATH=/uploads/quarantine",
+		"memory: 2G",
+	} {
+		if !strings.Contains(yaml, part) {
+			t.Errorf("YAML should contain %q, got:\n%s", part, yaml)
+		}
+	}
+}
+
+// TestComposeGenerator_FileProcessorSidecar_Notify tests webhook and queue notification settings.
+func TestComposeGenerator_FileProcessorSidecar_Notify(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   models.FileProcessorOptions
+		services  []string
+		wantParts []string
+		dontWant  []string
+	}{
+		{
+			name:      "webhook",
+			options:   models.FileProcessorOptions{Notify: "webhook"},
+			wantParts: []string{"NOTIFY_METHOD=webhook", "NOTIFY_URL=http://app:3000/webhooks/file-processed"},
+			dontWant:  []string{"NOTIFY_QUEUE=", "image: redis:"},
+		},
+		{
+			name:    "queue auto-adds redis",
+			options: models.FileProcessorOptions{Notify: "queue"},
+			wantParts: []string{
+				"NOTIFY_METHOD=queue",
+				"NOTIFY_QUEUE=file-processed",
+				"REDIS_URL=redis://redis:6379",
+				"image: redis:",
+				"      - app\n      - redis\n",
+			},
+			dontWant: []string{"NOTIFY_URL="},
+		},
+		{
+			name:      "queue uses detected redis",
+			options:   models.FileProcessorOptions{Notify: "queue", NotifyQueue: "uploads:done"},
+			services:  []string{"redis"},
+			wantParts: []string{"NOTIFY_QUEUE=uploads:done"},
+		},
+	}
+
+	gen := NewComposeGenerator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detection := &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Services: tt.services,
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: []string{"multer"},
+				},
+				FileProcessor: tt.options,
+			}
+
+			content, err := gen.GenerateContent(detection, "upload-app")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+
+			yaml := string(content)
+
+			for _, part := range tt.wantParts {
+				if !strings.Contains(yaml, part) {
+					t.Errorf("YAML should contain %q, got:\n%s", part, yaml)
+				}
+			}
+			for _, part := range tt.dontWant {
+				if strings.Contains(yaml, part) {
+					t.Errorf("YAML should NOT contain %q", part)
+				}
+			}
+			if strings.Count(yaml, "image: redis:") > 1 {
+				t.Error("redis should only be added once")
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestGRPCSidecar tests gRPC settings and grpcui generation in docker-compose.yml.
+func TestGRPCSidecar(t *testing.T) {
+	tests := []struct {
+		name        string
+		detection   *models.Detection
+		projectName string
+		wantParts   []string
+		dontWant    []string
+	}{
+		{
+			name: "go grpc server gets grpcui",
+			detection: &models.Detection{
+				Language: "go",
+				Version:  "1.23",
+				Capabilities: models.Capabilities{
+					GRPCLibraries: []string{"grpc-go"},
+				},
+			},
+			projectName: "svc",
+			wantParts: []string{
+				"GRPC_PORT=50051",
+				"GRPC_HOST=0.0.0.0",
+				"grpcui:",
+				"image: fullstorydev/grpcui:latest",
+				"command: -plaintext -bind 0.0.0.0 -port 8080 app:50051",
+				`"8082:8080"`,
+			},
+		},
+		{
+			name: "custom grpc port",
+			detection: &models.Detection{
+				Language: "rust",
+				Version:  "1.75",
+				Capabilities: models.Capabilities{
+					GRPCLibraries: []string{"tonic"},
+				},
+				GRPCPort: 9000,
+			},
+			projectName: "svc",
+			wantParts: []string{
+				"GRPC_PORT=9000",
+				"app:9000",
+			},
+		},
+		{
+			name: "no grpc",
+			detection: &models.Detection{
+				Language: "go",
+				Version:  "1.23",
+				Services: []string{"postgres"},
+			},
+			projectName: "api",
+			dontWant: []string{
+				"grpcui",
+				"GRPC_PORT",
+			},
+		},
+	}
+
+	gen := NewComposeGenerator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := gen.GenerateContent(tt.detection, tt.projectName)
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+
+			yamlContent := string(content)
+
+			for _, want := range tt.wantParts {
+				if !strings.Contains(yamlContent, want) {
+					t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, yamlContent)
+				}
+			}
+
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(yamlContent, dontWant) {
+					t.Errorf("docker-compose.yml should NOT contain %q", dontWant)
+				}
+			}
+
+			var parsed map[string]interface{}
+			if err := yaml.Unmarshal(content, &parsed); err != nil {
+				t.Errorf("Generated YAML is invalid: %v", err)
+			}
+		})
+	}
+}
+
+// TestGRPCSidecar_DevcontainerPorts tests that the gRPC and grpcui ports are forwarded.
+func TestGRPCSidecar_DevcontainerPorts(t *testing.T) {
+	gen := NewDevcontainerGenerator()
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Capabilities: models.Capabilities{
+			GRPCLibraries: []string{"grpcio"},
+		},
+	}
+
+	content, err := gen.GenerateContent(detection, "svc")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	var parsed struct {
+		DockerComposeFile string                       `json:"dockerComposeFile"`
+		ForwardPorts      []int                        `json:"forwardPorts"`
+		PortsAttributes   map[string]map[string]string `json:"portsAttributes"`
+	}
+	if err := json.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v\n%s", err, content)
+	}
+
+	if parsed.DockerComposeFile == "" {
+		t.Error("Expected docker-compose to be used when gRPC is detected")
+	}
+	for _, port := range []int{50051, 8082} {
+		if !containsPort(parsed.ForwardPorts, port) {
+			t.Errorf("Expected port %d in forwardPorts, got %v", port, parsed.ForwardPorts)
+		}
+	}
+
+	grpcAttrs := parsed.PortsAttributes["50051"]
+	if grpcAttrs["label"] != "gRPC (HTTP/2)" {
+		t.Errorf("gRPC port label = %q, want %q", grpcAttrs["label"], "gRPC (HTTP/2)")
+	}
+	if grpcAttrs["onAutoForward"] != "silent" {
+		t.Errorf("gRPC port onAutoForward = %q, want %q", grpcAttrs["onAutoForward"], "silent")
+	}
+}
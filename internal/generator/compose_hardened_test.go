@@ -0,0 +1,190 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestComposeGenerator_Hardened tests the --hardened profile of each service.
+func TestComposeGenerator_Hardened(t *testing.T) {
+	noNewPrivileges := "    security_opt:\n      - no-new-privileges:true\n"
+	logging := "    logging:\n      driver: json-file\n      options:\n        max-size: \"10m\"\n        max-file: \"3\"\n"
+
+	tests := []struct {
+		name      string
+		detection *models.Detection
+		wantParts []string
+		dontWant  []string
+	}{
+		{
+			name: "databases keep the capabilities their entrypoints need",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Services: []string{"postgres", "redis"},
+				Hardened: true,
+			},
+			wantParts: []string{
+				"      - \"5432:5432\"\n" + logging + "    read_only: true\n    tmpfs:\n      - /tmp\n      - /var/run/postgresql\n" + noNewPrivileges +
+					"    cap_drop:\n      - ALL\n    cap_add:\n      - CHOWN\n      - DAC_OVERRIDE\n      - FOWNER\n      - SETGID\n      - SETUID\n",
+				"      - \"6379:6379\"\n" + logging + "    read_only: true\n    tmpfs:\n      - /tmp\n" + noNewPrivileges +
+					"    cap_drop:\n      - ALL\n    cap_add:\n      - CHOWN\n      - SETGID\n      - SETUID\n",
+				"    # Not hardened: development container",
+				"    # Not hardened: mounts the Docker socket",
+			},
+		},
+		{
+			name: "root backups write to the host-owned directory",
+			detection: &models.Detection{
+				Language: "go",
+				Version:  "1.23",
+				Services: []string{"postgres"},
+				Hardened: true,
+			},
+			wantParts: []string{
+				"    restart: unless-stopped\n" + logging + "    read_only: true\n    tmpfs:\n      - /tmp\n" + noNewPrivileges +
+					"    cap_drop:\n      - ALL\n    cap_add:\n      - DAC_OVERRIDE\n",
+			},
+		},
+		{
+			name: "sidecars without special needs drop all capabilities",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					SchedulerLibraries: []string{"node-cron"},
+				},
+				Hardened: true,
+			},
+			wantParts: []string{
+				"      - app\n    restart: unless-stopped\n" + logging + "    read_only: true\n    tmpfs:\n      - /tmp\n" + noNewPrivileges + "    cap_drop:\n      - ALL\n",
+			},
+			dontWant: []string{"cap_add:"},
+		},
+		{
+			name: "scheduler command runs the app image",
+			detection: &models.Detection{
+				Language: "python",
+				Version:  "3.12",
+				Capabilities: models.Capabilities{
+					SchedulerLibraries: []string{"celery-beat"},
+				},
+				SchedulerCommand: "celery -A app beat",
+				Hardened:         true,
+			},
+			wantParts: []string{"    restart: unless-stopped\n" + logging + "    # Not hardened: runs the app's toolchain"},
+		},
+		{
+			name: "non-root file processor switches users",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: []string{"multer"},
+				},
+				NonRoot:  true,
+				Hardened: true,
+			},
+			wantParts: []string{
+				"    read_only: true\n    tmpfs:\n      - /tmp\n" + noNewPrivileges +
+					"    cap_drop:\n      - ALL\n    cap_add:\n      - CHOWN\n      - DAC_OVERRIDE\n      - FOWNER\n      - SETGID\n      - SETUID\n",
+			},
+		},
+		{
+			name: "off by default",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Services: []string{"postgres", "redis"},
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: []string{"multer"},
+				},
+			},
+			dontWant: []string{"read_only:", "security_opt:", "cap_drop:", "Not hardened"},
+		},
+	}
+
+	gen := NewComposeGenerator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := gen.GenerateContent(tt.detection, "myapp")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+
+			yamlContent := string(content)
+
+			for _, want := range tt.wantParts {
+				if !strings.Contains(yamlContent, want) {
+					t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, yamlContent)
+				}
+			}
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(yamlContent, dontWant) {
+					t.Errorf("docker-compose.yml should NOT contain %q", dontWant)
+				}
+			}
+
+			var parsed map[string]interface{}
+			if err := yaml.Unmarshal(content, &parsed); err != nil {
+				t.Errorf("Generated YAML is invalid: %v", err)
+			}
+		})
+	}
+}
+
+// TestComposeGenerator_HardenedCoversEveryService tests that every generated
+// service is either hardened or documented as an exception.
+func TestComposeGenerator_HardenedCoversEveryService(t *testing.T) {
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Services: []string{"postgres", "redis"},
+		Capabilities: models.Capabilities{
+			LoggingLibraries:    []string{"pino"},
+			QueueLibraries:      []string{"bullmq"},
+			FileUploadLibraries: []string{"multer"},
+			MetricsLibraries:    []string{"prom-client"},
+			TracingLibraries:    []string{"@opentelemetry/sdk-node"},
+			SchedulerLibraries:  []string{"node-cron"},
+			GRPCLibraries:       []string{"@grpc/grpc-js"},
+			AuthLibraries:       []string{"keycloak-connect"},
+			PaymentLibraries:    []string{"stripe"},
+			AWSServices:         []string{"sqs", "s3"},
+			VectorLibraries:     []string{"@qdrant/js-client-rest"},
+			LLMLibraries:        []string{"ollama"},
+		},
+		WorkerCommand: "npm run worker",
+		LocalLLM:      true,
+		Hardened:      true,
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "myapp")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	var parsed struct {
+		Services map[string]map[string]interface{} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("Generated YAML is invalid: %v", err)
+	}
+	if len(parsed.Services) < 10 {
+		t.Fatalf("expected most sidecars to be generated, got %d services", len(parsed.Services))
+	}
+
+	config := NewComposeGenerator().buildConfig(detection, "myapp")
+	for name, service := range parsed.Services {
+		if config.hardeningProfile(name).Exempt != "" {
+			continue
+		}
+		if _, ok := service["security_opt"]; !ok {
+			t.Errorf("service %s has no security_opt", name)
+		}
+	}
+}
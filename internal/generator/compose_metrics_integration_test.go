@@ -21,10 +21,12 @@ func TestComposeConfig_MetricsSidecar(t *testing.T) {
 		{
 			name: "metrics library enables metrics sidecar",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: []string{"prom-client"},
-				MetricsPort:      3000,
-				MetricsPath:      "/metrics",
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+				},
+				MetricsPort: 3000,
+				MetricsPath: "/metrics",
 			},
 			checkConfig: func(t *testing.T, config *ComposeConfig) {
 				if !config.MetricsSidecar.Enabled {
@@ -47,8 +49,10 @@ func TestComposeConfig_MetricsSidecar(t *testing.T) {
 		{
 			name: "no metrics library disables metrics sidecar",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: nil,
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: nil,
+				},
 			},
 			checkConfig: func(t *testing.T, config *ComposeConfig) {
 				if config.MetricsSidecar.Enabled {
@@ -59,9 +63,11 @@ func TestComposeConfig_MetricsSidecar(t *testing.T) {
 		{
 			name: "metrics with postgres enables postgres exporter",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: []string{"prom-client"},
-				Services:         []string{"postgres"},
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+				},
+				Services: []string{"postgres"},
 			},
 			checkConfig: func(t *testing.T, config *ComposeConfig) {
 				if !config.MetricsSidecar.Enabled {
@@ -75,9 +81,11 @@ func TestComposeConfig_MetricsSidecar(t *testing.T) {
 		{
 			name: "metrics with redis enables redis exporter",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: []string{"prom-client"},
-				Services:         []string{"redis"},
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+				},
+				Services: []string{"redis"},
 			},
 			checkConfig: func(t *testing.T, config *ComposeConfig) {
 				if !config.MetricsSidecar.Enabled {
@@ -91,9 +99,11 @@ func TestComposeConfig_MetricsSidecar(t *testing.T) {
 		{
 			name: "metrics with worker enables worker in config",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: []string{"prom-client"},
-				QueueLibraries:   []string{"bull"},
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+					QueueLibraries:   []string{"bull"},
+				},
 			},
 			checkConfig: func(t *testing.T, config *ComposeConfig) {
 				if !config.MetricsSidecar.Enabled {
@@ -107,10 +117,12 @@ func TestComposeConfig_MetricsSidecar(t *testing.T) {
 		{
 			name: "go project with prometheus client",
 			detection: &models.Detection{
-				Language:         "go",
-				MetricsLibraries: []string{"prometheus/client_golang"},
-				MetricsPort:      8080,
-				MetricsPath:      "/metrics",
+				Language: "go",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prometheus/client_golang"},
+				},
+				MetricsPort: 8080,
+				MetricsPath: "/metrics",
 			},
 			checkConfig: func(t *testing.T, config *ComposeConfig) {
 				if !config.MetricsSidecar.Enabled {
@@ -143,10 +155,12 @@ func TestComposeGenerator_MetricsServices(t *testing.T) {
 		{
 			name: "metrics enabled generates prometheus and grafana",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: []string{"prom-client"},
-				MetricsPort:      3000,
-				MetricsPath:      "/metrics",
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+				},
+				MetricsPort: 3000,
+				MetricsPath: "/metrics",
 			},
 			expectedParts: []string{
 				"prometheus:",
@@ -155,7 +169,7 @@ func TestComposeGenerator_MetricsServices(t *testing.T) {
 				"image: grafana/grafana:latest",
 				"prometheus-data:",
 				"grafana-data:",
-				"GF_SECURITY_ADMIN_PASSWORD=admin",
+				"GF_SECURITY_ADMIN_PASSWORD=${GRAFANA_ADMIN_PASSWORD}",
 				"prometheus.scrape=true",
 				"prometheus.port=3000",
 				"prometheus.path=/metrics",
@@ -168,23 +182,27 @@ func TestComposeGenerator_MetricsServices(t *testing.T) {
 		{
 			name: "metrics with postgres includes postgres exporter",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: []string{"prom-client"},
-				Services:         []string{"postgres"},
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+				},
+				Services: []string{"postgres"},
 			},
 			expectedParts: []string{
 				"prometheus:",
 				"grafana:",
 				"postgres-exporter:",
-				"quay.io/prometheuscommunity/postgres-exporter:latest",
+				"quay.io/prometheuscommunity/postgres-exporter:v0.15.0",
 			},
 		},
 		{
 			name: "metrics with redis includes redis exporter",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: []string{"prom-client"},
-				Services:         []string{"redis"},
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+				},
+				Services: []string{"redis"},
 			},
 			expectedParts: []string{
 				"prometheus:",
@@ -196,9 +214,11 @@ func TestComposeGenerator_MetricsServices(t *testing.T) {
 		{
 			name: "metrics with worker includes worker dependency",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: []string{"prom-client"},
-				QueueLibraries:   []string{"bull"},
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+					QueueLibraries:   []string{"bull"},
+				},
 			},
 			expectedParts: []string{
 				"prometheus:",
@@ -251,13 +271,15 @@ func TestEndToEndMetricsGeneration(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	detection := &models.Detection{
-		Language:         "nodejs",
-		Version:          "20",
-		MetricsLibraries: []string{"prom-client"},
-		MetricsPort:      3000,
-		MetricsPath:      "/metrics",
-		Services:         []string{"postgres", "redis"},
-		QueueLibraries:   []string{"bull"},
+		Language: "nodejs",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			MetricsLibraries: []string{"prom-client"},
+			QueueLibraries:   []string{"bull"},
+		},
+		MetricsPort: 3000,
+		MetricsPath: "/metrics",
+		Services:    []string{"postgres", "redis"},
 	}
 
 	projectName := "e2e-test"
@@ -347,9 +369,11 @@ func TestDevcontainerGenerator_MetricsPorts(t *testing.T) {
 		{
 			name: "metrics adds prometheus and grafana ports",
 			detection: &models.Detection{
-				Language:         "node",
-				Version:          "20",
-				MetricsLibraries: []string{"prom-client"},
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+				},
 			},
 			expectPorts: []int{3000, 9090, 3001}, // app + prometheus + grafana
 		},
@@ -364,10 +388,12 @@ func TestDevcontainerGenerator_MetricsPorts(t *testing.T) {
 		{
 			name: "metrics with postgres adds exporter port",
 			detection: &models.Detection{
-				Language:         "node",
-				Version:          "20",
-				MetricsLibraries: []string{"prom-client"},
-				Services:         []string{"postgres"},
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+				},
+				Services: []string{"postgres"},
 			},
 			expectPorts: []int{3000, 5432, 9090, 3001}, // app + postgres + prometheus + grafana
 		},
@@ -404,9 +430,11 @@ func TestDevcontainerGenerator_UseComposeWithMetrics(t *testing.T) {
 		{
 			name: "metrics enables compose",
 			detection: &models.Detection{
-				Language:         "node",
-				Version:          "20",
-				MetricsLibraries: []string{"prom-client"},
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+				},
 			},
 			expectUseCompose: true,
 		},
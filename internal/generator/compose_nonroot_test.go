@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestComposeGenerator_NonRoot tests that services run as the host UID/GID.
+func TestComposeGenerator_NonRoot(t *testing.T) {
+	buildArgs := "      args:\n        USER_UID: ${USER_UID:-1000}\n        USER_GID: ${USER_GID:-1000}\n"
+
+	tests := []struct {
+		name      string
+		detection *models.Detection
+		wantParts []string
+		dontWant  []string
+	}{
+		{
+			name: "app, worker and sidecars",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Services: []string{"postgres", "redis"},
+				Capabilities: models.Capabilities{
+					QueueLibraries:      []string{"bullmq"},
+					SchedulerLibraries:  []string{"node-cron"},
+					FileUploadLibraries: []string{"multer"},
+				},
+				WorkerCommand: "npm run worker",
+				NonRoot:       true,
+			},
+			wantParts: []string{
+				"      dockerfile: .devcontainer/Dockerfile\n" + buildArgs + "    user: node\n",
+				"      dockerfile: Dockerfile.scheduler\n" + buildArgs,
+				"      dockerfile: Dockerfile.processor\n" + buildArgs,
+				"      dockerfile: Dockerfile.backup\n" + buildArgs,
+			},
+		},
+		{
+			name: "scheduler command runs as the app user",
+			detection: &models.Detection{
+				Language: "python",
+				Version:  "3.12",
+				Capabilities: models.Capabilities{
+					SchedulerLibraries: []string{"celery-beat"},
+				},
+				SchedulerCommand: "celery -A app beat",
+				NonRoot:          true,
+			},
+			wantParts: []string{
+				"  scheduler:\n    build:\n      context: ..\n      dockerfile: .devcontainer/Dockerfile\n" + buildArgs + "    user: vscode\n",
+			},
+		},
+		{
+			name: "reused Dockerfile keeps its own user",
+			detection: &models.Detection{
+				Language:           "go",
+				Version:            "1.23",
+				Services:           []string{"postgres"},
+				ExistingDockerfile: &models.ExistingDockerfile{File: "Dockerfile", Target: "dev"},
+				NonRoot:            true,
+			},
+			wantParts: []string{"      dockerfile: Dockerfile.backup\n" + buildArgs},
+			dontWant:  []string{"    user:"},
+		},
+		{
+			name: "root by default",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Services: []string{"postgres", "redis"},
+				Capabilities: models.Capabilities{
+					QueueLibraries: []string{"bullmq"},
+				},
+				WorkerCommand: "npm run worker",
+			},
+			dontWant: []string{"USER_UID", "    user:"},
+		},
+	}
+
+	gen := NewComposeGenerator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := gen.GenerateContent(tt.detection, "myapp")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+
+			yamlContent := string(content)
+
+			for _, want := range tt.wantParts {
+				if !strings.Contains(yamlContent, want) {
+					t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, yamlContent)
+				}
+			}
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(yamlContent, dontWant) {
+					t.Errorf("docker-compose.yml should NOT contain %q", dontWant)
+				}
+			}
+
+			var parsed map[string]interface{}
+			if err := yaml.Unmarshal(content, &parsed); err != nil {
+				t.Errorf("Generated YAML is invalid: %v", err)
+			}
+		})
+	}
+}
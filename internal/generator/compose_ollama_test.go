@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestOllamaSidecar tests local LLM sidecar generation in docker-compose.yml.
+func TestOllamaSidecar(t *testing.T) {
+	tests := []struct {
+		name        string
+		detection   *models.Detection
+		projectName string
+		wantParts   []string
+		dontWant    []string
+	}{
+		{
+			name: "local llm enabled",
+			detection: &models.Detection{
+				Language: "python",
+				Version:  "3.12",
+				Capabilities: models.Capabilities{
+					LLMLibraries: []string{"openai"},
+				},
+				LocalLLM: true,
+			},
+			projectName: "chat",
+			wantParts: []string{
+				"ollama:",
+				"image: ollama/ollama:latest",
+				`"11434:11434"`,
+				"ollama-models:/root/.ollama",
+				"ollama-pull:",
+				`entrypoint: ["ollama", "pull", "llama3.2"]`,
+				"condition: service_healthy",
+				"OLLAMA_HOST=http://ollama:11434",
+				"OPENAI_BASE_URL=http://ollama:11434/v1",
+				"LLM_MODEL=llama3.2",
+			},
+		},
+		{
+			name: "hosted llm clients without opt-in",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					LLMLibraries: []string{"openai", "anthropic"},
+				},
+				Services: []string{"redis"},
+			},
+			projectName: "chat",
+			dontWant: []string{
+				"ollama",
+				"OPENAI_BASE_URL",
+			},
+		},
+	}
+
+	gen := NewComposeGenerator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := gen.GenerateContent(tt.detection, tt.projectName)
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+
+			yamlContent := string(content)
+
+			for _, want := range tt.wantParts {
+				if !strings.Contains(yamlContent, want) {
+					t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, yamlContent)
+				}
+			}
+
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(yamlContent, dontWant) {
+					t.Errorf("docker-compose.yml should NOT contain %q", dontWant)
+				}
+			}
+
+			var parsed map[string]interface{}
+			if err := yaml.Unmarshal(content, &parsed); err != nil {
+				t.Errorf("Generated YAML is invalid: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package generator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/detector"
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestQueueDashboard tests queue management UI generation in docker-compose.yml.
+func TestQueueDashboard(t *testing.T) {
+	tests := []struct {
+		name      string
+		detection *models.Detection
+		wantParts []string
+		dontWant  []string
+	}{
+		{
+			name: "bullmq gets bull board",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					QueueLibraries: []string{"bullmq"},
+				},
+				WorkerCommand: "npm run worker",
+			},
+			wantParts: []string{
+				"bull-board:",
+				"image: deadly0/bull-board:latest",
+				`"3002:3000"`,
+				"REDIS_HOST=redis",
+				"REDIS_PORT=6379",
+				"BULL_VERSION=BULLMQ",
+			},
+			dontWant: []string{"flower:", "asynqmon:"},
+		},
+		{
+			name: "bull uses the legacy queue api",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					QueueLibraries: []string{"bull"},
+				},
+				WorkerCommand: "npm run worker",
+			},
+			wantParts: []string{"bull-board:", "BULL_VERSION=BULL\n"},
+		},
+		{
+			name: "celery gets flower on the redis broker",
+			detection: &models.Detection{
+				Language: "python",
+				Version:  "3.12",
+				Services: []string{"redis"},
+				Capabilities: models.Capabilities{
+					QueueLibraries: []string{"celery"},
+				},
+				WorkerCommand: "celery -A app worker",
+			},
+			wantParts: []string{
+				"flower:",
+				"image: mher/flower:2.0",
+				`"5555:5555"`,
+				"CELERY_BROKER_URL=redis://redis:6379/0",
+			},
+			dontWant: []string{"bull-board:", "asynqmon:"},
+		},
+		{
+			name: "celery without redis reads the broker from the host",
+			detection: &models.Detection{
+				Language: "python",
+				Version:  "3.12",
+				Capabilities: models.Capabilities{
+					QueueLibraries: []string{"celery"},
+				},
+				WorkerCommand: "celery -A app worker",
+			},
+			wantParts: []string{
+				"flower:",
+				"CELERY_BROKER_URL=${CELERY_BROKER_URL:-}",
+			},
+			dontWant: []string{"image: redis:"},
+		},
+		{
+			name: "asynq gets asynqmon",
+			detection: &models.Detection{
+				Language: "go",
+				Version:  "1.23",
+				Capabilities: models.Capabilities{
+					QueueLibraries: []string{"asynq"},
+				},
+				WorkerCommand: "go run ./cmd/worker",
+			},
+			wantParts: []string{
+				"asynqmon:",
+				"image: hibiken/asynqmon:latest",
+				`"8081:8080"`,
+				"REDIS_ADDR=redis:6379",
+			},
+			dontWant: []string{"bull-board:", "flower:"},
+		},
+		{
+			name: "queue library without a dashboard",
+			detection: &models.Detection{
+				Language: "python",
+				Version:  "3.12",
+				Capabilities: models.Capabilities{
+					QueueLibraries: []string{"dramatiq"},
+				},
+				WorkerCommand: "dramatiq app",
+			},
+			dontWant: []string{"bull-board:", "flower:", "asynqmon:"},
+		},
+	}
+
+	gen := NewComposeGenerator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := gen.GenerateContent(tt.detection, "jobs")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+
+			yamlContent := string(content)
+
+			for _, want := range tt.wantParts {
+				if !strings.Contains(yamlContent, want) {
+					t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, yamlContent)
+				}
+			}
+
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(yamlContent, dontWant) {
+					t.Errorf("docker-compose.yml should NOT contain %q", dontWant)
+				}
+			}
+
+			var parsed map[string]interface{}
+			if err := yaml.Unmarshal(content, &parsed); err != nil {
+				t.Errorf("Generated YAML is invalid: %v", err)
+			}
+		})
+	}
+}
+
+// TestQueueDashboard_ImportedRedis tests that the dashboard reads from an imported Redis service.
+func TestQueueDashboard_ImportedRedis(t *testing.T) {
+	detection := &models.Detection{
+		Language: "go",
+		Version:  "1.23",
+		Services: []string{"redis"},
+		Capabilities: models.Capabilities{
+			QueueLibraries: []string{"asynq"},
+		},
+		WorkerCommand: "go run ./cmd/worker",
+		ExistingCompose: &models.ExistingCompose{
+			File: "docker-compose.yml",
+			Services: []models.ExistingService{
+				{Name: "cache", Role: "redis", Definition: map[string]interface{}{"image": "redis:7-alpine"}},
+			},
+		},
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "jobs")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	if !strings.Contains(string(content), "REDIS_ADDR=cache:6379") {
+		t.Errorf("asynqmon should use the imported Redis service, got:\n%s", content)
+	}
+}
+
+// TestQueueDashboard_DevcontainerPorts tests that the dashboard port is forwarded.
+func TestQueueDashboard_DevcontainerPorts(t *testing.T) {
+	gen := NewDevcontainerGenerator()
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Capabilities: models.Capabilities{
+			QueueLibraries: []string{"celery"},
+		},
+	}
+
+	content, err := gen.GenerateContent(detection, "jobs")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	var parsed struct {
+		ForwardPorts    []int                        `json:"forwardPorts"`
+		PortsAttributes map[string]map[string]string `json:"portsAttributes"`
+	}
+	if err := json.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v\n%s", err, content)
+	}
+
+	if !containsPort(parsed.ForwardPorts, 5555) {
+		t.Errorf("Expected port 5555 in forwardPorts, got %v", parsed.ForwardPorts)
+	}
+	if label := parsed.PortsAttributes["5555"]["label"]; label != "Flower" {
+		t.Errorf("Flower port label = %q, want %q", label, "Flower")
+	}
+}
+
+// TestQueueDashboard_FrontendPort tests that Bull Board moves off the port a
+// Next.js frontend next to an Express backend takes (3000 is the app's, 3001
+// Grafana's, so both default to 3002).
+func TestQueueDashboard_FrontendPort(t *testing.T) {
+	tmpDir := t.TempDir()
+	pkg := `{"name": "jobs", "scripts": {"worker": "node worker.js"}, "dependencies": {"express": "^4.18.0", "next": "^14.0.0", "bullmq": "^5.0.0"}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "package.json"), []byte(pkg), 0644); err != nil {
+		t.Fatal(err)
+	}
+	detection, err := detector.NewNodeDetector().Detect(tmpDir)
+	if err != nil || detection == nil {
+		t.Fatalf("Detect() = %v, %v", detection, err)
+	}
+	if detection.FrontendPort != 3002 || detection.GetQueueDashboardPort() != 3003 {
+		t.Fatalf("frontend port = %d, dashboard port = %d, want 3002 and 3003", detection.FrontendPort, detection.GetQueueDashboardPort())
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "jobs")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	var compose struct {
+		Services map[string]struct {
+			Ports []string `yaml:"ports"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(content, &compose); err != nil {
+		t.Fatalf("Generated YAML is invalid: %v", err)
+	}
+	hostPorts := map[string]string{}
+	for name, service := range compose.Services {
+		for _, mapping := range service.Ports {
+			host := strings.Split(mapping, ":")[0]
+			if other, ok := hostPorts[host]; ok {
+				t.Errorf("%s and %s both bind host port %s", other, name, host)
+			}
+			hostPorts[host] = name
+		}
+	}
+	if hostPorts["3003"] != "bull-board" {
+		t.Errorf("bull-board should bind host port 3003, got ports %v", hostPorts)
+	}
+
+	content, err = NewDevcontainerGenerator().GenerateContent(detection, "jobs")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	var devcontainer struct {
+		ForwardPorts []int `json:"forwardPorts"`
+	}
+	if err := json.Unmarshal(content, &devcontainer); err != nil {
+		t.Fatalf("Generated JSON is invalid: %v\n%s", err, content)
+	}
+	seen := map[int]bool{}
+	for _, port := range devcontainer.ForwardPorts {
+		if seen[port] {
+			t.Errorf("forwardPorts lists %d twice: %v", port, devcontainer.ForwardPorts)
+		}
+		seen[port] = true
+	}
+	if strings.Count(string(content), `"3002": {`) != 1 || strings.Count(string(content), `"3003": {`) != 1 {
+		t.Errorf("portsAttributes should label 3002 and 3003 once each, got:\n%s", content)
+	}
+}
@@ -0,0 +1,175 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestSchedulerSidecar tests scheduler generation in docker-compose.yml.
+func TestSchedulerSidecar(t *testing.T) {
+	tests := []struct {
+		name        string
+		detection   *models.Detection
+		projectName string
+		wantParts   []string
+		dontWant    []string
+	}{
+		{
+			name: "celery beat runs as a dedicated process",
+			detection: &models.Detection{
+				Language: "python",
+				Version:  "3.12",
+				Capabilities: models.Capabilities{
+					SchedulerLibraries: []string{"celery-beat"},
+				},
+				SchedulerCommand: "celery -A shop beat",
+			},
+			projectName: "shop",
+			wantParts: []string{
+				"# Scheduler process",
+				"scheduler:",
+				"command: celery -A shop beat",
+				"dockerfile: .devcontainer/Dockerfile",
+				"REDIS_URL=redis://redis:6379",
+				"redis:",
+			},
+			dontWant: []string{
+				"Dockerfile.scheduler",
+			},
+		},
+		{
+			name: "in-process scheduler uses supercronic sidecar",
+			detection: &models.Detection{
+				Language: "go",
+				Version:  "1.23",
+				Capabilities: models.Capabilities{
+					SchedulerLibraries: []string{"robfig/cron"},
+				},
+			},
+			projectName: "jobs",
+			wantParts: []string{
+				"# Scheduler sidecar (Supercronic)",
+				"scheduler:",
+				"dockerfile: Dockerfile.scheduler",
+				"APP_URL=http://app:8080",
+			},
+			dontWant: []string{
+				"command: celery",
+			},
+		},
+		{
+			name: "no scheduler libraries",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+			},
+			projectName: "api",
+			dontWant: []string{
+				"scheduler:",
+			},
+		},
+	}
+
+	gen := NewComposeGenerator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := gen.GenerateContent(tt.detection, tt.projectName)
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+
+			yamlContent := string(content)
+
+			for _, want := range tt.wantParts {
+				if !strings.Contains(yamlContent, want) {
+					t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, yamlContent)
+				}
+			}
+
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(yamlContent, dontWant) {
+					t.Errorf("docker-compose.yml should NOT contain %q", dontWant)
+				}
+			}
+
+			var parsed map[string]interface{}
+			if err := yaml.Unmarshal(content, &parsed); err != nil {
+				t.Errorf("Generated YAML is invalid: %v", err)
+			}
+		})
+	}
+}
+
+// TestSchedulerSidecarGenerator_Generate tests supercronic sidecar file generation.
+func TestSchedulerSidecarGenerator_Generate(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "dockstart-scheduler-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	gen := NewSchedulerSidecarGenerator()
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			SchedulerLibraries: []string{"node-cron"},
+		},
+	}
+
+	if !gen.ShouldGenerate(detection) {
+		t.Fatal("ShouldGenerate() = false, want true for in-process scheduler")
+	}
+
+	if err := gen.Generate(detection, tmpDir, "api"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	dockerfile, err := os.ReadFile(filepath.Join(tmpDir, ".devcontainer", "Dockerfile.scheduler"))
+	if err != nil {
+		t.Fatalf("Failed to read Dockerfile.scheduler: %v", err)
+	}
+	for _, want := range []string{"supercronic", "COPY crontab.scheduler /etc/crontab", "ENV APP_URL=http://app:3000"} {
+		if !strings.Contains(string(dockerfile), want) {
+			t.Errorf("Dockerfile.scheduler should contain %q", want)
+		}
+	}
+
+	crontab, err := os.ReadFile(filepath.Join(tmpDir, ".devcontainer", "crontab.scheduler"))
+	if err != nil {
+		t.Fatalf("Failed to read crontab.scheduler: %v", err)
+	}
+	for _, want := range []string{"Detected scheduler libraries: node-cron", `curl -fsS -X POST "${APP_URL}/jobs/cleanup"`, "@hourly touch /tmp/scheduler-alive"} {
+		if !strings.Contains(string(crontab), want) {
+			t.Errorf("crontab.scheduler should contain %q", want)
+		}
+	}
+
+	if strings.Contains(string(dockerfile), "USER ") {
+		t.Error("Dockerfile.scheduler should run as root by default")
+	}
+
+	// Non-root runs supercronic as the host UID/GID
+	detection.NonRoot = true
+	dockerfile, err = gen.GenerateDockerfile(gen.buildConfig(detection, "api"))
+	if err != nil {
+		t.Fatalf("GenerateDockerfile() error = %v", err)
+	}
+	for _, want := range []string{`adduser -D -u "$USER_UID" -G "$group" dockstart`, "USER dockstart\n"} {
+		if !strings.Contains(string(dockerfile), want) {
+			t.Errorf("non-root Dockerfile.scheduler should contain %q", want)
+		}
+	}
+
+	// A dedicated scheduler process doesn't need the supercronic sidecar
+	detection.SchedulerCommand = "npm run scheduler"
+	if gen.ShouldGenerate(detection) {
+		t.Error("ShouldGenerate() = true, want false when a scheduler command is set")
+	}
+}
@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestComposeGenerator_Search tests the search engine service and the
+// variables injected into the app and the worker.
+func TestComposeGenerator_Search(t *testing.T) {
+	tests := []struct {
+		name      string
+		libraries []string
+		wantParts []string
+		wantEnv   map[string]string
+	}{
+		{
+			name:      "meilisearch",
+			libraries: []string{"meilisearch"},
+			wantParts: []string{
+				"  meilisearch:\n    image: getmeili/meilisearch:v1.11\n",
+				`- "7700:7700"`,
+				"- MEILI_MASTER_KEY=${MEILI_MASTER_KEY:-dockstart-development-master-key}",
+				"meilisearch-data:/meili_data",
+				`test: ["CMD", "curl", "-fs", "http://localhost:7700/health"]`,
+			},
+			wantEnv: map[string]string{
+				"MEILI_URL":        "http://meilisearch:7700",
+				"MEILI_MASTER_KEY": "${MEILI_MASTER_KEY:-dockstart-development-master-key}",
+			},
+		},
+		{
+			name:      "typesense",
+			libraries: []string{"typesense"},
+			wantParts: []string{
+				"  typesense:\n    image: typesense/typesense:27.1\n",
+				`"--api-key", "${TYPESENSE_API_KEY:-dockstart-development-api-key}"`,
+				"typesense-data:/data",
+				"exec 3<>/dev/tcp/127.0.0.1/8108",
+			},
+			wantEnv: map[string]string{
+				"TYPESENSE_URL":     "http://typesense:8108",
+				"TYPESENSE_API_KEY": "${TYPESENSE_API_KEY:-dockstart-development-api-key}",
+			},
+		},
+		{
+			name:      "meilisearch wins when both are used",
+			libraries: []string{"typesense", "meilisearch"},
+			wantParts: []string{"  meilisearch:\n"},
+			wantEnv:   map[string]string{"MEILI_URL": "http://meilisearch:7700", "TYPESENSE_URL": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detection := &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					QueueLibraries:  []string{"bullmq"},
+					SearchLibraries: tt.libraries,
+				},
+			}
+			content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+			var parsed map[string]interface{}
+			if err := yaml.Unmarshal(content, &parsed); err != nil {
+				t.Fatalf("docker-compose.yml is not valid YAML: %v", err)
+			}
+			compose := string(content)
+			for _, want := range tt.wantParts {
+				if !strings.Contains(compose, want) {
+					t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+				}
+			}
+			for _, service := range []string{"app", "worker"} {
+				env, _ := composeEnv(t, content, service)
+				for name, want := range tt.wantEnv {
+					if env[name] != want {
+						t.Errorf("%s %s = %q, want %q", service, name, env[name], want)
+					}
+				}
+			}
+		})
+	}
+
+	// Without a search client, no engine runs
+	content, err := NewComposeGenerator().GenerateContent(&models.Detection{Language: "go", Version: "1.23", Services: []string{"redis"}}, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if strings.Contains(string(content), "meilisearch") || strings.Contains(string(content), "typesense") {
+		t.Errorf("docker-compose.yml without a search client should not run a search engine, got:\n%s", content)
+	}
+}
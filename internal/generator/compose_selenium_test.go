@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestSeleniumGrid tests the Selenium Grid hub and browser nodes in docker-compose.yml.
+func TestSeleniumGrid(t *testing.T) {
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Capabilities: models.Capabilities{
+			WebDriverLibraries: []string{"selenium"},
+		},
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+
+	for _, want := range []string{
+		"  selenium-hub:\n    image: selenium/hub:4.25\n",
+		`"4444:4444"`,
+		"/opt/bin/check-grid.sh",
+		"  chrome:\n    image: selenium/node-chrome:4.25\n",
+		"  firefox:\n    image: selenium/node-firefox:4.25\n",
+		`"7900:7900"`,
+		`"7901:7900"`,
+		"shm_size: 2gb",
+		"SE_EVENT_BUS_HOST=selenium-hub",
+		"SE_EVENT_BUS_PUBLISH_PORT=4442",
+		"SE_EVENT_BUS_SUBSCRIBE_PORT=4443",
+		"SELENIUM_REMOTE_URL=http://selenium-hub:4444",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+		}
+	}
+
+	var parsed struct {
+		Services map[string]interface{} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("Generated YAML is invalid: %v", err)
+	}
+	for _, service := range []string{"app", "selenium-hub", "chrome", "firefox"} {
+		if _, ok := parsed.Services[service]; !ok {
+			t.Errorf("services should include %q", service)
+		}
+	}
+
+	// Without a WebDriver client there is no grid
+	content, err = NewComposeGenerator().GenerateContent(&models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Services: []string{"postgres"},
+	}, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if strings.Contains(string(content), "selenium") {
+		t.Errorf("docker-compose.yml should not include the Selenium Grid, got:\n%s", content)
+	}
+}
@@ -23,11 +23,13 @@ func TestIntegration_DetectionToCompose(t *testing.T) {
 		{
 			name: "node with pino generates sidecar",
 			detection: &models.Detection{
-				Language:         "node",
-				Version:          "20",
-				Services:         []string{},
-				LoggingLibraries: []string{"pino"},
-				LogFormat:        "json",
+				Language: "node",
+				Version:  "20",
+				Services: []string{},
+				Capabilities: models.Capabilities{
+					LoggingLibraries: []string{"pino"},
+				},
+				LogFormat: "json",
 			},
 			projectName: "node-pino-app",
 			wantParts: []string{
@@ -46,11 +48,13 @@ func TestIntegration_DetectionToCompose(t *testing.T) {
 		{
 			name: "go with zap and postgres",
 			detection: &models.Detection{
-				Language:         "go",
-				Version:          "1.21",
-				Services:         []string{"postgres"},
-				LoggingLibraries: []string{"zap"},
-				LogFormat:        "json",
+				Language: "go",
+				Version:  "1.21",
+				Services: []string{"postgres"},
+				Capabilities: models.Capabilities{
+					LoggingLibraries: []string{"zap"},
+				},
+				LogFormat: "json",
 			},
 			projectName: "go-zap-app",
 			wantParts: []string{
@@ -68,11 +72,13 @@ func TestIntegration_DetectionToCompose(t *testing.T) {
 		{
 			name: "python with structlog and redis",
 			detection: &models.Detection{
-				Language:         "python",
-				Version:          "3.11",
-				Services:         []string{"redis"},
-				LoggingLibraries: []string{"structlog"},
-				LogFormat:        "json",
+				Language: "python",
+				Version:  "3.11",
+				Services: []string{"redis"},
+				Capabilities: models.Capabilities{
+					LoggingLibraries: []string{"structlog"},
+				},
+				LogFormat: "json",
 			},
 			projectName: "python-app",
 			wantParts: []string{
@@ -90,11 +96,13 @@ func TestIntegration_DetectionToCompose(t *testing.T) {
 		{
 			name: "rust with tracing, postgres, and redis",
 			detection: &models.Detection{
-				Language:         "rust",
-				Version:          "1.75",
-				Services:         []string{"postgres", "redis"},
-				LoggingLibraries: []string{"tracing"},
-				LogFormat:        "json",
+				Language: "rust",
+				Version:  "1.75",
+				Services: []string{"postgres", "redis"},
+				Capabilities: models.Capabilities{
+					LoggingLibraries: []string{"tracing"},
+				},
+				LogFormat: "json",
 			},
 			projectName: "rust-full-app",
 			wantParts: []string{
@@ -116,11 +124,13 @@ func TestIntegration_DetectionToCompose(t *testing.T) {
 		{
 			name: "no logging libraries - no sidecar",
 			detection: &models.Detection{
-				Language:         "node",
-				Version:          "20",
-				Services:         []string{"postgres"},
-				LoggingLibraries: []string{},
-				LogFormat:        "unknown",
+				Language: "node",
+				Version:  "20",
+				Services: []string{"postgres"},
+				Capabilities: models.Capabilities{
+					LoggingLibraries: []string{},
+				},
+				LogFormat: "unknown",
 			},
 			projectName: "node-no-logs",
 			wantParts: []string{
@@ -193,20 +203,24 @@ func TestIntegration_GeneratedYAMLIsValid(t *testing.T) {
 		{
 			name: "with sidecar",
 			detection: &models.Detection{
-				Language:         "node",
-				Version:          "20",
-				LoggingLibraries: []string{"pino"},
-				LogFormat:        "json",
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					LoggingLibraries: []string{"pino"},
+				},
+				LogFormat: "json",
 			},
 		},
 		{
 			name: "full config",
 			detection: &models.Detection{
-				Language:         "rust",
-				Version:          "1.75",
-				Services:         []string{"postgres", "redis"},
-				LoggingLibraries: []string{"tracing"},
-				LogFormat:        "json",
+				Language: "rust",
+				Version:  "1.75",
+				Services: []string{"postgres", "redis"},
+				Capabilities: models.Capabilities{
+					LoggingLibraries: []string{"tracing"},
+				},
+				LogFormat: "json",
 			},
 		},
 	}
@@ -245,10 +259,12 @@ func TestIntegration_DevcontainerWithSidecar(t *testing.T) {
 		{
 			name: "with logging - uses compose and forwards 24224",
 			detection: &models.Detection{
-				Language:         "node",
-				Version:          "20",
-				LoggingLibraries: []string{"winston"},
-				LogFormat:        "text",
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					LoggingLibraries: []string{"winston"},
+				},
+				LogFormat: "text",
 			},
 			wantPorts:   []int{3000, 24224},
 			wantCompose: true,
@@ -325,10 +341,12 @@ func TestIntegration_FluentBitConfigValid(t *testing.T) {
 		{
 			name: "json format includes parser",
 			detection: &models.Detection{
-				Language:         "node",
-				Version:          "20",
-				LoggingLibraries: []string{"pino"},
-				LogFormat:        "json",
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					LoggingLibraries: []string{"pino"},
+				},
+				LogFormat: "json",
 			},
 			wantParts: []string{
 				"[SERVICE]",
@@ -341,10 +359,12 @@ func TestIntegration_FluentBitConfigValid(t *testing.T) {
 		{
 			name: "text format no json parser",
 			detection: &models.Detection{
-				Language:         "go",
-				Version:          "1.21",
-				LoggingLibraries: []string{"logrus"},
-				LogFormat:        "text",
+				Language: "go",
+				Version:  "1.21",
+				Capabilities: models.Capabilities{
+					LoggingLibraries: []string{"logrus"},
+				},
+				LogFormat: "text",
 			},
 			wantParts: []string{
 				"[SERVICE]",
@@ -395,11 +415,13 @@ func TestIntegration_FullGeneration(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	detection := &models.Detection{
-		Language:         "node",
-		Version:          "20",
-		Services:         []string{"postgres"},
-		LoggingLibraries: []string{"pino"},
-		LogFormat:        "json",
+		Language: "node",
+		Version:  "20",
+		Services: []string{"postgres"},
+		Capabilities: models.Capabilities{
+			LoggingLibraries: []string{"pino"},
+		},
+		LogFormat: "json",
 	}
 
 	// Generate all files
@@ -486,11 +508,13 @@ func TestVolumeConfiguration(t *testing.T) {
 		{
 			name: "all services with sidecar",
 			detection: &models.Detection{
-				Language:         "python",
-				Version:          "3.11",
-				Services:         []string{"postgres", "redis"},
-				LoggingLibraries: []string{"structlog"},
-				LogFormat:        "json",
+				Language: "python",
+				Version:  "3.11",
+				Services: []string{"postgres", "redis"},
+				Capabilities: models.Capabilities{
+					LoggingLibraries: []string{"structlog"},
+				},
+				LogFormat: "json",
 			},
 			wantVolumes: []string{"postgres-data:", "redis-data:", "fluent-bit-logs:"},
 		},
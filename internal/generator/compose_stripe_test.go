@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestStripeSidecar tests stripe-cli webhook forwarding in docker-compose.yml.
+func TestStripeSidecar(t *testing.T) {
+	tests := []struct {
+		name        string
+		detection   *models.Detection
+		projectName string
+		wantParts   []string
+		dontWant    []string
+	}{
+		{
+			name: "node stripe forwards to app port",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					PaymentLibraries: []string{"stripe"},
+				},
+			},
+			projectName: "shop",
+			wantParts: []string{
+				"stripe-cli:",
+				"image: stripe/stripe-cli:latest",
+				"--forward-to app:3000/webhooks",
+				"--print-secret > /run/stripe/webhook-secret",
+				"STRIPE_SECRET_KEY=${STRIPE_SECRET_KEY:-}",
+				"STRIPE_WEBHOOK_SECRET_FILE=/run/stripe/webhook-secret",
+				"stripe-cli:/run/stripe:ro",
+			},
+		},
+		{
+			name: "python stripe uses python app port",
+			detection: &models.Detection{
+				Language: "python",
+				Version:  "3.12",
+				Capabilities: models.Capabilities{
+					PaymentLibraries: []string{"stripe"},
+				},
+			},
+			projectName: "shop",
+			wantParts: []string{
+				"--forward-to app:8000/webhooks",
+			},
+		},
+		{
+			name: "no stripe",
+			detection: &models.Detection{
+				Language: "go",
+				Version:  "1.23",
+				Services: []string{"postgres"},
+			},
+			projectName: "api",
+			dontWant: []string{
+				"stripe",
+				"STRIPE_",
+			},
+		},
+	}
+
+	gen := NewComposeGenerator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := gen.GenerateContent(tt.detection, tt.projectName)
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+
+			yamlContent := string(content)
+
+			for _, want := range tt.wantParts {
+				if !strings.Contains(yamlContent, want) {
+					t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, yamlContent)
+				}
+			}
+
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(yamlContent, dontWant) {
+					t.Errorf("docker-compose.yml should NOT contain %q", dontWant)
+				}
+			}
+
+			var parsed map[string]interface{}
+			if err := yaml.Unmarshal(content, &parsed); err != nil {
+				t.Errorf("Generated YAML is invalid: %v", err)
+			}
+		})
+	}
+}
@@ -19,9 +19,11 @@ func TestComposeConfig_TracingSidecar(t *testing.T) {
 		{
 			name: "tracing library enables tracing sidecar",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				TracingLibraries: []string{"@opentelemetry/sdk-node"},
-				TracingProtocol:  "otlp",
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				},
+				TracingProtocol: "otlp",
 			},
 			checkConfig: func(t *testing.T, config *ComposeConfig) {
 				if !config.TracingSidecar.Enabled {
@@ -44,8 +46,10 @@ func TestComposeConfig_TracingSidecar(t *testing.T) {
 		{
 			name: "no tracing library disables tracing sidecar",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				TracingLibraries: nil,
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					TracingLibraries: nil,
+				},
 			},
 			checkConfig: func(t *testing.T, config *ComposeConfig) {
 				if config.TracingSidecar.Enabled {
@@ -56,9 +60,11 @@ func TestComposeConfig_TracingSidecar(t *testing.T) {
 		{
 			name: "uses default protocol when unknown",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				TracingLibraries: []string{"@opentelemetry/sdk-node"},
-				TracingProtocol:  "unknown",
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				},
+				TracingProtocol: "unknown",
 			},
 			checkConfig: func(t *testing.T, config *ComposeConfig) {
 				if !config.TracingSidecar.Enabled {
@@ -72,9 +78,11 @@ func TestComposeConfig_TracingSidecar(t *testing.T) {
 		{
 			name: "preserves jaeger protocol",
 			detection: &models.Detection{
-				Language:         "go",
-				TracingLibraries: []string{"github.com/uber/jaeger-client-go"},
-				TracingProtocol:  "jaeger",
+				Language: "go",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"github.com/uber/jaeger-client-go"},
+				},
+				TracingProtocol: "jaeger",
 			},
 			checkConfig: func(t *testing.T, config *ComposeConfig) {
 				if config.TracingSidecar.TracingProtocol != "jaeger" {
@@ -85,9 +93,11 @@ func TestComposeConfig_TracingSidecar(t *testing.T) {
 		{
 			name: "sets service name from project name",
 			detection: &models.Detection{
-				Language:         "python",
-				TracingLibraries: []string{"opentelemetry-sdk"},
-				TracingProtocol:  "otlp",
+				Language: "python",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"opentelemetry-sdk"},
+				},
+				TracingProtocol: "otlp",
 			},
 			checkConfig: func(t *testing.T, config *ComposeConfig) {
 				if config.TracingSidecar.ServiceName != "myproject" {
@@ -117,9 +127,11 @@ func TestComposeGenerator_TracingServices(t *testing.T) {
 		{
 			name: "tracing enabled generates jaeger",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				TracingLibraries: []string{"@opentelemetry/sdk-node"},
-				TracingProtocol:  "otlp",
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				},
+				TracingProtocol: "otlp",
 			},
 			expectedParts: []string{
 				"jaeger:",
@@ -148,11 +160,13 @@ func TestComposeGenerator_TracingServices(t *testing.T) {
 		{
 			name: "tracing with worker adds OTEL env to worker",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				TracingLibraries: []string{"@opentelemetry/sdk-node"},
-				TracingProtocol:  "otlp",
-				QueueLibraries:   []string{"bull"},
-				WorkerCommand:    "npm run worker",
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+					QueueLibraries:   []string{"bull"},
+				},
+				TracingProtocol: "otlp",
+				WorkerCommand:   "npm run worker",
 			},
 			expectedParts: []string{
 				"jaeger:",
@@ -163,8 +177,10 @@ func TestComposeGenerator_TracingServices(t *testing.T) {
 		{
 			name: "jaeger healthcheck configured",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				},
 			},
 			expectedParts: []string{
 				"healthcheck:",
@@ -209,9 +225,11 @@ func TestDevcontainerGenerator_TracingPorts(t *testing.T) {
 		{
 			name: "tracing adds jaeger UI port",
 			detection: &models.Detection{
-				Language:         "node",
-				Version:          "20",
-				TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				},
 			},
 			expectPorts: []int{3000, 16686}, // app + jaeger UI
 		},
@@ -226,10 +244,12 @@ func TestDevcontainerGenerator_TracingPorts(t *testing.T) {
 		{
 			name: "tracing with metrics adds all ports",
 			detection: &models.Detection{
-				Language:         "node",
-				Version:          "20",
-				TracingLibraries: []string{"@opentelemetry/sdk-node"},
-				MetricsLibraries: []string{"prom-client"},
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+					MetricsLibraries: []string{"prom-client"},
+				},
 			},
 			expectPorts: []int{3000, 9090, 3001, 16686}, // app + prometheus + grafana + jaeger UI
 		},
@@ -266,9 +286,11 @@ func TestDevcontainerGenerator_UseComposeWithTracing(t *testing.T) {
 		{
 			name: "tracing enables compose",
 			detection: &models.Detection{
-				Language:         "node",
-				Version:          "20",
-				TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				},
 			},
 			expectUseCompose: true,
 		},
@@ -283,10 +305,12 @@ func TestDevcontainerGenerator_UseComposeWithTracing(t *testing.T) {
 		{
 			name: "tracing and metrics enable compose",
 			detection: &models.Detection{
-				Language:         "node",
-				Version:          "20",
-				TracingLibraries: []string{"@opentelemetry/sdk-node"},
-				MetricsLibraries: []string{"prom-client"},
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+					MetricsLibraries: []string{"prom-client"},
+				},
 			},
 			expectUseCompose: true,
 		},
@@ -313,33 +337,41 @@ func TestTracingWithAllLanguages(t *testing.T) {
 		{
 			name: "nodejs with opentelemetry",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				TracingLibraries: []string{"@opentelemetry/sdk-node"},
-				TracingProtocol:  "otlp",
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				},
+				TracingProtocol: "otlp",
 			},
 		},
 		{
 			name: "go with opentelemetry",
 			detection: &models.Detection{
-				Language:         "go",
-				TracingLibraries: []string{"go.opentelemetry.io/otel"},
-				TracingProtocol:  "otlp",
+				Language: "go",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"go.opentelemetry.io/otel"},
+				},
+				TracingProtocol: "otlp",
 			},
 		},
 		{
 			name: "python with opentelemetry",
 			detection: &models.Detection{
-				Language:         "python",
-				TracingLibraries: []string{"opentelemetry-sdk"},
-				TracingProtocol:  "otlp",
+				Language: "python",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"opentelemetry-sdk"},
+				},
+				TracingProtocol: "otlp",
 			},
 		},
 		{
 			name: "rust with opentelemetry",
 			detection: &models.Detection{
-				Language:         "rust",
-				TracingLibraries: []string{"opentelemetry"},
-				TracingProtocol:  "otlp",
+				Language: "rust",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"opentelemetry"},
+				},
+				TracingProtocol: "otlp",
 			},
 		},
 	}
@@ -373,18 +405,20 @@ func TestTracingCombinedWithOtherSidecars(t *testing.T) {
 
 	// Full stack detection with tracing, metrics, logging, worker, and services
 	detection := &models.Detection{
-		Language:         "nodejs",
-		Version:          "20",
-		TracingLibraries: []string{"@opentelemetry/sdk-node"},
-		TracingProtocol:  "otlp",
-		MetricsLibraries: []string{"prom-client"},
-		MetricsPort:      3000,
-		MetricsPath:      "/metrics",
-		LoggingLibraries: []string{"pino"},
-		LogFormat:        "json",
-		QueueLibraries:   []string{"bull"},
-		WorkerCommand:    "npm run worker",
-		Services:         []string{"postgres", "redis"},
+		Language: "nodejs",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			TracingLibraries: []string{"@opentelemetry/sdk-node"},
+			MetricsLibraries: []string{"prom-client"},
+			LoggingLibraries: []string{"pino"},
+			QueueLibraries:   []string{"bull"},
+		},
+		TracingProtocol: "otlp",
+		MetricsPort:     3000,
+		MetricsPath:     "/metrics",
+		LogFormat:       "json",
+		WorkerCommand:   "npm run worker",
+		Services:        []string{"postgres", "redis"},
 	}
 
 	content, err := gen.GenerateContent(detection, "fullstack")
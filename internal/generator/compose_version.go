@@ -0,0 +1,315 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// swarmVersion is the compose file format version docker stack deploy reads.
+const swarmVersion = "3.8"
+
+// swarmRestartConditions maps restart policies to Swarm restart conditions.
+var swarmRestartConditions = map[string]string{
+	"no":             "none",
+	"always":         "any",
+	"unless-stopped": "any",
+	"on-failure":     "on-failure",
+}
+
+// swarmLimits maps service-level resource limits to their deploy.resources.limits keys.
+var swarmLimits = map[string]string{"mem_limit": "memory", "cpus": "cpus"}
+
+// swarmIgnoredKeys are service keys docker stack deploy ignores. They are left
+// in place, since the file still works with docker compose.
+var swarmIgnoredKeys = []string{"cgroup_parent", "devices", "external_links", "links", "network_mode", "security_opt", "tmpfs", "userns_mode"}
+
+// adaptCompose adapts a rendered docker-compose.yml to a compose version,
+// returning the adapted file and warnings about features the version lacks
+// or handles differently. v2 (or empty) returns content unchanged.
+func adaptCompose(content []byte, version string) ([]byte, []string, error) {
+	if version == "" || version == models.ComposeV2 {
+		return content, nil, nil
+	}
+
+	var warnings []string
+	content, err := editCompose(content, func(root, services *yaml.Node) {
+		switch version {
+		case models.ComposeV1:
+			warnings = adaptComposeV1(services)
+		case models.ComposeSwarm:
+			warnings = adaptComposeSwarm(root, services)
+		}
+	})
+	return content, warnings, err
+}
+
+// editCompose parses a rendered docker-compose.yml, lets edit change its
+// top-level mapping and services, and writes it back out. A file without
+// services is returned as is.
+func editCompose(content []byte, edit func(root, services *yaml.Node)) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse docker-compose.yml: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return content, nil
+	}
+	root := doc.Content[0]
+	services := mappingValue(root, "services")
+	if services == nil || services.Kind != yaml.MappingNode {
+		return content, nil
+	}
+
+	edit(root, services)
+	return encodeCompose(&doc)
+}
+
+// encodeCompose writes a docker-compose.yml document back out, in the layout
+// of the rendered template.
+func encodeCompose(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to write docker-compose.yml: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write docker-compose.yml: %w", err)
+	}
+	return separateBlocks(buf.Bytes()), nil
+}
+
+// separateBlocks restores the blank lines the YAML encoder drops between
+// services and top-level sections: one goes before every line (or comment)
+// that is less indented than the line before it, up to the service level.
+func separateBlocks(content []byte) []byte {
+	lines := strings.Split(string(content), "\n")
+	var out []string
+	prevIndent := 0
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+		if trimmed != "" && i > 0 && indent <= 2 && indent < prevIndent {
+			out = append(out, "")
+		}
+		out = append(out, line)
+		if trimmed != "" {
+			prevIndent = indent
+		}
+	}
+	return []byte(strings.Join(out, "\n"))
+}
+
+// adaptComposeV1 moves deploy resource limits to the service-level keys
+// docker-compose 1.x applies without --compatibility, and warns about
+// features that need a recent 1.x release.
+func adaptComposeV1(services *yaml.Node) []string {
+	var warnings, profiled, completed []string
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		name, service := services.Content[i].Value, services.Content[i+1]
+		if service.Kind != yaml.MappingNode {
+			continue
+		}
+
+		if deploy := mappingValue(service, "deploy"); deploy != nil {
+			if resources := mappingValue(deploy, "resources"); resources != nil {
+				limits := mappingValue(resources, "limits")
+				reservations := mappingValue(resources, "reservations")
+				setValue(service, "mem_limit", mappingValue(limits, "memory"))
+				setValue(service, "cpus", mappingValue(limits, "cpus"))
+				setValue(service, "mem_reservation", mappingValue(reservations, "memory"))
+				deleteKey(deploy, "resources")
+			}
+			if len(deploy.Content) == 0 {
+				deleteKey(service, "deploy")
+			}
+		}
+
+		if mappingValue(service, "profiles") != nil {
+			profiled = append(profiled, name)
+		}
+		if dependsOnCondition(service, "service_completed_successfully") {
+			completed = append(completed, name)
+		}
+	}
+
+	if len(profiled) > 0 {
+		warnings = append(warnings, fmt.Sprintf("docker-compose 1.x needs 1.28 or later for profiles (used by %s)", strings.Join(profiled, ", ")))
+	}
+	if len(completed) > 0 {
+		warnings = append(warnings, fmt.Sprintf("docker-compose 1.x needs 1.29 or later for depends_on condition service_completed_successfully (used by %s)", strings.Join(completed, ", ")))
+	}
+	return warnings
+}
+
+// adaptComposeSwarm removes what docker stack deploy rejects (profiles,
+// extends, container_name, depends_on conditions), moves restart policies
+// and resource limits under deploy, and warns about what Swarm ignores.
+func adaptComposeSwarm(root, services *yaml.Node) []string {
+	var warnings, removed, built, ignored []string
+
+	// Services behind a profile are opt-in tools and test runners, and extends
+	// isn't supported at all
+	for i := 0; i+1 < len(services.Content); {
+		name, service := services.Content[i].Value, services.Content[i+1]
+		if mappingValue(service, "profiles") != nil || mappingValue(service, "extends") != nil {
+			removed = append(removed, name)
+			services.Content = append(services.Content[:i], services.Content[i+2:]...)
+			continue
+		}
+		i += 2
+	}
+
+	var ordered bool
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		name, service := services.Content[i].Value, services.Content[i+1]
+		if service.Kind != yaml.MappingNode {
+			continue
+		}
+
+		if mappingValue(service, "build") != nil {
+			built = append(built, name)
+		}
+		if dependsOn := mappingValue(service, "depends_on"); dependsOn != nil {
+			ordered = true
+			listDependencies(dependsOn, removed)
+		}
+		deleteKey(service, "container_name")
+
+		if restart := mappingValue(service, "restart"); restart != nil {
+			if condition, ok := swarmRestartConditions[restart.Value]; ok {
+				policy := ensureMapping(ensureMapping(service, "deploy"), "restart_policy")
+				setValue(policy, "condition", &yaml.Node{Kind: yaml.ScalarNode, Value: condition})
+			}
+			deleteKey(service, "restart")
+		}
+		for _, key := range []string{"mem_limit", "cpus"} {
+			if value := mappingValue(service, key); value != nil {
+				limits := ensureMapping(ensureMapping(ensureMapping(service, "deploy"), "resources"), "limits")
+				setValue(limits, swarmLimits[key], value)
+				deleteKey(service, key)
+			}
+		}
+
+		for _, key := range swarmIgnoredKeys {
+			if mappingValue(service, key) != nil {
+				ignored = append(ignored, name+"."+key)
+			}
+		}
+	}
+
+	if mappingValue(root, "version") == nil {
+		root.Content = append([]*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "version"},
+			{Kind: yaml.ScalarNode, Value: swarmVersion, Style: yaml.DoubleQuotedStyle},
+		}, root.Content...)
+	}
+
+	if len(removed) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Swarm doesn't support profiles or extends: left out %s", strings.Join(removed, ", ")))
+	}
+	if len(built) > 0 {
+		warnings = append(warnings, fmt.Sprintf("docker stack deploy ignores build: build and push the images of %s, and set their image:, before deploying", strings.Join(built, ", ")))
+	}
+	if ordered {
+		warnings = append(warnings, "Swarm ignores depends_on: services start in any order, so they must retry connections to their dependencies")
+	}
+	if len(ignored) > 0 {
+		sort.Strings(ignored)
+		warnings = append(warnings, fmt.Sprintf("docker stack deploy ignores %s", strings.Join(ignored, ", ")))
+	}
+	return warnings
+}
+
+// listDependencies rewrites a depends_on mapping with conditions to the list
+// form Swarm accepts, dropping services that were left out.
+func listDependencies(dependsOn *yaml.Node, removed []string) {
+	var names []string
+	switch dependsOn.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(dependsOn.Content); i += 2 {
+			names = append(names, dependsOn.Content[i].Value)
+		}
+	case yaml.SequenceNode:
+		for _, item := range dependsOn.Content {
+			names = append(names, item.Value)
+		}
+	}
+
+	dependsOn.Kind = yaml.SequenceNode
+	dependsOn.Tag = "!!seq"
+	dependsOn.Content = nil
+	for _, name := range names {
+		if !containsString(removed, name) {
+			dependsOn.Content = append(dependsOn.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name})
+		}
+	}
+}
+
+// dependsOnCondition reports whether any of a service's dependencies waits
+// for condition.
+func dependsOnCondition(service *yaml.Node, condition string) bool {
+	dependsOn := mappingValue(service, "depends_on")
+	if dependsOn == nil || dependsOn.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 1; i < len(dependsOn.Content); i += 2 {
+		if value := mappingValue(dependsOn.Content[i], "condition"); value != nil && value.Value == condition {
+			return true
+		}
+	}
+	return false
+}
+
+// mappingValue returns the value of key in a mapping node, or nil.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setValue sets key in a mapping to value, replacing any existing value.
+// A nil value leaves the mapping unchanged.
+func setValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	if value == nil {
+		return
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
+
+// ensureMapping returns the mapping under key, adding an empty one if needed.
+func ensureMapping(mapping *yaml.Node, key string) *yaml.Node {
+	if value := mappingValue(mapping, key); value != nil {
+		return value
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	return value
+}
+
+// deleteKey removes key from a mapping.
+func deleteKey(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
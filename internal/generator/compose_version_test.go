@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// composeVersionDetection runs a worker behind a queue, admin tools behind
+// a profile, and resource limits: everything the compose versions treat
+// differently.
+func composeVersionDetection(version string) *models.Detection {
+	return &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Services: []string{"postgres", "redis"},
+		Capabilities: models.Capabilities{
+			QueueLibraries: []string{"bullmq"},
+		},
+		ComposeVersion: version,
+	}
+}
+
+// TestComposeGenerator_ComposeVersion tests the syntax generated for each compose version.
+func TestComposeGenerator_ComposeVersion(t *testing.T) {
+	tests := []struct {
+		version      string
+		wantParts    []string
+		dontWant     []string
+		wantWarnings []string
+	}{
+		{
+			version:   models.ComposeV2,
+			wantParts: []string{"    deploy:\n      resources:\n        limits:\n", `profiles: ["tools"]`},
+			dontWant:  []string{"version:", "mem_limit:"},
+		},
+		{
+			version:      models.ComposeV1,
+			wantParts:    []string{"    mem_limit: 128M\n    cpus: \"0.25\"\n", `profiles: ["tools"]`},
+			dontWant:     []string{"resources:", "version:"},
+			wantWarnings: []string{"needs 1.28 or later for profiles"},
+		},
+		{
+			version: models.ComposeSwarm,
+			wantParts: []string{
+				"version: \"3.8\"\nservices:\n",
+				"    depends_on:\n      - postgres\n",
+				"    deploy:\n      resources:\n        limits:\n          cpus: \"0.25\"\n          memory: 128M\n      restart_policy:\n        condition: any\n",
+			},
+			dontWant: []string{"profiles:", "extends:", "restart: unless-stopped", "condition: service_healthy"},
+			wantWarnings: []string{
+				"Swarm doesn't support profiles or extends: left out adminer, redisinsight",
+				"docker stack deploy ignores build: build and push the images of app",
+				"Swarm ignores depends_on",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			gen := NewComposeGenerator()
+			detection := composeVersionDetection(tt.version)
+			content, err := gen.GenerateContent(detection, "shop")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+			output := string(content)
+
+			var parsed map[string]interface{}
+			if err := yaml.Unmarshal(content, &parsed); err != nil {
+				t.Fatalf("generated invalid YAML: %v", err)
+			}
+			if !strings.Contains(output, "\n\n  postgres:\n") && !strings.Contains(output, "\n\n  # postgres service\n  postgres:\n") {
+				t.Error("services should stay separated by blank lines")
+			}
+			for _, part := range tt.wantParts {
+				if !strings.Contains(output, part) {
+					t.Errorf("output should contain %q", part)
+				}
+			}
+			for _, part := range tt.dontWant {
+				if strings.Contains(output, part) {
+					t.Errorf("output should not contain %q", part)
+				}
+			}
+
+			warnings, err := gen.VersionWarnings(detection, "shop")
+			if err != nil {
+				t.Fatalf("VersionWarnings() error = %v", err)
+			}
+			if len(warnings) != len(tt.wantWarnings) {
+				t.Fatalf("VersionWarnings() = %q, want %d warnings", warnings, len(tt.wantWarnings))
+			}
+			for i, want := range tt.wantWarnings {
+				if !strings.Contains(warnings[i], want) {
+					t.Errorf("warning %d = %q, want it to contain %q", i, warnings[i], want)
+				}
+			}
+		})
+	}
+}
+
+// TestAdaptCompose_Swarm tests the rewrites docker stack deploy needs on a
+// hand-written file.
+func TestAdaptCompose_Swarm(t *testing.T) {
+	content := []byte(`services:
+  app:
+    build: .
+    container_name: app
+    mem_limit: 1g
+    restart: "no"
+    network_mode: host
+    depends_on:
+      db:
+        condition: service_healthy
+      seed:
+        condition: service_completed_successfully
+  db:
+    image: postgres:16
+  seed:
+    image: postgres:16
+    profiles: ["tools"]
+`)
+	got, warnings, err := adaptCompose(content, models.ComposeSwarm)
+	if err != nil {
+		t.Fatalf("adaptCompose() error = %v", err)
+	}
+
+	want := `version: "3.8"
+services:
+  app:
+    build: .
+    network_mode: host
+    depends_on:
+      - db
+    deploy:
+      restart_policy:
+        condition: none
+      resources:
+        limits:
+          memory: 1g
+
+  db:
+    image: postgres:16
+`
+	if string(got) != want {
+		t.Errorf("adaptCompose() =\n%s\nwant\n%s", got, want)
+	}
+	if len(warnings) != 4 || warnings[3] != "docker stack deploy ignores app.network_mode" {
+		t.Errorf("warnings = %q", warnings)
+	}
+}
@@ -0,0 +1,143 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestWebService tests frontend dev server generation in docker-compose.yml.
+func TestWebService(t *testing.T) {
+	tests := []struct {
+		name        string
+		detection   *models.Detection
+		projectName string
+		wantParts   []string
+		dontWant    []string
+	}{
+		{
+			name: "go backend with vite frontend",
+			detection: &models.Detection{
+				Language:          "go",
+				Version:           "1.23",
+				FrontendFramework: "vite",
+				FrontendDir:       "frontend",
+				FrontendPort:      5173,
+			},
+			projectName: "go-vite-app",
+			wantParts: []string{
+				"# Frontend dev server (Vite)",
+				"web:",
+				"image: node:20",
+				"working_dir: /workspace/frontend",
+				`command: sh -c "npm install && npm run dev -- --host 0.0.0.0 --port 5173"`,
+				`"5173:5173"`,
+				"CHOKIDAR_USEPOLLING=true",
+			},
+		},
+		{
+			name: "node backend with next.js in the same package",
+			detection: &models.Detection{
+				Language:          "node",
+				Version:           "22",
+				FrontendFramework: "nextjs",
+				FrontendDir:       ".",
+				FrontendPort:      3002,
+			},
+			projectName: "next-express-app",
+			wantParts: []string{
+				"# Frontend dev server (Next.js)",
+				"image: node:22",
+				"working_dir: /workspace\n",
+				"--hostname 0.0.0.0 --port 3002",
+				`"3002:3002"`,
+				"WATCHPACK_POLLING=true",
+			},
+		},
+		{
+			name: "create react app configures the HMR websocket port",
+			detection: &models.Detection{
+				Language:          "python",
+				Version:           "3.12",
+				FrontendFramework: "cra",
+				FrontendDir:       "client",
+				FrontendPort:      3000,
+			},
+			projectName: "django-cra-app",
+			wantParts: []string{
+				"# Frontend dev server (Create React App)",
+				`command: sh -c "npm install && npm start"`,
+				"HOST=0.0.0.0",
+				"PORT=3000",
+				"WDS_SOCKET_PORT=3000",
+			},
+		},
+		{
+			name: "frontend-only project has no web service",
+			detection: &models.Detection{
+				Language:          "node",
+				Version:           "20",
+				FrontendFramework: "nextjs",
+				FrontendPort:      3000,
+			},
+			projectName: "next-app",
+			dontWant: []string{
+				"web:",
+				"Frontend dev server",
+			},
+		},
+	}
+
+	gen := NewComposeGenerator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := gen.GenerateContent(tt.detection, tt.projectName)
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+
+			yamlContent := string(content)
+
+			for _, want := range tt.wantParts {
+				if !strings.Contains(yamlContent, want) {
+					t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, yamlContent)
+				}
+			}
+
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(yamlContent, dontWant) {
+					t.Errorf("docker-compose.yml should NOT contain %q", dontWant)
+				}
+			}
+
+			var parsed map[string]interface{}
+			if err := yaml.Unmarshal(content, &parsed); err != nil {
+				t.Errorf("Generated YAML is invalid: %v", err)
+			}
+		})
+	}
+}
+
+// TestWebService_DevcontainerPorts tests that the frontend dev server port is forwarded.
+func TestWebService_DevcontainerPorts(t *testing.T) {
+	gen := NewDevcontainerGenerator()
+	detection := &models.Detection{
+		Language:          "go",
+		Version:           "1.23",
+		FrontendFramework: "vite",
+		FrontendDir:       "frontend",
+		FrontendPort:      5173,
+	}
+
+	config := gen.buildConfig(detection, "go-vite-app")
+
+	if !config.UseCompose {
+		t.Error("Expected UseCompose to be true when a web service is needed")
+	}
+	if !containsPort(config.ForwardPorts, 5173) {
+		t.Errorf("Expected port 5173 in ForwardPorts, got %v", config.ForwardPorts)
+	}
+}
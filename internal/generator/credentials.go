@@ -0,0 +1,258 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// CredentialsFile holds the generated development passwords, relative to the
+// project root. Docker Compose reads the .env next to docker-compose.yml to
+// substitute the ${...} references the generated services use.
+const CredentialsFile = ".devcontainer/.env"
+
+// credentialsHeader starts the generated .env.
+const credentialsHeader = `# Development passwords generated by dockstart for this project.
+# docker-compose.yml reads them as ${VARIABLE}; regenerating keeps the values
+# below, so existing data volumes still accept them. Don't commit this file.
+`
+
+// credentialVars are the passwords the generated services share, in .env order.
+// Legacy is the fixed password dockstart used before generating them, which
+// the data volumes of previously generated projects were initialized with.
+var credentialVars = []struct {
+	Name   string
+	Legacy string
+}{
+	{"POSTGRES_PASSWORD", "postgres"},
+	{"MYSQL_ROOT_PASSWORD", "mysql"},
+	{"CLICKHOUSE_PASSWORD", "clickhouse"},
+	{"INFLUXDB_PASSWORD", "influxdb"},
+	{"GLITCHTIP_DB_PASSWORD", "glitchtip"},
+	{"GRAFANA_ADMIN_PASSWORD", "admin"},
+	{"KEYCLOAK_ADMIN_PASSWORD", "admin"},
+}
+
+// credentialRef returns the compose reference to a generated password.
+func credentialRef(name string) string {
+	return "${" + name + "}"
+}
+
+// Credential is a generated password in .devcontainer/.env.
+type Credential struct {
+	// Name is the environment variable (e.g., "POSTGRES_PASSWORD")
+	Name string
+
+	// Value is the password
+	Value string
+}
+
+// CredentialsGenerator generates .devcontainer/.env with a random password for
+// each service in docker-compose.yml that needs one.
+type CredentialsGenerator struct {
+	output
+
+	// random is the source of new passwords
+	random io.Reader
+}
+
+// NewCredentialsGenerator creates a new credentials generator.
+func NewCredentialsGenerator() *CredentialsGenerator {
+	return &CredentialsGenerator{random: rand.Reader}
+}
+
+// ShouldGenerate returns true if the generated docker-compose.yml references a password.
+func (g *CredentialsGenerator) ShouldGenerate(detection *models.Detection, projectName string) bool {
+	if !detection.NeedsCompose() {
+		return false
+	}
+	names, err := credentialNames(detection, projectName)
+	return err == nil && len(names) > 0
+}
+
+// Names returns the password variables the generated docker-compose.yml references.
+func (g *CredentialsGenerator) Names(detection *models.Detection, projectName string) ([]string, error) {
+	return credentialNames(detection, projectName)
+}
+
+// Generate writes .devcontainer/.env, keeping the passwords it already has
+// and any other variables set there.
+func (g *CredentialsGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	credentials, err := g.Credentials(detection, projectPath, projectName)
+	if err != nil {
+		return err
+	}
+	existing, err := g.readEnv(filepath.Join(projectPath, filepath.FromSlash(CredentialsFile)))
+	if err != nil {
+		return err
+	}
+	for _, variable := range existing {
+		if !slices.ContainsFunc(credentials, func(c Credential) bool { return c.Name == variable.Name }) {
+			credentials = append(credentials, variable)
+		}
+	}
+
+	devcontainerDir := filepath.Join(projectPath, ".devcontainer")
+	if err := g.fs().MkdirAll(devcontainerDir, 0755); err != nil {
+		return fmt.Errorf("failed to create .devcontainer directory: %w", err)
+	}
+	// Other users on the host have no business reading the passwords
+	if err := g.fs().WriteFile(filepath.Join(devcontainerDir, ".env"), g.GenerateContent(credentials), 0600); err != nil {
+		return fmt.Errorf("failed to write .env: %w", err)
+	}
+	return nil
+}
+
+// Credentials returns the passwords for the services in docker-compose.yml.
+// Role passwords set in .dockstart.yml are used as is; other passwords
+// already in .devcontainer/.env are kept. New ones are random,
+// except for a project generated before the passwords were: its data volumes
+// were initialized with the fixed development passwords, which are kept.
+func (g *CredentialsGenerator) Credentials(detection *models.Detection, projectPath, projectName string) ([]Credential, error) {
+	names, err := credentialNames(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	variables, err := g.readEnv(filepath.Join(projectPath, filepath.FromSlash(CredentialsFile)))
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]string, len(variables))
+	for _, variable := range variables {
+		existing[variable.Name] = variable.Value
+	}
+	legacy := false
+	if variables == nil {
+		compose, err := g.fs().ReadFile(filepath.Join(projectPath, ".devcontainer", "docker-compose.yml"))
+		legacy = err == nil && len(referencedCredentials(compose)) == 0
+	}
+
+	roles := make(map[string]models.PostgresRole, len(detection.PostgresRoles))
+	for _, role := range detection.PostgresRoles {
+		roles[role.PasswordVar()] = role
+	}
+
+	credentials := make([]Credential, 0, len(names))
+	for _, name := range names {
+		value, ok := existing[name]
+		role, isRole := roles[name]
+		switch {
+		case isRole && role.Password != "":
+			// Set in .dockstart.yml
+			value = role.Password
+		case ok && value != "":
+		case legacy && isRole:
+			// Roles were created with their name as the password
+			value = role.Name
+		case legacy:
+			value = legacyCredential(name)
+		default:
+			if value, err = g.newPassword(); err != nil {
+				return nil, err
+			}
+		}
+		credentials = append(credentials, Credential{Name: name, Value: value})
+	}
+	return credentials, nil
+}
+
+// GenerateContent returns the .env content for credentials.
+func (g *CredentialsGenerator) GenerateContent(credentials []Credential) []byte {
+	var b strings.Builder
+	b.WriteString(credentialsHeader)
+	for _, credential := range credentials {
+		fmt.Fprintf(&b, "%s=%s\n", credential.Name, credential.Value)
+	}
+	return []byte(b.String())
+}
+
+// newPassword returns 32 random hex characters, which need no escaping in
+// connection URLs, shell scripts, or YAML.
+func (g *CredentialsGenerator) newPassword() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := io.ReadFull(g.random, buf); err != nil {
+		return "", fmt.Errorf("failed to generate a password: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// readEnv returns the variables set in an .env file, in file order, or nil if
+// there is none.
+func (g *CredentialsGenerator) readEnv(path string) ([]Credential, error) {
+	data, err := g.fs().ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", CredentialsFile, err)
+	}
+
+	env := []Credential{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		env = append(env, Credential{
+			Name:  strings.TrimSpace(name),
+			Value: strings.Trim(strings.TrimSpace(value), `"'`),
+		})
+	}
+	return env, nil
+}
+
+// credentialNames returns the password variables the generated
+// docker-compose.yml references, in .env order: the services' passwords,
+// then those of the PostgreSQL roles from .dockstart.yml.
+func credentialNames(detection *models.Detection, projectName string) ([]string, error) {
+	compose, err := NewComposeGenerator().GenerateContent(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+	names := referencedCredentials(compose)
+	for _, role := range detection.PostgresRoles {
+		if bytes.Contains(compose, []byte(credentialRef(role.PasswordVar()))) {
+			names = append(names, role.PasswordVar())
+		}
+	}
+	return names, nil
+}
+
+// referencedCredentials returns the password variables a docker-compose.yml
+// references, in .env order.
+func referencedCredentials(compose []byte) []string {
+	var names []string
+	for _, credential := range credentialVars {
+		if bytes.Contains(compose, []byte(credentialRef(credential.Name))) {
+			names = append(names, credential.Name)
+		}
+	}
+	return names
+}
+
+// legacyCredential returns the fixed development password dockstart used for
+// a variable before generating them.
+func legacyCredential(name string) string {
+	for _, credential := range credentialVars {
+		if credential.Name == name {
+			return credential.Legacy
+		}
+	}
+	return ""
+}
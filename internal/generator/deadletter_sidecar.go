@@ -0,0 +1,99 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// DeadLetterSidecarConfig holds the retry policy and queue names of the
+// dead-letter queue scaffolding.
+type DeadLetterSidecarConfig struct {
+	// ProjectName is the name of the project
+	ProjectName string
+
+	// MaxRetries is how many times a failed job is retried before it is dead-lettered
+	MaxRetries int
+
+	// Backoff is the delay before a failed job is retried (e.g., "5s")
+	Backoff string
+
+	// BackoffMillis is Backoff in milliseconds, the TTL of the RabbitMQ retry queue
+	BackoffMillis int64
+
+	// QueueName is the queue the worker consumes (e.g., "myapp")
+	QueueName string
+
+	// DeadLetterQueue is the queue failed jobs are moved to (e.g., "myapp-dlq")
+	DeadLetterQueue string
+
+	// RetryQueue holds RabbitMQ jobs for Backoff before they return to QueueName
+	RetryQueue string
+
+	// Exchange is the RabbitMQ dead-letter exchange (e.g., "myapp-dlx")
+	Exchange string
+}
+
+// DeadLetterConfig returns the dead-letter queue settings for a Detection.
+func DeadLetterConfig(detection *models.Detection, projectName string) *DeadLetterSidecarConfig {
+	queue := awsResourceName(projectName)
+	backoff := detection.GetRetryBackoff()
+
+	// config.Load validates the backoff, so a parse error leaves the TTL at 0
+	duration, _ := time.ParseDuration(backoff)
+
+	return &DeadLetterSidecarConfig{
+		ProjectName:     projectName,
+		MaxRetries:      detection.GetMaxRetries(),
+		Backoff:         backoff,
+		BackoffMillis:   duration.Milliseconds(),
+		QueueName:       queue,
+		DeadLetterQueue: queue + "-dlq",
+		RetryQueue:      queue + "-retry",
+		Exchange:        queue + "-dlx",
+	}
+}
+
+// DeadLetterSidecarGenerator generates the RabbitMQ dead-letter exchange init script.
+type DeadLetterSidecarGenerator struct {
+	output
+}
+
+// NewDeadLetterSidecarGenerator creates a new dead-letter sidecar generator.
+func NewDeadLetterSidecarGenerator() *DeadLetterSidecarGenerator {
+	return &DeadLetterSidecarGenerator{}
+}
+
+// GenerateInitScript generates the init-dlx.sh content run once RabbitMQ is healthy.
+func (g *DeadLetterSidecarGenerator) GenerateInitScript(config *DeadLetterSidecarConfig) ([]byte, error) {
+	return renderTemplate("rabbitmq/init-dlx.sh.tmpl", config)
+}
+
+// Generate creates .devcontainer/rabbitmq/init-dlx.sh.
+func (g *DeadLetterSidecarGenerator) Generate(detection *models.Detection, outputPath, projectName string) error {
+	config := DeadLetterConfig(detection, projectName)
+
+	rabbitmqDir := filepath.Join(outputPath, ".devcontainer", "rabbitmq")
+	if err := g.fs().MkdirAll(rabbitmqDir, 0755); err != nil {
+		return fmt.Errorf("failed to create rabbitmq directory: %w", err)
+	}
+
+	script, err := g.GenerateInitScript(config)
+	if err != nil {
+		return err
+	}
+	if err := g.fs().WriteFile(filepath.Join(rabbitmqDir, "init-dlx.sh"), script, 0755); err != nil {
+		return fmt.Errorf("failed to write rabbitmq init script: %w", err)
+	}
+
+	return nil
+}
+
+// ShouldGenerate returns true if the RabbitMQ dead-letter init script should be generated.
+// Other queues get a worker-dlq consumer in docker-compose.yml instead.
+func (g *DeadLetterSidecarGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.NeedsDeadLetter() && usesRabbitMQ(detection.QueueLibraries)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/jpequegn/dockstart/internal/models"
 )
@@ -18,24 +19,91 @@ type DevcontainerConfig struct {
 	// Image is the Docker image to use (when not using Compose)
 	Image string
 
+	// Dockerfile is the project's own Dockerfile to build instead of Image (when not
+	// using Compose), relative to .devcontainer (e.g., "../Dockerfile"), or the
+	// generated one in prebuild mode
+	Dockerfile string
+
+	// Target is the build stage of Dockerfile to use, or empty for the last stage
+	Target string
+
+	// CacheFrom is the prebuilt image the build reuses layers from, in prebuild mode
+	CacheFrom string
+
 	// UseCompose indicates whether to use docker-compose.yml
 	UseCompose bool
 
+	// DevOverride is the dev environment's compose override layered over
+	// docker-compose.yml (e.g., "docker-compose.dev.yml"), or empty for none
+	DevOverride string
+
 	// Extensions is a list of VS Code extension IDs
 	Extensions []string
 
+	// JetBrainsBackend is the IDE JetBrains Gateway runs in the container
+	// (e.g., "GoLand")
+	JetBrainsBackend string
+
 	// ForwardPorts is a list of ports to forward from the container
 	ForwardPorts []int
 
+	// PortsAttributes labels forwarded ports (e.g., ports that carry WebSocket traffic)
+	PortsAttributes []PortAttributes
+
+	// Features are the IDs of dev container features to install (Codespaces only)
+	Features []string
+
+	// InitializeCommand is the command to run on the local machine before the
+	// container is created, such as the remote workspace sync
+	InitializeCommand string
+
+	// OnCreateCommand is the command to run when the container is first
+	// created, which Codespaces prebuilds run ahead of time (Codespaces only)
+	OnCreateCommand string
+
 	// PostCreateCommand is the command to run after container creation
 	PostCreateCommand string
 
+	// PostStartCommand is the command to run each time the container starts
+	PostStartCommand string
+
 	// RemoteUser is the user to run as in the container
 	RemoteUser string
+
+	// UpdateRemoteUserUID asks the dev container tools to remap RemoteUser to
+	// the host UID/GID when the container is created
+	UpdateRemoteUserUID bool
+
+	// WorkspaceMount overrides how the workspace is mounted (when not using Compose)
+	WorkspaceMount string
+
+	// Mounts are additional mounts: named volumes for dependency directories
+	// in Windows mode or persisted caches (when not using Compose), and the
+	// forwarded host credentials
+	Mounts []string
+
+	// RemoteEnv sets variables for terminals and lifecycle commands, such as
+	// HISTFILE and cache directories pointing into persisted volumes
+	RemoteEnv []EnvVar
+}
+
+// PortAttributes holds devcontainer.json portsAttributes settings for a forwarded port.
+type PortAttributes struct {
+	// Port is the forwarded port number
+	Port int
+
+	// Label is the name shown for the port in the VS Code Ports view
+	Label string
+
+	// OnAutoForward controls what happens when the port is auto-forwarded
+	// (e.g., "silent" for non-browser protocols like gRPC). Empty means the default
+	OnAutoForward string
 }
 
 // DevcontainerGenerator generates devcontainer.json files.
-type DevcontainerGenerator struct{}
+type DevcontainerGenerator struct {
+	output
+}
 
 // NewDevcontainerGenerator creates a new devcontainer generator.
 func NewDevcontainerGenerator() *DevcontainerGenerator {
@@ -43,24 +111,23 @@ func NewDevcontainerGenerator() *DevcontainerGenerator {
 }
 
 // Generate creates a devcontainer.json file from a Detection.
+// An existing devcontainer.json is updated rather than replaced (see MergeContent).
 func (g *DevcontainerGenerator) Generate(detection *models.Detection, projectPath string, projectName string) error {
-	config := g.buildConfig(detection, projectName)
-
 	// Create .devcontainer directory
 	devcontainerDir := filepath.Join(projectPath, ".devcontainer")
-	if err := os.MkdirAll(devcontainerDir, 0755); err != nil {
+	if err := g.fs().MkdirAll(devcontainerDir, 0755); err != nil {
 		return fmt.Errorf("failed to create .devcontainer directory: %w", err)
 	}
 
-	// Generate devcontainer.json content
-	content, err := g.render(config)
+	// Generate devcontainer.json content, merged into the existing file
+	content, err := g.MergeContent(detection, projectPath, projectName)
 	if err != nil {
-		return fmt.Errorf("failed to render template: %w", err)
+		return err
 	}
 
 	// Write to file
 	outputPath := filepath.Join(devcontainerDir, "devcontainer.json")
-	if err := os.WriteFile(outputPath, content, 0644); err != nil {
+	if err := g.fs().WriteFile(outputPath, content, 0644); err != nil {
 		return fmt.Errorf("failed to write devcontainer.json: %w", err)
 	}
 
@@ -74,6 +141,137 @@ func (g *DevcontainerGenerator) GenerateContent(detection *models.Detection, pro
 	return g.render(config)
 }
 
+// MergeContent returns the devcontainer.json content Generate writes: the generated
+// file, or, when .devcontainer/devcontainer.json exists, the existing file with
+// dockstart's keys added. User settings, extensions, and features are kept.
+func (g *DevcontainerGenerator) MergeContent(detection *models.Detection, projectPath string, projectName string) ([]byte, error) {
+	content, err := g.GenerateContent(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := g.fs().ReadFile(filepath.Join(projectPath, ".devcontainer", "devcontainer.json"))
+	if os.IsNotExist(err) {
+		return content, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read devcontainer.json: %w", err)
+	}
+
+	existing, err := parseJSONC(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse existing devcontainer.json (fix or remove it): %w", err)
+	}
+	generated, err := parseJSONC(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse generated devcontainer.json: %w", err)
+	}
+
+	mergeDevcontainer(existing, generated)
+	return indentJSON(existing)
+}
+
+// composeKeys are the devcontainer.json keys dockstart manages in Compose mode.
+// They replace the user's values, along with dropping imageKeys.
+var composeKeys = []string{"dockerComposeFile", "service", "workspaceFolder"}
+
+// imageKeys are the devcontainer.json keys that pick the container without Compose.
+var imageKeys = []string{"image", "build", "dockerFile"}
+
+// mergeDevcontainer adds the keys of a generated devcontainer.json to an existing one.
+// Compose settings are dockstart's; forwarded ports and extensions are merged; any
+// other key is only added when the user hasn't set it.
+func mergeDevcontainer(existing, generated *jsonObject) {
+	if _, ok := generated.Get("dockerComposeFile"); ok {
+		for _, key := range composeKeys {
+			value, _ := generated.Get(key)
+			existing.Set(key, value)
+		}
+		for _, key := range imageKeys {
+			existing.Delete(key)
+		}
+	} else if hasAnyKey(existing, append([]string{"dockerComposeFile"}, imageKeys...)) {
+		// The user already chose how to run the container
+		for _, key := range append([]string{"workspaceFolder"}, imageKeys...) {
+			generated.Delete(key)
+		}
+	}
+
+	for _, key := range generated.keys {
+		value := generated.values[key]
+		current, ok := existing.Get(key)
+		switch {
+		case !ok:
+			existing.Set(key, value)
+		case key == "forwardPorts":
+			existing.Set(key, mergeArrays(current, value))
+		case key == "portsAttributes" || key == "customizations" || key == "remoteEnv" || key == "features":
+			mergeObjects(current, value)
+		}
+	}
+}
+
+// mergeObjects adds the keys of generated to existing, recursively, merging arrays
+// (e.g., customizations.vscode.extensions). Values the user set are kept.
+func mergeObjects(existing, generated interface{}) {
+	existingObject, ok := existing.(*jsonObject)
+	if !ok {
+		return
+	}
+	generatedObject, ok := generated.(*jsonObject)
+	if !ok {
+		return
+	}
+
+	for _, key := range generatedObject.keys {
+		value := generatedObject.values[key]
+		current, ok := existingObject.Get(key)
+		if !ok {
+			existingObject.Set(key, value)
+			continue
+		}
+		if _, isArray := current.([]interface{}); isArray {
+			existingObject.Set(key, mergeArrays(current, value))
+		} else {
+			mergeObjects(current, value)
+		}
+	}
+}
+
+// mergeArrays appends the values of generated missing from existing.
+func mergeArrays(existing, generated interface{}) interface{} {
+	existingArray, ok := existing.([]interface{})
+	if !ok {
+		return existing
+	}
+	generatedArray, _ := generated.([]interface{})
+
+	merged := append([]interface{}{}, existingArray...)
+	for _, value := range generatedArray {
+		found := false
+		for _, current := range merged {
+			if fmt.Sprint(current) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, value)
+		}
+	}
+	return merged
+}
+
+// hasAnyKey returns true if an object has any of keys.
+func hasAnyKey(object *jsonObject, keys []string) bool {
+	for _, key := range keys {
+		if _, ok := object.Get(key); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // buildConfig creates a DevcontainerConfig from a Detection.
 func (g *DevcontainerGenerator) buildConfig(detection *models.Detection, projectName string) *DevcontainerConfig {
 	config := &DevcontainerConfig{
@@ -81,10 +279,22 @@ func (g *DevcontainerGenerator) buildConfig(detection *models.Detection, project
 		RemoteUser: "root", // Default, will be overridden per language
 	}
 
-	// Determine if we need docker-compose (when services, sidecars, metrics, or tracing detected)
-	config.UseCompose = len(detection.Services) > 0 || detection.HasStructuredLogging() ||
-		detection.NeedsMetrics() || detection.NeedsWorker() || detection.NeedsFileProcessor() ||
-		detection.NeedsTracing()
+	// Determine if we need docker-compose (when services, sidecars, metrics, or tracing
+	// detected, or when the project's own compose file is imported)
+	config.UseCompose = len(detection.Services) > 0 || detection.NeedsLogSidecar() ||
+		detection.NeedsMetrics() || detection.NeedsWorker() || detection.NeedsScheduler() ||
+		detection.NeedsGRPC() || detection.NeedsAuthProvider() || detection.NeedsStripe() ||
+		detection.NeedsLocalStack() || detection.NeedsVectorStore() || detection.NeedsSearch() ||
+		detection.NeedsMailpit() || detection.NeedsOllama() || detection.NeedsFileProcessor() ||
+		detection.NeedsTracing() || detection.NeedsWebService() || detection.NeedsSeleniumGrid() ||
+		detection.NeedsWireMock() || detection.NeedsGlitchTip() || detection.NeedsStatusPage() ||
+		detection.ExistingCompose != nil
+	if config.UseCompose && detection.HasEnvironment(models.EnvDev) {
+		config.DevOverride = filepath.Base(OverrideFile(models.EnvDev))
+	}
+
+	// Open the language's own IDE from JetBrains Gateway
+	config.JetBrainsBackend = JetBrainsBackend(detection.Language)
 
 	// Language-specific configuration
 	switch detection.Language {
@@ -93,7 +303,10 @@ func (g *DevcontainerGenerator) buildConfig(detection *models.Detection, project
 		config.Extensions = []string{
 			"dbaeumer.vscode-eslint",
 		}
-		config.PostCreateCommand = "npm install"
+		config.PostCreateCommand = installCommand(detection, "npm install")
+		if detection.TypeScript && detection.HasBuildStep() {
+			config.PostCreateCommand += " && " + detection.BuildCommand
+		}
 		config.RemoteUser = "node"
 		config.ForwardPorts = []int{3000}
 
@@ -102,7 +315,7 @@ func (g *DevcontainerGenerator) buildConfig(detection *models.Detection, project
 		config.Extensions = []string{
 			"golang.go",
 		}
-		config.PostCreateCommand = "go mod download"
+		config.PostCreateCommand = installCommand(detection, "go mod download")
 		config.RemoteUser = "vscode"
 		config.ForwardPorts = []int{8080}
 
@@ -112,7 +325,7 @@ func (g *DevcontainerGenerator) buildConfig(detection *models.Detection, project
 			"ms-python.python",
 			"ms-python.vscode-pylance",
 		}
-		config.PostCreateCommand = "pip install -r requirements.txt"
+		config.PostCreateCommand = installCommand(detection, "pip install -r requirements.txt")
 		config.RemoteUser = "vscode"
 		config.ForwardPorts = []int{8000}
 
@@ -121,7 +334,7 @@ func (g *DevcontainerGenerator) buildConfig(detection *models.Detection, project
 		config.Extensions = []string{
 			"rust-lang.rust-analyzer",
 		}
-		config.PostCreateCommand = "cargo build"
+		config.PostCreateCommand = installCommand(detection, "cargo build")
 		config.RemoteUser = "vscode"
 		config.ForwardPorts = []int{8080}
 
@@ -130,6 +343,112 @@ func (g *DevcontainerGenerator) buildConfig(detection *models.Detection, project
 		config.RemoteUser = "vscode"
 	}
 
+	// Build the project's own Dockerfile when it has a dev target, running as its user
+	if existing := detection.ExistingDockerfile; detection.ReusesDockerfile() {
+		dockerfile, _ := filepath.Rel(".devcontainer", filepath.FromSlash(existing.File))
+		config.Dockerfile = filepath.ToSlash(dockerfile)
+		config.Target = existing.Target
+		config.RemoteUser = existing.User
+		if config.RemoteUser == "" {
+			config.RemoteUser = "root"
+		}
+	}
+
+	// In prebuild mode, build the generated Dockerfile, whose dependency layer
+	// the prebuilt image caches; Compose builds it already
+	if detection.Prebuild != nil && !config.UseCompose {
+		if config.Dockerfile == "" {
+			config.Dockerfile = "Dockerfile"
+			config.RemoteUser = detection.GetContainerUser()
+			if config.RemoteUser == "" {
+				config.RemoteUser = "root"
+			}
+		}
+		config.CacheFrom = detection.Prebuild.Image
+	}
+
+	// Keep the non-root user in step with the host when running non-root
+	config.UpdateRemoteUserUID = detection.NonRoot && config.RemoteUser != "root"
+
+	// On Windows hosts, keep dependency directories in named volumes; Compose
+	// mounts them in docker-compose.yml instead (see ComposeConfig.WorkspaceVolumes)
+	var targets []string
+	if dirs := detection.DependencyVolumeDirs(); len(dirs) > 0 && !config.UseCompose && !detection.RemoteDocker {
+		config.WorkspaceMount = "source=${localWorkspaceFolder},target=/workspace,type=bind"
+		for _, dir := range dirs {
+			target := "/workspace/" + dir
+			config.Mounts = append(config.Mounts, fmt.Sprintf(
+				"source=${localWorkspaceFolderBasename}-%s,target=%s,type=volume", dependencyVolumeName(dir), target))
+			targets = append(targets, target)
+		}
+	}
+
+	// A remote Docker engine can't bind-mount the project: the workspace is a
+	// volume the sync script fills first; Compose mounts it in docker-compose.yml
+	if detection.RemoteDocker {
+		config.InitializeCommand = "sh " + SyncWorkspaceScript
+		if !config.UseCompose {
+			config.WorkspaceMount = fmt.Sprintf("source=%s,target=/workspace,type=volume", RemoteWorkspaceVolume(projectName))
+		}
+	}
+
+	// Keep shell history and tool caches across rebuilds; Compose mounts them
+	// in docker-compose.yml instead (see ComposeConfig.PersistentVolumes)
+	if !config.UseCompose {
+		for _, volume := range persistentVolumes(detection) {
+			config.Mounts = append(config.Mounts, fmt.Sprintf(
+				"source=${localWorkspaceFolderBasename}-%s,target=%s,type=volume", volume.Name, volume.Target))
+			targets = append(targets, volume.Target)
+		}
+	}
+	config.RemoteEnv = persistentEnv(detection)
+
+	// Forward the host's SSH agent, git config, and gpg-agent, for private
+	// dependencies and signed commits
+	mounts, env := credentialMounts(detection)
+	config.Mounts = append(config.Mounts, mounts...)
+	config.RemoteEnv = append(config.RemoteEnv, env...)
+
+	// Go through the proxy; Compose sets it on the app service instead
+	if detection.Proxy.Enabled() && !config.UseCompose {
+		config.RemoteEnv = append(config.RemoteEnv, proxyEnv(detection.Proxy, noProxy(detection.Proxy, nil),
+			func(_, value string) string { return value })...)
+	}
+
+	// Install from the project's private registries, with the host's tokens
+	config.RemoteEnv = append(config.RemoteEnv, registryEnv(detection.Registries)...)
+	config.RemoteEnv = append(config.RemoteEnv, registryCredentials(detection.Registries)...)
+
+	// New volumes are owned by root; the dev container images include sudo
+	if len(targets) > 0 && config.Dockerfile == "" && config.RemoteUser != "root" {
+		config.PostCreateCommand = joinCommands(
+			fmt.Sprintf("sudo chown %s %s", config.RemoteUser, strings.Join(targets, " ")), config.PostCreateCommand)
+	}
+
+	// Git refuses to work in a workspace owned by another user, as a bind mount
+	// from the host is once the container user is remapped
+	config.PostCreateCommand = joinCommands(gitSafeDirectoryCommand, config.PostCreateCommand)
+	if detection.Forwards(models.ForwardGPG) {
+		config.PostCreateCommand = joinCommands(gpgLinkCommand, config.PostCreateCommand)
+	}
+
+	// Clone the dotfiles last, so they can rely on the installed dependencies
+	if detection.Persistence.Dotfiles != "" {
+		config.PostCreateCommand = joinCommands(config.PostCreateCommand, "bash "+DotfilesScript)
+	}
+
+	// Apply migrations each time the stack starts, once the database accepts connections
+	if config.UseCompose && detection.NeedsMigrations() {
+		config.PostStartCommand = "bash " + MigrateScript
+	}
+
+	if detection.Lifecycle.PostCreate != "" {
+		config.PostCreateCommand = detection.Lifecycle.PostCreate
+	}
+	if detection.Lifecycle.PostStart != "" {
+		config.PostStartCommand = detection.Lifecycle.PostStart
+	}
+
 	// Add service-specific ports
 	for _, service := range detection.Services {
 		switch service {
@@ -137,11 +456,54 @@ func (g *DevcontainerGenerator) buildConfig(detection *models.Detection, project
 			config.ForwardPorts = append(config.ForwardPorts, 5432)
 		case "redis":
 			config.ForwardPorts = append(config.ForwardPorts, 6379)
+		case "clickhouse":
+			config.ForwardPorts = append(config.ForwardPorts, 8123, clickHouseNativePort(detection))
+		case "memcached":
+			config.ForwardPorts = append(config.ForwardPorts, 11211)
+		case "nats":
+			config.ForwardPorts = append(config.ForwardPorts, 4222, 8222)
+		case "influxdb":
+			config.ForwardPorts = append(config.ForwardPorts, 8086)
+		}
+	}
+
+	// Add the WireMock port for the stubs and its /__admin API
+	if detection.NeedsWireMock() {
+		config.ForwardPorts = append(config.ForwardPorts, WireMockPort)
+	}
+
+	// Add the GlitchTip web UI port
+	if detection.NeedsGlitchTip() {
+		config.ForwardPorts = append(config.ForwardPorts, GlitchTipPort)
+	}
+
+	// Add the Gatus status page port
+	if detection.NeedsStatusPage() {
+		config.ForwardPorts = append(config.ForwardPorts, GatusPort)
+	}
+
+	// Add the Temporal frontend and Web UI ports
+	if detection.NeedsTemporal() {
+		config.ForwardPorts = append(config.ForwardPorts, 7233, TemporalUIPort)
+	}
+
+	// Add the test database ports if tests get their own services
+	if detection.Testing.Isolation == models.TestIsolationService {
+		for _, service := range detection.Services {
+			if detection.ExistingServiceFor(service) != "" {
+				continue
+			}
+			switch service {
+			case "postgres":
+				config.ForwardPorts = append(config.ForwardPorts, 5433)
+			case "redis":
+				config.ForwardPorts = append(config.ForwardPorts, 6380)
+			}
 		}
 	}
 
 	// Add Fluent Bit port if logging is detected
-	if detection.HasStructuredLogging() {
+	if detection.NeedsLogSidecar() {
 		config.ForwardPorts = append(config.ForwardPorts, 24224)
 	}
 
@@ -151,36 +513,183 @@ func (g *DevcontainerGenerator) buildConfig(detection *models.Detection, project
 		config.ForwardPorts = append(config.ForwardPorts, 3001)  // Grafana
 	}
 
+	// Add frontend dev server port if a frontend framework is detected
+	if detection.FrontendFramework != "" && detection.FrontendPort > 0 && !containsPort(config.ForwardPorts, detection.FrontendPort) {
+		config.ForwardPorts = append(config.ForwardPorts, detection.FrontendPort)
+	}
+
+	// Label ports that carry WebSocket traffic so upgrades are expected on them.
+	// VS Code port forwarding tunnels WebSocket upgrades on the same port as HTTP.
+	if detection.NeedsWebsockets() {
+		appPort := detection.GetAppPort()
+		if !containsPort(config.ForwardPorts, appPort) {
+			config.ForwardPorts = append(config.ForwardPorts, appPort)
+		}
+		config.PortsAttributes = append(config.PortsAttributes, PortAttributes{
+			Port:  appPort,
+			Label: "App (HTTP + WebSocket)",
+		})
+	}
+	if detection.FrontendFramework != "" && detection.FrontendPort > 0 && detection.FrontendPort != detection.GetAppPort() {
+		config.PortsAttributes = append(config.PortsAttributes, PortAttributes{
+			Port:  detection.FrontendPort,
+			Label: "Frontend dev server (HMR WebSocket)",
+		})
+	}
+
+	// Forward the gRPC port without opening a browser (HTTP/2, not a web page)
+	// and the grpcui port for interactive exploration
+	if detection.NeedsGRPC() {
+		grpcPort := detection.GetGRPCPort()
+		if !containsPort(config.ForwardPorts, grpcPort) {
+			config.ForwardPorts = append(config.ForwardPorts, grpcPort)
+		}
+		config.ForwardPorts = append(config.ForwardPorts, 8082) // grpcui
+		config.PortsAttributes = append(config.PortsAttributes, PortAttributes{
+			Port:          grpcPort,
+			Label:         "gRPC (HTTP/2)",
+			OnAutoForward: "silent",
+		}, PortAttributes{
+			Port:  8082,
+			Label: "grpcui",
+		})
+	}
+
+	// Add the queue dashboard port (Bull Board, Flower, or Asynqmon) for the worker's queues
+	if dashboard := detection.GetQueueDashboard(); dashboard != "" {
+		port := detection.GetQueueDashboardPort()
+		config.ForwardPorts = append(config.ForwardPorts, port)
+		config.PortsAttributes = append(config.PortsAttributes, PortAttributes{
+			Port:  port,
+			Label: queueDashboardNames[dashboard],
+		})
+	}
+
+	// Add the RabbitMQ management UI port if it holds the dead-letter queue
+	if detection.NeedsDeadLetter() && usesRabbitMQ(detection.QueueLibraries) {
+		config.ForwardPorts = append(config.ForwardPorts, 15672) // RabbitMQ management
+	}
+
+	// Add Keycloak port if an auth provider is needed
+	if detection.NeedsAuthProvider() {
+		config.ForwardPorts = append(config.ForwardPorts, 8180) // Keycloak
+	}
+
+	// Add LocalStack edge port if AWS services are emulated
+	if detection.NeedsLocalStack() {
+		config.ForwardPorts = append(config.ForwardPorts, 4566) // LocalStack
+	}
+
+	// Add MinIO ports if uploads go through an S3 bucket on MinIO
+	if detection.NeedsMinIO() {
+		config.ForwardPorts = append(config.ForwardPorts, 9000, 9001) // MinIO API, console
+	}
+
+	// Add vector database port (pgvector shares the postgres port)
+	switch detection.GetVectorStore() {
+	case "qdrant":
+		config.ForwardPorts = append(config.ForwardPorts, 6333) // Qdrant REST + dashboard
+	case "chroma":
+		config.ForwardPorts = append(config.ForwardPorts, 8001) // Chroma
+	}
+
+	// Add the Mailpit web UI port
+	if detection.NeedsMailpit() {
+		config.ForwardPorts = append(config.ForwardPorts, MailpitPort)
+	}
+
+	// Add the search engine's API port
+	if search := searchConfig(detection); search.Enabled {
+		config.ForwardPorts = append(config.ForwardPorts, search.Port)
+	}
+
+	// Add Ollama API port if local LLM inference is enabled
+	if detection.NeedsOllama() {
+		config.ForwardPorts = append(config.ForwardPorts, 11434) // Ollama
+	}
+
+	// Add the Selenium Grid and noVNC ports if WebDriver tests are detected
+	if detection.NeedsSeleniumGrid() {
+		config.ForwardPorts = append(config.ForwardPorts, 4444) // Selenium Grid
+		config.PortsAttributes = append(config.PortsAttributes, PortAttributes{
+			Port:  4444,
+			Label: "Selenium Grid",
+		})
+		for _, node := range seleniumNodes {
+			config.ForwardPorts = append(config.ForwardPorts, node.VNCPort)
+			config.PortsAttributes = append(config.PortsAttributes, PortAttributes{
+				Port:  node.VNCPort,
+				Label: node.Name + " (noVNC)",
+			})
+		}
+	}
+
 	// Add Jaeger port if tracing is detected
 	if detection.NeedsTracing() {
 		config.ForwardPorts = append(config.ForwardPorts, 16686) // Jaeger UI
 	}
 
+	dedupePorts(config)
+
+	if detection.TargetsCodespaces() {
+		applyCodespaces(config, detection)
+	}
+
 	return config
 }
 
+// ForwardPorts returns the ports devcontainer.json forwards for a Detection.
+func (g *DevcontainerGenerator) ForwardPorts(detection *models.Detection) []int {
+	return g.buildConfig(detection, "").ForwardPorts
+}
+
+// dedupePorts drops repeated forwarded ports and port attributes, keeping the
+// first of each, so devcontainer.json has no duplicate portsAttributes keys.
+func dedupePorts(config *DevcontainerConfig) {
+	var ports []int
+	for _, port := range config.ForwardPorts {
+		if !containsPort(ports, port) {
+			ports = append(ports, port)
+		}
+	}
+	config.ForwardPorts = ports
+
+	var attributes []PortAttributes
+	for _, attr := range config.PortsAttributes {
+		if !hasPortAttributes(attributes, attr.Port) {
+			attributes = append(attributes, attr)
+		}
+	}
+	config.PortsAttributes = attributes
+}
+
+// containsPort checks if a port is already in the list.
+func containsPort(ports []int, port int) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
 // render executes the template with the given config.
 func (g *DevcontainerGenerator) render(config *DevcontainerConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("devcontainer.json.tmpl")
+	content, err := renderTemplate("devcontainer.json.tmpl", config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load template: %w", err)
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
+		return nil, err
 	}
 
 	// Validate JSON output
 	var js json.RawMessage
-	if err := json.Unmarshal(buf.Bytes(), &js); err != nil {
-		return nil, fmt.Errorf("generated invalid JSON: %w", err)
+	if err := json.Unmarshal(content, &js); err != nil {
+		return nil, &TemplateError{Template: "devcontainer.json.tmpl", Err: fmt.Errorf("generated invalid JSON: %w", err)}
 	}
 
 	// Pretty-print the JSON
 	var prettyBuf bytes.Buffer
-	if err := json.Indent(&prettyBuf, buf.Bytes(), "", "\t"); err != nil {
-		return buf.Bytes(), nil // Return original if pretty-print fails
+	if err := json.Indent(&prettyBuf, content, "", "\t"); err != nil {
+		return content, nil // Return original if pretty-print fails
 	}
 
 	return prettyBuf.Bytes(), nil
@@ -2,6 +2,7 @@ package generator
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -34,7 +35,7 @@ func TestDevcontainerGenerator_GenerateContent(t *testing.T) {
 			wantUser:    "node",
 			wantPorts:   []int{3000},
 			wantExts:    []string{"dbaeumer.vscode-eslint"},
-			wantCmd:     "npm install",
+			wantCmd:     "git config --global --add safe.directory /workspace && npm install",
 		},
 		{
 			name: "go project",
@@ -48,7 +49,7 @@ func TestDevcontainerGenerator_GenerateContent(t *testing.T) {
 			wantUser:    "vscode",
 			wantPorts:   []int{8080},
 			wantExts:    []string{"golang.go"},
-			wantCmd:     "go mod download",
+			wantCmd:     "git config --global --add safe.directory /workspace && go mod download",
 		},
 		{
 			name: "python project",
@@ -62,7 +63,7 @@ func TestDevcontainerGenerator_GenerateContent(t *testing.T) {
 			wantUser:    "vscode",
 			wantPorts:   []int{8000},
 			wantExts:    []string{"ms-python.python", "ms-python.vscode-pylance"},
-			wantCmd:     "pip install -r requirements.txt",
+			wantCmd:     "git config --global --add safe.directory /workspace && pip install -r requirements.txt",
 		},
 		{
 			name: "rust project",
@@ -76,7 +77,7 @@ func TestDevcontainerGenerator_GenerateContent(t *testing.T) {
 			wantUser:    "vscode",
 			wantPorts:   []int{8080},
 			wantExts:    []string{"rust-lang.rust-analyzer"},
-			wantCmd:     "cargo build",
+			wantCmd:     "git config --global --add safe.directory /workspace && cargo build",
 		},
 		{
 			name: "unknown language falls back to base",
@@ -105,7 +106,7 @@ func TestDevcontainerGenerator_GenerateContent(t *testing.T) {
 			wantUser:    "node",
 			wantPorts:   []int{3000, 5432},
 			wantExts:    []string{"dbaeumer.vscode-eslint"},
-			wantCmd:     "npm install",
+			wantCmd:     "git config --global --add safe.directory /workspace && npm install",
 		},
 		{
 			name: "go with redis service",
@@ -120,7 +121,7 @@ func TestDevcontainerGenerator_GenerateContent(t *testing.T) {
 			wantUser:    "vscode",
 			wantPorts:   []int{8080, 6379},
 			wantExts:    []string{"golang.go"},
-			wantCmd:     "go mod download",
+			wantCmd:     "git config --global --add safe.directory /workspace && go mod download",
 		},
 		{
 			name: "python with multiple services",
@@ -135,7 +136,7 @@ func TestDevcontainerGenerator_GenerateContent(t *testing.T) {
 			wantUser:    "vscode",
 			wantPorts:   []int{8000, 5432, 6379},
 			wantExts:    []string{"ms-python.python", "ms-python.vscode-pylance"},
-			wantCmd:     "pip install -r requirements.txt",
+			wantCmd:     "git config --global --add safe.directory /workspace && pip install -r requirements.txt",
 		},
 	}
 
@@ -322,3 +323,289 @@ func TestBuildConfig(t *testing.T) {
 		t.Errorf("ForwardPorts count = %d, want 3", len(config.ForwardPorts))
 	}
 }
+
+// TestDevcontainerGenerator_WebsocketPorts tests that WebSocket ports are labelled.
+func TestDevcontainerGenerator_WebsocketPorts(t *testing.T) {
+	gen := NewDevcontainerGenerator()
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Capabilities: models.Capabilities{
+			WebsocketLibraries: []string{"channels"},
+		},
+	}
+
+	content, err := gen.GenerateContent(detection, "chat-app")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(content, &result); err != nil {
+		t.Fatalf("Generated invalid JSON: %v", err)
+	}
+
+	attrs, ok := result["portsAttributes"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("portsAttributes not found in:\n%s", content)
+	}
+	appPort, ok := attrs["8000"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("portsAttributes missing app port 8000: %v", attrs)
+	}
+	if appPort["label"] != "App (HTTP + WebSocket)" {
+		t.Errorf("label = %v, want %q", appPort["label"], "App (HTTP + WebSocket)")
+	}
+
+	// Without WebSocket libraries there should be no portsAttributes
+	detection.WebsocketLibraries = nil
+	content, err = gen.GenerateContent(detection, "chat-app")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	var withoutWebsockets map[string]interface{}
+	if err := json.Unmarshal(content, &withoutWebsockets); err != nil {
+		t.Fatalf("Generated invalid JSON: %v", err)
+	}
+	if _, ok := withoutWebsockets["portsAttributes"]; ok {
+		t.Error("portsAttributes should be omitted when no WebSocket library is detected")
+	}
+}
+
+// TestDevcontainerGenerator_ExistingDockerfile tests building from the project's own Dockerfile.
+func TestDevcontainerGenerator_ExistingDockerfile(t *testing.T) {
+	detection := &models.Detection{
+		Language: "go",
+		Version:  "1.22",
+		ExistingDockerfile: &models.ExistingDockerfile{
+			File:   "Dockerfile",
+			Target: "dev",
+		},
+	}
+
+	content, err := NewDevcontainerGenerator().GenerateContent(detection, "api")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	var result struct {
+		Image      string            `json:"image"`
+		Build      map[string]string `json:"build"`
+		RemoteUser string            `json:"remoteUser"`
+	}
+	if err := json.Unmarshal(content, &result); err != nil {
+		t.Fatalf("Generated invalid JSON: %v\n%s", err, content)
+	}
+
+	if result.Image != "" {
+		t.Errorf("image = %q, want none when building the project's Dockerfile", result.Image)
+	}
+	want := map[string]string{"dockerfile": "../Dockerfile", "context": "..", "target": "dev"}
+	for key, value := range want {
+		if result.Build[key] != value {
+			t.Errorf("build.%s = %q, want %q", key, result.Build[key], value)
+		}
+	}
+	if result.RemoteUser != "root" {
+		t.Errorf("remoteUser = %q, want root for a Dockerfile without USER", result.RemoteUser)
+	}
+
+	// A Dockerfile that can't be used for development falls back to the image
+	detection.ExistingDockerfile.Problem = "multi-stage build has no dev stage"
+	content, err = NewDevcontainerGenerator().GenerateContent(detection, "api")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	result.Image = ""
+	if err := json.Unmarshal(content, &result); err != nil {
+		t.Fatalf("Generated invalid JSON: %v\n%s", err, content)
+	}
+	if result.Image != "mcr.microsoft.com/devcontainers/go:1.22" {
+		t.Errorf("image = %q, want the Go devcontainer image when the Dockerfile can't be reused", result.Image)
+	}
+}
+
+// TestDevcontainerGenerator_NonRoot tests that the remote user follows the host UID/GID.
+func TestDevcontainerGenerator_NonRoot(t *testing.T) {
+	tests := []struct {
+		name       string
+		detection  *models.Detection
+		wantUser   string
+		wantUpdate bool
+	}{
+		{
+			name:       "go with services",
+			detection:  &models.Detection{Language: "go", Version: "1.23", Services: []string{"postgres"}, NonRoot: true},
+			wantUser:   "vscode",
+			wantUpdate: true,
+		},
+		{
+			name:       "node",
+			detection:  &models.Detection{Language: "node", Version: "20", NonRoot: true},
+			wantUser:   "node",
+			wantUpdate: true,
+		},
+		{
+			name: "reused Dockerfile without USER",
+			detection: &models.Detection{
+				Language:           "go",
+				Version:            "1.23",
+				NonRoot:            true,
+				ExistingDockerfile: &models.ExistingDockerfile{File: "Dockerfile", Target: "dev"},
+			},
+			wantUser: "root",
+		},
+		{
+			name:      "off by default",
+			detection: &models.Detection{Language: "go", Version: "1.23"},
+			wantUser:  "vscode",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := NewDevcontainerGenerator().GenerateContent(tt.detection, "api")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+
+			var result struct {
+				RemoteUser          string `json:"remoteUser"`
+				UpdateRemoteUserUID bool   `json:"updateRemoteUserUID"`
+			}
+			if err := json.Unmarshal(content, &result); err != nil {
+				t.Fatalf("Generated invalid JSON: %v\n%s", err, content)
+			}
+			if result.RemoteUser != tt.wantUser {
+				t.Errorf("remoteUser = %q, want %q", result.RemoteUser, tt.wantUser)
+			}
+			if result.UpdateRemoteUserUID != tt.wantUpdate {
+				t.Errorf("updateRemoteUserUID = %v, want %v", result.UpdateRemoteUserUID, tt.wantUpdate)
+			}
+		})
+	}
+}
+
+// TestDevcontainerGenerator_MergeExisting tests updating an existing devcontainer.json.
+func TestDevcontainerGenerator_MergeExisting(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  string
+		detection *models.Detection
+		check     func(t *testing.T, result map[string]interface{})
+	}{
+		{
+			name: "compose keys replace the image, user settings are kept",
+			existing: `{
+	// team settings
+	"name": "my api",
+	"image": "mcr.microsoft.com/devcontainers/go:1.22",
+	"features": {"ghcr.io/devcontainers/features/github-cli:1": {}},
+	"customizations": {"vscode": {"extensions": ["eamodio.gitlens"], "settings": {"editor.formatOnSave": true}}},
+	"forwardPorts": [8080, "docs:4000"],
+	"remoteUser": "dev",
+}`,
+			detection: &models.Detection{Language: "go", Version: "1.22", Services: []string{"postgres"}},
+			check: func(t *testing.T, result map[string]interface{}) {
+				if result["name"] != "my api" || result["remoteUser"] != "dev" {
+					t.Errorf("user name and remoteUser should be kept, got %v and %v", result["name"], result["remoteUser"])
+				}
+				if _, ok := result["image"]; ok {
+					t.Error("image should be dropped in favor of dockerComposeFile")
+				}
+				if result["dockerComposeFile"] != "docker-compose.yml" || result["service"] != "app" {
+					t.Errorf("compose keys = %v, %v", result["dockerComposeFile"], result["service"])
+				}
+				if _, ok := result["features"].(map[string]interface{})["ghcr.io/devcontainers/features/github-cli:1"]; !ok {
+					t.Error("features should be kept")
+				}
+				vscode := result["customizations"].(map[string]interface{})["vscode"].(map[string]interface{})
+				if got := fmt.Sprint(vscode["extensions"]); got != "[eamodio.gitlens golang.go]" {
+					t.Errorf("extensions = %s, want user's followed by dockstart's", got)
+				}
+				if _, ok := vscode["settings"]; !ok {
+					t.Error("vscode settings should be kept")
+				}
+				if got := fmt.Sprint(result["forwardPorts"]); got != "[8080 docs:4000 5432]" {
+					t.Errorf("forwardPorts = %s, want [8080 docs:4000 5432]", got)
+				}
+			},
+		},
+		{
+			name:      "user's own container is kept without compose",
+			existing:  `{"build": {"dockerfile": "Dockerfile"}, "postCreateCommand": "make setup"}`,
+			detection: &models.Detection{Language: "node", Version: "20"},
+			check: func(t *testing.T, result map[string]interface{}) {
+				if _, ok := result["image"]; ok {
+					t.Error("image should not be added when the user builds a Dockerfile")
+				}
+				if _, ok := result["workspaceFolder"]; ok {
+					t.Error("workspaceFolder should not be added for the user's own container")
+				}
+				if result["postCreateCommand"] != "make setup" {
+					t.Errorf("postCreateCommand = %v, want the user's", result["postCreateCommand"])
+				}
+				if result["remoteUser"] != "node" {
+					t.Errorf("remoteUser = %v, want node added", result["remoteUser"])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "dockstart-devcontainer-test-*")
+			if err != nil {
+				t.Fatalf("Failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			path := filepath.Join(tmpDir, ".devcontainer", "devcontainer.json")
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatalf("Failed to create .devcontainer: %v", err)
+			}
+			if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+				t.Fatalf("Failed to write devcontainer.json: %v", err)
+			}
+
+			if err := NewDevcontainerGenerator().Generate(tt.detection, tmpDir, "api"); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read devcontainer.json: %v", err)
+			}
+			var result map[string]interface{}
+			if err := json.Unmarshal(content, &result); err != nil {
+				t.Fatalf("Merged devcontainer.json is invalid JSON: %v\n%s", err, content)
+			}
+			tt.check(t, result)
+		})
+	}
+}
+
+// TestDevcontainerGenerator_MergeInvalid tests that an unparsable devcontainer.json is not replaced.
+func TestDevcontainerGenerator_MergeInvalid(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "dockstart-devcontainer-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	path := filepath.Join(tmpDir, ".devcontainer", "devcontainer.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create .devcontainer: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{"name": `), 0644); err != nil {
+		t.Fatalf("Failed to write devcontainer.json: %v", err)
+	}
+
+	detection := &models.Detection{Language: "go", Version: "1.22"}
+	if err := NewDevcontainerGenerator().Generate(detection, tmpDir, "api"); err == nil {
+		t.Error("Generate() should fail on an unparsable devcontainer.json")
+	}
+	if content, _ := os.ReadFile(path); string(content) != `{"name": ` {
+		t.Errorf("devcontainer.json should be left alone, got %s", content)
+	}
+}
@@ -1,10 +1,12 @@
 package generator
 
 import (
-	"bytes"
 	"fmt"
-	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/jpequegn/dockstart/internal/models"
 )
@@ -17,18 +19,131 @@ type DockerfileConfig struct {
 	// BaseImage is the Docker base image (e.g., "node:20", "golang:1.23")
 	BaseImage string
 
+	// VersionArg is the build arg that selects the base image's tag (e.g.,
+	// "NODE_VERSION"), declared before FROM with the detected version as its
+	// default when docker-bake.hcl builds the image. Empty to build FROM
+	// BaseImage as is
+	VersionArg string
+
+	// Version is VersionArg's default, the tag of BaseImage
+	Version string
+
 	// PackageManager is the OS package manager command (apt-get, apk, etc.)
 	PackageManager string
 
 	// CacheCleanup is the command to clean package cache (varies by OS)
 	CacheCleanup string
 
+	// RegistryEnv points the package managers at private registries, with
+	// values quoted for ENV instructions
+	RegistryEnv []EnvVar
+
+	// Timezone is the IANA timezone set with TZ (e.g., "Europe/Paris"), or
+	// empty for the base image's UTC
+	Timezone string
+
+	// Locale is the locale generated in the image and set with LANG and
+	// LC_ALL (e.g., "fr_FR.UTF-8"), or empty for the base image's
+	Locale string
+
+	// GDAL installs the GDAL library and headers that the detected geospatial
+	// libraries build against
+	GDAL bool
+
+	// GDALPython pins the Python GDAL bindings to the installed library's version
+	GDALPython bool
+
 	// PostInstall is optional language-specific setup commands
 	PostInstall string
+
+	// TypeScriptTools is the list of npm packages to install globally for TypeScript
+	// projects (e.g., "typescript tsx"). Empty for non-TypeScript projects
+	TypeScriptTools string
+
+	// BuildCommand is the command that compiles the project (e.g., "npm run build")
+	BuildCommand string
+
+	// BuildOutputDir is the directory containing compiled output (e.g., "dist")
+	BuildOutputDir string
+
+	// WorkerStage adds a "worker" stage built on the "app" stage, for workers
+	// that need their own build settings or entrypoint
+	WorkerStage bool
+
+	// WorkerEnv holds the variables set in the worker stage, sorted by name
+	WorkerEnv []EnvVar
+
+	// WorkerCmd is the worker stage's CMD in exec form (e.g., ["sh", "-c", "..."])
+	WorkerCmd string
+
+	// User is the non-root user the image runs as, created (or remapped, when the
+	// base image already has it) with the USER_UID/USER_GID build args.
+	// Empty to run as root
+	User string
+
+	// UserSetup is optional language-specific setup for the non-root user
+	// (e.g., handing it the toolchain's cache directories)
+	UserSetup string
+
+	// DependencyDirs are the paths mounted as named volumes: dependency directories
+	// in Windows mode (e.g., "/workspace/node_modules") and persisted history and
+	// caches, space-separated. They are created in the image so the volumes start
+	// out owned by the non-root user
+	DependencyDirs string
+
+	// PrebuildTools installs the package manager the dependency layer runs, as
+	// root (e.g., "corepack enable"). Empty when the base image has it
+	PrebuildTools string
+
+	// PrebuildFiles are the manifests and lockfiles copied before the dependency
+	// layer (e.g., "go.mod go.sum*"). Empty outside prebuild mode
+	PrebuildFiles string
+
+	// PrebuildInstall installs the dependencies from PrebuildFiles alone, in a
+	// layer cached until they change
+	PrebuildInstall string
+}
+
+// From returns the image the Dockerfile builds FROM: BaseImage, with its tag
+// replaced by VersionArg when set.
+func (c *DockerfileConfig) From() string {
+	if c.VersionArg == "" {
+		return c.BaseImage
+	}
+	image, _, _ := strings.Cut(c.BaseImage, ":")
+	return fmt.Sprintf("%s:${%s}", image, c.VersionArg)
+}
+
+// LocaleInput returns the localedef input of Locale, or "" when the locale
+// is built in and needs no generating.
+func (c *DockerfileConfig) LocaleInput() string {
+	return localeInput(c.Locale)
+}
+
+// LocalePackages returns the OS packages Timezone and Locale need, space-separated.
+func (c *DockerfileConfig) LocalePackages() string {
+	var packages []string
+	if c.Timezone != "" {
+		packages = append(packages, "tzdata")
+	}
+	if c.LocaleInput() != "" {
+		packages = append(packages, "locales")
+	}
+	return strings.Join(packages, " ")
+}
+
+// EnvVar is an environment variable set in a Dockerfile.
+type EnvVar struct {
+	Name string
+
+	// Value is quoted for an ENV instruction
+	Value string
 }
 
 // DockerfileGenerator generates Dockerfile files.
-type DockerfileGenerator struct{}
+type DockerfileGenerator struct {
+	output
+}
 
 // NewDockerfileGenerator creates a new dockerfile generator.
 func NewDockerfileGenerator() *DockerfileGenerator {
@@ -42,19 +157,19 @@ func (g *DockerfileGenerator) Generate(detection *models.Detection, projectPath
 
 	// Create .devcontainer directory (may already exist)
 	devcontainerDir := filepath.Join(projectPath, ".devcontainer")
-	if err := os.MkdirAll(devcontainerDir, 0755); err != nil {
+	if err := g.fs().MkdirAll(devcontainerDir, 0755); err != nil {
 		return fmt.Errorf("failed to create .devcontainer directory: %w", err)
 	}
 
 	// Generate Dockerfile content
 	content, err := g.render(config)
 	if err != nil {
-		return fmt.Errorf("failed to render template: %w", err)
+		return err
 	}
 
 	// Write to file
 	outputPath := filepath.Join(devcontainerDir, "Dockerfile")
-	if err := os.WriteFile(outputPath, content, 0644); err != nil {
+	if err := g.fs().WriteFile(outputPath, content, 0644); err != nil {
 		return fmt.Errorf("failed to write Dockerfile: %w", err)
 	}
 
@@ -68,6 +183,11 @@ func (g *DockerfileGenerator) GenerateContent(detection *models.Detection, proje
 	return g.render(config)
 }
 
+// BaseImage returns the image the generated Dockerfile builds FROM.
+func (g *DockerfileGenerator) BaseImage(detection *models.Detection) string {
+	return g.buildConfig(detection, "").BaseImage
+}
+
 // buildConfig creates a DockerfileConfig from a Detection.
 func (g *DockerfileGenerator) buildConfig(detection *models.Detection, projectName string) *DockerfileConfig {
 	config := &DockerfileConfig{
@@ -83,6 +203,14 @@ func (g *DockerfileGenerator) buildConfig(detection *models.Detection, projectNa
 		config.PackageManager = "apt-get"
 		config.CacheCleanup = "/var/lib/apt/lists/*"
 		// npm is already available in the node image
+		if detection.TypeScript {
+			config.TypeScriptTools = "typescript"
+			if detection.DevRunner != "" {
+				config.TypeScriptTools += " " + detection.DevRunner
+			}
+			config.BuildCommand = detection.BuildCommand
+			config.BuildOutputDir = detection.GetBuildOutputDir()
+		}
 
 	case "go":
 		// Go - using official golang image (Debian-based)
@@ -98,6 +226,8 @@ func (g *DockerfileGenerator) buildConfig(detection *models.Detection, projectNa
 		config.CacheCleanup = "/var/lib/apt/lists/*"
 		// pip is already available in the python image
 		config.PostInstall = "RUN pip install --upgrade pip"
+		// pip falls back to user installs, whose scripts land in ~/.local/bin
+		config.UserSetup = `ENV PATH="/home/$USERNAME/.local/bin:$PATH"`
 
 	case "rust":
 		// Rust - using official rust image (Debian-based)
@@ -106,6 +236,8 @@ func (g *DockerfileGenerator) buildConfig(detection *models.Detection, projectNa
 		config.CacheCleanup = "/var/lib/apt/lists/*"
 		// rustup, cargo, and rustc are already available
 		config.PostInstall = "RUN rustup component add rustfmt clippy"
+		// cargo writes its registry and build tools under CARGO_HOME
+		config.UserSetup = `RUN chown -R "$USER_UID:$USER_GID" "$CARGO_HOME" "$RUSTUP_HOME"`
 
 	default:
 		// Default to Ubuntu for unknown languages
@@ -114,20 +246,59 @@ func (g *DockerfileGenerator) buildConfig(detection *models.Detection, projectNa
 		config.CacheCleanup = "/var/lib/apt/lists/*"
 	}
 
-	return config
-}
+	// Install from the project's private registries
+	config.RegistryEnv = quoteEnv(registryEnv(detection.Registries))
+	config.Timezone = detection.Timezone
+	config.Locale = detection.Locale
+	config.GDAL = detection.NeedsGDAL()
+	config.GDALPython = config.GDAL && detection.Language == "python"
 
-// render executes the template with the given config.
-func (g *DockerfileGenerator) render(config *DockerfileConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("Dockerfile.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load template: %w", err)
+	// docker-bake.hcl passes the language version as a build arg
+	if arg, ok := bakeVersionArgs[detection.Language]; ok && detection.BuildsMultipleImages() {
+		config.VersionArg = arg
+		config.Version = detection.Version
+	}
+
+	// Run as a non-root user matching the host UID/GID
+	config.User = detection.GetContainerUser()
+	if config.User == "" {
+		config.UserSetup = ""
+	}
+	if config.User != "" {
+		var dirs []string
+		for _, dir := range detection.DependencyVolumeDirs() {
+			dirs = append(dirs, path.Join("/workspace", dir))
+		}
+		for _, volume := range persistentVolumes(detection) {
+			dirs = append(dirs, volume.Target)
+		}
+		config.DependencyDirs = strings.Join(dirs, " ")
+	}
+
+	// Install the dependencies before the sources change, in prebuild mode
+	if layer, ok := dependencyLayer(detection); ok {
+		config.PrebuildTools = layer.Tools
+		config.PrebuildFiles = layer.Files
+		config.PrebuildInstall = layer.Install
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
+	// Give the worker its own stage when configured
+	if detection.WorkerStage() == "worker" {
+		config.WorkerStage = true
+		for name, value := range detection.Worker.Env {
+			config.WorkerEnv = append(config.WorkerEnv, EnvVar{Name: name, Value: strconv.Quote(value)})
+		}
+		sort.Slice(config.WorkerEnv, func(i, j int) bool {
+			return config.WorkerEnv[i].Name < config.WorkerEnv[j].Name
+		})
+		cmd, _ := marshalJSON([]string{"sh", "-c", detection.WorkerCommand})
+		config.WorkerCmd = string(cmd)
 	}
 
-	return buf.Bytes(), nil
+	return config
+}
+
+// render executes the template with the given config.
+func (g *DockerfileGenerator) render(config *DockerfileConfig) ([]byte, error) {
+	return renderTemplate("Dockerfile.tmpl", config)
 }
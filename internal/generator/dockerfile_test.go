@@ -104,6 +104,49 @@ func TestDockerfileGenerator_GenerateContent(t *testing.T) {
 				"pip install",
 			},
 		},
+		{
+			name: "typescript project with build script",
+			detection: &models.Detection{
+				Language:       "node",
+				Version:        "20",
+				Confidence:     1.0,
+				TypeScript:     true,
+				BuildCommand:   "npm run build",
+				BuildOutputDir: "build",
+				DevRunner:      "tsx",
+			},
+			projectName: "my-ts-app",
+			wantImage:   "node:20",
+			wantPkgMgr:  "apt-get",
+			wantInFile: []string{
+				"FROM node:20",
+				"RUN npm install -g typescript tsx",
+				"# `npm run build` compiles them to build/ in devcontainer.json's\n# postCreateCommand",
+			},
+			dontWant: []string{
+				"pip install",
+				"ENV BUILD_",
+				"RUN npm run build",
+			},
+		},
+		{
+			name: "typescript project without build script",
+			detection: &models.Detection{
+				Language:   "node",
+				Version:    "20",
+				Confidence: 1.0,
+				TypeScript: true,
+			},
+			projectName: "my-ts-app",
+			wantImage:   "node:20",
+			wantPkgMgr:  "apt-get",
+			wantInFile: []string{
+				"RUN npm install -g typescript\n",
+			},
+			dontWant: []string{
+				"postCreateCommand",
+			},
+		},
 		{
 			name: "unknown language defaults to ubuntu",
 			detection: &models.Detection{
@@ -337,3 +380,116 @@ func TestDockerfileGenerator_SleepCommand(t *testing.T) {
 		t.Error("Dockerfile should use 'sleep infinity' as default command")
 	}
 }
+
+// TestDockerfileGenerator_WorkerStage tests the separate app and worker stages.
+func TestDockerfileGenerator_WorkerStage(t *testing.T) {
+	gen := NewDockerfileGenerator()
+	detection := &models.Detection{
+		Language: "go",
+		Version:  "1.22",
+		Capabilities: models.Capabilities{
+			QueueLibraries: []string{"asynq"},
+		},
+		WorkerCommand: "go run ./cmd/worker && echo done",
+		Worker: models.WorkerOptions{
+			Target: true,
+			Env:    map[string]string{"GOFLAGS": "-tags=worker", "CGO_ENABLED": "0"},
+		},
+	}
+
+	content, err := gen.GenerateContent(detection, "jobs")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	dockerfile := string(content)
+
+	wantParts := []string{
+		// The app and worker images are built with docker-bake.hcl, which sets GO_VERSION
+		"ARG GO_VERSION=1.22\nFROM golang:${GO_VERSION} AS app\n",
+		`CMD ["sleep", "infinity"]`,
+		"FROM app AS worker\nENV CGO_ENABLED=\"0\"\nENV GOFLAGS=\"-tags=worker\"\n",
+		`CMD ["sh","-c","go run ./cmd/worker && echo done"]` + "\n",
+	}
+	for _, part := range wantParts {
+		if !strings.Contains(dockerfile, part) {
+			t.Errorf("Dockerfile should contain %q:\n%s", part, dockerfile)
+		}
+	}
+
+	// Without a worker, the target setting has no effect
+	detection.QueueLibraries = nil
+	content, err = gen.GenerateContent(detection, "jobs")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if strings.Contains(string(content), " AS ") {
+		t.Errorf("Dockerfile should have a single stage without a worker:\n%s", content)
+	}
+}
+
+func TestDockerfileGenerator_NonRoot(t *testing.T) {
+	tests := []struct {
+		name      string
+		detection *models.Detection
+		wantParts []string
+		dontWant  []string
+	}{
+		{
+			name:      "node remaps the node user",
+			detection: &models.Detection{Language: "node", Version: "20", NonRoot: true},
+			wantParts: []string{
+				"    sudo \\\n",
+				"ARG USERNAME=node\nARG USER_UID=1000\nARG USER_GID=1000\n",
+				`usermod --non-unique --uid "$USER_UID" --gid "$USER_GID" "$USERNAME"`,
+				`chown "$USER_UID:$USER_GID" /workspace`,
+				"USER $USERNAME\n\n# Default command",
+			},
+			dontWant: []string{"CARGO_HOME", ".local/bin"},
+		},
+		{
+			name:      "rust hands over the cargo home",
+			detection: &models.Detection{Language: "rust", Version: "1.75", NonRoot: true},
+			wantParts: []string{
+				"ARG USERNAME=vscode\n",
+				`useradd --non-unique --uid "$USER_UID" --gid "$USER_GID" --create-home --shell /bin/bash "$USERNAME"`,
+				`RUN chown -R "$USER_UID:$USER_GID" "$CARGO_HOME" "$RUSTUP_HOME"` + "\nUSER $USERNAME\n",
+			},
+		},
+		{
+			name:      "python puts user installs on the path",
+			detection: &models.Detection{Language: "python", Version: "3.12", NonRoot: true},
+			wantParts: []string{
+				"ARG USERNAME=vscode\n",
+				`ENV PATH="/home/$USERNAME/.local/bin:$PATH"` + "\nUSER $USERNAME\n",
+			},
+		},
+		{
+			name:      "root by default",
+			detection: &models.Detection{Language: "rust", Version: "1.75"},
+			dontWant:  []string{"sudo", "USER_UID", "USER ", "CARGO_HOME"},
+		},
+	}
+
+	gen := NewDockerfileGenerator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := gen.GenerateContent(tt.detection, "myapp")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+			dockerfile := string(content)
+
+			for _, want := range tt.wantParts {
+				if !strings.Contains(dockerfile, want) {
+					t.Errorf("Dockerfile should contain %q:\n%s", want, dockerfile)
+				}
+			}
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(dockerfile, dontWant) {
+					t.Errorf("Dockerfile should NOT contain %q:\n%s", dontWant, dockerfile)
+				}
+			}
+		})
+	}
+}
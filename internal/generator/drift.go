@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// Drift is a detected fact of the project that changed since the .devcontainer
+// files were generated.
+type Drift struct {
+	// Field names what changed (e.g., "services", "queue libraries")
+	Field string
+
+	// Added and Removed are the changes to a list
+	Added   []string
+	Removed []string
+
+	// Was and Now are the old and new values of a single value
+	Was string
+	Now string
+}
+
+// String formats the drift for messages (e.g., "services: +redis -mysql",
+// "version: 3.11 → 3.12").
+func (d Drift) String() string {
+	if d.Added == nil && d.Removed == nil {
+		return fmt.Sprintf("%s: %s → %s", d.Field, valueOrNone(d.Was), valueOrNone(d.Now))
+	}
+	var changes []string
+	for _, item := range d.Added {
+		changes = append(changes, "+"+item)
+	}
+	for _, item := range d.Removed {
+		changes = append(changes, "-"+item)
+	}
+	return d.Field + ": " + strings.Join(changes, " ")
+}
+
+// driftLists are the detected lists the generated files depend on, besides
+// the capability libraries, which are all compared.
+var driftLists = []struct {
+	field string
+	list  func(*models.Detection) []string
+}{
+	{"services", func(d *models.Detection) []string { return d.Services }},
+	{"service versions", func(d *models.Detection) []string { return serviceVersionEntries(d.ServiceVersions) }},
+	{"external APIs", func(d *models.Detection) []string { return d.ExternalAPIs }},
+}
+
+// driftValues are the detected values the generated files depend on.
+var driftValues = []struct {
+	field string
+	value func(*models.Detection) string
+}{
+	{"language", func(d *models.Detection) string { return d.Language }},
+	{"version", func(d *models.Detection) string { return d.Version }},
+	{"package manager", func(d *models.Detection) string { return d.PackageManager }},
+	{"install command", func(d *models.Detection) string { return d.InstallCommand }},
+	{"build command", func(d *models.Detection) string { return d.BuildCommand }},
+	{"migrate command", func(d *models.Detection) string { return d.MigrateCommand }},
+	{"test command", func(d *models.Detection) string { return d.TestCommand }},
+	{"frontend", func(d *models.Detection) string { return d.FrontendFramework }},
+}
+
+// DetectionDrift compares the detection the files were generated from with the
+// project's current detection, returning what changed. Only the facts the
+// generated files depend on are compared; options chosen on the command line
+// (non-root, hardened, sidecars, ...) aren't.
+func DetectionDrift(generated, current *models.Detection) []Drift {
+	var drift []Drift
+	for _, v := range driftValues {
+		if was, now := v.value(generated), v.value(current); was != now {
+			drift = append(drift, Drift{Field: v.field, Was: was, Now: now})
+		}
+	}
+	for _, l := range driftLists {
+		added, removed := listChanges(l.list(generated), l.list(current))
+		if len(added) > 0 || len(removed) > 0 {
+			drift = append(drift, Drift{Field: l.field, Added: added, Removed: removed})
+		}
+	}
+	currentLists := current.Capabilities.Lists()
+	for i, l := range generated.Capabilities.Lists() {
+		added, removed := listChanges(l.Libraries, currentLists[i].Libraries)
+		if len(added) > 0 || len(removed) > 0 {
+			drift = append(drift, Drift{Field: l.Name, Added: added, Removed: removed})
+		}
+	}
+	return drift
+}
+
+// listChanges returns the items only in now (added) and only in was (removed), sorted.
+func listChanges(was, now []string) (added, removed []string) {
+	for _, item := range now {
+		if !containsString(was, item) && !containsString(added, item) {
+			added = append(added, item)
+		}
+	}
+	for _, item := range was {
+		if !containsString(now, item) && !containsString(removed, item) {
+			removed = append(removed, item)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// serviceVersionEntries formats service versions as a list (e.g., "postgres 16").
+func serviceVersionEntries(versions map[string]string) []string {
+	entries := make([]string, 0, len(versions))
+	for service, version := range versions {
+		entries = append(entries, service+" "+version)
+	}
+	return entries
+}
+
+// valueOrNone returns value, or "(none)" when it is empty.
+func valueOrNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
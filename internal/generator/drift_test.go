@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestDetectionDrift tests finding what changed between two detections.
+func TestDetectionDrift(t *testing.T) {
+	generated := &models.Detection{
+		Language:        "python",
+		Version:         "3.11",
+		PackageManager:  "pip",
+		Services:        []string{"postgres"},
+		ServiceVersions: map[string]string{"postgres": "15"},
+		Capabilities: models.Capabilities{
+			QueueLibraries: []string{"celery"},
+		},
+	}
+
+	if drift := DetectionDrift(generated, generated); len(drift) != 0 {
+		t.Errorf("DetectionDrift() of the same detection = %v, want none", drift)
+	}
+
+	current := &models.Detection{
+		Language:        "python",
+		Version:         "3.12",
+		PackageManager:  "pip",
+		Services:        []string{"redis", "postgres"},
+		ServiceVersions: map[string]string{"postgres": "16"},
+		Capabilities: models.Capabilities{
+			QueueLibraries:   []string{"celery"},
+			MetricsLibraries: []string{"prometheus_client"},
+		},
+	}
+	var got []string
+	for _, d := range DetectionDrift(generated, current) {
+		got = append(got, d.String())
+	}
+	want := []string{
+		"version: 3.11 → 3.12",
+		"services: +redis",
+		"service versions: +postgres 16 -postgres 15",
+		"metrics libraries: +prometheus_client",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectionDrift() = %q, want %q", got, want)
+	}
+}
+
+// TestDetectionDrift_Order tests that reordered lists are not drift.
+func TestDetectionDrift_Order(t *testing.T) {
+	generated := &models.Detection{Language: "node", Services: []string{"postgres", "redis"}}
+	current := &models.Detection{Language: "node", Services: []string{"redis", "postgres"}}
+	if drift := DetectionDrift(generated, current); len(drift) != 0 {
+		t.Errorf("DetectionDrift() = %v, want none for reordered services", drift)
+	}
+}
+
+// TestDrift_String tests formatting removed values.
+func TestDrift_String(t *testing.T) {
+	d := Drift{Field: "frontend", Was: "react"}
+	if got := d.String(); got != "frontend: react → (none)" {
+		t.Errorf("String() = %q", got)
+	}
+	d = Drift{Field: "services", Removed: []string{"mysql"}}
+	if got := d.String(); got != "services: -mysql" {
+		t.Errorf("String() = %q", got)
+	}
+}
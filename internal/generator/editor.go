@@ -0,0 +1,202 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// Editor files, relative to the project root, as generated by dockstart editor.
+const (
+	// EnvrcFile is direnv's environment file
+	EnvrcFile = ".envrc"
+
+	// EditorConfigFile holds the indentation and line ending settings most
+	// editors read (https://editorconfig.org)
+	EditorConfigFile = ".editorconfig"
+)
+
+// EditorFiles lists the files dockstart editor generates.
+var EditorFiles = []string{EnvrcFile, EditorConfigFile}
+
+// editorTemplates are the editor file templates.
+var editorTemplates = map[string]string{
+	EnvrcFile:        "envrc.tmpl",
+	EditorConfigFile: "editorconfig.tmpl",
+}
+
+// indentSizes are the indentation widths of each language's style guide; Go
+// indents with tabs (gofmt).
+var indentSizes = map[string]int{
+	"node":   2,
+	"python": 4,
+	"rust":   4,
+}
+
+// EnvrcConfig holds the configuration for generating .envrc.
+type EnvrcConfig struct {
+	// Name is the project name
+	Name string
+
+	// ComposeFile, relative to the project root, and ComposeProject point
+	// docker compose at the devcontainer's stack. Empty without Compose
+	ComposeFile    string
+	ComposeProject string
+
+	// CredentialsFile is the generated .env the connection URLs' passwords
+	// are loaded from, relative to the project root
+	CredentialsFile string
+
+	// NonRoot exports the host UID/GID the generated Dockerfiles build the
+	// app user with
+	NonRoot bool
+
+	// Env holds the connection variables, pointing at the services' ports
+	// published on localhost
+	Env []EnvVar
+
+	// NodeBin puts node_modules/.bin on PATH
+	NodeBin bool
+}
+
+// EditorConfigConfig holds the configuration for generating .editorconfig.
+type EditorConfigConfig struct {
+	// Extensions are the detected language's source file extensions (e.g.,
+	// "{js,ts}"), which get their own section. Empty for unknown languages
+	Extensions string
+
+	// IndentSize is the language's indentation width, or 0 to indent with tabs
+	IndentSize int
+}
+
+// sourceExtensions are the source files of each language, in .editorconfig glob syntax.
+var sourceExtensions = map[string]string{
+	"node":   "{js,jsx,mjs,cjs,ts,tsx}",
+	"go":     "go",
+	"python": "py",
+	"rust":   "rs",
+}
+
+// EditorGenerator generates .envrc and .editorconfig, for editors without dev
+// container support: the stack runs in Docker while the editor and its tools
+// run on the host.
+type EditorGenerator struct {
+	output
+}
+
+// NewEditorGenerator creates a new editor file generator.
+func NewEditorGenerator() *EditorGenerator {
+	return &EditorGenerator{}
+}
+
+// Generate writes file, one of EditorFiles. composeProject is the compose
+// project name the devcontainer runs under (e.g., "myapp_devcontainer").
+func (g *EditorGenerator) Generate(detection *models.Detection, projectPath, projectName, composeProject, file string) error {
+	content, err := g.GenerateContent(detection, projectName, composeProject, file)
+	if err != nil {
+		return err
+	}
+
+	if err := g.fs().WriteFile(filepath.Join(projectPath, file), content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	return nil
+}
+
+// GenerateContent returns file, one of EditorFiles, without writing to disk.
+func (g *EditorGenerator) GenerateContent(detection *models.Detection, projectName, composeProject, file string) ([]byte, error) {
+	name, ok := editorTemplates[file]
+	if !ok {
+		return nil, fmt.Errorf("unknown editor file %q: expected one of %s", file, strings.Join(EditorFiles, ", "))
+	}
+
+	if file == EditorConfigFile {
+		return renderTemplate(name, &EditorConfigConfig{
+			Extensions: sourceExtensions[detection.Language],
+			IndentSize: indentSizes[detection.Language],
+		})
+	}
+
+	config, err := g.envrcConfig(detection, projectName, composeProject)
+	if err != nil {
+		return nil, err
+	}
+	return renderTemplate(name, config)
+}
+
+// envrcConfig creates the .envrc configuration from the compose stack
+// generated for the detection. Imported services are left out: their ports
+// aren't known to be published.
+func (g *EditorGenerator) envrcConfig(detection *models.Detection, projectName, composeProject string) (*EnvrcConfig, error) {
+	config := &EnvrcConfig{
+		Name:    projectName,
+		NodeBin: detection.Language == "node",
+	}
+	if !detection.NeedsCompose() {
+		return config, nil
+	}
+
+	compose, err := NewComposeGenerator().config(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+	config.ComposeFile = ".devcontainer/docker-compose.yml"
+	config.ComposeProject = composeProject
+	config.CredentialsFile = CredentialsFile
+	config.NonRoot = compose.NonRoot
+
+	for _, service := range compose.Services {
+		if service.Existing != "" {
+			continue
+		}
+		switch service.Name {
+		case "postgres":
+			config.Env = append(config.Env, EnvVar{Name: "DATABASE_URL", Value: localPostgresURL(compose, compose.Postgres.Database)})
+			for _, database := range compose.PostgresDatabases {
+				config.Env = append(config.Env, EnvVar{Name: database.URLVar, Value: localPostgresURL(compose, database.Name)})
+			}
+		case "redis":
+			config.Env = append(config.Env, EnvVar{Name: "REDIS_URL", Value: "redis://localhost:6379"})
+		case "clickhouse":
+			config.Env = append(config.Env, EnvVar{Name: "CLICKHOUSE_URL", Value: "http://localhost:8123"})
+			if compose.ClickHouse.User != "" {
+				config.Env = append(config.Env,
+					EnvVar{Name: "CLICKHOUSE_HOST", Value: "localhost"},
+					EnvVar{Name: "CLICKHOUSE_USER", Value: compose.ClickHouse.User},
+					EnvVar{Name: "CLICKHOUSE_PASSWORD", Value: compose.ClickHouse.Password},
+					EnvVar{Name: "CLICKHOUSE_DB", Value: compose.ClickHouse.Database},
+				)
+			}
+		case "memcached":
+			config.Env = append(config.Env,
+				EnvVar{Name: "MEMCACHED_HOST", Value: "localhost"},
+				EnvVar{Name: "MEMCACHED_PORT", Value: "11211"},
+			)
+		case "nats":
+			config.Env = append(config.Env, EnvVar{Name: "NATS_URL", Value: "nats://localhost:4222"})
+		case "temporal":
+			config.Env = append(config.Env,
+				EnvVar{Name: "TEMPORAL_ADDRESS", Value: "localhost:7233"},
+				EnvVar{Name: "TEMPORAL_NAMESPACE", Value: "default"},
+			)
+		case "influxdb":
+			config.Env = append(config.Env, EnvVar{Name: "INFLUXDB_URL", Value: "http://localhost:8086"})
+			if compose.InfluxDB.Token != "" {
+				config.Env = append(config.Env,
+					EnvVar{Name: "INFLUXDB_TOKEN", Value: compose.InfluxDB.Token},
+					EnvVar{Name: "INFLUXDB_ORG", Value: compose.InfluxDB.Org},
+					EnvVar{Name: "INFLUXDB_BUCKET", Value: compose.InfluxDB.Bucket},
+				)
+			}
+		}
+	}
+	return config, nil
+}
+
+// localPostgresURL returns the URL of a database on the PostgreSQL port
+// published on localhost.
+func localPostgresURL(compose *ComposeConfig, database string) string {
+	return fmt.Sprintf("postgres://%s:%s@localhost:5432/%s", compose.Postgres.User, compose.Postgres.Password, database)
+}
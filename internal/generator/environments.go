@@ -0,0 +1,263 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// ErrNoProductionDockerfile is returned when the staging override is
+// requested for a project without a Dockerfile at its root.
+var ErrNoProductionDockerfile = errors.New("the staging environment builds the project's production Dockerfile, and there is no Dockerfile at the project root")
+
+// OverrideFile returns the compose override of an environment, relative to
+// the project root (e.g., ".devcontainer/docker-compose.test.yml").
+func OverrideFile(env string) string {
+	return ".devcontainer/docker-compose." + env + ".yml"
+}
+
+// debugPorts are the ports the language's debugger listens on in the dev
+// environment, with how to start it. Rust is debugged with lldb inside the
+// container, so it has none.
+var debugPorts = map[string]struct {
+	Port  int
+	Start string
+}{
+	"node":   {9229, "node --inspect=0.0.0.0:9229"},
+	"go":     {2345, "dlv debug --headless --listen=:2345 --api-version=2"},
+	"python": {5678, "python -m debugpy --listen 0.0.0.0:5678"},
+}
+
+// environmentVars are the variables that tell the app which environment it
+// runs in, by language and environment.
+var environmentVars = map[string]map[string][]string{
+	"node": {
+		models.EnvDev:     {"NODE_ENV=development"},
+		models.EnvTest:    {"NODE_ENV=test"},
+		models.EnvStaging: {"NODE_ENV=production"},
+	},
+	"python": {
+		models.EnvDev: {"PYTHONDEVMODE=1"},
+	},
+	"rust": {
+		models.EnvDev: {"RUST_BACKTRACE=1"},
+	},
+}
+
+// dataDirs are where the generated backing services keep their data, which
+// the test environment moves to tmpfs.
+var dataDirs = map[string]string{
+	"postgres":   "/var/lib/postgresql/data",
+	"redis":      "/data",
+	"clickhouse": "/var/lib/clickhouse",
+	"nats":       "/data",
+	"influxdb":   "/var/lib/influxdb2",
+}
+
+// ComposeOverride is a layer over the generated docker-compose.yml for one
+// environment. Compose merges it into the base file:
+//
+//	docker compose -f docker-compose.yml -f docker-compose.test.yml up
+type ComposeOverride struct {
+	// Name is the project name
+	Name string
+
+	// Environment is the environment the override is for (e.g., "test")
+	Environment string
+
+	// Summary describes the environment in the file header
+	Summary string
+
+	// Services are the services the override changes, in base file order
+	Services []ServiceOverride
+}
+
+// File returns the override's file name, relative to .devcontainer.
+func (o *ComposeOverride) File() string {
+	return filepath.Base(OverrideFile(o.Environment))
+}
+
+// ServiceOverride is what an environment changes in a service. Lists are
+// merged into the base service's: ports and environment variables are added,
+// and volumes replace the base volume mounted at the same target.
+type ServiceOverride struct {
+	// Name is the compose service
+	Name string
+
+	// Comments explain the changes, above the service's keys
+	Comments []string
+
+	// Build replaces the service's build, or is nil to keep it
+	Build *OverrideBuild
+
+	// ResetUser drops the base service's user, for images that don't have it
+	ResetUser bool
+
+	// Command replaces the service's command
+	Command string
+
+	// ResetCommand drops the base service's command, so the image's CMD runs
+	ResetCommand bool
+
+	// ResetVolumes drops the base service's volumes, workspace mount included
+	ResetVolumes bool
+
+	// Tmpfs are the container paths mounted as tmpfs
+	Tmpfs []string
+
+	// Ports are published ports, in compose short syntax
+	Ports []string
+
+	// Environment are added variables, as NAME=value
+	Environment []string
+}
+
+// OverrideBuild is a service's build section.
+type OverrideBuild struct {
+	// Dockerfile is relative to the project root, the build context
+	Dockerfile string
+
+	// Target is the build stage, or empty for the last stage
+	Target string
+}
+
+// add appends a service override, unless it changes nothing: compose rejects
+// a service without keys.
+func (o *ComposeOverride) add(service ServiceOverride) {
+	if service.Build != nil || service.ResetUser || service.Command != "" || service.ResetCommand ||
+		service.ResetVolumes || len(service.Tmpfs) > 0 || len(service.Ports) > 0 || len(service.Environment) > 0 {
+		o.Services = append(o.Services, service)
+	}
+}
+
+// OverridesGenerator generates the docker-compose.<env>.yml overrides of the
+// environments in detection.Environments.
+type OverridesGenerator struct {
+	output
+}
+
+// NewOverridesGenerator creates a new compose overrides generator.
+func NewOverridesGenerator() *OverridesGenerator {
+	return &OverridesGenerator{}
+}
+
+// ShouldGenerate returns true if any environment override is requested.
+func (g *OverridesGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return len(detection.OverrideEnvironments()) > 0
+}
+
+// Files returns the override files generated, relative to the project root.
+func (g *OverridesGenerator) Files(detection *models.Detection) []string {
+	var files []string
+	for _, env := range detection.OverrideEnvironments() {
+		files = append(files, OverrideFile(env))
+	}
+	return files
+}
+
+// Generate writes an override for each environment in detection.Environments.
+func (g *OverridesGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	if !g.ShouldGenerate(detection) {
+		return nil
+	}
+
+	devcontainerDir := filepath.Join(projectPath, ".devcontainer")
+	if err := g.fs().MkdirAll(devcontainerDir, 0755); err != nil {
+		return fmt.Errorf("failed to create .devcontainer directory: %w", err)
+	}
+	for _, env := range detection.OverrideEnvironments() {
+		content, err := g.GenerateContent(detection, projectName, env)
+		if err != nil {
+			return err
+		}
+		file := OverrideFile(env)
+		if err := g.fs().WriteFile(filepath.Join(projectPath, filepath.FromSlash(file)), content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", filepath.Base(file), err)
+		}
+	}
+	return nil
+}
+
+// GenerateContent returns the override of one environment without writing to disk.
+func (g *OverridesGenerator) GenerateContent(detection *models.Detection, projectName, env string) ([]byte, error) {
+	base, err := NewComposeGenerator().config(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+	override, err := buildOverride(detection, base, env)
+	if err != nil {
+		return nil, err
+	}
+	return renderTemplate("docker-compose.override.yml.tmpl", override)
+}
+
+// buildOverride layers an environment over the base ComposeConfig.
+func buildOverride(detection *models.Detection, base *ComposeConfig, env string) (*ComposeOverride, error) {
+	override := &ComposeOverride{Name: base.Name, Environment: env}
+	vars := environmentVars[detection.Language][env]
+
+	switch env {
+	case models.EnvDev:
+		override.Summary = "Development: debugger ports and development settings, over the workspace bind mounts"
+		app := ServiceOverride{Name: "app", Environment: vars}
+		if debug, ok := debugPorts[detection.Language]; ok {
+			port := strconv.Itoa(debug.Port)
+			app.Comments = append(app.Comments, "Attach a debugger after starting the app with: "+debug.Start)
+			app.Ports = append(app.Ports, "127.0.0.1:"+port+":"+port)
+		}
+		override.add(app)
+		if base.WorkerSidecar.Enabled {
+			override.add(ServiceOverride{Name: "worker", Environment: vars})
+		}
+
+	case models.EnvTest:
+		override.Summary = "Test: databases keep their data in tmpfs, so every run starts empty"
+		override.add(ServiceOverride{Name: "app", Environment: vars})
+		for _, service := range base.Services {
+			dir, ok := dataDirs[service.Name]
+			if !ok || service.Existing != "" {
+				continue
+			}
+			override.add(ServiceOverride{Name: service.Name, Tmpfs: []string{dir}})
+		}
+		if base.MinIO.Enabled {
+			override.add(ServiceOverride{Name: "minio", Tmpfs: []string{"/data"}})
+		}
+
+	case models.EnvStaging:
+		production := detection.ProductionDockerfile
+		if production == nil {
+			return nil, ErrNoProductionDockerfile
+		}
+		override.Summary = "Staging: the production image, without the workspace mounted"
+		build := &OverrideBuild{Dockerfile: production.File, Target: production.Target}
+		port := strconv.Itoa(detection.GetAppPort())
+		override.add(ServiceOverride{
+			Name:         "app",
+			Comments:     []string{"Runs the production image's CMD instead of sleeping for the devcontainer"},
+			Build:        build,
+			ResetUser:    base.User != "",
+			ResetCommand: true,
+			ResetVolumes: true,
+			Ports:        []string{port + ":" + port},
+			Environment:  vars,
+		})
+		if base.WorkerSidecar.Enabled {
+			override.add(ServiceOverride{
+				Name:         "worker",
+				Build:        build,
+				ResetUser:    base.User != "",
+				Command:      base.WorkerSidecar.Command,
+				ResetVolumes: true,
+				Environment:  vars,
+			})
+		}
+
+	default:
+		return nil, models.ValidateEnvironments([]string{env})
+	}
+	return override, nil
+}
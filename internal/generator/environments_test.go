@@ -0,0 +1,142 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestOverridesGenerator_GenerateContent tests the dev, test, and staging
+// layers over docker-compose.yml.
+func TestOverridesGenerator_GenerateContent(t *testing.T) {
+	detection := &models.Detection{
+		Language:             "node",
+		Version:              "20",
+		Services:             []string{"postgres", "redis"},
+		Capabilities:         models.Capabilities{QueueLibraries: []string{"bullmq"}},
+		WorkerCommand:        "npm run worker",
+		NonRoot:              true,
+		Environments:         []string{models.EnvStaging, models.EnvDev, models.EnvTest},
+		ProductionDockerfile: &models.ProductionDockerfile{File: "Dockerfile", Target: "production"},
+		Sidecars:             models.SidecarPolicy{Minimal: true},
+	}
+	gen := NewOverridesGenerator()
+
+	if got, want := gen.Files(detection), []string{
+		".devcontainer/docker-compose.dev.yml",
+		".devcontainer/docker-compose.test.yml",
+		".devcontainer/docker-compose.staging.yml",
+	}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Files() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		env       string
+		wantParts []string
+	}{
+		{
+			env: models.EnvDev,
+			wantParts: []string{
+				"docker compose -f docker-compose.yml -f docker-compose.dev.yml up",
+				"  app:\n    # Attach a debugger after starting the app with: node --inspect=0.0.0.0:9229\n" +
+					"    ports:\n      - \"127.0.0.1:9229:9229\"\n    environment:\n      - NODE_ENV=development\n",
+				"  worker:\n    environment:\n      - NODE_ENV=development\n",
+			},
+		},
+		{
+			env: models.EnvTest,
+			wantParts: []string{
+				"  postgres:\n    volumes:\n      - type: tmpfs\n        target: /var/lib/postgresql/data\n",
+				"  redis:\n    volumes:\n      - type: tmpfs\n        target: /data\n",
+				"      - NODE_ENV=test\n",
+			},
+		},
+		{
+			env: models.EnvStaging,
+			wantParts: []string{
+				"  app:\n    # Runs the production image's CMD instead of sleeping for the devcontainer\n" +
+					"    build:\n      context: ..\n      dockerfile: Dockerfile\n      target: production\n" +
+					"    user: !reset null\n    command: !reset null\n    volumes: !reset []\n" +
+					"    ports:\n      - \"3000:3000\"\n    environment:\n      - NODE_ENV=production\n",
+				"  worker:\n    build:\n      context: ..\n      dockerfile: Dockerfile\n      target: production\n" +
+					"    user: !reset null\n    command: npm run worker\n    volumes: !reset []\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			content, err := gen.GenerateContent(detection, "shop", tt.env)
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+			output := string(content)
+			for _, part := range tt.wantParts {
+				if !strings.Contains(output, part) {
+					t.Errorf("%s override should contain %q:\n%s", tt.env, part, output)
+				}
+			}
+
+			var parsed map[string]interface{}
+			if err := yaml.Unmarshal(content, &parsed); err != nil {
+				t.Errorf("%s override is not valid YAML: %v", tt.env, err)
+			}
+		})
+	}
+}
+
+// TestOverridesGenerator_Empty tests overrides that change no service, and
+// staging without a production Dockerfile.
+func TestOverridesGenerator_Empty(t *testing.T) {
+	detection := &models.Detection{
+		Language:     "go",
+		Version:      "1.23",
+		Services:     []string{"mysql"},
+		Environments: []string{models.EnvTest, models.EnvStaging},
+	}
+	gen := NewOverridesGenerator()
+
+	content, err := gen.GenerateContent(detection, "api", models.EnvTest)
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if !strings.Contains(string(content), "\nservices: {}\n") {
+		t.Errorf("test override without tmpfs databases should have no services:\n%s", content)
+	}
+
+	if _, err := gen.GenerateContent(detection, "api", models.EnvStaging); !errors.Is(err, ErrNoProductionDockerfile) {
+		t.Errorf("GenerateContent(staging) error = %v, want ErrNoProductionDockerfile", err)
+	}
+
+	// Without compose there is nothing to layer over
+	if gen.ShouldGenerate(&models.Detection{Language: "go", Version: "1.23", Environments: []string{models.EnvDev}}) {
+		t.Error("ShouldGenerate() without compose = true, want false")
+	}
+}
+
+// TestDevcontainerGenerator_DevOverride tests that devcontainer.json layers
+// the dev override over docker-compose.yml.
+func TestDevcontainerGenerator_DevOverride(t *testing.T) {
+	detection := &models.Detection{
+		Language:     "python",
+		Version:      "3.12",
+		Services:     []string{"postgres"},
+		Environments: []string{models.EnvDev},
+	}
+	content, err := NewDevcontainerGenerator().GenerateContent(detection, "api")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	var devcontainer struct {
+		DockerComposeFile []string `json:"dockerComposeFile"`
+	}
+	if err := json.Unmarshal(content, &devcontainer); err != nil {
+		t.Fatalf("devcontainer.json is not valid JSON: %v", err)
+	}
+	if got := strings.Join(devcontainer.DockerComposeFile, ","); got != "docker-compose.yml,docker-compose.dev.yml" {
+		t.Errorf("dockerComposeFile = %v, want docker-compose.yml and docker-compose.dev.yml", devcontainer.DockerComposeFile)
+	}
+}
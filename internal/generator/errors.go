@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrConflictingFiles means generating would overwrite existing files
+	ErrConflictingFiles = errors.New("generated files already exist")
+
+	// ErrTemplateRender means a template failed to load or render
+	ErrTemplateRender = errors.New("template failed to render")
+)
+
+// ConflictError is returned when files dockstart would generate already exist.
+type ConflictError struct {
+	// Files are the existing files, relative to the project or .devcontainer
+	Files []string
+}
+
+func (e *ConflictError) Error() string {
+	if len(e.Files) == 1 {
+		return fmt.Sprintf("%s already exists. Use --force to overwrite", e.Files[0])
+	}
+	return fmt.Sprintf("%s already exist. Use --force to overwrite", strings.Join(e.Files, ", "))
+}
+
+// Is makes errors.Is(err, ErrConflictingFiles) match.
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflictingFiles
+}
+
+// Hint tells the user how to regenerate safely.
+func (e *ConflictError) Hint() string {
+	return "Preview the changes with --dry-run, then rerun with --force to overwrite; commit or back up local edits first"
+}
+
+// TemplateError is returned when a template fails to load or render.
+type TemplateError struct {
+	// Template is the template's name (e.g., "docker-compose.yml.tmpl")
+	Template string
+
+	// Err is the underlying load or execution error
+	Err error
+}
+
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("failed to render %s: %v", e.Template, e.Err)
+}
+
+func (e *TemplateError) Unwrap() error { return e.Err }
+
+// Is makes errors.Is(err, ErrTemplateRender) match.
+func (e *TemplateError) Is(target error) bool {
+	return target == ErrTemplateRender
+}
+
+// Hint points at the user's template, if they replaced it, or the issue tracker.
+func (e *TemplateError) Hint() string {
+	return fmt.Sprintf("If you replaced %s with your own template, fix or remove it; "+
+		"otherwise please report this at https://github.com/jpequegn/dockstart/issues", e.Template)
+}
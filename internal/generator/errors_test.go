@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// TestConflictError tests the message and classification of conflicts.
+func TestConflictError(t *testing.T) {
+	tests := []struct {
+		files []string
+		want  string
+	}{
+		{[]string{"Dockerfile"}, "Dockerfile already exists. Use --force to overwrite"},
+		{[]string{"docker-compose.yml", "Dockerfile"}, "docker-compose.yml, Dockerfile already exist. Use --force to overwrite"},
+	}
+
+	for _, tt := range tests {
+		var err error = &ConflictError{Files: tt.files}
+		if err.Error() != tt.want {
+			t.Errorf("Error() = %q, want %q", err.Error(), tt.want)
+		}
+		if !errors.Is(err, ErrConflictingFiles) {
+			t.Errorf("errors.Is(%v, ErrConflictingFiles) = false", err)
+		}
+		if errors.Is(err, ErrTemplateRender) {
+			t.Errorf("errors.Is(%v, ErrTemplateRender) = true", err)
+		}
+	}
+}
+
+// TestRenderTemplate_Errors tests that template failures name the template.
+func TestRenderTemplate_Errors(t *testing.T) {
+	previous := templateLoader
+	t.Cleanup(func() { templateLoader = previous })
+	// Parses, but fails when executed
+	if err := UseTemplateOverlay(fstest.MapFS{"gitattributes.tmpl": {Data: []byte(`{{template "missing"}}`)}}); err != nil {
+		t.Fatalf("UseTemplateOverlay() error = %v", err)
+	}
+
+	for _, name := range []string{"gitattributes.tmpl", "missing.tmpl"} {
+		_, err := renderTemplate(name, nil)
+		if !errors.Is(err, ErrTemplateRender) {
+			t.Fatalf("renderTemplate(%s) error = %v, want ErrTemplateRender", name, err)
+		}
+		var tmplErr *TemplateError
+		if !errors.As(err, &tmplErr) || tmplErr.Template != name {
+			t.Errorf("renderTemplate(%s) error = %#v, want a *TemplateError for it", name, err)
+		}
+		if !strings.Contains(err.Error(), "failed to render "+name) || !strings.Contains(tmplErr.Hint(), name) {
+			t.Errorf("error = %q, hint = %q, want both to name %s", err, tmplErr.Hint(), name)
+		}
+	}
+
+	// Generators return the error unchanged, so callers can classify it
+	gen := NewLineEndingsGenerator()
+	gen.SetFS(NewMemFS(nil))
+	if err := gen.Generate(renderFixtures()["rust"], "/workspace/shop", "shop"); !errors.Is(err, ErrTemplateRender) {
+		t.Errorf("LineEndingsGenerator.Generate() error = %v, want ErrTemplateRender", err)
+	}
+}
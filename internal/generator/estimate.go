@@ -0,0 +1,171 @@
+package generator
+
+import (
+	"strconv"
+	"strings"
+)
+
+// imageFootprint is the approximate footprint of an image.
+type imageFootprint struct {
+	// DownloadMB is the compressed size pulled from the registry
+	DownloadMB int
+
+	// MemoryMB is the memory its container uses when idle in development, or
+	// 0 for toolchain images, whose usage depends on the app
+	MemoryMB int
+}
+
+// imageCatalog holds rough footprints of the images dockstart generates, by
+// repository (the image without its tag), for the tags it pins on linux/amd64.
+// They only need to be close enough to tell a 200 MB stack from a 4 GB one.
+var imageCatalog = map[string]imageFootprint{
+	// Dockerfile base images
+	"node":                                 {DownloadMB: 400},
+	"golang":                               {DownloadMB: 300},
+	"python":                               {DownloadMB: 400},
+	"rust":                                 {DownloadMB: 550},
+	"ubuntu":                               {DownloadMB: 30},
+	"mcr.microsoft.com/devcontainers/base": {DownloadMB: 300},
+
+	// Backing services
+	"postgres":                     {DownloadMB: 100, MemoryMB: 50},
+	"pgvector/pgvector":            {DownloadMB: 160, MemoryMB: 50},
+	"timescale/timescaledb":        {DownloadMB: 250, MemoryMB: 60},
+	"postgis/postgis":              {DownloadMB: 250, MemoryMB: 60},
+	"redis":                        {DownloadMB: 15, MemoryMB: 10},
+	"memcached":                    {DownloadMB: 5, MemoryMB: 10},
+	"clickhouse/clickhouse-server": {DownloadMB: 250, MemoryMB: 300},
+	"influxdb":                     {DownloadMB: 150, MemoryMB: 100},
+	"nats":                         {DownloadMB: 8, MemoryMB: 20},
+	"temporalio/auto-setup":        {DownloadMB: 250, MemoryMB: 200},
+	"temporalio/ui":                {DownloadMB: 60, MemoryMB: 40},
+	"rabbitmq":                     {DownloadMB: 100, MemoryMB: 150},
+
+	// Sidecars
+	"natsio/nats-box":           {DownloadMB: 20, MemoryMB: 5},
+	"adminer":                   {DownloadMB: 40, MemoryMB: 20},
+	"redis/redisinsight":        {DownloadMB: 150, MemoryMB: 150},
+	"deadly0/bull-board":        {DownloadMB: 60, MemoryMB: 50},
+	"mher/flower":               {DownloadMB: 60, MemoryMB: 60},
+	"hibiken/asynqmon":          {DownloadMB: 15, MemoryMB: 20},
+	"fluent/fluent-bit":         {DownloadMB: 40, MemoryMB: 20},
+	"busybox":                   {DownloadMB: 5, MemoryMB: 1},
+	"prom/prometheus":           {DownloadMB: 100, MemoryMB: 80},
+	"grafana/grafana":           {DownloadMB: 130, MemoryMB: 100},
+	"oliver006/redis_exporter":  {DownloadMB: 5, MemoryMB: 10},
+	"jaegertracing/all-in-one":  {DownloadMB: 30, MemoryMB: 50},
+	"quay.io/keycloak/keycloak": {DownloadMB: 200, MemoryMB: 500},
+	"stripe/stripe-cli":         {DownloadMB: 20, MemoryMB: 20},
+	"selenium/hub":              {DownloadMB: 300, MemoryMB: 200},
+	"selenium/node-chrome":      {DownloadMB: 600, MemoryMB: 500},
+	"selenium/node-firefox":     {DownloadMB: 550, MemoryMB: 500},
+	"qdrant/qdrant":             {DownloadMB: 60, MemoryMB: 80},
+	"chromadb/chroma":           {DownloadMB: 250, MemoryMB: 150},
+	"getmeili/meilisearch":      {DownloadMB: 60, MemoryMB: 100},
+	"typesense/typesense":       {DownloadMB: 50, MemoryMB: 100},
+	"axllent/mailpit":           {DownloadMB: 15, MemoryMB: 30},
+	"wiremock/wiremock":         {DownloadMB: 250, MemoryMB: 200},
+	"ghcr.io/shopify/toxiproxy": {DownloadMB: 10, MemoryMB: 10},
+	"glitchtip/glitchtip":       {DownloadMB: 200, MemoryMB: 250},
+	"twinproduction/gatus":      {DownloadMB: 20, MemoryMB: 20},
+	"localstack/localstack":     {DownloadMB: 450, MemoryMB: 300},
+	"minio/minio":               {DownloadMB: 60, MemoryMB: 100},
+	"minio/mc":                  {DownloadMB: 30},
+	"fullstorydev/grpcui":       {DownloadMB: 20, MemoryMB: 20},
+	"quay.io/prometheuscommunity/postgres-exporter": {DownloadMB: 10, MemoryMB: 15},
+
+	// The llama3.2 model adds about 2 GB to download and to memory
+	"ollama/ollama": {DownloadMB: 1500, MemoryMB: 2500},
+}
+
+// ImageEstimate is the estimated footprint of one image of the stack.
+type ImageEstimate struct {
+	// Image is the image reference (e.g., "postgres:16-alpine")
+	Image string
+
+	// Known is false for images missing from the catalog, which aren't counted
+	Known bool
+
+	// Pulled is true if the image is already in the local image store
+	Pulled bool
+
+	// DownloadMB is the compressed size to pull, 0 if already pulled
+	DownloadMB int
+
+	// MemoryMB is the memory its container uses when idle
+	MemoryMB int
+}
+
+// StackEstimate is the estimated footprint of the planned stack.
+type StackEstimate struct {
+	// Images are the per-image estimates, in stack order
+	Images []ImageEstimate
+
+	// DownloadMB is the total left to download
+	DownloadMB int
+
+	// MemoryMB is the total memory of the containers when idle, leaving out
+	// the app containers, whose usage depends on the app
+	MemoryMB int
+
+	// Unknown lists the images without an estimate
+	Unknown []string
+}
+
+// EstimateStack estimates the download size and idle memory of a stack's
+// images from the image catalog. pulled reports images already present
+// locally, which cost no download; it may be nil. An image listed twice
+// (e.g., the Dockerfile's base image also run as a service) counts once.
+func EstimateStack(images []string, pulled func(image string) bool) StackEstimate {
+	var estimate StackEstimate
+	seen := make(map[string]bool, len(images))
+	for _, image := range images {
+		ref := imageReference(image)
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		footprint, known := imageCatalog[imageRepository(image)]
+		entry := ImageEstimate{Image: image, Known: known, MemoryMB: footprint.MemoryMB}
+		if !known {
+			estimate.Unknown = append(estimate.Unknown, image)
+		}
+		if pulled != nil && pulled(image) {
+			entry.Pulled = true
+		} else {
+			entry.DownloadMB = footprint.DownloadMB
+		}
+		estimate.DownloadMB += entry.DownloadMB
+		estimate.MemoryMB += entry.MemoryMB
+		estimate.Images = append(estimate.Images, entry)
+	}
+	return estimate
+}
+
+// imageReference returns an image reference without the docker.io/library/
+// prefix official images may be written with (e.g., "redis:7" for
+// "docker.io/library/redis:7").
+func imageReference(image string) string {
+	image = strings.TrimPrefix(image, "docker.io/")
+	return strings.TrimPrefix(image, "library/")
+}
+
+// imageRepository returns an image reference without its tag or digest
+// (e.g., "postgres" for "postgres:16-alpine"). Official images may be
+// written with their docker.io/library/ prefix.
+func imageRepository(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	image = strings.TrimPrefix(image, "docker.io/")
+	return strings.TrimPrefix(image, "library/")
+}
+
+// FormatMB formats a size in megabytes for messages (e.g., "850 MB", "1.2 GB").
+func FormatMB(mb int) string {
+	if mb < 1024 {
+		return strconv.Itoa(mb) + " MB"
+	}
+	return strings.TrimSuffix(strconv.FormatFloat(float64(mb)/1024, 'f', 1, 64), ".0") + " GB"
+}
@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestEstimateStack tests adding up the download size and memory of a stack.
+func TestEstimateStack(t *testing.T) {
+	images := []string{"golang:1.23", "postgres:16-alpine", "docker.io/library/redis:7-alpine", "example/custom:1.0"}
+	pulled := func(image string) bool { return image == "postgres:16-alpine" }
+
+	estimate := EstimateStack(images, pulled)
+
+	// golang 300 MB + redis 15 MB; postgres is already pulled
+	if estimate.DownloadMB != 315 {
+		t.Errorf("DownloadMB = %d, want 315", estimate.DownloadMB)
+	}
+	// postgres 50 MB + redis 10 MB; the toolchain image isn't counted
+	if estimate.MemoryMB != 60 {
+		t.Errorf("MemoryMB = %d, want 60", estimate.MemoryMB)
+	}
+	if len(estimate.Unknown) != 1 || estimate.Unknown[0] != "example/custom:1.0" {
+		t.Errorf("Unknown = %v, want [example/custom:1.0]", estimate.Unknown)
+	}
+	if len(estimate.Images) != len(images) || !estimate.Images[1].Pulled || estimate.Images[1].DownloadMB != 0 {
+		t.Errorf("Images = %+v, want postgres pulled with nothing to download", estimate.Images)
+	}
+
+	// Without a pulled check, everything is downloaded
+	if got := EstimateStack(images, nil).DownloadMB; got != 415 {
+		t.Errorf("DownloadMB without pulled = %d, want 415", got)
+	}
+}
+
+// TestImageRepository tests stripping tags and digests from image references.
+func TestImageRepository(t *testing.T) {
+	tests := map[string]string{
+		"postgres:16-alpine":                              "postgres",
+		"docker.io/library/redis:7":                       "redis",
+		"ghcr.io/shopify/toxiproxy:2.9.0":                 "ghcr.io/shopify/toxiproxy",
+		"localhost:5000/app":                              "localhost:5000/app",
+		"mcr.microsoft.com/devcontainers/base@sha256:abc": "mcr.microsoft.com/devcontainers/base",
+	}
+	for image, want := range tests {
+		if got := imageRepository(image); got != want {
+			t.Errorf("imageRepository(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
+
+// TestFormatMB tests formatting sizes for messages.
+func TestFormatMB(t *testing.T) {
+	for mb, want := range map[int]string{0: "0 MB", 850: "850 MB", 1024: "1 GB", 1229: "1.2 GB"} {
+		if got := FormatMB(mb); got != want {
+			t.Errorf("FormatMB(%d) = %q, want %q", mb, got, want)
+		}
+	}
+}
+
+// TestEstimateStack_SharedImage tests that the Dockerfile's base image also
+// run as a service (a Node.js frontend's web service) is counted once.
+func TestEstimateStack_SharedImage(t *testing.T) {
+	detection := &models.Detection{
+		Language:          "node",
+		Version:           "20",
+		Services:          []string{"postgres"},
+		FrontendFramework: "nextjs",
+		FrontendDir:       ".",
+		FrontendPort:      3002,
+	}
+	composeImages, err := NewComposeGenerator().Images(detection, "shop")
+	if err != nil {
+		t.Fatalf("Images() error = %v", err)
+	}
+	base := NewDockerfileGenerator().BaseImage(detection)
+	images := append([]string{base}, composeImages...)
+	if !slices.Contains(composeImages, base) {
+		t.Fatalf("the web service should run the base image %s, got %v", base, composeImages)
+	}
+
+	estimate := EstimateStack(images, nil)
+	// node 400 MB + postgres 100 MB + adminer 40 MB
+	if estimate.DownloadMB != 540 {
+		t.Errorf("DownloadMB = %d, want 540", estimate.DownloadMB)
+	}
+	if len(estimate.Images) != len(images)-1 || estimate.Images[0].Image != base {
+		t.Errorf("Images = %+v, want %s once", estimate.Images, base)
+	}
+
+	if got := EstimateStack([]string{"redis:7", "docker.io/library/redis:7"}, nil).DownloadMB; got != 15 {
+		t.Errorf("DownloadMB of the same image written two ways = %d, want 15", got)
+	}
+}
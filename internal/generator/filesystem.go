@@ -0,0 +1,207 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// FS is the filesystem generators read existing files from and write
+// generated files to. Paths are OS paths, as passed to the os package.
+type FS interface {
+	// MkdirAll creates a directory and any missing parents
+	MkdirAll(path string, perm os.FileMode) error
+
+	// WriteFile creates or replaces a file
+	WriteFile(name string, data []byte, perm os.FileMode) error
+
+	// ReadFile returns a file's content
+	ReadFile(name string) ([]byte, error)
+
+	// Stat describes a file
+	Stat(name string) (os.FileInfo, error)
+
+	// ReadDir lists a directory's entries, sorted by name
+	ReadDir(name string) ([]os.DirEntry, error)
+}
+
+// OS is the real filesystem, which generators write to unless given another FS.
+var OS FS = osFS{}
+
+// osFS implements FS with the os package.
+type osFS struct{}
+
+func (osFS) MkdirAll(path string, perm os.FileMode) error { return os.MkdirAll(path, perm) }
+
+func (osFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (osFS) ReadFile(name string) ([]byte, error) { return os.ReadFile(name) }
+
+func (osFS) Stat(name string) (os.FileInfo, error) { return os.Stat(name) }
+
+func (osFS) ReadDir(name string) ([]os.DirEntry, error) { return os.ReadDir(name) }
+
+// output is embedded in every generator to choose the filesystem it writes to.
+type output struct {
+	fsys FS
+}
+
+// SetFS makes the generator read and write files through fsys instead of
+// the real filesystem (e.g., a MemFS to generate without touching the project).
+func (o *output) SetFS(fsys FS) {
+	o.fsys = fsys
+}
+
+// fs returns the filesystem the generator writes to.
+func (o *output) fs() FS {
+	if o.fsys == nil {
+		return OS
+	}
+	return o.fsys
+}
+
+// MemFS is an in-memory FS. Files written to it stay in memory; files it
+// doesn't have are read from its base, so generators that update existing
+// files (like devcontainer.json) still see the project's current ones.
+type MemFS struct {
+	base  FS
+	files map[string]*memFile
+	dirs  map[string]bool
+}
+
+// memFile is a file written to a MemFS.
+type memFile struct {
+	data []byte
+	mode os.FileMode
+}
+
+// NewMemFS creates an empty in-memory filesystem that reads through to base,
+// or has no files but the ones written to it when base is nil.
+func NewMemFS(base FS) *MemFS {
+	return &MemFS{
+		base:  base,
+		files: make(map[string]*memFile),
+		dirs:  make(map[string]bool),
+	}
+}
+
+// MkdirAll records a directory and its parents.
+func (m *MemFS) MkdirAll(path string, perm os.FileMode) error {
+	for dir := filepath.Clean(path); !m.dirs[dir]; dir = filepath.Dir(dir) {
+		m.dirs[dir] = true
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	return nil
+}
+
+// WriteFile stores a file in memory.
+func (m *MemFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	m.files[filepath.Clean(name)] = &memFile{data: append([]byte(nil), data...), mode: perm}
+	return nil
+}
+
+// ReadFile returns a file written to the MemFS, or the base's file.
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	if file, ok := m.files[filepath.Clean(name)]; ok {
+		return append([]byte(nil), file.data...), nil
+	}
+	if m.base != nil {
+		return m.base.ReadFile(name)
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// Stat describes a file or directory written to the MemFS, or the base's.
+func (m *MemFS) Stat(name string) (os.FileInfo, error) {
+	name = filepath.Clean(name)
+	if file, ok := m.files[name]; ok {
+		return memFileInfo{name: filepath.Base(name), size: int64(len(file.data)), mode: file.mode}, nil
+	}
+	if m.dirs[name] {
+		return memFileInfo{name: filepath.Base(name), mode: fs.ModeDir | 0755}, nil
+	}
+	if m.base != nil {
+		return m.base.Stat(name)
+	}
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir lists the files and directories written to the MemFS in a
+// directory together with the base's, sorted by name.
+func (m *MemFS) ReadDir(name string) ([]os.DirEntry, error) {
+	name = filepath.Clean(name)
+	entries := make(map[string]os.DirEntry)
+	for dir := range m.dirs {
+		if dir != name && filepath.Dir(dir) == name {
+			entries[filepath.Base(dir)] = fs.FileInfoToDirEntry(memFileInfo{name: filepath.Base(dir), mode: fs.ModeDir | 0755})
+		}
+	}
+	for path, file := range m.files {
+		if filepath.Dir(path) == name {
+			entries[filepath.Base(path)] = fs.FileInfoToDirEntry(memFileInfo{name: filepath.Base(path), size: int64(len(file.data)), mode: file.mode})
+		}
+	}
+
+	// A directory only created in memory isn't in the base
+	inMemory := m.dirs[name] || len(entries) > 0
+	if m.base != nil {
+		base, err := m.base.ReadDir(name)
+		if err != nil && !(inMemory && os.IsNotExist(err)) {
+			return nil, err
+		}
+		for _, entry := range base {
+			if _, ok := entries[entry.Name()]; !ok {
+				entries[entry.Name()] = entry
+			}
+		}
+	} else if !inMemory {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	list := make([]os.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, entry)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list, nil
+}
+
+// Files returns the paths of the files written to the MemFS, sorted.
+func (m *MemFS) Files() []string {
+	files := make([]string, 0, len(m.files))
+	for name := range m.files {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Mode returns the permissions a file was written with.
+func (m *MemFS) Mode(name string) (os.FileMode, bool) {
+	file, ok := m.files[filepath.Clean(name)]
+	if !ok {
+		return 0, false
+	}
+	return file.mode, true
+}
+
+// memFileInfo describes a MemFS file or directory.
+type memFileInfo struct {
+	name string
+	size int64
+	mode os.FileMode
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) Mode() os.FileMode  { return i.mode }
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i memFileInfo) Sys() any           { return nil }
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestMemFS tests that files written to a MemFS stay in memory and that
+// reads fall through to the base filesystem.
+func TestMemFS(t *testing.T) {
+	tmpDir := t.TempDir()
+	existing := filepath.Join(tmpDir, "existing.txt")
+	if err := os.WriteFile(existing, []byte("on disk"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	mem := NewMemFS(OS)
+	script := filepath.Join(tmpDir, ".devcontainer", "scripts", "migrate.sh")
+	if err := mem.MkdirAll(filepath.Dir(script), 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := mem.WriteFile(script, []byte("#!/bin/sh"), 0755); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, ".devcontainer")); !os.IsNotExist(err) {
+		t.Errorf("MemFS should not write to disk, stat error = %v", err)
+	}
+	if data, err := mem.ReadFile(script); err != nil || string(data) != "#!/bin/sh" {
+		t.Errorf("ReadFile(migrate.sh) = %q, %v", data, err)
+	}
+	if mode, ok := mem.Mode(script); !ok || mode != 0755 {
+		t.Errorf("Mode(migrate.sh) = %v, %v, want 0755", mode, ok)
+	}
+	if info, err := mem.Stat(filepath.Join(tmpDir, ".devcontainer")); err != nil || !info.IsDir() {
+		t.Errorf("Stat(.devcontainer) = %v, %v, want a directory", info, err)
+	}
+	if data, err := mem.ReadFile(existing); err != nil || string(data) != "on disk" {
+		t.Errorf("ReadFile(existing.txt) = %q, %v, want the base's file", data, err)
+	}
+	if got := mem.Files(); len(got) != 1 || got[0] != script {
+		t.Errorf("Files() = %v, want only %s", got, script)
+	}
+	if got := dirNames(t, mem, tmpDir); !slices.Equal(got, []string{".devcontainer", "existing.txt"}) {
+		t.Errorf("ReadDir(project) = %v, want the MemFS's and the base's entries", got)
+	}
+	if got := dirNames(t, mem, filepath.Dir(script)); !slices.Equal(got, []string{"migrate.sh"}) {
+		t.Errorf("ReadDir(scripts) = %v, want [migrate.sh]", got)
+	}
+
+	if _, err := NewMemFS(nil).ReadFile(existing); !os.IsNotExist(err) {
+		t.Errorf("ReadFile() without a base error = %v, want not exist", err)
+	}
+	if _, err := mem.ReadDir(filepath.Join(tmpDir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("ReadDir(missing) error = %v, want not exist", err)
+	}
+}
+
+// dirNames returns the names of a directory's entries in fsys.
+func dirNames(t *testing.T, fsys FS, dir string) []string {
+	t.Helper()
+	entries, err := fsys.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir(%s) error = %v", dir, err)
+	}
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name()
+	}
+	return names
+}
+
+// TestGenerators_MemFS tests that every generator writes through its FS, so
+// generating into a MemFS leaves the project untouched.
+func TestGenerators_MemFS(t *testing.T) {
+	tmpDir := t.TempDir()
+	mem := NewMemFS(OS)
+	generateAll(t, renderFixtures()["node with every sidecar"], tmpDir, mem)
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to read project: %v", err)
+	}
+	if len(entries) > 0 {
+		t.Errorf("generators wrote %d entries to disk, want none", len(entries))
+	}
+
+	var files []string
+	for _, path := range mem.Files() {
+		rel, _ := filepath.Rel(tmpDir, path)
+		files = append(files, filepath.ToSlash(rel))
+	}
+	joined := strings.Join(files, "\n")
+	for _, want := range []string{
+		".devcontainer/docker-compose.yml",
+		".devcontainer/scripts/backup-postgres.sh",
+		".devcontainer/grafana/provisioning/dashboards/app-metrics.json",
+		"Justfile",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("MemFS is missing %s, has:\n%s", want, joined)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// hostHome is the host user's home directory as a devcontainer.json
+// variable: HOME on Linux and macOS, USERPROFILE on Windows (the other is empty).
+const hostHome = "${localEnv:HOME}${localEnv:USERPROFILE}"
+
+// Where the forwarded sockets and files are mounted in the dev container.
+// System-wide paths are used where the tools read them, so they work for any
+// remote user; gpg only reads its home, so gpgLinkCommand links them there.
+const (
+	sshAgentSocket = "/ssh-agent"
+	sshKnownHosts  = "/etc/ssh/ssh_known_hosts"
+	gitSystemConf  = "/etc/gitconfig"
+	gpgAgentSocket = "/gpg-agent"
+	gpgPublicRing  = "/gpg-pubring.kbx"
+)
+
+// gpgLinkCommand links the forwarded gpg-agent socket and public keyring into
+// the remote user's ~/.gnupg. Mounting them there directly would leave the
+// directory owned by root.
+const gpgLinkCommand = "mkdir -p -m 700 ~/.gnupg && ln -sf " + gpgAgentSocket + " ~/.gnupg/S.gpg-agent && ln -sf " + gpgPublicRing + " ~/.gnupg/pubring.kbx"
+
+// credentialMounts returns the devcontainer.json mounts and remoteEnv that
+// forward the host credentials in detection.Forward into the dev container.
+// The sockets come from the host's SSH_AUTH_SOCK and GPG_AGENT_SOCK.
+func credentialMounts(detection *models.Detection) ([]string, []EnvVar) {
+	var mounts []string
+	var env []EnvVar
+	if detection.Forwards(models.ForwardSSH) {
+		mounts = append(mounts,
+			"source=${localEnv:SSH_AUTH_SOCK},target="+sshAgentSocket+",type=bind",
+			"source="+hostHome+"/.ssh/known_hosts,target="+sshKnownHosts+",type=bind,readonly",
+		)
+		env = append(env, EnvVar{Name: "SSH_AUTH_SOCK", Value: sshAgentSocket})
+	}
+	if detection.Forwards(models.ForwardGitConfig) {
+		// As the system config, so "git config --global" still writes ~/.gitconfig
+		mounts = append(mounts, "source="+hostHome+"/.gitconfig,target="+gitSystemConf+",type=bind,readonly")
+	}
+	if detection.Forwards(models.ForwardGPG) {
+		mounts = append(mounts,
+			"source=${localEnv:GPG_AGENT_SOCK},target="+gpgAgentSocket+",type=bind",
+			"source="+hostHome+"/.gnupg/pubring.kbx,target="+gpgPublicRing+",type=bind,readonly",
+		)
+	}
+	return mounts, env
+}
@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestDevcontainerGenerator_Forward tests the mounts and environment that
+// forward the host's credentials, with and without Compose.
+func TestDevcontainerGenerator_Forward(t *testing.T) {
+	for _, services := range [][]string{nil, {"postgres"}} {
+		detection := &models.Detection{
+			Language: "go",
+			Version:  "1.23",
+			Services: services,
+			Forward:  []string{models.ForwardSSH, models.ForwardGitConfig, models.ForwardGPG},
+		}
+
+		content, err := NewDevcontainerGenerator().GenerateContent(detection, "api")
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		var devcontainer struct {
+			Mounts            []string          `json:"mounts"`
+			RemoteEnv         map[string]string `json:"remoteEnv"`
+			PostCreateCommand string            `json:"postCreateCommand"`
+		}
+		if err := json.Unmarshal(content, &devcontainer); err != nil {
+			t.Fatalf("devcontainer.json is not valid JSON: %v", err)
+		}
+
+		want := []string{
+			"source=${localEnv:SSH_AUTH_SOCK},target=/ssh-agent,type=bind",
+			"source=${localEnv:HOME}${localEnv:USERPROFILE}/.ssh/known_hosts,target=/etc/ssh/ssh_known_hosts,type=bind,readonly",
+			"source=${localEnv:HOME}${localEnv:USERPROFILE}/.gitconfig,target=/etc/gitconfig,type=bind,readonly",
+			"source=${localEnv:GPG_AGENT_SOCK},target=/gpg-agent,type=bind",
+			"source=${localEnv:HOME}${localEnv:USERPROFILE}/.gnupg/pubring.kbx,target=/gpg-pubring.kbx,type=bind,readonly",
+		}
+		if strings.Join(devcontainer.Mounts, "\n") != strings.Join(want, "\n") {
+			t.Errorf("mounts with services %v = %q, want %q", services, devcontainer.Mounts, want)
+		}
+		if devcontainer.RemoteEnv["SSH_AUTH_SOCK"] != "/ssh-agent" {
+			t.Errorf("remoteEnv = %v, want SSH_AUTH_SOCK=/ssh-agent", devcontainer.RemoteEnv)
+		}
+		if !strings.HasPrefix(devcontainer.PostCreateCommand, gpgLinkCommand+" && ") {
+			t.Errorf("postCreateCommand = %q, want it to link the gpg-agent first", devcontainer.PostCreateCommand)
+		}
+	}
+}
+
+// TestDevcontainerGenerator_NoForward tests that nothing is forwarded by default.
+func TestDevcontainerGenerator_NoForward(t *testing.T) {
+	content, err := NewDevcontainerGenerator().GenerateContent(&models.Detection{Language: "go", Version: "1.23"}, "api")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	for _, unwanted := range []string{"SSH_AUTH_SOCK", "/etc/gitconfig", "gnupg"} {
+		if strings.Contains(string(content), unwanted) {
+			t.Errorf("devcontainer.json should not contain %q, got:\n%s", unwanted, content)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// gatusImage is the Gatus image the status page sidecar runs.
+const gatusImage = "twinproduction/gatus:v5.12.1"
+
+// GatusPort is the host port of the Gatus status page.
+const GatusPort = 8084
+
+// GatusConfigFile is the generated Gatus configuration, relative to the project root.
+const GatusConfigFile = ".devcontainer/gatus/config.yaml"
+
+// GatusComposeConfig holds configuration for the Gatus status page sidecar.
+type GatusComposeConfig struct {
+	// Enabled indicates whether to include the Gatus sidecar
+	Enabled bool
+
+	// Image is the Gatus image
+	Image string
+
+	// Port is the external port for the status page
+	Port int
+}
+
+// gatusComposeConfig returns the Gatus sidecar settings, or a zero config
+// unless the status page was requested.
+func gatusComposeConfig(detection *models.Detection) GatusComposeConfig {
+	if !detection.NeedsStatusPage() {
+		return GatusComposeConfig{}
+	}
+	return GatusComposeConfig{
+		Enabled: true,
+		Image:   gatusImage,
+		Port:    GatusPort,
+	}
+}
+
+// GatusEndpoint is a check on the Gatus status page.
+type GatusEndpoint struct {
+	// Name is the display name, the compose service checked (e.g., "postgres")
+	Name string
+
+	// Group groups endpoints on the page ("app", "databases", "observability")
+	Group string
+
+	// URL is the address checked from inside the compose network
+	// (e.g., "http://grafana:3000/api/health", "tcp://postgres:5432")
+	URL string
+
+	// Conditions must all hold for the endpoint to be up
+	Conditions []string
+}
+
+// httpUp and tcpUp are the conditions of HTTP and TCP checks.
+var (
+	httpUp = []string{"[STATUS] == 200"}
+	tcpUp  = []string{"[CONNECTED] == true"}
+)
+
+// gatusServiceChecks are the checks of the generated backing services, by
+// service: an HTTP health endpoint where the service has one, TCP otherwise.
+var gatusServiceChecks = map[string]struct {
+	url        string
+	conditions []string
+}{
+	"postgres":   {"tcp://%s:5432", tcpUp},
+	"redis":      {"tcp://%s:6379", tcpUp},
+	"clickhouse": {"http://%s:8123/ping", httpUp},
+	"memcached":  {"tcp://%s:11211", tcpUp},
+	"nats":       {"http://%s:8222/healthz", httpUp},
+	"temporal":   {"tcp://%s:7233", tcpUp},
+	"influxdb":   {"http://%s:8086/health", httpUp},
+}
+
+// GatusConfig holds the configuration for generating the Gatus config.yaml.
+type GatusConfig struct {
+	// Name is the project name, shown as the page title
+	Name string
+
+	// Endpoints are the checks, in page order
+	Endpoints []GatusEndpoint
+}
+
+// GatusGenerator generates .devcontainer/gatus/config.yaml, which Gatus loads
+// its endpoint checks from.
+type GatusGenerator struct {
+	output
+}
+
+// NewGatusGenerator creates a new Gatus generator.
+func NewGatusGenerator() *GatusGenerator {
+	return &GatusGenerator{}
+}
+
+// ShouldGenerate returns true if the status page was requested.
+func (g *GatusGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.NeedsStatusPage()
+}
+
+// Generate creates .devcontainer/gatus/config.yaml.
+func (g *GatusGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	content, err := g.GenerateContent(detection, projectName)
+	if err != nil {
+		return err
+	}
+
+	gatusDir := filepath.Join(projectPath, ".devcontainer", "gatus")
+	if err := g.fs().MkdirAll(gatusDir, 0755); err != nil {
+		return fmt.Errorf("failed to create gatus directory: %w", err)
+	}
+	if err := g.fs().WriteFile(filepath.Join(gatusDir, "config.yaml"), content, 0644); err != nil {
+		return fmt.Errorf("failed to write gatus config.yaml: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateContent returns the Gatus config.yaml content without writing to disk.
+func (g *GatusGenerator) GenerateContent(detection *models.Detection, projectName string) ([]byte, error) {
+	config, err := g.buildConfig(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderTemplate("gatus/config.yaml.tmpl", config)
+}
+
+// buildConfig creates a GatusConfig from a Detection, with a check for each
+// service of the generated docker-compose.yml that has something to check.
+func (g *GatusGenerator) buildConfig(detection *models.Detection, projectName string) (*GatusConfig, error) {
+	compose, err := NewComposeGenerator().config(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &GatusConfig{Name: projectName}
+
+	// Any response short of a server error means the app is serving, unless
+	// a health endpoint is configured
+	appUp := []string{"[STATUS] < 500"}
+	if detection.HealthPath != "" {
+		appUp = httpUp
+	}
+	config.Endpoints = append(config.Endpoints, GatusEndpoint{
+		Name:       "app",
+		Group:      "app",
+		URL:        fmt.Sprintf("http://app:%d%s", detection.GetAppPort(), detection.GetHealthPath()),
+		Conditions: appUp,
+	})
+	if compose.WebService.Enabled {
+		config.Endpoints = append(config.Endpoints, GatusEndpoint{
+			Name:       "web",
+			Group:      "app",
+			URL:        fmt.Sprintf("http://web:%d", compose.WebService.Port),
+			Conditions: []string{"[STATUS] < 500"},
+		})
+	}
+
+	for _, service := range compose.Services {
+		check, ok := gatusServiceChecks[service.Name]
+		if !ok {
+			continue
+		}
+		config.Endpoints = append(config.Endpoints, GatusEndpoint{
+			Name:       service.ComposeName(),
+			Group:      "databases",
+			URL:        fmt.Sprintf(check.url, service.ComposeName()),
+			Conditions: check.conditions,
+		})
+	}
+
+	if compose.MetricsSidecar.Enabled {
+		config.Endpoints = append(config.Endpoints,
+			GatusEndpoint{Name: "grafana", Group: "observability", URL: "http://grafana:3000/api/health", Conditions: httpUp},
+			GatusEndpoint{Name: "prometheus", Group: "observability", URL: "http://prometheus:9090/-/healthy", Conditions: httpUp},
+		)
+	}
+	if compose.TracingSidecar.Enabled {
+		config.Endpoints = append(config.Endpoints,
+			GatusEndpoint{Name: "jaeger", Group: "observability", URL: "http://jaeger:16686", Conditions: httpUp},
+		)
+	}
+
+	return config, nil
+}
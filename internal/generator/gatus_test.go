@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestComposeGenerator_Gatus tests the Gatus status page in docker-compose.yml.
+func TestComposeGenerator_Gatus(t *testing.T) {
+	detection := &models.Detection{
+		Language:   "node",
+		Version:    "20",
+		Services:   []string{"postgres"},
+		StatusPage: true,
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+
+	for _, want := range []string{
+		"  gatus:\n    image: twinproduction/gatus:v5.12.1\n",
+		`"8084:8080"`,
+		"      - ./gatus:/config:ro\n",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml missing %q:\n%s", want, compose)
+		}
+	}
+
+	detection.StatusPage = false
+	content, err = NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if strings.Contains(string(content), "gatus") {
+		t.Error("docker-compose.yml has Gatus without --gatus")
+	}
+}
+
+// TestGatusGenerator_Endpoints tests the checks generated for the stack.
+func TestGatusGenerator_Endpoints(t *testing.T) {
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Services: []string{"postgres", "redis"},
+		Capabilities: models.Capabilities{
+			MetricsLibraries: []string{"prometheus_client"},
+			TracingLibraries: []string{"opentelemetry"},
+		},
+		StatusPage: true,
+		HealthPath: "/health",
+	}
+
+	content, err := NewGatusGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	var parsed struct {
+		Endpoints []struct {
+			Name       string   `yaml:"name"`
+			Group      string   `yaml:"group"`
+			URL        string   `yaml:"url"`
+			Conditions []string `yaml:"conditions"`
+		} `yaml:"endpoints"`
+	}
+	if err := yaml.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, content)
+	}
+
+	urls := make(map[string]string)
+	conditions := make(map[string][]string)
+	for _, endpoint := range parsed.Endpoints {
+		urls[endpoint.Name] = endpoint.URL
+		conditions[endpoint.Name] = endpoint.Conditions
+	}
+	want := map[string]string{
+		"app":        "http://app:8000/health",
+		"postgres":   "tcp://postgres:5432",
+		"redis":      "tcp://redis:6379",
+		"grafana":    "http://grafana:3000/api/health",
+		"prometheus": "http://prometheus:9090/-/healthy",
+		"jaeger":     "http://jaeger:16686",
+	}
+	for name, url := range want {
+		if urls[name] != url {
+			t.Errorf("endpoint %s URL = %q, want %q", name, urls[name], url)
+		}
+	}
+	if !slices.Equal(conditions["app"], []string{"[STATUS] == 200"}) {
+		t.Errorf("app conditions = %v, want a 200 from the health path", conditions["app"])
+	}
+	if !slices.Equal(conditions["postgres"], []string{"[CONNECTED] == true"}) {
+		t.Errorf("postgres conditions = %v, want a TCP connection", conditions["postgres"])
+	}
+}
+
+// TestGatusGenerator_NoHealthPath tests that without a health path any
+// response short of a server error counts as up.
+func TestGatusGenerator_NoHealthPath(t *testing.T) {
+	detection := &models.Detection{
+		Language:   "go",
+		Version:    "1.23",
+		StatusPage: true,
+	}
+
+	content, err := NewGatusGenerator().GenerateContent(detection, "api")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	config := string(content)
+
+	for _, want := range []string{`url: "http://app:8080/"`, `- "[STATUS] < 500"`} {
+		if !strings.Contains(config, want) {
+			t.Errorf("gatus config.yaml missing %q:\n%s", want, config)
+		}
+	}
+	if strings.Contains(config, "grafana") {
+		t.Errorf("gatus config.yaml checks Grafana without the metrics sidecar:\n%s", config)
+	}
+}
+
+// TestDevcontainerGenerator_Gatus tests that the status page is forwarded and
+// that Gatus alone needs docker-compose.yml.
+func TestDevcontainerGenerator_Gatus(t *testing.T) {
+	detection := &models.Detection{
+		Language:   "go",
+		Version:    "1.23",
+		StatusPage: true,
+	}
+	if !detection.NeedsCompose() {
+		t.Error("NeedsCompose() = false with Gatus")
+	}
+
+	config := NewDevcontainerGenerator().buildConfig(detection, "api")
+	if !config.UseCompose {
+		t.Error("UseCompose = false with Gatus")
+	}
+	if !slices.Contains(config.ForwardPorts, GatusPort) {
+		t.Errorf("ForwardPorts = %v, want %d", config.ForwardPorts, GatusPort)
+	}
+}
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestComposeGenerator_GlitchTip tests the GlitchTip sidecar in docker-compose.yml.
+func TestComposeGenerator_GlitchTip(t *testing.T) {
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Services: []string{"postgres"},
+		Capabilities: models.Capabilities{
+			QueueLibraries:         []string{"celery"},
+			ErrorTrackingLibraries: []string{"sentry-sdk"},
+		},
+		WorkerCommand:      "celery -A app worker",
+		LocalErrorTracking: true,
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+
+	var parsed struct {
+		Services map[string]struct {
+			// A list, or a map for the databases
+			Environment interface{} `yaml:"environment"`
+		} `yaml:"services"`
+		Volumes map[string]interface{} `yaml:"volumes"`
+	}
+	if err := yaml.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, compose)
+	}
+
+	for _, service := range []string{"glitchtip", "glitchtip-worker", "glitchtip-migrate", "glitchtip-postgres", "glitchtip-redis"} {
+		if _, ok := parsed.Services[service]; !ok {
+			t.Errorf("docker-compose.yml missing the %s service", service)
+		}
+	}
+	for _, service := range []string{"app", "worker"} {
+		environment, _ := parsed.Services[service].Environment.([]interface{})
+		if !slices.Contains(environment, interface{}("SENTRY_DSN=${SENTRY_DSN:-}")) {
+			t.Errorf("%s environment = %v, want SENTRY_DSN", service, parsed.Services[service].Environment)
+		}
+	}
+	if _, ok := parsed.Volumes["glitchtip-postgres-data"]; !ok {
+		t.Error("docker-compose.yml missing the glitchtip-postgres-data volume")
+	}
+
+	for _, want := range []string{
+		"  glitchtip:\n    image: glitchtip/glitchtip:v4.2\n",
+		`"8010:8000"`,
+		"      - GLITCHTIP_DOMAIN=http://localhost:8010\n",
+		"    command: ./bin/run-celery-with-beat.sh\n",
+		"      glitchtip-migrate:\n        condition: service_completed_successfully\n",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+		}
+	}
+	// GlitchTip keeps its data apart from the project's database
+	if strings.Contains(compose, "@postgres:5432/glitchtip") {
+		t.Error("GlitchTip should use its own PostgreSQL")
+	}
+
+	// Without --glitchtip, the SDKs are only reported
+	detection.LocalErrorTracking = false
+	content, err = NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if strings.Contains(string(content), "glitchtip") || strings.Contains(string(content), "SENTRY_DSN") {
+		t.Errorf("docker-compose.yml should not contain GlitchTip without --glitchtip, got:\n%s", content)
+	}
+}
+
+// TestDevcontainerGenerator_GlitchTip tests that the GlitchTip UI is forwarded
+// and that GlitchTip alone needs docker-compose.yml.
+func TestDevcontainerGenerator_GlitchTip(t *testing.T) {
+	detection := &models.Detection{
+		Language: "go",
+		Version:  "1.23",
+		Capabilities: models.Capabilities{
+			ErrorTrackingLibraries: []string{"sentry-go"},
+		},
+		LocalErrorTracking: true,
+	}
+	if !detection.NeedsCompose() {
+		t.Error("NeedsCompose() = false with GlitchTip")
+	}
+
+	config := NewDevcontainerGenerator().buildConfig(detection, "api")
+	if !config.UseCompose {
+		t.Error("UseCompose = false with GlitchTip")
+	}
+	if !slices.Contains(config.ForwardPorts, GlitchTipPort) {
+		t.Errorf("ForwardPorts = %v, want %d", config.ForwardPorts, GlitchTipPort)
+	}
+}
@@ -0,0 +1,241 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// GraphNode is a service or named volume of the generated stack.
+type GraphNode struct {
+	// Name is the compose service or volume name
+	Name string
+
+	// Kind is "app" (built from the app's Dockerfile), "database" (a backing
+	// service), "sidecar", or "volume"
+	Kind string
+}
+
+// GraphEdge connects a service to a service it depends on or a volume it mounts.
+type GraphEdge struct {
+	From string
+	To   string
+
+	// Volume is true when To is a named volume
+	Volume bool
+}
+
+// Graph is the topology of the generated docker-compose.yml.
+type Graph struct {
+	// Name is the project name
+	Name string
+
+	// Nodes are the services, in file order, then the named volumes
+	Nodes []GraphNode
+
+	// Edges are the depends_on edges, then the volume mounts
+	Edges []GraphEdge
+}
+
+// Graph returns the service graph of the generated docker-compose.yml.
+func (g *ComposeGenerator) Graph(detection *models.Detection, projectName string) (*Graph, error) {
+	config, err := g.config(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+	content, err := g.render(config)
+	if err != nil {
+		return nil, err
+	}
+	return buildGraph(config, content)
+}
+
+// buildGraph builds the graph of a docker-compose.yml rendered from config.
+func buildGraph(config *ComposeConfig, content []byte) (*Graph, error) {
+	services, err := parseComposeServices(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var compose struct {
+		Volumes yaml.Node `yaml:"volumes"`
+	}
+	if err := yaml.Unmarshal(content, &compose); err != nil {
+		return nil, fmt.Errorf("failed to parse docker-compose.yml: %w", err)
+	}
+	var volumes []string
+	for i := 0; i < len(compose.Volumes.Content); i += 2 {
+		volumes = append(volumes, compose.Volumes.Content[i].Value)
+	}
+
+	var databases []string
+	for _, s := range config.Services {
+		databases = append(databases, s.ComposeName())
+	}
+
+	graph := &Graph{Name: config.Name}
+	var mounts []GraphEdge
+	for _, s := range services {
+		kind := "sidecar"
+		switch {
+		case containsString(databases, s.Name):
+			kind = "database"
+		case buildsAppDockerfile(s, config.Dockerfile), s.extends() == "app":
+			kind = "app"
+		}
+		graph.Nodes = append(graph.Nodes, GraphNode{Name: s.Name, Kind: kind})
+
+		for _, dep := range dependencies(s.DependsOn) {
+			graph.Edges = append(graph.Edges, GraphEdge{From: s.Name, To: dep})
+		}
+		for _, volume := range namedVolumes(s.Volumes, volumes) {
+			mounts = append(mounts, GraphEdge{From: s.Name, To: volume, Volume: true})
+		}
+	}
+	for _, volume := range volumes {
+		graph.Nodes = append(graph.Nodes, GraphNode{Name: volume, Kind: "volume"})
+	}
+	graph.Edges = append(graph.Edges, mounts...)
+
+	return graph, nil
+}
+
+// buildsAppDockerfile reports whether a service is built from the app's Dockerfile.
+func buildsAppDockerfile(s composeService, dockerfile string) bool {
+	if s.Build.Kind != yaml.MappingNode {
+		return false
+	}
+	var build struct {
+		Dockerfile string `yaml:"dockerfile"`
+	}
+	return s.Build.Decode(&build) == nil && build.Dockerfile == dockerfile
+}
+
+// dependencies returns the services in a depends_on list or mapping.
+func dependencies(node yaml.Node) []string {
+	var deps []string
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, entry := range node.Content {
+			deps = append(deps, entry.Value)
+		}
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			deps = append(deps, node.Content[i].Value)
+		}
+	}
+	return deps
+}
+
+// namedVolumes returns the named volumes mounted by a service's volumes entries,
+// in short ("data:/var/lib/data") or long (type: volume) syntax. Bind mounts are skipped.
+func namedVolumes(node yaml.Node, volumes []string) []string {
+	var mounted []string
+	for _, entry := range node.Content {
+		source := ""
+		switch entry.Kind {
+		case yaml.ScalarNode:
+			source, _, _ = strings.Cut(entry.Value, ":")
+		case yaml.MappingNode:
+			var mount struct {
+				Type   string `yaml:"type"`
+				Source string `yaml:"source"`
+			}
+			if entry.Decode(&mount) == nil && mount.Type == "volume" {
+				source = mount.Source
+			}
+		}
+		if containsString(volumes, source) && !containsString(mounted, source) {
+			mounted = append(mounted, source)
+		}
+	}
+	return mounted
+}
+
+// graphIDPattern matches characters that are not valid in Mermaid node IDs.
+var graphIDPattern = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// mermaidID returns the Mermaid node ID of a node. Volumes are prefixed, since a
+// volume may share its name with a service.
+func (g *Graph) mermaidID(name string) string {
+	if g.isVolume(name) {
+		name = "volume_" + name
+	}
+	return graphIDPattern.ReplaceAllString(name, "_")
+}
+
+// isVolume reports whether a node is a named volume.
+func (g *Graph) isVolume(name string) bool {
+	for _, n := range g.Nodes {
+		if n.Name == name {
+			return n.Kind == "volume"
+		}
+	}
+	return false
+}
+
+// mermaidShapes are the Mermaid node shapes of each node kind.
+var mermaidShapes = map[string][2]string{
+	"app":      {"[", "]"},
+	"database": {"[(", ")]"},
+	"sidecar":  {"(", ")"},
+	"volume":   {"[/", "/]"},
+}
+
+// Mermaid renders the graph as a Mermaid flowchart. Service dependencies are
+// solid arrows; volume mounts are dotted.
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes {
+		shape := mermaidShapes[n.Kind]
+		fmt.Fprintf(&b, "    %s%s\"%s\"%s\n", g.mermaidID(n.Name), shape[0], n.Name, shape[1])
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		if e.Volume {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "    %s %s %s\n", g.mermaidID(e.From), arrow, g.mermaidID(e.To))
+	}
+	return b.String()
+}
+
+// dotShapes are the Graphviz node shapes of each node kind.
+var dotShapes = map[string]string{
+	"app":      "box",
+	"database": "cylinder",
+	"sidecar":  "ellipse",
+	"volume":   "folder",
+}
+
+// Dot renders the graph in the Graphviz DOT language. Volume nodes are
+// prefixed with "volume:" to keep them apart from services of the same name.
+func (g *Graph) Dot() string {
+	id := func(name string) string {
+		if g.isVolume(name) {
+			return fmt.Sprintf("%q", "volume:"+name)
+		}
+		return fmt.Sprintf("%q", name)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", g.Name)
+	b.WriteString("    rankdir=LR;\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "    %s [label=%q, shape=%s];\n", id(n.Name), n.Name, dotShapes[n.Kind])
+	}
+	for _, e := range g.Edges {
+		if e.Volume {
+			fmt.Fprintf(&b, "    %s -> %s [style=dashed];\n", id(e.From), id(e.To))
+		} else {
+			fmt.Fprintf(&b, "    %s -> %s;\n", id(e.From), id(e.To))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,147 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestComposeGenerator_Graph tests the service graph of the generated docker-compose.yml.
+func TestComposeGenerator_Graph(t *testing.T) {
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Services: []string{"postgres"},
+		Capabilities: models.Capabilities{
+			QueueLibraries: []string{"bullmq"},
+		},
+		WorkerCommand: "node worker.js",
+	}
+
+	graph, err := NewComposeGenerator().Graph(detection, "shop")
+	if err != nil {
+		t.Fatalf("Graph() error = %v", err)
+	}
+
+	kinds := make(map[string]string)
+	for _, n := range graph.Nodes {
+		kinds[n.Name] = n.Kind
+	}
+	for name, want := range map[string]string{
+		"app":           "app",
+		"worker":        "app",
+		"postgres":      "database",
+		"redis":         "database",
+		"bull-board":    "sidecar",
+		"db-backup":     "sidecar",
+		"postgres-data": "volume",
+	} {
+		if kinds[name] != want {
+			t.Errorf("node %s kind = %q, want %q", name, kinds[name], want)
+		}
+	}
+
+	hasEdge := func(from, to string, volume bool) bool {
+		for _, e := range graph.Edges {
+			if e.From == from && e.To == to && e.Volume == volume {
+				return true
+			}
+		}
+		return false
+	}
+	for _, e := range []GraphEdge{
+		{From: "app", To: "postgres"},
+		{From: "worker", To: "app"},
+		{From: "bull-board", To: "redis"},
+		{From: "postgres", To: "postgres-data", Volume: true},
+	} {
+		if !hasEdge(e.From, e.To, e.Volume) {
+			t.Errorf("graph should have edge %+v, got %+v", e, graph.Edges)
+		}
+	}
+
+	// The workspace and ./backups are bind mounts, not volumes
+	if hasEdge("app", "..", true) || hasEdge("db-backup", "backups", true) {
+		t.Errorf("bind mounts should not be volume edges, got %+v", graph.Edges)
+	}
+}
+
+// TestGraph_Render tests the Mermaid and DOT renderings.
+func TestGraph_Render(t *testing.T) {
+	graph := &Graph{
+		Name: "shop",
+		Nodes: []GraphNode{
+			{Name: "app", Kind: "app"},
+			{Name: "postgres", Kind: "database"},
+			{Name: "db-backup", Kind: "sidecar"},
+			{Name: "postgres", Kind: "volume"},
+		},
+		Edges: []GraphEdge{
+			{From: "app", To: "postgres"},
+			{From: "db-backup", To: "postgres"},
+		},
+	}
+
+	mermaid := graph.Mermaid()
+	for _, want := range []string{
+		"flowchart LR\n",
+		`app["app"]`,
+		`postgres[("postgres")]`,
+		`db_backup("db-backup")`,
+		"app --> postgres",
+		"db_backup --> postgres",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("Mermaid() should contain %q, got:\n%s", want, mermaid)
+		}
+	}
+
+	dot := graph.Dot()
+	for _, want := range []string{
+		`digraph "shop" {`,
+		`"app" [label="app", shape=box];`,
+		`"postgres" [label="postgres", shape=cylinder];`,
+		`"app" -> "postgres";`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("Dot() should contain %q, got:\n%s", want, dot)
+		}
+	}
+}
+
+// TestNamedVolumes tests which volumes entries mount named volumes.
+func TestNamedVolumes(t *testing.T) {
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		ExistingCompose: &models.ExistingCompose{
+			File: "docker-compose.yml",
+			Services: []models.ExistingService{
+				{Name: "search", Definition: map[string]interface{}{
+					"image": "opensearchproject/opensearch:2",
+					"volumes": []interface{}{
+						map[string]interface{}{"type": "volume", "source": "search-data", "target": "/usr/share/opensearch/data"},
+						"./config:/config:ro",
+					},
+				}},
+			},
+			Volumes: map[string]interface{}{"search-data": nil},
+		},
+	}
+
+	graph, err := NewComposeGenerator().Graph(detection, "api")
+	if err != nil {
+		t.Fatalf("Graph() error = %v", err)
+	}
+
+	var mounts []string
+	for _, e := range graph.Edges {
+		if e.Volume && e.From == "search" {
+			mounts = append(mounts, e.To)
+		}
+	}
+	if strings.Join(mounts, ",") != "search-data" {
+		t.Errorf("search mounts = %v, want [search-data]", mounts)
+	}
+}
@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// hardeningProfile describes how --hardened locks down a generated compose service.
+// By default a service gets a read-only root filesystem, no-new-privileges, and
+// cap_drop: ALL; the fields list what it needs on top of that.
+type hardeningProfile struct {
+	// Exempt leaves the service unchanged, for the reason given
+	Exempt string
+
+	// Writable keeps the root filesystem writable, for the reason given
+	Writable string
+
+	// Tmpfs lists the scratch paths mounted on a read-only root filesystem
+	Tmpfs []string
+
+	// CapAdd lists the capabilities added back after dropping all of them
+	CapAdd []string
+
+	// KeepCaps keeps Docker's default capabilities, for the reason given
+	KeepCaps string
+}
+
+// capsForPrivilegeDrop are the capabilities an entrypoint needs to chown its
+// data directory and switch to an unprivileged user (gosu, su-exec).
+var capsForPrivilegeDrop = []string{"CHOWN", "DAC_OVERRIDE", "FOWNER", "SETGID", "SETUID"}
+
+// hardeningProfile returns the --hardened profile of a generated service.
+func (c *ComposeConfig) hardeningProfile(service string) hardeningProfile {
+	switch service {
+	case "app", "worker", "worker-dlq":
+		return hardeningProfile{Exempt: "development container: toolchains write outside /workspace and sudo needs new privileges"}
+	case "web":
+		return hardeningProfile{Exempt: "frontend dev server: package managers write caches outside /workspace"}
+	case "scheduler":
+		if c.SchedulerSidecar.Command != "" {
+			return hardeningProfile{Exempt: "runs the app's toolchain, which writes caches outside /workspace"}
+		}
+		return hardeningProfile{Tmpfs: []string{"/tmp"}}
+	case "postgres", "postgres-test", "glitchtip-postgres":
+		return hardeningProfile{Tmpfs: []string{"/tmp", "/var/run/postgresql"}, CapAdd: capsForPrivilegeDrop}
+	case "redis", "redis-test", "glitchtip-redis":
+		return hardeningProfile{Tmpfs: []string{"/tmp"}, CapAdd: []string{"CHOWN", "SETGID", "SETUID"}}
+	case "rabbitmq":
+		return hardeningProfile{
+			Writable: "the entrypoint writes its configuration and Erlang cookie at startup",
+			CapAdd:   capsForPrivilegeDrop,
+		}
+	case "influxdb":
+		return hardeningProfile{Tmpfs: []string{"/tmp"}, CapAdd: capsForPrivilegeDrop}
+	case "clickhouse":
+		return hardeningProfile{
+			Writable: "the entrypoint writes the configured user into /etc/clickhouse-server/users.d",
+			CapAdd:   capsForPrivilegeDrop,
+		}
+	case "selenium-hub", "chrome", "firefox":
+		return hardeningProfile{Writable: "supervisord and the browsers write logs, profiles, and X11 sockets into the image"}
+	case "temporal", "temporal-ui":
+		return hardeningProfile{Writable: "the entrypoint renders its configuration file from the environment at startup"}
+	case "redisinsight":
+		return hardeningProfile{Writable: "RedisInsight keeps its settings and the database list in a SQLite file under /data"}
+	case "keycloak":
+		return hardeningProfile{Writable: "start-dev rebuilds the server and keeps its H2 database under /opt/keycloak"}
+	case "glitchtip-worker":
+		return hardeningProfile{Writable: "Celery beat writes its schedule file into the working directory"}
+	case "chroma":
+		return hardeningProfile{Writable: "the server writes its log file next to its code in /chroma"}
+	case "localstack":
+		return hardeningProfile{
+			Writable: "emulated services keep runtime state under /var/lib/localstack and /tmp",
+			KeepCaps: "emulated services start their own processes",
+		}
+	case "file-processor":
+		profile := hardeningProfile{Tmpfs: []string{"/tmp"}}
+		if c.FileProcessorSidecar.S3 {
+			// mc keeps its alias configuration in the home directory
+			profile.Tmpfs = append(profile.Tmpfs, c.sidecarHome())
+		}
+		if c.NonRoot {
+			profile.CapAdd = capsForPrivilegeDrop
+		}
+		return profile
+	case "db-backup":
+		if c.BackupSidecar.NeedsDockerSocket {
+			return hardeningProfile{Exempt: "mounts the Docker socket, which is root-equivalent access to the host"}
+		}
+		if c.NonRoot {
+			return hardeningProfile{Tmpfs: []string{"/tmp"}, CapAdd: []string{"CHOWN", "SETGID", "SETUID"}}
+		}
+		// Root needs DAC_OVERRIDE to write to the host-owned backups directory
+		return hardeningProfile{Tmpfs: []string{"/tmp"}, CapAdd: []string{"DAC_OVERRIDE"}}
+	case "grafana":
+		return hardeningProfile{Tmpfs: []string{"/tmp", "/var/log/grafana"}}
+	case "qdrant":
+		return hardeningProfile{Tmpfs: []string{"/tmp", "/qdrant/snapshots"}}
+	case "stripe-cli":
+		return hardeningProfile{Tmpfs: []string{"/tmp", "/root/.config"}}
+	case "ollama-pull":
+		return hardeningProfile{Tmpfs: []string{"/root/.ollama"}}
+	case "nats-box":
+		// The nats CLI keeps its contexts in the home directory
+		return hardeningProfile{Tmpfs: []string{"/tmp", "/root"}}
+	case "minio", "minio-init":
+		// minio and mc keep their configuration in the home directory
+		return hardeningProfile{Tmpfs: []string{"/tmp", "/root"}}
+	case "prometheus", "postgres-exporter", "redis-exporter", "asynqmon":
+		return hardeningProfile{}
+	default:
+		return hardeningProfile{Tmpfs: []string{"/tmp"}}
+	}
+}
+
+// sidecarHome returns the home directory of the user the generated sidecars run as.
+func (c *ComposeConfig) sidecarHome() string {
+	if c.NonRoot {
+		return "/home/" + models.SidecarUser
+	}
+	return "/root"
+}
+
+// Hardening renders the --hardened settings of a generated service, placed at
+// the end of its block in docker-compose.yml. Empty unless Hardened is set.
+func (c *ComposeConfig) Hardening(service string) string {
+	if !c.Hardened {
+		return ""
+	}
+	profile := c.hardeningProfile(service)
+
+	var b strings.Builder
+	if profile.Exempt != "" {
+		fmt.Fprintf(&b, "\n    # Not hardened: %s", profile.Exempt)
+		return b.String()
+	}
+
+	if profile.Writable != "" {
+		fmt.Fprintf(&b, "\n    # Root filesystem stays writable: %s", profile.Writable)
+	} else {
+		b.WriteString("\n    read_only: true")
+		if len(profile.Tmpfs) > 0 {
+			b.WriteString("\n    tmpfs:")
+			for _, path := range profile.Tmpfs {
+				fmt.Fprintf(&b, "\n      - %s", path)
+			}
+		}
+	}
+
+	b.WriteString("\n    security_opt:\n      - no-new-privileges:true")
+
+	if profile.KeepCaps != "" {
+		fmt.Fprintf(&b, "\n    # Default capabilities kept: %s", profile.KeepCaps)
+		return b.String()
+	}
+	b.WriteString("\n    cap_drop:\n      - ALL")
+	if len(profile.CapAdd) > 0 {
+		b.WriteString("\n    cap_add:")
+		for _, capability := range profile.CapAdd {
+			fmt.Fprintf(&b, "\n      - %s", capability)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,361 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// ImportedComposeConfig holds the services and top-level definitions imported
+// from the project's own compose file, rendered as YAML for .devcontainer/docker-compose.yml.
+type ImportedComposeConfig struct {
+	// Enabled indicates whether an existing compose file was imported
+	Enabled bool
+
+	// File is the imported file, relative to .devcontainer (e.g., "../docker-compose.yml")
+	File string
+
+	// Services are the imported service definitions, in file order
+	Services []ImportedServiceConfig
+
+	// Replaced lists imported services left out because a generated service
+	// has the same name (e.g., "app", which the devcontainer replaces)
+	Replaced []string
+
+	// Volumes, Networks, Configs, and Secrets are rendered top-level entries,
+	// indented to sit under their section key
+	Volumes  string
+	Networks string
+	Configs  string
+	Secrets  string
+}
+
+// ImportedServiceConfig is an imported compose service.
+type ImportedServiceConfig struct {
+	// Name is the compose service name
+	Name string
+
+	// YAML is the rendered service definition, indented to sit under "services:"
+	YAML string
+}
+
+// PostgresConnection holds the credentials generated services use to reach PostgreSQL.
+type PostgresConnection struct {
+	User     string
+	Password string
+	Database string
+}
+
+// importCompose adds the services of an existing compose file to config. Imported
+// services that run a backing service stand in for the generated one, reachable
+// under the generated service's hostname (e.g., "postgres") through a network alias.
+// Imported services named like a generated service are replaced by it.
+func (g *ComposeGenerator) importCompose(config *ComposeConfig, existing *models.ExistingCompose) error {
+	// Render without the import to learn the generated service names
+	content, err := g.render(config)
+	if err != nil {
+		return err
+	}
+	generated, err := serviceNames(content)
+	if err != nil {
+		return err
+	}
+
+	imported := ImportedComposeConfig{
+		Enabled: true,
+		File:    path.Join("..", filepath.ToSlash(existing.File)),
+	}
+
+	for _, service := range existing.Services {
+		if service.Role == "" || service.Name != service.Role {
+			if containsString(generated, service.Name) {
+				imported.Replaced = append(imported.Replaced, service.Name)
+				continue
+			}
+		}
+
+		definition := rebaseService(service.Definition)
+		if service.Role != "" && service.Name != service.Role {
+			addNetworkAlias(definition, service.Role)
+		}
+		if service.Role == "postgres" {
+			config.Postgres = postgresConnection(definition, config.Postgres)
+		}
+
+		rendered, err := renderYAML(map[string]interface{}{service.Name: definition})
+		if err != nil {
+			return fmt.Errorf("failed to render imported service %s: %w", service.Name, err)
+		}
+		imported.Services = append(imported.Services, ImportedServiceConfig{Name: service.Name, YAML: rendered})
+	}
+
+	sections := []struct {
+		entries map[string]interface{}
+		target  *string
+	}{
+		{existing.Volumes, &imported.Volumes},
+		{existing.Networks, &imported.Networks},
+		{rebaseFiles(existing.Configs), &imported.Configs},
+		{rebaseFiles(existing.Secrets), &imported.Secrets},
+	}
+	for _, section := range sections {
+		if len(section.entries) == 0 {
+			continue
+		}
+		rendered, err := renderYAML(section.entries)
+		if err != nil {
+			return fmt.Errorf("failed to render imported definitions: %w", err)
+		}
+		*section.target = rendered
+	}
+
+	config.Imported = imported
+	return nil
+}
+
+// serviceNames returns the service names of a rendered compose file.
+func serviceNames(content []byte) ([]string, error) {
+	var compose struct {
+		Services map[string]interface{} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(content, &compose); err != nil {
+		return nil, fmt.Errorf("failed to parse docker-compose.yml: %w", err)
+	}
+
+	names := make([]string, 0, len(compose.Services))
+	for name := range compose.Services {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// renderYAML renders a mapping indented by two spaces, to nest under a section key.
+func renderYAML(entries map[string]interface{}) (string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(entries); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	for i, line := range lines {
+		// Write empty definitions the way compose files do ("pgdata:", not "pgdata: null")
+		if strings.HasSuffix(line, ": null") {
+			line = strings.TrimSuffix(line, " null")
+		}
+		lines[i] = "  " + line
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// rebasePath makes a path relative to the project root relative to .devcontainer.
+// Absolute paths, home and variable paths, and remote build contexts are kept.
+func rebasePath(p string) string {
+	if p == "" || filepath.IsAbs(p) || strings.HasPrefix(p, "/") || strings.HasPrefix(p, "~") ||
+		strings.HasPrefix(p, "$") || strings.Contains(p, "://") || strings.HasPrefix(p, "git@") {
+		return p
+	}
+	return path.Join("..", p)
+}
+
+// rebaseService returns a copy of a service definition with the paths compose
+// resolves relative to the file (build context, bind mounts, env files) rebased.
+func rebaseService(definition map[string]interface{}) map[string]interface{} {
+	service := make(map[string]interface{}, len(definition))
+	for key, value := range definition {
+		service[key] = value
+	}
+
+	switch build := service["build"].(type) {
+	case string:
+		service["build"] = rebasePath(build)
+	case map[string]interface{}:
+		rebased := copyMap(build)
+		context, _ := rebased["context"].(string)
+		if context == "" {
+			context = "."
+		}
+		rebased["context"] = rebasePath(context)
+		service["build"] = rebased
+	}
+
+	if volumes, ok := service["volumes"].([]interface{}); ok {
+		rebased := make([]interface{}, len(volumes))
+		for i, volume := range volumes {
+			rebased[i] = rebaseVolume(volume)
+		}
+		service["volumes"] = rebased
+	}
+
+	switch envFile := service["env_file"].(type) {
+	case string:
+		service["env_file"] = rebasePath(envFile)
+	case []interface{}:
+		rebased := make([]interface{}, len(envFile))
+		for i, entry := range envFile {
+			switch entry := entry.(type) {
+			case string:
+				rebased[i] = rebasePath(entry)
+			case map[string]interface{}:
+				entryCopy := copyMap(entry)
+				if p, ok := entryCopy["path"].(string); ok {
+					entryCopy["path"] = rebasePath(p)
+				}
+				rebased[i] = entryCopy
+			default:
+				rebased[i] = entry
+			}
+		}
+		service["env_file"] = rebased
+	}
+
+	if extends, ok := service["extends"].(map[string]interface{}); ok {
+		if file, ok := extends["file"].(string); ok {
+			rebased := copyMap(extends)
+			rebased["file"] = rebasePath(file)
+			service["extends"] = rebased
+		}
+	}
+
+	return service
+}
+
+// rebaseVolume rebases the source of a bind mount. Named volumes are kept.
+func rebaseVolume(volume interface{}) interface{} {
+	switch volume := volume.(type) {
+	case string:
+		// Bind mounts start with "." or "/"; anything else is a named volume
+		if strings.HasPrefix(volume, ".") {
+			source, rest, _ := strings.Cut(volume, ":")
+			if rest == "" {
+				return rebasePath(source)
+			}
+			return rebasePath(source) + ":" + rest
+		}
+		return volume
+	case map[string]interface{}:
+		source, ok := volume["source"].(string)
+		if !ok || volume["type"] != "bind" {
+			return volume
+		}
+		rebased := copyMap(volume)
+		rebased["source"] = rebasePath(source)
+		return rebased
+	default:
+		return volume
+	}
+}
+
+// rebaseFiles rebases the "file" of top-level config and secret definitions.
+func rebaseFiles(entries map[string]interface{}) map[string]interface{} {
+	if entries == nil {
+		return nil
+	}
+
+	rebased := make(map[string]interface{}, len(entries))
+	for name, entry := range entries {
+		definition, ok := entry.(map[string]interface{})
+		if !ok {
+			rebased[name] = entry
+			continue
+		}
+		if file, ok := definition["file"].(string); ok {
+			definition = copyMap(definition)
+			definition["file"] = rebasePath(file)
+		}
+		rebased[name] = definition
+	}
+	return rebased
+}
+
+// addNetworkAlias makes a service reachable under alias on the default network,
+// where generated services run. Services using network_mode are left alone.
+func addNetworkAlias(service map[string]interface{}, alias string) {
+	if _, ok := service["network_mode"]; ok {
+		return
+	}
+
+	networks := make(map[string]interface{})
+	switch existing := service["networks"].(type) {
+	case []interface{}:
+		for _, network := range existing {
+			if name, ok := network.(string); ok {
+				networks[name] = nil
+			}
+		}
+	case map[string]interface{}:
+		for name, config := range existing {
+			networks[name] = config
+		}
+	}
+
+	network, _ := networks["default"].(map[string]interface{})
+	network = copyMap(network)
+	aliases, _ := network["aliases"].([]interface{})
+	network["aliases"] = append(append([]interface{}{}, aliases...), alias)
+	networks["default"] = network
+
+	service["networks"] = networks
+}
+
+// postgresConnection reads the credentials of an imported PostgreSQL service from
+// its environment, following the official image's defaults.
+func postgresConnection(service map[string]interface{}, fallback PostgresConnection) PostgresConnection {
+	env := serviceEnvironment(service)
+	user := env["POSTGRES_USER"]
+	if user == "" {
+		user = "postgres"
+	}
+	database := env["POSTGRES_DB"]
+	if database == "" {
+		database = user
+	}
+
+	// Credentials kept in an env_file can't be read; keep the default user and
+	// database, and guess the usual development password rather than the
+	// generated one, which the imported service doesn't know
+	if env["POSTGRES_PASSWORD"] == "" && service["env_file"] != nil {
+		fallback.Password = legacyCredential("POSTGRES_PASSWORD")
+		return fallback
+	}
+	return PostgresConnection{User: user, Password: env["POSTGRES_PASSWORD"], Database: database}
+}
+
+// serviceEnvironment returns a service's environment, in map or list form.
+func serviceEnvironment(service map[string]interface{}) map[string]string {
+	env := make(map[string]string)
+	switch environment := service["environment"].(type) {
+	case map[string]interface{}:
+		for key, value := range environment {
+			if value != nil {
+				env[key] = fmt.Sprint(value)
+			}
+		}
+	case []interface{}:
+		for _, entry := range environment {
+			if s, ok := entry.(string); ok {
+				key, value, _ := strings.Cut(s, "=")
+				env[key] = value
+			}
+		}
+	}
+	return env
+}
+
+// copyMap returns a shallow copy of a map, or an empty map for nil.
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		copied[key] = value
+	}
+	return copied
+}
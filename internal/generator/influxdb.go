@@ -0,0 +1,36 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// InfluxDBComposeConfig holds the settings the InfluxDB image bootstraps on first
+// start, and the app connects with.
+type InfluxDBComposeConfig struct {
+	// User and Password log in to the UI at http://localhost:8086
+	User     string
+	Password string
+
+	// Org and Bucket are the organization and bucket created for the app
+	Org    string
+	Bucket string
+
+	// Token is the admin API token the app authenticates with
+	Token string
+}
+
+// influxDBConfig returns the InfluxDB bootstrap settings, or a zero config when
+// no InfluxDB service is generated.
+func influxDBConfig(detection *models.Detection, projectName string) InfluxDBComposeConfig {
+	if !detection.HasService("influxdb") || detection.ExistingServiceFor("influxdb") != "" {
+		return InfluxDBComposeConfig{}
+	}
+	return InfluxDBComposeConfig{
+		User:     "admin",
+		Password: credentialRef("INFLUXDB_PASSWORD"),
+		Org:      projectName,
+		Bucket:   projectName + "_dev",
+		Token:    projectName + "-dev-token",
+	}
+}
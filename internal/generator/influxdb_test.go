@@ -0,0 +1,138 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestComposeGenerator_InfluxDB tests the InfluxDB service in docker-compose.yml.
+func TestComposeGenerator_InfluxDB(t *testing.T) {
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Services: []string{"influxdb"},
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "sensors")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+
+	var parsed map[string]interface{}
+	if err := yaml.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, compose)
+	}
+
+	for _, want := range []string{
+		"  influxdb:\n    image: influxdb:2.7\n",
+		"      - influxdb-data:/var/lib/influxdb2\n",
+		"DOCKER_INFLUXDB_INIT_MODE: setup",
+		"DOCKER_INFLUXDB_INIT_ORG: sensors",
+		"DOCKER_INFLUXDB_INIT_BUCKET: sensors_dev",
+		"DOCKER_INFLUXDB_INIT_ADMIN_TOKEN: sensors-dev-token",
+		`test: ["CMD", "influx", "ping"]`,
+		`"8086:8086"`,
+		"      - INFLUXDB_URL=http://influxdb:8086\n",
+		"      - INFLUXDB_TOKEN=sensors-dev-token\n",
+		"      - INFLUXDB_ORG=sensors\n",
+		"      - INFLUXDB_BUCKET=sensors_dev\n",
+		"\n  influxdb-data:\n",
+		"\n  influxdb-config:\n",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+		}
+	}
+}
+
+// TestComposeGenerator_ImportedInfluxDB tests pointing the app at the project's own InfluxDB.
+func TestComposeGenerator_ImportedInfluxDB(t *testing.T) {
+	detection := &models.Detection{
+		Language: "go",
+		Version:  "1.23",
+		Services: []string{"influxdb"},
+		ExistingCompose: &models.ExistingCompose{
+			File: "docker-compose.yml",
+			Services: []models.ExistingService{{
+				Name:       "tsdb",
+				Role:       "influxdb",
+				Definition: map[string]interface{}{"image": "influxdb:2.7"},
+			}},
+		},
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "sensors")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+	if !strings.Contains(compose, "      - INFLUXDB_URL=http://tsdb:8086\n") {
+		t.Errorf("app should connect to the imported service, got:\n%s", compose)
+	}
+	// The imported service has its own token, which dockstart doesn't know
+	if strings.Contains(compose, "INFLUXDB_TOKEN") {
+		t.Errorf("docker-compose.yml should not set a token for an imported InfluxDB, got:\n%s", compose)
+	}
+}
+
+// TestComposeGenerator_TimescaleDB tests running PostgreSQL with the TimescaleDB image.
+func TestComposeGenerator_TimescaleDB(t *testing.T) {
+	tests := []struct {
+		name     string
+		vector   []string
+		want     []string
+		dontWant []string
+	}{
+		{
+			name: "timescaledb",
+			want: []string{
+				"  postgres:\n    # TimescaleDB image; the extension is created in the default database on first start\n    image: timescale/timescaledb:latest-pg16\n",
+				"  postgres-test:\n    image: timescale/timescaledb:latest-pg16\n",
+			},
+			dontWant: []string{"image: postgres:16-alpine"},
+		},
+		{
+			name:   "pgvector takes precedence",
+			vector: []string{"pgvector"},
+			want: []string{
+				"image: pgvector/pgvector:pg16",
+			},
+			dontWant: []string{"timescale/timescaledb"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detection := &models.Detection{
+				Language:    "python",
+				Version:     "3.12",
+				Services:    []string{"postgres"},
+				TimescaleDB: true,
+				Capabilities: models.Capabilities{
+					VectorLibraries: tt.vector,
+				},
+				Testing: models.TestingOptions{Isolation: models.TestIsolationService},
+			}
+			content, err := NewComposeGenerator().GenerateContent(detection, "sensors")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+			compose := string(content)
+
+			for _, want := range tt.want {
+				if !strings.Contains(compose, want) {
+					t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+				}
+			}
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(compose, dontWant) {
+					t.Errorf("docker-compose.yml should NOT contain %q", dontWant)
+				}
+			}
+		})
+	}
+}
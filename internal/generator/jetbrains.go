@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// JetBrainsRunDir holds the shared run configurations, relative to the project
+// root. IntelliJ IDEA, GoLand, PyCharm, WebStorm, and RustRover load the
+// *.run.xml files in it, locally and through Gateway.
+const JetBrainsRunDir = ".run"
+
+// jetbrainsBackends are the IDEs JetBrains Gateway opens each language's dev
+// container with (customizations.jetbrains.backend in devcontainer.json).
+var jetbrainsBackends = map[string]string{
+	"node":   "WebStorm",
+	"go":     "GoLand",
+	"python": "PyCharm",
+	"rust":   "RustRover",
+}
+
+// JetBrainsBackend returns the IDE Gateway runs in the dev container, IntelliJ
+// IDEA for languages without their own IDE.
+func JetBrainsBackend(language string) string {
+	if backend, ok := jetbrainsBackends[language]; ok {
+		return backend
+	}
+	return "IntelliJ"
+}
+
+// JetBrainsRunConfig is a Docker Compose run configuration.
+type JetBrainsRunConfig struct {
+	// Name is the name in the IDE's run configuration list (e.g., "Dev stack")
+	Name string
+
+	// File is the configuration's path, relative to the project root
+	File string
+
+	// ComposeFile is the compose file, relative to the project root
+	ComposeFile string
+
+	// ComposeProject is the compose project the dev container runs under, so
+	// the IDE and "Reopen in Container" share the same stack
+	ComposeProject string
+
+	// Services are the services started, or every service when empty
+	Services []string
+}
+
+// JetBrainsGenerator generates JetBrains run configurations that start the
+// generated compose stack from the IDE's Services tool window.
+type JetBrainsGenerator struct {
+	output
+}
+
+// NewJetBrainsGenerator creates a new JetBrains run configuration generator.
+func NewJetBrainsGenerator() *JetBrainsGenerator {
+	return &JetBrainsGenerator{}
+}
+
+// RunConfigs returns the run configurations for the detection: the whole
+// stack, and the one-shot test service when a test command was detected.
+// composeProject is the compose project name the devcontainer runs under
+// (e.g., "myapp_devcontainer").
+func (g *JetBrainsGenerator) RunConfigs(detection *models.Detection, composeProject string) []JetBrainsRunConfig {
+	composeFile := path.Join(".devcontainer", "docker-compose.yml")
+	configs := []JetBrainsRunConfig{{
+		Name:           "Dev stack",
+		File:           path.Join(JetBrainsRunDir, "dev-stack.run.xml"),
+		ComposeFile:    composeFile,
+		ComposeProject: composeProject,
+	}}
+	if detection.TestCommand != "" {
+		// Naming the service enables its test profile
+		configs = append(configs, JetBrainsRunConfig{
+			Name:           "Tests",
+			File:           path.Join(JetBrainsRunDir, "tests.run.xml"),
+			ComposeFile:    composeFile,
+			ComposeProject: composeProject,
+			Services:       []string{TestService},
+		})
+	}
+	return configs
+}
+
+// Generate writes the run configurations under .run/.
+func (g *JetBrainsGenerator) Generate(detection *models.Detection, projectPath, composeProject string) error {
+	if err := g.fs().MkdirAll(filepath.Join(projectPath, JetBrainsRunDir), 0755); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", JetBrainsRunDir, err)
+	}
+	for _, config := range g.RunConfigs(detection, composeProject) {
+		content, err := g.GenerateContent(config)
+		if err != nil {
+			return err
+		}
+		if err := g.fs().WriteFile(filepath.Join(projectPath, filepath.FromSlash(config.File)), content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", config.File, err)
+		}
+	}
+	return nil
+}
+
+// GenerateContent returns a run configuration without writing it to disk.
+func (g *JetBrainsGenerator) GenerateContent(config JetBrainsRunConfig) ([]byte, error) {
+	return renderTemplate("jetbrains/compose.run.xml.tmpl", config)
+}
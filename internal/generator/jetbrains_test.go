@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestJetBrainsGenerator tests the compose run configurations.
+func TestJetBrainsGenerator(t *testing.T) {
+	detection := &models.Detection{
+		Language:    "go",
+		Version:     "1.23",
+		Services:    []string{"postgres"},
+		TestCommand: "go test ./...",
+	}
+
+	tmpDir := t.TempDir()
+	if err := NewJetBrainsGenerator().Generate(detection, tmpDir, "shop_devcontainer"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{
+			file: ".run/dev-stack.run.xml",
+			want: []string{
+				`name="Dev stack" type="docker-deploy" factoryName="docker-compose.yml"`,
+				`<option name="sourceFilePath" value="$PROJECT_DIR$/.devcontainer/docker-compose.yml" />`,
+				`<option name="value" value="shop_devcontainer" />`,
+			},
+		},
+		{
+			file: ".run/tests.run.xml",
+			want: []string{`<option value="test" />`},
+		},
+	}
+	for _, tt := range tests {
+		content, err := os.ReadFile(filepath.Join(tmpDir, filepath.FromSlash(tt.file)))
+		if err != nil {
+			t.Fatalf("%s not written: %v", tt.file, err)
+		}
+		if err := xml.Unmarshal(content, new(struct{})); err != nil {
+			t.Errorf("%s is not valid XML: %v", tt.file, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s should contain %q, got:\n%s", tt.file, want, content)
+			}
+		}
+	}
+	if content, _ := os.ReadFile(filepath.Join(tmpDir, ".run", "dev-stack.run.xml")); strings.Contains(string(content), `name="services"`) {
+		t.Error("Dev stack should start every service")
+	}
+
+	detection.TestCommand = ""
+	if configs := NewJetBrainsGenerator().RunConfigs(detection, "shop_devcontainer"); len(configs) != 1 {
+		t.Errorf("RunConfigs() = %d configurations, want only Dev stack without a test command", len(configs))
+	}
+}
+
+// TestDevcontainerGenerator_JetBrainsBackend tests the IDE Gateway opens each language with.
+func TestDevcontainerGenerator_JetBrainsBackend(t *testing.T) {
+	tests := map[string]string{
+		"node":    "WebStorm",
+		"go":      "GoLand",
+		"python":  "PyCharm",
+		"rust":    "RustRover",
+		"unknown": "IntelliJ",
+	}
+	for language, want := range tests {
+		content, err := NewDevcontainerGenerator().GenerateContent(&models.Detection{Language: language, Version: "1"}, "shop")
+		if err != nil {
+			t.Fatalf("%s: GenerateContent() error = %v", language, err)
+		}
+		var devcontainer struct {
+			Customizations struct {
+				JetBrains struct {
+					Backend string `json:"backend"`
+				} `json:"jetbrains"`
+			} `json:"customizations"`
+		}
+		if err := json.Unmarshal(content, &devcontainer); err != nil {
+			t.Fatalf("%s: devcontainer.json is not valid JSON: %v\n%s", language, err, content)
+		}
+		if got := devcontainer.Customizations.JetBrains.Backend; got != want {
+			t.Errorf("%s: customizations.jetbrains.backend = %q, want %q", language, got, want)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonObject is a JSON object that keeps its key order, so hand-written files
+// round-trip without their keys being reshuffled.
+type jsonObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// newJSONObject creates an empty jsonObject.
+func newJSONObject() *jsonObject {
+	return &jsonObject{values: make(map[string]interface{})}
+}
+
+// Get returns the value of a key.
+func (o *jsonObject) Get(key string) (interface{}, bool) {
+	value, ok := o.values[key]
+	return value, ok
+}
+
+// Set sets the value of a key, appending the key if it is new.
+func (o *jsonObject) Set(key string, value interface{}) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// Delete removes a key.
+func (o *jsonObject) Delete(key string) {
+	if _, ok := o.values[key]; !ok {
+		return
+	}
+	delete(o.values, key)
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// MarshalJSON writes the object's keys in order.
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodedKey, err := marshalJSON(key)
+		if err != nil {
+			return nil, err
+		}
+		encodedValue, err := marshalJSON(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(encodedValue)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalJSON encodes a value without escaping HTML characters, which are
+// common in commands (e.g., "npm install && npm run build").
+func marshalJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// indentJSON encodes an object with tab indentation, as dockstart's templates do.
+func indentJSON(o *jsonObject) ([]byte, error) {
+	compact, err := marshalJSON(o)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, compact, "", "\t"); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// parseJSONC parses a JSON object that may contain comments and trailing commas,
+// as devcontainer.json files do. Comments are not preserved.
+func parseJSONC(data []byte) (*jsonObject, error) {
+	decoder := json.NewDecoder(bytes.NewReader(stripJSONC(data)))
+	decoder.UseNumber()
+
+	value, err := decodeJSONValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	object, ok := value.(*jsonObject)
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+	return object, nil
+}
+
+// decodeJSONValue decodes the next value, keeping the key order of objects.
+func decodeJSONValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		object := newJSONObject()
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			object.Set(key.(string), value)
+		}
+		_, err := decoder.Token()
+		return object, err
+	case '[':
+		array := []interface{}{}
+		for decoder.More() {
+			value, err := decodeJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err := decoder.Token()
+		return array, err
+	default:
+		return nil, fmt.Errorf("unexpected %v", delim)
+	}
+}
+
+// stripJSONC removes // and /* */ comments and trailing commas outside strings.
+func stripJSONC(data []byte) []byte {
+	var stripped []byte
+	inString := false
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			stripped = append(stripped, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				stripped = append(stripped, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			stripped = append(stripped, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				stripped = append(stripped, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return stripped
+			}
+			i += end + 3
+		case c == ',' && trailingComma(data[i+1:]):
+			// Dropped: only whitespace and comments separate it from } or ]
+		default:
+			stripped = append(stripped, c)
+		}
+	}
+
+	return stripped
+}
+
+// trailingComma reports whether the rest of the input, past whitespace and
+// comments, closes an object or array.
+func trailingComma(rest []byte) bool {
+	for i := 0; i < len(rest); i++ {
+		switch c := rest[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+		case c == '/' && i+1 < len(rest) && rest[i+1] == '/':
+			for i < len(rest) && rest[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(rest) && rest[i+1] == '*':
+			end := bytes.Index(rest[i+2:], []byte("*/"))
+			if end < 0 {
+				return false
+			}
+			i += end + 3
+		default:
+			return c == '}' || c == ']'
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"testing"
+)
+
+// TestParseJSONC tests parsing devcontainer.json files with comments and trailing commas.
+func TestParseJSONC(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "plain JSON keeps key order",
+			input: `{"name": "app", "image": "go", "forwardPorts": [8080, 5432]}`,
+			want:  "{\n\t\"name\": \"app\",\n\t\"image\": \"go\",\n\t\"forwardPorts\": [\n\t\t8080,\n\t\t5432\n\t]\n}\n",
+		},
+		{
+			name: "comments and trailing commas",
+			input: `// header
+{
+	"name": "app", // trailing comment
+	/* block
+	   comment */
+	"ports": [1, 2,],
+	"url": "http://example.com/*not a comment*/",
+}`,
+			want: "{\n\t\"name\": \"app\",\n\t\"ports\": [\n\t\t1,\n\t\t2\n\t],\n\t\"url\": \"http://example.com/*not a comment*/\"\n}\n",
+		},
+		{
+			name:  "commands are not HTML-escaped",
+			input: `{"postCreateCommand": "npm install && npm run build > /dev/null"}`,
+			want:  "{\n\t\"postCreateCommand\": \"npm install && npm run build > /dev/null\"\n}\n",
+		},
+		{
+			name:  "escaped quotes inside strings",
+			input: `{"cmd": "echo \"// hi\", done",}`,
+			want:  "{\n\t\"cmd\": \"echo \\\"// hi\\\", done\"\n}\n",
+		},
+		{
+			name:    "not an object",
+			input:   `["a"]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			input:   `{"name": }`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			object, err := parseJSONC([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseJSONC() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got, err := indentJSON(object)
+			if err != nil {
+				t.Fatalf("indentJSON() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("round-trip =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
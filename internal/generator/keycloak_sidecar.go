@@ -0,0 +1,93 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// KeycloakSidecarConfig holds configuration for generating the Keycloak realm import.
+type KeycloakSidecarConfig struct {
+	// ProjectName is the name of the project
+	ProjectName string
+
+	// Realm is the name of the imported realm
+	Realm string
+
+	// ClientID is the OIDC client the app authenticates as
+	ClientID string
+
+	// ClientSecret is the OIDC client secret (development only)
+	ClientSecret string
+
+	// AppURL is the browser-facing app URL used for redirect URIs
+	AppURL string
+
+	// TestUser is the username of the pre-created test user
+	TestUser string
+
+	// TestPassword is the password of the pre-created test user
+	TestPassword string
+}
+
+// DefaultKeycloakConfig returns a KeycloakSidecarConfig with sensible defaults.
+func DefaultKeycloakConfig() *KeycloakSidecarConfig {
+	return &KeycloakSidecarConfig{
+		Realm:        "dev",
+		ClientID:     "app",
+		ClientSecret: "dev-client-secret",
+		AppURL:       "http://localhost:3000",
+		TestUser:     "dev",
+		TestPassword: "dev",
+	}
+}
+
+// KeycloakSidecarGenerator generates the Keycloak realm import file.
+type KeycloakSidecarGenerator struct {
+	output
+}
+
+// NewKeycloakSidecarGenerator creates a new Keycloak sidecar generator.
+func NewKeycloakSidecarGenerator() *KeycloakSidecarGenerator {
+	return &KeycloakSidecarGenerator{}
+}
+
+// GenerateRealm generates the realm.json content imported by Keycloak on startup.
+func (g *KeycloakSidecarGenerator) GenerateRealm(config *KeycloakSidecarConfig) ([]byte, error) {
+	return renderTemplate("keycloak/realm.json.tmpl", config)
+}
+
+// Generate creates .devcontainer/keycloak/realm.json.
+func (g *KeycloakSidecarGenerator) Generate(detection *models.Detection, outputPath, projectName string) error {
+	config := g.buildConfig(detection, projectName)
+
+	keycloakDir := filepath.Join(outputPath, ".devcontainer", "keycloak")
+	if err := g.fs().MkdirAll(keycloakDir, 0755); err != nil {
+		return fmt.Errorf("failed to create keycloak directory: %w", err)
+	}
+
+	realm, err := g.GenerateRealm(config)
+	if err != nil {
+		return err
+	}
+	if err := g.fs().WriteFile(filepath.Join(keycloakDir, "realm.json"), realm, 0644); err != nil {
+		return fmt.Errorf("failed to write keycloak realm: %w", err)
+	}
+
+	return nil
+}
+
+// buildConfig creates a KeycloakSidecarConfig from detection results.
+func (g *KeycloakSidecarGenerator) buildConfig(detection *models.Detection, projectName string) *KeycloakSidecarConfig {
+	config := DefaultKeycloakConfig()
+	config.ProjectName = projectName
+	config.AppURL = fmt.Sprintf("http://localhost:%d", detection.GetAppPort())
+	return config
+}
+
+// ShouldGenerate returns true if the Keycloak sidecar should be generated.
+func (g *KeycloakSidecarGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.NeedsAuthProvider()
+}
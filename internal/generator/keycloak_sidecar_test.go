@@ -0,0 +1,132 @@
+package generator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestKeycloakSidecar_Compose tests Keycloak generation in docker-compose.yml.
+func TestKeycloakSidecar_Compose(t *testing.T) {
+	gen := NewComposeGenerator()
+
+	t.Run("auth library adds keycloak and wires the issuer", func(t *testing.T) {
+		detection := &models.Detection{
+			Language: "node",
+			Version:  "20",
+			Capabilities: models.Capabilities{
+				AuthLibraries: []string{"next-auth"},
+			},
+		}
+
+		content, err := gen.GenerateContent(detection, "web")
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+
+		yamlContent := string(content)
+		for _, want := range []string{
+			"keycloak:",
+			"image: quay.io/keycloak/keycloak:24.0",
+			"command: start-dev --import-realm",
+			`"8180:8080"`,
+			"./keycloak:/opt/keycloak/data/import:ro",
+			"OIDC_ISSUER_URL=http://keycloak:8080/realms/dev",
+			"OIDC_PUBLIC_ISSUER_URL=http://localhost:8180/realms/dev",
+			"OIDC_CLIENT_ID=app",
+			"OIDC_CLIENT_SECRET=dev-client-secret",
+		} {
+			if !strings.Contains(yamlContent, want) {
+				t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, yamlContent)
+			}
+		}
+
+		var parsed map[string]interface{}
+		if err := yaml.Unmarshal(content, &parsed); err != nil {
+			t.Errorf("Generated YAML is invalid: %v", err)
+		}
+	})
+
+	t.Run("no auth library", func(t *testing.T) {
+		detection := &models.Detection{
+			Language: "go",
+			Version:  "1.23",
+			Services: []string{"postgres"},
+		}
+
+		content, err := gen.GenerateContent(detection, "api")
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		if strings.Contains(string(content), "keycloak") || strings.Contains(string(content), "OIDC_") {
+			t.Error("docker-compose.yml should NOT contain keycloak without auth libraries")
+		}
+	})
+}
+
+// TestKeycloakSidecarGenerator_Generate tests the realm import file.
+func TestKeycloakSidecarGenerator_Generate(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "dockstart-keycloak-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	gen := NewKeycloakSidecarGenerator()
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Capabilities: models.Capabilities{
+			AuthLibraries: []string{"authlib"},
+		},
+	}
+
+	if !gen.ShouldGenerate(detection) {
+		t.Fatal("ShouldGenerate() = false, want true")
+	}
+	if err := gen.Generate(detection, tmpDir, "shop"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, ".devcontainer", "keycloak", "realm.json"))
+	if err != nil {
+		t.Fatalf("Failed to read realm.json: %v", err)
+	}
+
+	var realm struct {
+		Realm   string `json:"realm"`
+		Clients []struct {
+			ClientID     string   `json:"clientId"`
+			Secret       string   `json:"secret"`
+			RedirectURIs []string `json:"redirectUris"`
+		} `json:"clients"`
+		Users []struct {
+			Username string `json:"username"`
+		} `json:"users"`
+	}
+	if err := json.Unmarshal(content, &realm); err != nil {
+		t.Fatalf("realm.json is invalid: %v\n%s", err, content)
+	}
+
+	if realm.Realm != "dev" {
+		t.Errorf("realm = %q, want %q", realm.Realm, "dev")
+	}
+	if len(realm.Clients) != 1 {
+		t.Fatalf("Expected 1 client, got %d", len(realm.Clients))
+	}
+	client := realm.Clients[0]
+	if client.ClientID != "app" || client.Secret != "dev-client-secret" {
+		t.Errorf("client = %q/%q, want app/dev-client-secret", client.ClientID, client.Secret)
+	}
+	if len(client.RedirectURIs) != 1 || client.RedirectURIs[0] != "http://localhost:8000/*" {
+		t.Errorf("redirectUris = %v, want [http://localhost:8000/*]", client.RedirectURIs)
+	}
+	if len(realm.Users) != 1 || realm.Users[0].Username != "dev" {
+		t.Errorf("users = %v, want a single dev user", realm.Users)
+	}
+}
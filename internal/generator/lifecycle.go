@@ -0,0 +1,138 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// MigrateScript is the wait-and-migrate script, relative to the project root.
+// postStartCommand runs it from the workspace folder.
+const MigrateScript = ".devcontainer/scripts/migrate.sh"
+
+// gitSafeDirectoryCommand marks the workspace as safe for git, which refuses
+// repositories owned by another user (e.g., a bind mount from the host).
+const gitSafeDirectoryCommand = "git config --global --add safe.directory /workspace"
+
+// databasePorts are the ports migrate.sh waits on.
+var databasePorts = map[string]int{
+	"postgres": 5432,
+	"mysql":    3306,
+}
+
+// migrationCLI is a migration tool's command-line binary, which isn't a
+// project dependency and is installed on first use.
+type migrationCLI struct {
+	binary  string
+	install string
+}
+
+// migrationCLIs lists the migration tools whose CLI migrate.sh installs when missing.
+var migrationCLIs = map[string]migrationCLI{
+	"golang-migrate": {"migrate", "go install -tags 'postgres mysql' github.com/golang-migrate/migrate/v4/cmd/migrate@latest"},
+	"goose":          {"goose", "go install github.com/pressly/goose/v3/cmd/goose@latest"},
+	"diesel":         {"diesel", "cargo install diesel_cli --no-default-features --features postgres,mysql"},
+	"sqlx":           {"sqlx", "cargo install sqlx-cli --no-default-features --features rustls,postgres,mysql"},
+}
+
+// MigrateConfig holds the configuration for generating migrate.sh.
+type MigrateConfig struct {
+	// Tool is the detected migration tool, or empty when the command is configured
+	Tool string
+
+	// Command applies the migrations
+	Command string
+
+	// Host and Port are the database's compose service and port
+	Host string
+	Port int
+
+	// Binary is the migration CLI installed with Install when it is missing
+	Binary  string
+	Install string
+}
+
+// MigrateGenerator generates .devcontainer/scripts/migrate.sh, which waits for
+// the database to accept connections and applies pending migrations.
+type MigrateGenerator struct {
+	output
+}
+
+// NewMigrateGenerator creates a new migrate script generator.
+func NewMigrateGenerator() *MigrateGenerator {
+	return &MigrateGenerator{}
+}
+
+// Generate creates .devcontainer/scripts/migrate.sh.
+func (g *MigrateGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	content, err := g.GenerateContent(detection)
+	if err != nil {
+		return err
+	}
+
+	scriptsDir := filepath.Join(projectPath, ".devcontainer", "scripts")
+	if err := g.fs().MkdirAll(scriptsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create scripts directory: %w", err)
+	}
+	if err := g.fs().WriteFile(filepath.Join(scriptsDir, "migrate.sh"), content, 0755); err != nil {
+		return fmt.Errorf("failed to write migrate.sh: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateContent returns the migrate.sh content without writing to disk.
+func (g *MigrateGenerator) GenerateContent(detection *models.Detection) ([]byte, error) {
+	return renderTemplate("migrate.sh.tmpl", g.buildConfig(detection))
+}
+
+// ShouldGenerate returns true if migrate.sh should be generated: migrations
+// run against a database in the generated docker-compose.yml.
+func (g *MigrateGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.NeedsMigrations()
+}
+
+// buildConfig creates a MigrateConfig from a Detection.
+func (g *MigrateGenerator) buildConfig(detection *models.Detection) *MigrateConfig {
+	database := detection.MigrationDatabase()
+	host := detection.ExistingServiceFor(database)
+	if host == "" {
+		host = database
+	}
+
+	config := &MigrateConfig{
+		Tool:    detection.MigrationTool,
+		Command: detection.MigrateCommand,
+		Host:    host,
+		Port:    databasePorts[database],
+	}
+	// A configured command may not use the detected tool's CLI
+	if cli, ok := migrationCLIs[detection.MigrationTool]; ok && strings.HasPrefix(detection.MigrateCommand, cli.binary+" ") {
+		config.Binary = cli.binary
+		config.Install = cli.install
+	}
+	return config
+}
+
+// installCommand returns the detected dependency install command, or fallback
+// when the package manager is unknown.
+func installCommand(detection *models.Detection, fallback string) string {
+	if detection.InstallCommand != "" {
+		return detection.InstallCommand
+	}
+	return fallback
+}
+
+// joinCommands joins the non-empty commands with &&.
+func joinCommands(commands ...string) string {
+	var parts []string
+	for _, command := range commands {
+		if command != "" {
+			parts = append(parts, command)
+		}
+	}
+	return strings.Join(parts, " && ")
+}
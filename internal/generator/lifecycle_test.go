@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestDevcontainerGenerator_Lifecycle tests the generated lifecycle commands.
+func TestDevcontainerGenerator_Lifecycle(t *testing.T) {
+	tests := []struct {
+		name           string
+		detection      *models.Detection
+		wantPostCreate string
+		wantPostStart  string
+	}{
+		{
+			name: "detected install command and migrations",
+			detection: &models.Detection{
+				Language:       "node",
+				Version:        "20",
+				Services:       []string{"postgres"},
+				InstallCommand: "npm ci",
+				MigrationTool:  "prisma",
+				MigrateCommand: "npx prisma migrate deploy",
+			},
+			wantPostCreate: "git config --global --add safe.directory /workspace && npm ci",
+			wantPostStart:  "bash .devcontainer/scripts/migrate.sh",
+		},
+		{
+			name: "no database to migrate",
+			detection: &models.Detection{
+				Language:       "python",
+				Version:        "3.12",
+				InstallCommand: "uv sync",
+				MigrationTool:  "alembic",
+				MigrateCommand: "uv run alembic upgrade head",
+			},
+			wantPostCreate: "git config --global --add safe.directory /workspace && uv sync",
+		},
+		{
+			name: "configured overrides",
+			detection: &models.Detection{
+				Language:       "go",
+				Version:        "1.23",
+				Services:       []string{"postgres"},
+				MigrateCommand: "make migrate",
+				Lifecycle: models.LifecycleOptions{
+					PostCreate: `make setup NAME="dev"`,
+					PostStart:  "make seed",
+				},
+			},
+			wantPostCreate: `make setup NAME="dev"`,
+			wantPostStart:  "make seed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := NewDevcontainerGenerator().GenerateContent(tt.detection, "app")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+
+			var result map[string]interface{}
+			if err := json.Unmarshal(content, &result); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if got, _ := result["postCreateCommand"].(string); got != tt.wantPostCreate {
+				t.Errorf("postCreateCommand = %q, want %q", got, tt.wantPostCreate)
+			}
+			if got, _ := result["postStartCommand"].(string); got != tt.wantPostStart {
+				t.Errorf("postStartCommand = %q, want %q", got, tt.wantPostStart)
+			}
+		})
+	}
+}
+
+// TestMigrateGenerator tests the wait-and-migrate script.
+func TestMigrateGenerator(t *testing.T) {
+	detection := &models.Detection{
+		Language:       "rust",
+		Version:        "1.80",
+		Services:       []string{"mysql"},
+		MigrationTool:  "diesel",
+		MigrateCommand: "diesel migration run",
+	}
+
+	gen := NewMigrateGenerator()
+	if !gen.ShouldGenerate(detection) {
+		t.Fatal("ShouldGenerate() = false, want true")
+	}
+
+	content, err := gen.GenerateContent(detection)
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	script := string(content)
+	for _, want := range []string{
+		"#!/bin/bash",
+		`DB_HOST="${DB_HOST:-mysql}"`,
+		`DB_PORT="${DB_PORT:-3306}"`,
+		"if ! command -v diesel >/dev/null 2>&1; then",
+		"cargo install diesel_cli",
+		"Applying migrations with diesel...",
+		"\ndiesel migration run\n",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("migrate.sh should contain %q, got:\n%s", want, script)
+		}
+	}
+
+	// A configured command doesn't need the detected tool's CLI
+	detection.MigrateCommand = "cargo run --bin migrate"
+	content, err = gen.GenerateContent(detection)
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if strings.Contains(string(content), "cargo install") {
+		t.Errorf("migrate.sh should not install diesel_cli for a configured command, got:\n%s", content)
+	}
+
+	if gen.ShouldGenerate(&models.Detection{Language: "rust", MigrateCommand: "diesel migration run"}) {
+		t.Error("ShouldGenerate() = true without a database, want false")
+	}
+}
@@ -0,0 +1,38 @@
+package generator
+
+import (
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// timezoneServices are the compose services that get TZ: the app, and the
+// containers that run scheduled jobs or name backups after the time.
+var timezoneServices = []string{"app", "worker", "scheduler", "db-backup"}
+
+// addTimezone sets TZ in the environment of the timezoneServices, so
+// scheduled jobs and log timestamps follow the timezone instead of UTC.
+func addTimezone(services *yaml.Node, timezone string) {
+	if timezone == "" {
+		return
+	}
+	for _, name := range timezoneServices {
+		if service := mappingValue(services, name); service != nil && service.Kind == yaml.MappingNode {
+			addEnv(service, "environment", []EnvVar{{Name: "TZ", Value: timezone}})
+		}
+	}
+}
+
+// localeInput returns the localedef input of a locale (e.g., "fr_FR" for
+// "fr_FR.UTF-8", "sr_RS@latin" for "sr_RS.UTF-8@latin"), or "" for C.UTF-8,
+// which glibc has built in.
+func localeInput(locale string) string {
+	lang, rest, _ := strings.Cut(locale, ".")
+	if lang == "" || lang == "C" {
+		return ""
+	}
+	if _, modifier, ok := strings.Cut(rest, "@"); ok {
+		return lang + "@" + modifier
+	}
+	return lang
+}
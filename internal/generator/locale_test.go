@@ -0,0 +1,114 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestDockerfileGenerator_Locale tests the timezone and locale set up in the
+// generated Dockerfile.
+func TestDockerfileGenerator_Locale(t *testing.T) {
+	tests := []struct {
+		name     string
+		timezone string
+		locale   string
+		want     string
+	}{
+		{
+			name:     "timezone and locale",
+			timezone: "Europe/Paris",
+			locale:   "fr_FR.UTF-8",
+			want: "# Timezone and locale (timezone and locale in .dockstart.yml)\n" +
+				"ENV TZ=\"Europe/Paris\"\n" +
+				"RUN apt-get update && DEBIAN_FRONTEND=noninteractive apt-get install -y tzdata locales \\\n" +
+				"    && ln -snf \"/usr/share/zoneinfo/$TZ\" /etc/localtime && echo \"$TZ\" > /etc/timezone \\\n" +
+				"    && localedef -i fr_FR -c -f UTF-8 -A /usr/share/locale/locale.alias fr_FR.UTF-8 \\\n" +
+				"    && rm -rf /var/lib/apt/lists/*\n" +
+				"ENV LANG=\"fr_FR.UTF-8\" LC_ALL=\"fr_FR.UTF-8\"\n\n",
+		},
+		{
+			name:     "timezone only",
+			timezone: "America/New_York",
+			want: "ENV TZ=\"America/New_York\"\n" +
+				"RUN apt-get update && DEBIAN_FRONTEND=noninteractive apt-get install -y tzdata \\\n" +
+				"    && ln -snf \"/usr/share/zoneinfo/$TZ\" /etc/localtime && echo \"$TZ\" > /etc/timezone \\\n" +
+				"    && rm -rf /var/lib/apt/lists/*\n\n",
+		},
+		{
+			name:   "built-in locale",
+			locale: "C.UTF-8",
+			want: "# Timezone and locale (timezone and locale in .dockstart.yml)\n" +
+				"ENV LANG=\"C.UTF-8\" LC_ALL=\"C.UTF-8\"\n\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detection := &models.Detection{Language: "go", Version: "1.23", Timezone: tt.timezone, Locale: tt.locale}
+			content, err := NewDockerfileGenerator().GenerateContent(detection, "api")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+			if !strings.Contains(string(content), tt.want) {
+				t.Errorf("Dockerfile should contain:\n%s\ngot:\n%s", tt.want, content)
+			}
+		})
+	}
+
+	content, err := NewDockerfileGenerator().GenerateContent(&models.Detection{Language: "go", Version: "1.23"}, "api")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if strings.Contains(string(content), "Timezone") {
+		t.Errorf("Dockerfile without a timezone or locale should not set them, got:\n%s", content)
+	}
+}
+
+// TestComposeGenerator_Timezone tests TZ in the app and backup services,
+// and tzdata in the backup image.
+func TestComposeGenerator_Timezone(t *testing.T) {
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Services: []string{"postgres", "redis"},
+		Sidecars: models.SidecarPolicy{With: []string{models.SidecarBackup}},
+		Timezone: "Europe/Paris",
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	for _, service := range []string{"app", "db-backup"} {
+		if env, _ := composeEnv(t, content, service); env["TZ"] != "Europe/Paris" {
+			t.Errorf("%s TZ = %q, want Europe/Paris", service, env["TZ"])
+		}
+	}
+	if env, _ := composeEnv(t, content, "postgres"); env["TZ"] != "" {
+		t.Errorf("postgres TZ = %q, want it unset", env["TZ"])
+	}
+
+	dockerfile, err := NewBackupSidecarGenerator().GenerateDockerfile(&BackupSidecarConfig{HasPostgres: true, Timezone: "Europe/Paris"})
+	if err != nil {
+		t.Fatalf("GenerateDockerfile() error = %v", err)
+	}
+	if !strings.Contains(string(dockerfile), "    tzdata \\\n") {
+		t.Errorf("Dockerfile.backup should install tzdata, got:\n%s", dockerfile)
+	}
+}
+
+// TestLocaleInput tests the localedef input of locales.
+func TestLocaleInput(t *testing.T) {
+	tests := map[string]string{
+		"fr_FR.UTF-8":       "fr_FR",
+		"sr_RS.UTF-8@latin": "sr_RS@latin",
+		"C.UTF-8":           "",
+		"":                  "",
+	}
+	for locale, want := range tests {
+		if got := localeInput(locale); got != want {
+			t.Errorf("localeInput(%q) = %q, want %q", locale, got, want)
+		}
+	}
+}
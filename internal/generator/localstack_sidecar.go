@@ -0,0 +1,125 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// LocalStackSidecarConfig holds configuration for generating the LocalStack init script.
+type LocalStackSidecarConfig struct {
+	// ProjectName is the name of the project
+	ProjectName string
+
+	// ResourcePrefix is the name used for created queues, tables, and buckets
+	ResourcePrefix string
+
+	// Region is the AWS region resources are created in (default: us-east-1)
+	Region string
+
+	// HasSQS indicates if an SQS queue should be created
+	HasSQS bool
+
+	// HasSNS indicates if an SNS topic should be created
+	HasSNS bool
+
+	// HasDynamoDB indicates if a DynamoDB table should be created
+	HasDynamoDB bool
+
+	// HasS3 indicates if an S3 bucket should be created
+	HasS3 bool
+
+	// HasSecretsManager indicates if a Secrets Manager secret should be created
+	HasSecretsManager bool
+
+	// HasKinesis indicates if a Kinesis stream should be created
+	HasKinesis bool
+
+	// HasLambda indicates if Lambda is used (functions must be deployed manually)
+	HasLambda bool
+}
+
+// DefaultLocalStackConfig returns a LocalStackSidecarConfig with sensible defaults.
+func DefaultLocalStackConfig() *LocalStackSidecarConfig {
+	return &LocalStackSidecarConfig{
+		Region: "us-east-1",
+	}
+}
+
+// LocalStackSidecarGenerator generates the LocalStack init script.
+type LocalStackSidecarGenerator struct {
+	output
+}
+
+// NewLocalStackSidecarGenerator creates a new LocalStack sidecar generator.
+func NewLocalStackSidecarGenerator() *LocalStackSidecarGenerator {
+	return &LocalStackSidecarGenerator{}
+}
+
+// GenerateInitScript generates the init-aws.sh content run when LocalStack is ready.
+func (g *LocalStackSidecarGenerator) GenerateInitScript(config *LocalStackSidecarConfig) ([]byte, error) {
+	return renderTemplate("localstack/init-aws.sh.tmpl", config)
+}
+
+// Generate creates .devcontainer/localstack/init-aws.sh.
+func (g *LocalStackSidecarGenerator) Generate(detection *models.Detection, outputPath, projectName string) error {
+	config := g.buildConfig(detection, projectName)
+
+	localstackDir := filepath.Join(outputPath, ".devcontainer", "localstack")
+	if err := g.fs().MkdirAll(localstackDir, 0755); err != nil {
+		return fmt.Errorf("failed to create localstack directory: %w", err)
+	}
+
+	script, err := g.GenerateInitScript(config)
+	if err != nil {
+		return err
+	}
+	if err := g.fs().WriteFile(filepath.Join(localstackDir, "init-aws.sh"), script, 0755); err != nil {
+		return fmt.Errorf("failed to write localstack init script: %w", err)
+	}
+
+	return nil
+}
+
+// buildConfig creates a LocalStackSidecarConfig from detection results.
+func (g *LocalStackSidecarGenerator) buildConfig(detection *models.Detection, projectName string) *LocalStackSidecarConfig {
+	config := DefaultLocalStackConfig()
+	config.ProjectName = projectName
+	config.ResourcePrefix = awsResourceName(projectName)
+	config.HasSQS = detection.HasAWSService("sqs")
+	config.HasSNS = detection.HasAWSService("sns")
+	config.HasDynamoDB = detection.HasAWSService("dynamodb")
+	config.HasS3 = detection.HasAWSService("s3")
+	config.HasSecretsManager = detection.HasAWSService("secretsmanager")
+	config.HasKinesis = detection.HasAWSService("kinesis")
+	config.HasLambda = detection.HasAWSService("lambda")
+	return config
+}
+
+// ShouldGenerate returns true if the LocalStack sidecar should be generated.
+func (g *LocalStackSidecarGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.NeedsLocalStack()
+}
+
+// awsResourceName converts a project name into a name valid for S3 buckets,
+// SQS queues, and DynamoDB tables (lowercase letters, digits, and hyphens).
+func awsResourceName(projectName string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(projectName) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+
+	name := strings.Trim(b.String(), "-")
+	if name == "" {
+		return "app"
+	}
+	return name
+}
@@ -0,0 +1,165 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestLocalStackSidecar_Compose tests LocalStack generation in docker-compose.yml.
+func TestLocalStackSidecar_Compose(t *testing.T) {
+	tests := []struct {
+		name        string
+		detection   *models.Detection
+		projectName string
+		wantParts   []string
+		dontWant    []string
+	}{
+		{
+			name: "sqs and dynamodb",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					AWSServices: []string{"sqs", "dynamodb"},
+				},
+			},
+			projectName: "Order_Service",
+			wantParts: []string{
+				"localstack:",
+				"image: localstack/localstack:3",
+				"SERVICES=sqs,dynamodb",
+				`"4566:4566"`,
+				"./localstack/init-aws.sh:/etc/localstack/init/ready.d/init-aws.sh:ro",
+				"AWS_ENDPOINT_URL=http://localstack:4566",
+				"AWS_ACCESS_KEY_ID=test",
+				"SQS_QUEUE_URL=http://localstack:4566/queue/us-east-1/000000000000/order-service-queue",
+				"DYNAMODB_TABLE=order-service",
+			},
+			dontWant: []string{
+				"SNS_TOPIC_ARN",
+				"S3_BUCKET",
+			},
+		},
+		{
+			name: "s3 only does not use localstack",
+			detection: &models.Detection{
+				Language: "go",
+				Version:  "1.23",
+				Capabilities: models.Capabilities{
+					AWSServices: []string{"s3"},
+				},
+			},
+			projectName: "uploads",
+			dontWant: []string{
+				"localstack",
+				"AWS_ENDPOINT_URL",
+			},
+		},
+	}
+
+	gen := NewComposeGenerator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := gen.GenerateContent(tt.detection, tt.projectName)
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+
+			yamlContent := string(content)
+
+			for _, want := range tt.wantParts {
+				if !strings.Contains(yamlContent, want) {
+					t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, yamlContent)
+				}
+			}
+
+			for _, dontWant := range tt.dontWant {
+				if strings.Contains(yamlContent, dontWant) {
+					t.Errorf("docker-compose.yml should NOT contain %q", dontWant)
+				}
+			}
+
+			var parsed map[string]interface{}
+			if err := yaml.Unmarshal(content, &parsed); err != nil {
+				t.Errorf("Generated YAML is invalid: %v", err)
+			}
+		})
+	}
+}
+
+// TestLocalStackSidecarGenerator_Generate tests the init script that creates detected resources.
+func TestLocalStackSidecarGenerator_Generate(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "dockstart-localstack-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	gen := NewLocalStackSidecarGenerator()
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Capabilities: models.Capabilities{
+			AWSServices: []string{"sqs", "sns", "dynamodb"},
+		},
+	}
+
+	if err := gen.Generate(detection, tmpDir, "orders"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	scriptPath := filepath.Join(tmpDir, ".devcontainer", "localstack", "init-aws.sh")
+	info, err := os.Stat(scriptPath)
+	if err != nil {
+		t.Fatalf("Failed to stat init-aws.sh: %v", err)
+	}
+	if info.Mode()&0111 == 0 {
+		t.Error("init-aws.sh should be executable")
+	}
+
+	content, err := os.ReadFile(scriptPath)
+	if err != nil {
+		t.Fatalf("Failed to read init-aws.sh: %v", err)
+	}
+	script := string(content)
+
+	for _, want := range []string{
+		"#!/bin/bash",
+		`awslocal sqs create-queue --queue-name "orders-queue"`,
+		`awslocal sns create-topic --name "orders-events"`,
+		"--protocol sqs",
+		`--table-name "orders"`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("init-aws.sh should contain %q, got:\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, "s3 mb") {
+		t.Error("init-aws.sh should NOT create an S3 bucket when S3 isn't used")
+	}
+}
+
+// TestAWSResourceName tests conversion of project names to AWS resource names.
+func TestAWSResourceName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"orders", "orders"},
+		{"Order_Service", "order-service"},
+		{"my.app", "my-app"},
+		{"__", "app"},
+	}
+
+	for _, tt := range tests {
+		if got := awsResourceName(tt.input); got != tt.want {
+			t.Errorf("awsResourceName(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// LogParsersFile is the generated Fluent Bit parsers file, relative to .devcontainer/.
+const LogParsersFile = "fluent-bit-parsers.conf"
+
+// logParsersPath is where the parsers file is mounted in the Fluent Bit container.
+// The image's own parsers.conf (with the json parser) stays in place next to it
+const logParsersPath = "/fluent-bit/etc/parsers-text.conf"
+
+// LogParser is a Fluent Bit regex parser that lifts the level, timestamp, and
+// message of a logging library's text output into structured fields.
+type LogParser struct {
+	// Name is the parser name (e.g., "logrus")
+	Name string
+
+	// Library is the detected logging library whose output it parses
+	Library string
+
+	// Example is a line in the library's default text format
+	Example string
+
+	// Regex captures the fields, with named groups (Onigmo syntax)
+	Regex string
+
+	// TimeKey and TimeFormat parse the captured timestamp into the record's
+	// time. Empty when the format has no timestamp
+	TimeKey    string
+	TimeFormat string
+}
+
+// logParsers are the regex parsers for text logging libraries, by library.
+// Libraries without an entry are shipped as raw lines.
+var logParsers = map[string]LogParser{
+	"logrus": {
+		Name:       "logrus",
+		Example:    `time="2024-01-02T15:04:05Z" level=info msg="Server started" port=8080`,
+		Regex:      `^time="(?<time>[^"]*)" level=(?<level>[a-z]+) msg="(?<message>(?:[^"\\]|\\.)*)"(?: (?<fields>.*))?$`,
+		TimeKey:    "time",
+		TimeFormat: "%Y-%m-%dT%H:%M:%S%z",
+	},
+	"winston": {
+		Name:    "winston-simple",
+		Example: `info: Server started {"port":3000}`,
+		Regex:   `^(?:\x1b\[\d+m)?(?<level>[a-z]+)(?:\x1b\[\d+m)?: (?<message>.*?)(?: (?<meta>\{.*\}))?$`,
+	},
+	"loguru": {
+		Name:       "loguru",
+		Example:    `2024-01-02 15:04:05.123 | INFO     | app.main:startup:42 - Server started`,
+		Regex:      `^(?<time>\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d{3}) \| (?<level>[A-Z]+) *\| (?<source>\S+) - (?<message>.*)$`,
+		TimeKey:    "time",
+		TimeFormat: "%Y-%m-%d %H:%M:%S.%L",
+	},
+}
+
+// textLogParsers returns the parsers for the detected logging libraries, in
+// detection order, when the project logs text. JSON logs are parsed by the
+// image's json parser instead.
+func textLogParsers(detection *models.Detection) []LogParser {
+	if detection.LogFormat != "text" {
+		return nil
+	}
+
+	var parsers []LogParser
+	for _, library := range detection.LoggingLibraries {
+		if parser, ok := logParsers[library]; ok {
+			parser.Library = library
+			parsers = append(parsers, parser)
+		}
+	}
+	return parsers
+}
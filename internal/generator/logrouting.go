@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LoggedService is a generated service that logs through the Fluent Bit sidecar.
+type LoggedService struct {
+	// Name is the compose service name (e.g., "db-backup")
+	Name string
+
+	// Tag is the fluentd logging driver tag, <service>.<project>. Fluent Bit
+	// prefixes it with "docker." on arrival
+	Tag string
+
+	// AppCode marks services running the project's code, whose logs are in
+	// the detected log format
+	AppCode bool
+}
+
+// LoggedServices returns the generated services that log through Fluent Bit,
+// in docker-compose.yml order, or nil without the log sidecar.
+func (c *ComposeConfig) LoggedServices() []LoggedService {
+	if !c.LogSidecar.Enabled {
+		return nil
+	}
+
+	services := []LoggedService{{Name: "app", AppCode: true}}
+	if c.WorkerSidecar.Enabled {
+		services = append(services, LoggedService{Name: "worker", AppCode: true})
+		if c.WorkerSidecar.DeadLetter && !c.WorkerSidecar.RabbitMQ {
+			services = append(services, LoggedService{Name: "worker-dlq", AppCode: true})
+		}
+	}
+	if c.SchedulerSidecar.Enabled {
+		// Without a scheduler command, Supercronic calls the app over HTTP
+		services = append(services, LoggedService{Name: "scheduler", AppCode: c.SchedulerSidecar.Command != ""})
+	}
+	if c.FileProcessorSidecar.Enabled {
+		services = append(services, LoggedService{Name: "file-processor"})
+	}
+	if c.BackupSidecar.Enabled {
+		services = append(services, LoggedService{Name: "db-backup"})
+	}
+
+	for i := range services {
+		services[i].Tag = services[i].Name + "." + c.Name
+	}
+	return services
+}
+
+// Logging renders the logging driver of a generated service. Services logging
+// through Fluent Bit get the fluentd driver, which ships their output to the
+// sidecar under the service's tag; the others keep Docker's json-file driver,
+// rotated at LogRotation's limits so long-lived environments don't fill the disk.
+func (c *ComposeConfig) Logging(service string) string {
+	for _, logged := range c.LoggedServices() {
+		if logged.Name != service {
+			continue
+		}
+		var b strings.Builder
+		b.WriteString("\n    logging:\n      driver: fluentd\n      options:")
+		b.WriteString("\n        fluentd-address: localhost:24224")
+		fmt.Fprintf(&b, "\n        tag: %s", logged.Tag)
+		b.WriteString("\n        fluentd-async: \"true\"")
+		return b.String()
+	}
+
+	var b strings.Builder
+	b.WriteString("\n    logging:\n      driver: json-file\n      options:")
+	fmt.Fprintf(&b, "\n        max-size: %q", c.LogRotation.MaxSize)
+	fmt.Fprintf(&b, "\n        max-file: \"%d\"", c.LogRotation.MaxFiles)
+	return b.String()
+}
@@ -0,0 +1,153 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// loggedDetection is a Node.js project with text logs, a worker, a scheduler,
+// file uploads, and PostgreSQL backups.
+func loggedDetection() *models.Detection {
+	return &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Services: []string{"postgres", "redis"},
+		Capabilities: models.Capabilities{
+			LoggingLibraries:    []string{"winston"},
+			QueueLibraries:      []string{"bullmq"},
+			SchedulerLibraries:  []string{"node-cron"},
+			FileUploadLibraries: []string{"multer"},
+		},
+		LogFormat: "text",
+	}
+}
+
+func TestComposeConfig_LoggedServices(t *testing.T) {
+	config := NewComposeGenerator().buildConfig(loggedDetection(), "shop")
+
+	var got []string
+	for _, service := range config.LoggedServices() {
+		got = append(got, service.Tag)
+		if wantAppCode := service.Name == "app" || service.Name == "worker"; service.AppCode != wantAppCode {
+			t.Errorf("%s AppCode = %v, want %v", service.Name, service.AppCode, wantAppCode)
+		}
+	}
+	want := "app.shop worker.shop scheduler.shop file-processor.shop db-backup.shop"
+	if strings.Join(got, " ") != want {
+		t.Errorf("LoggedServices() tags = %v, want %s", got, want)
+	}
+
+	detection := loggedDetection()
+	detection.LoggingLibraries = nil
+	if services := NewComposeGenerator().buildConfig(detection, "shop").LoggedServices(); services != nil {
+		t.Errorf("LoggedServices() = %v without the log sidecar, want nil", services)
+	}
+}
+
+func TestComposeGenerator_ServiceLogTags(t *testing.T) {
+	content, err := NewComposeGenerator().GenerateContent(loggedDetection(), "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	var compose struct {
+		Services map[string]struct {
+			Logging struct {
+				Driver  string            `yaml:"driver"`
+				Options map[string]string `yaml:"options"`
+			} `yaml:"logging"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(content, &compose); err != nil {
+		t.Fatalf("invalid YAML: %v", err)
+	}
+
+	for _, service := range []string{"app", "worker", "scheduler", "file-processor", "db-backup"} {
+		logging := compose.Services[service].Logging
+		if logging.Driver != "fluentd" || logging.Options["tag"] != service+".shop" {
+			t.Errorf("%s logging = %+v, want fluentd with tag %s.shop", service, logging, service)
+		}
+	}
+	for _, service := range []string{"postgres", "fluent-bit"} {
+		logging := compose.Services[service].Logging
+		if logging.Driver != "json-file" || logging.Options["max-size"] != "10m" || logging.Options["max-file"] != "3" {
+			t.Errorf("%s logging = %+v, want json-file rotated at 10m, 3 files", service, logging)
+		}
+	}
+}
+
+func TestLogSidecarGenerator_Routing(t *testing.T) {
+	content, err := NewLogSidecarGenerator().GenerateContent(loggedDetection(), "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	got := string(content)
+
+	wantStrings := []string{
+		"Tag_Prefix      docker.",
+		// Only services running the app's code are parsed with its log format
+		`Match_Regex     ^docker\.(app\.shop|worker\.shop)$` + "\n    Key_Name        log\n    Parser          winston-simple",
+		"Match           docker.db-backup.shop\n    Add             service db-backup",
+		"Match           docker.file-processor.shop\n    Add             service file-processor",
+		"Match           docker.scheduler.shop\n    Add             service scheduler",
+	}
+	for _, want := range wantStrings {
+		if !strings.Contains(got, want) {
+			t.Errorf("fluent-bit.conf missing %q\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Tag             docker.*") {
+		t.Error("fluent-bit.conf overrides the services' tags")
+	}
+}
+
+// TestLogRotation tests the configured rotation limits and the file output
+// of the log sidecar.
+func TestLogRotation(t *testing.T) {
+	detection := loggedDetection()
+	detection.Logging = models.LoggingOptions{MaxSize: "50m", MaxSizeBytes: 50 << 20, MaxFiles: 5, FileOutput: true}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+	for _, want := range []string{
+		"      - fluent-bit-logs:/var/log/fluentbit\n    ports:\n      - \"24224:24224\"\n",
+		"  log-rotate:\n    image: busybox:1.36\n",
+		"      - ./scripts/rotate-logs.sh:/usr/local/bin/rotate-logs.sh:ro\n",
+		"      - \"5432:5432\"\n    logging:\n      driver: json-file\n      options:\n        max-size: \"50m\"\n        max-file: \"5\"\n",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml missing %q\n%s", want, compose)
+		}
+	}
+
+	gen := NewLogSidecarGenerator()
+	conf, err := gen.GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if !strings.Contains(string(conf), "    Name            file\n    Match           *\n    Path            /var/log/fluentbit\n") {
+		t.Errorf("fluent-bit.conf doesn't write to the logs volume\n%s", conf)
+	}
+
+	script, err := gen.GenerateRotateScriptContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateRotateScriptContent() error = %v", err)
+	}
+	for _, want := range []string{`MAX_BYTES="${MAX_BYTES:-52428800}"`, `MAX_FILES="${MAX_FILES:-5}"`} {
+		if !strings.Contains(string(script), want) {
+			t.Errorf("rotate-logs.sh missing %q\n%s", want, script)
+		}
+	}
+
+	// Without file output, nothing is written that needs rotating
+	script, err = gen.GenerateRotateScriptContent(loggedDetection(), "shop")
+	if err != nil || script != nil {
+		t.Errorf("GenerateRotateScriptContent() = %q, %v; want nil without file output", script, err)
+	}
+}
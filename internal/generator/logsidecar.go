@@ -1,14 +1,20 @@
 package generator
 
 import (
-	"bytes"
 	"fmt"
-	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/jpequegn/dockstart/internal/models"
 )
 
+// LogRotateScript is the generated log rotation script, relative to .devcontainer/.
+const LogRotateScript = "scripts/rotate-logs.sh"
+
+// logDir is where Fluent Bit writes log files, in the fluent-bit-logs volume.
+const logDir = "/var/log/fluentbit"
+
 // LogSidecarConfig holds the configuration for generating log sidecar configs.
 type LogSidecarConfig struct {
 	// Name is the project name
@@ -20,12 +26,37 @@ type LogSidecarConfig struct {
 	// EnableFileOutput enables writing logs to files in addition to stdout
 	EnableFileOutput bool
 
+	// LogDir is where the log files are written
+	LogDir string
+
+	// MaxSize and MaxSizeBytes are the size log files are rotated at
+	MaxSize      string
+	MaxSizeBytes int64
+
+	// MaxFiles is the number of log files kept per service
+	MaxFiles int
+
 	// LoggingLibraries is the list of detected logging libraries
 	LoggingLibraries []string
+
+	// Parsers lift the fields out of the detected libraries' text logs
+	Parsers []LogParser
+
+	// ParsersPath is where the Parsers file is mounted in the container
+	ParsersPath string
+
+	// Services are the generated services that log through Fluent Bit
+	Services []LoggedService
+
+	// AppCodeMatch matches the tags of the services running the project's
+	// code, which the log format parsers apply to
+	AppCodeMatch string
 }
 
 // LogSidecarGenerator generates Fluent Bit configuration files.
-type LogSidecarGenerator struct{}
+type LogSidecarGenerator struct {
+	output
+}
 
 // NewLogSidecarGenerator creates a new log sidecar generator.
 func NewLogSidecarGenerator() *LogSidecarGenerator {
@@ -33,67 +64,148 @@ func NewLogSidecarGenerator() *LogSidecarGenerator {
 }
 
 // ShouldGenerate returns true if log sidecar configuration should be generated.
-// This is based on whether structured logging libraries were detected.
+// This is based on whether structured logging libraries were detected and the
+// sidecar policy.
 func (g *LogSidecarGenerator) ShouldGenerate(detection *models.Detection) bool {
-	return detection.HasStructuredLogging()
+	return detection.NeedsLogSidecar()
 }
 
 // Generate creates a Fluent Bit configuration file from a Detection.
-// The file is written to .devcontainer/fluent-bit.conf.
+// The file is written to .devcontainer/fluent-bit.conf, along with
+// .devcontainer/fluent-bit-parsers.conf when text logs are parsed and
+// .devcontainer/scripts/rotate-logs.sh when logs are written to files.
 func (g *LogSidecarGenerator) Generate(detection *models.Detection, projectPath string, projectName string) error {
-	config := g.buildConfig(detection, projectName)
+	config, err := g.buildConfig(detection, projectName)
+	if err != nil {
+		return err
+	}
 
 	// Create .devcontainer directory (may already exist)
 	devcontainerDir := filepath.Join(projectPath, ".devcontainer")
-	if err := os.MkdirAll(devcontainerDir, 0755); err != nil {
+	if err := g.fs().MkdirAll(devcontainerDir, 0755); err != nil {
 		return fmt.Errorf("failed to create .devcontainer directory: %w", err)
 	}
 
 	// Generate fluent-bit.conf content
 	content, err := g.render(config)
 	if err != nil {
-		return fmt.Errorf("failed to render template: %w", err)
+		return err
 	}
 
 	// Write to file
 	outputPath := filepath.Join(devcontainerDir, "fluent-bit.conf")
-	if err := os.WriteFile(outputPath, content, 0644); err != nil {
+	if err := g.fs().WriteFile(outputPath, content, 0644); err != nil {
 		return fmt.Errorf("failed to write fluent-bit.conf: %w", err)
 	}
 
+	if len(config.Parsers) > 0 {
+		parsers, err := g.renderParsers(config)
+		if err != nil {
+			return err
+		}
+		if err := g.fs().WriteFile(filepath.Join(devcontainerDir, LogParsersFile), parsers, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", LogParsersFile, err)
+		}
+	}
+
+	if config.EnableFileOutput {
+		script, err := g.renderRotateScript(config)
+		if err != nil {
+			return err
+		}
+		scriptsDir := filepath.Join(devcontainerDir, "scripts")
+		if err := g.fs().MkdirAll(scriptsDir, 0755); err != nil {
+			return fmt.Errorf("failed to create scripts directory: %w", err)
+		}
+		if err := g.fs().WriteFile(filepath.Join(devcontainerDir, LogRotateScript), script, 0755); err != nil {
+			return fmt.Errorf("failed to write %s: %w", LogRotateScript, err)
+		}
+	}
+
 	return nil
 }
 
+// GenerateRotateScriptContent returns the generated rotate-logs.sh content
+// without writing to disk, or nil when logs aren't written to files.
+func (g *LogSidecarGenerator) GenerateRotateScriptContent(detection *models.Detection, projectName string) ([]byte, error) {
+	config, err := g.buildConfig(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+	if !config.EnableFileOutput {
+		return nil, nil
+	}
+	return g.renderRotateScript(config)
+}
+
+// GenerateParsersContent returns the generated fluent-bit-parsers.conf content
+// without writing to disk, or nil when no text logs are parsed.
+func (g *LogSidecarGenerator) GenerateParsersContent(detection *models.Detection, projectName string) ([]byte, error) {
+	config, err := g.buildConfig(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+	if len(config.Parsers) == 0 {
+		return nil, nil
+	}
+	return g.renderParsers(config)
+}
+
 // GenerateContent returns the generated fluent-bit.conf content without writing to disk.
 // Useful for dry-run mode.
 func (g *LogSidecarGenerator) GenerateContent(detection *models.Detection, projectName string) ([]byte, error) {
-	config := g.buildConfig(detection, projectName)
+	config, err := g.buildConfig(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
 	return g.render(config)
 }
 
-// buildConfig creates a LogSidecarConfig from a Detection.
-func (g *LogSidecarGenerator) buildConfig(detection *models.Detection, projectName string) *LogSidecarConfig {
-	return &LogSidecarConfig{
+// buildConfig creates a LogSidecarConfig from a Detection. The services that
+// log through Fluent Bit are those of the generated docker-compose.yml.
+func (g *LogSidecarGenerator) buildConfig(detection *models.Detection, projectName string) (*LogSidecarConfig, error) {
+	compose, err := NewComposeGenerator().config(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &LogSidecarConfig{
 		Name:             projectName,
 		LogFormat:        detection.LogFormat,
-		EnableFileOutput: false, // Default to stdout only for dev
+		EnableFileOutput: detection.Logging.FileOutput, // Default to stdout only for dev
+		LogDir:           logDir,
+		MaxSize:          detection.GetLogMaxSize(),
+		MaxSizeBytes:     detection.GetLogMaxSizeBytes(),
+		MaxFiles:         detection.GetLogMaxFiles(),
 		LoggingLibraries: detection.LoggingLibraries,
+		Parsers:          textLogParsers(detection),
+		ParsersPath:      logParsersPath,
+		Services:         compose.LoggedServices(),
 	}
+
+	var appCode []string
+	for _, service := range config.Services {
+		if service.AppCode {
+			appCode = append(appCode, regexp.QuoteMeta(service.Tag))
+		}
+	}
+	config.AppCodeMatch = `^docker\.(` + strings.Join(appCode, "|") + `)$`
+	return config, nil
 }
 
 // render executes the template with the given config.
 func (g *LogSidecarGenerator) render(config *LogSidecarConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("fluent-bit.conf.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load template: %w", err)
-	}
+	return renderTemplate("fluent-bit.conf.tmpl", config)
+}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
-	}
+// renderParsers executes the parsers template with the given config.
+func (g *LogSidecarGenerator) renderParsers(config *LogSidecarConfig) ([]byte, error) {
+	return renderTemplate("fluent-bit-parsers.conf.tmpl", config)
+}
 
-	return buf.Bytes(), nil
+// renderRotateScript executes the rotation script template with the given config.
+func (g *LogSidecarGenerator) renderRotateScript(config *LogSidecarConfig) ([]byte, error) {
+	return renderTemplate("rotate-logs.sh.tmpl", config)
 }
 
 // GetComposeService returns the docker-compose service definition for Fluent Bit.
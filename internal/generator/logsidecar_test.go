@@ -3,6 +3,7 @@ package generator
 import (
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -20,18 +21,22 @@ func TestLogSidecarGenerator_ShouldGenerate(t *testing.T) {
 		{
 			name: "with logging libraries",
 			detection: &models.Detection{
-				Language:         "node",
-				LoggingLibraries: []string{"winston", "pino"},
-				LogFormat:        "json",
+				Language: "node",
+				Capabilities: models.Capabilities{
+					LoggingLibraries: []string{"winston", "pino"},
+				},
+				LogFormat: "json",
 			},
 			want: true,
 		},
 		{
 			name: "without logging libraries",
 			detection: &models.Detection{
-				Language:         "node",
-				LoggingLibraries: []string{},
-				LogFormat:        "unknown",
+				Language: "node",
+				Capabilities: models.Capabilities{
+					LoggingLibraries: []string{},
+				},
+				LogFormat: "unknown",
 			},
 			want: false,
 		},
@@ -59,9 +64,11 @@ func TestLogSidecarGenerator_GenerateContent_Basic(t *testing.T) {
 	g := NewLogSidecarGenerator()
 
 	detection := &models.Detection{
-		Language:         "node",
-		LoggingLibraries: []string{"pino"},
-		LogFormat:        "json",
+		Language: "node",
+		Capabilities: models.Capabilities{
+			LoggingLibraries: []string{"pino"},
+		},
+		LogFormat: "json",
 	}
 
 	content, err := g.GenerateContent(detection, "my-app")
@@ -100,9 +107,11 @@ func TestLogSidecarGenerator_GenerateContent_JSONFormat(t *testing.T) {
 	g := NewLogSidecarGenerator()
 
 	detection := &models.Detection{
-		Language:         "node",
-		LoggingLibraries: []string{"pino"},
-		LogFormat:        "json",
+		Language: "node",
+		Capabilities: models.Capabilities{
+			LoggingLibraries: []string{"pino"},
+		},
+		LogFormat: "json",
 	}
 
 	content, err := g.GenerateContent(detection, "test-app")
@@ -128,9 +137,11 @@ func TestLogSidecarGenerator_GenerateContent_TextFormat(t *testing.T) {
 	g := NewLogSidecarGenerator()
 
 	detection := &models.Detection{
-		Language:         "go",
-		LoggingLibraries: []string{"logrus"},
-		LogFormat:        "text",
+		Language: "go",
+		Capabilities: models.Capabilities{
+			LoggingLibraries: []string{"logrus"},
+		},
+		LogFormat: "text",
 	}
 
 	content, err := g.GenerateContent(detection, "test-app")
@@ -155,9 +166,11 @@ func TestLogSidecarGenerator_GenerateContent_MetadataFilter(t *testing.T) {
 	g := NewLogSidecarGenerator()
 
 	detection := &models.Detection{
-		Language:         "python",
-		LoggingLibraries: []string{"structlog"},
-		LogFormat:        "json",
+		Language: "python",
+		Capabilities: models.Capabilities{
+			LoggingLibraries: []string{"structlog"},
+		},
+		LogFormat: "json",
 	}
 
 	content, err := g.GenerateContent(detection, "my-python-app")
@@ -187,9 +200,11 @@ func TestLogSidecarGenerator_Generate_WritesFile(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	detection := &models.Detection{
-		Language:         "rust",
-		LoggingLibraries: []string{"tracing"},
-		LogFormat:        "json",
+		Language: "rust",
+		Capabilities: models.Capabilities{
+			LoggingLibraries: []string{"tracing"},
+		},
+		LogFormat: "json",
 	}
 
 	err = g.Generate(detection, tmpDir, "rust-app")
@@ -255,9 +270,11 @@ func TestLogSidecarGenerator_AllLanguages(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.language+"_"+tt.format, func(t *testing.T) {
 			detection := &models.Detection{
-				Language:         tt.language,
-				LoggingLibraries: tt.libs,
-				LogFormat:        tt.format,
+				Language: tt.language,
+				Capabilities: models.Capabilities{
+					LoggingLibraries: tt.libs,
+				},
+				LogFormat: tt.format,
 			}
 
 			content, err := g.GenerateContent(detection, tt.language+"-app")
@@ -283,3 +300,108 @@ func TestLogSidecarGenerator_AllLanguages(t *testing.T) {
 		})
 	}
 }
+
+func TestLogSidecarGenerator_TextParsers(t *testing.T) {
+	g := NewLogSidecarGenerator()
+
+	detection := &models.Detection{
+		Language: "go",
+		Capabilities: models.Capabilities{
+			LoggingLibraries: []string{"logrus", "apex-log"},
+		},
+		LogFormat: "text",
+		Services:  []string{"redis"},
+	}
+
+	content, err := g.GenerateContent(detection, "test-app")
+	if err != nil {
+		t.Fatalf("GenerateContent failed: %v", err)
+	}
+	contentStr := string(content)
+	if !strings.Contains(contentStr, "Parsers_File    /fluent-bit/etc/parsers-text.conf") {
+		t.Error("Expected the text parsers file to be loaded")
+	}
+	if !strings.Contains(contentStr, "Parser          logrus") {
+		t.Error("Expected a logrus parser filter")
+	}
+
+	parsers, err := g.GenerateParsersContent(detection, "test-app")
+	if err != nil {
+		t.Fatalf("GenerateParsersContent failed: %v", err)
+	}
+	parsersStr := string(parsers)
+	for _, want := range []string{"[PARSER]", "Name            logrus", "Format          regex", "Time_Key        time"} {
+		if !strings.Contains(parsersStr, want) {
+			t.Errorf("Expected %q in parsers file\n%s", want, parsersStr)
+		}
+	}
+	// apex-log has no parser and is shipped as raw lines
+	if strings.Count(parsersStr, "[PARSER]") != 1 {
+		t.Errorf("Expected one parser\n%s", parsersStr)
+	}
+
+	compose, err := NewComposeGenerator().GenerateContent(detection, "test-app")
+	if err != nil {
+		t.Fatalf("compose GenerateContent failed: %v", err)
+	}
+	if !strings.Contains(string(compose), "- ./fluent-bit-parsers.conf:/fluent-bit/etc/parsers-text.conf:ro") {
+		t.Error("Expected the parsers file to be mounted into fluent-bit")
+	}
+}
+
+func TestLogSidecarGenerator_NoTextParsersForJSON(t *testing.T) {
+	g := NewLogSidecarGenerator()
+
+	// winston is detected, but pino makes the logs JSON
+	detection := &models.Detection{
+		Language: "node",
+		Capabilities: models.Capabilities{
+			LoggingLibraries: []string{"pino", "winston"},
+		},
+		LogFormat: "json",
+	}
+
+	parsers, err := g.GenerateParsersContent(detection, "test-app")
+	if err != nil {
+		t.Fatalf("GenerateParsersContent failed: %v", err)
+	}
+	if parsers != nil {
+		t.Errorf("Expected no parsers file for JSON logs, got\n%s", parsers)
+	}
+
+	content, err := g.GenerateContent(detection, "test-app")
+	if err != nil {
+		t.Fatalf("GenerateContent failed: %v", err)
+	}
+	if strings.Contains(string(content), "parsers-text.conf") {
+		t.Error("JSON logs should not load the text parsers file")
+	}
+}
+
+// TestLogParsers_MatchExamples checks each parser's regex against its
+// library's example line. Go's regexp accepts the Onigmo named groups used here.
+func TestLogParsers_MatchExamples(t *testing.T) {
+	wantLevels := map[string]string{"logrus": "info", "winston": "info", "loguru": "INFO"}
+
+	for library, parser := range logParsers {
+		re, err := regexp.Compile(parser.Regex)
+		if err != nil {
+			t.Errorf("%s: invalid regex: %v", library, err)
+			continue
+		}
+		match := re.FindStringSubmatch(parser.Example)
+		if match == nil {
+			t.Errorf("%s: regex doesn't match its example %q", library, parser.Example)
+			continue
+		}
+		if got := match[re.SubexpIndex("level")]; got != wantLevels[library] {
+			t.Errorf("%s: level = %q, want %q", library, got, wantLevels[library])
+		}
+		if got := match[re.SubexpIndex("message")]; got != "Server started" {
+			t.Errorf("%s: message = %q, want %q", library, got, "Server started")
+		}
+		if parser.TimeKey != "" && match[re.SubexpIndex(parser.TimeKey)] == "" {
+			t.Errorf("%s: no %s captured", library, parser.TimeKey)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// mailpitImage is the Mailpit image the mail catcher runs.
+const mailpitImage = "axllent/mailpit:v1.21"
+
+// MailpitPort is the host port of the Mailpit web UI; SMTP listens on 1025.
+const MailpitPort = 8025
+
+// MailExampleDir holds the generated examples, relative to the project root.
+const MailExampleDir = ".devcontainer/examples"
+
+// mailExampleFiles are the example email jobs, by language.
+var mailExampleFiles = map[string]string{
+	"node":   "email_job.js",
+	"python": "email_job.py",
+	"go":     "email_job.go",
+	"rust":   "email_job.rs",
+}
+
+// mailExampleQueues are the queue libraries the example email job is written
+// for, by language. With another queue library, the example sends directly
+// and says where to call it from.
+var mailExampleQueues = map[string][]string{
+	"node":   {"bullmq", "bull"},
+	"python": {"celery", "rq", "dramatiq"},
+	"go":     {"asynq"},
+}
+
+// MailpitComposeConfig holds configuration for the Mailpit mail catcher.
+type MailpitComposeConfig struct {
+	// Enabled indicates whether to include the Mailpit sidecar
+	Enabled bool
+
+	// Image is the Mailpit image
+	Image string
+
+	// Port is the external port for the web UI
+	Port int
+
+	// Libraries are the detected mail libraries that send through Mailpit
+	Libraries []string
+
+	// Environment is the SMTP settings injected into the app, and into the
+	// worker when it sends mail too
+	Environment []string
+
+	// Worker indicates whether the worker gets the SMTP settings
+	Worker bool
+}
+
+// mailpitConfig returns the Mailpit sidecar settings, or a zero config unless
+// a mail library was detected.
+func mailpitConfig(detection *models.Detection) MailpitComposeConfig {
+	if !detection.NeedsMailpit() {
+		return MailpitComposeConfig{}
+	}
+	return MailpitComposeConfig{
+		Enabled:   true,
+		Image:     mailpitImage,
+		Port:      MailpitPort,
+		Libraries: detection.MailLibraries,
+		Environment: []string{
+			"SMTP_HOST=mailpit",
+			"SMTP_PORT=1025",
+			"SMTP_URL=smtp://mailpit:1025",
+			"SMTP_SECURE=false",
+			"MAIL_FROM=no-reply@localhost",
+		},
+		Worker: detection.NeedsMailExample(),
+	}
+}
+
+// MailExampleFile returns the example email job for the project's language,
+// relative to the project root, or "" when there is none.
+func MailExampleFile(detection *models.Detection) string {
+	file, ok := mailExampleFiles[detection.Language]
+	if !ok {
+		return ""
+	}
+	return MailExampleDir + "/" + file
+}
+
+// MailExampleConfig holds the configuration for generating the example email job.
+type MailExampleConfig struct {
+	// Name is the project name, used in the email subject
+	Name string
+
+	// Queue is the queue library the job is written for, or empty when the
+	// example sends directly
+	Queue string
+
+	// QueueLibraries are the detected queue libraries
+	QueueLibraries []string
+
+	// MailLibraries are the detected mail libraries
+	MailLibraries []string
+
+	// UIPort is the host port of the Mailpit web UI
+	UIPort int
+}
+
+// MailExampleGenerator generates .devcontainer/examples/email_job.*, a job the
+// app enqueues and the worker runs to send mail through Mailpit.
+type MailExampleGenerator struct {
+	output
+}
+
+// NewMailExampleGenerator creates a new example email job generator.
+func NewMailExampleGenerator() *MailExampleGenerator {
+	return &MailExampleGenerator{}
+}
+
+// ShouldGenerate returns true if both a queue and a mail library were detected.
+func (g *MailExampleGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.NeedsMailExample() && MailExampleFile(detection) != ""
+}
+
+// Generate creates the example email job for the project's language.
+func (g *MailExampleGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	content, err := g.GenerateContent(detection, projectName)
+	if err != nil {
+		return err
+	}
+
+	exampleDir := filepath.Join(projectPath, filepath.FromSlash(MailExampleDir))
+	if err := g.fs().MkdirAll(exampleDir, 0755); err != nil {
+		return fmt.Errorf("failed to create examples directory: %w", err)
+	}
+	file := MailExampleFile(detection)
+	if err := g.fs().WriteFile(filepath.Join(projectPath, filepath.FromSlash(file)), content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(file), err)
+	}
+	return nil
+}
+
+// GenerateContent returns the example email job without writing to disk.
+func (g *MailExampleGenerator) GenerateContent(detection *models.Detection, projectName string) ([]byte, error) {
+	file, ok := mailExampleFiles[detection.Language]
+	if !ok {
+		return nil, fmt.Errorf("no example email job for %s projects", detection.Language)
+	}
+
+	config := &MailExampleConfig{
+		Name:           projectName,
+		QueueLibraries: detection.QueueLibraries,
+		MailLibraries:  detection.MailLibraries,
+		UIPort:         MailpitPort,
+	}
+	for _, queue := range detection.QueueLibraries {
+		if slices.Contains(mailExampleQueues[detection.Language], queue) {
+			config.Queue = queue
+			break
+		}
+	}
+
+	return renderTemplate("examples/"+file+".tmpl", config)
+}
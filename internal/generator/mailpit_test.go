@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestComposeGenerator_Mailpit tests the Mailpit service and the SMTP settings
+// of the app, and of the worker when it sends mail too.
+func TestComposeGenerator_Mailpit(t *testing.T) {
+	tests := []struct {
+		name       string
+		queue      []string
+		wantWorker bool
+	}{
+		{"app only", nil, false},
+		{"app and worker", []string{"bullmq"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detection := &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Services: []string{"redis"},
+				Capabilities: models.Capabilities{
+					QueueLibraries: tt.queue,
+					MailLibraries:  []string{"nodemailer"},
+				},
+			}
+			content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+			compose := string(content)
+			for _, want := range []string{
+				"  mailpit:\n    image: axllent/mailpit:v1.21\n",
+				`- "8025:8025"  # Web UI`,
+				`test: ["CMD", "/mailpit", "readyz"]`,
+			} {
+				if !strings.Contains(compose, want) {
+					t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+				}
+			}
+
+			if env, _ := composeEnv(t, content, "app"); env["SMTP_HOST"] != "mailpit" || env["SMTP_PORT"] != "1025" {
+				t.Errorf("app SMTP_HOST:SMTP_PORT = %s:%s, want mailpit:1025", env["SMTP_HOST"], env["SMTP_PORT"])
+			}
+			if tt.wantWorker {
+				if env, _ := composeEnv(t, content, "worker"); env["SMTP_HOST"] != "mailpit" || env["MAIL_FROM"] == "" {
+					t.Errorf("worker environment = %v, want the SMTP settings", env)
+				}
+			}
+		})
+	}
+}
+
+// TestMailExampleGenerator tests the example email job, written for the
+// detected queue library where there is one.
+func TestMailExampleGenerator(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		queue    string
+		wantFile string
+		want     []string
+	}{
+		{
+			name:     "node bullmq",
+			language: "node",
+			queue:    "bullmq",
+			wantFile: ".devcontainer/examples/email_job.js",
+			want: []string{
+				"const { Queue, Worker } = require('bullmq');",
+				"new Worker('email', (job) => sendWelcomeEmail(job.data), { connection });",
+				"await queue.add('welcome-email', { to });",
+			},
+		},
+		{
+			name:     "python rq",
+			language: "python",
+			queue:    "rq",
+			wantFile: ".devcontainer/examples/email_job.py",
+			want: []string{
+				`cd .devcontainer/examples && rq worker email --url "$REDIS_URL"`,
+				`.enqueue("email_job.send_welcome_email", to)`,
+				`with smtplib.SMTP(os.environ["SMTP_HOST"], int(os.environ["SMTP_PORT"])) as smtp:`,
+			},
+		},
+		{
+			name:     "go asynq",
+			language: "go",
+			queue:    "asynq",
+			wantFile: ".devcontainer/examples/email_job.go",
+			want: []string{
+				"//go:build ignore\n",
+				`"github.com/hibiken/asynq"`,
+				`asynq.ParseRedisURI(os.Getenv("REDIS_URL"))`,
+			},
+		},
+		{
+			name:     "go queue without an example",
+			language: "go",
+			queue:    "machinery",
+			wantFile: ".devcontainer/examples/email_job.go",
+			want:     []string{"// Call sendWelcomeEmail from your machinery job handler; here it runs directly"},
+		},
+		{
+			name:     "rust",
+			language: "rust",
+			queue:    "apalis",
+			wantFile: ".devcontainer/examples/email_job.rs",
+			want:     []string{"SmtpTransport::builder_dangerous(host).port(port).build()", "call it from your apalis job\n//! handler"},
+		},
+	}
+	gen := NewMailExampleGenerator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detection := &models.Detection{
+				Language: tt.language,
+				Capabilities: models.Capabilities{
+					QueueLibraries: []string{tt.queue},
+					MailLibraries:  []string{"smtp"},
+				},
+			}
+			if !gen.ShouldGenerate(detection) {
+				t.Fatal("ShouldGenerate() = false, want true with a queue and a mail library")
+			}
+			if got := MailExampleFile(detection); got != tt.wantFile {
+				t.Errorf("MailExampleFile() = %q, want %q", got, tt.wantFile)
+			}
+			content, err := gen.GenerateContent(detection, "shop")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("example should contain %q, got:\n%s", want, content)
+				}
+			}
+		})
+	}
+
+	// A mail library alone has nothing for the worker to send
+	detection := &models.Detection{Language: "node", Capabilities: models.Capabilities{MailLibraries: []string{"nodemailer"}}}
+	if gen.ShouldGenerate(detection) {
+		t.Error("ShouldGenerate() = true, want false without a queue library")
+	}
+}
@@ -0,0 +1,184 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// managedFiles lists every file dockstart may generate, relative to .devcontainer/.
+// Keep in sync with the Generate methods when adding a generator.
+var managedFiles = []string{
+	"devcontainer.json",
+	"docker-compose.yml",
+	".env",
+	"README.devcontainer.md",
+	"dockstart-report.json",
+	".gitattributes",
+	"scripts/migrate.sh",
+	"scripts/dotfiles.sh",
+	"scripts/sync-workspace.sh",
+	"Dockerfile",
+	"fluent-bit.conf",
+	"fluent-bit-parsers.conf",
+	"scripts/rotate-logs.sh",
+	"Dockerfile.backup",
+	"crontab",
+	"entrypoint.sh",
+	"scripts/backup.sh",
+	"scripts/backup-postgres.sh",
+	"scripts/restore-postgres.sh",
+	"scripts/backup-mysql.sh",
+	"scripts/restore-mysql.sh",
+	"scripts/backup-redis.sh",
+	"scripts/restore-redis.sh",
+	"scripts/backup-sqlite.sh",
+	"scripts/restore-sqlite.sh",
+	"backups/.gitkeep",
+	"Dockerfile.processor",
+	"entrypoint.processor.sh",
+	"scripts/process-files.sh",
+	"scripts/notify.sh",
+	"scripts/process-image.sh",
+	"scripts/process-document.sh",
+	"scripts/process-video.sh",
+	"scripts/scan-file.sh",
+	"scripts/s3-bridge.sh",
+	"files/pending/.gitkeep",
+	"prometheus/prometheus.yml",
+	"grafana/provisioning/datasources/prometheus.yml",
+	"grafana/provisioning/dashboards/provider.yml",
+	"grafana/provisioning/dashboards/app-metrics.json",
+	"Dockerfile.scheduler",
+	"crontab.scheduler",
+	"keycloak/realm.json",
+	"localstack/init-aws.sh",
+	"rabbitmq/init-dlx.sh",
+	"postgres/init-test-db.sh",
+	"postgres/init-databases.sql",
+	"postgres/setup.sh",
+	"scripts/snapshot.sh",
+	"scripts/restore-snapshot.sh",
+	"scripts/import-prod.sh",
+	"clickhouse/users.xml",
+	"clickhouse/prometheus.xml",
+	"wiremock/mappings/example.json",
+	"wiremock/__files/.gitkeep",
+	"toxiproxy/toxiproxy.json",
+	"scripts/chaos.sh",
+	"gatus/config.yaml",
+	"examples/email_job.js",
+	"examples/email_job.py",
+	"examples/email_job.go",
+	"examples/email_job.rs",
+	"docker-bake.hcl",
+	"docker-compose.dev.yml",
+	"docker-compose.test.yml",
+	"docker-compose.staging.yml",
+}
+
+// reusableFiles lists managed files a project may write itself, which dockstart
+// then reuses. They are only managed when they carry generatedMarker.
+var reusableFiles = []string{
+	"Dockerfile",
+}
+
+// generatedMarker is the attribution in the header of generated files.
+const generatedMarker = "Generated by dockstart"
+
+// managedDirs lists directories dockstart creates, relative to .devcontainer/, deepest first.
+// They are only removed once empty, so backups and uploaded files are never deleted.
+var managedDirs = []string{
+	"files/pending",
+	"files/processing",
+	"files/processed",
+	"files/failed",
+	"files/quarantine",
+	"files",
+	"backups",
+	"scripts",
+	"prometheus",
+	"grafana/provisioning/datasources",
+	"grafana/provisioning/dashboards",
+	"grafana/provisioning",
+	"grafana",
+	"keycloak",
+	"localstack",
+	"rabbitmq",
+	"postgres",
+	"clickhouse",
+	"wiremock/mappings",
+	"wiremock/__files",
+	"wiremock",
+	"toxiproxy",
+	"gatus",
+	"",
+}
+
+// ManagedFiles returns the dockstart-generated files present in a project,
+// as paths relative to the project root.
+func ManagedFiles(projectPath string) []string {
+	var files []string
+	for _, name := range managedFiles {
+		rel := filepath.Join(".devcontainer", filepath.FromSlash(name))
+		info, err := os.Lstat(filepath.Join(projectPath, rel))
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if containsString(reusableFiles, name) {
+			data, err := os.ReadFile(filepath.Join(projectPath, rel))
+			if err != nil || !bytes.Contains(data, []byte(generatedMarker)) {
+				continue
+			}
+		}
+		files = append(files, rel)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// RemoveManagedFiles deletes dockstart-generated files from a project, then removes
+// the directories dockstart created once they are empty.
+// Returns the paths (relative to the project root) left behind in .devcontainer/.
+func RemoveManagedFiles(projectPath string) ([]string, error) {
+	for _, rel := range ManagedFiles(projectPath) {
+		if err := os.Remove(filepath.Join(projectPath, rel)); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", rel, err)
+		}
+	}
+
+	for _, dir := range managedDirs {
+		path := filepath.Join(projectPath, ".devcontainer", filepath.FromSlash(dir))
+		entries, err := os.ReadDir(path)
+		if err != nil || len(entries) > 0 {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove directory %s: %w", path, err)
+		}
+	}
+
+	var remaining []string
+	devcontainerDir := filepath.Join(projectPath, ".devcontainer")
+	err := filepath.WalkDir(devcontainerDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			rel, _ := filepath.Rel(projectPath, path)
+			remaining = append(remaining, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remaining files: %w", err)
+	}
+
+	return remaining, nil
+}
@@ -0,0 +1,148 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestManagedFiles tests that every generated file is tracked and cleaned up.
+func TestManagedFiles(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "dockstart-managed-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Services: []string{"postgres", "redis", "clickhouse"},
+		Capabilities: models.Capabilities{
+			MetricsLibraries:    []string{"prometheus-client"},
+			SchedulerLibraries:  []string{"apscheduler"},
+			AuthLibraries:       []string{"authlib"},
+			AWSServices:         []string{"sqs"},
+			FileUploadLibraries: []string{"pillow"},
+		},
+		FileProcessor:     models.FileProcessorOptions{Scan: true, Storage: "s3"},
+		MigrationTool:     "alembic",
+		MigrateCommand:    "alembic upgrade head",
+		Persistence:       models.PersistenceOptions{Dotfiles: "https://github.com/octocat/dotfiles.git"},
+		Testing:           models.TestingOptions{Isolation: models.TestIsolationDatabase},
+		PostgresDatabases: []string{"analytics"},
+		ProdImport:        &models.ProdImportOptions{SourceEnv: "PROD_DATABASE_URL"},
+		MockAPIs:          true,
+		ChaosProxy:        true,
+		StatusPage:        true,
+	}
+
+	generators := []func() error{
+		func() error { return NewDevcontainerGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewComposeGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewCredentialsGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewReadmeGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewLineEndingsGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewMigrateGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewDotfilesGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewTestDatabaseGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewPostgresDatabasesGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewSnapshotGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewProdImportGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewClickHouseGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewWireMockGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewToxiproxyGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewGatusGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewDockerfileGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewLogSidecarGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewBackupSidecarGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewProcessorSidecarGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewMetricsSidecarGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewSchedulerSidecarGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewKeycloakSidecarGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewLocalStackSidecarGenerator().Generate(detection, tmpDir, "app") },
+		func() error { return NewDeadLetterSidecarGenerator().Generate(detection, tmpDir, "app") },
+		func() error {
+			report, err := NewGenerationReport(OS, tmpDir, "dev", BuildInfo{Version: "dev"}, ReportOptions{}, detection, nil)
+			if err != nil {
+				return err
+			}
+			return report.Write(OS, tmpDir)
+		},
+	}
+	for _, generate := range generators {
+		if err := generate(); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	}
+
+	// A user file and a backup must survive cleanup
+	userFile := filepath.Join(tmpDir, ".devcontainer", "notes.md")
+	backup := filepath.Join(tmpDir, ".devcontainer", "backups", "postgres-2024.sql.gz")
+	for _, path := range []string{userFile, backup} {
+		if err := os.WriteFile(path, []byte("keep"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	if files := ManagedFiles(tmpDir); len(files) == 0 {
+		t.Fatal("ManagedFiles() returned no files")
+	}
+
+	remaining, err := RemoveManagedFiles(tmpDir)
+	if err != nil {
+		t.Fatalf("RemoveManagedFiles() error = %v", err)
+	}
+
+	want := map[string]bool{
+		filepath.Join(".devcontainer", "backups", "postgres-2024.sql.gz"): true,
+		filepath.Join(".devcontainer", "notes.md"):                        true,
+	}
+	if len(remaining) != len(want) {
+		t.Fatalf("RemoveManagedFiles() left %v, want only the user files (every generated file should be in managedFiles)", remaining)
+	}
+	for _, path := range remaining {
+		if !want[path] {
+			t.Errorf("RemoveManagedFiles() left unexpected file %s", path)
+		}
+	}
+
+	// Once the user files are gone, cleanup removes .devcontainer entirely
+	os.Remove(userFile)
+	os.Remove(backup)
+	if _, err := RemoveManagedFiles(tmpDir); err != nil {
+		t.Fatalf("RemoveManagedFiles() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".devcontainer")); !os.IsNotExist(err) {
+		t.Error(".devcontainer should be removed once empty")
+	}
+}
+
+// TestManagedFiles_HandWrittenDockerfile tests that a Dockerfile dockstart didn't generate is kept.
+func TestManagedFiles_HandWrittenDockerfile(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "dockstart-managed-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dockerfile := filepath.Join(tmpDir, ".devcontainer", "Dockerfile")
+	if err := os.MkdirAll(filepath.Dir(dockerfile), 0755); err != nil {
+		t.Fatalf("Failed to create .devcontainer: %v", err)
+	}
+	if err := os.WriteFile(dockerfile, []byte("FROM mcr.microsoft.com/devcontainers/go:1.22\n"), 0644); err != nil {
+		t.Fatalf("Failed to write Dockerfile: %v", err)
+	}
+
+	if files := ManagedFiles(tmpDir); len(files) != 0 {
+		t.Errorf("ManagedFiles() = %v, want none for a hand-written Dockerfile", files)
+	}
+	if _, err := RemoveManagedFiles(tmpDir); err != nil {
+		t.Fatalf("RemoveManagedFiles() error = %v", err)
+	}
+	if _, err := os.Stat(dockerfile); err != nil {
+		t.Errorf("hand-written Dockerfile should be kept: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// MemcachedComposeConfig holds configuration for the Memcached service.
+type MemcachedComposeConfig struct {
+	// CacheMB is the memory Memcached uses for items, in megabytes (-m)
+	CacheMB int
+
+	// MaxItemSize is the largest item Memcached stores (-I)
+	MaxItemSize string
+
+	// MaxConnections is the simultaneous connection limit (-c)
+	MaxConnections int
+
+	// MemoryLimit caps the container, leaving room for connection buffers above the cache
+	MemoryLimit string
+}
+
+// memcachedConfig returns the Memcached settings, sized for a development
+// machine rather than the image's production-style defaults.
+func memcachedConfig(detection *models.Detection) MemcachedComposeConfig {
+	if !detection.HasService("memcached") || detection.ExistingServiceFor("memcached") != "" {
+		return MemcachedComposeConfig{}
+	}
+	return MemcachedComposeConfig{
+		CacheMB:        64,
+		MaxItemSize:    "1m",
+		MaxConnections: 1024,
+		MemoryLimit:    "128m",
+	}
+}
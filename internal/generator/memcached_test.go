@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestComposeGenerator_Memcached tests the Memcached service in docker-compose.yml.
+func TestComposeGenerator_Memcached(t *testing.T) {
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Services: []string{"memcached"},
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+
+	var parsed map[string]interface{}
+	if err := yaml.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, compose)
+	}
+
+	for _, want := range []string{
+		"  memcached:\n    image: memcached:1.6-alpine\n",
+		`command: ["memcached", "-m", "64", "-I", "1m", "-c", "1024"]`,
+		"          memory: 128m\n",
+		`"11211:11211"`,
+		"      - MEMCACHED_HOST=memcached\n",
+		"      - MEMCACHED_PORT=11211\n",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+		}
+	}
+	if strings.Contains(compose, "memcached-data") {
+		t.Errorf("Memcached is a cache and should not get a volume, got:\n%s", compose)
+	}
+}
+
+// TestComposeGenerator_ImportedMemcached tests pointing the app at the project's own Memcached.
+func TestComposeGenerator_ImportedMemcached(t *testing.T) {
+	detection := &models.Detection{
+		Language: "go",
+		Version:  "1.23",
+		Services: []string{"memcached"},
+		ExistingCompose: &models.ExistingCompose{
+			File: "docker-compose.yml",
+			Services: []models.ExistingService{{
+				Name:       "cache",
+				Role:       "memcached",
+				Definition: map[string]interface{}{"image": "memcached:1.6"},
+			}},
+		},
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+	if !strings.Contains(compose, "      - MEMCACHED_HOST=cache\n") {
+		t.Errorf("app should connect to the imported service, got:\n%s", compose)
+	}
+	if strings.Contains(compose, "image: memcached:1.6-alpine") {
+		t.Errorf("docker-compose.yml should not add a second Memcached, got:\n%s", compose)
+	}
+}
@@ -2,9 +2,7 @@
 package generator
 
 import (
-	"bytes"
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"github.com/jpequegn/dockstart/internal/models"
@@ -42,6 +40,9 @@ type MetricsSidecarConfig struct {
 	// HasRedis indicates if Redis is detected
 	HasRedis bool
 
+	// HasClickHouse indicates if a generated ClickHouse service exposes its Prometheus endpoint
+	HasClickHouse bool
+
 	// GrafanaPort is the port to expose Grafana on (default: 3001)
 	GrafanaPort int
 
@@ -66,7 +67,9 @@ func DefaultMetricsConfig() *MetricsSidecarConfig {
 }
 
 // MetricsSidecarGenerator generates Prometheus + Grafana configuration files.
-type MetricsSidecarGenerator struct{}
+type MetricsSidecarGenerator struct {
+	output
+}
 
 // NewMetricsSidecarGenerator creates a new metrics sidecar generator.
 func NewMetricsSidecarGenerator() *MetricsSidecarGenerator {
@@ -75,62 +78,22 @@ func NewMetricsSidecarGenerator() *MetricsSidecarGenerator {
 
 // GeneratePrometheusConfig generates the prometheus.yml content.
 func (g *MetricsSidecarGenerator) GeneratePrometheusConfig(config *MetricsSidecarConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("prometheus.yml.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load prometheus template: %w", err)
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute prometheus template: %w", err)
-	}
-
-	return buf.Bytes(), nil
+	return renderTemplate("prometheus.yml.tmpl", config)
 }
 
 // GenerateGrafanaDatasource generates the Grafana datasource provisioning file.
 func (g *MetricsSidecarGenerator) GenerateGrafanaDatasource(config *MetricsSidecarConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("grafana/datasources/prometheus.yml.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load datasource template: %w", err)
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute datasource template: %w", err)
-	}
-
-	return buf.Bytes(), nil
+	return renderTemplate("grafana/datasources/prometheus.yml.tmpl", config)
 }
 
 // GenerateGrafanaDashboardProvider generates the Grafana dashboard provider file.
 func (g *MetricsSidecarGenerator) GenerateGrafanaDashboardProvider(config *MetricsSidecarConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("grafana/dashboards/provider.yml.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load dashboard provider template: %w", err)
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute dashboard provider template: %w", err)
-	}
-
-	return buf.Bytes(), nil
+	return renderTemplate("grafana/dashboards/provider.yml.tmpl", config)
 }
 
 // GenerateAppDashboard generates the application metrics dashboard JSON.
 func (g *MetricsSidecarGenerator) GenerateAppDashboard(config *MetricsSidecarConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("grafana/dashboards/app-metrics.json.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load app dashboard template: %w", err)
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute app dashboard template: %w", err)
-	}
-
-	return buf.Bytes(), nil
+	return renderTemplate("grafana/dashboards/app-metrics.json.tmpl", config)
 }
 
 // Generate creates all Prometheus and Grafana configuration files.
@@ -141,17 +104,17 @@ func (g *MetricsSidecarGenerator) Generate(detection *models.Detection, outputPa
 
 	// Create prometheus directory
 	prometheusDir := filepath.Join(devcontainerDir, "prometheus")
-	if err := os.MkdirAll(prometheusDir, 0755); err != nil {
+	if err := g.fs().MkdirAll(prometheusDir, 0755); err != nil {
 		return fmt.Errorf("failed to create prometheus directory: %w", err)
 	}
 
 	// Create grafana provisioning directories
 	grafanaDatasourcesDir := filepath.Join(devcontainerDir, "grafana", "provisioning", "datasources")
 	grafanaDashboardsDir := filepath.Join(devcontainerDir, "grafana", "provisioning", "dashboards")
-	if err := os.MkdirAll(grafanaDatasourcesDir, 0755); err != nil {
+	if err := g.fs().MkdirAll(grafanaDatasourcesDir, 0755); err != nil {
 		return fmt.Errorf("failed to create grafana datasources directory: %w", err)
 	}
-	if err := os.MkdirAll(grafanaDashboardsDir, 0755); err != nil {
+	if err := g.fs().MkdirAll(grafanaDashboardsDir, 0755); err != nil {
 		return fmt.Errorf("failed to create grafana dashboards directory: %w", err)
 	}
 
@@ -160,7 +123,7 @@ func (g *MetricsSidecarGenerator) Generate(detection *models.Detection, outputPa
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(prometheusDir, "prometheus.yml"), prometheusConfig, 0644); err != nil {
+	if err := g.fs().WriteFile(filepath.Join(prometheusDir, "prometheus.yml"), prometheusConfig, 0644); err != nil {
 		return fmt.Errorf("failed to write prometheus.yml: %w", err)
 	}
 
@@ -169,7 +132,7 @@ func (g *MetricsSidecarGenerator) Generate(detection *models.Detection, outputPa
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(grafanaDatasourcesDir, "prometheus.yml"), datasource, 0644); err != nil {
+	if err := g.fs().WriteFile(filepath.Join(grafanaDatasourcesDir, "prometheus.yml"), datasource, 0644); err != nil {
 		return fmt.Errorf("failed to write grafana datasource: %w", err)
 	}
 
@@ -178,7 +141,7 @@ func (g *MetricsSidecarGenerator) Generate(detection *models.Detection, outputPa
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(grafanaDashboardsDir, "provider.yml"), provider, 0644); err != nil {
+	if err := g.fs().WriteFile(filepath.Join(grafanaDashboardsDir, "provider.yml"), provider, 0644); err != nil {
 		return fmt.Errorf("failed to write grafana dashboard provider: %w", err)
 	}
 
@@ -187,7 +150,7 @@ func (g *MetricsSidecarGenerator) Generate(detection *models.Detection, outputPa
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(grafanaDashboardsDir, "app-metrics.json"), dashboard, 0644); err != nil {
+	if err := g.fs().WriteFile(filepath.Join(grafanaDashboardsDir, "app-metrics.json"), dashboard, 0644); err != nil {
 		return fmt.Errorf("failed to write app-metrics dashboard: %w", err)
 	}
 
@@ -222,6 +185,7 @@ func (g *MetricsSidecarGenerator) buildConfig(detection *models.Detection, proje
 	// Check for services
 	config.HasPostgres = detection.HasService("postgres")
 	config.HasRedis = detection.HasService("redis")
+	config.HasClickHouse = NewClickHouseGenerator().ShouldGenerate(detection)
 
 	return config
 }
@@ -262,12 +262,14 @@ func TestMetricsSidecarGenerator_Generate(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	detection := &models.Detection{
-		Language:         "nodejs",
-		MetricsPort:      3000,
-		MetricsPath:      "/metrics",
-		MetricsLibraries: []string{"prom-client"},
-		Services:         []string{"postgres", "redis"},
-		QueueLibraries:   []string{"bull"}, // This makes NeedsWorker() return true
+		Language:    "nodejs",
+		MetricsPort: 3000,
+		MetricsPath: "/metrics",
+		Capabilities: models.Capabilities{
+			MetricsLibraries: []string{"prom-client"},
+			QueueLibraries:   []string{"bull"}, // This makes NeedsWorker() return true
+		},
+		Services: []string{"postgres", "redis"},
 	}
 
 	err = gen.Generate(detection, tmpDir, "testproject")
@@ -331,32 +333,40 @@ func TestMetricsSidecarGenerator_ShouldGenerate(t *testing.T) {
 		{
 			name: "with metrics library",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: []string{"prom-client"},
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+				},
 			},
 			expected: true,
 		},
 		{
 			name: "without metrics library",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: nil,
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: nil,
+				},
 			},
 			expected: false,
 		},
 		{
 			name: "empty metrics library slice",
 			detection: &models.Detection{
-				Language:         "go",
-				MetricsLibraries: []string{},
+				Language: "go",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{},
+				},
 			},
 			expected: false,
 		},
 		{
 			name: "multiple metrics libraries",
 			detection: &models.Detection{
-				Language:         "go",
-				MetricsLibraries: []string{"prometheus/client_golang", "prometheus/promhttp"},
+				Language: "go",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prometheus/client_golang", "prometheus/promhttp"},
+				},
 			},
 			expected: true,
 		},
@@ -407,10 +417,12 @@ func TestMetricsSidecarGenerator_buildConfig(t *testing.T) {
 		{
 			name: "uses defaults when detection values are zero",
 			detection: &models.Detection{
-				Language:         "go",
-				MetricsPort:      0,
-				MetricsPath:      "",
-				MetricsLibraries: []string{"prometheus/client_golang"},
+				Language:    "go",
+				MetricsPort: 0,
+				MetricsPath: "",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prometheus/client_golang"},
+				},
 			},
 			projectName: "goapp",
 			checkConfig: func(t *testing.T, config *MetricsSidecarConfig) {
@@ -442,8 +454,10 @@ func TestMetricsSidecarGenerator_buildConfig(t *testing.T) {
 		{
 			name: "detects worker",
 			detection: &models.Detection{
-				Language:       "nodejs",
-				QueueLibraries: []string{"bull"}, // NeedsWorker() returns true when queue libs exist
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					QueueLibraries: []string{"bull"}, // NeedsWorker() returns true when queue libs exist
+				},
 			},
 			projectName: "workerapp",
 			checkConfig: func(t *testing.T, config *MetricsSidecarConfig) {
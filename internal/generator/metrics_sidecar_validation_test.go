@@ -392,8 +392,10 @@ func TestGenerateErrorHandling(t *testing.T) {
 
 	t.Run("non-existent output path", func(t *testing.T) {
 		detection := &models.Detection{
-			Language:         "nodejs",
-			MetricsLibraries: []string{"prom-client"},
+			Language: "nodejs",
+			Capabilities: models.Capabilities{
+				MetricsLibraries: []string{"prom-client"},
+			},
 		}
 
 		// Try to generate in a non-existent nested path without write permissions
@@ -415,37 +417,45 @@ func TestMetricsSidecarAllLanguages(t *testing.T) {
 		{
 			name: "nodejs",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: []string{"prom-client"},
-				MetricsPort:      3000,
-				MetricsPath:      "/metrics",
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+				},
+				MetricsPort: 3000,
+				MetricsPath: "/metrics",
 			},
 		},
 		{
 			name: "go",
 			detection: &models.Detection{
-				Language:         "go",
-				MetricsLibraries: []string{"prometheus/client_golang"},
-				MetricsPort:      8080,
-				MetricsPath:      "/metrics",
+				Language: "go",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prometheus/client_golang"},
+				},
+				MetricsPort: 8080,
+				MetricsPath: "/metrics",
 			},
 		},
 		{
 			name: "python",
 			detection: &models.Detection{
-				Language:         "python",
-				MetricsLibraries: []string{"prometheus-client"},
-				MetricsPort:      8000,
-				MetricsPath:      "/metrics",
+				Language: "python",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prometheus-client"},
+				},
+				MetricsPort: 8000,
+				MetricsPath: "/metrics",
 			},
 		},
 		{
 			name: "rust",
 			detection: &models.Detection{
-				Language:         "rust",
-				MetricsLibraries: []string{"prometheus"},
-				MetricsPort:      8080,
-				MetricsPath:      "/metrics",
+				Language: "rust",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prometheus"},
+				},
+				MetricsPort: 8080,
+				MetricsPath: "/metrics",
 			},
 		},
 	}
@@ -522,9 +532,11 @@ func TestMetricsSidecarWithAllServices(t *testing.T) {
 			name:        "postgres only",
 			projectName: "pgapp",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: []string{"prom-client"},
-				Services:         []string{"postgres"},
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+				},
+				Services: []string{"postgres"},
 			},
 			expectedJobNames: []string{"pgapp", "postgres"},
 		},
@@ -532,9 +544,11 @@ func TestMetricsSidecarWithAllServices(t *testing.T) {
 			name:        "redis only",
 			projectName: "redisapp",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: []string{"prom-client"},
-				Services:         []string{"redis"},
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+				},
+				Services: []string{"redis"},
 			},
 			expectedJobNames: []string{"redisapp", "redis"},
 		},
@@ -542,9 +556,11 @@ func TestMetricsSidecarWithAllServices(t *testing.T) {
 			name:        "both postgres and redis",
 			projectName: "bothapp",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: []string{"prom-client"},
-				Services:         []string{"postgres", "redis"},
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+				},
+				Services: []string{"postgres", "redis"},
 			},
 			expectedJobNames: []string{"bothapp", "postgres", "redis"},
 		},
@@ -552,9 +568,11 @@ func TestMetricsSidecarWithAllServices(t *testing.T) {
 			name:        "with worker",
 			projectName: "workerapp",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: []string{"prom-client"},
-				QueueLibraries:   []string{"bull"},
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+					QueueLibraries:   []string{"bull"},
+				},
 			},
 			expectedJobNames: []string{"workerapp", "workerapp-worker"},
 		},
@@ -562,10 +580,12 @@ func TestMetricsSidecarWithAllServices(t *testing.T) {
 			name:        "full stack",
 			projectName: "fullapp",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				MetricsLibraries: []string{"prom-client"},
-				Services:         []string{"postgres", "redis"},
-				QueueLibraries:   []string{"bull"},
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+					QueueLibraries:   []string{"bull"},
+				},
+				Services: []string{"postgres", "redis"},
 			},
 			expectedJobNames: []string{"fullapp", "fullapp-worker", "postgres", "redis"},
 		},
@@ -0,0 +1,34 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// natsBoxImage is the NATS CLI toolbox image.
+const natsBoxImage = "natsio/nats-box:0.14.5"
+
+// NATSComposeConfig holds configuration for the NATS server.
+type NATSComposeConfig struct {
+	// Enabled indicates whether a NATS server is generated
+	Enabled bool
+
+	// MonitorPort is the HTTP monitoring port (/varz, /jsz, /healthz)
+	MonitorPort int
+
+	// BoxImage is the nats-box image started on demand in the tools profile
+	BoxImage string
+}
+
+// natsConfig returns the NATS server settings, with JetStream storing streams
+// in the nats-data volume.
+func natsConfig(detection *models.Detection) NATSComposeConfig {
+	if !detection.HasService("nats") || detection.ExistingServiceFor("nats") != "" {
+		return NATSComposeConfig{}
+	}
+	return NATSComposeConfig{
+		Enabled:     true,
+		MonitorPort: 8222,
+		BoxImage:    natsBoxImage,
+	}
+}
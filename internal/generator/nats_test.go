@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestComposeGenerator_NATS tests the NATS server and nats-box in docker-compose.yml.
+func TestComposeGenerator_NATS(t *testing.T) {
+	detection := &models.Detection{
+		Language: "go",
+		Version:  "1.23",
+		Services: []string{"nats"},
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "orders")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+
+	var parsed struct {
+		Services map[string]struct {
+			Profiles []string `yaml:"profiles"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, compose)
+	}
+	if _, ok := parsed.Services["nats"]; !ok {
+		t.Fatalf("docker-compose.yml should have a nats service, got:\n%s", compose)
+	}
+	if box, ok := parsed.Services["nats-box"]; !ok || len(box.Profiles) != 1 || box.Profiles[0] != "tools" {
+		t.Errorf("nats-box should be in the tools profile, got:\n%s", compose)
+	}
+
+	for _, want := range []string{
+		"  nats:\n    image: nats:2.10-alpine\n",
+		`- "--jetstream"`,
+		`- "--store_dir=/data"`,
+		"      - nats-data:/data\n",
+		`"4222:4222"`,
+		`"8222:8222"`,
+		"/healthz?js-enabled-only=true",
+		"      - NATS_URL=nats://nats:4222\n",
+		"    image: natsio/nats-box:",
+		"\n  nats-data:\n",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+		}
+	}
+}
+
+// TestComposeGenerator_ImportedNATS tests pointing the app at the project's own NATS server.
+func TestComposeGenerator_ImportedNATS(t *testing.T) {
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Services: []string{"nats"},
+		ExistingCompose: &models.ExistingCompose{
+			File: "docker-compose.yml",
+			Services: []models.ExistingService{{
+				Name:       "bus",
+				Role:       "nats",
+				Definition: map[string]interface{}{"image": "nats:2.10"},
+			}},
+		},
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "orders")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+	if !strings.Contains(compose, "      - NATS_URL=nats://bus:4222\n") {
+		t.Errorf("app should connect to the imported service, got:\n%s", compose)
+	}
+	for _, dontWant := range []string{"nats-box:", "nats-data:"} {
+		if strings.Contains(compose, dontWant) {
+			t.Errorf("docker-compose.yml should not contain %q, got:\n%s", dontWant, compose)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// DotfilesScript is the dotfiles clone script, relative to the project root.
+// postCreateCommand runs it from the workspace folder.
+const DotfilesScript = ".devcontainer/scripts/dotfiles.sh"
+
+// PersistentVolume is a named volume mounted into the dev container so its
+// content survives rebuilds.
+type PersistentVolume struct {
+	// Name is the volume name (prefixed with the workspace name without Compose)
+	Name string
+
+	// Target is the mount path in the container
+	Target string
+}
+
+// historyVolume keeps the shell history; HISTFILE points into it.
+var historyVolume = PersistentVolume{Name: "shell-history", Target: "/commandhistory"}
+
+// cacheVolumes keep each language's package and build caches. The cache
+// environment variables point into them, except for Cargo, whose registry
+// cache is mounted where the dev container images keep CARGO_HOME.
+var cacheVolumes = map[string]PersistentVolume{
+	"node":   {Name: "tool-cache", Target: "/cache"},
+	"python": {Name: "tool-cache", Target: "/cache"},
+	"go":     {Name: "tool-cache", Target: "/cache"},
+	"rust":   {Name: "cargo-registry", Target: "/usr/local/cargo/registry"},
+}
+
+// cacheEnv moves each language's caches into its cache volume.
+var cacheEnv = map[string][]EnvVar{
+	"node": {
+		{Name: "npm_config_cache", Value: "/cache/npm"},
+		{Name: "npm_config_store_dir", Value: "/cache/pnpm"},
+		{Name: "YARN_CACHE_FOLDER", Value: "/cache/yarn"},
+	},
+	"python": {
+		{Name: "PIP_CACHE_DIR", Value: "/cache/pip"},
+		{Name: "POETRY_CACHE_DIR", Value: "/cache/poetry"},
+		{Name: "UV_CACHE_DIR", Value: "/cache/uv"},
+	},
+	"go": {
+		{Name: "GOMODCACHE", Value: "/cache/go/mod"},
+		{Name: "GOCACHE", Value: "/cache/go/build"},
+	},
+}
+
+// persistentVolumes returns the named volumes that keep shell history and tool caches.
+func persistentVolumes(detection *models.Detection) []PersistentVolume {
+	var volumes []PersistentVolume
+	if detection.Persistence.History {
+		volumes = append(volumes, historyVolume)
+	}
+	if volume, ok := cacheVolumes[detection.Language]; ok && detection.Persistence.Caches {
+		volumes = append(volumes, volume)
+	}
+	return volumes
+}
+
+// persistentEnv returns the environment variables that point the shell
+// history and tool caches into their volumes.
+func persistentEnv(detection *models.Detection) []EnvVar {
+	var env []EnvVar
+	if detection.Persistence.History {
+		env = append(env,
+			EnvVar{Name: "HISTFILE", Value: historyVolume.Target + "/.bash_history"},
+			// Write each command as it runs, so closing a terminal loses nothing
+			EnvVar{Name: "PROMPT_COMMAND", Value: "history -a"},
+		)
+	}
+	if detection.Persistence.Caches {
+		env = append(env, cacheEnv[detection.Language]...)
+	}
+	return env
+}
+
+// DotfilesConfig holds the configuration for generating dotfiles.sh.
+type DotfilesConfig struct {
+	// Repository is the git URL of the dotfiles repository
+	Repository string
+
+	// Install runs in the clone instead of its install script, or empty to detect one
+	Install string
+}
+
+// DotfilesGenerator generates .devcontainer/scripts/dotfiles.sh, which clones
+// a dotfiles repository and runs its install script on container creation.
+type DotfilesGenerator struct {
+	output
+}
+
+// NewDotfilesGenerator creates a new dotfiles script generator.
+func NewDotfilesGenerator() *DotfilesGenerator {
+	return &DotfilesGenerator{}
+}
+
+// Generate creates .devcontainer/scripts/dotfiles.sh.
+func (g *DotfilesGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	content, err := g.GenerateContent(detection)
+	if err != nil {
+		return err
+	}
+
+	scriptsDir := filepath.Join(projectPath, ".devcontainer", "scripts")
+	if err := g.fs().MkdirAll(scriptsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create scripts directory: %w", err)
+	}
+	if err := g.fs().WriteFile(filepath.Join(scriptsDir, "dotfiles.sh"), content, 0755); err != nil {
+		return fmt.Errorf("failed to write dotfiles.sh: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateContent returns the dotfiles.sh content without writing to disk.
+func (g *DotfilesGenerator) GenerateContent(detection *models.Detection) ([]byte, error) {
+
+	config := &DotfilesConfig{
+		Repository: detection.Persistence.Dotfiles,
+		Install:    detection.Persistence.DotfilesInstall,
+	}
+	return renderTemplate("dotfiles.sh.tmpl", config)
+}
+
+// ShouldGenerate returns true if a dotfiles repository is configured.
+func (g *DotfilesGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.Persistence.Dotfiles != ""
+}
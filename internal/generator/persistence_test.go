@@ -0,0 +1,140 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestDevcontainerGenerator_Persistence tests persisted history and caches without Compose.
+func TestDevcontainerGenerator_Persistence(t *testing.T) {
+	detection := &models.Detection{
+		Language:       "python",
+		Version:        "3.12",
+		InstallCommand: "uv sync",
+		Persistence: models.PersistenceOptions{
+			History:  true,
+			Caches:   true,
+			Dotfiles: "https://github.com/octocat/dotfiles.git",
+		},
+	}
+
+	content, err := NewDevcontainerGenerator().GenerateContent(detection, "api")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	var result struct {
+		Mounts            []string          `json:"mounts"`
+		RemoteEnv         map[string]string `json:"remoteEnv"`
+		PostCreateCommand string            `json:"postCreateCommand"`
+	}
+	if err := json.Unmarshal(content, &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	wantMounts := []string{
+		"source=${localWorkspaceFolderBasename}-shell-history,target=/commandhistory,type=volume",
+		"source=${localWorkspaceFolderBasename}-tool-cache,target=/cache,type=volume",
+	}
+	if strings.Join(result.Mounts, "\n") != strings.Join(wantMounts, "\n") {
+		t.Errorf("mounts = %v, want %v", result.Mounts, wantMounts)
+	}
+
+	for name, want := range map[string]string{
+		"HISTFILE":       "/commandhistory/.bash_history",
+		"PROMPT_COMMAND": "history -a",
+		"PIP_CACHE_DIR":  "/cache/pip",
+		"UV_CACHE_DIR":   "/cache/uv",
+	} {
+		if result.RemoteEnv[name] != want {
+			t.Errorf("remoteEnv[%s] = %q, want %q", name, result.RemoteEnv[name], want)
+		}
+	}
+
+	want := "git config --global --add safe.directory /workspace && sudo chown vscode /commandhistory /cache && uv sync && bash .devcontainer/scripts/dotfiles.sh"
+	if result.PostCreateCommand != want {
+		t.Errorf("postCreateCommand = %q, want %q", result.PostCreateCommand, want)
+	}
+}
+
+// TestComposeGenerator_Persistence tests persisted history and caches with Compose.
+func TestComposeGenerator_Persistence(t *testing.T) {
+	detection := &models.Detection{
+		Language:    "rust",
+		Version:     "1.80",
+		Services:    []string{"postgres"},
+		NonRoot:     true,
+		Persistence: models.PersistenceOptions{History: true, Caches: true},
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "cli")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+	for _, want := range []string{
+		"      - shell-history:/commandhistory\n",
+		"      - cargo-registry:/usr/local/cargo/registry\n",
+		"\n  shell-history:\n",
+		"\n  cargo-registry:\n",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+		}
+	}
+
+	// The devcontainer.json doesn't mount them again
+	devcontainer, err := NewDevcontainerGenerator().GenerateContent(detection, "cli")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if strings.Contains(string(devcontainer), `"mounts"`) {
+		t.Errorf("devcontainer.json should not mount volumes with Compose, got:\n%s", devcontainer)
+	}
+
+	// The non-root user owns the volumes
+	dockerfile, err := NewDockerfileGenerator().GenerateContent(detection, "cli")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if !strings.Contains(string(dockerfile), "mkdir -p /commandhistory /usr/local/cargo/registry") {
+		t.Errorf("Dockerfile should create the volume directories, got:\n%s", dockerfile)
+	}
+}
+
+// TestDotfilesGenerator tests the dotfiles clone script.
+func TestDotfilesGenerator(t *testing.T) {
+	gen := NewDotfilesGenerator()
+	if gen.ShouldGenerate(&models.Detection{Language: "go"}) {
+		t.Error("ShouldGenerate() = true without dotfiles, want false")
+	}
+
+	detection := &models.Detection{
+		Language:    "go",
+		Persistence: models.PersistenceOptions{Dotfiles: "git@github.com:octocat/dotfiles.git"},
+	}
+	content, err := gen.GenerateContent(detection)
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	for _, want := range []string{
+		`DOTFILES_REPO="${DOTFILES_REPO:-git@github.com:octocat/dotfiles.git}"`,
+		"for script in install.sh",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("dotfiles.sh should contain %q, got:\n%s", want, content)
+		}
+	}
+
+	detection.Persistence.DotfilesInstall = "make install"
+	content, err = gen.GenerateContent(detection)
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if !strings.Contains(string(content), "\nmake install ||") || strings.Contains(string(content), "for script in") {
+		t.Errorf("dotfiles.sh should run the configured install command, got:\n%s", content)
+	}
+}
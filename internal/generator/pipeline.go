@@ -0,0 +1,397 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Step is one generator in a generation plan.
+type Step struct {
+	// Name identifies the step for dependencies (e.g., "docker-compose.yml")
+	Name string
+
+	// Title is the progress message printed when the step runs
+	// (e.g., "Generating docker-compose.yml...")
+	Title string
+
+	// Files are the files the step writes, relative to the project root.
+	// They are restored if a later step fails.
+	Files []string
+
+	// After names the steps that must run before this one
+	After []string
+
+	// Updates marks a step that merges into an existing file rather than replacing it
+	Updates bool
+
+	// Rerun marks a step whose output depends on what the run wrote before it
+	// (like the generation report), which Execute runs again rather than
+	// writing the files it rendered
+	Rerun bool
+
+	// Summary describes the files in dry-run mode when there is no Preview
+	Summary string
+
+	// Preview returns the content of one of the step's files for dry-run mode
+	Preview func(rel string) ([]byte, error)
+
+	// Generate writes the step's files under the project path, through fsys
+	Generate func(fsys FS, projectPath string) error
+}
+
+// Plan is the ordered set of generators that produce a project's files.
+// Execute runs them and restores the files they owned if one fails, so a
+// failed run never leaves a half-generated .devcontainer directory.
+type Plan struct {
+	steps []Step
+
+	// rendered holds each step's writes from the last Render of renderedPath,
+	// by step name, which Execute replays instead of generating them again
+	rendered     map[string][]fsWrite
+	renderedPath string
+}
+
+// NewPlan creates an empty generation plan.
+func NewPlan() *Plan {
+	return &Plan{}
+}
+
+// Add appends a step to the plan.
+func (p *Plan) Add(step Step) {
+	p.steps = append(p.steps, step)
+}
+
+// Names returns the names of the steps added so far.
+func (p *Plan) Names() []string {
+	names := make([]string, len(p.steps))
+	for i, step := range p.steps {
+		names[i] = step.Name
+	}
+	return names
+}
+
+// Files returns every file the plan writes, in step order.
+func (p *Plan) Files() []string {
+	steps, err := p.Steps()
+	if err != nil {
+		steps = p.steps
+	}
+	var files []string
+	for _, step := range steps {
+		files = append(files, step.Files...)
+	}
+	return files
+}
+
+// Owners returns the plan's file manifest: the step that owns each file it
+// writes, by path relative to the project root. It fails if two steps write
+// the same file, since the later one would silently overwrite the other's.
+func (p *Plan) Owners() (map[string]string, error) {
+	owners := make(map[string]string)
+	for _, step := range p.steps {
+		for _, rel := range step.Files {
+			rel = path.Clean(filepath.ToSlash(rel))
+			if owner, ok := owners[rel]; ok && owner != step.Name {
+				return nil, fmt.Errorf("%s is written by both the %s and %s generators", rel, owner, step.Name)
+			}
+			owners[rel] = step.Name
+		}
+	}
+	return owners, nil
+}
+
+// Steps returns the steps in the order they run: each after the steps it
+// names in After, otherwise in the order they were added. It fails on a
+// dependency on an unknown step, a dependency cycle, or two steps writing
+// the same file.
+func (p *Plan) Steps() ([]Step, error) {
+	if _, err := p.Owners(); err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int, len(p.steps))
+	for i, step := range p.steps {
+		if _, ok := index[step.Name]; ok {
+			return nil, fmt.Errorf("generation plan has two %q steps", step.Name)
+		}
+		index[step.Name] = i
+	}
+
+	pending := make([]int, len(p.steps))
+	dependents := make([][]int, len(p.steps))
+	for i, step := range p.steps {
+		for _, name := range step.After {
+			j, ok := index[name]
+			if !ok {
+				return nil, fmt.Errorf("generation step %q runs after unknown step %q", step.Name, name)
+			}
+			pending[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	var ready []int
+	for i := range p.steps {
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	ordered := make([]Step, 0, len(p.steps))
+	for len(ready) > 0 {
+		// Lowest index first keeps the order steps were added in
+		sort.Ints(ready)
+		i := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, p.steps[i])
+		for _, j := range dependents[i] {
+			pending[j]--
+			if pending[j] == 0 {
+				ready = append(ready, j)
+			}
+		}
+	}
+
+	if len(ordered) != len(p.steps) {
+		var cycle []string
+		for i, step := range p.steps {
+			if pending[i] > 0 {
+				cycle = append(cycle, step.Name)
+			}
+		}
+		return nil, fmt.Errorf("generation steps depend on each other: %s", strings.Join(cycle, ", "))
+	}
+	return ordered, nil
+}
+
+// Execute runs the steps in order. done is called after each step with
+// whether each of its files existed before it ran. If a step fails, every
+// file written so far is restored to its previous content, or removed along
+// with the directories the run created, and the step's error is returned.
+func (p *Plan) Execute(projectPath string, done func(step Step, existed []bool)) error {
+	steps, err := p.Steps()
+	if err != nil {
+		return err
+	}
+
+	snapshot := newFileSnapshot(projectPath)
+	for _, step := range steps {
+		existed := make([]bool, len(step.Files))
+		for i, rel := range step.Files {
+			existed[i] = snapshot.save(rel)
+		}
+
+		if err := p.run(step, projectPath); err != nil {
+			if restoreErr := snapshot.restore(); restoreErr != nil {
+				return errors.Join(err, fmt.Errorf("failed to roll back generated files: %w", restoreErr))
+			}
+			return &RollbackError{Step: step.Name, Err: err}
+		}
+		if done != nil {
+			done(step, existed)
+		}
+	}
+	return nil
+}
+
+// run writes a step's files to disk: the ones it rendered when the plan was
+// rendered for projectPath, otherwise by running the step.
+func (p *Plan) run(step Step, projectPath string) error {
+	writes, ok := p.rendered[step.Name]
+	if !ok || step.Rerun || projectPath != p.renderedPath {
+		return step.Generate(OS, projectPath)
+	}
+	for _, w := range writes {
+		if err := w.apply(OS); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render runs the steps in order against an in-memory filesystem that reads
+// through to the project, and returns it with the files the steps would write.
+// Nothing is written to disk, so a plan can be checked or compared with the
+// project's current files before it is executed; Execute then writes the
+// rendered files rather than generating them again.
+func (p *Plan) Render(projectPath string) (*MemFS, error) {
+	p.rendered = nil
+	steps, err := p.Steps()
+	if err != nil {
+		return nil, err
+	}
+
+	mem := NewMemFS(OS)
+	rendered := make(map[string][]fsWrite, len(steps))
+	for _, step := range steps {
+		rec := &recordingFS{FS: mem}
+		if err := step.Generate(rec, projectPath); err != nil {
+			return nil, err
+		}
+		rendered[step.Name] = rec.writes
+	}
+	p.rendered, p.renderedPath = rendered, projectPath
+	return mem, nil
+}
+
+// fsWrite is a directory or file a step created through an FS.
+type fsWrite struct {
+	path string
+	dir  bool
+	data []byte
+	mode os.FileMode
+}
+
+// apply makes the write again on fsys.
+func (w fsWrite) apply(fsys FS) error {
+	if w.dir {
+		return fsys.MkdirAll(w.path, w.mode)
+	}
+	return fsys.WriteFile(w.path, w.data, w.mode)
+}
+
+// recordingFS is an FS that records the writes made through it.
+type recordingFS struct {
+	FS
+	writes []fsWrite
+}
+
+func (r *recordingFS) MkdirAll(path string, perm os.FileMode) error {
+	if err := r.FS.MkdirAll(path, perm); err != nil {
+		return err
+	}
+	r.writes = append(r.writes, fsWrite{path: path, dir: true, mode: perm})
+	return nil
+}
+
+func (r *recordingFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	if err := r.FS.WriteFile(name, data, perm); err != nil {
+		return err
+	}
+	r.writes = append(r.writes, fsWrite{path: name, data: append([]byte(nil), data...), mode: perm})
+	return nil
+}
+
+// RollbackError is returned by Execute when a step failed and the files
+// written before it were restored.
+type RollbackError struct {
+	// Step is the name of the step that failed
+	Step string
+
+	// Err is the step's error
+	Err error
+}
+
+func (e *RollbackError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RollbackError) Unwrap() error {
+	return e.Err
+}
+
+// fileSnapshot holds the content of files before a run, to restore them.
+type fileSnapshot struct {
+	projectPath string
+
+	// files maps each saved path to its previous content, nil when it didn't exist
+	files map[string]*savedFile
+
+	// order is the saved paths, in the order they were saved
+	order []string
+
+	// dirs are the directories that didn't exist before the run
+	dirs map[string]bool
+}
+
+// savedFile is a file's content and permissions before the run.
+type savedFile struct {
+	content []byte
+	mode    os.FileMode
+}
+
+func newFileSnapshot(projectPath string) *fileSnapshot {
+	return &fileSnapshot{
+		projectPath: projectPath,
+		files:       make(map[string]*savedFile),
+		dirs:        make(map[string]bool),
+	}
+}
+
+// save records a file's current content and reports whether it exists.
+// Saving a file twice keeps its content from before the run.
+func (s *fileSnapshot) save(rel string) bool {
+	path := filepath.Join(s.projectPath, filepath.FromSlash(rel))
+	if saved, ok := s.files[path]; ok {
+		if saved != nil {
+			return true
+		}
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	for dir := filepath.Dir(path); dir != s.projectPath && strings.HasPrefix(dir, s.projectPath); dir = filepath.Dir(dir) {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		s.dirs[dir] = true
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		s.files[path] = nil
+		s.order = append(s.order, path)
+		return false
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		// An unreadable file can't be written either, so it is left alone
+		return true
+	}
+	s.files[path] = &savedFile{content: content, mode: info.Mode().Perm()}
+	s.order = append(s.order, path)
+	return true
+}
+
+// restore puts back every saved file and removes the directories the run created.
+func (s *fileSnapshot) restore() error {
+	var errs []error
+	for i := len(s.order) - 1; i >= 0; i-- {
+		path := s.order[i]
+		saved := s.files[path]
+		if saved == nil {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if err := os.WriteFile(path, saved.content, saved.mode); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Deepest first, so parents are empty by the time they are removed
+	dirs := make([]string, 0, len(s.dirs))
+	for dir := range s.dirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool { return len(dirs[i]) > len(dirs[j]) })
+	for _, dir := range dirs {
+		// Directories still holding files the steps didn't declare are kept
+		if err := os.Remove(dir); err != nil && !os.IsNotExist(err) && !isNotEmpty(dir) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isNotEmpty reports whether a directory has entries.
+func isNotEmpty(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	return err == nil && len(entries) > 0
+}
@@ -0,0 +1,287 @@
+package generator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// writeStep returns a step that writes content to each of its files.
+func writeStep(name string, after []string, files ...string) Step {
+	return Step{
+		Name:  name,
+		Files: files,
+		After: after,
+		Generate: func(fsys FS, projectPath string) error {
+			for _, rel := range files {
+				path := filepath.Join(projectPath, filepath.FromSlash(rel))
+				if err := fsys.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					return err
+				}
+				if err := fsys.WriteFile(path, []byte(name), 0644); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// writtenFiles returns the files under projectPath, relative to it and sorted.
+func writtenFiles(t *testing.T, projectPath string) []string {
+	t.Helper()
+	var files []string
+	err := filepath.WalkDir(projectPath, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(projectPath, path)
+		files = append(files, filepath.ToSlash(rel))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to list written files: %v", err)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// stepNames returns the names of steps.
+func stepNames(steps []Step) []string {
+	var names []string
+	for _, step := range steps {
+		names = append(names, step.Name)
+	}
+	return names
+}
+
+// TestPlan_Steps tests that steps run after their dependencies and otherwise
+// in the order they were added.
+func TestPlan_Steps(t *testing.T) {
+	plan := NewPlan()
+	plan.Add(writeStep("fluent-bit", []string{"docker-compose.yml"}))
+	plan.Add(writeStep("devcontainer.json", nil))
+	plan.Add(writeStep("docker-compose.yml", nil))
+	plan.Add(writeStep("report", []string{"fluent-bit", "devcontainer.json", "docker-compose.yml"}))
+
+	steps, err := plan.Steps()
+	if err != nil {
+		t.Fatalf("Steps() error = %v", err)
+	}
+	want := []string{"devcontainer.json", "docker-compose.yml", "fluent-bit", "report"}
+	if got := stepNames(steps); !reflect.DeepEqual(got, want) {
+		t.Errorf("Steps() = %v, want %v", got, want)
+	}
+}
+
+// TestPlan_StepsErrors tests that unknown dependencies and cycles are rejected.
+func TestPlan_StepsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   []Step
+		wantErr string
+	}{
+		{
+			name:    "unknown dependency",
+			steps:   []Step{writeStep("fluent-bit", []string{"docker-compose.yml"})},
+			wantErr: `unknown step "docker-compose.yml"`,
+		},
+		{
+			name:    "cycle",
+			steps:   []Step{writeStep("a", []string{"b"}), writeStep("b", []string{"a"}), writeStep("c", nil)},
+			wantErr: "depend on each other: a, b",
+		},
+		{
+			name: "two steps writing the same file",
+			steps: []Step{
+				writeStep("backup", nil, ".devcontainer/entrypoint.sh"),
+				writeStep("processor", nil, ".devcontainer/./entrypoint.sh"),
+			},
+			wantErr: ".devcontainer/entrypoint.sh is written by both the backup and processor generators",
+		},
+		{
+			name:    "duplicate step",
+			steps:   []Step{writeStep("a", nil), writeStep("a", nil)},
+			wantErr: `two "a" steps`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := NewPlan()
+			for _, step := range tt.steps {
+				plan.Add(step)
+			}
+			if _, err := plan.Steps(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Steps() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestPlan_Execute tests that every step runs and reports which files existed.
+func TestPlan_Execute(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".devcontainer"), 0755); err != nil {
+		t.Fatalf("Failed to create .devcontainer: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".devcontainer", "Dockerfile"), []byte("FROM old"), 0644); err != nil {
+		t.Fatalf("Failed to write Dockerfile: %v", err)
+	}
+
+	plan := NewPlan()
+	plan.Add(writeStep("docker-compose.yml", nil, ".devcontainer/docker-compose.yml"))
+	plan.Add(writeStep("Dockerfile", nil, ".devcontainer/Dockerfile"))
+
+	existed := make(map[string]bool)
+	err := plan.Execute(tmpDir, func(step Step, stepExisted []bool) {
+		for i, rel := range step.Files {
+			existed[rel] = stepExisted[i]
+		}
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := map[string]bool{".devcontainer/docker-compose.yml": false, ".devcontainer/Dockerfile": true}
+	if !reflect.DeepEqual(existed, want) {
+		t.Errorf("existed = %v, want %v", existed, want)
+	}
+	if got := plan.Files(); !reflect.DeepEqual(got, []string{".devcontainer/docker-compose.yml", ".devcontainer/Dockerfile"}) {
+		t.Errorf("Files() = %v", got)
+	}
+}
+
+// TestPlan_ExecuteRollback tests that a failing step restores overwritten
+// files and removes the files and directories written before it.
+func TestPlan_ExecuteRollback(t *testing.T) {
+	tmpDir := t.TempDir()
+	dockerfile := filepath.Join(tmpDir, ".devcontainer", "Dockerfile")
+	if err := os.MkdirAll(filepath.Dir(dockerfile), 0755); err != nil {
+		t.Fatalf("Failed to create .devcontainer: %v", err)
+	}
+	if err := os.WriteFile(dockerfile, []byte("FROM old"), 0600); err != nil {
+		t.Fatalf("Failed to write Dockerfile: %v", err)
+	}
+
+	failure := errors.New("keycloak sidecar generation failed")
+	plan := NewPlan()
+	plan.Add(writeStep("Dockerfile", nil, ".devcontainer/Dockerfile"))
+	plan.Add(writeStep("metrics", nil, ".devcontainer/grafana/provisioning/dashboards/app-metrics.json"))
+	plan.Add(Step{
+		Name:     "keycloak",
+		Files:    []string{".devcontainer/keycloak/realm.json"},
+		Generate: func(FS, string) error { return failure },
+	})
+
+	var ran []string
+	err := plan.Execute(tmpDir, func(step Step, _ []bool) { ran = append(ran, step.Name) })
+
+	var rollback *RollbackError
+	if !errors.As(err, &rollback) || rollback.Step != "keycloak" || !errors.Is(err, failure) {
+		t.Fatalf("Execute() error = %v, want a rollback of keycloak", err)
+	}
+	if !reflect.DeepEqual(ran, []string{"Dockerfile", "metrics"}) {
+		t.Errorf("steps run = %v, want Dockerfile, metrics", ran)
+	}
+
+	content, err := os.ReadFile(dockerfile)
+	if err != nil || string(content) != "FROM old" {
+		t.Errorf("Dockerfile = %q, %v, want it restored", content, err)
+	}
+	if info, err := os.Stat(dockerfile); err == nil && info.Mode().Perm() != 0600 {
+		t.Errorf("Dockerfile mode = %v, want 0600 kept", info.Mode().Perm())
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".devcontainer", "grafana")); !os.IsNotExist(err) {
+		t.Errorf("grafana directory should be removed, stat error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".devcontainer")); err != nil {
+		t.Errorf(".devcontainer existed before the run and should be kept: %v", err)
+	}
+}
+
+// TestPlan_Owners tests the file manifest of a plan.
+func TestPlan_Owners(t *testing.T) {
+	plan := NewPlan()
+	plan.Add(writeStep("backup", nil, ".devcontainer/entrypoint.sh", ".devcontainer/scripts/backup.sh"))
+	plan.Add(writeStep("file-processor", nil, ".devcontainer/entrypoint.processor.sh"))
+
+	owners, err := plan.Owners()
+	if err != nil {
+		t.Fatalf("Owners() error = %v", err)
+	}
+	want := map[string]string{
+		".devcontainer/entrypoint.sh":           "backup",
+		".devcontainer/scripts/backup.sh":       "backup",
+		".devcontainer/entrypoint.processor.sh": "file-processor",
+	}
+	if !reflect.DeepEqual(owners, want) {
+		t.Errorf("Owners() = %v, want %v", owners, want)
+	}
+}
+
+// TestPlan_Render tests that a plan renders in memory without writing to disk.
+func TestPlan_Render(t *testing.T) {
+	tmpDir := t.TempDir()
+	plan := NewPlan()
+	plan.Add(writeStep("docker-compose.yml", nil, ".devcontainer/docker-compose.yml"))
+	plan.Add(writeStep("metrics", []string{"docker-compose.yml"}, ".devcontainer/prometheus/prometheus.yml"))
+
+	mem, err := plan.Render(tmpDir)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	want := []string{
+		filepath.Join(tmpDir, ".devcontainer", "docker-compose.yml"),
+		filepath.Join(tmpDir, ".devcontainer", "prometheus", "prometheus.yml"),
+	}
+	if got := mem.Files(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Render() files = %v, want %v", got, want)
+	}
+	if got := writtenFiles(t, tmpDir); len(got) > 0 {
+		t.Errorf("Render() wrote %v to disk", got)
+	}
+}
+
+// TestPlan_ExecuteRendered tests that Execute writes the files a plan
+// rendered instead of generating them again, except for Rerun steps.
+func TestPlan_ExecuteRendered(t *testing.T) {
+	tmpDir := t.TempDir()
+	runs := map[string]int{}
+	counted := func(step Step) Step {
+		generate := step.Generate
+		step.Generate = func(fsys FS, projectPath string) error {
+			runs[step.Name]++
+			return generate(fsys, projectPath)
+		}
+		return step
+	}
+
+	plan := NewPlan()
+	plan.Add(counted(writeStep("docker-compose.yml", nil, ".devcontainer/docker-compose.yml")))
+	report := writeStep("report", []string{"docker-compose.yml"}, ReportFile)
+	report.Rerun = true
+	plan.Add(counted(report))
+
+	if _, err := plan.Render(tmpDir); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if err := plan.Execute(tmpDir, nil); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if want := map[string]int{"docker-compose.yml": 1, "report": 2}; !reflect.DeepEqual(runs, want) {
+		t.Errorf("generator runs = %v, want %v", runs, want)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".devcontainer", "docker-compose.yml"))
+	if err != nil || string(data) != "docker-compose.yml" {
+		t.Errorf("rendered file on disk = %q, %v", data, err)
+	}
+	if got, want := writtenFiles(t, tmpDir), []string{".devcontainer/docker-compose.yml", ReportFile}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Execute() wrote %v, want %v", got, want)
+	}
+}
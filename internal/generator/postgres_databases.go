@@ -0,0 +1,86 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// PostgresDatabasesScript is the PostgreSQL init script that creates the
+// additional databases, relative to the project root.
+const PostgresDatabasesScript = ".devcontainer/postgres/init-databases.sql"
+
+// PostgresDatabase is an additional database in the development PostgreSQL.
+type PostgresDatabase struct {
+	// Name is the database name (e.g., "analytics")
+	Name string
+
+	// URLVar is the environment variable holding its URL (e.g., "ANALYTICS_DATABASE_URL")
+	URLVar string
+}
+
+// PostgresDatabaseURLVar returns the environment variable holding an
+// additional database's URL.
+func PostgresDatabaseURLVar(name string) string {
+	return strings.ToUpper(name) + "_DATABASE_URL"
+}
+
+// postgresDatabases returns the additional databases declared in .dockstart.yml.
+func postgresDatabases(detection *models.Detection) []PostgresDatabase {
+	databases := make([]PostgresDatabase, 0, len(detection.PostgresDatabases))
+	for _, name := range detection.PostgresDatabases {
+		databases = append(databases, PostgresDatabase{Name: name, URLVar: PostgresDatabaseURLVar(name)})
+	}
+	return databases
+}
+
+// PostgresDatabasesConfig holds the configuration for generating init-databases.sql.
+type PostgresDatabasesConfig struct {
+	// Databases are the databases to create
+	Databases []string
+}
+
+// PostgresDatabasesGenerator generates .devcontainer/postgres/init-databases.sql,
+// which creates the additional databases when PostgreSQL initializes its volume.
+type PostgresDatabasesGenerator struct {
+	output
+}
+
+// NewPostgresDatabasesGenerator creates a new additional databases script generator.
+func NewPostgresDatabasesGenerator() *PostgresDatabasesGenerator {
+	return &PostgresDatabasesGenerator{}
+}
+
+// Generate creates .devcontainer/postgres/init-databases.sql.
+func (g *PostgresDatabasesGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	content, err := g.GenerateContent(detection)
+	if err != nil {
+		return err
+	}
+
+	postgresDir := filepath.Join(projectPath, ".devcontainer", "postgres")
+	if err := g.fs().MkdirAll(postgresDir, 0755); err != nil {
+		return fmt.Errorf("failed to create postgres directory: %w", err)
+	}
+	if err := g.fs().WriteFile(filepath.Join(postgresDir, "init-databases.sql"), content, 0644); err != nil {
+		return fmt.Errorf("failed to write init-databases.sql: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateContent returns the init-databases.sql content without writing to disk.
+func (g *PostgresDatabasesGenerator) GenerateContent(detection *models.Detection) ([]byte, error) {
+	return renderTemplate("postgres/init-databases.sql.tmpl", &PostgresDatabasesConfig{Databases: detection.PostgresDatabases})
+}
+
+// ShouldGenerate returns true if additional databases are declared and
+// PostgreSQL is generated rather than imported.
+func (g *PostgresDatabasesGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return len(detection.PostgresDatabases) > 0 &&
+		(detection.HasService("postgres") || detection.GetVectorStore() == "pgvector") &&
+		detection.ExistingServiceFor("postgres") == ""
+}
@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// PostgresSetupScript is the PostgreSQL init script that creates the
+// extensions and roles declared in .dockstart.yml, relative to the project root.
+const PostgresSetupScript = ".devcontainer/postgres/setup.sh"
+
+// PostgresSetupConfig holds the configuration for generating setup.sh.
+type PostgresSetupConfig struct {
+	// Extensions are created in every database
+	Extensions []string
+
+	// Roles are created, and granted access to every database
+	Roles []models.PostgresRole
+}
+
+// PostgresSetupGenerator generates .devcontainer/postgres/setup.sh, which
+// creates extensions and roles when PostgreSQL initializes its volume.
+type PostgresSetupGenerator struct {
+	output
+}
+
+// NewPostgresSetupGenerator creates a new extensions and roles script generator.
+func NewPostgresSetupGenerator() *PostgresSetupGenerator {
+	return &PostgresSetupGenerator{}
+}
+
+// Generate creates .devcontainer/postgres/setup.sh.
+func (g *PostgresSetupGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	content, err := g.GenerateContent(detection)
+	if err != nil {
+		return err
+	}
+
+	postgresDir := filepath.Join(projectPath, ".devcontainer", "postgres")
+	if err := g.fs().MkdirAll(postgresDir, 0755); err != nil {
+		return fmt.Errorf("failed to create postgres directory: %w", err)
+	}
+	if err := g.fs().WriteFile(filepath.Join(postgresDir, "setup.sh"), content, 0755); err != nil {
+		return fmt.Errorf("failed to write setup.sh: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateContent returns the setup.sh content without writing to disk.
+func (g *PostgresSetupGenerator) GenerateContent(detection *models.Detection) ([]byte, error) {
+	return renderTemplate("postgres/setup.sh.tmpl", &PostgresSetupConfig{
+		Extensions: detection.SetupExtensions(),
+		Roles:      detection.PostgresRoles,
+	})
+}
+
+// ShouldGenerate returns true if extensions (postgis for the detected
+// geospatial libraries included) or roles are declared, and PostgreSQL is
+// generated rather than imported.
+func (g *PostgresSetupGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return (len(detection.SetupExtensions()) > 0 || len(detection.PostgresRoles) > 0) &&
+		(detection.HasService("postgres") || detection.GetVectorStore() == "pgvector") &&
+		detection.ExistingServiceFor("postgres") == ""
+}
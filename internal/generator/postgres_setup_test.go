@@ -0,0 +1,226 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestPostgresSetupGenerator tests the init script creating extensions and roles.
+func TestPostgresSetupGenerator(t *testing.T) {
+	gen := NewPostgresSetupGenerator()
+
+	tests := []struct {
+		name      string
+		detection *models.Detection
+		want      bool
+	}{
+		{
+			name:      "no extensions or roles",
+			detection: &models.Detection{Services: []string{"postgres"}},
+			want:      false,
+		},
+		{
+			name:      "extensions",
+			detection: &models.Detection{Services: []string{"postgres"}, PostgresExtensions: []string{"uuid-ossp"}},
+			want:      true,
+		},
+		{
+			name:      "roles",
+			detection: &models.Detection{Services: []string{"postgres"}, PostgresRoles: []models.PostgresRole{{Name: "reporting"}}},
+			want:      true,
+		},
+		{
+			name: "imported postgres",
+			detection: &models.Detection{
+				Services:           []string{"postgres"},
+				PostgresExtensions: []string{"uuid-ossp"},
+				ExistingCompose: &models.ExistingCompose{
+					Services: []models.ExistingService{{Name: "db", Role: "postgres"}},
+				},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gen.ShouldGenerate(tt.detection); got != tt.want {
+				t.Errorf("ShouldGenerate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	content, err := gen.GenerateContent(&models.Detection{
+		PostgresExtensions: []string{"uuid-ossp", "pg_trgm"},
+		PostgresRoles: []models.PostgresRole{
+			{Name: "app_user", Password: "s3cret"},
+			{Name: "reporting", ReadOnly: true},
+			{Name: "admin", Superuser: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	script := string(content)
+	for _, want := range []string{
+		`    -v app_user_password="$POSTGRES_ROLE_APP_USER_PASSWORD" \`,
+		`SELECT format('CREATE ROLE %I LOGIN PASSWORD %L', 'app_user', :'app_user_password')` + "\nWHERE NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'app_user')\\gexec\n",
+		`SELECT format('CREATE ROLE %I LOGIN SUPERUSER PASSWORD %L', 'admin', :'admin_password')`,
+		"CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";\nCREATE EXTENSION IF NOT EXISTS \"pg_trgm\";\n",
+		`GRANT ALL PRIVILEGES ON DATABASE "$db" TO "app_user";`,
+		`ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT ON TABLES TO "reporting";`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("setup.sh should contain %q, got:\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, "s3cret") {
+		t.Errorf("setup.sh should read the role passwords from the environment, got:\n%s", script)
+	}
+	if strings.Contains(script, `TO "admin"`) {
+		t.Errorf("setup.sh should not grant privileges to a superuser, got:\n%s", script)
+	}
+	if strings.Contains(script, "GRANT ALL ON SCHEMA public TO \"reporting\"") {
+		t.Errorf("setup.sh should only grant SELECT to a read-only role, got:\n%s", script)
+	}
+}
+
+// TestComposeGenerator_PostgresSetup tests mounting setup.sh into both
+// PostgreSQL services, and the images the extensions need.
+func TestComposeGenerator_PostgresSetup(t *testing.T) {
+	tests := []struct {
+		name       string
+		extensions []string
+		wantImage  string
+	}{
+		{"contrib extension", []string{"uuid-ossp"}, "image: postgres:16-alpine\n"},
+		{"pgvector", []string{"vector"}, "image: pgvector/pgvector:pg16\n"},
+		{"postgis", []string{"postgis"}, "image: postgis/postgis:16-3.5-alpine\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detection := &models.Detection{
+				Language:           "go",
+				Version:            "1.23",
+				Services:           []string{"postgres"},
+				PostgresExtensions: tt.extensions,
+				Testing:            models.TestingOptions{Isolation: models.TestIsolationService},
+			}
+			content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+			compose := string(content)
+
+			mount := "      - ./postgres/setup.sh:/docker-entrypoint-initdb.d/setup.sh:ro\n"
+			if got := strings.Count(compose, mount); got != 2 {
+				t.Errorf("docker-compose.yml should mount setup.sh into postgres and postgres-test, got %d:\n%s", got, compose)
+			}
+			if got := strings.Count(compose, tt.wantImage); got != 2 {
+				t.Errorf("docker-compose.yml should run %q for postgres and postgres-test, got %d:\n%s", tt.wantImage, got, compose)
+			}
+		})
+	}
+}
+
+// TestPostGIS tests running PostGIS for detected geospatial libraries, and
+// GDAL in the app's Dockerfile for the libraries that build against it.
+func TestPostGIS(t *testing.T) {
+	detection := &models.Detection{
+		Language:     "python",
+		Version:      "3.12",
+		Services:     []string{"postgres"},
+		Capabilities: models.Capabilities{GeoLibraries: []string{"geoalchemy2", "gdal"}},
+	}
+
+	compose, err := NewComposeGenerator().GenerateContent(detection, "maps")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if !strings.Contains(string(compose), "image: postgis/postgis:16-3.5-alpine\n") {
+		t.Errorf("docker-compose.yml should run the PostGIS image, got:\n%s", compose)
+	}
+
+	gen := NewPostgresSetupGenerator()
+	if !gen.ShouldGenerate(detection) {
+		t.Error("ShouldGenerate() = false, want true for the postgis extension")
+	}
+	script, err := gen.GenerateContent(detection)
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if !strings.Contains(string(script), `CREATE EXTENSION IF NOT EXISTS "postgis";`) {
+		t.Errorf("setup.sh should create the postgis extension, got:\n%s", script)
+	}
+
+	dockerfile, err := NewDockerfileGenerator().GenerateContent(detection, "maps")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	for _, want := range []string{
+		"RUN apt-get update && apt-get install -y gdal-bin libgdal-dev \\\n",
+		`pip install "GDAL==$(gdal-config --version)"`,
+	} {
+		if !strings.Contains(string(dockerfile), want) {
+			t.Errorf("Dockerfile should contain %q, got:\n%s", want, dockerfile)
+		}
+	}
+
+	// pgvector's image has no PostGIS, so it wins
+	detection.VectorLibraries = []string{"pgvector"}
+	if detection.UsesPostGIS() {
+		t.Error("UsesPostGIS() = true, want false with pgvector")
+	}
+
+	// Without a library building against GDAL, nor PostgreSQL, nothing changes
+	detection = &models.Detection{Language: "node", Version: "20", Capabilities: models.Capabilities{GeoLibraries: []string{"@turf/turf"}}}
+	if detection.UsesPostGIS() || gen.ShouldGenerate(detection) {
+		t.Error("PostGIS should not be set up without PostgreSQL")
+	}
+	dockerfile, err = NewDockerfileGenerator().GenerateContent(detection, "maps")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if strings.Contains(string(dockerfile), "gdal") {
+		t.Errorf("Dockerfile should not install GDAL for turf, got:\n%s", dockerfile)
+	}
+}
+
+// TestPostgresRoles_Credentials tests that role passwords are generated in
+// .devcontainer/.env and passed to the PostgreSQL services.
+func TestPostgresRoles_Credentials(t *testing.T) {
+	detection := &models.Detection{
+		Language:      "go",
+		Version:       "1.23",
+		Services:      []string{"postgres"},
+		PostgresRoles: []models.PostgresRole{{Name: "app_user", Password: "s3cret"}, {Name: "reporting", ReadOnly: true}},
+		Testing:       models.TestingOptions{Isolation: models.TestIsolationService},
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	env := "      POSTGRES_ROLE_REPORTING_PASSWORD: ${POSTGRES_ROLE_REPORTING_PASSWORD}\n"
+	if got := strings.Count(string(content), env); got != 2 {
+		t.Errorf("postgres and postgres-test should get the role's password, got %d:\n%s", got, content)
+	}
+
+	gen := NewCredentialsGenerator()
+	gen.random = strings.NewReader(strings.Repeat("x", 64))
+	credentials, err := gen.Credentials(detection, t.TempDir(), "shop")
+	if err != nil {
+		t.Fatalf("Credentials() error = %v", err)
+	}
+	values := make(map[string]string)
+	for _, credential := range credentials {
+		values[credential.Name] = credential.Value
+	}
+	if values["POSTGRES_ROLE_APP_USER_PASSWORD"] != "s3cret" {
+		t.Errorf("a role password set in .dockstart.yml should be used as is, got %v", values)
+	}
+	if password := values["POSTGRES_ROLE_REPORTING_PASSWORD"]; len(password) != 32 || password == "reporting" {
+		t.Errorf("a role without a password should get a generated one, got %v", values)
+	}
+}
@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// prebuildLayer installs the dependencies in their own Dockerfile layer, from
+// the manifests and lockfile alone, so source changes don't invalidate it.
+type prebuildLayer struct {
+	// Tools installs the package manager, as root
+	Tools string
+
+	// Files are the manifests and lockfiles copied first; globs match files
+	// that may be missing
+	Files string
+
+	// Install fills the package manager's cache, or installs outside the
+	// bind-mounted /workspace, so the postCreateCommand install finds the
+	// dependencies local. What it would leave in /workspace is removed, since
+	// the bind mount hides it
+	Install string
+}
+
+// prebuildLayers are the dependency layers, by package manager. The Cargo
+// layer stubs the crate's sources, which cargo fetch needs to read the manifest.
+var prebuildLayers = map[string]prebuildLayer{
+	"npm":    {Files: "package.json package-lock.json* npm-shrinkwrap.json*", Install: "npm install --ignore-scripts --no-audit --no-fund && rm -rf node_modules"},
+	"yarn":   {Tools: "corepack enable", Files: "package.json yarn.lock", Install: "yarn install --frozen-lockfile --ignore-scripts && rm -rf node_modules"},
+	"pnpm":   {Tools: "corepack enable", Files: "package.json pnpm-lock.yaml", Install: "pnpm fetch"},
+	"pip":    {Files: "requirements.txt", Install: "pip install -r requirements.txt"},
+	"poetry": {Tools: "pip install --no-cache-dir poetry", Files: "pyproject.toml poetry.lock", Install: "poetry install --no-root --no-interaction"},
+	"uv":     {Tools: "pip install --no-cache-dir uv", Files: "pyproject.toml uv.lock", Install: "uv sync --frozen --no-install-project && rm -rf .venv"},
+	"pipenv": {Tools: "pip install --no-cache-dir pipenv", Files: "Pipfile Pipfile.lock", Install: "pipenv install --dev"},
+	"go":     {Files: "go.mod go.sum*", Install: "go mod download"},
+	"cargo":  {Files: "Cargo.toml Cargo.lock*", Install: "mkdir -p src && touch src/lib.rs src/main.rs && cargo fetch && rm -rf src"},
+}
+
+// dependencyLayer returns the prebuild dependency layer of the detected
+// package manager, and false outside prebuild mode or when the dependencies
+// can't be installed without the sources (pip install -e .).
+func dependencyLayer(detection *models.Detection) (prebuildLayer, bool) {
+	if detection.Prebuild == nil {
+		return prebuildLayer{}, false
+	}
+	if detection.PackageManager == "pip" && !strings.Contains(detection.InstallCommand, "requirements.txt") {
+		return prebuildLayer{}, false
+	}
+	layer, ok := prebuildLayers[detection.PackageManager]
+	return layer, ok
+}
+
+// invalidImageChars are the characters replaced in a project name to make an image name.
+var invalidImageChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// PrebuildImage returns the local image prebuild mode caches from when
+// prebuild.image is not set (e.g., "shop-devcontainer").
+func PrebuildImage(projectName string) string {
+	name := strings.Trim(invalidImageChars.ReplaceAllString(strings.ToLower(projectName), "-"), "-._")
+	if name == "" {
+		name = "app"
+	}
+	return name + "-devcontainer"
+}
+
+// PrebuildCI holds the CI job that prebuilds the dev container image and pushes it.
+type PrebuildCI struct {
+	// Image is the image built, pushed, and cached from
+	Image string
+
+	// Push is false for a local image name, which no registry accepts
+	Push bool
+
+	// Registry is the registry logged in to, or empty for Docker Hub
+	Registry string
+
+	// Username and Password log in to Registry: the CI job's own credentials
+	// for the provider's registry, and secrets or variables otherwise
+	Username string
+	Password string
+}
+
+// prebuildCI returns the prebuild job of a CI provider, or nil outside prebuild mode.
+func prebuildCI(detection *models.Detection, provider string) *PrebuildCI {
+	if detection.Prebuild == nil {
+		return nil
+	}
+	job := &PrebuildCI{Image: detection.Prebuild.Image, Push: detection.Prebuild.Pushed()}
+	if host, _, ok := strings.Cut(job.Image, "/"); ok && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		job.Registry = host
+	}
+
+	switch provider {
+	case CIGitHub:
+		job.Username, job.Password = "${{ secrets.REGISTRY_USERNAME }}", "${{ secrets.REGISTRY_PASSWORD }}"
+		if job.Registry == "ghcr.io" {
+			job.Username, job.Password = "${{ github.actor }}", "${{ secrets.GITHUB_TOKEN }}"
+		}
+	case CIGitLab:
+		job.Username, job.Password = "$REGISTRY_USERNAME", "$REGISTRY_PASSWORD"
+		if job.Registry == "registry.gitlab.com" {
+			job.Username, job.Password = "$CI_REGISTRY_USER", "$CI_REGISTRY_PASSWORD"
+		}
+	}
+	return job
+}
@@ -0,0 +1,224 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestDockerfileGenerator_Prebuild tests the dependency layer, copied and
+// installed from the manifests before anything else can change.
+func TestDockerfileGenerator_Prebuild(t *testing.T) {
+	tests := []struct {
+		name      string
+		detection *models.Detection
+		want      []string
+	}{
+		{
+			name:      "npm as the non-root user",
+			detection: &models.Detection{Language: "node", Version: "20", PackageManager: "npm", InstallCommand: "npm ci", NonRoot: true},
+			want: []string{
+				"COPY --chown=$USER_UID:$USER_GID package.json package-lock.json* npm-shrinkwrap.json* ./\nRUN npm install",
+			},
+		},
+		{
+			name:      "poetry installs its tool as root",
+			detection: &models.Detection{Language: "python", Version: "3.12", PackageManager: "poetry", InstallCommand: "poetry install"},
+			want: []string{
+				"RUN pip install --no-cache-dir poetry\n",
+				"COPY pyproject.toml poetry.lock ./\nRUN poetry install --no-root --no-interaction\n",
+			},
+		},
+		{
+			name:      "go",
+			detection: &models.Detection{Language: "go", Version: "1.23", PackageManager: "go", InstallCommand: "go mod download"},
+			want:      []string{"COPY go.mod go.sum* ./\nRUN go mod download\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.detection.Prebuild = &models.PrebuildOptions{Image: "shop-devcontainer"}
+			content, err := NewDockerfileGenerator().GenerateContent(tt.detection, "shop")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+			dockerfile := string(content)
+			for _, want := range tt.want {
+				if !strings.Contains(dockerfile, want) {
+					t.Errorf("Dockerfile should contain %q, got:\n%s", want, dockerfile)
+				}
+			}
+			if layer, cmd := strings.Index(dockerfile, "\nCOPY "), strings.Index(dockerfile, "\nCMD "); layer < 0 || layer > cmd {
+				t.Errorf("the dependency layer should come before the default command, got:\n%s", dockerfile)
+			}
+
+			tt.detection.Prebuild = nil
+			content, err = NewDockerfileGenerator().GenerateContent(tt.detection, "shop")
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+			if strings.Contains(string(content), "COPY ") {
+				t.Errorf("Dockerfile should not copy manifests outside prebuild mode, got:\n%s", content)
+			}
+		})
+	}
+
+	// pip install -e . needs the sources, so it stays in postCreateCommand
+	detection := &models.Detection{
+		Language: "python", Version: "3.12", PackageManager: "pip", InstallCommand: "pip install -e .",
+		Prebuild: &models.PrebuildOptions{Image: "shop-devcontainer"},
+	}
+	content, err := NewDockerfileGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if strings.Contains(string(content), "COPY ") {
+		t.Errorf("Dockerfile should not have a dependency layer for pip install -e ., got:\n%s", content)
+	}
+}
+
+// TestDevcontainerGenerator_Prebuild tests that devcontainer.json builds the
+// generated Dockerfile and caches from the prebuilt image.
+func TestDevcontainerGenerator_Prebuild(t *testing.T) {
+	detection := &models.Detection{
+		Language:       "go",
+		Version:        "1.23",
+		PackageManager: "go",
+		Prebuild:       &models.PrebuildOptions{Image: "ghcr.io/acme/shop-devcontainer"},
+	}
+	content, err := NewDevcontainerGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	var devcontainer struct {
+		Image      string `json:"image"`
+		RemoteUser string `json:"remoteUser"`
+		Build      struct {
+			Dockerfile string `json:"dockerfile"`
+			CacheFrom  string `json:"cacheFrom"`
+		} `json:"build"`
+	}
+	if err := json.Unmarshal(content, &devcontainer); err != nil {
+		t.Fatalf("devcontainer.json is not valid JSON: %v\n%s", err, content)
+	}
+	if devcontainer.Image != "" || devcontainer.Build.Dockerfile != "Dockerfile" {
+		t.Errorf("devcontainer.json should build the generated Dockerfile, got:\n%s", content)
+	}
+	if devcontainer.Build.CacheFrom != "ghcr.io/acme/shop-devcontainer" {
+		t.Errorf("build.cacheFrom = %q, want the prebuilt image", devcontainer.Build.CacheFrom)
+	}
+	if devcontainer.RemoteUser != "root" {
+		t.Errorf("remoteUser = %q, want root, the generated Dockerfile's user", devcontainer.RemoteUser)
+	}
+}
+
+// TestComposeGenerator_Prebuild tests that the app service caches from the prebuilt image.
+func TestComposeGenerator_Prebuild(t *testing.T) {
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Services: []string{"postgres"},
+		Prebuild: &models.PrebuildOptions{Image: "shop-devcontainer"},
+	}
+	content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if want := "      dockerfile: .devcontainer/Dockerfile\n      cache_from:\n        - shop-devcontainer\n"; !strings.Contains(string(content), want) {
+		t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, content)
+	}
+}
+
+// TestCIGenerator_Prebuild tests the prebuild job of each provider.
+func TestCIGenerator_Prebuild(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		image    string
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:     "github with ghcr.io",
+			provider: CIGitHub,
+			image:    "ghcr.io/acme/shop-devcontainer",
+			want: []string{
+				"uses: devcontainers/ci@v0.3",
+				"imageName: ghcr.io/acme/shop-devcontainer\n          cacheFrom: ghcr.io/acme/shop-devcontainer\n",
+				"push: filter\n          refFilterForPush: refs/heads/main",
+				"registry: ghcr.io\n          username: ${{ github.actor }}\n          password: ${{ secrets.GITHUB_TOKEN }}",
+				"packages: write",
+			},
+		},
+		{
+			name:     "github with a local image",
+			provider: CIGitHub,
+			image:    "shop-devcontainer",
+			want:     []string{"push: never"},
+			notWant:  []string{"docker/login-action", "packages: write"},
+		},
+		{
+			name:     "gitlab with its registry",
+			provider: CIGitLab,
+			image:    "registry.gitlab.com/acme/shop-devcontainer",
+			want: []string{
+				"- npm install -g @devcontainers/cli",
+				`- echo "$CI_REGISTRY_PASSWORD" | docker login -u "$CI_REGISTRY_USER" --password-stdin registry.gitlab.com`,
+				"--image-name registry.gitlab.com/acme/shop-devcontainer --cache-from registry.gitlab.com/acme/shop-devcontainer --push",
+				"- if: $CI_COMMIT_BRANCH == $CI_DEFAULT_BRANCH",
+			},
+		},
+		{
+			name:     "gitlab with Docker Hub",
+			provider: CIGitLab,
+			image:    "acme/shop-devcontainer",
+			want:     []string{`docker login -u "$REGISTRY_USERNAME" --password-stdin` + "\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detection := &models.Detection{
+				Language:       "node",
+				Version:        "20",
+				PackageManager: "npm",
+				InstallCommand: "npm ci",
+				TestCommand:    "npm test",
+				Prebuild:       &models.PrebuildOptions{Image: tt.image},
+			}
+			content, err := NewCIGenerator().GenerateContent(detection, "shop", tt.provider)
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+			pipeline := string(content)
+			for _, want := range tt.want {
+				if !strings.Contains(pipeline, want) {
+					t.Errorf("pipeline should contain %q, got:\n%s", want, pipeline)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(pipeline, notWant) {
+					t.Errorf("pipeline should not contain %q, got:\n%s", notWant, pipeline)
+				}
+			}
+		})
+	}
+}
+
+// TestPrebuildImage tests the default image name.
+func TestPrebuildImage(t *testing.T) {
+	tests := map[string]string{
+		"shop":        "shop-devcontainer",
+		"My Project!": "my-project-devcontainer",
+		"___":         "app-devcontainer",
+	}
+	for project, want := range tests {
+		if got := PrebuildImage(project); got != want {
+			t.Errorf("PrebuildImage(%q) = %q, want %q", project, got, want)
+		}
+	}
+}
@@ -2,9 +2,7 @@
 package generator
 
 import (
-	"bytes"
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"github.com/jpequegn/dockstart/internal/models"
@@ -33,6 +31,42 @@ type ProcessorSidecarConfig struct {
 	// ThumbnailSize is the thumbnail dimensions (default: 200x200)
 	ThumbnailSize string
 
+	// ExtraThumbnailSizes are additional image thumbnails, written as <name>.thumb-<size>.<ext>
+	ExtraThumbnailSizes []string
+
+	// AllowedMIMETypes restricts accepted files (e.g., "image/*"); empty accepts any type
+	AllowedMIMETypes []string
+
+	// ScanEnabled runs scan-file.sh (ClamAV) on each file before processing
+	ScanEnabled bool
+
+	// SanitizeImages strips EXIF/GPS metadata, normalizes orientation, and
+	// converts images outside the safe format whitelist to PNG
+	SanitizeImages bool
+
+	// NotifyMethod is how the app is told a file was processed: "file", "webhook", or "queue"
+	NotifyMethod string
+
+	// NotifyURL is the webhook notify.sh POSTs to when NotifyMethod is "webhook"
+	NotifyURL string
+
+	// NotifyQueue is the Redis list notify.sh pushes onto when NotifyMethod is "queue"
+	NotifyQueue string
+
+	// S3 exchanges uploads through a bucket: s3-bridge.sh moves objects from
+	// pending/ into the local pipeline and mirrors processed/ and failed/ back
+	S3 bool
+
+	// S3Endpoint is the S3 API the bridge talks to (MinIO or LocalStack)
+	S3Endpoint string
+
+	// S3Bucket is the bucket holding the pending/, processed/, and failed/ prefixes
+	S3Bucket string
+
+	// User is the non-root user the pipeline runs as after the entrypoint hands
+	// it the files volume. Empty to run as root
+	User string
+
 	// ProjectName is the name of the project
 	ProjectName string
 }
@@ -47,11 +81,14 @@ func DefaultProcessorConfig() *ProcessorSidecarConfig {
 		PollInterval:     5,
 		MaxFileSize:      52428800, // 50MB
 		ThumbnailSize:    "200x200",
+		NotifyMethod:     "file",
 	}
 }
 
 // ProcessorSidecarGenerator generates file processor sidecar container files.
-type ProcessorSidecarGenerator struct{}
+type ProcessorSidecarGenerator struct {
+	output
+}
 
 // NewProcessorSidecarGenerator creates a new processor sidecar generator.
 func NewProcessorSidecarGenerator() *ProcessorSidecarGenerator {
@@ -60,92 +97,73 @@ func NewProcessorSidecarGenerator() *ProcessorSidecarGenerator {
 
 // GenerateDockerfile generates the Dockerfile.processor content.
 func (g *ProcessorSidecarGenerator) GenerateDockerfile(config *ProcessorSidecarConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("Dockerfile.processor.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load template: %w", err)
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
-	}
-
-	return buf.Bytes(), nil
+	return renderTemplate("Dockerfile.processor.tmpl", config)
 }
 
 // GenerateProcessScript generates the main process-files.sh script.
 func (g *ProcessorSidecarGenerator) GenerateProcessScript(config *ProcessorSidecarConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("processor/process-files.sh.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load template: %w", err)
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
-	}
-
-	return buf.Bytes(), nil
+	return renderTemplate("processor/process-files.sh.tmpl", config)
 }
 
 // GenerateImageScript generates the image processing script.
 func (g *ProcessorSidecarGenerator) GenerateImageScript(config *ProcessorSidecarConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("processor/process-image.sh.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load template: %w", err)
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
-	}
-
-	return buf.Bytes(), nil
+	return renderTemplate("processor/process-image.sh.tmpl", config)
 }
 
 // GenerateDocumentScript generates the document processing script.
 func (g *ProcessorSidecarGenerator) GenerateDocumentScript(config *ProcessorSidecarConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("processor/process-document.sh.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load template: %w", err)
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
-	}
-
-	return buf.Bytes(), nil
+	return renderTemplate("processor/process-document.sh.tmpl", config)
 }
 
 // GenerateVideoScript generates the video processing script.
 func (g *ProcessorSidecarGenerator) GenerateVideoScript(config *ProcessorSidecarConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("processor/process-video.sh.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load template: %w", err)
-	}
+	return renderTemplate("processor/process-video.sh.tmpl", config)
+}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
-	}
+// GenerateScanScript generates the scan-file.sh virus-scanning script.
+func (g *ProcessorSidecarGenerator) GenerateScanScript(config *ProcessorSidecarConfig) ([]byte, error) {
+	return renderTemplate("processor/scan-file.sh.tmpl", config)
+}
+
+// GenerateNotifyScript generates the notify.sh completion notification script.
+func (g *ProcessorSidecarGenerator) GenerateNotifyScript(config *ProcessorSidecarConfig) ([]byte, error) {
+	return renderTemplate("processor/notify.sh.tmpl", config)
+}
 
-	return buf.Bytes(), nil
+// GenerateS3BridgeScript generates the s3-bridge.sh script that syncs the pipeline with a bucket.
+func (g *ProcessorSidecarGenerator) GenerateS3BridgeScript(config *ProcessorSidecarConfig) ([]byte, error) {
+	return renderTemplate("processor/s3-bridge.sh.tmpl", config)
 }
 
 // GenerateEntrypoint generates the entrypoint.processor.sh script.
 func (g *ProcessorSidecarGenerator) GenerateEntrypoint(config *ProcessorSidecarConfig) ([]byte, error) {
-	tmpl, err := loadTemplate("entrypoint.processor.tmpl")
-	if err != nil {
-		return nil, fmt.Errorf("failed to load template: %w", err)
-	}
+	return renderTemplate("entrypoint.processor.tmpl", config)
+}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, config); err != nil {
-		return nil, fmt.Errorf("failed to execute template: %w", err)
+// Files returns the files Generate writes, relative to the project root.
+func (g *ProcessorSidecarGenerator) Files(detection *models.Detection, projectName string) []string {
+	config := processorConfig(detection, projectName)
+	files := []string{
+		".devcontainer/Dockerfile.processor",
+		".devcontainer/scripts/process-files.sh",
+		".devcontainer/scripts/notify.sh",
 	}
-
-	return buf.Bytes(), nil
+	scripts := []struct {
+		enabled bool
+		name    string
+	}{
+		{config.ProcessImages, "process-image.sh"},
+		{config.ProcessDocuments, "process-document.sh"},
+		{config.ProcessVideo, "process-video.sh"},
+		{config.S3, "s3-bridge.sh"},
+		{config.ScanEnabled, "scan-file.sh"},
+	}
+	for _, script := range scripts {
+		if script.enabled {
+			files = append(files, ".devcontainer/scripts/"+script.name)
+		}
+	}
+	return append(files, ".devcontainer/entrypoint.processor.sh", ".devcontainer/files/pending/.gitkeep")
 }
 
 // Generate writes all processor sidecar files to the target directory.
@@ -154,26 +172,19 @@ func (g *ProcessorSidecarGenerator) Generate(detection *models.Detection, projec
 	scriptsDir := filepath.Join(devcontainerDir, "scripts")
 
 	// Create directories
-	if err := os.MkdirAll(scriptsDir, 0755); err != nil {
+	if err := g.fs().MkdirAll(scriptsDir, 0755); err != nil {
 		return fmt.Errorf("failed to create scripts directory: %w", err)
 	}
 
-	// Create config based on detection
-	config := DefaultProcessorConfig()
-	config.ProjectName = projectName
-
-	// Determine processing capabilities based on detected libraries
-	// For now, enable image processing by default when file upload is detected
-	if detection.NeedsFileProcessor() {
-		config.ProcessImages = true
-	}
+	// Create config based on detection and the configured pipeline
+	config := processorConfig(detection, projectName)
 
 	// Generate Dockerfile.processor
 	dockerfile, err := g.GenerateDockerfile(config)
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(devcontainerDir, "Dockerfile.processor"), dockerfile, 0644); err != nil {
+	if err := g.fs().WriteFile(filepath.Join(devcontainerDir, "Dockerfile.processor"), dockerfile, 0644); err != nil {
 		return fmt.Errorf("failed to write Dockerfile.processor: %w", err)
 	}
 
@@ -182,17 +193,26 @@ func (g *ProcessorSidecarGenerator) Generate(detection *models.Detection, projec
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(scriptsDir, "process-files.sh"), processScript, 0755); err != nil {
+	if err := g.fs().WriteFile(filepath.Join(scriptsDir, "process-files.sh"), processScript, 0755); err != nil {
 		return fmt.Errorf("failed to write process-files.sh: %w", err)
 	}
 
+	// Generate notify.sh, which tells the app a file was processed
+	notifyScript, err := g.GenerateNotifyScript(config)
+	if err != nil {
+		return err
+	}
+	if err := g.fs().WriteFile(filepath.Join(scriptsDir, "notify.sh"), notifyScript, 0755); err != nil {
+		return fmt.Errorf("failed to write notify.sh: %w", err)
+	}
+
 	// Generate image processing script if enabled
 	if config.ProcessImages {
 		imageScript, err := g.GenerateImageScript(config)
 		if err != nil {
 			return err
 		}
-		if err := os.WriteFile(filepath.Join(scriptsDir, "process-image.sh"), imageScript, 0755); err != nil {
+		if err := g.fs().WriteFile(filepath.Join(scriptsDir, "process-image.sh"), imageScript, 0755); err != nil {
 			return fmt.Errorf("failed to write process-image.sh: %w", err)
 		}
 	}
@@ -203,7 +223,7 @@ func (g *ProcessorSidecarGenerator) Generate(detection *models.Detection, projec
 		if err != nil {
 			return err
 		}
-		if err := os.WriteFile(filepath.Join(scriptsDir, "process-document.sh"), docScript, 0755); err != nil {
+		if err := g.fs().WriteFile(filepath.Join(scriptsDir, "process-document.sh"), docScript, 0755); err != nil {
 			return fmt.Errorf("failed to write process-document.sh: %w", err)
 		}
 	}
@@ -214,37 +234,126 @@ func (g *ProcessorSidecarGenerator) Generate(detection *models.Detection, projec
 		if err != nil {
 			return err
 		}
-		if err := os.WriteFile(filepath.Join(scriptsDir, "process-video.sh"), videoScript, 0755); err != nil {
+		if err := g.fs().WriteFile(filepath.Join(scriptsDir, "process-video.sh"), videoScript, 0755); err != nil {
 			return fmt.Errorf("failed to write process-video.sh: %w", err)
 		}
 	}
 
+	// Generate the S3 bridge if uploads go through a bucket
+	if config.S3 {
+		bridgeScript, err := g.GenerateS3BridgeScript(config)
+		if err != nil {
+			return err
+		}
+		if err := g.fs().WriteFile(filepath.Join(scriptsDir, "s3-bridge.sh"), bridgeScript, 0755); err != nil {
+			return fmt.Errorf("failed to write s3-bridge.sh: %w", err)
+		}
+	}
+
+	// Generate virus scanning script if enabled
+	if config.ScanEnabled {
+		scanScript, err := g.GenerateScanScript(config)
+		if err != nil {
+			return err
+		}
+		if err := g.fs().WriteFile(filepath.Join(scriptsDir, "scan-file.sh"), scanScript, 0755); err != nil {
+			return fmt.Errorf("failed to write scan-file.sh: %w", err)
+		}
+	}
+
 	// Generate entrypoint
 	entrypoint, err := g.GenerateEntrypoint(config)
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(devcontainerDir, "entrypoint.processor.sh"), entrypoint, 0755); err != nil {
+	if err := g.fs().WriteFile(filepath.Join(devcontainerDir, "entrypoint.processor.sh"), entrypoint, 0755); err != nil {
 		return fmt.Errorf("failed to write entrypoint.processor.sh: %w", err)
 	}
 
 	// Create files directory structure
 	filesDir := filepath.Join(devcontainerDir, "files")
-	for _, dir := range []string{"pending", "processing", "processed", "failed"} {
-		if err := os.MkdirAll(filepath.Join(filesDir, dir), 0755); err != nil {
+	dirs := []string{"pending", "processing", "processed", "failed"}
+	if config.ScanEnabled {
+		dirs = append(dirs, "quarantine")
+	}
+	for _, dir := range dirs {
+		if err := g.fs().MkdirAll(filepath.Join(filesDir, dir), 0755); err != nil {
 			return fmt.Errorf("failed to create files/%s directory: %w", dir, err)
 		}
 	}
 
 	// Create .gitkeep in pending directory
 	gitkeep := filepath.Join(filesDir, "pending", ".gitkeep")
-	if err := os.WriteFile(gitkeep, []byte{}, 0644); err != nil {
+	if err := g.fs().WriteFile(gitkeep, []byte{}, 0644); err != nil {
 		return fmt.Errorf("failed to write .gitkeep: %w", err)
 	}
 
 	return nil
 }
 
+// processorConfig creates a ProcessorSidecarConfig from the defaults and the
+// pipeline settings in .dockstart.yml or --processor-* flags.
+func processorConfig(detection *models.Detection, projectName string) *ProcessorSidecarConfig {
+	config := DefaultProcessorConfig()
+	config.ProjectName = projectName
+
+	options := detection.FileProcessor
+	if len(options.Types) > 0 {
+		config.ProcessImages = containsString(options.Types, "images")
+		config.ProcessDocuments = containsString(options.Types, "documents")
+		config.ProcessVideo = containsString(options.Types, "video")
+	}
+	if len(options.ThumbnailSizes) > 0 {
+		config.ThumbnailSize = options.ThumbnailSizes[0]
+		config.ExtraThumbnailSizes = options.ThumbnailSizes[1:]
+	}
+	config.AllowedMIMETypes = options.AllowedMIMETypes
+	if options.MaxFileSize > 0 {
+		config.MaxFileSize = options.MaxFileSize
+	}
+	if options.PollInterval > 0 {
+		config.PollInterval = options.PollInterval
+	}
+	config.UseInotify = options.Watch == "inotify"
+	if detection.NonRoot {
+		config.User = models.SidecarUser
+	}
+	config.ScanEnabled = options.Scan
+	config.SanitizeImages = options.SanitizeImages
+
+	if detection.UsesS3Uploads() {
+		config.S3 = true
+		config.S3Endpoint, _, _ = s3UploadEndpoint(detection)
+		config.S3Bucket = awsResourceName(projectName)
+	}
+
+	switch options.Notify {
+	case "webhook":
+		config.NotifyMethod = "webhook"
+		config.NotifyURL = options.NotifyURL
+		if config.NotifyURL == "" {
+			config.NotifyURL = fmt.Sprintf("http://app:%d/webhooks/file-processed", detection.GetAppPort())
+		}
+	case "queue":
+		config.NotifyMethod = "queue"
+		config.NotifyQueue = options.NotifyQueue
+		if config.NotifyQueue == "" {
+			config.NotifyQueue = "file-processed"
+		}
+	}
+
+	return config
+}
+
+// s3UploadEndpoint returns the S3 endpoint and credentials for S3 uploads:
+// LocalStack when it already emulates S3, otherwise the MinIO sidecar.
+func s3UploadEndpoint(detection *models.Detection) (endpoint, accessKey, secretKey string) {
+	if detection.NeedsMinIO() {
+		return "http://minio:9000", "minioadmin", "minioadmin"
+	}
+	return "http://localstack:4566", "test", "test"
+}
+
 // ShouldGenerate checks if processor sidecar should be generated based on detection.
 func (g *ProcessorSidecarGenerator) ShouldGenerate(detection *models.Detection) bool {
 	return detection.NeedsFileProcessor()
@@ -3,6 +3,8 @@ package generator
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 
@@ -418,6 +420,7 @@ func TestProcessorShellScriptsAreValid(t *testing.T) {
 		ProcessDocuments: true,
 		ProcessVideo:     true,
 		UseInotify:       true,
+		ScanEnabled:      true,
 	}
 
 	scripts := []struct {
@@ -428,6 +431,9 @@ func TestProcessorShellScriptsAreValid(t *testing.T) {
 		{"process-image.sh", g.GenerateImageScript},
 		{"process-document.sh", g.GenerateDocumentScript},
 		{"process-video.sh", g.GenerateVideoScript},
+		{"scan-file.sh", g.GenerateScanScript},
+		{"notify.sh", g.GenerateNotifyScript},
+		{"s3-bridge.sh", g.GenerateS3BridgeScript},
 		{"entrypoint.processor.sh", g.GenerateEntrypoint},
 	}
 
@@ -478,10 +484,12 @@ func TestEndToEndProcessorGeneration(t *testing.T) {
 
 	// Create detection with file upload library
 	detection := &models.Detection{
-		Language:            "node",
-		Version:             "20",
-		FileUploadLibraries: []string{"multer"},
-		UploadPath:          "uploads",
+		Language: "node",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"multer"},
+		},
+		UploadPath: "uploads",
 	}
 
 	g := NewProcessorSidecarGenerator()
@@ -502,6 +510,7 @@ func TestEndToEndProcessorGeneration(t *testing.T) {
 		".devcontainer/entrypoint.processor.sh",
 		".devcontainer/scripts/process-files.sh",
 		".devcontainer/scripts/process-image.sh",
+		".devcontainer/scripts/notify.sh",
 		".devcontainer/files/pending/.gitkeep",
 	}
 
@@ -562,28 +571,36 @@ func TestShouldGenerate(t *testing.T) {
 		{
 			name: "with file upload library",
 			detection: &models.Detection{
-				FileUploadLibraries: []string{"multer"},
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: []string{"multer"},
+				},
 			},
 			want: true,
 		},
 		{
 			name: "with multiple upload libraries",
 			detection: &models.Detection{
-				FileUploadLibraries: []string{"multer", "formidable"},
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: []string{"multer", "formidable"},
+				},
 			},
 			want: true,
 		},
 		{
 			name: "no file upload libraries",
 			detection: &models.Detection{
-				FileUploadLibraries: nil,
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: nil,
+				},
 			},
 			want: false,
 		},
 		{
 			name: "empty file upload libraries",
 			detection: &models.Detection{
-				FileUploadLibraries: []string{},
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: []string{},
+				},
 			},
 			want: false,
 		},
@@ -627,3 +644,377 @@ func TestDefaultProcessorConfig(t *testing.T) {
 		t.Errorf("Default ThumbnailSize should be 200x200, got %s", config.ThumbnailSize)
 	}
 }
+
+// TestProcessorConfig_Options tests that .dockstart.yml pipeline settings override the defaults.
+func TestProcessorConfig_Options(t *testing.T) {
+	detection := &models.Detection{
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"multer"},
+		},
+		FileProcessor: models.FileProcessorOptions{
+			Types:            []string{"documents", "video"},
+			ThumbnailSizes:   []string{"320x240", "1024x768"},
+			AllowedMIMETypes: []string{"application/pdf", "video/*"},
+			MaxFileSize:      10485760,
+			Watch:            "inotify",
+			PollInterval:     30,
+		},
+	}
+
+	config := processorConfig(detection, "upload-app")
+
+	if config.ProcessImages || !config.ProcessDocuments || !config.ProcessVideo {
+		t.Errorf("types = images:%v documents:%v video:%v, want documents and video only",
+			config.ProcessImages, config.ProcessDocuments, config.ProcessVideo)
+	}
+	if config.ThumbnailSize != "320x240" {
+		t.Errorf("ThumbnailSize = %q, want 320x240", config.ThumbnailSize)
+	}
+	if len(config.ExtraThumbnailSizes) != 1 || config.ExtraThumbnailSizes[0] != "1024x768" {
+		t.Errorf("ExtraThumbnailSizes = %v, want [1024x768]", config.ExtraThumbnailSizes)
+	}
+	if config.MaxFileSize != 10485760 {
+		t.Errorf("MaxFileSize = %d, want 10485760", config.MaxFileSize)
+	}
+	if config.PollInterval != 30 {
+		t.Errorf("PollInterval = %d, want 30", config.PollInterval)
+	}
+	if !config.UseInotify {
+		t.Error("UseInotify should be true when watch is inotify")
+	}
+}
+
+// TestProcessorConfig_Defaults tests that an empty file_processor section keeps the defaults.
+func TestProcessorConfig_Defaults(t *testing.T) {
+	config := processorConfig(&models.Detection{Capabilities: models.Capabilities{FileUploadLibraries: []string{"multer"}}}, "upload-app")
+	defaults := DefaultProcessorConfig()
+
+	if config.ProcessImages != defaults.ProcessImages || config.ThumbnailSize != defaults.ThumbnailSize ||
+		config.MaxFileSize != defaults.MaxFileSize || config.UseInotify != defaults.UseInotify {
+		t.Errorf("processorConfig() = %+v, want defaults %+v", config, defaults)
+	}
+}
+
+// TestProcessorScripts_PipelineSettings tests that MIME filtering and extra thumbnails are rendered.
+func TestProcessorScripts_PipelineSettings(t *testing.T) {
+	gen := NewProcessorSidecarGenerator()
+	config := DefaultProcessorConfig()
+	config.ThumbnailSize = "320x240"
+	config.ExtraThumbnailSizes = []string{"1024x768"}
+	config.AllowedMIMETypes = []string{"image/*", "application/pdf"}
+
+	script, err := gen.GenerateProcessScript(config)
+	if err != nil {
+		t.Fatalf("GenerateProcessScript() error = %v", err)
+	}
+	for _, want := range []string{"image/* application/pdf", "is_allowed_type"} {
+		if !strings.Contains(string(script), want) {
+			t.Errorf("process-files.sh should contain %q", want)
+		}
+	}
+
+	image, err := gen.GenerateImageScript(config)
+	if err != nil {
+		t.Fatalf("GenerateImageScript() error = %v", err)
+	}
+	for _, want := range []string{"320x240", "1024x768", "EXTRA_THUMBNAIL_SIZES"} {
+		if !strings.Contains(string(image), want) {
+			t.Errorf("process-image.sh should contain %q", want)
+		}
+	}
+}
+
+// TestProcessorVirusScan tests the optional ClamAV scanning stage.
+func TestProcessorVirusScan(t *testing.T) {
+	g := NewProcessorSidecarGenerator()
+	config := DefaultProcessorConfig()
+	config.ScanEnabled = true
+
+	dockerfile, err := g.GenerateDockerfile(config)
+	if err != nil {
+		t.Fatalf("GenerateDockerfile() error = %v", err)
+	}
+	for _, want := range []string{"clamav", "freshclam", "/files/quarantine", "COPY scripts/scan-file.sh"} {
+		if !strings.Contains(string(dockerfile), want) {
+			t.Errorf("Dockerfile.processor should contain %q", want)
+		}
+	}
+
+	script, err := g.GenerateProcessScript(config)
+	if err != nil {
+		t.Fatalf("GenerateProcessScript() error = %v", err)
+	}
+	for _, want := range []string{`SCAN_ENABLED="${SCAN_ENABLED:-true}"`, "/usr/local/bin/scan-file.sh", `"quarantined"`} {
+		if !strings.Contains(string(script), want) {
+			t.Errorf("process-files.sh should contain %q", want)
+		}
+	}
+
+	scan, err := g.GenerateScanScript(config)
+	if err != nil {
+		t.Fatalf("GenerateScanScript() error = %v", err)
+	}
+	for _, want := range []string{"clamscan", "QUARANTINE_DIR", ".threat"} {
+		if !strings.Contains(string(scan), want) {
+			t.Errorf("scan-file.sh should contain %q", want)
+		}
+	}
+
+	// Scanning is off by default
+	dockerfile, err = g.GenerateDockerfile(DefaultProcessorConfig())
+	if err != nil {
+		t.Fatalf("GenerateDockerfile() error = %v", err)
+	}
+	if strings.Contains(string(dockerfile), "clamav") {
+		t.Error("Dockerfile.processor should not install clamav unless scanning is enabled")
+	}
+}
+
+// TestProcessorVirusScan_Generate tests that scan-file.sh and quarantine/ are written when scanning is enabled.
+func TestProcessorVirusScan_Generate(t *testing.T) {
+	tmpDir := t.TempDir()
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"multer"},
+		},
+		FileProcessor: models.FileProcessorOptions{Scan: true},
+	}
+
+	if err := NewProcessorSidecarGenerator().Generate(detection, tmpDir, "test-app"); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	for _, path := range []string{".devcontainer/scripts/scan-file.sh", ".devcontainer/files/quarantine"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, path)); err != nil {
+			t.Errorf("Expected %s to be created: %v", path, err)
+		}
+	}
+}
+
+// TestProcessorSanitizeImages tests the EXIF stripping and safe-format conversion option.
+func TestProcessorSanitizeImages(t *testing.T) {
+	g := NewProcessorSidecarGenerator()
+	config := DefaultProcessorConfig()
+	config.SanitizeImages = true
+
+	image, err := g.GenerateImageScript(config)
+	if err != nil {
+		t.Fatalf("GenerateImageScript() error = %v", err)
+	}
+	for _, want := range []string{
+		`SANITIZE_IMAGES="${SANITIZE_IMAGES:-true}"`,
+		`SAFE_IMAGE_FORMATS="${SAFE_IMAGE_FORMATS:-jpg jpeg png webp}"`,
+		"-auto-orient -strip",
+		"exiftool -all=",
+	} {
+		if !strings.Contains(string(image), want) {
+			t.Errorf("process-image.sh should contain %q", want)
+		}
+	}
+
+	script, err := g.GenerateProcessScript(config)
+	if err != nil {
+		t.Fatalf("GenerateProcessScript() error = %v", err)
+	}
+	if !strings.Contains(string(script), "image/*)") {
+		t.Error("process-files.sh should send every image type to process-image.sh when sanitizing")
+	}
+
+	dockerfile, err := g.GenerateDockerfile(config)
+	if err != nil {
+		t.Fatalf("GenerateDockerfile() error = %v", err)
+	}
+	if !strings.Contains(string(dockerfile), "apk add --no-cache exiftool") {
+		t.Error("Dockerfile.processor should install exiftool when sanitizing")
+	}
+
+	// Sanitizing is off by default
+	image, err = g.GenerateImageScript(DefaultProcessorConfig())
+	if err != nil {
+		t.Fatalf("GenerateImageScript() error = %v", err)
+	}
+	if !strings.Contains(string(image), `SANITIZE_IMAGES="${SANITIZE_IMAGES:-false}"`) {
+		t.Error("process-image.sh should not sanitize by default")
+	}
+}
+
+// TestProcessorNotify tests completion notifications to the app.
+func TestProcessorNotify(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    models.FileProcessorOptions
+		wantMethod string
+		wantURL    string
+		wantQueue  string
+		wantParts  []string
+	}{
+		{
+			name:       "file by default",
+			wantMethod: "file",
+			wantParts:  []string{`NOTIFY_METHOD="${NOTIFY_METHOD:-file}"`},
+		},
+		{
+			name:       "webhook to the app by default",
+			options:    models.FileProcessorOptions{Notify: "webhook"},
+			wantMethod: "webhook",
+			wantURL:    "http://app:3000/webhooks/file-processed",
+			wantParts:  []string{"http://app:3000/webhooks/file-processed", "curl -sS --fail"},
+		},
+		{
+			name:       "webhook to a custom url",
+			options:    models.FileProcessorOptions{Notify: "webhook", NotifyURL: "http://app:3000/api/uploads/done"},
+			wantMethod: "webhook",
+			wantURL:    "http://app:3000/api/uploads/done",
+		},
+		{
+			name:       "queue with a custom list",
+			options:    models.FileProcessorOptions{Notify: "queue", NotifyQueue: "uploads:done"},
+			wantMethod: "queue",
+			wantQueue:  "uploads:done",
+			wantParts:  []string{`NOTIFY_QUEUE="${NOTIFY_QUEUE:-uploads:done}"`, "LPUSH"},
+		},
+	}
+
+	g := NewProcessorSidecarGenerator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detection := &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					FileUploadLibraries: []string{"multer"},
+				},
+				FileProcessor: tt.options,
+			}
+			config := processorConfig(detection, "upload-app")
+
+			if config.NotifyMethod != tt.wantMethod {
+				t.Errorf("NotifyMethod = %q, want %q", config.NotifyMethod, tt.wantMethod)
+			}
+			if config.NotifyURL != tt.wantURL {
+				t.Errorf("NotifyURL = %q, want %q", config.NotifyURL, tt.wantURL)
+			}
+			if config.NotifyQueue != tt.wantQueue {
+				t.Errorf("NotifyQueue = %q, want %q", config.NotifyQueue, tt.wantQueue)
+			}
+
+			script, err := g.GenerateNotifyScript(config)
+			if err != nil {
+				t.Fatalf("GenerateNotifyScript() error = %v", err)
+			}
+			for _, want := range tt.wantParts {
+				if !strings.Contains(string(script), want) {
+					t.Errorf("notify.sh should contain %q", want)
+				}
+			}
+		})
+	}
+}
+
+// TestProcessorNotify_Dockerfile tests that the notification client is installed.
+func TestProcessorNotify_Dockerfile(t *testing.T) {
+	g := NewProcessorSidecarGenerator()
+
+	for method, want := range map[string]string{"webhook": "apk add --no-cache curl", "queue": "apk add --no-cache redis"} {
+		config := DefaultProcessorConfig()
+		config.NotifyMethod = method
+
+		dockerfile, err := g.GenerateDockerfile(config)
+		if err != nil {
+			t.Fatalf("GenerateDockerfile() error = %v", err)
+		}
+		if !strings.Contains(string(dockerfile), want) {
+			t.Errorf("Dockerfile.processor for %s notifications should contain %q", method, want)
+		}
+		if !strings.Contains(string(dockerfile), "COPY scripts/notify.sh") {
+			t.Error("Dockerfile.processor should copy notify.sh")
+		}
+	}
+}
+
+func TestProcessorNonRoot(t *testing.T) {
+	g := NewProcessorSidecarGenerator()
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"multer"},
+		},
+		FileProcessor: models.FileProcessorOptions{Scan: true, Storage: "s3"},
+		NonRoot:       true,
+	}
+	config := processorConfig(detection, "upload-app")
+	if config.User != models.SidecarUser {
+		t.Fatalf("User = %q, want %q", config.User, models.SidecarUser)
+	}
+
+	dockerfile, err := g.GenerateDockerfile(config)
+	if err != nil {
+		t.Fatalf("GenerateDockerfile() error = %v", err)
+	}
+	for _, want := range []string{
+		"ARG USER_UID=1000\nARG USER_GID=1000\nRUN apk add --no-cache su-exec",
+		`adduser -D -u "$USER_UID" -G "$group" dockstart`,
+	} {
+		if !strings.Contains(string(dockerfile), want) {
+			t.Errorf("Dockerfile.processor should contain %q:\n%s", want, dockerfile)
+		}
+	}
+	if strings.Contains(string(dockerfile), "\nUSER ") {
+		t.Error("Dockerfile.processor should start as root so the entrypoint can chown the volume")
+	}
+
+	entrypoint, err := g.GenerateEntrypoint(config)
+	if err != nil {
+		t.Fatalf("GenerateEntrypoint() error = %v", err)
+	}
+	for _, want := range []string{
+		`chown -R "$(id -u dockstart):$(id -g dockstart)"`,
+		`"${QUARANTINE_PATH:-/files/quarantine}"`,
+		"$RUN_AS /usr/local/bin/s3-bridge.sh &",
+		`exec $RUN_AS "$@"`,
+	} {
+		if !strings.Contains(string(entrypoint), want) {
+			t.Errorf("entrypoint.processor.sh should contain %q:\n%s", want, entrypoint)
+		}
+	}
+
+	// Root by default
+	config = processorConfig(&models.Detection{Capabilities: models.Capabilities{FileUploadLibraries: []string{"multer"}}}, "upload-app")
+	entrypoint, err = g.GenerateEntrypoint(config)
+	if err != nil {
+		t.Fatalf("GenerateEntrypoint() error = %v", err)
+	}
+	if strings.Contains(string(entrypoint), "su-exec") {
+		t.Error("entrypoint.processor.sh should not drop privileges by default")
+	}
+}
+
+// TestProcessorSidecarGenerator_Files tests that Files lists exactly the files Generate writes.
+func TestProcessorSidecarGenerator_Files(t *testing.T) {
+	tmpDir := t.TempDir()
+	detection := &models.Detection{
+		Language: "node",
+		Capabilities: models.Capabilities{
+			FileUploadLibraries: []string{"multer"},
+		},
+		FileProcessor: models.FileProcessorOptions{Scan: true},
+	}
+
+	g := NewProcessorSidecarGenerator()
+	if err := g.Generate(detection, tmpDir, "shop"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	files := g.Files(detection, "shop")
+	sort.Strings(files)
+	if written := writtenFiles(t, tmpDir); !reflect.DeepEqual(files, written) {
+		t.Errorf("Files() = %v, want the written files %v", files, written)
+	}
+	if !strings.Contains(strings.Join(files, " "), "scan-file.sh") {
+		t.Errorf("Files() = %v, want scan-file.sh with scanning enabled", files)
+	}
+}
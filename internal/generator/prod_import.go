@@ -0,0 +1,187 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// ProdImportScript imports an anonymized copy of the production database,
+// relative to the project root.
+const ProdImportScript = ".devcontainer/scripts/import-prod.sh"
+
+// DefaultProdImportSource is the host environment variable holding the
+// production database URL, unless postgres.prod_import.source_env names another.
+const DefaultProdImportSource = "PROD_DATABASE_URL"
+
+// anonymizeExpressions are the SQL expressions each strategy rewrites a column
+// with, {column} standing for the quoted column. They keep NULLs, and derive
+// the fake values from the real ones so equal values stay equal across tables.
+var anonymizeExpressions = map[string]string{
+	"email":  `'user_' || left(md5({column}::text), 12) || '@example.com'`,
+	"name":   `'Person ' || left(md5({column}::text), 8)`,
+	"phone":  `'+1555' || lpad((abs(hashtext({column}::text)) % 10000000)::text, 7, '0')`,
+	"redact": `CASE WHEN {column} IS NULL THEN NULL ELSE 'redacted' END`,
+	"hash":   `md5({column}::text)`,
+	"clear":  `NULL`,
+}
+
+// ProdImportConfig holds the configuration for generating import-prod.sh.
+type ProdImportConfig struct {
+	// Database is the development database the anonymized copy replaces
+	Database string
+
+	// SourceEnv is the host environment variable holding the production database URL
+	SourceEnv string
+
+	// Tables are the UPDATE statements run on the copy, one per table
+	Tables []AnonymizedTable
+
+	// ExcludeData are the tables copied without their rows
+	ExcludeData []string
+}
+
+// AnonymizedTable is a table whose personal data is rewritten.
+type AnonymizedTable struct {
+	// Name is the quoted, possibly schema-qualified, table name
+	Name string
+
+	// Columns are the quoted columns and the expressions they are set to
+	Columns []AnonymizedColumnSQL
+}
+
+// AnonymizedColumnSQL is a column and the SQL expression rewriting it.
+type AnonymizedColumnSQL struct {
+	Name       string
+	Expression string
+}
+
+// ProdImportComposeConfig holds configuration for the prod-import service,
+// which "docker compose up" leaves out until the tools profile is enabled.
+type ProdImportComposeConfig struct {
+	// Enabled indicates whether the prod-import service is generated
+	Enabled bool
+
+	// Image runs the PostgreSQL version of the stack, for its pg_dump and pg_restore
+	Image string
+
+	// Postgres is the PostgreSQL service the copy is restored into
+	Postgres string
+
+	// PostgresHealthy waits for the generated PostgreSQL's healthcheck;
+	// imported services may not define one
+	PostgresHealthy bool
+
+	// SourceEnv is the host environment variable holding the production database URL
+	SourceEnv string
+}
+
+// prodImportConfig returns the prod-import service settings when the import is
+// enabled in .dockstart.yml. The service runs the image of the stack's
+// PostgreSQL, imported or generated, and connects to it directly.
+func prodImportConfig(config *ComposeConfig, detection *models.Detection) ProdImportComposeConfig {
+	if detection.ProdImport == nil {
+		return ProdImportComposeConfig{}
+	}
+	for _, service := range config.Services {
+		if service.Name == "postgres" {
+			return ProdImportComposeConfig{
+				Enabled:         true,
+				Image:           fmt.Sprintf("postgres:%s-alpine", service.Version),
+				Postgres:        service.ComposeName(),
+				PostgresHealthy: service.Existing == "",
+				SourceEnv:       detection.ProdImport.SourceEnv,
+			}
+		}
+	}
+	return ProdImportComposeConfig{}
+}
+
+// ProdImportGenerator generates import-prod.sh, which copies the production
+// database into a staging database, anonymizes it, and swaps it in for the
+// development database.
+type ProdImportGenerator struct {
+	output
+}
+
+// NewProdImportGenerator creates a new production import script generator.
+func NewProdImportGenerator() *ProdImportGenerator {
+	return &ProdImportGenerator{}
+}
+
+// ShouldGenerate returns true if the import is enabled in .dockstart.yml and
+// the stack runs PostgreSQL.
+func (g *ProdImportGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.ProdImport != nil && detection.NeedsCompose() &&
+		(detection.HasService("postgres") || detection.GetVectorStore() == "pgvector")
+}
+
+// Generate creates .devcontainer/scripts/import-prod.sh.
+func (g *ProdImportGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	content, err := g.GenerateContent(detection, projectName)
+	if err != nil {
+		return err
+	}
+
+	scriptsDir := filepath.Join(projectPath, ".devcontainer", "scripts")
+	if err := g.fs().MkdirAll(scriptsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create scripts directory: %w", err)
+	}
+	if err := g.fs().WriteFile(filepath.Join(scriptsDir, "import-prod.sh"), content, 0755); err != nil {
+		return fmt.Errorf("failed to write import-prod.sh: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateContent returns the import-prod.sh content without writing to disk.
+func (g *ProdImportGenerator) GenerateContent(detection *models.Detection, projectName string) ([]byte, error) {
+	options := detection.ProdImport
+	if options == nil {
+		options = &models.ProdImportOptions{SourceEnv: DefaultProdImportSource}
+	}
+	compose, err := NewComposeGenerator().config(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ProdImportConfig{
+		Database:    compose.Postgres.Database,
+		SourceEnv:   options.SourceEnv,
+		Tables:      anonymizedTables(options.Anonymize),
+		ExcludeData: options.ExcludeData,
+	}
+	return renderTemplate("postgres/import-prod.sh.tmpl", config)
+}
+
+// anonymizedTables groups the anonymized columns by table, keeping the order
+// they are listed in.
+func anonymizedTables(columns []models.AnonymizedColumn) []AnonymizedTable {
+	var tables []AnonymizedTable
+	index := make(map[string]int)
+	for _, column := range columns {
+		name := quoteIdent(column.Table)
+		if column.Schema != "" {
+			name = quoteIdent(column.Schema) + "." + name
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(tables)
+			index[name] = i
+			tables = append(tables, AnonymizedTable{Name: name})
+		}
+		quoted := quoteIdent(column.Column)
+		tables[i].Columns = append(tables[i].Columns, AnonymizedColumnSQL{
+			Name:       quoted,
+			Expression: strings.ReplaceAll(anonymizeExpressions[column.Strategy], "{column}", quoted),
+		})
+	}
+	return tables
+}
+
+// quoteIdent quotes a PostgreSQL identifier.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,114 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestProdImportGenerator tests the anonymized production import script.
+func TestProdImportGenerator(t *testing.T) {
+	gen := NewProdImportGenerator()
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Services: []string{"postgres"},
+		ProdImport: &models.ProdImportOptions{
+			SourceEnv: "PROD_DATABASE_URL",
+			Anonymize: []models.AnonymizedColumn{
+				{Schema: "billing", Table: "cards", Column: "holder", Strategy: "name"},
+				{Table: "users", Column: "email", Strategy: "email"},
+				{Table: "users", Column: "phone", Strategy: "phone"},
+			},
+			ExcludeData: []string{"audit_*"},
+		},
+	}
+	if !gen.ShouldGenerate(detection) {
+		t.Fatal("ShouldGenerate() = false, want true when enabled")
+	}
+	if gen.ShouldGenerate(&models.Detection{Language: "node", Version: "20", Services: []string{"postgres"}}) {
+		t.Error("ShouldGenerate() = true, want false unless enabled in .dockstart.yml")
+	}
+
+	tmpDir := t.TempDir()
+	if err := gen.Generate(detection, tmpDir, "shop"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	script := readScript(t, filepath.Join(tmpDir, filepath.FromSlash(ProdImportScript)))
+	for _, want := range []string{
+		`TARGET="shop_dev"`,
+		`--exclude-table-data='audit_*'`,
+		"UPDATE \"billing\".\"cards\" SET\n    \"holder\" = 'Person ' || left(md5(\"holder\"::text), 8);",
+		"UPDATE \"users\" SET\n    \"email\" = 'user_' || left(md5(\"email\"::text), 12) || '@example.com',\n    \"phone\" = ",
+		`sql "ALTER DATABASE \"${STAGING}\" RENAME TO \"${TARGET}\""`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("import-prod.sh should contain %q, got:\n%s", want, script)
+		}
+	}
+	if strings.Contains(script, "nothing was anonymized") {
+		t.Error("import-prod.sh should not warn when columns are anonymized")
+	}
+
+	// The staging database is dropped on failure, before it can replace the development one
+	if trap, rename := strings.Index(script, "trap drop_staging EXIT"), strings.Index(script, "RENAME TO"); trap < 0 || trap > rename {
+		t.Error("import-prod.sh should drop the staging database on failure")
+	}
+}
+
+// TestProdImportGenerator_NothingAnonymized tests that a script without
+// anonymized columns warns instead of running an empty UPDATE.
+func TestProdImportGenerator_NothingAnonymized(t *testing.T) {
+	detection := &models.Detection{
+		Language:   "go",
+		Version:    "1.23",
+		Services:   []string{"postgres"},
+		ProdImport: &models.ProdImportOptions{SourceEnv: "PROD_DATABASE_URL"},
+	}
+	content, err := NewProdImportGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if strings.Contains(string(content), "UPDATE") || !strings.Contains(string(content), "nothing was anonymized") {
+		t.Errorf("import-prod.sh should only warn, got:\n%s", content)
+	}
+}
+
+// TestComposeGenerator_ProdImport tests the prod-import service in the tools profile.
+func TestComposeGenerator_ProdImport(t *testing.T) {
+	detection := &models.Detection{
+		Language:        "node",
+		Version:         "20",
+		Services:        []string{"postgres"},
+		ServiceVersions: map[string]string{"postgres": "15"},
+		ProdImport:      &models.ProdImportOptions{SourceEnv: "REPLICA_URL"},
+	}
+	content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+	for _, want := range []string{
+		"  prod-import:\n    image: postgres:15-alpine\n    profiles: [\"tools\"]",
+		"- SOURCE_DATABASE_URL=${REPLICA_URL:-}",
+		"- PGHOST=postgres",
+		"- PGPASSWORD=${POSTGRES_PASSWORD}",
+		"- ./scripts/import-prod.sh:/import-prod.sh:ro",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+		}
+	}
+
+	detection.ProdImport = nil
+	content, err = NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if strings.Contains(string(content), "prod-import") {
+		t.Error("docker-compose.yml should not contain prod-import unless enabled")
+	}
+}
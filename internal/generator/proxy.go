@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// localHosts are always reached without the proxy.
+var localHosts = []string{"localhost", "127.0.0.1"}
+
+// noProxy returns the NO_PROXY list: the local addresses, the configured
+// entries, and the given compose service names, so traffic between the
+// containers doesn't go through the proxy.
+func noProxy(proxy models.ProxyOptions, services []string) string {
+	hosts := slices.Clone(localHosts)
+	for _, host := range append(slices.Clone(proxy.NoProxy), services...) {
+		if !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	return strings.Join(hosts, ",")
+}
+
+// proxyEnv returns the proxy variables in upper and lower case, since tools
+// read one or the other (curl only reads the lowercase http_proxy). The proxy
+// URLs are wrapped with wrap, such as for compose to prefer the host's own.
+func proxyEnv(proxy models.ProxyOptions, noProxy string, wrap func(name, value string) string) []EnvVar {
+	var env []EnvVar
+	for _, v := range []EnvVar{{Name: "HTTP_PROXY", Value: proxy.HTTP}, {Name: "HTTPS_PROXY", Value: proxy.HTTPS}} {
+		if v.Value != "" {
+			value := wrap(v.Name, v.Value)
+			env = append(env, EnvVar{Name: v.Name, Value: value}, EnvVar{Name: strings.ToLower(v.Name), Value: value})
+		}
+	}
+	return append(env, EnvVar{Name: "NO_PROXY", Value: noProxy}, EnvVar{Name: "no_proxy", Value: noProxy})
+}
+
+// hostProxy lets compose take a proxy URL from the host's environment, which
+// may add the credentials kept out of .dockstart.yml.
+func hostProxy(name, value string) string {
+	return fmt.Sprintf("${%s:-%s}", name, value)
+}
+
+// addProxy routes the generated compose services through the proxy: their
+// environment gets the proxy variables, and their builds get them as build
+// args, which Docker predefines for every Dockerfile. The services in skip
+// (imported from the project's compose file) are left as is.
+func addProxy(services *yaml.Node, proxy models.ProxyOptions, skip []string) {
+	if !proxy.Enabled() {
+		return
+	}
+
+	var names []string
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		names = append(names, services.Content[i].Value)
+	}
+	env := proxyEnv(proxy, noProxy(proxy, names), hostProxy)
+
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		name, service := services.Content[i].Value, services.Content[i+1]
+		if service.Kind != yaml.MappingNode || slices.Contains(skip, name) {
+			continue
+		}
+		addEnv(service, "environment", env)
+
+		build := mappingValue(service, "build")
+		if build == nil {
+			continue
+		}
+		if build.Kind == yaml.ScalarNode {
+			context := *build
+			*build = yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+				{Kind: yaml.ScalarNode, Value: "context"}, &context,
+			}}
+		}
+		var args []EnvVar
+		for _, v := range env {
+			if v.Name == strings.ToUpper(v.Name) {
+				args = append(args, v)
+			}
+		}
+		addEnv(build, "args", args)
+	}
+}
+
+// hasEnv returns true if an environment list or mapping sets a variable.
+func hasEnv(env *yaml.Node, name string) bool {
+	if env.Kind == yaml.MappingNode {
+		return mappingValue(env, name) != nil
+	}
+	return slices.ContainsFunc(env.Content, func(item *yaml.Node) bool {
+		key, _, _ := strings.Cut(item.Value, "=")
+		return key == name
+	})
+}
+
+// addEnv adds variables to the environment list or mapping under key,
+// creating a list when there is none. Variables already set are kept.
+func addEnv(mapping *yaml.Node, key string, env []EnvVar) {
+	list := mappingValue(mapping, key)
+	if list == nil {
+		list = &yaml.Node{Kind: yaml.SequenceNode}
+		setValue(mapping, key, list)
+	}
+
+	for _, v := range env {
+		switch {
+		case hasEnv(list, v.Name):
+		case list.Kind == yaml.SequenceNode:
+			list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: v.Name + "=" + v.Value})
+		case list.Kind == yaml.MappingNode:
+			list.Content = append(list.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: v.Name},
+				&yaml.Node{Kind: yaml.ScalarNode, Value: v.Value})
+		}
+	}
+}
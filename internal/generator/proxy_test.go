@@ -0,0 +1,170 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// composeEnv returns a service's environment and build args from a rendered
+// docker-compose.yml, whether they are lists or mappings.
+func composeEnv(t *testing.T, content []byte, service string) (env, args map[string]string) {
+	t.Helper()
+	var compose struct {
+		Services map[string]struct {
+			Environment yaml.Node `yaml:"environment"`
+			Build       struct {
+				Args yaml.Node `yaml:"args"`
+			} `yaml:"build"`
+		} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(content, &compose); err != nil {
+		t.Fatalf("docker-compose.yml is not valid YAML: %v", err)
+	}
+	s, ok := compose.Services[service]
+	if !ok {
+		t.Fatalf("docker-compose.yml has no %s service", service)
+	}
+	return envMap(&s.Environment), envMap(&s.Build.Args)
+}
+
+func envMap(node *yaml.Node) map[string]string {
+	env := make(map[string]string)
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			env[node.Content[i].Value] = node.Content[i+1].Value
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			name, value, _ := strings.Cut(item.Value, "=")
+			env[name] = value
+		}
+	}
+	return env
+}
+
+// TestComposeGenerator_Proxy tests the proxy variables and build args added
+// to the generated services.
+func TestComposeGenerator_Proxy(t *testing.T) {
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Services: []string{"postgres", "redis"},
+		Proxy: models.ProxyOptions{
+			HTTP:    "http://proxy.acme.dev:3128",
+			HTTPS:   "http://proxy.acme.dev:3128",
+			NoProxy: []string{".acme.dev"},
+		},
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	env, args := composeEnv(t, content, "app")
+	if got, want := env["HTTP_PROXY"], "${HTTP_PROXY:-http://proxy.acme.dev:3128}"; got != want {
+		t.Errorf("app HTTP_PROXY = %q, want %q", got, want)
+	}
+	if got, want := env["https_proxy"], "${HTTPS_PROXY:-http://proxy.acme.dev:3128}"; got != want {
+		t.Errorf("app https_proxy = %q, want %q", got, want)
+	}
+	if env["no_proxy"] != env["NO_PROXY"] {
+		t.Errorf("app no_proxy = %q, want NO_PROXY %q", env["no_proxy"], env["NO_PROXY"])
+	}
+	for _, host := range []string{"localhost", ".acme.dev", "app", "postgres", "redis"} {
+		if !strings.Contains(","+env["NO_PROXY"]+",", ","+host+",") {
+			t.Errorf("app NO_PROXY = %q, want it to contain %s", env["NO_PROXY"], host)
+		}
+	}
+	// Docker predefines the uppercase names only as build args
+	if args["HTTP_PROXY"] != env["HTTP_PROXY"] || args["NO_PROXY"] != env["NO_PROXY"] {
+		t.Errorf("app build args = %v, want the proxy variables", args)
+	}
+	if _, ok := args["http_proxy"]; ok {
+		t.Errorf("app build args should not set http_proxy, got %v", args)
+	}
+
+	// The backing services get the variables too, in their own environment form
+	env, _ = composeEnv(t, content, "postgres")
+	if env["HTTP_PROXY"] == "" || env["POSTGRES_DB"] == "" {
+		t.Errorf("postgres environment = %v, want the proxy variables kept beside its own", env)
+	}
+}
+
+// TestComposeGenerator_ProxyImported tests that services imported from the
+// project's compose file are left as is.
+func TestComposeGenerator_ProxyImported(t *testing.T) {
+	detection := &models.Detection{
+		Language: "go",
+		Version:  "1.23",
+		Services: []string{"memcached"},
+		Proxy:    models.ProxyOptions{HTTP: "http://proxy.acme.dev:3128"},
+		ExistingCompose: &models.ExistingCompose{
+			File: "docker-compose.yml",
+			Services: []models.ExistingService{{
+				Name:       "cache",
+				Role:       "memcached",
+				Definition: map[string]interface{}{"image": "memcached:1.6"},
+			}},
+		},
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if env, _ := composeEnv(t, content, "cache"); len(env) != 0 {
+		t.Errorf("imported service environment = %v, want it unchanged", env)
+	}
+	if env, _ := composeEnv(t, content, "app"); !strings.Contains(env["NO_PROXY"], "cache") {
+		t.Errorf("app NO_PROXY = %q, want it to contain the imported service", env["NO_PROXY"])
+	}
+}
+
+// TestDevcontainerGenerator_Proxy tests the proxy variables in devcontainer.json
+// for a project without compose, and that nothing changes without a proxy.
+func TestDevcontainerGenerator_Proxy(t *testing.T) {
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Proxy:    models.ProxyOptions{HTTPS: "http://proxy.acme.dev:3128"},
+	}
+
+	content, err := NewDevcontainerGenerator().GenerateContent(detection, "api")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	var devcontainer struct {
+		RemoteEnv map[string]string `json:"remoteEnv"`
+	}
+	if err := json.Unmarshal(content, &devcontainer); err != nil {
+		t.Fatalf("devcontainer.json is not valid JSON: %v", err)
+	}
+	want := map[string]string{
+		"HTTPS_PROXY": "http://proxy.acme.dev:3128",
+		"https_proxy": "http://proxy.acme.dev:3128",
+		"NO_PROXY":    "localhost,127.0.0.1",
+	}
+	for name, value := range want {
+		if devcontainer.RemoteEnv[name] != value {
+			t.Errorf("remoteEnv[%s] = %q, want %q", name, devcontainer.RemoteEnv[name], value)
+		}
+	}
+	if _, ok := devcontainer.RemoteEnv["HTTP_PROXY"]; ok {
+		t.Error("remoteEnv should not set HTTP_PROXY without an http proxy")
+	}
+
+	detection.Proxy = models.ProxyOptions{}
+	content, err = NewDevcontainerGenerator().GenerateContent(detection, "api")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if strings.Contains(string(content), "PROXY") {
+		t.Errorf("devcontainer.json without a proxy should not set it, got:\n%s", content)
+	}
+}
@@ -0,0 +1,236 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// ReadmeService describes one service of the generated docker-compose.yml.
+type ReadmeService struct {
+	// Name is the compose service name (e.g., "postgres")
+	Name string
+
+	// Source is the image the service runs, or the Dockerfile it is built from
+	Source string
+
+	// Ports are the published ports (e.g., "9090:9090")
+	Ports []string
+
+	// Credentials are the KEY=value environment entries holding users, passwords,
+	// secrets, and connection strings with credentials
+	Credentials []string
+}
+
+// ReadmeConfig holds the data for generating README.devcontainer.md.
+type ReadmeConfig struct {
+	// ProjectName is the name of the project
+	ProjectName string
+
+	// Services are the services of the generated docker-compose.yml, in file order
+	Services []ReadmeService
+
+	// URLs are the dashboards and endpoints opened in a browser
+	URLs []ServiceURL
+
+	// Diagram is the Mermaid flowchart of the services, their dependencies, and volumes
+	Diagram string
+
+	// Compose is the configuration docker-compose.yml was rendered from
+	Compose *ComposeConfig
+}
+
+// HasCredentials returns true if any service has credential environment variables.
+func (c *ReadmeConfig) HasCredentials() bool {
+	for _, s := range c.Services {
+		if len(s.Credentials) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// credentialWords are words of environment variable names that hold credentials.
+var credentialWords = []string{"USER", "PASSWORD", "SECRET", "KEY", "TOKEN", "ADMIN"}
+
+// ReadmeGenerator generates README.devcontainer.md, which documents the generated stack.
+type ReadmeGenerator struct {
+	output
+}
+
+// NewReadmeGenerator creates a new README generator.
+func NewReadmeGenerator() *ReadmeGenerator {
+	return &ReadmeGenerator{}
+}
+
+// Generate creates .devcontainer/README.devcontainer.md.
+func (g *ReadmeGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	content, err := g.GenerateContent(detection, projectName)
+	if err != nil {
+		return err
+	}
+
+	devcontainerDir := filepath.Join(projectPath, ".devcontainer")
+	if err := g.fs().MkdirAll(devcontainerDir, 0755); err != nil {
+		return fmt.Errorf("failed to create .devcontainer directory: %w", err)
+	}
+	if err := g.fs().WriteFile(filepath.Join(devcontainerDir, "README.devcontainer.md"), content, 0644); err != nil {
+		return fmt.Errorf("failed to write README.devcontainer.md: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateContent returns the README.devcontainer.md content without writing to disk.
+func (g *ReadmeGenerator) GenerateContent(detection *models.Detection, projectName string) ([]byte, error) {
+	config, err := g.buildConfig(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderTemplate("README.devcontainer.md.tmpl", config)
+}
+
+// buildConfig renders docker-compose.yml and describes the services it contains,
+// so the README always matches the generated file.
+func (g *ReadmeGenerator) buildConfig(detection *models.Detection, projectName string) (*ReadmeConfig, error) {
+	composeGen := NewComposeGenerator()
+	compose, err := composeGen.config(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+	content, err := composeGen.render(compose)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render docker-compose.yml: %w", err)
+	}
+	services, err := parseComposeServices(content)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := buildGraph(compose, content)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ReadmeConfig{
+		ProjectName: projectName,
+		URLs:        ServiceURLs(detection),
+		Diagram:     graph.Mermaid(),
+		Compose:     compose,
+	}
+	for _, s := range services {
+		config.Services = append(config.Services, ReadmeService{
+			Name:        s.Name,
+			Source:      serviceSource(s),
+			Ports:       servicePorts(s.Ports),
+			Credentials: serviceCredentials(s.Environment),
+		})
+	}
+	return config, nil
+}
+
+// ShouldGenerate returns true if README.devcontainer.md should be generated.
+func (g *ReadmeGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.NeedsCompose()
+}
+
+// serviceSource returns the image a service runs, or the Dockerfile it is built from.
+func serviceSource(s composeService) string {
+	if s.Image != "" {
+		return "`" + s.Image + "`"
+	}
+	if extends := s.extends(); extends != "" {
+		return "same build as `" + extends + "`"
+	}
+
+	switch s.Build.Kind {
+	case yaml.ScalarNode:
+		return "built from `" + s.Build.Value + "`"
+	case yaml.MappingNode:
+		var build struct {
+			Context    string `yaml:"context"`
+			Dockerfile string `yaml:"dockerfile"`
+			Target     string `yaml:"target"`
+		}
+		if err := s.Build.Decode(&build); err != nil {
+			return "built locally"
+		}
+		dockerfile := build.Dockerfile
+		if dockerfile == "" {
+			dockerfile = "Dockerfile"
+		}
+		if build.Target != "" {
+			return fmt.Sprintf("built from `%s` (target `%s`)", dockerfile, build.Target)
+		}
+		return "built from `" + dockerfile + "`"
+	}
+	return "-"
+}
+
+// servicePorts returns the published ports of a service in short syntax,
+// converting long-syntax entries of imported services.
+func servicePorts(node yaml.Node) []string {
+	var ports []string
+	for _, entry := range node.Content {
+		switch entry.Kind {
+		case yaml.ScalarNode:
+			ports = append(ports, entry.Value)
+		case yaml.MappingNode:
+			var port struct {
+				Published string `yaml:"published"`
+				Target    string `yaml:"target"`
+			}
+			if err := entry.Decode(&port); err != nil {
+				continue
+			}
+			if port.Published == "" {
+				ports = append(ports, port.Target)
+			} else {
+				ports = append(ports, port.Published+":"+port.Target)
+			}
+		}
+	}
+	return ports
+}
+
+// serviceCredentials returns the KEY=value environment entries of a service that
+// hold credentials. Both the list and mapping environment syntaxes are read.
+func serviceCredentials(node yaml.Node) []string {
+	var entries []string
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, entry := range node.Content {
+			entries = append(entries, entry.Value)
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			entries = append(entries, node.Content[i-1].Value+"="+node.Content[i].Value)
+		}
+	}
+
+	var credentials []string
+	for _, entry := range entries {
+		key, value, _ := strings.Cut(entry, "=")
+		if isCredential(key, value) {
+			credentials = append(credentials, entry)
+		}
+	}
+	return credentials
+}
+
+// isCredential reports whether an environment variable holds a credential: its name
+// mentions one, or its value is a URL with a user and password.
+func isCredential(key, value string) bool {
+	for _, word := range strings.Split(strings.ToUpper(key), "_") {
+		if containsString(credentialWords, word) {
+			return true
+		}
+	}
+	_, rest, ok := strings.Cut(value, "://")
+	return ok && strings.Contains(rest, "@")
+}
@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// registryEnv returns the variables that point the package managers at the
+// project's private registries. GOPRIVATE also turns off the proxy and the
+// checksum database for the matching modules (GONOPROXY, GONOSUMDB).
+func registryEnv(registries models.RegistryOptions) []EnvVar {
+	var env []EnvVar
+	if registries.NPM != "" {
+		env = append(env, EnvVar{Name: "NPM_CONFIG_REGISTRY", Value: registries.NPM})
+	}
+	if registries.PyPI != "" {
+		env = append(env,
+			EnvVar{Name: "PIP_INDEX_URL", Value: registries.PyPI},
+			EnvVar{Name: "UV_DEFAULT_INDEX", Value: registries.PyPI},
+		)
+	}
+	if len(registries.GoPrivate) > 0 {
+		env = append(env, EnvVar{Name: "GOPRIVATE", Value: strings.Join(registries.GoPrivate, ",")})
+	}
+	if registries.GoProxy != "" {
+		env = append(env, EnvVar{Name: "GOPROXY", Value: registries.GoProxy})
+	}
+	for _, name := range cargoRegistries(registries) {
+		env = append(env, EnvVar{Name: cargoRegistryVar(name, "INDEX"), Value: registries.Cargo[name]})
+	}
+	return env
+}
+
+// registryCredentials returns the registry tokens passed from the host's
+// environment into the dev container's: NPM_TOKEN, which the project's .npmrc
+// references, and the Cargo registries' tokens.
+func registryCredentials(registries models.RegistryOptions) []EnvVar {
+	var names []string
+	if registries.NPM != "" {
+		names = append(names, "NPM_TOKEN")
+	}
+	for _, name := range cargoRegistries(registries) {
+		names = append(names, cargoRegistryVar(name, "TOKEN"))
+	}
+
+	env := make([]EnvVar, 0, len(names))
+	for _, name := range names {
+		env = append(env, EnvVar{Name: name, Value: "${localEnv:" + name + "}"})
+	}
+	return env
+}
+
+// cargoRegistries returns the names of the Cargo registries, sorted.
+func cargoRegistries(registries models.RegistryOptions) []string {
+	names := make([]string, 0, len(registries.Cargo))
+	for name := range registries.Cargo {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// cargoRegistryVar returns the variable that sets a Cargo registry's setting
+// (e.g., CARGO_REGISTRIES_MY_CORP_INDEX for my-corp's index).
+func cargoRegistryVar(name, setting string) string {
+	return "CARGO_REGISTRIES_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_" + setting
+}
+
+// quoteEnv quotes the values of variables for ENV instructions.
+func quoteEnv(env []EnvVar) []EnvVar {
+	quoted := make([]EnvVar, len(env))
+	for i, v := range env {
+		quoted[i] = EnvVar{Name: v.Name, Value: strconv.Quote(v.Value)}
+	}
+	return quoted
+}
@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestDockerfileGenerator_Registries tests the private registry variables
+// set in the generated Dockerfile.
+func TestDockerfileGenerator_Registries(t *testing.T) {
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Registries: models.RegistryOptions{
+			PyPI:      "https://pypi.acme.dev/simple",
+			GoPrivate: []string{"github.com/acme/*", "gitlab.acme.dev"},
+		},
+	}
+
+	content, err := NewDockerfileGenerator().GenerateContent(detection, "api")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	want := "WORKDIR /workspace\n\n" +
+		"# Private package registries (registries in .dockstart.yml); tokens come\n" +
+		"# from the host environment when the dev container starts\n" +
+		"ENV PIP_INDEX_URL=\"https://pypi.acme.dev/simple\"\n" +
+		"ENV UV_DEFAULT_INDEX=\"https://pypi.acme.dev/simple\"\n" +
+		"ENV GOPRIVATE=\"github.com/acme/*,gitlab.acme.dev\"\n\n" +
+		"# Language-specific setup\nRUN pip install --upgrade pip\n"
+	if !strings.Contains(string(content), want) {
+		t.Errorf("Dockerfile should contain:\n%s\ngot:\n%s", want, content)
+	}
+
+	// Without registries the Dockerfile is unchanged
+	detection.Registries = models.RegistryOptions{}
+	content, err = NewDockerfileGenerator().GenerateContent(detection, "api")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if strings.Contains(string(content), "registries") {
+		t.Errorf("Dockerfile without registries should not set them, got:\n%s", content)
+	}
+}
+
+// TestDevcontainerGenerator_Registries tests the registry variables and the
+// tokens passed from the host in devcontainer.json.
+func TestDevcontainerGenerator_Registries(t *testing.T) {
+	detection := &models.Detection{
+		Language: "rust",
+		Version:  "1.83",
+		Registries: models.RegistryOptions{
+			NPM:   "https://npm.acme.dev/",
+			Cargo: map[string]string{"my-corp": "sparse+https://cargo.acme.dev/index/"},
+		},
+	}
+
+	content, err := NewDevcontainerGenerator().GenerateContent(detection, "cli")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	var devcontainer struct {
+		RemoteEnv map[string]string `json:"remoteEnv"`
+	}
+	if err := json.Unmarshal(content, &devcontainer); err != nil {
+		t.Fatalf("devcontainer.json is not valid JSON: %v", err)
+	}
+	want := map[string]string{
+		"NPM_CONFIG_REGISTRY":            "https://npm.acme.dev/",
+		"CARGO_REGISTRIES_MY_CORP_INDEX": "sparse+https://cargo.acme.dev/index/",
+		"NPM_TOKEN":                      "${localEnv:NPM_TOKEN}",
+		"CARGO_REGISTRIES_MY_CORP_TOKEN": "${localEnv:CARGO_REGISTRIES_MY_CORP_TOKEN}",
+	}
+	for name, value := range want {
+		if devcontainer.RemoteEnv[name] != value {
+			t.Errorf("remoteEnv[%s] = %q, want %q", name, devcontainer.RemoteEnv[name], value)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// SyncWorkspaceScript copies the project into the workspace volume of a remote
+// Docker engine, relative to the project root. initializeCommand runs it on
+// the local machine before the containers start.
+const SyncWorkspaceScript = ".devcontainer/scripts/sync-workspace.sh"
+
+// syncImage is the image that unpacks the project into the workspace volume.
+const syncImage = "alpine:3.20"
+
+// dockerSocket is the engine socket sidecars mount to manage containers.
+const dockerSocket = "/var/run/docker.sock"
+
+// RemoteWorkspaceVolume returns the named volume holding the workspace on a
+// remote Docker engine. It has a fixed name, so the sync script and compose
+// agree on it whatever the compose project is called.
+func RemoteWorkspaceVolume(projectName string) string {
+	return projectName + "-workspace"
+}
+
+// SyncWorkspaceConfig holds the configuration for generating sync-workspace.sh.
+type SyncWorkspaceConfig struct {
+	// Volume is the workspace volume on the remote engine
+	Volume string
+
+	// Image unpacks the project into the volume
+	Image string
+
+	// Excludes are the directories left out of the copy: dependencies are
+	// installed inside the container, for Linux
+	Excludes []string
+}
+
+// RemoteWorkspaceGenerator generates .devcontainer/scripts/sync-workspace.sh
+// when the Docker engine is remote (detection.RemoteDocker).
+type RemoteWorkspaceGenerator struct {
+	output
+}
+
+// NewRemoteWorkspaceGenerator creates a new remote workspace generator.
+func NewRemoteWorkspaceGenerator() *RemoteWorkspaceGenerator {
+	return &RemoteWorkspaceGenerator{}
+}
+
+// ShouldGenerate returns true if the Docker engine is remote.
+func (g *RemoteWorkspaceGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.RemoteDocker
+}
+
+// Generate creates .devcontainer/scripts/sync-workspace.sh. It does nothing
+// unless the Docker engine is remote.
+func (g *RemoteWorkspaceGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	if !g.ShouldGenerate(detection) {
+		return nil
+	}
+	content, err := g.GenerateContent(detection, projectName)
+	if err != nil {
+		return err
+	}
+
+	scriptsDir := filepath.Join(projectPath, ".devcontainer", "scripts")
+	if err := g.fs().MkdirAll(scriptsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create scripts directory: %w", err)
+	}
+	if err := g.fs().WriteFile(filepath.Join(scriptsDir, "sync-workspace.sh"), content, 0755); err != nil {
+		return fmt.Errorf("failed to write sync-workspace.sh: %w", err)
+	}
+	return nil
+}
+
+// GenerateContent returns the sync-workspace.sh content without writing to disk.
+func (g *RemoteWorkspaceGenerator) GenerateContent(detection *models.Detection, projectName string) ([]byte, error) {
+	return renderTemplate("sync-workspace.sh.tmpl", &SyncWorkspaceConfig{
+		Volume:   RemoteWorkspaceVolume(projectName),
+		Image:    syncImage,
+		Excludes: detection.DependencyDirs(),
+	})
+}
+
+// RemoteWarnings returns what doesn't work as generated on a remote Docker
+// engine: services bind-mounting files from the project, which the engine
+// looks up on its own filesystem, and services mounting the Docker socket,
+// which is the remote engine's. Empty unless detection.RemoteDocker is set.
+func (g *ComposeGenerator) RemoteWarnings(detection *models.Detection, projectName string) ([]string, error) {
+	if !detection.RemoteDocker {
+		return nil, nil
+	}
+	services, err := g.services(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	var local, socket []string
+	for _, service := range services {
+		for _, source := range bindSources(service.Volumes) {
+			switch {
+			case source == dockerSocket:
+				if !containsString(socket, service.Name) {
+					socket = append(socket, service.Name)
+				}
+			case strings.HasPrefix(source, "."):
+				if !containsString(local, service.Name) {
+					local = append(local, service.Name)
+				}
+			}
+		}
+	}
+
+	var warnings []string
+	if len(local) > 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"Remote Docker engine: %s %s files from the project, which must exist at the same path on the remote host",
+			strings.Join(local, ", "), plural(len(local), "bind-mounts", "bind-mount")))
+	}
+	if len(socket) > 0 {
+		warnings = append(warnings, fmt.Sprintf(
+			"Remote Docker engine: %s %s %s, which controls the remote engine's containers, not local ones",
+			strings.Join(socket, ", "), plural(len(socket), "mounts", "mount"), dockerSocket))
+	}
+	return warnings, nil
+}
+
+// plural returns one when n is 1, and other otherwise.
+func plural(n int, one, other string) string {
+	if n == 1 {
+		return one
+	}
+	return other
+}
+
+// bindSources returns the host paths in a service's volumes, in short
+// ("./prometheus.yml:/etc/prometheus/prometheus.yml:ro") or long syntax.
+// Named volumes are left out.
+func bindSources(volumes yaml.Node) []string {
+	var sources []string
+	for _, volume := range volumes.Content {
+		var source string
+		switch volume.Kind {
+		case yaml.ScalarNode:
+			source, _, _ = strings.Cut(volume.Value, ":")
+		case yaml.MappingNode:
+			var long struct {
+				Type   string `yaml:"type"`
+				Source string `yaml:"source"`
+			}
+			if err := volume.Decode(&long); err != nil || long.Type != "bind" {
+				continue
+			}
+			source = long.Source
+		}
+		if strings.HasPrefix(source, ".") || strings.HasPrefix(source, "/") || strings.HasPrefix(source, "~") {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
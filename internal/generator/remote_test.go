@@ -0,0 +1,118 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestComposeGenerator_RemoteDocker tests that services mount the workspace
+// volume instead of the project, and the warnings about host mounts.
+func TestComposeGenerator_RemoteDocker(t *testing.T) {
+	detection := &models.Detection{
+		Language:     "node",
+		Version:      "20",
+		Services:     []string{"redis"},
+		Capabilities: models.Capabilities{QueueLibraries: []string{"bullmq"}, LoggingLibraries: []string{"winston"}},
+		Windows:      true,
+		RemoteDocker: true,
+	}
+	gen := NewComposeGenerator()
+
+	content, err := gen.GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+	for _, want := range []string{
+		"      - workspace:/workspace\n",
+		"  workspace:\n    name: shop-workspace\n",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+		}
+	}
+	// The workspace volume holds the dependencies too, in Windows mode as well
+	for _, unwanted := range []string{"..:/workspace", "source: ..", "node-modules"} {
+		if strings.Contains(compose, unwanted) {
+			t.Errorf("docker-compose.yml should not contain %q, got:\n%s", unwanted, compose)
+		}
+	}
+
+	warnings, err := gen.RemoteWarnings(detection, "shop")
+	if err != nil {
+		t.Fatalf("RemoteWarnings() error = %v", err)
+	}
+	if len(warnings) != 2 {
+		t.Fatalf("RemoteWarnings() = %q, want 2 warnings", warnings)
+	}
+	if !strings.Contains(warnings[0], "fluent-bit") || strings.Contains(warnings[0], "app") {
+		t.Errorf("RemoteWarnings()[0] = %q, want the services mounting project files", warnings[0])
+	}
+	if !strings.Contains(warnings[1], "db-backup mounts /var/run/docker.sock") {
+		t.Errorf("RemoteWarnings()[1] = %q, want the backup sidecar's socket", warnings[1])
+	}
+
+	detection.RemoteDocker = false
+	if warnings, _ := gen.RemoteWarnings(detection, "shop"); len(warnings) != 0 {
+		t.Errorf("RemoteWarnings() with a local engine = %q, want none", warnings)
+	}
+}
+
+// TestDevcontainerGenerator_RemoteDocker tests the sync before the container
+// starts, and the workspace volume mount without Compose.
+func TestDevcontainerGenerator_RemoteDocker(t *testing.T) {
+	detection := &models.Detection{
+		Language:     "rust",
+		Version:      "1.80",
+		Windows:      true,
+		RemoteDocker: true,
+	}
+
+	content, err := NewDevcontainerGenerator().GenerateContent(detection, "cli")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	devcontainer := string(content)
+	for _, want := range []string{
+		`"workspaceMount": "source=cli-workspace,target=/workspace,type=volume"`,
+		`"initializeCommand": "sh .devcontainer/scripts/sync-workspace.sh"`,
+	} {
+		if !strings.Contains(devcontainer, want) {
+			t.Errorf("devcontainer.json should contain %q, got:\n%s", want, devcontainer)
+		}
+	}
+	if strings.Contains(devcontainer, "-target,target=/workspace/target") {
+		t.Errorf("devcontainer.json should not mount target in its own volume, got:\n%s", devcontainer)
+	}
+	if _, err := parseJSONC(content); err != nil {
+		t.Errorf("Generated JSON is invalid: %v", err)
+	}
+}
+
+// TestRemoteWorkspaceGenerator tests the workspace sync script.
+func TestRemoteWorkspaceGenerator(t *testing.T) {
+	gen := NewRemoteWorkspaceGenerator()
+	detection := &models.Detection{Language: "python", Version: "3.12"}
+	if gen.ShouldGenerate(detection) {
+		t.Error("ShouldGenerate() should be false with a local engine")
+	}
+
+	detection.RemoteDocker = true
+	content, err := gen.GenerateContent(detection, "api")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	script := string(content)
+	for _, want := range []string{
+		`VOLUME="api-workspace"`,
+		`docker volume create "$VOLUME"`,
+		"tar -cf - --exclude=./.venv . |",
+		`docker run --rm -i -v "$VOLUME:/workspace" "$IMAGE" tar -xf - -C /workspace`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("sync-workspace.sh should contain %q, got:\n%s", want, script)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jpequegn/dockstart/internal/generator/templates"
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// ReportFile is the generation report, relative to the project root.
+const ReportFile = ".devcontainer/dockstart-report.json"
+
+// reportSchema is bumped when the report format changes.
+const reportSchema = 1
+
+// GenerationReport records how the .devcontainer files were generated, so later
+// runs can explain why a file looks the way it does and which files were edited
+// since. It is written next to the generated files and never sent anywhere.
+type GenerationReport struct {
+	Schema int `json:"schema"`
+
+	// DetectionSchema is the models.DetectionSchema the detection was written with
+	DetectionSchema int `json:"detection_schema"`
+
+	// GeneratedAt is when the files were generated
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// Version and Build identify the dockstart binary
+	Version string `json:"version"`
+	Build   string `json:"build"`
+
+	// BuildInfo describes how the binary was built, to trace the files back to it
+	BuildInfo BuildInfo `json:"build_info"`
+
+	// Templates is a hash of the embedded templates, which changes whenever
+	// the generated output could change for the same detection
+	Templates string `json:"templates"`
+
+	// Options are the command-line and .dockstart.yml choices
+	Options ReportOptions `json:"options"`
+
+	// Detection is the detection the files were generated from
+	Detection *models.Detection `json:"detection"`
+
+	// Files maps each generated file, relative to the project root, to its SHA-256
+	Files map[string]string `json:"files"`
+
+	// Owners maps each generated file to the generator that wrote it
+	// (e.g., "docker-compose.yml", "backup", "fluent-bit")
+	Owners map[string]string `json:"owners,omitempty"`
+}
+
+// ReportOptions are the generation choices that aren't part of the detection.
+// Worker, file processor, non-root, and hardened settings are in the detection.
+type ReportOptions struct {
+	Language        string `json:"language,omitempty"`
+	Lockfiles       bool   `json:"lockfiles,omitempty"`
+	NoCache         bool   `json:"no_cache,omitempty"`
+	Force           bool   `json:"force,omitempty"`
+	ForceDockerfile bool   `json:"force_dockerfile,omitempty"`
+}
+
+// BuildInfo describes a dockstart build.
+type BuildInfo struct {
+	// Version is the release version, or "dev"
+	Version string `json:"version"`
+
+	// Commit is the git commit the binary was built from
+	Commit string `json:"commit,omitempty"`
+
+	// Date is when the binary was built (or the commit time for go install builds)
+	Date string `json:"date,omitempty"`
+
+	// GoVersion is the Go toolchain the binary was built with
+	GoVersion string `json:"go_version"`
+
+	// Platform is the OS and architecture (e.g., "linux/amd64")
+	Platform string `json:"platform"`
+
+	// ImageCatalog is the version of the pinned sidecar image catalog
+	ImageCatalog string `json:"image_catalog"`
+}
+
+// ImageCatalogVersion identifies the catalog of pinned sidecar images. Release
+// builds set it at build time:
+//
+//	go build -ldflags "-X github.com/jpequegn/dockstart/internal/generator.ImageCatalogVersion=2026.10"
+//
+// Other builds use a hash of the embedded templates (see CatalogVersion).
+var ImageCatalogVersion = ""
+
+// CatalogVersion returns ImageCatalogVersion, or "templates-" and the first 12
+// characters of the templates hash when it isn't set.
+func CatalogVersion() string {
+	if ImageCatalogVersion != "" {
+		return ImageCatalogVersion
+	}
+	hash, err := templates.Hash()
+	if err != nil {
+		return "unknown"
+	}
+	return "templates-" + hash[:12]
+}
+
+// NewGenerationReport creates a report of the files just generated, hashing their
+// content as read through fsys. build identifies the binary for cache
+// invalidation, and info describes it.
+func NewGenerationReport(fsys FS, projectPath, build string, info BuildInfo, options ReportOptions, detection *models.Detection, files []string) (*GenerationReport, error) {
+	templatesSum, err := templates.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &GenerationReport{
+		Schema:          reportSchema,
+		DetectionSchema: models.DetectionSchema,
+		GeneratedAt:     time.Now().UTC().Truncate(time.Second),
+		Version:         info.Version,
+		Build:           build,
+		BuildInfo:       info,
+		Templates:       templatesSum,
+		Options:         options,
+		Detection:       detection,
+		Files:           make(map[string]string, len(files)),
+	}
+	for _, rel := range files {
+		hash, err := fileHash(fsys, filepath.Join(projectPath, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash %s: %w", rel, err)
+		}
+		report.Files[rel] = hash
+	}
+	return report, nil
+}
+
+// Write writes the report to .devcontainer/dockstart-report.json through fsys.
+func (r *GenerationReport) Write(fsys FS, projectPath string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode generation report: %w", err)
+	}
+
+	path := filepath.Join(projectPath, filepath.FromSlash(ReportFile))
+	if err := fsys.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create .devcontainer directory: %w", err)
+	}
+	if err := fsys.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ReportFile, err)
+	}
+	return nil
+}
+
+// ReadReport reads a project's generation report through fsys.
+// Returns nil without an error when the project has none.
+func ReadReport(fsys FS, projectPath string) (*GenerationReport, error) {
+	data, err := fsys.ReadFile(filepath.Join(projectPath, filepath.FromSlash(ReportFile)))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ReportFile, err)
+	}
+
+	var report GenerationReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ReportFile, err)
+	}
+	if report.Schema != reportSchema {
+		return nil, fmt.Errorf("%s has unsupported schema %d", ReportFile, report.Schema)
+	}
+	if report.DetectionSchema != models.DetectionSchema {
+		return nil, fmt.Errorf("%s was written by an older dockstart: run dockstart again to update it", ReportFile)
+	}
+	return &report, nil
+}
+
+// ChangedFiles returns the generated files that were edited or deleted since the
+// report was written, sorted.
+func (r *GenerationReport) ChangedFiles(projectPath string) []string {
+	var changed []string
+	for rel, want := range r.Files {
+		hash, err := fileHash(OS, filepath.Join(projectPath, filepath.FromSlash(rel)))
+		if err != nil || hash != want {
+			changed = append(changed, rel)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// fileHash returns the hex SHA-256 of a file's content, read through fsys.
+func fileHash(fsys FS, path string) (string, error) {
+	data, err := fsys.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,149 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestGenerationReport tests writing, reading, and checking the generation report.
+func TestGenerationReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	detection := &models.Detection{
+		Language: "go",
+		Version:  "1.23",
+		Services: []string{"postgres"},
+		NonRoot:  true,
+		Capabilities: models.Capabilities{
+			QueueLibraries: []string{"asynq"},
+		},
+	}
+
+	for _, gen := range []func() error{
+		func() error { return NewComposeGenerator().Generate(detection, tmpDir, "api") },
+		func() error { return NewDockerfileGenerator().Generate(detection, tmpDir, "api") },
+	} {
+		if err := gen(); err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+	}
+
+	files := []string{".devcontainer/docker-compose.yml", ".devcontainer/Dockerfile"}
+	options := ReportOptions{Language: "go", Force: true}
+	info := BuildInfo{Version: "1.2.0", Commit: "abc123", GoVersion: "go1.23.4", Platform: "linux/amd64", ImageCatalog: CatalogVersion()}
+	report, err := NewGenerationReport(OS, tmpDir, "1.2.0 abc123", info, options, detection, files)
+	if err != nil {
+		t.Fatalf("NewGenerationReport() error = %v", err)
+	}
+	if len(report.Files) != 2 || len(report.Templates) != 64 {
+		t.Errorf("report should hash 2 files and the templates, got %d files, templates %q", len(report.Files), report.Templates)
+	}
+	if err := report.Write(OS, tmpDir); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	read, err := ReadReport(OS, tmpDir)
+	if err != nil {
+		t.Fatalf("ReadReport() error = %v", err)
+	}
+	if read.Version != "1.2.0" || read.BuildInfo != info || read.Options != options || !read.GeneratedAt.Equal(report.GeneratedAt) {
+		t.Errorf("ReadReport() = %+v, want %+v", read, report)
+	}
+	if !reflect.DeepEqual(read.Detection.Services, detection.Services) || !read.Detection.NonRoot {
+		t.Errorf("ReadReport() detection = %+v, want %+v", read.Detection, detection)
+	}
+	if !read.Detection.HasQueueLibrary("asynq") {
+		t.Errorf("ReadReport() capabilities = %+v, want %+v", read.Detection.Capabilities, detection.Capabilities)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, filepath.FromSlash(ReportFile)))
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	if !strings.Contains(string(data), `"capabilities": {
+      "queue": [
+        "asynq"
+      ]
+    }`) {
+		t.Errorf("report should nest the detected libraries under capabilities:\n%s", data)
+	}
+
+	if !strings.HasPrefix(info.ImageCatalog, "templates-") {
+		t.Errorf("CatalogVersion() = %q, want the templates hash without ImageCatalogVersion", info.ImageCatalog)
+	}
+
+	if changed := read.ChangedFiles(tmpDir); len(changed) != 0 {
+		t.Errorf("ChangedFiles() = %v, want none", changed)
+	}
+
+	// Edit one file and delete the other
+	compose := filepath.Join(tmpDir, ".devcontainer", "docker-compose.yml")
+	if err := os.WriteFile(compose, []byte("services: {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to edit docker-compose.yml: %v", err)
+	}
+	if err := os.Remove(filepath.Join(tmpDir, ".devcontainer", "Dockerfile")); err != nil {
+		t.Fatalf("Failed to remove Dockerfile: %v", err)
+	}
+	want := []string{".devcontainer/Dockerfile", ".devcontainer/docker-compose.yml"}
+	if changed := read.ChangedFiles(tmpDir); !reflect.DeepEqual(changed, want) {
+		t.Errorf("ChangedFiles() = %v, want %v", changed, want)
+	}
+}
+
+// TestReadReport_Missing tests that a project without a report has none.
+func TestReadReport_Missing(t *testing.T) {
+	report, err := ReadReport(OS, t.TempDir())
+	if err != nil || report != nil {
+		t.Errorf("ReadReport() = %v, %v, want nil, nil", report, err)
+	}
+}
+
+// TestReadReport_OldDetectionSchema tests that a report whose detection was
+// written with another schema isn't compared against.
+func TestReadReport_OldDetectionSchema(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, filepath.FromSlash(ReportFile))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create .devcontainer: %v", err)
+	}
+	old := `{"schema": 1, "detection": {"Language": "go", "QueueLibraries": ["asynq"]}}`
+	if err := os.WriteFile(path, []byte(old), 0644); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+
+	if _, err := ReadReport(OS, tmpDir); err == nil || !strings.Contains(err.Error(), "older dockstart") {
+		t.Errorf("ReadReport() error = %v, want an older dockstart error", err)
+	}
+}
+
+// TestGenerationReport_MemFS tests that the report is hashed and written
+// through the filesystem it is given, leaving the project untouched.
+func TestGenerationReport_MemFS(t *testing.T) {
+	tmpDir := t.TempDir()
+	detection := &models.Detection{Language: "go", Version: "1.23"}
+
+	mem := NewMemFS(OS)
+	gen := NewDockerfileGenerator()
+	gen.SetFS(mem)
+	if err := gen.Generate(detection, tmpDir, "api"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	report, err := NewGenerationReport(mem, tmpDir, "dev", BuildInfo{Version: "dev"}, ReportOptions{}, detection, []string{".devcontainer/Dockerfile"})
+	if err != nil {
+		t.Fatalf("NewGenerationReport() error = %v", err)
+	}
+	if err := report.Write(mem, tmpDir); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if read, err := ReadReport(mem, tmpDir); err != nil || read == nil {
+		t.Errorf("ReadReport() = %v, %v, want the report written to the MemFS", read, err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".devcontainer")); !os.IsNotExist(err) {
+		t.Errorf("nothing should be written to disk, got .devcontainer: %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// defaultResourceLimits are the limits of the generated services, sized for a
+// development workload. The app, worker, scheduler, and frontend containers run
+// the project's toolchains, whose needs vary too much for a default; they are
+// only limited when .dockstart.yml sets resources for them.
+var defaultResourceLimits = map[string]models.ResourceLimits{
+	"postgres":          {Memory: "512M", CPUs: "1"},
+	"postgres-test":     {Memory: "256M", CPUs: "1"},
+	"redis":             {Memory: "256M", CPUs: "0.5"},
+	"redis-test":        {Memory: "128M", CPUs: "0.5"},
+	"clickhouse":        {Memory: "1G", CPUs: "2"},
+	"influxdb":          {Memory: "512M", CPUs: "1"},
+	"nats":              {Memory: "256M", CPUs: "0.5"},
+	"temporal":          {Memory: "512M", CPUs: "1"},
+	"temporal-ui":       {Memory: "128M", CPUs: "0.25"},
+	"rabbitmq":          {Memory: "512M", CPUs: "1"},
+	"rabbitmq-init":     {Memory: "64M", CPUs: "0.25"},
+	"bull-board":        {Memory: "128M", CPUs: "0.25"},
+	"flower":            {Memory: "128M", CPUs: "0.25"},
+	"asynqmon":          {Memory: "64M", CPUs: "0.25"},
+	"nats-box":          {Memory: "64M", CPUs: "0.25"},
+	"adminer":           {Memory: "128M", CPUs: "0.25"},
+	"redisinsight":      {Memory: "256M", CPUs: "0.5"},
+	"prod-import":       {Memory: "512M", CPUs: "1"},
+	"fluent-bit":        {Memory: "128M", CPUs: "0.25"},
+	"log-rotate":        {Memory: "16M", CPUs: "0.1"},
+	"prometheus":        {Memory: "512M", CPUs: "0.5"},
+	"grafana":           {Memory: "256M", CPUs: "0.5"},
+	"postgres-exporter": {Memory: "64M", CPUs: "0.25"},
+	"redis-exporter":    {Memory: "64M", CPUs: "0.25"},
+	"jaeger":            {Memory: "512M", CPUs: "0.5"},
+	"keycloak":          {Memory: "1G", CPUs: "1"},
+	"stripe-cli":        {Memory: "64M", CPUs: "0.25"},
+	"selenium-hub":      {Memory: "256M", CPUs: "0.5"},
+	"chrome":            {Memory: "2G", CPUs: "1"},
+	"firefox":           {Memory: "2G", CPUs: "1"},
+	"qdrant":            {Memory: "512M", CPUs: "1"},
+	"chroma":            {Memory: "512M", CPUs: "1"},
+	"meilisearch":       {Memory: "512M", CPUs: "1"},
+	"typesense":         {Memory: "512M", CPUs: "1"},
+	"mailpit":           {Memory: "128M", CPUs: "0.25"},
+	"ollama":            {Memory: "4G", CPUs: "2"},
+	"ollama-pull":       {Memory: "128M", CPUs: "0.25"},
+	"wiremock":          {Memory: "256M", CPUs: "0.5"},
+	"toxiproxy":         {Memory: "64M", CPUs: "0.25"},
+	"localstack":        {Memory: "1G", CPUs: "1"},
+	"minio":             {Memory: "512M", CPUs: "0.5"},
+	"minio-init":        {Memory: "64M", CPUs: "0.25"},
+	"grpcui":            {Memory: "64M", CPUs: "0.25"},
+	"gatus":             {Memory: "64M", CPUs: "0.25"},
+	"db-backup":         {Memory: "256M", CPUs: "0.5"},
+
+	// GlitchTip and its own databases
+	"glitchtip":          {Memory: "512M", CPUs: "0.5"},
+	"glitchtip-worker":   {Memory: "512M", CPUs: "0.5"},
+	"glitchtip-migrate":  {Memory: "256M", CPUs: "0.5"},
+	"glitchtip-postgres": {Memory: "256M", CPUs: "0.5"},
+	"glitchtip-redis":    {Memory: "64M", CPUs: "0.25"},
+}
+
+// ownResourceLimits are the services whose deploy block comes from their own
+// settings: worker.memory/worker.cpus, the Memcached cache size, and the file
+// processor's enabled processors.
+var ownResourceLimits = []string{"worker", "memcached", "file-processor"}
+
+// resourceLimits returns the limits of the generated services: the defaults
+// (smaller in Codespaces), with the limits set in .dockstart.yml on top.
+func resourceLimits(detection *models.Detection) map[string]models.ResourceLimits {
+	limits := make(map[string]models.ResourceLimits, len(defaultResourceLimits)+len(detection.Resources))
+	for service, limit := range defaultResourceLimits {
+		limits[service] = limit
+	}
+	if detection.TargetsCodespaces() {
+		for service, limit := range codespacesResourceLimits {
+			limits[service] = limit
+		}
+	}
+	for service, override := range detection.Resources {
+		limit := limits[service]
+		if override.Memory != "" {
+			limit.Memory = override.Memory
+		}
+		if override.CPUs != "" {
+			limit.CPUs = override.CPUs
+		}
+		limits[service] = limit
+	}
+	return limits
+}
+
+// Resources renders the deploy.resources limits of a generated service, placed
+// after its hardening settings in docker-compose.yml. Empty for services
+// without limits and for those with their own deploy block.
+func (c *ComposeConfig) Resources(service string) string {
+	if containsString(ownResourceLimits, service) {
+		return ""
+	}
+	limit := c.ResourceLimits[service]
+	if limit.Memory == "" && limit.CPUs == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n    deploy:\n      resources:\n        limits:")
+	if limit.CPUs != "" {
+		fmt.Fprintf(&b, "\n          cpus: %q", limit.CPUs)
+	}
+	if limit.Memory != "" {
+		fmt.Fprintf(&b, "\n          memory: %s", limit.Memory)
+	}
+	return b.String()
+}
+
+// MemoryBudget is the memory the services started by "docker compose up" may use.
+type MemoryBudget struct {
+	// Total is the sum of the memory limits, in bytes, counting every replica
+	Total int64
+
+	// Unlimited lists the services without a memory limit, which the total leaves out
+	Unlimited []string
+}
+
+// MemoryBudget adds up the memory limits of the generated docker-compose.yml.
+// Services in a profile (tools, test) aren't started by default and don't count.
+func (g *ComposeGenerator) MemoryBudget(detection *models.Detection, projectName string) (*MemoryBudget, error) {
+	services, err := g.services(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	budget := &MemoryBudget{}
+	for _, s := range services {
+		if len(s.Profiles.Content) > 0 {
+			continue
+		}
+
+		var deploy struct {
+			Replicas  *int `yaml:"replicas"`
+			Resources struct {
+				Limits struct {
+					Memory string `yaml:"memory"`
+				} `yaml:"limits"`
+			} `yaml:"resources"`
+		}
+		// Imported services may use any syntax; an unreadable deploy block counts as no limit
+		_ = s.Deploy.Decode(&deploy)
+
+		replicas := 1
+		if deploy.Replicas != nil {
+			replicas = *deploy.Replicas
+		}
+		memory, err := ParseMemory(deploy.Resources.Limits.Memory)
+		if err != nil || memory == 0 {
+			if replicas > 0 {
+				budget.Unlimited = append(budget.Unlimited, s.Name)
+			}
+			continue
+		}
+		budget.Total += memory * int64(replicas)
+	}
+	return budget, nil
+}
+
+// ParseMemory parses a compose memory size (e.g., "512M", "1g", "256mb") into
+// bytes. An empty size is 0.
+func ParseMemory(size string) (int64, error) {
+	if size == "" {
+		return 0, nil
+	}
+
+	lower := strings.ToLower(strings.TrimSpace(size))
+	number := strings.TrimRight(lower, "bkmg")
+	unit := strings.TrimSuffix(lower[len(number):], "b")
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value < 0 || len(unit) > 1 {
+		return 0, fmt.Errorf("invalid memory size %q: expected a size like \"512m\" or \"8g\"", size)
+	}
+
+	switch unit {
+	case "k":
+		value *= 1 << 10
+	case "m":
+		value *= 1 << 20
+	case "g":
+		value *= 1 << 30
+	}
+	return int64(value), nil
+}
+
+// FormatMemory formats bytes as a size for messages (e.g., "1.5G", "512M").
+func FormatMemory(bytes int64) string {
+	switch {
+	case bytes >= 1<<30:
+		return strings.TrimSuffix(strconv.FormatFloat(float64(bytes)/(1<<30), 'f', 1, 64), ".0") + "G"
+	case bytes >= 1<<20:
+		return strconv.FormatInt(bytes>>20, 10) + "M"
+	default:
+		return strconv.FormatInt(bytes, 10) + "B"
+	}
+}
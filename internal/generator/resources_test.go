@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestComposeGenerator_Resources tests the memory and CPU limits of generated services.
+func TestComposeGenerator_Resources(t *testing.T) {
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Services: []string{"postgres", "redis", "memcached"},
+		Resources: map[string]models.ResourceLimits{
+			"redis": {Memory: "1g"},
+			"app":   {Memory: "4g", CPUs: "2"},
+		},
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+	logging := "    logging:\n      driver: json-file\n      options:\n        max-size: \"10m\"\n        max-file: \"3\"\n"
+
+	for _, want := range []string{
+		// Defaults
+		"      - \"5432:5432\"\n" + logging + "    deploy:\n      resources:\n        limits:\n          cpus: \"1\"\n          memory: 512M\n",
+		// A configured limit replaces only what it sets
+		"      - \"6379:6379\"\n" + logging + "    deploy:\n      resources:\n        limits:\n          cpus: \"0.5\"\n          memory: 1g\n",
+		// The app is only limited when configured
+		"      - MEMCACHED_PORT=11211\n" + logging + "    deploy:\n      resources:\n        limits:\n          cpus: \"2\"\n          memory: 4g\n",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+		}
+	}
+
+	// Memcached keeps the single deploy block sized from its cache
+	memcached := compose[strings.Index(compose, "  memcached:\n"):]
+	memcached = memcached[:strings.Index(memcached, "\n\n")]
+	if strings.Count(memcached, "deploy:") != 1 {
+		t.Errorf("memcached should have one deploy block, got:\n%s", memcached)
+	}
+}
+
+// TestComposeGenerator_MemoryBudget tests adding up the memory limits of the stack.
+func TestComposeGenerator_MemoryBudget(t *testing.T) {
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Services: []string{"postgres", "redis"},
+		Capabilities: models.Capabilities{
+			QueueLibraries: []string{"celery"},
+		},
+		WorkerCommand: "celery -A app worker",
+		Worker:        models.WorkerOptions{Replicas: 2, Memory: "512m"},
+	}
+
+	budget, err := NewComposeGenerator().MemoryBudget(detection, "shop")
+	if err != nil {
+		t.Fatalf("MemoryBudget() error = %v", err)
+	}
+
+	// postgres 512M + redis 256M + flower 128M + db-backup 256M + 2 workers x
+	// 512M; adminer and redisinsight are in the tools profile and don't count
+	if want := int64(2176 << 20); budget.Total != want {
+		t.Errorf("Total = %s, want %s", FormatMemory(budget.Total), FormatMemory(want))
+	}
+	if !containsString(budget.Unlimited, "app") || containsString(budget.Unlimited, "adminer") {
+		t.Errorf("Unlimited = %v, want app but not adminer", budget.Unlimited)
+	}
+}
+
+// TestParseMemory tests converting compose memory sizes to bytes.
+func TestParseMemory(t *testing.T) {
+	tests := []struct {
+		size    string
+		want    int64
+		wantErr bool
+	}{
+		{size: "", want: 0},
+		{size: "1024", want: 1024},
+		{size: "64k", want: 64 << 10},
+		{size: "512M", want: 512 << 20},
+		{size: "256mb", want: 256 << 20},
+		{size: "1.5g", want: 3 << 29},
+		{size: "lots", wantErr: true},
+		{size: "1tb", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			got, err := ParseMemory(tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMemory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMemory() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	for bytes, want := range map[int64]string{512 << 20: "512M", 1 << 30: "1G", 3 << 29: "1.5G"} {
+		if got := FormatMemory(bytes); got != want {
+			t.Errorf("FormatMemory(%d) = %q, want %q", bytes, got, want)
+		}
+	}
+}
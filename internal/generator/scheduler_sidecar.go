@@ -0,0 +1,104 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// SchedulerSidecarConfig holds configuration for generating the supercronic scheduler sidecar.
+type SchedulerSidecarConfig struct {
+	// ProjectName is the name of the project
+	ProjectName string
+
+	// SchedulerLibraries is the list of detected scheduler libraries
+	SchedulerLibraries []string
+
+	// AppURL is the base URL jobs use to reach the application
+	AppURL string
+
+	// User is the non-root user supercronic runs as. Empty to run as root
+	User string
+
+	// Timezone is the TZ docker-compose.yml sets, which the image needs
+	// tzdata for; jobs are scheduled in it. Empty for UTC
+	Timezone string
+}
+
+// DefaultSchedulerConfig returns a SchedulerSidecarConfig with sensible defaults.
+func DefaultSchedulerConfig() *SchedulerSidecarConfig {
+	return &SchedulerSidecarConfig{
+		AppURL: "http://app:3000",
+	}
+}
+
+// SchedulerSidecarGenerator generates the supercronic scheduler sidecar files.
+type SchedulerSidecarGenerator struct {
+	output
+}
+
+// NewSchedulerSidecarGenerator creates a new scheduler sidecar generator.
+func NewSchedulerSidecarGenerator() *SchedulerSidecarGenerator {
+	return &SchedulerSidecarGenerator{}
+}
+
+// GenerateDockerfile generates the Dockerfile.scheduler content.
+func (g *SchedulerSidecarGenerator) GenerateDockerfile(config *SchedulerSidecarConfig) ([]byte, error) {
+	return renderTemplate("Dockerfile.scheduler.tmpl", config)
+}
+
+// GenerateCrontab generates the crontab.scheduler content.
+func (g *SchedulerSidecarGenerator) GenerateCrontab(config *SchedulerSidecarConfig) ([]byte, error) {
+	return renderTemplate("crontab.scheduler.tmpl", config)
+}
+
+// Generate creates the scheduler sidecar Dockerfile and crontab.
+func (g *SchedulerSidecarGenerator) Generate(detection *models.Detection, outputPath, projectName string) error {
+	config := g.buildConfig(detection, projectName)
+
+	devcontainerDir := filepath.Join(outputPath, ".devcontainer")
+	if err := g.fs().MkdirAll(devcontainerDir, 0755); err != nil {
+		return fmt.Errorf("failed to create .devcontainer directory: %w", err)
+	}
+
+	// Generate Dockerfile.scheduler
+	dockerfile, err := g.GenerateDockerfile(config)
+	if err != nil {
+		return err
+	}
+	if err := g.fs().WriteFile(filepath.Join(devcontainerDir, "Dockerfile.scheduler"), dockerfile, 0644); err != nil {
+		return fmt.Errorf("failed to write Dockerfile.scheduler: %w", err)
+	}
+
+	// Generate crontab.scheduler
+	crontab, err := g.GenerateCrontab(config)
+	if err != nil {
+		return err
+	}
+	if err := g.fs().WriteFile(filepath.Join(devcontainerDir, "crontab.scheduler"), crontab, 0644); err != nil {
+		return fmt.Errorf("failed to write crontab.scheduler: %w", err)
+	}
+
+	return nil
+}
+
+// buildConfig creates a SchedulerSidecarConfig from detection results.
+func (g *SchedulerSidecarGenerator) buildConfig(detection *models.Detection, projectName string) *SchedulerSidecarConfig {
+	config := DefaultSchedulerConfig()
+	config.ProjectName = projectName
+	config.SchedulerLibraries = detection.SchedulerLibraries
+	config.AppURL = fmt.Sprintf("http://app:%d", detection.GetAppPort())
+	config.Timezone = detection.Timezone
+	if detection.NonRoot {
+		config.User = models.SidecarUser
+	}
+	return config
+}
+
+// ShouldGenerate returns true if the supercronic sidecar should be generated.
+// Projects with a dedicated scheduler process run it as a compose service instead.
+func (g *SchedulerSidecarGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.NeedsScheduler() && detection.SchedulerCommand == ""
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// MeilisearchPort and TypesensePort are the search engines' API ports, on the
+// host and in the compose network.
+const (
+	MeilisearchPort = 7700
+	TypesensePort   = 8108
+)
+
+// searchEngines are the search engines run for the detected clients.
+var searchEngines = map[string]struct {
+	Image string
+	Port  int
+}{
+	"meilisearch": {"getmeili/meilisearch:v1.11", MeilisearchPort},
+	"typesense":   {"typesense/typesense:27.1", TypesensePort},
+}
+
+// SearchComposeConfig holds configuration for the Meilisearch or Typesense
+// full-text search engine.
+type SearchComposeConfig struct {
+	// Enabled indicates whether to include the search engine
+	Enabled bool
+
+	// Engine is the search engine to run ("meilisearch" or "typesense")
+	Engine string
+
+	// Image is the search engine's image
+	Image string
+
+	// Port is the external and internal API port
+	Port int
+
+	// Libraries are the detected search clients
+	Libraries []string
+
+	// APIKey is the master (Meilisearch) or bootstrap admin (Typesense) key,
+	// taken from the host environment when set there
+	APIKey string
+
+	// Environment is injected into the app and the worker
+	Environment []string
+}
+
+// searchConfig returns the search engine settings, or a zero config unless a
+// Meilisearch or Typesense client was detected.
+func searchConfig(detection *models.Detection) SearchComposeConfig {
+	engine := detection.GetSearchEngine()
+	if engine == "" {
+		return SearchComposeConfig{}
+	}
+	config := SearchComposeConfig{
+		Enabled:   true,
+		Engine:    engine,
+		Image:     searchEngines[engine].Image,
+		Port:      searchEngines[engine].Port,
+		Libraries: detection.SearchLibraries,
+	}
+	url := fmt.Sprintf("http://%s:%d", engine, config.Port)
+
+	// Development only: the key is the same for every project unless the host sets it
+	switch engine {
+	case "meilisearch":
+		config.APIKey = "${MEILI_MASTER_KEY:-dockstart-development-master-key}"
+		config.Environment = []string{
+			"MEILI_URL=" + url,
+			"MEILI_MASTER_KEY=" + config.APIKey,
+		}
+	case "typesense":
+		config.APIKey = "${TYPESENSE_API_KEY:-dockstart-development-api-key}"
+		config.Environment = []string{
+			"TYPESENSE_URL=" + url,
+			"TYPESENSE_HOST=" + engine,
+			fmt.Sprintf("TYPESENSE_PORT=%d", config.Port),
+			"TYPESENSE_PROTOCOL=http",
+			"TYPESENSE_API_KEY=" + config.APIKey,
+		}
+	}
+	return config
+}
@@ -0,0 +1,132 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestComposeGenerator_Minimal tests leaving the optional sidecars out of the stack.
+func TestComposeGenerator_Minimal(t *testing.T) {
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Services: []string{"postgres", "redis"},
+		Capabilities: models.Capabilities{
+			LoggingLibraries:    []string{"structlog"},
+			MetricsLibraries:    []string{"prometheus-client"},
+			TracingLibraries:    []string{"opentelemetry"},
+			FileUploadLibraries: []string{"python-multipart"},
+			QueueLibraries:      []string{"celery"},
+		},
+		WorkerCommand: "celery -A app worker",
+		Sidecars:      models.SidecarPolicy{Minimal: true},
+	}
+
+	services, err := NewComposeGenerator().Services(detection, "shop")
+	if err != nil {
+		t.Fatalf("Services() error = %v", err)
+	}
+	for _, want := range []string{"app", "postgres", "redis", "worker"} {
+		if !containsString(services, want) {
+			t.Errorf("Services() = %v, should include %s", services, want)
+		}
+	}
+	for _, sidecar := range []string{"fluent-bit", "prometheus", "grafana", "jaeger", "db-backup", "file-processor", "flower", "adminer", "redisinsight"} {
+		if containsString(services, sidecar) {
+			t.Errorf("Services() = %v, should not include %s in minimal mode", services, sidecar)
+		}
+	}
+
+	devcontainer := NewDevcontainerGenerator().buildConfig(detection, "shop")
+	for _, port := range []int{24224, 9090, 3001} {
+		if containsPort(devcontainer.ForwardPorts, port) {
+			t.Errorf("ForwardPorts = %v, should not forward %d in minimal mode", devcontainer.ForwardPorts, port)
+		}
+	}
+
+	// The detected libraries still add their sidecars without minimal mode
+	detection.Sidecars = models.SidecarPolicy{}
+	services, err = NewComposeGenerator().Services(detection, "shop")
+	if err != nil {
+		t.Fatalf("Services() error = %v", err)
+	}
+	for _, sidecar := range []string{"fluent-bit", "prometheus", "jaeger", "db-backup", "file-processor", "flower"} {
+		if !containsString(services, sidecar) {
+			t.Errorf("Services() = %v, want %s without minimal mode", services, sidecar)
+		}
+	}
+}
+
+// TestComposeGenerator_SidecarPolicy tests adding and leaving out sidecars with --with and --without.
+func TestComposeGenerator_SidecarPolicy(t *testing.T) {
+	detection := &models.Detection{
+		Language: "go",
+		Version:  "1.23",
+		Services: []string{"postgres"},
+		Capabilities: models.Capabilities{
+			TracingLibraries: []string{"opentelemetry"},
+		},
+		Sidecars: models.SidecarPolicy{
+			With:    []string{models.SidecarMetrics},
+			Without: []string{models.SidecarTracing, models.SidecarBackup},
+		},
+	}
+
+	services, err := NewComposeGenerator().Services(detection, "shop")
+	if err != nil {
+		t.Fatalf("Services() error = %v", err)
+	}
+	for _, want := range []string{"prometheus", "grafana", "adminer"} {
+		if !containsString(services, want) {
+			t.Errorf("Services() = %v, should include %s", services, want)
+		}
+	}
+	for _, sidecar := range []string{"jaeger", "db-backup"} {
+		if containsString(services, sidecar) {
+			t.Errorf("Services() = %v, should leave out %s", services, sidecar)
+		}
+	}
+	if NewBackupSidecarGenerator().ShouldGenerate(detection) || !NewMetricsSidecarGenerator().ShouldGenerate(detection) {
+		t.Error("ShouldGenerate() should follow the sidecar policy")
+	}
+
+	// --with keeps a sidecar in minimal mode and Codespaces, --without wins over --with
+	tests := []struct {
+		policy models.SidecarPolicy
+		want   bool
+	}{
+		{policy: models.SidecarPolicy{}, want: true},
+		{policy: models.SidecarPolicy{Minimal: true}, want: false},
+		{policy: models.SidecarPolicy{Minimal: true, With: []string{models.SidecarAdmin}}, want: true},
+		{policy: models.SidecarPolicy{Codespaces: true}, want: false},
+		{policy: models.SidecarPolicy{Codespaces: true, With: []string{models.SidecarAdmin}}, want: true},
+		{policy: models.SidecarPolicy{With: []string{models.SidecarAdmin}, Without: []string{models.SidecarAdmin}}, want: false},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.Allows(models.SidecarAdmin, true); got != tt.want {
+			t.Errorf("%+v Allows(admin) = %v, want %v", tt.policy, got, tt.want)
+		}
+	}
+}
+
+// TestSidecarPolicy_Validate tests rejecting unknown and conflicting sidecar names.
+func TestSidecarPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  models.SidecarPolicy
+		wantErr bool
+	}{
+		{name: "empty"},
+		{name: "known sidecars", policy: models.SidecarPolicy{With: []string{"tracing"}, Without: []string{"backup", "admin"}}},
+		{name: "unknown sidecar", policy: models.SidecarPolicy{With: []string{"kafka"}}, wantErr: true},
+		{name: "with and without", policy: models.SidecarPolicy{With: []string{"metrics"}, Without: []string{"metrics"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
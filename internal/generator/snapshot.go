@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// SnapshotScript and RestoreSnapshotScript take and restore named snapshots of
+// the development PostgreSQL databases, relative to the project root.
+const (
+	SnapshotScript        = ".devcontainer/scripts/snapshot.sh"
+	RestoreSnapshotScript = ".devcontainer/scripts/restore-snapshot.sh"
+)
+
+// SnapshotConfig holds the configuration for generating the snapshot scripts.
+type SnapshotConfig struct {
+	// Service is the PostgreSQL service in docker-compose.yml
+	Service string
+
+	// User is the superuser the scripts run psql as
+	User string
+
+	// Databases are the development database and the additional ones, all
+	// snapshotted together
+	Databases []string
+}
+
+// SnapshotGenerator generates snapshot.sh and restore-snapshot.sh, which copy
+// the development databases inside PostgreSQL to branch and reset their state.
+type SnapshotGenerator struct {
+	output
+}
+
+// NewSnapshotGenerator creates a new snapshot scripts generator.
+func NewSnapshotGenerator() *SnapshotGenerator {
+	return &SnapshotGenerator{}
+}
+
+// ShouldGenerate returns true if the stack runs PostgreSQL.
+func (g *SnapshotGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.NeedsCompose() &&
+		(detection.HasService("postgres") || detection.GetVectorStore() == "pgvector")
+}
+
+// Files returns the files Generate writes, relative to the project root.
+func (g *SnapshotGenerator) Files() []string {
+	return []string{SnapshotScript, RestoreSnapshotScript}
+}
+
+// Generate creates .devcontainer/scripts/snapshot.sh and restore-snapshot.sh.
+func (g *SnapshotGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	scriptsDir := filepath.Join(projectPath, ".devcontainer", "scripts")
+	if err := g.fs().MkdirAll(scriptsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create scripts directory: %w", err)
+	}
+
+	for _, script := range g.Files() {
+		content, err := g.GenerateContent(detection, projectName, script)
+		if err != nil {
+			return err
+		}
+		if err := g.fs().WriteFile(filepath.Join(projectPath, filepath.FromSlash(script)), content, 0755); err != nil {
+			return fmt.Errorf("failed to write %s: %w", filepath.Base(script), err)
+		}
+	}
+	return nil
+}
+
+// GenerateContent returns one of the scripts, SnapshotScript or
+// RestoreSnapshotScript, without writing to disk.
+func (g *SnapshotGenerator) GenerateContent(detection *models.Detection, projectName, script string) ([]byte, error) {
+	config, err := g.buildConfig(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	switch script {
+	case SnapshotScript:
+		return renderTemplate("postgres/snapshot.sh.tmpl", config)
+	case RestoreSnapshotScript:
+		return renderTemplate("postgres/restore-snapshot.sh.tmpl", config)
+	}
+	return nil, fmt.Errorf("unknown snapshot script %s", script)
+}
+
+// buildConfig takes the service, user, and databases from the compose stack
+// generated for the detection, so an imported PostgreSQL is snapshotted under
+// its own name and credentials.
+func (g *SnapshotGenerator) buildConfig(detection *models.Detection, projectName string) (*SnapshotConfig, error) {
+	compose, err := NewComposeGenerator().config(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &SnapshotConfig{
+		Service:   "postgres",
+		User:      compose.Postgres.User,
+		Databases: []string{compose.Postgres.Database},
+	}
+	for _, service := range compose.Services {
+		if service.Name == "postgres" {
+			config.Service = service.ComposeName()
+		}
+	}
+	for _, database := range compose.PostgresDatabases {
+		config.Databases = append(config.Databases, database.Name)
+	}
+	return config, nil
+}
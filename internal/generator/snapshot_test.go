@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestSnapshotGenerator tests the snapshot and restore scripts for the
+// development databases.
+func TestSnapshotGenerator(t *testing.T) {
+	gen := NewSnapshotGenerator()
+	detection := &models.Detection{
+		Language:          "node",
+		Version:           "20",
+		Services:          []string{"postgres", "redis"},
+		PostgresDatabases: []string{"analytics"},
+	}
+	if !gen.ShouldGenerate(detection) {
+		t.Fatal("ShouldGenerate() = false, want true with PostgreSQL")
+	}
+	if gen.ShouldGenerate(&models.Detection{Language: "go", Version: "1.23", Services: []string{"redis"}}) {
+		t.Error("ShouldGenerate() = true, want false without PostgreSQL")
+	}
+
+	tmpDir := t.TempDir()
+	if err := gen.Generate(detection, tmpDir, "shop"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	snapshot := readScript(t, filepath.Join(tmpDir, filepath.FromSlash(SnapshotScript)))
+	for _, want := range []string{
+		`SERVICE="postgres"`,
+		`DATABASES=("shop_dev" "analytics")`,
+		`psql -U "postgres" -d postgres`,
+		`sql "CREATE DATABASE \"${target}\" TEMPLATE \"${source}\""`,
+		`trap "allow_connections '${source}' true" EXIT`,
+	} {
+		if !strings.Contains(snapshot, want) {
+			t.Errorf("snapshot.sh should contain %q, got:\n%s", want, snapshot)
+		}
+	}
+
+	restore := readScript(t, filepath.Join(tmpDir, filepath.FromSlash(RestoreSnapshotScript)))
+	for _, want := range []string{
+		`sql "CREATE DATABASE \"${db}__restoring\" TEMPLATE \"${db}${SUFFIX}${name}\""`,
+		`sql "ALTER DATABASE \"${db}__restoring\" RENAME TO \"${db}\""`,
+	} {
+		if !strings.Contains(restore, want) {
+			t.Errorf("restore-snapshot.sh should contain %q, got:\n%s", want, restore)
+		}
+	}
+
+	// The usage lines printed by usage() are the ones documented in the header
+	if usage := commentLines(snapshot, 10, 13); !strings.HasPrefix(usage[0], "# Usage:") || !strings.Contains(usage[3], "snapshot.sh delete <name>") {
+		t.Errorf("snapshot.sh usage lines = %q", usage)
+	}
+	if usage := commentLines(restore, 8, 9); !strings.HasPrefix(usage[0], "# Usage:") || !strings.Contains(usage[1], "restore-snapshot.sh <name>") {
+		t.Errorf("restore-snapshot.sh usage lines = %q", usage)
+	}
+}
+
+// TestSnapshotGenerator_Imported tests that an imported PostgreSQL is
+// snapshotted under its own service name and credentials.
+func TestSnapshotGenerator_Imported(t *testing.T) {
+	detection := &models.Detection{
+		Language: "go",
+		Version:  "1.23",
+		Services: []string{"postgres"},
+		ExistingCompose: &models.ExistingCompose{
+			File: "docker-compose.yml",
+			Services: []models.ExistingService{{
+				Name: "db",
+				Role: "postgres",
+				Definition: map[string]interface{}{
+					"image":       "postgres:15",
+					"environment": map[string]interface{}{"POSTGRES_USER": "shop", "POSTGRES_PASSWORD": "secret", "POSTGRES_DB": "shop"},
+				},
+			}},
+		},
+	}
+
+	content, err := NewSnapshotGenerator().GenerateContent(detection, "shop", SnapshotScript)
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	for _, want := range []string{`SERVICE="db"`, `psql -U "shop"`, `DATABASES=("shop")`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("snapshot.sh should contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
+// readScript reads a generated script, checking that it is executable and
+// that bash can parse it.
+func readScript(t *testing.T, path string) string {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Failed to stat %s: %v", path, err)
+	}
+	if info.Mode().Perm()&0111 == 0 {
+		t.Errorf("%s should be executable, mode %v", filepath.Base(path), info.Mode())
+	}
+	if bash, err := exec.LookPath("bash"); err == nil {
+		if out, err := exec.Command(bash, "-n", path).CombinedOutput(); err != nil {
+			t.Errorf("bash -n %s: %v\n%s", filepath.Base(path), err, out)
+		}
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return string(content)
+}
+
+// commentLines returns lines first to last (1-based) of a script.
+func commentLines(script string, first, last int) []string {
+	lines := strings.Split(script, "\n")
+	return lines[first-1 : last]
+}
@@ -0,0 +1,200 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// Task runner formats, as named by dockstart tasks --format.
+const (
+	// TasksJust is a Justfile for just (https://just.systems)
+	TasksJust = "just"
+
+	// TasksEarthly is an Earthfile for Earthly (https://earthly.dev)
+	TasksEarthly = "earthly"
+
+	// TasksMake is a Makefile for make
+	TasksMake = "make"
+)
+
+// TaskFormats lists the supported task runner formats.
+var TaskFormats = []string{TasksJust, TasksEarthly, TasksMake}
+
+// taskFiles are the task files, relative to the project root.
+var taskFiles = map[string]string{
+	TasksJust:    "Justfile",
+	TasksEarthly: "Earthfile",
+	TasksMake:    "Makefile",
+}
+
+// taskTemplates are the task file templates.
+var taskTemplates = map[string]string{
+	TasksJust:    "Justfile.tmpl",
+	TasksEarthly: "Earthfile.tmpl",
+	TasksMake:    "Makefile.tmpl",
+}
+
+// seedCommands load the seed data, by migration tool.
+var seedCommands = map[string]string{
+	"prisma":    "npx prisma db seed",
+	"knex":      "npx knex seed:run",
+	"sequelize": "npx sequelize-cli db:seed:all",
+}
+
+// restoreDatabases are the databases the backup sidecar has a restore script
+// for (restore-<database>.sh).
+var restoreDatabases = []string{"postgres", "mysql", "redis", "sqlite"}
+
+// TasksConfig holds the configuration for generating a task file.
+type TasksConfig struct {
+	// Name is the project name
+	Name string
+
+	// ComposeFile and ComposeProject are the generated compose file, relative
+	// to the project root, and the compose project the devcontainer runs under
+	ComposeFile    string
+	ComposeProject string
+
+	// Compose is the docker compose invocation every task runs, pinned to
+	// ComposeFile and ComposeProject
+	Compose string
+
+	// NonRoot exports the host UID/GID the generated Dockerfiles are built with
+	NonRoot bool
+
+	// Test adds the test task, which runs the one-shot test service
+	Test bool
+
+	// Backup adds the backup-now and restore tasks
+	Backup bool
+
+	// RestoreDatabases are the databases the backup sidecar backs up and can
+	// restore (e.g., "postgres", "redis")
+	RestoreDatabases []string
+
+	// Postgres is the PostgreSQL service psql connects to, or empty without one
+	Postgres string
+
+	// PostgresUser and PostgresDatabase are the credentials psql connects with
+	PostgresUser     string
+	PostgresDatabase string
+
+	// Redis is the Redis service redis-cli connects to, or empty without one
+	Redis string
+
+	// Migrate applies pending migrations in the app container, or is empty
+	// without migrations
+	Migrate string
+
+	// Seed loads the seed data in the app container, or is empty when the
+	// migration tool has no seed command
+	Seed string
+
+	// AppPort is the port the app is published on
+	AppPort int
+
+	// URLs are the browser-reachable endpoints of the stack
+	URLs []ServiceURL
+}
+
+// TasksGenerator generates Justfiles, Earthfiles, and Makefiles with the
+// tasks for the generated compose stack.
+type TasksGenerator struct {
+	output
+}
+
+// NewTasksGenerator creates a new task file generator.
+func NewTasksGenerator() *TasksGenerator {
+	return &TasksGenerator{}
+}
+
+// TasksFile returns the task file of a format, relative to the project root.
+func TasksFile(format string) string {
+	return taskFiles[format]
+}
+
+// Generate writes the task file for format. composeProject is the compose
+// project name the devcontainer runs under (e.g., "myapp_devcontainer").
+func (g *TasksGenerator) Generate(detection *models.Detection, projectPath, projectName, composeProject, format string) error {
+	content, err := g.GenerateContent(detection, projectName, composeProject, format)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(projectPath, TasksFile(format))
+	if err := g.fs().WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", TasksFile(format), err)
+	}
+	return nil
+}
+
+// GenerateContent returns the task file for format without writing to disk.
+func (g *TasksGenerator) GenerateContent(detection *models.Detection, projectName, composeProject, format string) ([]byte, error) {
+	name, ok := taskTemplates[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown task format %q: expected one of %s", format, strings.Join(TaskFormats, ", "))
+	}
+
+	config, err := g.buildConfig(detection, projectName, composeProject)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderTemplate(name, config)
+}
+
+// buildConfig creates the task configuration from the compose stack generated
+// for the detection, so the tasks name the services as they are in
+// docker-compose.yml.
+func (g *TasksGenerator) buildConfig(detection *models.Detection, projectName, composeProject string) (*TasksConfig, error) {
+	compose, err := NewComposeGenerator().config(detection, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &TasksConfig{
+		Name:           projectName,
+		ComposeFile:    ".devcontainer/docker-compose.yml",
+		ComposeProject: composeProject,
+		NonRoot:        compose.NonRoot,
+		Test:           compose.TestRunner.Enabled,
+		Seed:           seedCommands[detection.MigrationTool],
+		AppPort:        detection.GetAppPort(),
+		URLs:           ServiceURLs(detection),
+	}
+	config.Compose = fmt.Sprintf("docker compose -f %s -p %s", config.ComposeFile, config.ComposeProject)
+	if NewMigrateGenerator().ShouldGenerate(detection) {
+		config.Migrate = "bash " + MigrateScript
+	}
+
+	if compose.BackupSidecar.Enabled {
+		has := map[string]bool{
+			"postgres": compose.BackupSidecar.HasPostgres,
+			"mysql":    compose.BackupSidecar.HasMySQL,
+			"redis":    compose.BackupSidecar.HasRedis,
+			"sqlite":   compose.BackupSidecar.HasSQLite,
+		}
+		for _, database := range restoreDatabases {
+			if has[database] {
+				config.RestoreDatabases = append(config.RestoreDatabases, database)
+			}
+		}
+		config.Backup = len(config.RestoreDatabases) > 0
+	}
+
+	for _, service := range compose.Services {
+		switch service.Name {
+		case "postgres":
+			config.Postgres = service.ComposeName()
+			config.PostgresUser = compose.Postgres.User
+			config.PostgresDatabase = compose.Postgres.Database
+		case "redis":
+			config.Redis = service.ComposeName()
+		}
+	}
+
+	return config, nil
+}
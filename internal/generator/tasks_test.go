@@ -0,0 +1,190 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// tasksDetection is a Node.js project with PostgreSQL, Redis, and a test command.
+func tasksDetection() *models.Detection {
+	return &models.Detection{
+		Language:    "node",
+		Version:     "20",
+		TestCommand: "npm test",
+		Services:    []string{"postgres", "redis"},
+	}
+}
+
+// TestTasksGenerator_Justfile tests the tasks in the generated Justfile.
+func TestTasksGenerator_Justfile(t *testing.T) {
+	content, err := NewTasksGenerator().GenerateContent(tasksDetection(), "shop", "shop_devcontainer", TasksJust)
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	got := string(content)
+
+	wantStrings := []string{
+		`compose := "docker compose -f .devcontainer/docker-compose.yml -p shop_devcontainer"`,
+		"up:\n    {{compose}} up -d --build\n",
+		"down:\n    {{compose}} down\n",
+		"logs *services:\n    {{compose}} logs -f {{services}}\n",
+		"test:\n    {{compose}} --profile test run --rm --build test\n",
+		"backup-now:\n    {{compose}} exec db-backup /usr/local/bin/backup.sh\n",
+		"restore database file:\n    {{compose}} exec db-backup restore-{{database}}.sh /backup/{{file}}\n",
+		"into postgres, redis\n",
+		"psql:\n    {{compose}} exec postgres psql -U postgres -d shop_dev\n",
+		"redis-cli:\n    {{compose}} exec redis redis-cli\n",
+	}
+	for _, want := range wantStrings {
+		if !strings.Contains(got, want) {
+			t.Errorf("Justfile missing %q\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "USER_UID") {
+		t.Error("Justfile exports USER_UID for a root setup")
+	}
+}
+
+// TestTasksGenerator_Earthfile tests the tasks in the generated Earthfile.
+func TestTasksGenerator_Earthfile(t *testing.T) {
+	detection := tasksDetection()
+	detection.NonRoot = true
+	content, err := NewTasksGenerator().GenerateContent(detection, "shop", "shop_devcontainer", TasksEarthly)
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	got := string(content)
+
+	compose := "USER_UID=$(id -u) USER_GID=$(id -g) docker compose -f .devcontainer/docker-compose.yml -p shop_devcontainer"
+	wantStrings := []string{
+		"VERSION 0.8\n",
+		"up:\n    LOCALLY\n    RUN " + compose + " up -d --build\n",
+		"    ARG services=\"\"\n    RUN " + compose + " logs -f $services\n",
+		"    ARG --required database\n    ARG --required file\n    RUN " + compose + " exec -T db-backup restore-$database.sh /backup/$file\n",
+		"    RUN --interactive " + compose + " exec postgres psql -U postgres -d shop_dev\n",
+		"    RUN --interactive " + compose + " exec redis redis-cli\n",
+	}
+	for _, want := range wantStrings {
+		if !strings.Contains(got, want) {
+			t.Errorf("Earthfile missing %q\n%s", want, got)
+		}
+	}
+}
+
+// TestTasksGenerator_OmitsUnusedTasks tests that tasks for services and
+// sidecars the project doesn't run are left out.
+func TestTasksGenerator_OmitsUnusedTasks(t *testing.T) {
+	detection := &models.Detection{Language: "go", Version: "1.23", Services: []string{"redis"}}
+	detection.Sidecars.Without = []string{models.SidecarBackup}
+	for _, format := range []string{TasksJust, TasksEarthly} {
+		content, err := NewTasksGenerator().GenerateContent(detection, "api", "api_devcontainer", format)
+		if err != nil {
+			t.Fatalf("GenerateContent(%s) error = %v", format, err)
+		}
+		got := string(content)
+
+		if !strings.Contains(got, "\nredis-cli:\n") {
+			t.Errorf("%s missing the redis-cli task\n%s", format, got)
+		}
+		for _, task := range []string{"test:", "psql:", "backup-now:", "restore"} {
+			if strings.Contains(got, "\n"+task) {
+				t.Errorf("%s has the %s task without its service\n%s", format, task, got)
+			}
+		}
+	}
+}
+
+// TestTasksGenerator_ImportedPostgres tests that psql connects to a
+// PostgreSQL service imported from the project's own compose file.
+func TestTasksGenerator_ImportedPostgres(t *testing.T) {
+	config, err := NewTasksGenerator().buildConfig(&models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Services: []string{"postgres"},
+		ExistingCompose: &models.ExistingCompose{
+			File:     "docker-compose.yml",
+			Services: []models.ExistingService{{Name: "db", Role: "postgres", Definition: map[string]interface{}{"image": "postgres:15"}}},
+		},
+	}, "shop", "shop_devcontainer")
+	if err != nil {
+		t.Fatalf("buildConfig() error = %v", err)
+	}
+	if config.Postgres != "db" {
+		t.Errorf("Postgres = %q, want the imported db service", config.Postgres)
+	}
+}
+
+// TestTasksGenerator_Generate tests writing the task file to the project root.
+func TestTasksGenerator_Generate(t *testing.T) {
+	dir := t.TempDir()
+	if err := NewTasksGenerator().Generate(tasksDetection(), dir, "shop", "shop_devcontainer", TasksEarthly); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Earthfile")); err != nil {
+		t.Errorf("Earthfile not written: %v", err)
+	}
+
+	if _, err := NewTasksGenerator().GenerateContent(tasksDetection(), "shop", "shop_devcontainer", "rake"); err == nil {
+		t.Error("GenerateContent() accepted an unknown format")
+	}
+}
+
+// TestTasksGenerator_Makefile tests the targets in the generated Makefile.
+func TestTasksGenerator_Makefile(t *testing.T) {
+	detection := tasksDetection()
+	detection.MigrationTool = "prisma"
+	detection.MigrateCommand = "npx prisma migrate deploy"
+	detection.Sidecars.With = []string{models.SidecarMetrics}
+	content, err := NewTasksGenerator().GenerateContent(detection, "shop", "shop_devcontainer", TasksMake)
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	got := string(content)
+
+	wantStrings := []string{
+		"COMPOSE_PROJECT ?= shop_devcontainer\n",
+		"COMPOSE := docker compose -f $(COMPOSE_FILE) -p $(COMPOSE_PROJECT)\n",
+		"APP_PORT := 3000\n",
+		".PHONY: help dev stop rebuild migrate seed backup dashboards\n",
+		"dev: ## Start the dev environment in the background\n\t$(COMPOSE) up -d --build\n",
+		"stop: ## Stop the dev environment (data volumes are kept)\n\t$(COMPOSE) down\n",
+		"\t$(COMPOSE) build --pull --no-cache\n\t$(COMPOSE) up -d --force-recreate\n",
+		"\t$(COMPOSE) exec -w /workspace app bash .devcontainer/scripts/migrate.sh\n",
+		"\t$(COMPOSE) exec -w /workspace app npx prisma db seed\n",
+		"\t$(COMPOSE) exec db-backup /usr/local/bin/backup.sh\n",
+		`"App" "http://localhost:$(APP_PORT)"`,
+		`"Grafana" "http://localhost:3001"`,
+	}
+	for _, want := range wantStrings {
+		if !strings.Contains(got, want) {
+			t.Errorf("Makefile missing %q\n%s", want, got)
+		}
+	}
+}
+
+// TestTasksGenerator_MakefileWithoutMigrations tests that the migrate, seed,
+// and backup targets are left out when the project has nothing to run them on.
+func TestTasksGenerator_MakefileWithoutMigrations(t *testing.T) {
+	detection := &models.Detection{Language: "go", Version: "1.23", Services: []string{"nats"}, NonRoot: true}
+	content, err := NewTasksGenerator().GenerateContent(detection, "api", "api_devcontainer", TasksMake)
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	got := string(content)
+
+	if !strings.Contains(got, ".PHONY: help dev stop rebuild dashboards\n") {
+		t.Errorf("unexpected .PHONY targets\n%s", got)
+	}
+	for _, target := range []string{"migrate:", "seed:", "backup:"} {
+		if strings.Contains(got, "\n"+target) {
+			t.Errorf("Makefile has the %s target\n%s", target, got)
+		}
+	}
+	if !strings.Contains(got, "export USER_UID ?= $(shell id -u)\n") {
+		t.Errorf("Makefile doesn't export the host UID for a non-root setup\n%s", got)
+	}
+}
@@ -2,17 +2,46 @@
 package generator
 
 import (
-	"embed"
+	"bytes"
+	"io/fs"
 	"text/template"
+
+	"github.com/jpequegn/dockstart/internal/generator/templates"
 )
 
-// templates embeds all template files at compile time.
-// This means the templates are included in the binary - no external files needed.
-//
-//go:embed templates/*.tmpl templates/processor/*.tmpl templates/grafana/datasources/*.tmpl templates/grafana/dashboards/*.tmpl
-var templatesFS embed.FS
+// templateLoader loads the templates every generator renders: the embedded
+// ones, with any overlay set by UseTemplateOverlay on top.
+var templateLoader = templates.Default
+
+// UseTemplateOverlay makes the generators render user templates from overlay
+// in place of the embedded templates of the same name. It fails, leaving the
+// templates in use unchanged, if a template in overlay doesn't replace an
+// embedded one or doesn't parse.
+func UseTemplateOverlay(overlay fs.FS) error {
+	loader, err := templateLoader.WithOverlay(overlay)
+	if err != nil {
+		return err
+	}
+	templateLoader = loader
+	return nil
+}
+
+// renderTemplate loads a template and executes it with data. Failures are
+// returned as a *TemplateError naming the template.
+func renderTemplate(name string, data any) ([]byte, error) {
+	tmpl, err := loadTemplate(name)
+	if err != nil {
+		return nil, &TemplateError{Template: name, Err: err}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, &TemplateError{Template: name, Err: err}
+	}
+	return buf.Bytes(), nil
+}
 
-// loadTemplate loads and parses a template from the embedded filesystem.
+// loadTemplate loads and parses a template (e.g., "docker-compose.yml.tmpl").
 func loadTemplate(name string) (*template.Template, error) {
-	return template.ParseFS(templatesFS, "templates/"+name)
+	return templateLoader.Load(name)
 }
@@ -0,0 +1,148 @@
+// Package templates embeds the templates the generators render, and loads
+// them by name with user templates optionally layered on top.
+//
+// Every embedded template is parsed when the package is initialized, so a
+// template with a syntax error fails the first test run instead of the first
+// user whose project happens to need it.
+package templates
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// files embeds all template files at compile time.
+// This means the templates are included in the binary - no external files needed.
+//
+//go:embed *.tmpl */*.tmpl */*/*.tmpl
+var files embed.FS
+
+// names is the sorted names of the embedded templates.
+var names []string
+
+func init() {
+	err := fs.WalkDir(files, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		names = append(names, name)
+		return nil
+	})
+	sort.Strings(names)
+	if err == nil {
+		err = Default.Validate()
+	}
+	if err != nil {
+		panic(fmt.Sprintf("dockstart: invalid embedded templates: %v", err))
+	}
+}
+
+// Files returns the embedded templates, named as Loader.Load expects.
+func Files() fs.FS {
+	return files
+}
+
+// Names returns the names of the embedded templates, sorted
+// (e.g., "docker-compose.yml.tmpl", "grafana/dashboards/provider.yml.tmpl").
+func Names() []string {
+	return append([]string(nil), names...)
+}
+
+// Has reports whether name is an embedded template.
+func Has(name string) bool {
+	i := sort.SearchStrings(names, name)
+	return i < len(names) && names[i] == name
+}
+
+// Hash returns the SHA-256 of the embedded templates' names and content,
+// which changes whenever a template does.
+func Hash() (string, error) {
+	h := sha256.New()
+	for _, name := range names {
+		data, err := files.ReadFile(name)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash templates: %w", err)
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", name, len(data))
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Loader loads templates by name, from the first overlay that has the
+// template, or from the embedded templates.
+type Loader struct {
+	// overlays hold user templates, searched in order before the embedded ones
+	overlays []fs.FS
+}
+
+// Default loads the embedded templates only.
+var Default = &Loader{}
+
+// WithOverlay returns a loader that reads templates from overlay before the
+// ones this loader reads. The overlay is laid out like the embedded
+// templates (e.g., "grafana/dashboards/app-metrics.json.tmpl"); every
+// template in it must replace an embedded template and parse.
+func (l *Loader) WithOverlay(overlay fs.FS) (*Loader, error) {
+	err := fs.WalkDir(overlay, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(name, ".tmpl") {
+			return err
+		}
+		if !Has(name) {
+			return fmt.Errorf("template override %s does not replace a dockstart template", name)
+		}
+		if _, err := parse(overlay, name); err != nil {
+			return fmt.Errorf("template override %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	overlays := append([]fs.FS{overlay}, l.overlays...)
+	return &Loader{overlays: overlays}, nil
+}
+
+// Load loads and parses the template called name.
+func (l *Loader) Load(name string) (*template.Template, error) {
+	if !Has(name) {
+		return nil, fmt.Errorf("unknown template %q", name)
+	}
+	for _, overlay := range l.overlays {
+		if _, err := fs.Stat(overlay, name); err == nil {
+			return parse(overlay, name)
+		}
+	}
+	return parse(files, name)
+}
+
+// Validate parses every template the loader would load.
+func (l *Loader) Validate() error {
+	for _, name := range names {
+		if _, err := l.Load(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parse parses a template from fsys, named after its file like template.ParseFS.
+func parse(fsys fs.FS, name string) (*template.Template, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(path.Base(name)).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,128 @@
+package templates
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// TestNames tests that every template directory is embedded.
+func TestNames(t *testing.T) {
+	names := Names()
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("Names() should be sorted, got %v", names)
+	}
+	for _, name := range []string{
+		"docker-compose.yml.tmpl",
+		"backup/postgres-backup.sh.tmpl",
+		"grafana/dashboards/app-metrics.json.tmpl",
+		"processor/process-files.sh.tmpl",
+	} {
+		if !Has(name) {
+			t.Errorf("%s should be embedded", name)
+		}
+	}
+	if Has("templates.go") {
+		t.Error("only .tmpl files should be embedded")
+	}
+}
+
+// TestLoader_Load tests loading embedded templates.
+func TestLoader_Load(t *testing.T) {
+	tmpl, err := Default.Load("gitattributes.tmpl")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if tmpl.Name() != "gitattributes.tmpl" {
+		t.Errorf("Name() = %q, want the file name", tmpl.Name())
+	}
+
+	if _, err := Default.Load("missing.tmpl"); err == nil || !strings.Contains(err.Error(), `unknown template "missing.tmpl"`) {
+		t.Errorf("Load(missing.tmpl) error = %v, want unknown template", err)
+	}
+}
+
+// TestLoader_WithOverlay tests that user templates replace embedded ones.
+func TestLoader_WithOverlay(t *testing.T) {
+	overlay := fstest.MapFS{
+		"gitattributes.tmpl": {Data: []byte("* text=auto {{.}}\n")},
+		"README.md":          {Data: []byte("not a template")},
+	}
+	loader, err := Default.WithOverlay(overlay)
+	if err != nil {
+		t.Fatalf("WithOverlay() error = %v", err)
+	}
+
+	tmpl, err := loader.Load("gitattributes.tmpl")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, "eol=lf"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if buf.String() != "* text=auto eol=lf\n" {
+		t.Errorf("overlay template rendered %q", buf.String())
+	}
+
+	// Templates the overlay doesn't have still come from the binary
+	if _, err := loader.Load("docker-compose.yml.tmpl"); err != nil {
+		t.Errorf("Load(docker-compose.yml.tmpl) error = %v", err)
+	}
+	if err := loader.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	// Later overlays take precedence
+	later, err := loader.WithOverlay(fstest.MapFS{"gitattributes.tmpl": {Data: []byte("later")}})
+	if err != nil {
+		t.Fatalf("WithOverlay() error = %v", err)
+	}
+	tmpl, _ = later.Load("gitattributes.tmpl")
+	buf.Reset()
+	if err := tmpl.Execute(&buf, nil); err != nil || buf.String() != "later" {
+		t.Errorf("later overlay rendered %q, %v", buf.String(), err)
+	}
+}
+
+// TestLoader_WithOverlayErrors tests that invalid overlays are rejected.
+func TestLoader_WithOverlayErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		overlay fstest.MapFS
+		wantErr string
+	}{
+		{
+			name:    "unknown template",
+			overlay: fstest.MapFS{"grafana/custom.json.tmpl": {Data: []byte("{}")}},
+			wantErr: "grafana/custom.json.tmpl does not replace a dockstart template",
+		},
+		{
+			name:    "syntax error",
+			overlay: fstest.MapFS{"Dockerfile.tmpl": {Data: []byte("FROM {{.Image")}},
+			wantErr: "template override Dockerfile.tmpl:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Default.WithOverlay(tt.overlay); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("WithOverlay() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestHash tests that the hash is stable and covers the templates.
+func TestHash(t *testing.T) {
+	first, err := Hash()
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	second, _ := Hash()
+	if first != second || len(first) != 64 {
+		t.Errorf("Hash() = %q then %q, want the same SHA-256", first, second)
+	}
+}
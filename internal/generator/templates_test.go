@@ -0,0 +1,240 @@
+package generator
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/generator/templates"
+	"github.com/jpequegn/dockstart/internal/lint"
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// openedFS records the files opened through it.
+type openedFS struct {
+	fs.FS
+
+	mu     sync.Mutex
+	opened map[string]bool
+}
+
+func (f *openedFS) Open(name string) (fs.File, error) {
+	f.mu.Lock()
+	f.opened[name] = true
+	f.mu.Unlock()
+	return f.FS.Open(name)
+}
+
+// renderFixtures are representative detections that, between them, make the
+// generators render every embedded template.
+func renderFixtures() map[string]*models.Detection {
+	return map[string]*models.Detection{
+		"node with every sidecar": {
+			Language:           "node",
+			Version:            "20",
+			Services:           []string{"postgres", "redis", "clickhouse"},
+			PostgresDatabases:  []string{"analytics"},
+			PostgresExtensions: []string{"pg_trgm"},
+			PostgresRoles:      []models.PostgresRole{{Name: "reporting", Password: "reporting", ReadOnly: true}},
+			ProdImport: &models.ProdImportOptions{
+				SourceEnv: "PROD_DATABASE_URL",
+				Anonymize: []models.AnonymizedColumn{{Table: "users", Column: "email", Strategy: "email"}},
+			},
+			Capabilities: models.Capabilities{
+				LoggingLibraries:    []string{"winston"},
+				QueueLibraries:      []string{"bullmq"},
+				SchedulerLibraries:  []string{"node-cron"},
+				FileUploadLibraries: []string{"multer"},
+				MetricsLibraries:    []string{"prom-client"},
+				TracingLibraries:    []string{"@opentelemetry/sdk-node"},
+				AuthLibraries:       []string{"passport"},
+				AWSServices:         []string{"s3", "sqs"},
+				MailLibraries:       []string{"nodemailer"},
+			},
+			MigrationTool: "prisma",
+			LogFormat:     "text",
+			Logging:       models.LoggingOptions{FileOutput: true},
+			Worker:        models.WorkerOptions{DeadLetter: models.DeadLetterOptions{Enabled: true}},
+			FileProcessor: models.FileProcessorOptions{
+				Types:   []string{"images", "documents", "video"},
+				Scan:    true,
+				Notify:  "webhook",
+				Storage: "s3",
+			},
+			Persistence:          models.PersistenceOptions{Dotfiles: "https://github.com/example/dotfiles"},
+			Testing:              models.TestingOptions{Isolation: "database"},
+			ChaosProxy:           true,
+			StatusPage:           true,
+			Windows:              true,
+			Environments:         []string{models.EnvDev, models.EnvTest, models.EnvStaging},
+			ProductionDockerfile: &models.ProductionDockerfile{File: "Dockerfile", Target: "production"},
+		},
+		"go with mysql": {
+			Language:     "go",
+			Version:      "1.23",
+			Services:     []string{"mysql", "redis"},
+			Capabilities: models.Capabilities{QueueLibraries: []string{"asynq"}, MailLibraries: []string{"go-mail"}},
+			RemoteDocker: true,
+		},
+		"python": {
+			Language:       "python",
+			Version:        "3.12",
+			PackageManager: "poetry",
+			InstallCommand: "poetry install",
+			Services:       []string{"postgres", "redis"},
+			Capabilities:   models.Capabilities{QueueLibraries: []string{"celery"}, MailLibraries: []string{"fastapi-mail"}},
+			Prebuild:       &models.PrebuildOptions{Image: "ghcr.io/acme/shop-devcontainer"},
+		},
+		"rust": {
+			Language:     "rust",
+			Version:      "1.83",
+			Capabilities: models.Capabilities{QueueLibraries: []string{"apalis"}, MailLibraries: []string{"lettre"}},
+		},
+	}
+}
+
+// generateAll runs every generator for detection, writing under dir through fsys.
+func generateAll(t *testing.T, detection *models.Detection, dir string, fsys FS) {
+	t.Helper()
+	project := "shop"
+	generators := map[string]interface {
+		SetFS(FS)
+		Generate(detection *models.Detection, projectPath, projectName string) error
+	}{
+		"Dockerfile":         NewDockerfileGenerator(),
+		"devcontainer.json":  NewDevcontainerGenerator(),
+		"docker-compose.yml": NewComposeGenerator(),
+		"README":             NewReadmeGenerator(),
+		"migrate.sh":         NewMigrateGenerator(),
+		"dotfiles.sh":        NewDotfilesGenerator(),
+		".gitattributes":     NewLineEndingsGenerator(),
+		"fluent-bit":         NewLogSidecarGenerator(),
+		"metrics":            NewMetricsSidecarGenerator(),
+		"backup":             NewBackupSidecarGenerator(),
+		"file-processor":     NewProcessorSidecarGenerator(),
+		"scheduler":          NewSchedulerSidecarGenerator(),
+		"keycloak":           NewKeycloakSidecarGenerator(),
+		"localstack":         NewLocalStackSidecarGenerator(),
+		"dead-letter":        NewDeadLetterSidecarGenerator(),
+		"clickhouse":         NewClickHouseGenerator(),
+		"postgres databases": NewPostgresDatabasesGenerator(),
+		"postgres setup":     NewPostgresSetupGenerator(),
+		"snapshots":          NewSnapshotGenerator(),
+		"prod import":        NewProdImportGenerator(),
+		"test database":      NewTestDatabaseGenerator(),
+		"wiremock":           NewWireMockGenerator(),
+		"toxiproxy":          NewToxiproxyGenerator(),
+		"gatus":              NewGatusGenerator(),
+		"email example":      NewMailExampleGenerator(),
+		"environments":       NewOverridesGenerator(),
+		"sync-workspace.sh":  NewRemoteWorkspaceGenerator(),
+	}
+	for step, gen := range generators {
+		gen.SetFS(fsys)
+		if err := gen.Generate(detection, dir, project); err != nil {
+			t.Errorf("%s: Generate() error = %v", step, err)
+		}
+	}
+
+	for _, provider := range CIProviders {
+		gen := NewCIGenerator()
+		gen.SetFS(fsys)
+		if err := gen.Generate(detection, dir, project, provider); err != nil {
+			t.Errorf("ci %s: Generate() error = %v", provider, err)
+		}
+	}
+	bake := NewBakeGenerator()
+	bake.SetFS(fsys)
+	if err := bake.Generate(detection, dir, project, project); err != nil {
+		t.Errorf("bake: Generate() error = %v", err)
+	}
+	for _, file := range EditorFiles {
+		gen := NewEditorGenerator()
+		gen.SetFS(fsys)
+		if err := gen.Generate(detection, dir, project, project, file); err != nil {
+			t.Errorf("editor %s: Generate() error = %v", file, err)
+		}
+	}
+	jetbrains := NewJetBrainsGenerator()
+	jetbrains.SetFS(fsys)
+	if err := jetbrains.Generate(detection, dir, project); err != nil {
+		t.Errorf("jetbrains: Generate() error = %v", err)
+	}
+	for _, format := range TaskFormats {
+		gen := NewTasksGenerator()
+		gen.SetFS(fsys)
+		if err := gen.Generate(detection, dir, project, project, format); err != nil {
+			t.Errorf("tasks %s: Generate() error = %v", format, err)
+		}
+	}
+}
+
+// TestTemplates_Render renders the fixtures through every generator and
+// checks that each embedded template was rendered, so a template no
+// generator can reach, or one that fails with real data, fails here.
+func TestTemplates_Render(t *testing.T) {
+	recorder := &openedFS{FS: templates.Files(), opened: make(map[string]bool)}
+	loader, err := templates.Default.WithOverlay(recorder)
+	if err != nil {
+		t.Fatalf("WithOverlay() error = %v", err)
+	}
+	previous := templateLoader
+	templateLoader = loader
+	t.Cleanup(func() { templateLoader = previous })
+	// Validating the overlay read every template; count only what the generators load
+	recorder.opened = make(map[string]bool)
+
+	for name, detection := range renderFixtures() {
+		t.Run(name, func(t *testing.T) {
+			generateAll(t, detection, "/workspace/shop", NewMemFS(nil))
+		})
+	}
+
+	// The sqlite scripts are only rendered by dockstart backup, not by a sidecar
+	backups := NewBackupGenerator()
+	if err := backups.Generate(&models.BackupConfig{DatabaseType: "sqlite", DatabasePath: "db.sqlite3"}, t.TempDir()); err != nil {
+		t.Errorf("BackupGenerator.Generate(sqlite) error = %v", err)
+	}
+
+	var missing []string
+	for _, name := range templates.Names() {
+		if !recorder.opened[name] {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		t.Errorf("templates never rendered: %v", missing)
+	}
+}
+
+// TestTemplates_DockerfilesLint renders the fixtures and checks every
+// generated Dockerfile against the built-in lint rules, which dockstart
+// runs before writing them.
+func TestTemplates_DockerfilesLint(t *testing.T) {
+	linter := &lint.Linter{Ignore: lint.UnpinnedRules}
+	for name, detection := range renderFixtures() {
+		t.Run(name, func(t *testing.T) {
+			fsys := NewMemFS(nil)
+			generateAll(t, detection, "/workspace/shop", fsys)
+			for _, file := range fsys.Files() {
+				if !lint.IsDockerfile(filepath.Base(file)) {
+					continue
+				}
+				content, err := fsys.ReadFile(file)
+				if err != nil {
+					t.Fatalf("ReadFile() error = %v", err)
+				}
+				findings, err := linter.Lint(filepath.Base(file), content)
+				if err != nil {
+					t.Fatalf("Lint() error = %v", err)
+				}
+				for _, f := range findings {
+					t.Errorf("%s", f)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+// temporalUIImage is the Temporal Web UI image.
+const temporalUIImage = "temporalio/ui:2.31.2"
+
+// TemporalComposeConfig holds configuration for the Temporal server and its Web UI.
+type TemporalComposeConfig struct {
+	// Enabled indicates whether a Temporal server is generated
+	Enabled bool
+
+	// PostgresHost is the compose service auto-setup creates the temporal and
+	// temporal_visibility databases in
+	PostgresHost string
+
+	// PostgresHealthy is true when the PostgreSQL service has a healthcheck to wait for
+	PostgresHealthy bool
+
+	// UIImage is the Temporal Web UI image
+	UIImage string
+
+	// UIPort is the host port of the Web UI
+	UIPort int
+}
+
+// TemporalUIPort is the host port of the Temporal Web UI, the port the Temporal
+// CLI's dev server uses for it.
+const TemporalUIPort = 8233
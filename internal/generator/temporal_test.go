@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestComposeGenerator_Temporal tests the Temporal server, Web UI, and worker wiring.
+func TestComposeGenerator_Temporal(t *testing.T) {
+	detection := &models.Detection{
+		Language: "go",
+		Version:  "1.23",
+		Capabilities: models.Capabilities{
+			QueueLibraries: []string{"temporal"},
+		},
+		WorkerCommand: "go run ./worker",
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "orders")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+
+	var parsed struct {
+		Services map[string]interface{} `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, compose)
+	}
+	for _, name := range []string{"temporal", "temporal-ui", "postgres", "worker"} {
+		if _, ok := parsed.Services[name]; !ok {
+			t.Errorf("docker-compose.yml should have a %s service, got:\n%s", name, compose)
+		}
+	}
+	// app, worker, and temporal-ui connect to the frontend
+	if n := strings.Count(compose, "- TEMPORAL_ADDRESS=temporal:7233\n"); n != 3 {
+		t.Errorf("TEMPORAL_ADDRESS appears %d times, want 3, got:\n%s", n, compose)
+	}
+
+	for _, want := range []string{
+		"    image: temporalio/auto-setup:1.25\n",
+		"      DB: postgres12\n",
+		"      POSTGRES_SEEDS: postgres\n",
+		"      postgres:\n        condition: service_healthy\n",
+		`"7233:7233"`,
+		"    image: temporalio/ui:",
+		`"8233:8080"`,
+		"go run ./worker",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+		}
+	}
+}
+
+// TestComposeGenerator_TemporalImportedPostgres tests storing Temporal's state
+// in the project's own PostgreSQL service.
+func TestComposeGenerator_TemporalImportedPostgres(t *testing.T) {
+	detection := &models.Detection{
+		Language: "python",
+		Version:  "3.12",
+		Services: []string{"postgres"},
+		Capabilities: models.Capabilities{
+			QueueLibraries: []string{"temporal"},
+		},
+		WorkerCommand: "python -m flows.worker",
+		ExistingCompose: &models.ExistingCompose{
+			File: "docker-compose.yml",
+			Services: []models.ExistingService{{
+				Name:       "db",
+				Role:       "postgres",
+				Definition: map[string]interface{}{"image": "postgres:16"},
+			}},
+		},
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "flows")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+	for _, want := range []string{
+		"      POSTGRES_SEEDS: db\n",
+		"    depends_on:\n      - db\n",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+		}
+	}
+}
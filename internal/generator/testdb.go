@@ -0,0 +1,138 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestDatabaseScript is the PostgreSQL init script that creates the test
+// database, relative to the project root.
+const TestDatabaseScript = ".devcontainer/postgres/init-test-db.sh"
+
+// TestDatabaseComposeConfig holds configuration for the databases test suites use.
+type TestDatabaseComposeConfig struct {
+	// Enabled indicates whether tests get their own PostgreSQL or Redis data
+	Enabled bool
+
+	// Isolation is models.TestIsolationService or models.TestIsolationDatabase
+	Isolation string
+
+	// Database is the PostgreSQL test database name
+	Database string
+
+	// Postgres is the postgres-test service, in service isolation
+	Postgres *ServiceConfig
+
+	// Redis is the redis-test service, in service isolation
+	Redis *ServiceConfig
+
+	// InitScript mounts init-test-db.sh into the development PostgreSQL, in database isolation
+	InitScript bool
+
+	// DatabaseURL is TEST_DATABASE_URL, or empty without PostgreSQL
+	DatabaseURL string
+
+	// RedisURL is TEST_REDIS_URL, or empty without Redis
+	RedisURL string
+}
+
+// TestDatabaseName returns the PostgreSQL database tests run against.
+func TestDatabaseName(projectName string) string {
+	return projectName + "_test"
+}
+
+// configureTestDatabase isolates test data from the generated PostgreSQL and
+// Redis services. Imported services are left alone: their data is the project's.
+func configureTestDatabase(config *ComposeConfig, detection *models.Detection) {
+	var postgres, redis *ServiceConfig
+	for i, service := range config.Services {
+		if service.Existing != "" {
+			continue
+		}
+		switch service.Name {
+		case "postgres":
+			postgres = &config.Services[i]
+		case "redis":
+			redis = &config.Services[i]
+		}
+	}
+	if postgres == nil && redis == nil {
+		return
+	}
+
+	test := TestDatabaseComposeConfig{
+		Enabled:   true,
+		Isolation: detection.Testing.Isolation,
+		Database:  TestDatabaseName(config.Name),
+	}
+	postgresHost, redisURL := "postgres", "redis://redis:6379/1"
+	if test.Isolation == models.TestIsolationService {
+		postgresHost, redisURL = "postgres-test", "redis://redis-test:6379"
+		if postgres != nil {
+			test.Postgres = &ServiceConfig{Name: "postgres-test", Version: postgres.Version}
+		}
+		if redis != nil {
+			test.Redis = &ServiceConfig{Name: "redis-test", Version: redis.Version}
+		}
+	} else {
+		test.InitScript = postgres != nil
+	}
+	if postgres != nil {
+		test.DatabaseURL = fmt.Sprintf("postgres://%s:%s@%s:5432/%s",
+			config.Postgres.User, config.Postgres.Password, postgresHost, test.Database)
+	}
+	if redis != nil {
+		test.RedisURL = redisURL
+	}
+	config.TestDatabase = test
+}
+
+// TestDatabaseConfig holds the configuration for generating init-test-db.sh.
+type TestDatabaseConfig struct {
+	// Database is the test database to create
+	Database string
+}
+
+// TestDatabaseGenerator generates .devcontainer/postgres/init-test-db.sh, which
+// creates the <project>_test database when PostgreSQL initializes its volume.
+type TestDatabaseGenerator struct {
+	output
+}
+
+// NewTestDatabaseGenerator creates a new test database script generator.
+func NewTestDatabaseGenerator() *TestDatabaseGenerator {
+	return &TestDatabaseGenerator{}
+}
+
+// Generate creates .devcontainer/postgres/init-test-db.sh.
+func (g *TestDatabaseGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	content, err := g.GenerateContent(projectName)
+	if err != nil {
+		return err
+	}
+
+	postgresDir := filepath.Join(projectPath, ".devcontainer", "postgres")
+	if err := g.fs().MkdirAll(postgresDir, 0755); err != nil {
+		return fmt.Errorf("failed to create postgres directory: %w", err)
+	}
+	if err := g.fs().WriteFile(filepath.Join(postgresDir, "init-test-db.sh"), content, 0755); err != nil {
+		return fmt.Errorf("failed to write init-test-db.sh: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateContent returns the init-test-db.sh content without writing to disk.
+func (g *TestDatabaseGenerator) GenerateContent(projectName string) ([]byte, error) {
+	return renderTemplate("postgres/init-test-db.sh.tmpl", &TestDatabaseConfig{Database: TestDatabaseName(projectName)})
+}
+
+// ShouldGenerate returns true if the test database lives in the generated PostgreSQL service.
+func (g *TestDatabaseGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.Testing.Isolation == models.TestIsolationDatabase &&
+		(detection.HasService("postgres") || detection.GetVectorStore() == "pgvector") &&
+		detection.ExistingServiceFor("postgres") == ""
+}
@@ -0,0 +1,87 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestProfile is the compose profile of the one-shot test service, which
+// `docker compose up` leaves out.
+const TestProfile = "test"
+
+// TestService is the compose service that runs the test suite.
+const TestService = "test"
+
+// TestRunnerComposeConfig holds configuration for the one-shot test service.
+type TestRunnerComposeConfig struct {
+	// Enabled indicates whether to include the test service
+	Enabled bool
+
+	// Command runs the test suite, after pending migrations when there are any
+	Command string
+
+	// Env overrides the app's environment, pointing it at the test databases
+	Env []EnvVar
+
+	// DependsOn are the services started, and waited on, before the tests run
+	DependsOn []TestDependency
+}
+
+// TestDependency is a service the test service waits for.
+type TestDependency struct {
+	// Service is the compose service name
+	Service string
+
+	// Condition is the depends_on condition ("service_healthy" or "service_started")
+	Condition string
+}
+
+// TestRunCommand returns the shell command the test service runs for a test
+// or coverage command: pending migrations are applied first, if there are any.
+func TestRunCommand(detection *models.Detection, command string) string {
+	if detection.NeedsMigrations() {
+		return joinCommands("bash "+MigrateScript, command)
+	}
+	return command
+}
+
+// healthCheckedServices are the generated backing services with a healthcheck.
+var healthCheckedServices = []string{"postgres", "redis", "clickhouse", "memcached", "nats", "influxdb"}
+
+// configureTestRunner adds the test service, which extends app so it gets the
+// same image, mounts, and environment, then runs the detected test command.
+func configureTestRunner(config *ComposeConfig, detection *models.Detection) {
+	runner := TestRunnerComposeConfig{
+		Enabled: true,
+		Env:     []EnvVar{{Name: "CI", Value: "true"}},
+	}
+
+	// Migrate the database the tests use first
+	runner.Command = TestRunCommand(detection, detection.TestCommand)
+	if detection.NeedsMigrations() && detection.MigrationDatabase() == "postgres" && config.TestDatabase.Postgres != nil {
+		runner.Env = append(runner.Env, EnvVar{Name: "DB_HOST", Value: config.TestDatabase.Postgres.Name})
+	}
+
+	if config.TestDatabase.DatabaseURL != "" {
+		runner.Env = append(runner.Env, EnvVar{Name: "DATABASE_URL", Value: config.TestDatabase.DatabaseURL})
+	}
+	if config.TestDatabase.RedisURL != "" {
+		runner.Env = append(runner.Env, EnvVar{Name: "REDIS_URL", Value: config.TestDatabase.RedisURL})
+	}
+
+	for _, service := range config.Services {
+		dependency := TestDependency{Service: service.ComposeName(), Condition: "service_started"}
+		if service.Existing == "" && containsString(healthCheckedServices, service.Name) {
+			dependency.Condition = "service_healthy"
+			switch {
+			case service.Name == "postgres" && config.TestDatabase.Postgres != nil:
+				dependency.Service = config.TestDatabase.Postgres.Name
+			case service.Name == "redis" && config.TestDatabase.Redis != nil:
+				dependency.Service = config.TestDatabase.Redis.Name
+			}
+		}
+		runner.DependsOn = append(runner.DependsOn, dependency)
+	}
+
+	config.TestRunner = runner
+}
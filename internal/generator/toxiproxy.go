@@ -0,0 +1,196 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// toxiproxyImage is the Toxiproxy image the chaos proxy runs.
+const toxiproxyImage = "ghcr.io/shopify/toxiproxy:2.9.0"
+
+// ToxiproxyAPIPort is the host port of the Toxiproxy HTTP API.
+const ToxiproxyAPIPort = 8474
+
+// ToxiproxyConfigFile holds the proxy definitions Toxiproxy loads on start,
+// relative to the project root.
+const ToxiproxyConfigFile = ".devcontainer/toxiproxy/toxiproxy.json"
+
+// ToxiproxyScript is the helper script that injects faults, relative to the project root.
+const ToxiproxyScript = ".devcontainer/scripts/chaos.sh"
+
+// toxiproxyServices are the databases Toxiproxy can sit in front of, in proxy order.
+var toxiproxyServices = []string{"postgres", "redis"}
+
+// toxiproxyPorts are the ports the app reaches the proxied databases on.
+var toxiproxyPorts = map[string]int{
+	"postgres": 5432,
+	"redis":    6379,
+}
+
+// ToxiproxyProxy is a Toxiproxy listener forwarding to a database.
+type ToxiproxyProxy struct {
+	// Name is the proxy name, which is the proxied service (e.g., "postgres")
+	Name string `json:"name"`
+
+	// Listen is the address Toxiproxy accepts the app's connections on
+	Listen string `json:"listen"`
+
+	// Upstream is the database the connections are forwarded to
+	Upstream string `json:"upstream"`
+
+	// Enabled starts the proxy accepting connections
+	Enabled bool `json:"enabled"`
+
+	// Port is the port the app connects to, the database's own
+	Port int `json:"-"`
+}
+
+// ToxiproxyComposeConfig holds configuration for the Toxiproxy chaos proxy.
+type ToxiproxyComposeConfig struct {
+	// Enabled indicates whether to include the Toxiproxy sidecar
+	Enabled bool
+
+	// Image is the Toxiproxy image
+	Image string
+
+	// APIPort is the external port for the HTTP API
+	APIPort int
+
+	// Proxies are the databases the app reaches through Toxiproxy
+	Proxies []ToxiproxyProxy
+
+	// Upstreams are the compose services behind the proxies
+	Upstreams []string
+}
+
+// toxiproxyConfig returns the Toxiproxy sidecar settings, or a zero config
+// unless the chaos proxy was requested and the stack has a database to put it
+// in front of. Each proxy listens on the database's own port, so only the
+// hostname in the app's URLs changes.
+func toxiproxyConfig(config *ComposeConfig, detection *models.Detection) ToxiproxyComposeConfig {
+	if !detection.NeedsToxiproxy() {
+		return ToxiproxyComposeConfig{}
+	}
+
+	toxiproxy := ToxiproxyComposeConfig{
+		Image:   toxiproxyImage,
+		APIPort: ToxiproxyAPIPort,
+	}
+	for _, name := range toxiproxyServices {
+		for _, service := range config.Services {
+			if service.Name != name {
+				continue
+			}
+			port := toxiproxyPorts[name]
+			toxiproxy.Proxies = append(toxiproxy.Proxies, ToxiproxyProxy{
+				Name:     name,
+				Listen:   fmt.Sprintf("0.0.0.0:%d", port),
+				Upstream: fmt.Sprintf("%s:%d", service.ComposeName(), port),
+				Enabled:  true,
+				Port:     port,
+			})
+			toxiproxy.Upstreams = append(toxiproxy.Upstreams, service.ComposeName())
+		}
+	}
+	toxiproxy.Enabled = len(toxiproxy.Proxies) > 0
+	return toxiproxy
+}
+
+// Address returns the host:port the app connects to a database at: Toxiproxy's
+// listener when the chaos proxy is in front of it, the database otherwise.
+func (c *ComposeConfig) Address(service string) string {
+	for _, proxy := range c.Toxiproxy.Proxies {
+		if proxy.Name == service {
+			return fmt.Sprintf("toxiproxy:%d", proxy.Port)
+		}
+	}
+	return fmt.Sprintf("%s:%d", service, toxiproxyPorts[service])
+}
+
+// ToxiproxyScriptConfig holds the configuration for generating chaos.sh.
+type ToxiproxyScriptConfig struct {
+	// Proxies are the proxy names faults can be injected into
+	Proxies []string
+}
+
+// ToxiproxyGenerator generates the Toxiproxy proxy definitions and
+// .devcontainer/scripts/chaos.sh, which injects latency and connection
+// failures through the Toxiproxy API.
+type ToxiproxyGenerator struct {
+	output
+}
+
+// NewToxiproxyGenerator creates a new Toxiproxy generator.
+func NewToxiproxyGenerator() *ToxiproxyGenerator {
+	return &ToxiproxyGenerator{}
+}
+
+// Files returns the files Generate writes, relative to the project root.
+func (g *ToxiproxyGenerator) Files() []string {
+	return []string{ToxiproxyConfigFile, ToxiproxyScript}
+}
+
+// Generate creates toxiproxy.json and chaos.sh.
+func (g *ToxiproxyGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	proxies, err := g.GenerateProxies(detection)
+	if err != nil {
+		return err
+	}
+	script, err := g.GenerateScript(detection)
+	if err != nil {
+		return err
+	}
+
+	toxiproxyDir := filepath.Join(projectPath, ".devcontainer", "toxiproxy")
+	if err := g.fs().MkdirAll(toxiproxyDir, 0755); err != nil {
+		return fmt.Errorf("failed to create toxiproxy directory: %w", err)
+	}
+	if err := g.fs().WriteFile(filepath.Join(toxiproxyDir, "toxiproxy.json"), proxies, 0644); err != nil {
+		return fmt.Errorf("failed to write toxiproxy.json: %w", err)
+	}
+
+	scriptsDir := filepath.Join(projectPath, ".devcontainer", "scripts")
+	if err := g.fs().MkdirAll(scriptsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create scripts directory: %w", err)
+	}
+	if err := g.fs().WriteFile(filepath.Join(scriptsDir, "chaos.sh"), script, 0755); err != nil {
+		return fmt.Errorf("failed to write chaos.sh: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateProxies returns the toxiproxy.json content without writing to disk.
+func (g *ToxiproxyGenerator) GenerateProxies(detection *models.Detection) ([]byte, error) {
+	content, err := json.MarshalIndent(g.config(detection).Proxies, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode toxiproxy proxies: %w", err)
+	}
+	return append(content, '\n'), nil
+}
+
+// GenerateScript returns the chaos.sh content without writing to disk.
+func (g *ToxiproxyGenerator) GenerateScript(detection *models.Detection) ([]byte, error) {
+
+	config := &ToxiproxyScriptConfig{}
+	for _, proxy := range g.config(detection).Proxies {
+		config.Proxies = append(config.Proxies, proxy.Name)
+	}
+
+	return renderTemplate("toxiproxy/chaos.sh.tmpl", config)
+}
+
+// ShouldGenerate returns true if the chaos proxy was requested and the stack
+// has a PostgreSQL or Redis to put it in front of.
+func (g *ToxiproxyGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return g.config(detection).Enabled
+}
+
+// config returns the Toxiproxy settings of the generated docker-compose.yml.
+func (g *ToxiproxyGenerator) config(detection *models.Detection) ToxiproxyComposeConfig {
+	return NewComposeGenerator().buildConfig(detection, "").Toxiproxy
+}
@@ -52,7 +52,9 @@ func DefaultTracingConfig() *TracingSidecarConfig {
 }
 
 // TracingSidecarGenerator generates Jaeger configuration for docker-compose.
-type TracingSidecarGenerator struct{}
+type TracingSidecarGenerator struct {
+	output
+}
 
 // NewTracingSidecarGenerator creates a new tracing sidecar generator.
 func NewTracingSidecarGenerator() *TracingSidecarGenerator {
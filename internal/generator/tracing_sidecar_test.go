@@ -44,32 +44,40 @@ func TestTracingSidecarGenerator_ShouldGenerate(t *testing.T) {
 		{
 			name: "with tracing library",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				},
 			},
 			expected: true,
 		},
 		{
 			name: "without tracing library",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				TracingLibraries: nil,
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					TracingLibraries: nil,
+				},
 			},
 			expected: false,
 		},
 		{
 			name: "empty tracing library slice",
 			detection: &models.Detection{
-				Language:         "go",
-				TracingLibraries: []string{},
+				Language: "go",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{},
+				},
 			},
 			expected: false,
 		},
 		{
 			name: "multiple tracing libraries",
 			detection: &models.Detection{
-				Language:         "go",
-				TracingLibraries: []string{"go.opentelemetry.io/otel", "go.opentelemetry.io/otel/exporters/otlp/otlptrace"},
+				Language: "go",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"go.opentelemetry.io/otel", "go.opentelemetry.io/otel/exporters/otlp/otlptrace"},
+				},
 			},
 			expected: true,
 		},
@@ -97,9 +105,11 @@ func TestTracingSidecarGenerator_BuildConfig(t *testing.T) {
 		{
 			name: "uses detection values",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				TracingLibraries: []string{"@opentelemetry/sdk-node"},
-				TracingProtocol:  "otlp",
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				},
+				TracingProtocol: "otlp",
 			},
 			projectName: "myproject",
 			checkConfig: func(t *testing.T, config *TracingSidecarConfig) {
@@ -117,9 +127,11 @@ func TestTracingSidecarGenerator_BuildConfig(t *testing.T) {
 		{
 			name: "uses default protocol when unknown",
 			detection: &models.Detection{
-				Language:         "go",
-				TracingLibraries: []string{"go.opentelemetry.io/otel"},
-				TracingProtocol:  "unknown",
+				Language: "go",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"go.opentelemetry.io/otel"},
+				},
+				TracingProtocol: "unknown",
 			},
 			projectName: "goapp",
 			checkConfig: func(t *testing.T, config *TracingSidecarConfig) {
@@ -131,9 +143,11 @@ func TestTracingSidecarGenerator_BuildConfig(t *testing.T) {
 		{
 			name: "uses default protocol when empty",
 			detection: &models.Detection{
-				Language:         "python",
-				TracingLibraries: []string{"opentelemetry-sdk"},
-				TracingProtocol:  "",
+				Language: "python",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"opentelemetry-sdk"},
+				},
+				TracingProtocol: "",
 			},
 			projectName: "pyapp",
 			checkConfig: func(t *testing.T, config *TracingSidecarConfig) {
@@ -145,9 +159,11 @@ func TestTracingSidecarGenerator_BuildConfig(t *testing.T) {
 		{
 			name: "preserves jaeger protocol",
 			detection: &models.Detection{
-				Language:         "rust",
-				TracingLibraries: []string{"opentelemetry-jaeger"},
-				TracingProtocol:  "jaeger",
+				Language: "rust",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"opentelemetry-jaeger"},
+				},
+				TracingProtocol: "jaeger",
 			},
 			projectName: "rustapp",
 			checkConfig: func(t *testing.T, config *TracingSidecarConfig) {
@@ -159,9 +175,11 @@ func TestTracingSidecarGenerator_BuildConfig(t *testing.T) {
 		{
 			name: "preserves zipkin protocol",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				TracingLibraries: []string{"zipkin"},
-				TracingProtocol:  "zipkin",
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"zipkin"},
+				},
+				TracingProtocol: "zipkin",
 			},
 			projectName: "zipkinapp",
 			checkConfig: func(t *testing.T, config *TracingSidecarConfig) {
@@ -173,9 +191,11 @@ func TestTracingSidecarGenerator_BuildConfig(t *testing.T) {
 		{
 			name: "stores tracing libraries",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				TracingLibraries: []string{"@opentelemetry/sdk-node", "@opentelemetry/auto-instrumentations-node"},
-				TracingProtocol:  "otlp",
+				Language: "nodejs",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node", "@opentelemetry/auto-instrumentations-node"},
+				},
+				TracingProtocol: "otlp",
 			},
 			projectName: "multilib",
 			checkConfig: func(t *testing.T, config *TracingSidecarConfig) {
@@ -354,9 +374,11 @@ func TestTracingSidecarAllLanguages(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			detection := &models.Detection{
-				Language:         tt.language,
-				TracingLibraries: tt.libraries,
-				TracingProtocol:  tt.protocol,
+				Language: tt.language,
+				Capabilities: models.Capabilities{
+					TracingLibraries: tt.libraries,
+				},
+				TracingProtocol: tt.protocol,
 			}
 
 			if !gen.ShouldGenerate(detection) {
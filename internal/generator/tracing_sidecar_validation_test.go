@@ -19,58 +19,70 @@ func TestTracingComposeValidYAML(t *testing.T) {
 		{
 			name: "nodejs with otlp tracing",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				Version:          "20",
-				TracingLibraries: []string{"@opentelemetry/sdk-node"},
-				TracingProtocol:  "otlp",
+				Language: "nodejs",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				},
+				TracingProtocol: "otlp",
 			},
 		},
 		{
 			name: "go with otlp tracing",
 			detection: &models.Detection{
-				Language:         "go",
-				Version:          "1.21",
-				TracingLibraries: []string{"go.opentelemetry.io/otel"},
-				TracingProtocol:  "otlp",
+				Language: "go",
+				Version:  "1.21",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"go.opentelemetry.io/otel"},
+				},
+				TracingProtocol: "otlp",
 			},
 		},
 		{
 			name: "python with jaeger protocol",
 			detection: &models.Detection{
-				Language:         "python",
-				Version:          "3.12",
-				TracingLibraries: []string{"jaeger-client"},
-				TracingProtocol:  "jaeger",
+				Language: "python",
+				Version:  "3.12",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"jaeger-client"},
+				},
+				TracingProtocol: "jaeger",
 			},
 		},
 		{
 			name: "rust with zipkin protocol",
 			detection: &models.Detection{
-				Language:         "rust",
-				Version:          "1.75",
-				TracingLibraries: []string{"opentelemetry-zipkin"},
-				TracingProtocol:  "zipkin",
+				Language: "rust",
+				Version:  "1.75",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"opentelemetry-zipkin"},
+				},
+				TracingProtocol: "zipkin",
 			},
 		},
 		{
 			name: "tracing with services",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				Version:          "20",
-				TracingLibraries: []string{"@opentelemetry/sdk-node"},
-				TracingProtocol:  "otlp",
-				Services:         []string{"postgres", "redis"},
+				Language: "nodejs",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				},
+				TracingProtocol: "otlp",
+				Services:        []string{"postgres", "redis"},
 			},
 		},
 		{
 			name: "tracing with worker",
 			detection: &models.Detection{
-				Language:         "nodejs",
-				Version:          "20",
-				TracingLibraries: []string{"@opentelemetry/sdk-node"},
-				TracingProtocol:  "otlp",
-				QueueLibraries:   []string{"bull"},
-				WorkerCommand:    "npm run worker",
+				Language: "nodejs",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+					QueueLibraries:   []string{"bull"},
+				},
+				TracingProtocol: "otlp",
+				WorkerCommand:   "npm run worker",
 			},
 		},
 	}
@@ -101,10 +113,12 @@ func TestTracingComposeStructure(t *testing.T) {
 	gen := NewComposeGenerator()
 
 	detection := &models.Detection{
-		Language:         "nodejs",
-		Version:          "20",
-		TracingLibraries: []string{"@opentelemetry/sdk-node"},
-		TracingProtocol:  "otlp",
+		Language: "nodejs",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			TracingLibraries: []string{"@opentelemetry/sdk-node"},
+		},
+		TracingProtocol: "otlp",
 	}
 
 	content, err := gen.GenerateContent(detection, "myapp")
@@ -203,10 +217,12 @@ func TestTracingAppEnvironmentVariables(t *testing.T) {
 	gen := NewComposeGenerator()
 
 	detection := &models.Detection{
-		Language:         "nodejs",
-		Version:          "20",
-		TracingLibraries: []string{"@opentelemetry/sdk-node"},
-		TracingProtocol:  "otlp",
+		Language: "nodejs",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			TracingLibraries: []string{"@opentelemetry/sdk-node"},
+		},
+		TracingProtocol: "otlp",
 	}
 
 	content, err := gen.GenerateContent(detection, "myapp")
@@ -268,12 +284,14 @@ func TestTracingWorkerEnvironmentVariables(t *testing.T) {
 	gen := NewComposeGenerator()
 
 	detection := &models.Detection{
-		Language:         "nodejs",
-		Version:          "20",
-		TracingLibraries: []string{"@opentelemetry/sdk-node"},
-		TracingProtocol:  "otlp",
-		QueueLibraries:   []string{"bull"},
-		WorkerCommand:    "npm run worker",
+		Language: "nodejs",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			TracingLibraries: []string{"@opentelemetry/sdk-node"},
+			QueueLibraries:   []string{"bull"},
+		},
+		TracingProtocol: "otlp",
+		WorkerCommand:   "npm run worker",
 	}
 
 	content, err := gen.GenerateContent(detection, "myapp")
@@ -358,10 +376,12 @@ func TestTracingJaegerRestartPolicy(t *testing.T) {
 	gen := NewComposeGenerator()
 
 	detection := &models.Detection{
-		Language:         "nodejs",
-		Version:          "20",
-		TracingLibraries: []string{"@opentelemetry/sdk-node"},
-		TracingProtocol:  "otlp",
+		Language: "nodejs",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			TracingLibraries: []string{"@opentelemetry/sdk-node"},
+		},
+		TracingProtocol: "otlp",
 	}
 
 	content, err := gen.GenerateContent(detection, "testapp")
@@ -399,16 +419,18 @@ func TestTracingFullObservabilityStack(t *testing.T) {
 	gen := NewComposeGenerator()
 
 	detection := &models.Detection{
-		Language:         "nodejs",
-		Version:          "20",
-		TracingLibraries: []string{"@opentelemetry/sdk-node"},
-		TracingProtocol:  "otlp",
-		MetricsLibraries: []string{"prom-client"},
-		MetricsPort:      3000,
-		MetricsPath:      "/metrics",
-		LoggingLibraries: []string{"pino"},
-		LogFormat:        "json",
-		Services:         []string{"postgres", "redis"},
+		Language: "nodejs",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			TracingLibraries: []string{"@opentelemetry/sdk-node"},
+			MetricsLibraries: []string{"prom-client"},
+			LoggingLibraries: []string{"pino"},
+		},
+		TracingProtocol: "otlp",
+		MetricsPort:     3000,
+		MetricsPath:     "/metrics",
+		LogFormat:       "json",
+		Services:        []string{"postgres", "redis"},
 	}
 
 	content, err := gen.GenerateContent(detection, "fullstack")
@@ -455,10 +477,12 @@ func TestTracingProtocolVariations(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			detection := &models.Detection{
-				Language:         "nodejs",
-				Version:          "20",
-				TracingLibraries: []string{"@opentelemetry/sdk-node"},
-				TracingProtocol:  tt.protocol,
+				Language: "nodejs",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				},
+				TracingProtocol: tt.protocol,
 			}
 
 			content, err := gen.GenerateContent(detection, "prototest")
@@ -540,10 +564,12 @@ func TestTracingComposeWithDefaultPorts(t *testing.T) {
 	gen := NewComposeGenerator()
 
 	detection := &models.Detection{
-		Language:         "nodejs",
-		Version:          "20",
-		TracingLibraries: []string{"@opentelemetry/sdk-node"},
-		TracingProtocol:  "otlp",
+		Language: "nodejs",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			TracingLibraries: []string{"@opentelemetry/sdk-node"},
+		},
+		TracingProtocol: "otlp",
 	}
 
 	config := gen.buildConfig(detection, "porttest")
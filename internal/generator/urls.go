@@ -0,0 +1,109 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// ServiceURL is a browser-reachable endpoint of the generated stack.
+type ServiceURL struct {
+	// Name is the display name (e.g., "App", "Grafana")
+	Name string
+
+	// URL is the address on the host (e.g., "http://localhost:3001")
+	URL string
+}
+
+// ServiceURLs returns the URLs users open once the stack is running,
+// using the same ports the generators forward and publish.
+func ServiceURLs(detection *models.Detection) []ServiceURL {
+	urls := []ServiceURL{
+		{Name: "App", URL: fmt.Sprintf("http://localhost:%d", detection.GetAppPort())},
+	}
+
+	if detection.FrontendFramework != "" && detection.FrontendPort > 0 && detection.FrontendPort != detection.GetAppPort() {
+		urls = append(urls, ServiceURL{Name: "Frontend", URL: fmt.Sprintf("http://localhost:%d", detection.FrontendPort)})
+	}
+	if detection.NeedsMetrics() {
+		urls = append(urls,
+			ServiceURL{Name: "Grafana", URL: "http://localhost:3001"},
+			ServiceURL{Name: "Prometheus", URL: "http://localhost:9090"},
+		)
+	}
+	if detection.NeedsTracing() {
+		urls = append(urls, ServiceURL{Name: "Jaeger", URL: "http://localhost:16686"})
+	}
+	if dashboard := detection.GetQueueDashboard(); dashboard != "" {
+		urls = append(urls, ServiceURL{
+			Name: queueDashboardNames[dashboard],
+			URL:  fmt.Sprintf("http://localhost:%d", detection.GetQueueDashboardPort()),
+		})
+	}
+	if detection.NeedsDeadLetter() && usesRabbitMQ(detection.QueueLibraries) {
+		urls = append(urls, ServiceURL{Name: "RabbitMQ", URL: "http://localhost:15672"})
+	}
+	if detection.NeedsGRPC() {
+		urls = append(urls, ServiceURL{Name: "grpcui", URL: "http://localhost:8082"})
+	}
+	if detection.NeedsAuthProvider() {
+		urls = append(urls, ServiceURL{Name: "Keycloak", URL: "http://localhost:8180"})
+	}
+	if detection.NeedsLocalStack() {
+		urls = append(urls, ServiceURL{Name: "LocalStack", URL: "http://localhost:4566"})
+	}
+	if detection.NeedsMinIO() {
+		urls = append(urls, ServiceURL{Name: "MinIO Console", URL: "http://localhost:9001"})
+	}
+	if detection.HasService("clickhouse") {
+		urls = append(urls, ServiceURL{Name: "ClickHouse", URL: "http://localhost:8123/play"})
+	}
+	if detection.NeedsTemporal() {
+		urls = append(urls, ServiceURL{Name: "Temporal UI", URL: fmt.Sprintf("http://localhost:%d", TemporalUIPort)})
+	}
+	if detection.HasService("influxdb") {
+		urls = append(urls, ServiceURL{Name: "InfluxDB", URL: "http://localhost:8086"})
+	}
+	if detection.HasService("nats") {
+		urls = append(urls, ServiceURL{Name: "NATS Monitoring", URL: "http://localhost:8222"})
+	}
+	if detection.NeedsWireMock() {
+		urls = append(urls, ServiceURL{Name: "WireMock", URL: fmt.Sprintf("http://localhost:%d/__admin/mappings", WireMockPort)})
+	}
+	if detection.NeedsGlitchTip() {
+		urls = append(urls, ServiceURL{Name: "GlitchTip", URL: fmt.Sprintf("http://localhost:%d", GlitchTipPort)})
+	}
+	if detection.NeedsStatusPage() {
+		urls = append(urls, ServiceURL{Name: "Status page", URL: fmt.Sprintf("http://localhost:%d", GatusPort)})
+	}
+	switch detection.GetVectorStore() {
+	case "qdrant":
+		urls = append(urls, ServiceURL{Name: "Qdrant", URL: "http://localhost:6333/dashboard"})
+	case "chroma":
+		urls = append(urls, ServiceURL{Name: "Chroma", URL: "http://localhost:8001"})
+	}
+	if detection.NeedsMailpit() {
+		urls = append(urls, ServiceURL{Name: "Mailpit", URL: fmt.Sprintf("http://localhost:%d", MailpitPort)})
+	}
+	switch detection.GetSearchEngine() {
+	case "meilisearch":
+		urls = append(urls, ServiceURL{Name: "Meilisearch", URL: fmt.Sprintf("http://localhost:%d", MeilisearchPort)})
+	case "typesense":
+		urls = append(urls, ServiceURL{Name: "Typesense", URL: fmt.Sprintf("http://localhost:%d/health", TypesensePort)})
+	}
+	if detection.NeedsOllama() {
+		urls = append(urls, ServiceURL{Name: "Ollama", URL: "http://localhost:11434"})
+	}
+	if detection.NeedsSeleniumGrid() {
+		urls = append(urls, ServiceURL{Name: "Selenium Grid", URL: "http://localhost:4444/ui"})
+		for _, node := range seleniumNodes {
+			urls = append(urls, ServiceURL{
+				Name: node.Name + " (noVNC)",
+				URL:  fmt.Sprintf("http://localhost:%d", node.VNCPort),
+			})
+		}
+	}
+
+	return urls
+}
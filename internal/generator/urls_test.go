@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestServiceURLs tests the URLs listed for a running stack.
+func TestServiceURLs(t *testing.T) {
+	tests := []struct {
+		name      string
+		detection *models.Detection
+		want      map[string]string
+		dontWant  []string
+	}{
+		{
+			name:      "app only",
+			detection: &models.Detection{Language: "go", Version: "1.23"},
+			want:      map[string]string{"App": "http://localhost:8080"},
+			dontWant:  []string{"Grafana", "Jaeger"},
+		},
+		{
+			name: "metrics and tracing",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					MetricsLibraries: []string{"prom-client"},
+					TracingLibraries: []string{"@opentelemetry/sdk-node"},
+				},
+			},
+			want: map[string]string{
+				"App":        "http://localhost:3000",
+				"Grafana":    "http://localhost:3001",
+				"Prometheus": "http://localhost:9090",
+				"Jaeger":     "http://localhost:16686",
+			},
+		},
+		{
+			name: "queue dashboard",
+			detection: &models.Detection{
+				Language: "go",
+				Version:  "1.23",
+				Capabilities: models.Capabilities{
+					QueueLibraries: []string{"asynq"},
+				},
+			},
+			want:     map[string]string{"Asynqmon": "http://localhost:8081"},
+			dontWant: []string{"Bull Board", "Flower"},
+		},
+		{
+			name: "s3 uploads on minio",
+			detection: &models.Detection{
+				Language: "node",
+				Version:  "20",
+				Capabilities: models.Capabilities{
+					AWSServices:         []string{"s3"},
+					FileUploadLibraries: []string{"multer"},
+				},
+			},
+			want:     map[string]string{"MinIO Console": "http://localhost:9001"},
+			dontWant: []string{"LocalStack"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]string)
+			for _, u := range ServiceURLs(tt.detection) {
+				got[u.Name] = u.URL
+			}
+
+			for name, url := range tt.want {
+				if got[name] != url {
+					t.Errorf("URL for %s = %q, want %q", name, got[name], url)
+				}
+			}
+			for _, name := range tt.dontWant {
+				if _, ok := got[name]; ok {
+					t.Errorf("Unexpected URL for %s", name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// dependencyVolumeName returns the named volume holding a dependency directory
+// (e.g., "node-modules" for node_modules, "venv" for .venv).
+func dependencyVolumeName(dir string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(dir, "."), "_", "-")
+}
+
+// webDependencyVolume is the named volume holding the frontend's node_modules.
+const webDependencyVolume = "web-node-modules"
+
+// WorkspaceVolumes renders the workspace bind mount of a service that runs the
+// app's toolchain, as entries of its volumes list. In Windows mode the mount uses
+// long syntax, which isn't split on a drive letter's colon, and the dependency
+// directories are shadowed by named volumes, since bind mounts from a Windows
+// host are slow and break native modules built for Linux. On a remote Docker
+// engine the workspace is the RemoteWorkspace volume, dependencies included.
+func (c *ComposeConfig) WorkspaceVolumes(service string) string {
+	if c.RemoteWorkspace != "" {
+		return "\n      - workspace:/workspace"
+	}
+	if !c.Windows {
+		return "\n      - ..:/workspace:cached"
+	}
+
+	var b strings.Builder
+	b.WriteString("\n      - type: bind\n        source: ..\n        target: /workspace")
+	if service == "web" {
+		fmt.Fprintf(&b, "\n      - %s:%s", webDependencyVolume, path.Join(c.WebService.WorkingDir, "node_modules"))
+		return b.String()
+	}
+	for _, dir := range c.DependencyDirs {
+		fmt.Fprintf(&b, "\n      - %s:%s", dependencyVolumeName(dir), path.Join("/workspace", dir))
+	}
+	return b.String()
+}
+
+// DependencyVolumes returns the named volumes WorkspaceVolumes mounts.
+func (c *ComposeConfig) DependencyVolumes() []string {
+	if !c.Windows || c.RemoteWorkspace != "" {
+		return nil
+	}
+	var volumes []string
+	for _, dir := range c.DependencyDirs {
+		volumes = append(volumes, dependencyVolumeName(dir))
+	}
+	if c.WebService.Enabled {
+		volumes = append(volumes, webDependencyVolume)
+	}
+	return volumes
+}
+
+// LineEndingsGenerator generates .devcontainer/.gitattributes, which keeps the
+// generated files LF on Windows checkouts: git's core.autocrlf would otherwise
+// convert them to CRLF, and shell scripts and crontabs with CRLF fail in Linux containers.
+type LineEndingsGenerator struct {
+	output
+}
+
+// NewLineEndingsGenerator creates a new line endings generator.
+func NewLineEndingsGenerator() *LineEndingsGenerator {
+	return &LineEndingsGenerator{}
+}
+
+// Generate creates .devcontainer/.gitattributes.
+func (g *LineEndingsGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	content, err := g.GenerateContent()
+	if err != nil {
+		return err
+	}
+
+	devcontainerDir := filepath.Join(projectPath, ".devcontainer")
+	if err := g.fs().MkdirAll(devcontainerDir, 0755); err != nil {
+		return fmt.Errorf("failed to create .devcontainer directory: %w", err)
+	}
+	if err := g.fs().WriteFile(filepath.Join(devcontainerDir, ".gitattributes"), content, 0644); err != nil {
+		return fmt.Errorf("failed to write .gitattributes: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateContent returns the .gitattributes content without writing to disk.
+func (g *LineEndingsGenerator) GenerateContent() ([]byte, error) {
+	return renderTemplate("gitattributes.tmpl", nil)
+}
+
+// ShouldGenerate returns true if .devcontainer/.gitattributes should be generated.
+func (g *LineEndingsGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.Windows
+}
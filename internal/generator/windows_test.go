@@ -0,0 +1,161 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestComposeGenerator_Windows tests workspace mounts and dependency volumes in Windows mode.
+func TestComposeGenerator_Windows(t *testing.T) {
+	gen := NewComposeGenerator()
+
+	t.Run("node app and frontend keep node_modules in volumes", func(t *testing.T) {
+		detection := &models.Detection{
+			Language: "node",
+			Version:  "20",
+			Services: []string{"postgres"},
+			Capabilities: models.Capabilities{
+				QueueLibraries: []string{"bullmq"},
+			},
+			WorkerCommand:     "node worker.js",
+			FrontendFramework: "vite",
+			FrontendDir:       "frontend",
+			FrontendPort:      5173,
+			Windows:           true,
+		}
+
+		content, err := gen.GenerateContent(detection, "shop")
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+
+		var compose struct {
+			Services map[string]struct {
+				Volumes []interface{} `yaml:"volumes"`
+			} `yaml:"services"`
+			Volumes map[string]interface{} `yaml:"volumes"`
+		}
+		if err := yaml.Unmarshal(content, &compose); err != nil {
+			t.Fatalf("Generated YAML is invalid: %v\n%s", err, content)
+		}
+
+		for service, want := range map[string]string{
+			"app":    "node-modules:/workspace/node_modules",
+			"worker": "node-modules:/workspace/node_modules",
+			"web":    "web-node-modules:/workspace/frontend/node_modules",
+		} {
+			volumes := compose.Services[service].Volumes
+			bind, ok := volumes[0].(map[string]interface{})
+			if !ok || bind["type"] != "bind" || bind["source"] != ".." || bind["target"] != "/workspace" {
+				t.Errorf("%s should mount the workspace with long syntax, got %v", service, volumes[0])
+			}
+			if len(volumes) < 2 || volumes[1] != want {
+				t.Errorf("%s volumes = %v, want %q", service, volumes, want)
+			}
+		}
+		for _, volume := range []string{"node-modules", "web-node-modules", "postgres-data"} {
+			if _, ok := compose.Volumes[volume]; !ok {
+				t.Errorf("volumes should declare %s, got %v", volume, compose.Volumes)
+			}
+		}
+		if strings.Contains(string(content), "..:/workspace:cached") {
+			t.Error("Windows mode should not use the short workspace mount")
+		}
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		detection := &models.Detection{
+			Language: "python",
+			Version:  "3.12",
+			Services: []string{"redis"},
+		}
+
+		content, err := gen.GenerateContent(detection, "api")
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		yamlContent := string(content)
+		if !strings.Contains(yamlContent, "- ..:/workspace:cached") {
+			t.Errorf("docker-compose.yml should use the short workspace mount, got:\n%s", yamlContent)
+		}
+		if strings.Contains(yamlContent, "venv") {
+			t.Errorf("docker-compose.yml should not mount .venv in a volume, got:\n%s", yamlContent)
+		}
+	})
+}
+
+// TestDevcontainerGenerator_Windows tests devcontainer.json mounts in Windows mode without Compose.
+func TestDevcontainerGenerator_Windows(t *testing.T) {
+	detection := &models.Detection{
+		Language: "rust",
+		Version:  "1.80",
+		Windows:  true,
+	}
+
+	content, err := NewDevcontainerGenerator().GenerateContent(detection, "cli")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+
+	devcontainer := string(content)
+	for _, want := range []string{
+		`"workspaceMount": "source=${localWorkspaceFolder},target=/workspace,type=bind"`,
+		`"source=${localWorkspaceFolderBasename}-target,target=/workspace/target,type=volume"`,
+		`"postCreateCommand": "git config --global --add safe.directory /workspace && sudo chown vscode /workspace/target && cargo build"`,
+	} {
+		if !strings.Contains(devcontainer, want) {
+			t.Errorf("devcontainer.json should contain %q, got:\n%s", want, devcontainer)
+		}
+	}
+	if _, err := parseJSONC(content); err != nil {
+		t.Errorf("Generated JSON is invalid: %v", err)
+	}
+}
+
+// TestDockerfileGenerator_WindowsNonRoot tests that dependency directories are owned by the user.
+func TestDockerfileGenerator_WindowsNonRoot(t *testing.T) {
+	gen := NewDockerfileGenerator()
+
+	detection := &models.Detection{Language: "node", Version: "20", NonRoot: true, Windows: true}
+	content, err := gen.GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	want := `RUN mkdir -p /workspace/node_modules && chown "$USER_UID:$USER_GID" /workspace/node_modules`
+	if !strings.Contains(string(content), want) {
+		t.Errorf("Dockerfile should contain %q, got:\n%s", want, content)
+	}
+
+	// Root owns the volumes either way
+	detection.NonRoot = false
+	content, err = gen.GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if strings.Contains(string(content), "node_modules") {
+		t.Errorf("Dockerfile should not create node_modules when running as root, got:\n%s", content)
+	}
+}
+
+// TestLineEndingsGenerator tests the generated .gitattributes.
+func TestLineEndingsGenerator(t *testing.T) {
+	gen := NewLineEndingsGenerator()
+
+	if gen.ShouldGenerate(&models.Detection{Language: "go"}) {
+		t.Error("ShouldGenerate() should be false outside Windows mode")
+	}
+	if !gen.ShouldGenerate(&models.Detection{Language: "go", Windows: true}) {
+		t.Error("ShouldGenerate() should be true in Windows mode")
+	}
+
+	content, err := gen.GenerateContent()
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if !strings.Contains(string(content), "* text=auto eol=lf") {
+		t.Errorf(".gitattributes should force LF, got:\n%s", content)
+	}
+}
@@ -0,0 +1,121 @@
+// Package generator provides code generation for devcontainer files.
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// wireMockImage is the WireMock image the API-mocking sidecar runs.
+const wireMockImage = "wiremock/wiremock:3.9.1"
+
+// WireMockPort is the host port of the WireMock stubs and admin API.
+const WireMockPort = 8089
+
+// WireMockMappingsDir holds the WireMock stub mappings, relative to the project root.
+const WireMockMappingsDir = ".devcontainer/wiremock/mappings"
+
+// wireMockExampleMapping is the stub scaffolded into an empty mappings
+// directory: GET /example returns a JSON body.
+const wireMockExampleMapping = `{
+  "request": {
+    "method": "GET",
+    "urlPath": "/example"
+  },
+  "response": {
+    "status": 200,
+    "headers": {
+      "Content-Type": "application/json"
+    },
+    "jsonBody": {
+      "message": "Stubbed by WireMock. Add stubs to .devcontainer/wiremock/mappings"
+    }
+  }
+}
+`
+
+// WireMockComposeConfig holds configuration for the WireMock API-mocking sidecar.
+type WireMockComposeConfig struct {
+	// Enabled indicates whether to include the WireMock sidecar
+	Enabled bool
+
+	// Image is the WireMock image
+	Image string
+
+	// Port is the external port for the stubs and the /__admin API
+	Port int
+
+	// APIs are the app's external API variables pointed at WireMock
+	// (e.g., "PAYMENTS_API_URL")
+	APIs []string
+}
+
+// wireMockConfig returns the WireMock sidecar settings, or a zero config unless
+// API mocking was requested.
+func wireMockConfig(detection *models.Detection) WireMockComposeConfig {
+	if !detection.NeedsWireMock() {
+		return WireMockComposeConfig{}
+	}
+	return WireMockComposeConfig{
+		Enabled: true,
+		Image:   wireMockImage,
+		Port:    WireMockPort,
+		APIs:    detection.ExternalAPIs,
+	}
+}
+
+// WireMockGenerator scaffolds .devcontainer/wiremock, which WireMock loads its
+// stub mappings (mappings/) and response bodies (__files/) from.
+type WireMockGenerator struct {
+	output
+}
+
+// NewWireMockGenerator creates a new WireMock scaffold generator.
+func NewWireMockGenerator() *WireMockGenerator {
+	return &WireMockGenerator{}
+}
+
+// Files returns the files Generate writes, relative to the project root. An
+// example stub is only written to a mappings directory without stubs: the
+// stubs belong to the project.
+func (g *WireMockGenerator) Files(projectPath string) []string {
+	files := []string{".devcontainer/wiremock/__files/.gitkeep"}
+	entries, _ := g.fs().ReadDir(filepath.Join(projectPath, filepath.FromSlash(WireMockMappingsDir)))
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".json") {
+			return files
+		}
+	}
+	return append(files, WireMockMappingsDir+"/example.json")
+}
+
+// Generate creates the mappings and __files directories and the example stub.
+func (g *WireMockGenerator) Generate(detection *models.Detection, projectPath, projectName string) error {
+	files := g.Files(projectPath)
+
+	wireMockDir := filepath.Join(projectPath, ".devcontainer", "wiremock")
+	for _, dir := range []string{"mappings", "__files"} {
+		if err := g.fs().MkdirAll(filepath.Join(wireMockDir, dir), 0755); err != nil {
+			return fmt.Errorf("failed to create wiremock directory: %w", err)
+		}
+	}
+
+	if err := g.fs().WriteFile(filepath.Join(wireMockDir, "__files", ".gitkeep"), []byte{}, 0644); err != nil {
+		return fmt.Errorf("failed to write .gitkeep: %w", err)
+	}
+	if len(files) > 1 {
+		if err := g.fs().WriteFile(filepath.Join(wireMockDir, "mappings", "example.json"), []byte(wireMockExampleMapping), 0644); err != nil {
+			return fmt.Errorf("failed to write example mapping: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ShouldGenerate returns true if API mocking was requested.
+func (g *WireMockGenerator) ShouldGenerate(detection *models.Detection) bool {
+	return detection.NeedsWireMock()
+}
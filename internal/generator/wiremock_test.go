@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// TestComposeGenerator_WireMock tests the WireMock sidecar in docker-compose.yml.
+func TestComposeGenerator_WireMock(t *testing.T) {
+	detection := &models.Detection{
+		Language: "node",
+		Version:  "20",
+		Capabilities: models.Capabilities{
+			HTTPClientLibraries: []string{"axios"},
+		},
+		ExternalAPIs: []string{"PAYMENTS_API_URL"},
+		MockAPIs:     true,
+	}
+
+	content, err := NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	compose := string(content)
+
+	var parsed map[string]interface{}
+	if err := yaml.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("invalid YAML: %v\n%s", err, compose)
+	}
+
+	for _, want := range []string{
+		"  wiremock:\n    image: wiremock/wiremock:3.9.1\n",
+		"      - ./wiremock:/home/wiremock\n",
+		`"8089:8080"`,
+		"      - EXTERNAL_API_BASE_URL=http://wiremock:8080\n",
+		"      - PAYMENTS_API_URL=http://wiremock:8080\n",
+	} {
+		if !strings.Contains(compose, want) {
+			t.Errorf("docker-compose.yml should contain %q, got:\n%s", want, compose)
+		}
+	}
+
+	// Without --wiremock, external APIs are only reported
+	detection.MockAPIs = false
+	content, err = NewComposeGenerator().GenerateContent(detection, "shop")
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if strings.Contains(string(content), "wiremock") {
+		t.Errorf("docker-compose.yml should not contain WireMock without --wiremock, got:\n%s", content)
+	}
+}
+
+// TestWireMockGenerator tests the mappings scaffold.
+func TestWireMockGenerator(t *testing.T) {
+	tmpDir := t.TempDir()
+	detection := &models.Detection{Language: "python", MockAPIs: true}
+	gen := NewWireMockGenerator()
+
+	want := []string{".devcontainer/wiremock/__files/.gitkeep", ".devcontainer/wiremock/mappings/example.json"}
+	if files := gen.Files(tmpDir); !slices.Equal(files, want) {
+		t.Errorf("Files() = %v, want %v", files, want)
+	}
+	if err := gen.Generate(detection, tmpDir, "shop"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	example := filepath.Join(tmpDir, ".devcontainer", "wiremock", "mappings", "example.json")
+	if _, err := os.Stat(example); err != nil {
+		t.Fatalf("example.json should be created: %v", err)
+	}
+
+	// The project's own stubs replace the example, which isn't recreated
+	if err := os.Remove(example); err != nil {
+		t.Fatal(err)
+	}
+	stub := filepath.Join(tmpDir, ".devcontainer", "wiremock", "mappings", "payments.json")
+	if err := os.WriteFile(stub, []byte(`{"request": {"url": "/charges"}, "response": {"status": 201}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if files := gen.Files(tmpDir); len(files) != 1 {
+		t.Errorf("Files() = %v, want only the .gitkeep", files)
+	}
+	if err := gen.Generate(detection, tmpDir, "shop"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := os.Stat(example); !os.IsNotExist(err) {
+		t.Error("example.json should not be recreated next to the project's stubs")
+	}
+}
+
+// TestWireMockGenerator_MemFS tests that the project's stubs are looked up
+// through the generator's filesystem, so a stub only in memory counts.
+func TestWireMockGenerator_MemFS(t *testing.T) {
+	tmpDir := t.TempDir()
+	mem := NewMemFS(OS)
+	stub := filepath.Join(tmpDir, ".devcontainer", "wiremock", "mappings", "payments.json")
+	if err := mem.MkdirAll(filepath.Dir(stub), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := mem.WriteFile(stub, []byte(`{"request": {"url": "/charges"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gen := NewWireMockGenerator()
+	gen.SetFS(mem)
+	if files := gen.Files(tmpDir); len(files) != 1 {
+		t.Errorf("Files() = %v, want only the .gitkeep next to the stub in memory", files)
+	}
+	if err := gen.Generate(&models.Detection{Language: "python", MockAPIs: true}, tmpDir, "shop"); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if _, err := mem.Stat(filepath.Join(filepath.Dir(stub), "example.json")); !os.IsNotExist(err) {
+		t.Errorf("example.json should not be written next to the stub in memory, stat error = %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+// Package golden renders the generators against a corpus of fixture projects
+// and compares the output to checked-in golden files, so template changes
+// show up in review as diffs of the files users would get.
+//
+// A corpus is a directory laid out as:
+//
+//	projects/<name>/   a fixture project (package.json, go.mod, ...)
+//	golden/<name>/     the files generated for it, relative to the project
+package golden
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/config"
+	"github.com/jpequegn/dockstart/internal/detector"
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/jpequegn/dockstart/internal/project"
+)
+
+// Fixture is a project in the corpus and the directory of its golden files.
+type Fixture struct {
+	// Name is the project's directory name, also used as the project name
+	Name string
+
+	// Project is the fixture project's directory
+	Project string
+
+	// Golden is the directory of its golden files
+	Golden string
+}
+
+// Fixtures returns the fixture projects of the corpus in dir, sorted by name.
+func Fixtures(dir string) ([]Fixture, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, "projects"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture projects: %w", err)
+	}
+
+	var fixtures []Fixture
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		fixtures = append(fixtures, Fixture{
+			Name:    entry.Name(),
+			Project: filepath.Join(dir, "projects", entry.Name()),
+			Golden:  filepath.Join(dir, "golden", entry.Name()),
+		})
+	}
+	if len(fixtures) == 0 {
+		return nil, fmt.Errorf("no fixture projects in %s", filepath.Join(dir, "projects"))
+	}
+	return fixtures, nil
+}
+
+// Render detects the fixture project, applies its .dockstart.yml, and renders
+// the plan dockstart would run for it in memory, with every CI pipeline and
+// task file. It returns the generated files by slash-separated path relative
+// to the project (e.g., ".devcontainer/docker-compose.yml").
+func (f Fixture) Render() (map[string][]byte, error) {
+	projectPath, err := filepath.Abs(f.Project)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.Load(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Name, err)
+	}
+	resolution, err := project.Resolve(detector.NewRegistry(), projectPath, cfg, project.Flags{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: detection failed: %w", f.Name, err)
+	}
+	if resolution == nil {
+		return nil, fmt.Errorf("%s: %w", f.Name, &detector.NoProjectError{Path: projectPath})
+	}
+	detection := resolution.Primary
+	if _, err := project.Configure(detection, cfg, f.Name, project.Flags{}); err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Name, err)
+	}
+
+	plan, err := project.BuildPlan(detection, projectPath, f.Name, project.PlanOptions{
+		CIProviders: generator.CIProviders,
+		TaskFormats: generator.TaskFormats,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Name, err)
+	}
+	fsys, err := plan.Render(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Name, err)
+	}
+
+	files := make(map[string][]byte)
+	for _, name := range fsys.Files() {
+		rel, err := filepath.Rel(projectPath, name)
+		if err != nil {
+			return nil, err
+		}
+		rel = filepath.ToSlash(rel)
+		// The passwords in .devcontainer/.env are random
+		if rel == generator.CredentialsFile {
+			continue
+		}
+		data, err := fsys.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		files[rel] = data
+	}
+	return files, nil
+}
+
+// Diff is a difference between the rendered files and the golden files.
+type Diff struct {
+	// Path is the file's slash-separated path relative to the project
+	Path string
+
+	// Line is the first line that differs, or 0 if the file is Missing or
+	// Unexpected
+	Line int
+
+	// Want and Got are the golden and rendered content of that line
+	Want, Got string
+
+	// Missing means the golden file is no longer generated
+	Missing bool
+
+	// Unexpected means a generated file has no golden file
+	Unexpected bool
+}
+
+func (d Diff) String() string {
+	switch {
+	case d.Missing:
+		return fmt.Sprintf("%s: golden file is no longer generated", d.Path)
+	case d.Unexpected:
+		return fmt.Sprintf("%s: generated but has no golden file", d.Path)
+	default:
+		return fmt.Sprintf("%s:%d: want %q, got %q", d.Path, d.Line, d.Want, d.Got)
+	}
+}
+
+// Compare compares the rendered files with the fixture's golden files,
+// returning the differences sorted by path.
+func (f Fixture) Compare(files map[string][]byte) ([]Diff, error) {
+	golden, err := readDir(f.Golden)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []Diff
+	for path, want := range golden {
+		got, ok := files[path]
+		if !ok {
+			diffs = append(diffs, Diff{Path: path, Missing: true})
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			diffs = append(diffs, diffLines(path, want, got))
+		}
+	}
+	for path := range files {
+		if _, ok := golden[path]; !ok {
+			diffs = append(diffs, Diff{Path: path, Unexpected: true})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Path < diffs[j].Path })
+	return diffs, nil
+}
+
+// Update replaces the fixture's golden files with the rendered files.
+func (f Fixture) Update(files map[string][]byte) error {
+	if err := os.RemoveAll(f.Golden); err != nil {
+		return fmt.Errorf("failed to remove golden files: %w", err)
+	}
+	for path, data := range files {
+		name := filepath.Join(f.Golden, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			return fmt.Errorf("failed to create golden directory: %w", err)
+		}
+		if err := os.WriteFile(name, data, 0644); err != nil {
+			return fmt.Errorf("failed to write golden file: %w", err)
+		}
+	}
+	return nil
+}
+
+// readDir reads the files under dir by slash-separated relative path. A
+// missing dir has no files.
+func readDir(dir string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read golden files: %w", err)
+	}
+	return files, nil
+}
+
+// diffLines returns the first line where got differs from want.
+func diffLines(path string, want, got []byte) Diff {
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+	for i := 0; ; i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g || i >= len(wantLines) || i >= len(gotLines) {
+			return Diff{Path: path, Line: i + 1, Want: w, Got: g}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package golden
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+// update rewrites the golden files: go test ./internal/golden -update
+var update = flag.Bool("update", false, "rewrite the golden files with the current output")
+
+// TestGolden renders every fixture project and compares the output to its
+// golden files. After an intended template change, run with -update and
+// review the golden file diffs.
+func TestGolden(t *testing.T) {
+	fixtures, err := Fixtures("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(fixture.Name, func(t *testing.T) {
+			files, err := fixture.Render()
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for path, data := range files {
+				if strings.Contains(string(data), fixture.Project) {
+					t.Errorf("%s contains the fixture's path, which differs between machines", path)
+				}
+			}
+
+			if *update {
+				if err := fixture.Update(files); err != nil {
+					t.Fatalf("Update() error = %v", err)
+				}
+				return
+			}
+
+			diffs, err := fixture.Compare(files)
+			if err != nil {
+				t.Fatalf("Compare() error = %v", err)
+			}
+			for _, diff := range diffs {
+				t.Error(diff)
+			}
+			if len(diffs) > 0 {
+				t.Log("if the changes are intended, run go test ./internal/golden -update and review the diffs")
+			}
+		})
+	}
+}
+
+// TestFixture_Compare tests that changed, missing, and unexpected files are
+// reported, and that updated golden files match.
+func TestFixture_Compare(t *testing.T) {
+	fixture := Fixture{Name: "app", Golden: t.TempDir()}
+	if err := fixture.Update(map[string][]byte{
+		".devcontainer/Dockerfile": []byte("FROM node:20\nWORKDIR /app\n"),
+		"Makefile":                 []byte("up:\n"),
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	diffs, err := fixture.Compare(map[string][]byte{
+		".devcontainer/Dockerfile": []byte("FROM node:22\nWORKDIR /app\n"),
+		"Justfile":                 []byte("up:\n"),
+	})
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	want := []string{
+		`.devcontainer/Dockerfile:1: want "FROM node:20", got "FROM node:22"`,
+		"Justfile: generated but has no golden file",
+		"Makefile: golden file is no longer generated",
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("Compare() = %v, want %d diffs", diffs, len(want))
+	}
+	for i, diff := range diffs {
+		if diff.String() != want[i] {
+			t.Errorf("diff %d = %q, want %q", i, diff, want[i])
+		}
+	}
+
+	// A longer file differs at its first extra line
+	diffs, _ = fixture.Compare(map[string][]byte{
+		".devcontainer/Dockerfile": []byte("FROM node:20\nWORKDIR /app\nUSER node\n"),
+		"Makefile":                 []byte("up:\n"),
+	})
+	if len(diffs) != 1 || diffs[0].Line != 3 || diffs[0].Got != "USER node" {
+		t.Errorf("Compare() = %v, want line 3 to differ", diffs)
+	}
+}
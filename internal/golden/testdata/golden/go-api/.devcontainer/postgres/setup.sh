@@ -0,0 +1,33 @@
+#!/bin/sh
+# Create the PostgreSQL extensions and roles declared in .dockstart.yml
+# Generated by dockstart - https://github.com/jpequegn/dockstart
+#
+# PostgreSQL runs this from /docker-entrypoint-initdb.d on first start only,
+# after the init-* scripts, so every database already exists.
+# For an existing postgres-data volume, run it once by hand:
+#   docker compose exec postgres sh /docker-entrypoint-initdb.d/setup.sh
+
+set -e
+
+# The role passwords come from .devcontainer/.env, through the environment
+# docker-compose.yml gives the PostgreSQL services
+psql -v ON_ERROR_STOP=1 --username "${POSTGRES_USER}" --dbname postgres \
+    -v reporting_password="$POSTGRES_ROLE_REPORTING_PASSWORD" \
+    <<'EOSQL'
+SELECT format('CREATE ROLE %I LOGIN PASSWORD %L', 'reporting', :'reporting_password')
+WHERE NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'reporting')\gexec
+EOSQL
+
+databases=$(psql -At --username "${POSTGRES_USER}" --dbname postgres \
+    -c "SELECT datname FROM pg_database WHERE NOT datistemplate AND datname <> 'postgres'")
+for db in $databases; do
+    psql -v ON_ERROR_STOP=1 --username "${POSTGRES_USER}" --dbname "$db" <<EOSQL
+CREATE EXTENSION IF NOT EXISTS "pg_trgm";
+GRANT CONNECT ON DATABASE "$db" TO "reporting";
+GRANT USAGE ON SCHEMA public TO "reporting";
+GRANT SELECT ON ALL TABLES IN SCHEMA public TO "reporting";
+ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT ON TABLES TO "reporting";
+EOSQL
+done
+
+echo "PostgreSQL extensions and roles are ready"
@@ -0,0 +1,48 @@
+#!/bin/bash
+# Main Backup Script
+# Generated by dockstart - https://github.com/jpequegn/dockstart
+#
+# This script runs all configured database backups.
+# Individual database scripts can be run directly for manual backups.
+
+set -eo pipefail
+
+echo "=============================================="
+echo "[$(date)] Starting backup run"
+echo "=============================================="
+
+BACKUP_DIR="${BACKUP_DIR:-/backup}"
+mkdir -p "${BACKUP_DIR}"
+
+# Track success/failure
+TOTAL=0
+SUCCESS=0
+FAILED=0
+
+# PostgreSQL backup
+echo ""
+echo "[$(date)] Running PostgreSQL backup..."
+TOTAL=$((TOTAL + 1))
+if /usr/local/bin/backup-postgres.sh; then
+    SUCCESS=$((SUCCESS + 1))
+    echo "[$(date)] PostgreSQL backup: SUCCESS"
+else
+    FAILED=$((FAILED + 1))
+    echo "[$(date)] PostgreSQL backup: FAILED"
+fi
+
+echo ""
+echo "=============================================="
+echo "[$(date)] Backup run complete"
+echo "[$(date)] Results: ${SUCCESS}/${TOTAL} successful, ${FAILED} failed"
+echo "=============================================="
+
+# List current backups
+echo ""
+echo "Current backups in ${BACKUP_DIR}:"
+ls -lh "${BACKUP_DIR}"/*.gz 2>/dev/null || echo "  No backups found"
+
+# Exit with error if any backup failed
+if [ "${FAILED}" -gt 0 ]; then
+    exit 1
+fi
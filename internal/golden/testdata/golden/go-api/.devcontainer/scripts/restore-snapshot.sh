@@ -0,0 +1,61 @@
+#!/bin/bash
+# Reset the development PostgreSQL databases to a snapshot
+# Generated by dockstart - https://github.com/jpequegn/dockstart
+#
+# Replaces go-api_dev with the copies snapshot.sh made. The snapshot is
+# kept, so the same state can be restored again. Run it on the host.
+#
+# Usage:
+#   restore-snapshot.sh <name>  Restore snapshot <name> (see snapshot.sh list)
+#
+# Open connections, the app's included, are closed; the app reconnects to the
+# restored databases.
+
+set -euo pipefail
+
+ROOT="$(cd "$(dirname "${BASH_SOURCE[0]}")/../.." && pwd)"
+PROJECT="${COMPOSE_PROJECT_NAME:-$(basename "${ROOT}" | tr '[:upper:]' '[:lower:]' | tr -cd 'a-z0-9_-')_devcontainer}"
+COMPOSE=(docker compose -f "${ROOT}/.devcontainer/docker-compose.yml" -p "${PROJECT}")
+SERVICE="postgres"
+DATABASES=("go-api_dev")
+SUFFIX="__snap_"
+
+usage() {
+    sed -n '8,9s/^# \{0,1\}//p' "$0" >&2
+    exit 1
+}
+
+# sql runs statements as postgres, from the postgres maintenance database
+sql() {
+    "${COMPOSE[@]}" exec -T "${SERVICE}" psql -U "postgres" -d postgres -v ON_ERROR_STOP=1 -qAt -c "$1"
+}
+
+# close refuses new connections to database $1 and closes the open ones
+close() {
+    sql "ALTER DATABASE \"$1\" ALLOW_CONNECTIONS false"
+    sql "SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE datname = '$1' AND pid <> pg_backend_pid()" >/dev/null
+}
+
+name="${1:-}"
+if [ -z "${name}" ] || [[ ! "${name}" =~ ^[a-z0-9_]+$ ]]; then
+    usage
+fi
+
+for db in "${DATABASES[@]}"; do
+    if [ -z "$(sql "SELECT 1 FROM pg_database WHERE datname = '${db}${SUFFIX}${name}'")" ]; then
+        echo "No snapshot ${name} of ${db}: list the snapshots with snapshot.sh list" >&2
+        exit 1
+    fi
+done
+
+for db in "${DATABASES[@]}"; do
+    # Copy first, so a failed copy leaves the database as it was
+    sql "DROP DATABASE IF EXISTS \"${db}__restoring\""
+    close "${db}${SUFFIX}${name}"
+    sql "CREATE DATABASE \"${db}__restoring\" TEMPLATE \"${db}${SUFFIX}${name}\""
+    close "${db}"
+    sql "DROP DATABASE \"${db}\""
+    sql "ALTER DATABASE \"${db}__restoring\" RENAME TO \"${db}\""
+done
+
+echo "Restored snapshot ${name}"
@@ -0,0 +1,95 @@
+#!/bin/bash
+# Snapshot the development PostgreSQL databases
+# Generated by dockstart - https://github.com/jpequegn/dockstart
+#
+# A snapshot copies go-api_dev inside the postgres service, as
+# <database>__snap_<name>. PostgreSQL copies the files itself (CREATE DATABASE
+# ... TEMPLATE), so it takes seconds rather than a dump and reload. Run it on
+# the host; restore-snapshot.sh brings a snapshot back.
+#
+# Usage:
+#   snapshot.sh <name>         Copy the databases into snapshot <name>
+#   snapshot.sh list           List the snapshots and their sizes
+#   snapshot.sh delete <name>  Delete snapshot <name>
+#
+# The copy needs the databases to itself: open connections, the app's
+# included, are closed while it runs.
+
+set -euo pipefail
+
+ROOT="$(cd "$(dirname "${BASH_SOURCE[0]}")/../.." && pwd)"
+PROJECT="${COMPOSE_PROJECT_NAME:-$(basename "${ROOT}" | tr '[:upper:]' '[:lower:]' | tr -cd 'a-z0-9_-')_devcontainer}"
+COMPOSE=(docker compose -f "${ROOT}/.devcontainer/docker-compose.yml" -p "${PROJECT}")
+SERVICE="postgres"
+DATABASES=("go-api_dev")
+SUFFIX="__snap_"
+
+usage() {
+    sed -n '10,13s/^# \{0,1\}//p' "$0" >&2
+    exit 1
+}
+
+# sql runs statements as postgres, from the postgres maintenance database
+sql() {
+    "${COMPOSE[@]}" exec -T "${SERVICE}" psql -U "postgres" -d postgres -v ON_ERROR_STOP=1 -qAt -c "$1"
+}
+
+# check_name rejects names PostgreSQL would need quoting for or truncate
+check_name() {
+    local name="$1" db
+    if [[ ! "${name}" =~ ^[a-z0-9_]+$ ]]; then
+        echo "Invalid snapshot name '${name}': use lowercase letters, digits, and underscores" >&2
+        exit 1
+    fi
+    for db in "${DATABASES[@]}"; do
+        if (( ${#db} + ${#SUFFIX} + ${#name} > 63 )); then
+            echo "Snapshot name '${name}' is too long: ${db}${SUFFIX}${name} exceeds PostgreSQL's 63 characters" >&2
+            exit 1
+        fi
+    done
+}
+
+# allow_connections turns connections to database $1 on (true) or off (false)
+allow_connections() {
+    sql "ALTER DATABASE \"$1\" ALLOW_CONNECTIONS $2"
+}
+
+# copy creates database $2 from database $1, refusing new connections to $1
+# and closing the open ones while it runs
+copy() {
+    local source="$1" target="$2"
+    allow_connections "${source}" false
+    trap "allow_connections '${source}' true" EXIT
+    sql "SELECT pg_terminate_backend(pid) FROM pg_stat_activity WHERE datname = '${source}' AND pid <> pg_backend_pid()" >/dev/null
+    sql "CREATE DATABASE \"${target}\" TEMPLATE \"${source}\""
+    allow_connections "${source}" true
+    trap - EXIT
+}
+
+command="${1:-}"
+name="${2:-}"
+
+case "${command}" in
+    "" | -h | --help)
+        usage
+        ;;
+    list)
+        sql "SELECT substr(datname, length('${DATABASES[0]}${SUFFIX}') + 1) || '  ' || pg_size_pretty(pg_database_size(datname)) FROM pg_database WHERE starts_with(datname, '${DATABASES[0]}${SUFFIX}') ORDER BY datname"
+        ;;
+    delete)
+        [ -n "${name}" ] || usage
+        check_name "${name}"
+        for db in "${DATABASES[@]}"; do
+            sql "DROP DATABASE IF EXISTS \"${db}${SUFFIX}${name}\""
+        done
+        echo "Deleted snapshot ${name}"
+        ;;
+    *)
+        name="${command}"
+        check_name "${name}"
+        for db in "${DATABASES[@]}"; do
+            copy "${db}" "${db}${SUFFIX}${name}"
+        done
+        echo "Created snapshot ${name}; restore it with restore-snapshot.sh ${name}"
+        ;;
+esac
@@ -0,0 +1,87 @@
+#!/bin/bash
+# Completion Notification Script
+# Generated by dockstart - https://github.com/jpequegn/dockstart
+#
+# Tells the app that a file has been processed, so it can pick up the output.
+#
+# Usage: notify.sh <filename> <status>
+# Status is one of: success, failed, rejected, quarantined
+#
+# NOTIFY_METHOD selects how:
+#   file    - write <filename>.done next to the output (default)
+#   webhook - POST the JSON payload to NOTIFY_URL
+#   queue   - LPUSH the JSON payload onto the Redis list NOTIFY_QUEUE
+#   redis   - PUBLISH the JSON payload on the file:processed channel
+
+set -o pipefail
+
+PROCESSED_DIR="${PROCESSED_PATH:-/files/processed}"
+NOTIFY_METHOD="${NOTIFY_METHOD:-file}"
+NOTIFY_URL="${NOTIFY_URL:-${WEBHOOK_URL:-}}"
+NOTIFY_QUEUE="${NOTIFY_QUEUE:-file-processed}"
+
+FILENAME="$1"
+STATUS="$2"
+if [ -z "$FILENAME" ] || [ -z "$STATUS" ]; then
+    echo "Usage: notify.sh <filename> <status>"
+    exit 2
+fi
+
+log() {
+    echo "[$(date '+%Y-%m-%d %H:%M:%S')] [notify] $*"
+}
+
+TIMESTAMP=$(date -u +"%Y-%m-%dT%H:%M:%SZ")
+OUTPUT_PATH=""
+if [ "$STATUS" = "success" ]; then
+    OUTPUT_PATH="$PROCESSED_DIR/$FILENAME"
+fi
+
+PAYLOAD=$(cat <<EOF
+{"event":"file.processed","file":"$FILENAME","status":"$STATUS","path":"$OUTPUT_PATH","timestamp":"$TIMESTAMP"}
+EOF
+)
+
+case "$NOTIFY_METHOD" in
+    file)
+        echo "$PAYLOAD" > "$PROCESSED_DIR/${FILENAME}.done"
+        ;;
+    webhook|http)
+        if [ -z "$NOTIFY_URL" ]; then
+            log "WARNING: NOTIFY_METHOD=$NOTIFY_METHOD but NOTIFY_URL is not set"
+            exit 1
+        fi
+        if ! command -v curl >/dev/null 2>&1; then
+            log "WARNING: curl is not installed, cannot send webhook"
+            exit 1
+        fi
+        if ! curl -sS --fail --retry 3 --retry-delay 2 -X POST "$NOTIFY_URL" \
+            -H "Content-Type: application/json" \
+            -d "$PAYLOAD" >/dev/null; then
+            log "WARNING: Failed to POST notification to $NOTIFY_URL"
+            exit 1
+        fi
+        ;;
+    queue)
+        if ! command -v redis-cli >/dev/null 2>&1; then
+            log "WARNING: redis-cli is not installed, cannot push to $NOTIFY_QUEUE"
+            exit 1
+        fi
+        if ! redis-cli -u "${REDIS_URL:-redis://redis:6379}" LPUSH "$NOTIFY_QUEUE" "$PAYLOAD" >/dev/null; then
+            log "WARNING: Failed to push notification onto $NOTIFY_QUEUE"
+            exit 1
+        fi
+        ;;
+    redis)
+        if ! redis-cli -u "${REDIS_URL:-redis://redis:6379}" PUBLISH file:processed "$PAYLOAD" >/dev/null; then
+            log "WARNING: Failed to publish notification"
+            exit 1
+        fi
+        ;;
+    *)
+        log "WARNING: Unknown NOTIFY_METHOD: $NOTIFY_METHOD"
+        exit 1
+        ;;
+esac
+
+exit 0
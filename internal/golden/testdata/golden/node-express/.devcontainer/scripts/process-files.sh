@@ -0,0 +1,238 @@
+#!/bin/bash
+# File Processor - Main Processing Loop
+# Generated by dockstart - https://github.com/jpequegn/dockstart
+#
+# This script watches for new files and processes them through the pipeline:
+# pending/ -> processing/ -> processed/ (or failed/, or quarantine/ when a virus scan fails)
+
+set -eo pipefail
+
+# Configuration from environment variables
+PENDING_DIR="${PENDING_PATH:-/files/pending}"
+PROCESSING_DIR="${PROCESSING_PATH:-/files/processing}"
+PROCESSED_DIR="${PROCESSED_PATH:-/files/processed}"
+FAILED_DIR="${FAILED_PATH:-/files/failed}"
+QUARANTINE_DIR="${QUARANTINE_PATH:-/files/quarantine}"
+POLL_INTERVAL="${POLL_INTERVAL:-5}"
+MAX_FILE_SIZE="${MAX_FILE_SIZE:-52428800}"  # 50MB default
+RETRY_COUNT="${RETRY_COUNT:-3}"
+RETRY_DELAY="${RETRY_DELAY:-10}"
+NOTIFY_METHOD="${NOTIFY_METHOD:-file}"  # file, webhook, queue, or redis
+# Space-separated MIME types (wildcards like image/* allowed); empty accepts any type
+ALLOWED_MIME_TYPES="${ALLOWED_MIME_TYPES:-}"
+# Scan files with ClamAV (scan-file.sh) before processing
+SCAN_ENABLED="${SCAN_ENABLED:-false}"
+
+# Ensure directories exist
+mkdir -p "$PENDING_DIR" "$PROCESSING_DIR" "$PROCESSED_DIR" "$FAILED_DIR"
+if [ "$SCAN_ENABLED" = "true" ]; then
+    mkdir -p "$QUARANTINE_DIR"
+fi
+
+# Log with timestamp
+log() {
+    echo "[$(date '+%Y-%m-%d %H:%M:%S')] $*"
+}
+
+# Get file type using file command
+get_file_type() {
+    local file="$1"
+    local mime_type
+    mime_type=$(file --mime-type -b "$file" 2>/dev/null || echo "application/octet-stream")
+    echo "$mime_type"
+}
+
+# Check if the file's MIME type is in ALLOWED_MIME_TYPES
+is_allowed_type() {
+    local mime_type="$1"
+    local allowed
+
+    [ -z "$ALLOWED_MIME_TYPES" ] && return 0
+    for allowed in $ALLOWED_MIME_TYPES; do
+        # shellcheck disable=SC2254 # $allowed is a glob pattern such as image/*
+        case "$mime_type" in
+            $allowed) return 0 ;;
+        esac
+    done
+    return 1
+}
+
+# Check if file size is within limits
+check_file_size() {
+    local file="$1"
+    local size
+    size=$(stat -f%z "$file" 2>/dev/null || stat -c%s "$file" 2>/dev/null || echo "0")
+
+    if [ "$size" -gt "$MAX_FILE_SIZE" ]; then
+        log "WARNING: File $file exceeds max size ($size > $MAX_FILE_SIZE bytes)"
+        return 1
+    fi
+    return 0
+}
+
+# Scan a file for viruses; infected files are moved to quarantine by scan-file.sh
+# Returns 0 if clean, 1 if infected, 2 if the scan failed
+scan_file() {
+    local file="$1"
+
+    if [ "$SCAN_ENABLED" != "true" ]; then
+        return 0
+    fi
+    if [ ! -x /usr/local/bin/scan-file.sh ]; then
+        log "WARNING: SCAN_ENABLED is true but scan-file.sh is not installed, skipping scan"
+        return 0
+    fi
+
+    /usr/local/bin/scan-file.sh "$file"
+}
+
+# Process a single file
+process_file() {
+    local file="$1"
+    local filename
+    filename=$(basename "$file")
+    local mime_type
+    local result=0
+    local scan_result=0
+
+    # Scan before anything reads the file; quarantined files are not retried
+    scan_file "$file" || scan_result=$?
+    if [ $scan_result -eq 1 ]; then
+        log "Quarantined: $filename"
+        send_notification "$filename" "quarantined"
+        return 0
+    elif [ $scan_result -ne 0 ]; then
+        mv "$file" "$FAILED_DIR/$filename"
+        echo "Virus scan failed with exit code $scan_result" > "$FAILED_DIR/${filename}.error"
+        log "Failed: $filename (virus scan error)"
+        return $scan_result
+    fi
+
+    mime_type=$(get_file_type "$file")
+
+    # Reject types that are not allowed without retrying
+    if ! is_allowed_type "$mime_type"; then
+        log "Rejected: $filename (type $mime_type is not in ALLOWED_MIME_TYPES)"
+        mv "$file" "$FAILED_DIR/$filename"
+        echo "MIME type $mime_type is not allowed" > "$FAILED_DIR/${filename}.error"
+        send_notification "$filename" "rejected"
+        return 0
+    fi
+
+    log "Processing: $filename (type: $mime_type)"
+
+    # Move to processing directory
+    mv "$file" "$PROCESSING_DIR/$filename"
+
+    # Process based on mime type
+    case "$mime_type" in
+        image/jpeg|image/png|image/gif|image/webp)
+            if [ -x /usr/local/bin/process-image.sh ]; then
+                /usr/local/bin/process-image.sh "$PROCESSING_DIR/$filename" || result=$?
+            else
+                # Fallback: just move the file
+                mv "$PROCESSING_DIR/$filename" "$PROCESSED_DIR/$filename"
+            fi
+            ;;
+        *)
+            # Unknown file type - just move to processed
+            log "Unknown file type, moving without processing: $filename"
+            mv "$PROCESSING_DIR/$filename" "$PROCESSED_DIR/$filename"
+            ;;
+    esac
+
+    # Handle result
+    if [ $result -eq 0 ]; then
+        # Clean up processing file if still exists (processor should have moved it)
+        if [ -f "$PROCESSING_DIR/$filename" ]; then
+            mv "$PROCESSING_DIR/$filename" "$PROCESSED_DIR/$filename"
+        fi
+        log "Completed: $filename"
+        send_notification "$filename" "success"
+    else
+        # Move to failed directory
+        if [ -f "$PROCESSING_DIR/$filename" ]; then
+            mv "$PROCESSING_DIR/$filename" "$FAILED_DIR/$filename"
+        fi
+        echo "Processing failed with exit code $result" > "$FAILED_DIR/${filename}.error"
+        log "Failed: $filename (exit code: $result)"
+        send_notification "$filename" "failed"
+    fi
+
+    return $result
+}
+
+# Process file with retry logic
+process_with_retry() {
+    local file="$1"
+    local filename
+    filename=$(basename "$file")
+    local attempt=1
+
+    while [ $attempt -le "$RETRY_COUNT" ]; do
+        if process_file "$file"; then
+            return 0
+        fi
+
+        if [ $attempt -lt "$RETRY_COUNT" ]; then
+            log "Retry $attempt/$RETRY_COUNT for $filename in ${RETRY_DELAY}s..."
+
+            # Move back from failed to pending for retry
+            if [ -f "$FAILED_DIR/$filename" ]; then
+                mv "$FAILED_DIR/$filename" "$PENDING_DIR/$filename"
+                rm -f "$FAILED_DIR/${filename}.error"
+            fi
+
+            sleep "$RETRY_DELAY"
+        fi
+
+        attempt=$((attempt + 1))
+    done
+
+    log "Failed after $RETRY_COUNT attempts: $filename"
+    return 1
+}
+
+# Send notification when file is processed (see notify.sh for NOTIFY_METHOD)
+send_notification() {
+    local filename="$1"
+    local status="$2"
+
+    /usr/local/bin/notify.sh "$filename" "$status" || log "WARNING: Failed to send $NOTIFY_METHOD notification for $filename"
+}
+
+# Main processing loop
+main() {
+    log "File processor started"
+    log "Configuration:"
+    log "  PENDING_DIR: $PENDING_DIR"
+    log "  PROCESSED_DIR: $PROCESSED_DIR"
+    log "  POLL_INTERVAL: ${POLL_INTERVAL}s"
+    log "  MAX_FILE_SIZE: $MAX_FILE_SIZE bytes"
+    log "  RETRY_COUNT: $RETRY_COUNT"
+    log "  NOTIFY_METHOD: $NOTIFY_METHOD"
+    log "  ALLOWED_MIME_TYPES: ${ALLOWED_MIME_TYPES:-any}"
+    log "  SCAN_ENABLED: $SCAN_ENABLED"
+    log ""
+    # Use polling for cross-platform compatibility
+    polling_loop
+}
+
+# Polling-based file watching
+polling_loop() {
+    log "Using polling (interval: ${POLL_INTERVAL}s)"
+
+    while true; do
+        # Find files that have been completely written (older than 1 second)
+        find "$PENDING_DIR" -type f -mmin +0.016 2>/dev/null | while read -r file; do
+            if [ -f "$file" ]; then
+                check_file_size "$file" && process_with_retry "$file" || true
+            fi
+        done
+
+        sleep "$POLL_INTERVAL"
+    done
+}
+
+# Run main loop
+main "$@"
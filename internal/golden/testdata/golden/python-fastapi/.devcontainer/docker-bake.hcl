@@ -0,0 +1,48 @@
+# Buildx bake file for python-fastapi: builds every image of the compose stack in one call
+# Generated by dockstart - https://github.com/jpequegn/dockstart
+#
+# From the project root:
+#   docker buildx bake -f .devcontainer/docker-bake.hcl
+#
+# The images get the names docker compose gives them, so the stack starts
+# without building them again.
+
+# Language version of the app's base image
+variable "PYTHON_VERSION" {
+  default = "3.12"
+}
+
+# Directory to import and export the layer cache (e.g., .dockstart/buildx-cache),
+# or empty for the builder's own cache. Exporting needs a docker-container
+# builder: docker buildx create --use
+variable "CACHE_DIR" {
+  default = ""
+}
+
+group "default" {
+  targets = ["app", "db-backup"]
+}
+
+# The sidecar images, whose Dockerfiles don't use the project's toolchain
+group "sidecars" {
+  targets = ["db-backup"]
+}
+
+target "app" {
+  context = "."
+  dockerfile = ".devcontainer/Dockerfile"
+  tags = ["python-fastapi_devcontainer-app", "python-fastapi_devcontainer-worker"]
+  args = {
+    PYTHON_VERSION = PYTHON_VERSION
+  }
+  cache-from = CACHE_DIR == "" ? [] : ["type=local,src=${CACHE_DIR}/app"]
+  cache-to = CACHE_DIR == "" ? [] : ["type=local,dest=${CACHE_DIR}/app,mode=max"]
+}
+
+target "db-backup" {
+  context = ".devcontainer"
+  dockerfile = "Dockerfile.backup"
+  tags = ["python-fastapi_devcontainer-db-backup"]
+  cache-from = CACHE_DIR == "" ? [] : ["type=local,src=${CACHE_DIR}/db-backup"]
+  cache-to = CACHE_DIR == "" ? [] : ["type=local,dest=${CACHE_DIR}/db-backup,mode=max"]
+}
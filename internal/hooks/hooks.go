@@ -0,0 +1,129 @@
+// Package hooks installs the git hooks that warn when a project's generated
+// .devcontainer files fall behind its dependencies.
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Names are the git hooks dockstart installs: pre-commit catches dependency
+// changes about to be committed, post-merge catches the ones pulled in.
+var Names = []string{"pre-commit", "post-merge"}
+
+// Marker identifies hook scripts written by dockstart, so they can be
+// updated and removed without touching anyone else's hooks.
+const Marker = "# Installed by dockstart hooks install"
+
+// script runs the drift check in warn-only mode: a stale devcontainer never
+// blocks a commit or merge, and machines without dockstart skip the check.
+const script = `#!/bin/sh
+` + Marker + `
+# Warns when .devcontainer is stale; remove with: dockstart hooks uninstall
+command -v dockstart >/dev/null 2>&1 || exit 0
+dockstart drift --hook "$(git rev-parse --show-toplevel)" || true
+`
+
+// Action is what Install or Uninstall did with one hook.
+type Action string
+
+const (
+	// Installed means the hook was written (or rewritten)
+	Installed Action = "installed"
+
+	// Removed means a dockstart hook was deleted
+	Removed Action = "removed"
+
+	// Skipped means a hook that isn't dockstart's was left alone
+	Skipped Action = "skipped"
+)
+
+// Result is the outcome for one hook.
+type Result struct {
+	// Name is the hook name (e.g., "pre-commit")
+	Name string
+
+	// Path is the hook file
+	Path string
+
+	// Action is what was done
+	Action Action
+}
+
+// Script returns the content of a dockstart hook.
+func Script() string {
+	return script
+}
+
+// Dir returns the hooks directory of the git repository containing projectPath,
+// honoring core.hooksPath and worktrees.
+func Dir(projectPath string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--path-format=absolute", "--git-path", "hooks")
+	cmd.Dir = projectPath
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s is not in a git repository: %s", projectPath, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("failed to find the git hooks directory: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Install writes the dockstart hooks to dir. Existing dockstart hooks are
+// rewritten; other hooks are skipped unless force is set, which replaces them.
+func Install(dir string, force bool) ([]Result, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	var results []Result
+	for _, name := range Names {
+		path := filepath.Join(dir, name)
+		if !force && isForeign(path) {
+			results = append(results, Result{Name: name, Path: path, Action: Skipped})
+			continue
+		}
+		if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+			return results, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		// WriteFile keeps the mode of an existing file
+		if err := os.Chmod(path, 0755); err != nil {
+			return results, fmt.Errorf("failed to make %s executable: %w", path, err)
+		}
+		results = append(results, Result{Name: name, Path: path, Action: Installed})
+	}
+	return results, nil
+}
+
+// Uninstall removes the dockstart hooks from dir, leaving other hooks alone.
+func Uninstall(dir string) ([]Result, error) {
+	var results []Result
+	for _, name := range Names {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		if isForeign(path) {
+			results = append(results, Result{Name: name, Path: path, Action: Skipped})
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return results, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		results = append(results, Result{Name: name, Path: path, Action: Removed})
+	}
+	return results, nil
+}
+
+// isForeign reports whether path is an existing hook dockstart didn't write.
+func isForeign(path string) bool {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return !strings.Contains(string(content), Marker)
+}
@@ -0,0 +1,105 @@
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestInstall tests writing and rewriting the dockstart hooks.
+func TestInstall(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "hooks")
+
+	for i := 0; i < 2; i++ {
+		results, err := Install(dir, false)
+		if err != nil {
+			t.Fatalf("Install() error = %v", err)
+		}
+		if len(results) != len(Names) {
+			t.Fatalf("Install() = %d results, want %d", len(results), len(Names))
+		}
+		for _, r := range results {
+			if r.Action != Installed {
+				t.Errorf("%s: action = %s, want %s", r.Name, r.Action, Installed)
+			}
+			content, err := os.ReadFile(r.Path)
+			if err != nil || string(content) != Script() {
+				t.Errorf("%s = %q, %v; want the dockstart script", r.Name, content, err)
+			}
+			info, _ := os.Stat(r.Path)
+			if runtime.GOOS != "windows" && info.Mode().Perm()&0100 == 0 {
+				t.Errorf("%s should be executable, mode = %v", r.Name, info.Mode())
+			}
+		}
+	}
+}
+
+// TestInstall_ForeignHook tests that other hooks are only replaced with force.
+func TestInstall_ForeignHook(t *testing.T) {
+	dir := t.TempDir()
+	foreign := filepath.Join(dir, "pre-commit")
+	if err := os.WriteFile(foreign, []byte("#!/bin/sh\nmake lint\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := Install(dir, false)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if results[0].Action != Skipped || results[1].Action != Installed {
+		t.Errorf("Install() = %+v, want pre-commit skipped and post-merge installed", results)
+	}
+	if content, _ := os.ReadFile(foreign); !strings.Contains(string(content), "make lint") {
+		t.Error("Install() should leave the existing pre-commit hook alone")
+	}
+
+	// Uninstall leaves the foreign hook too
+	results, err = Uninstall(dir)
+	if err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if len(results) != 2 || results[0].Action != Skipped || results[1].Action != Removed {
+		t.Errorf("Uninstall() = %+v, want pre-commit skipped and post-merge removed", results)
+	}
+	if _, err := os.Stat(foreign); err != nil {
+		t.Errorf("Uninstall() removed the existing pre-commit hook: %v", err)
+	}
+
+	if _, err := Install(dir, true); err != nil {
+		t.Fatalf("Install(force) error = %v", err)
+	}
+	if content, _ := os.ReadFile(foreign); string(content) != Script() {
+		t.Error("Install(force) should replace the existing pre-commit hook")
+	}
+}
+
+// TestDir tests finding the hooks directory of a repository.
+func TestDir(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	if err := exec.Command("git", "init", "-q", repo).Run(); err != nil {
+		t.Skipf("git init failed: %v", err)
+	}
+	sub := filepath.Join(repo, "app")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := Dir(sub)
+	if err != nil {
+		t.Fatalf("Dir() error = %v", err)
+	}
+	want, _ := filepath.EvalSymlinks(filepath.Join(repo, ".git", "hooks"))
+	if got, _ := filepath.EvalSymlinks(dir); got != want {
+		t.Errorf("Dir() = %q, want %q", dir, want)
+	}
+
+	if _, err := Dir(t.TempDir()); err == nil {
+		t.Error("Dir() should fail outside a git repository")
+	}
+}
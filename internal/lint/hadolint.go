@@ -0,0 +1,47 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// lookHadolint finds the hadolint binary. Tests replace it to pin the engine.
+var lookHadolint = func() (string, error) {
+	return exec.LookPath("hadolint")
+}
+
+// runHadolint lints content with the hadolint binary at path.
+func runHadolint(path string, content []byte) ([]Finding, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(path, "--format", "json", "--no-fail", "-")
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("hadolint failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return parseHadolint(stdout.Bytes())
+}
+
+// parseHadolint parses hadolint's JSON output. Findings from the ShellCheck
+// rules it embeds (SC codes) are kept along with its own DL rules.
+func parseHadolint(data []byte) ([]Finding, error) {
+	var results []struct {
+		Code    string `json:"code"`
+		Level   string `json:"level"`
+		Line    int    `json:"line"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse hadolint output: %w", err)
+	}
+
+	findings := make([]Finding, 0, len(results))
+	for _, r := range results {
+		findings = append(findings, Finding{Line: r.Line, Rule: r.Code, Severity: r.Level, Message: r.Message})
+	}
+	return findings, nil
+}
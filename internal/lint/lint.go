@@ -0,0 +1,118 @@
+// Package lint checks Dockerfiles against hadolint's rules: with hadolint
+// itself when it is installed, and otherwise with a built-in subset of its
+// DL rules, so generated Dockerfiles are checked on every machine.
+package lint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severities are hadolint's severity levels, from lowest to highest.
+var Severities = []string{"style", "info", "warning", "error"}
+
+// SeverityRank returns the position of a severity in Severities, or -1 if it
+// is not a hadolint severity. Matching is case-insensitive.
+func SeverityRank(severity string) int {
+	for i, s := range Severities {
+		if strings.EqualFold(s, severity) {
+			return i
+		}
+	}
+	return -1
+}
+
+// UnpinnedRules are hadolint's version pinning rules (apt-get, pip, npm, apk,
+// gem). Generated development images install the latest packages on purpose,
+// so generation ignores them; dockstart lint still reports them.
+var UnpinnedRules = []string{"DL3008", "DL3013", "DL3016", "DL3018", "DL3028"}
+
+// Finding is a rule a Dockerfile breaks.
+type Finding struct {
+	// File is the Dockerfile, as passed to Lint
+	File string `json:"file"`
+
+	// Line is the line of the instruction, starting at 1
+	Line int `json:"line"`
+
+	// Rule is the hadolint rule code (e.g., "DL3007")
+	Rule string `json:"rule"`
+
+	// Severity is one of Severities
+	Severity string `json:"severity"`
+
+	// Message describes the problem
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d %s %s: %s", f.File, f.Line, f.Rule, f.Severity, f.Message)
+}
+
+// AtOrAbove returns the findings with a severity of at least severity.
+func AtOrAbove(findings []Finding, severity string) []Finding {
+	rank := SeverityRank(severity)
+	var matched []Finding
+	for _, f := range findings {
+		if SeverityRank(f.Severity) >= rank {
+			matched = append(matched, f)
+		}
+	}
+	return matched
+}
+
+// Linter lints Dockerfiles.
+type Linter struct {
+	// Hadolint is the hadolint binary to run, or empty to use the built-in rules
+	Hadolint string
+
+	// Ignore are rule codes to leave out (e.g., "DL3008")
+	Ignore []string
+}
+
+// New returns a Linter that runs hadolint if it is in PATH, and the
+// built-in rules otherwise.
+func New() *Linter {
+	path, _ := lookHadolint()
+	return &Linter{Hadolint: path}
+}
+
+// Engine names what checks the Dockerfiles: "hadolint" or "built-in rules".
+func (l *Linter) Engine() string {
+	if l.Hadolint != "" {
+		return "hadolint"
+	}
+	return "built-in rules"
+}
+
+// Lint checks a Dockerfile and returns its findings, sorted by line. name is
+// only used to label them.
+func (l *Linter) Lint(name string, content []byte) ([]Finding, error) {
+	var findings []Finding
+	if l.Hadolint != "" {
+		var err error
+		if findings, err = runHadolint(l.Hadolint, content); err != nil {
+			return nil, err
+		}
+	} else {
+		findings = checkRules(parse(content))
+	}
+
+	kept := findings[:0]
+	for _, f := range findings {
+		if !containsField(l.Ignore, f.Rule) {
+			f.File = name
+			kept = append(kept, f)
+		}
+	}
+	findings = kept
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Line < findings[j].Line })
+	return findings, nil
+}
+
+// IsDockerfile reports whether a file name is a Dockerfile
+// (e.g., "Dockerfile", "Dockerfile.backup", "dev.Dockerfile").
+func IsDockerfile(name string) bool {
+	return name == "Dockerfile" || strings.HasPrefix(name, "Dockerfile.") || strings.HasSuffix(name, ".Dockerfile")
+}
@@ -0,0 +1,161 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Check the built-in rules whether or not hadolint is installed
+	lookHadolint = func() (string, error) { return "", errors.New("not installed") }
+	os.Exit(m.Run())
+}
+
+// TestLinter_Lint tests the built-in rules.
+func TestLinter_Lint(t *testing.T) {
+	tests := []struct {
+		name       string
+		dockerfile string
+		want       []string
+	}{
+		{
+			name: "clean",
+			dockerfile: `FROM golang:1.23 AS app
+WORKDIR /workspace
+RUN apt-get update && apt-get install -y \
+    git \
+    sudo \
+    && echo "dev ALL=(root) NOPASSWD:ALL" > /etc/sudoers.d/dev
+USER dev
+CMD ["sleep", "infinity"]
+
+FROM app AS worker
+CMD ["go", "run", "./cmd/worker"]
+`,
+		},
+		{
+			name:       "untagged and latest images",
+			dockerfile: "FROM node\nFROM node:latest\nFROM localhost:5000/node\nFROM node@sha256:abc\nFROM scratch\n",
+			want:       []string{"1 DL3006", "2 DL3007", "3 DL3006"},
+		},
+		{
+			name:       "stage alias and build arg",
+			dockerfile: "ARG NODE_VERSION=20\nFROM node:${NODE_VERSION} AS app\nFROM app\n",
+		},
+		{
+			name:       "deprecated and misused instructions",
+			dockerfile: "FROM alpine:3.19\nMAINTAINER me\nWORKDIR app\nADD src /src\nADD app.tar.gz /app\nADD https://example.com/x /x\n",
+			want:       []string{"2 DL4000", "3 DL3000", "4 DL3020"},
+		},
+		{
+			name: "run commands",
+			dockerfile: `FROM debian:12
+RUN cd /tmp && make
+RUN sudo apt install curl
+RUN apt-get install curl
+RUN curl -fsSL https://example.com/install.sh | sh
+RUN test -f x || echo missing
+`,
+			want: []string{"2 DL3003", "3 DL3004", "3 DL3027", "4 DL3014", "5 DL4006"},
+		},
+		{
+			name:       "pipefail shell",
+			dockerfile: "FROM alpine:3.19\nSHELL [\"/bin/ash\", \"-o\", \"pipefail\", \"-c\"]\nRUN echo x | sha256sum -c -\n",
+		},
+		{
+			name:       "shell form and repeated CMD and ENTRYPOINT",
+			dockerfile: "FROM alpine:3.19\nENTRYPOINT [\"/a\"]\nENTRYPOINT /b\nCMD [\"x\"]\nCMD [\"y\"]\n",
+			want:       []string{"3 DL3025", "3 DL4004", "5 DL4003"},
+		},
+		{
+			name:       "root user in the final stage",
+			dockerfile: "FROM alpine:3.19 AS build\nUSER root\nFROM alpine:3.19\nUSER app\nUSER root:root\n",
+			want:       []string{"5 DL3002"},
+		},
+		{
+			name:       "ignore pragma",
+			dockerfile: "# hadolint ignore=DL3006, DL3007\nFROM node\nFROM node:latest\n",
+			want:       []string{"3 DL3007"},
+		},
+	}
+
+	linter := New()
+	if linter.Engine() != "built-in rules" {
+		t.Fatalf("Engine() = %q, want built-in rules", linter.Engine())
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := linter.Lint("Dockerfile", []byte(tt.dockerfile))
+			if err != nil {
+				t.Fatalf("Lint() error = %v", err)
+			}
+			var got []string
+			for _, f := range findings {
+				if f.File != "Dockerfile" {
+					t.Errorf("finding file = %q, want Dockerfile", f.File)
+				}
+				got = append(got, fmt.Sprintf("%d %s", f.Line, f.Rule))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLinter_Ignore tests that ignored rules are left out.
+func TestLinter_Ignore(t *testing.T) {
+	linter := &Linter{Ignore: []string{"DL3006"}}
+	findings, err := linter.Lint("Dockerfile", []byte("FROM node\nMAINTAINER me\n"))
+	if err != nil {
+		t.Fatalf("Lint() error = %v", err)
+	}
+	if len(findings) != 1 || findings[0].Rule != "DL4000" {
+		t.Errorf("Lint() = %v, want only DL4000", findings)
+	}
+	if got := AtOrAbove(findings, "error"); len(got) != 1 {
+		t.Errorf("AtOrAbove(error) = %v", got)
+	}
+	if got := AtOrAbove([]Finding{{Severity: "info"}}, "warning"); len(got) != 0 {
+		t.Errorf("AtOrAbove(warning) = %v, want none", got)
+	}
+}
+
+// TestParseHadolint tests reading hadolint's JSON output.
+func TestParseHadolint(t *testing.T) {
+	output := `[{"code":"DL3008","column":1,"file":"-","level":"warning","line":7,"message":"Pin versions in apt get install"},
+{"code":"SC2086","column":1,"file":"-","level":"info","line":9,"message":"Double quote to prevent globbing and word splitting."}]`
+	findings, err := parseHadolint([]byte(output))
+	if err != nil {
+		t.Fatalf("parseHadolint() error = %v", err)
+	}
+	want := []Finding{
+		{Line: 7, Rule: "DL3008", Severity: "warning", Message: "Pin versions in apt get install"},
+		{Line: 9, Rule: "SC2086", Severity: "info", Message: "Double quote to prevent globbing and word splitting."},
+	}
+	if !reflect.DeepEqual(findings, want) {
+		t.Errorf("parseHadolint() = %v, want %v", findings, want)
+	}
+
+	if _, err := parseHadolint([]byte("not json")); err == nil {
+		t.Error("parseHadolint() should fail on invalid output")
+	}
+}
+
+// TestIsDockerfile tests recognizing Dockerfile names.
+func TestIsDockerfile(t *testing.T) {
+	for name, want := range map[string]bool{
+		"Dockerfile":           true,
+		"Dockerfile.processor": true,
+		"dev.Dockerfile":       true,
+		"docker-compose.yml":   false,
+		"Dockerfiles":          false,
+	} {
+		if got := IsDockerfile(name); got != want {
+			t.Errorf("IsDockerfile(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package lint
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// instruction is a Dockerfile instruction, with its continuation lines joined.
+type instruction struct {
+	// Line is the line the instruction starts on
+	Line int
+
+	// Keyword is the upper-cased instruction (e.g., "RUN")
+	Keyword string
+
+	// Args is the rest of the instruction
+	Args string
+
+	// Ignored are the rules a "# hadolint ignore=" comment above it turns off
+	Ignored []string
+}
+
+// ignoreRe matches hadolint's inline ignore pragma.
+var ignoreRe = regexp.MustCompile(`^#\s*hadolint\s+ignore=([A-Z0-9, ]+)`)
+
+// parse splits a Dockerfile into instructions. Comment lines inside a
+// continuation are dropped, as Docker does.
+func parse(content []byte) []instruction {
+	var instructions []instruction
+	var current *instruction
+	var ignored []string
+	for i, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") {
+			if m := ignoreRe.FindStringSubmatch(trimmed); m != nil && current == nil {
+				for _, rule := range strings.Split(m[1], ",") {
+					ignored = append(ignored, strings.TrimSpace(rule))
+				}
+			}
+			continue
+		}
+		if trimmed == "" {
+			continue
+		}
+
+		continued := strings.HasSuffix(trimmed, "\\")
+		trimmed = strings.TrimSpace(strings.TrimSuffix(trimmed, "\\"))
+		if current != nil {
+			current.Args = strings.TrimSpace(current.Args + " " + trimmed)
+		} else {
+			keyword, args, _ := strings.Cut(trimmed, " ")
+			current = &instruction{Line: i + 1, Keyword: strings.ToUpper(keyword), Args: strings.TrimSpace(args), Ignored: ignored}
+			ignored = nil
+		}
+		if !continued {
+			instructions = append(instructions, *current)
+			current = nil
+		}
+	}
+	if current != nil {
+		instructions = append(instructions, *current)
+	}
+	return instructions
+}
+
+// rule is a check on one instruction. stage holds what the instructions
+// before it in the same build stage set up.
+type rule struct {
+	code     string
+	severity string
+	message  string
+	check    func(inst instruction, stage *stageState) bool
+}
+
+// stageState is what a rule may need to know about earlier instructions.
+type stageState struct {
+	// aliases are the names of the stages defined so far
+	aliases map[string]bool
+
+	// cmds and entrypoints count the CMD and ENTRYPOINT instructions of the stage
+	cmds, entrypoints int
+
+	// pipefail is set once a SHELL instruction turns on pipefail
+	pipefail bool
+}
+
+// rules is the built-in subset of hadolint's DL rules: those that catch
+// mistakes rather than style, leaving out the version pinning rules (DL3008,
+// DL3013, DL3016, DL3018, ...) that development images deliberately skip.
+var rules = []rule{
+	{"DL3000", "error", "Use absolute WORKDIR", func(inst instruction, _ *stageState) bool {
+		if inst.Keyword != "WORKDIR" {
+			return false
+		}
+		dir := strings.Trim(inst.Args, `"'`)
+		return !strings.HasPrefix(dir, "/") && !strings.HasPrefix(dir, "$") && !windowsPathRe.MatchString(dir)
+	}},
+	{"DL3003", "warning", "Use WORKDIR to switch to a directory", func(inst instruction, _ *stageState) bool {
+		return inst.Keyword == "RUN" && runsCommand(inst.Args, "cd")
+	}},
+	{"DL3004", "error", "Do not use sudo as it leads to unpredictable behavior. Use a tool like gosu to enforce root", func(inst instruction, _ *stageState) bool {
+		return inst.Keyword == "RUN" && runsCommand(inst.Args, "sudo")
+	}},
+	{"DL3006", "warning", "Always tag the version of an image explicitly", func(inst instruction, stage *stageState) bool {
+		image := fromImage(inst)
+		return image != "" && image != "scratch" && !stage.aliases[image] &&
+			!strings.Contains(image, "$") && !strings.Contains(image, "@") && !hasTag(image)
+	}},
+	{"DL3007", "warning", "Using latest is prone to errors if the image will ever update. Pin the version explicitly to a release tag", func(inst instruction, _ *stageState) bool {
+		return strings.HasSuffix(fromImage(inst), ":latest")
+	}},
+	{"DL3014", "warning", "Use the -y switch to avoid manual input `apt-get -y install <package>`", func(inst instruction, _ *stageState) bool {
+		if inst.Keyword != "RUN" {
+			return false
+		}
+		for _, command := range commands(inst.Args) {
+			fields := strings.Fields(command)
+			if len(fields) > 1 && fields[0] == "apt-get" && containsField(fields, "install") &&
+				!containsField(fields, "-y") && !containsField(fields, "--yes") && !containsField(fields, "-qq") && !containsField(fields, "--assume-yes") {
+				return true
+			}
+		}
+		return false
+	}},
+	{"DL3020", "error", "Use COPY instead of ADD for files and folders", func(inst instruction, _ *stageState) bool {
+		if inst.Keyword != "ADD" {
+			return false
+		}
+		args := withoutFlags(strings.Fields(inst.Args))
+		if len(args) < 2 {
+			return false
+		}
+		for _, src := range args[:len(args)-1] {
+			if !strings.Contains(src, "://") && !archiveRe.MatchString(src) {
+				return true
+			}
+		}
+		return false
+	}},
+	{"DL3025", "warning", "Use arguments JSON notation for CMD and ENTRYPOINT arguments", func(inst instruction, _ *stageState) bool {
+		if inst.Keyword != "CMD" && inst.Keyword != "ENTRYPOINT" {
+			return false
+		}
+		var args []string
+		return json.Unmarshal([]byte(inst.Args), &args) != nil
+	}},
+	{"DL3027", "warning", "Do not use apt as it is meant to be an end-user tool, use apt-get or apt-cache instead", func(inst instruction, _ *stageState) bool {
+		return inst.Keyword == "RUN" && runsCommand(inst.Args, "apt")
+	}},
+	{"DL4000", "error", "MAINTAINER is deprecated", func(inst instruction, _ *stageState) bool {
+		return inst.Keyword == "MAINTAINER"
+	}},
+	{"DL4003", "warning", "Multiple `CMD` instructions found. If you list more than one `CMD` then only the last `CMD` will take effect", func(inst instruction, stage *stageState) bool {
+		return inst.Keyword == "CMD" && stage.cmds > 0
+	}},
+	{"DL4004", "error", "Multiple `ENTRYPOINT` instructions found. If you list more than one `ENTRYPOINT` then only the last `ENTRYPOINT` will take effect", func(inst instruction, stage *stageState) bool {
+		return inst.Keyword == "ENTRYPOINT" && stage.entrypoints > 0
+	}},
+	{"DL4006", "warning", "Set the SHELL option -o pipefail before RUN with a pipe in it", func(inst instruction, stage *stageState) bool {
+		return inst.Keyword == "RUN" && !stage.pipefail && !strings.HasPrefix(inst.Args, "[") && pipeRe.MatchString(inst.Args)
+	}},
+}
+
+// rootUserRule is checked once, on the final stage's last USER.
+var rootUserRule = rule{code: "DL3002", severity: "warning", message: "Last USER should not be root"}
+
+var (
+	// windowsPathRe matches an absolute Windows path (e.g., "C:\app")
+	windowsPathRe = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+
+	// archiveRe matches the local archives ADD extracts
+	archiveRe = regexp.MustCompile(`\.(tar|tar\.gz|tgz|tar\.bz2|tbz2?|tar\.xz|txz|tar\.zst)$`)
+
+	// pipeRe matches a pipe, but not ||
+	pipeRe = regexp.MustCompile(`(^|[^|])\|([^|]|$)`)
+
+	// separatorRe splits a shell command line into commands
+	separatorRe = regexp.MustCompile(`&&|\|\||;|\|`)
+)
+
+// checkRules runs the built-in rules over a Dockerfile's instructions.
+func checkRules(instructions []instruction) []Finding {
+	var findings []Finding
+	report := func(inst instruction, r rule) {
+		if !containsField(inst.Ignored, r.code) {
+			findings = append(findings, Finding{Line: inst.Line, Rule: r.code, Severity: r.severity, Message: r.message})
+		}
+	}
+
+	stage := &stageState{aliases: make(map[string]bool)}
+	var lastUser *instruction
+	for _, inst := range instructions {
+		if inst.Keyword == "FROM" {
+			stage = &stageState{aliases: stage.aliases}
+			lastUser = nil
+		}
+
+		for _, r := range rules {
+			if r.check(inst, stage) {
+				report(inst, r)
+			}
+		}
+
+		switch inst.Keyword {
+		case "FROM":
+			fields := strings.Fields(inst.Args)
+			if len(fields) >= 3 && strings.EqualFold(fields[len(fields)-2], "AS") {
+				stage.aliases[fields[len(fields)-1]] = true
+			}
+		case "CMD":
+			stage.cmds++
+		case "ENTRYPOINT":
+			stage.entrypoints++
+		case "SHELL":
+			stage.pipefail = strings.Contains(inst.Args, "pipefail")
+		case "USER":
+			user := inst
+			lastUser = &user
+		}
+	}
+
+	// Only the final stage's user runs the container
+	if lastUser != nil {
+		user, _, _ := strings.Cut(strings.TrimSpace(lastUser.Args), ":")
+		if user == "root" || user == "0" {
+			report(*lastUser, rootUserRule)
+		}
+	}
+	return findings
+}
+
+// fromImage returns the image of a FROM instruction, or an empty string.
+func fromImage(inst instruction) string {
+	if inst.Keyword != "FROM" {
+		return ""
+	}
+	fields := withoutFlags(strings.Fields(inst.Args))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// hasTag reports whether an image reference has a tag, ignoring a registry port.
+func hasTag(image string) bool {
+	name := image[strings.LastIndex(image, "/")+1:]
+	return strings.Contains(name, ":")
+}
+
+// commands splits a RUN instruction's shell form into its commands.
+func commands(args string) []string {
+	var commands []string
+	for _, command := range separatorRe.Split(args, -1) {
+		if command = strings.TrimSpace(command); command != "" {
+			commands = append(commands, command)
+		}
+	}
+	return commands
+}
+
+// runsCommand reports whether a RUN instruction's shell form runs program,
+// directly or through sudo.
+func runsCommand(args, program string) bool {
+	for _, command := range commands(args) {
+		fields := strings.Fields(command)
+		if len(fields) > 1 && fields[0] == "sudo" && program != "sudo" {
+			fields = fields[1:]
+		}
+		if len(fields) > 0 && strings.TrimLeft(fields[0], "(") == program {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutFlags drops the --flag arguments of an instruction.
+func withoutFlags(fields []string) []string {
+	var args []string
+	for _, field := range fields {
+		if !strings.HasPrefix(field, "--") {
+			args = append(args, field)
+		}
+	}
+	return args
+}
+
+// containsField reports whether fields contains value.
+func containsField(fields []string, value string) bool {
+	for _, field := range fields {
+		if field == value {
+			return true
+		}
+	}
+	return false
+}
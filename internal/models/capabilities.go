@@ -0,0 +1,261 @@
+package models
+
+import "slices"
+
+// DetectionSchema is the version of the JSON form of Detection, stored in the
+// detection cache and the generation report. It is bumped when fields are
+// renamed or restructured, so files written by an older dockstart are re-detected.
+const DetectionSchema = 2
+
+// Capabilities lists the libraries detected for each capability a sidecar or
+// generated service builds on. It is embedded in Detection, so its fields and
+// methods are used as if they were declared there.
+type Capabilities struct {
+	// LoggingLibraries is a list of detected structured logging libraries
+	// (e.g., "winston", "pino" for Node.js, "zap", "zerolog" for Go)
+	LoggingLibraries []string `json:"logging,omitempty"`
+
+	// QueueLibraries is a list of detected job queue/worker libraries
+	// (e.g., "bull", "bullmq" for Node.js, "celery" for Python)
+	QueueLibraries []string `json:"queue,omitempty"`
+
+	// SchedulerLibraries is a list of detected cron/scheduled-job libraries
+	// (e.g., "node-cron" for Node.js, "apscheduler", "celery-beat" for Python)
+	SchedulerLibraries []string `json:"scheduler,omitempty"`
+
+	// FileUploadLibraries is a list of detected file upload libraries
+	// (e.g., "multer", "formidable" for Node.js, "python-multipart" for Python)
+	FileUploadLibraries []string `json:"file_upload,omitempty"`
+
+	// MetricsLibraries is a list of detected Prometheus metrics libraries
+	// (e.g., "prom-client" for Node.js, "prometheus/client_golang" for Go)
+	MetricsLibraries []string `json:"metrics,omitempty"`
+
+	// TracingLibraries is a list of detected distributed tracing libraries
+	// (e.g., "@opentelemetry/sdk-node" for Node.js, "go.opentelemetry.io/otel" for Go)
+	TracingLibraries []string `json:"tracing,omitempty"`
+
+	// WebsocketLibraries is a list of detected WebSocket libraries
+	// (e.g., "socket.io", "ws" for Node.js, "gorilla/websocket" for Go)
+	WebsocketLibraries []string `json:"websocket,omitempty"`
+
+	// GRPCLibraries is a list of detected gRPC server libraries
+	// (e.g., "@grpc/grpc-js" for Node.js, "grpcio" for Python, "tonic" for Rust)
+	GRPCLibraries []string `json:"grpc,omitempty"`
+
+	// AuthLibraries is a list of detected OIDC/OAuth client libraries
+	// (e.g., "next-auth" for Node.js, "authlib" for Python, "oauth2" for Go)
+	AuthLibraries []string `json:"auth,omitempty"`
+
+	// PaymentLibraries is a list of detected Stripe SDKs
+	// (e.g., "stripe" for Node.js/Python, "stripe-go" for Go, "async-stripe" for Rust)
+	PaymentLibraries []string `json:"payment,omitempty"`
+
+	// AWSServices is a list of AWS services used through service-specific SDK clients
+	// (e.g., "sqs", "sns", "dynamodb", "s3")
+	AWSServices []string `json:"aws_services,omitempty"`
+
+	// VectorLibraries is a list of detected vector database clients
+	// (e.g., "qdrant-client", "chromadb", "pgvector", "langchain-qdrant")
+	VectorLibraries []string `json:"vector,omitempty"`
+
+	// WebDriverLibraries is a list of detected Selenium/WebDriver clients
+	// (e.g., "selenium-webdriver" for Node.js, "selenium" for Python, "thirtyfour" for Rust)
+	WebDriverLibraries []string `json:"webdriver,omitempty"`
+
+	// LLMLibraries is a list of detected LLM client libraries
+	// (e.g., "openai", "anthropic", "ollama")
+	LLMLibraries []string `json:"llm,omitempty"`
+
+	// HTTPClientLibraries is a list of detected HTTP client libraries
+	// (e.g., "axios", "requests", "reqwest", "resty")
+	HTTPClientLibraries []string `json:"http_client,omitempty"`
+
+	// ErrorTrackingLibraries is a list of detected Sentry SDKs
+	// (e.g., "@sentry/node", "sentry-sdk", "sentry-go", "sentry")
+	ErrorTrackingLibraries []string `json:"error_tracking,omitempty"`
+
+	// GeoLibraries is a list of detected geospatial libraries and PostGIS adapters
+	// (e.g., "geoalchemy2", "geopandas", "knex-postgis", "go-geom", "geozero")
+	GeoLibraries []string `json:"geo,omitempty"`
+
+	// SearchLibraries is a list of detected Meilisearch and Typesense clients
+	// (e.g., "meilisearch", "meilisearch-go", "typesense", "typesense-go")
+	SearchLibraries []string `json:"search,omitempty"`
+
+	// MailLibraries is a list of detected SMTP mail libraries
+	// (e.g., "nodemailer", "fastapi-mail", "go-mail", "lettre")
+	MailLibraries []string `json:"mail,omitempty"`
+}
+
+// CapabilityList is one capability's detected libraries, named for messages.
+type CapabilityList struct {
+	// Name describes the list (e.g., "queue libraries", "AWS services")
+	Name string
+
+	// Libraries are the detected libraries, or services for AWS
+	Libraries []string
+}
+
+// Lists returns every capability's libraries, in a stable order.
+func (c *Capabilities) Lists() []CapabilityList {
+	return []CapabilityList{
+		{"logging libraries", c.LoggingLibraries},
+		{"queue libraries", c.QueueLibraries},
+		{"scheduler libraries", c.SchedulerLibraries},
+		{"upload libraries", c.FileUploadLibraries},
+		{"metrics libraries", c.MetricsLibraries},
+		{"tracing libraries", c.TracingLibraries},
+		{"websocket libraries", c.WebsocketLibraries},
+		{"gRPC libraries", c.GRPCLibraries},
+		{"auth libraries", c.AuthLibraries},
+		{"payment libraries", c.PaymentLibraries},
+		{"AWS services", c.AWSServices},
+		{"vector libraries", c.VectorLibraries},
+		{"WebDriver libraries", c.WebDriverLibraries},
+		{"LLM libraries", c.LLMLibraries},
+		{"HTTP client libraries", c.HTTPClientLibraries},
+		{"error tracking libraries", c.ErrorTrackingLibraries},
+		{"geospatial libraries", c.GeoLibraries},
+		{"search libraries", c.SearchLibraries},
+		{"mail libraries", c.MailLibraries},
+	}
+}
+
+// appendMissing appends item to list unless it is already present.
+func appendMissing(list []string, item string) []string {
+	if slices.Contains(list, item) {
+		return list
+	}
+	return append(list, item)
+}
+
+// HasLoggingLibrary checks if a specific logging library was detected.
+func (c *Capabilities) HasLoggingLibrary(library string) bool {
+	return slices.Contains(c.LoggingLibraries, library)
+}
+
+// AddLoggingLibrary adds a logging library if not already present.
+func (c *Capabilities) AddLoggingLibrary(library string) {
+	c.LoggingLibraries = appendMissing(c.LoggingLibraries, library)
+}
+
+// HasQueueLibrary checks if a specific queue library was detected.
+func (c *Capabilities) HasQueueLibrary(library string) bool {
+	return slices.Contains(c.QueueLibraries, library)
+}
+
+// AddQueueLibrary adds a queue library if not already present.
+func (c *Capabilities) AddQueueLibrary(library string) {
+	c.QueueLibraries = appendMissing(c.QueueLibraries, library)
+}
+
+// HasFileUploadLibrary checks if a specific file upload library was detected.
+func (c *Capabilities) HasFileUploadLibrary(library string) bool {
+	return slices.Contains(c.FileUploadLibraries, library)
+}
+
+// AddFileUploadLibrary adds a file upload library if not already present.
+func (c *Capabilities) AddFileUploadLibrary(library string) {
+	c.FileUploadLibraries = appendMissing(c.FileUploadLibraries, library)
+}
+
+// HasMetricsLibrary checks if a specific metrics library was detected.
+func (c *Capabilities) HasMetricsLibrary(library string) bool {
+	return slices.Contains(c.MetricsLibraries, library)
+}
+
+// AddMetricsLibrary adds a metrics library if not already present.
+func (c *Capabilities) AddMetricsLibrary(library string) {
+	c.MetricsLibraries = appendMissing(c.MetricsLibraries, library)
+}
+
+// HasTracingLibrary checks if a specific tracing library was detected.
+func (c *Capabilities) HasTracingLibrary(library string) bool {
+	return slices.Contains(c.TracingLibraries, library)
+}
+
+// AddTracingLibrary adds a tracing library if not already present.
+func (c *Capabilities) AddTracingLibrary(library string) {
+	c.TracingLibraries = appendMissing(c.TracingLibraries, library)
+}
+
+// HasWebsocketLibrary checks if a specific WebSocket library was detected.
+func (c *Capabilities) HasWebsocketLibrary(library string) bool {
+	return slices.Contains(c.WebsocketLibraries, library)
+}
+
+// AddWebsocketLibrary adds a WebSocket library if not already present.
+func (c *Capabilities) AddWebsocketLibrary(library string) {
+	c.WebsocketLibraries = appendMissing(c.WebsocketLibraries, library)
+}
+
+// HasGRPCLibrary checks if a specific gRPC library was detected.
+func (c *Capabilities) HasGRPCLibrary(library string) bool {
+	return slices.Contains(c.GRPCLibraries, library)
+}
+
+// AddGRPCLibrary adds a gRPC library if not already present.
+func (c *Capabilities) AddGRPCLibrary(library string) {
+	c.GRPCLibraries = appendMissing(c.GRPCLibraries, library)
+}
+
+// HasAuthLibrary checks if a specific OIDC/OAuth library was detected.
+func (c *Capabilities) HasAuthLibrary(library string) bool {
+	return slices.Contains(c.AuthLibraries, library)
+}
+
+// AddAuthLibrary adds an OIDC/OAuth library if not already present.
+func (c *Capabilities) AddAuthLibrary(library string) {
+	c.AuthLibraries = appendMissing(c.AuthLibraries, library)
+}
+
+// HasPaymentLibrary checks if a specific payment SDK was detected.
+func (c *Capabilities) HasPaymentLibrary(library string) bool {
+	return slices.Contains(c.PaymentLibraries, library)
+}
+
+// AddPaymentLibrary adds a payment SDK if not already present.
+func (c *Capabilities) AddPaymentLibrary(library string) {
+	c.PaymentLibraries = appendMissing(c.PaymentLibraries, library)
+}
+
+// HasAWSService checks if a specific AWS service client was detected.
+func (c *Capabilities) HasAWSService(service string) bool {
+	return slices.Contains(c.AWSServices, service)
+}
+
+// AddAWSService adds an AWS service if not already present.
+func (c *Capabilities) AddAWSService(service string) {
+	c.AWSServices = appendMissing(c.AWSServices, service)
+}
+
+// HasVectorLibrary checks if a specific vector database client was detected.
+func (c *Capabilities) HasVectorLibrary(library string) bool {
+	return slices.Contains(c.VectorLibraries, library)
+}
+
+// AddVectorLibrary adds a vector database client if not already present.
+func (c *Capabilities) AddVectorLibrary(library string) {
+	c.VectorLibraries = appendMissing(c.VectorLibraries, library)
+}
+
+// HasLLMLibrary checks if a specific LLM client library was detected.
+func (c *Capabilities) HasLLMLibrary(library string) bool {
+	return slices.Contains(c.LLMLibraries, library)
+}
+
+// AddLLMLibrary adds an LLM client library if not already present.
+func (c *Capabilities) AddLLMLibrary(library string) {
+	c.LLMLibraries = appendMissing(c.LLMLibraries, library)
+}
+
+// HasSchedulerLibrary checks if a specific scheduler library was detected.
+func (c *Capabilities) HasSchedulerLibrary(library string) bool {
+	return slices.Contains(c.SchedulerLibraries, library)
+}
+
+// AddSchedulerLibrary adds a scheduler library if not already present.
+func (c *Capabilities) AddSchedulerLibrary(library string) {
+	c.SchedulerLibraries = appendMissing(c.SchedulerLibraries, library)
+}
@@ -0,0 +1,43 @@
+package models
+
+// ExistingCompose is a compose file the project already has. Its services are
+// imported into the generated docker-compose.yml instead of being duplicated.
+type ExistingCompose struct {
+	// File is the compose file path relative to the project root (e.g., "docker-compose.yml")
+	File string `json:"file,omitempty"`
+
+	// Services are the file's services, in file order
+	Services []ExistingService `json:"services,omitempty"`
+
+	// Volumes, Networks, Configs, and Secrets are the file's top-level definitions
+	Volumes  map[string]interface{} `json:"volumes,omitempty"`
+	Networks map[string]interface{} `json:"networks,omitempty"`
+	Configs  map[string]interface{} `json:"configs,omitempty"`
+	Secrets  map[string]interface{} `json:"secrets,omitempty"`
+}
+
+// ExistingService is a service defined in an existing compose file.
+type ExistingService struct {
+	// Name is the compose service name (e.g., "db")
+	Name string `json:"name,omitempty"`
+
+	// Role is the backing service it runs (e.g., "postgres"), or empty
+	Role string `json:"role,omitempty"`
+
+	// Definition is the service's compose definition, with YAML anchors resolved
+	Definition map[string]interface{} `json:"definition,omitempty"`
+}
+
+// ExistingServiceFor returns the name of the existing compose service that runs
+// a backing service (e.g., "db" for "postgres"), or an empty string.
+func (d *Detection) ExistingServiceFor(role string) string {
+	if d.ExistingCompose == nil {
+		return ""
+	}
+	for _, service := range d.ExistingCompose.Services {
+		if service.Role == role {
+			return service.Name
+		}
+	}
+	return ""
+}
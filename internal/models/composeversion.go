@@ -0,0 +1,35 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Compose implementations the generated docker-compose.yml can target, as
+// named by --compose-version.
+const (
+	// ComposeV2 is Docker Compose v2 (docker compose), which implements the
+	// full Compose Specification (the default)
+	ComposeV2 = "v2"
+
+	// ComposeV1 is the standalone docker-compose 1.x, which applies deploy
+	// resource limits only with --compatibility, and needs 1.28 for profiles
+	// and 1.29 for service_completed_successfully
+	ComposeV1 = "v1"
+
+	// ComposeSwarm is docker stack deploy on a Swarm, which ignores build,
+	// depends_on, and restart, and doesn't support profiles or extends
+	ComposeSwarm = "swarm"
+)
+
+// ComposeVersions lists the valid compose versions.
+var ComposeVersions = []string{ComposeV2, ComposeV1, ComposeSwarm}
+
+// ValidateComposeVersion checks that version is empty (v2) or a known compose version.
+func ValidateComposeVersion(version string) error {
+	if version != "" && !slices.Contains(ComposeVersions, version) {
+		return fmt.Errorf("unknown compose version %q: expected one of %s", version, strings.Join(ComposeVersions, ", "))
+	}
+	return nil
+}
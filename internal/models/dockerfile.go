@@ -0,0 +1,72 @@
+package models
+
+// ExistingDockerfile is a Dockerfile the project already has. When it has a
+// usable dev target, the devcontainer builds from it instead of a generated one.
+type ExistingDockerfile struct {
+	// File is the Dockerfile path relative to the project root (e.g., "Dockerfile")
+	File string `json:"file,omitempty"`
+
+	// Target is the build stage to use (e.g., "dev"), or empty for the last stage
+	Target string `json:"target,omitempty"`
+
+	// User is the user the target stage runs as (from USER), or empty for root
+	User string `json:"user,omitempty"`
+
+	// WorkerTarget is the stage that builds the background worker (e.g., "worker"),
+	// or empty when the worker shares the app's image
+	WorkerTarget string `json:"worker_target,omitempty"`
+
+	// Problem explains why the Dockerfile can't be used for development, or is
+	// empty if it can (e.g., "multi-stage build has no dev stage")
+	Problem string `json:"problem,omitempty"`
+}
+
+// ProductionDockerfile is the Dockerfile a project ships to production.
+type ProductionDockerfile struct {
+	// File is the Dockerfile path relative to the project root (e.g., "Dockerfile")
+	File string `json:"file"`
+
+	// Target is the production build stage (e.g., "production"), or empty for the last stage
+	Target string `json:"target,omitempty"`
+}
+
+// ReusesDockerfile returns true if the devcontainer builds from the project's own Dockerfile.
+func (d *Detection) ReusesDockerfile() bool {
+	return d.ExistingDockerfile != nil && d.ExistingDockerfile.Problem == ""
+}
+
+// WorkerStage returns the Dockerfile stage the worker is built from when it has
+// its own, or an empty string when it shares the app's image.
+func (d *Detection) WorkerStage() string {
+	if !d.NeedsWorker() {
+		return ""
+	}
+	if d.ReusesDockerfile() {
+		return d.ExistingDockerfile.WorkerTarget
+	}
+	if d.Worker.Target {
+		return "worker"
+	}
+	return ""
+}
+
+// BuildsMultipleImages returns true if the compose stack builds more than one
+// image: the app plus a worker stage or the file processor, backup, or
+// scheduler sidecar. They are then built together with docker buildx bake.
+func (d *Detection) BuildsMultipleImages() bool {
+	if !d.NeedsCompose() {
+		return false
+	}
+	images := 1
+	for _, builds := range []bool{
+		d.WorkerStage() != "",
+		d.NeedsFileProcessor(),
+		d.NeedsSidecar(SidecarBackup),
+		d.NeedsScheduler() && d.SchedulerCommand == "",
+	} {
+		if builds {
+			images++
+		}
+	}
+	return images > 1
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Environments docker-compose.yml overrides are generated for, as named by --env.
+const (
+	// EnvDev adds debugger ports and development settings; devcontainer.json
+	// layers it over docker-compose.yml
+	EnvDev = "dev"
+
+	// EnvTest keeps the databases' data in tmpfs, so every run starts empty
+	EnvTest = "test"
+
+	// EnvStaging builds the app from the project's production Dockerfile and
+	// runs it without the workspace mounted
+	EnvStaging = "staging"
+)
+
+// ComposeEnvironments lists the valid environments, in the order their
+// overrides are generated.
+var ComposeEnvironments = []string{EnvDev, EnvTest, EnvStaging}
+
+// ValidateEnvironments checks that every environment is known and listed once.
+func ValidateEnvironments(environments []string) error {
+	for i, env := range environments {
+		if !slices.Contains(ComposeEnvironments, env) {
+			return fmt.Errorf("unknown environment %q: expected one of %s", env, strings.Join(ComposeEnvironments, ", "))
+		}
+		if slices.Contains(environments[:i], env) {
+			return fmt.Errorf("environment %q is listed twice", env)
+		}
+	}
+	return nil
+}
+
+// HasEnvironment returns true if a compose override is generated for env.
+func (d *Detection) HasEnvironment(env string) bool {
+	return d.NeedsCompose() && slices.Contains(d.Environments, env)
+}
+
+// OverrideEnvironments returns the environments compose overrides are
+// generated for, in ComposeEnvironments order.
+func (d *Detection) OverrideEnvironments() []string {
+	var envs []string
+	for _, env := range ComposeEnvironments {
+		if d.HasEnvironment(env) {
+			envs = append(envs, env)
+		}
+	}
+	return envs
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Credentials forwarded from the host into the dev container, as named by --forward.
+const (
+	// ForwardSSH mounts the host's SSH agent socket and known hosts, so git
+	// and package managers can fetch private repositories over SSH
+	ForwardSSH = "ssh"
+
+	// ForwardGitConfig mounts the host's ~/.gitconfig as the container's
+	// system git config: identity, URL rewrites, and credential settings
+	ForwardGitConfig = "gitconfig"
+
+	// ForwardGPG mounts the host's gpg-agent socket and public keyring, so
+	// commits made in the container are signed with the host's keys
+	ForwardGPG = "gpg"
+)
+
+// CredentialForwards lists the valid forwards, in the order they are mounted.
+var CredentialForwards = []string{ForwardSSH, ForwardGitConfig, ForwardGPG}
+
+// ValidateForwards checks that every forward is known and listed once.
+func ValidateForwards(forwards []string) error {
+	for i, forward := range forwards {
+		if !slices.Contains(CredentialForwards, forward) {
+			return fmt.Errorf("unknown forward %q: expected one of %s", forward, strings.Join(CredentialForwards, ", "))
+		}
+		if slices.Contains(forwards[:i], forward) {
+			return fmt.Errorf("forward %q is listed twice", forward)
+		}
+	}
+	return nil
+}
+
+// Forwards returns true if the host credential is forwarded into the dev container.
+func (d *Detection) Forwards(forward string) bool {
+	return slices.Contains(d.Forward, forward)
+}
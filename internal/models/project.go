@@ -1,65 +1,289 @@
 // Package models contains shared data structures used across the application.
 package models
 
+import (
+	"slices"
+	"strings"
+)
+
 // Detection represents the result of analyzing a project directory.
 // It contains information about the detected language, version, and services.
 type Detection struct {
 	// Language is the primary programming language detected (e.g., "node", "go", "python", "rust")
-	Language string
+	Language string `json:"language"`
 
 	// Version is the detected or inferred language version (e.g., "20", "1.23", "3.11")
-	Version string
+	Version string `json:"version"`
 
 	// Services is a list of detected backing services (e.g., "postgres", "redis")
-	Services []string
+	Services []string `json:"services,omitempty"`
+
+	// ServiceVersions maps backing services to the version to run (e.g., "postgres": "15"),
+	// inferred from existing compose files, CI workflows, or .tool-versions, or set in
+	// .dockstart.yml. Services without an entry use their default version.
+	ServiceVersions map[string]string `json:"service_versions,omitempty"`
+
+	// TimescaleDB runs PostgreSQL with the TimescaleDB extension, because the
+	// migrations create hypertables, an existing compose file runs its image,
+	// or .dockstart.yml asks for it
+	TimescaleDB bool `json:"timescaledb,omitempty"`
+
+	// PostgresDatabases are additional databases created in the generated
+	// PostgreSQL next to the development database, from .dockstart.yml
+	PostgresDatabases []string `json:"postgres_databases,omitempty"`
+
+	// PostgresExtensions are created in every database of the generated
+	// PostgreSQL on first start, from .dockstart.yml
+	PostgresExtensions []string `json:"postgres_extensions,omitempty"`
+
+	// PostgresRoles are extra roles created in the generated PostgreSQL on
+	// first start, from .dockstart.yml
+	PostgresRoles []PostgresRole `json:"postgres_roles,omitempty"`
+
+	// ProdImport configures the script importing an anonymized copy of the
+	// production database, from postgres.prod_import in .dockstart.yml.
+	// Nil unless it is enabled there.
+	ProdImport *ProdImportOptions `json:"prod_import,omitempty"`
+
+	// Resources overrides the memory and CPU limits of generated services,
+	// by compose service name, from .dockstart.yml
+	Resources map[string]ResourceLimits `json:"resources,omitempty"`
+
+	// ExistingCompose is the project's own compose file, whose services are
+	// imported into the generated one. Nil when the project has none.
+	ExistingCompose *ExistingCompose `json:"existing_compose,omitempty"`
+
+	// ExistingDockerfile is the project's own Dockerfile, built instead of a
+	// generated one when it has a usable dev target. Nil when the project has none.
+	ExistingDockerfile *ExistingDockerfile `json:"existing_dockerfile,omitempty"`
+
+	// ProductionDockerfile is the project's own Dockerfile at its root, which
+	// the staging compose override builds. Nil when the project has none.
+	ProductionDockerfile *ProductionDockerfile `json:"production_dockerfile,omitempty"`
 
 	// Confidence is a score from 0.0 to 1.0 indicating detection certainty
 	// Higher values mean more confident detection (e.g., explicit version vs inferred)
-	Confidence float64
+	Confidence float64 `json:"confidence"`
+
+	// Auxiliary is true when the manifest looks like supporting tooling (a frontend
+	// app, docs site, or dev-only tooling) rather than the backend runtime.
+	// Backend languages win over auxiliary detections in multi-language repos.
+	Auxiliary bool `json:"auxiliary,omitempty"`
+
+	// Lockfile is the lockfile that refined dependency detection (e.g., "package-lock.json").
+	// Empty unless lockfile parsing is enabled and a lockfile was found.
+	Lockfile string `json:"lockfile,omitempty"`
+
+	// PinnedVersions maps the manifest's direct dependencies to their locked versions
+	PinnedVersions map[string]string `json:"pinned_versions,omitempty"`
+
+	// Dependencies lists the dependencies the manifest declares, sorted by name
+	Dependencies []string `json:"dependencies,omitempty"`
+
+	// PackageManager is the tool that installs dependencies, picked from the
+	// lockfile present (e.g., "npm", "yarn", "pnpm", "poetry", "uv", "pip", "go", "cargo")
+	PackageManager string `json:"package_manager,omitempty"`
+
+	// InstallCommand installs dependencies, from the lockfile when there is one
+	// (e.g., "npm ci", "poetry install", "go mod download", "cargo fetch")
+	InstallCommand string `json:"install_command,omitempty"`
+
+	// MigrationTool is the detected database migration tool
+	// (e.g., "prisma", "alembic", "django", "goose", "diesel"), or empty if none
+	MigrationTool string `json:"migration_tool,omitempty"`
+
+	// MigrateCommand applies pending database migrations (e.g., "npx prisma migrate deploy")
+	MigrateCommand string `json:"migrate_command,omitempty"`
 
-	// LoggingLibraries is a list of detected structured logging libraries
-	// (e.g., "winston", "pino" for Node.js, "zap", "zerolog" for Go)
-	LoggingLibraries []string
+	// TestCommand runs the test suite (e.g., "npm test", "poetry run pytest", "go test ./...")
+	TestCommand string `json:"test_command,omitempty"`
+
+	// CoverageCommand runs the test suite with coverage, or is empty when no
+	// coverage tool was detected (e.g., "npx jest --coverage")
+	CoverageCommand string `json:"coverage_command,omitempty"`
+
+	// Lifecycle holds devcontainer.json lifecycle command overrides from .dockstart.yml
+	Lifecycle LifecycleOptions `json:"lifecycle"`
+
+	// Capabilities are the libraries detected per capability (logging, queues,
+	// metrics, tracing, ...), which decide the sidecars that are generated
+	Capabilities `json:"capabilities"`
 
 	// LogFormat indicates the detected or inferred log format
 	// Values: "json", "text", "unknown"
-	LogFormat string
-
-	// QueueLibraries is a list of detected job queue/worker libraries
-	// (e.g., "bull", "bullmq" for Node.js, "celery" for Python)
-	QueueLibraries []string
+	LogFormat string `json:"log_format,omitempty"`
 
 	// WorkerCommand is the detected or inferred command to start the worker
 	// (e.g., "npm run worker", "celery -A app worker")
-	WorkerCommand string
+	WorkerCommand string `json:"worker_command,omitempty"`
 
-	// FileUploadLibraries is a list of detected file upload libraries
-	// (e.g., "multer", "formidable" for Node.js, "python-multipart" for Python)
-	FileUploadLibraries []string
+	// Worker holds worker scaling settings from .dockstart.yml or --worker-* flags
+	Worker WorkerOptions `json:"worker"`
 
 	// UploadPath is the detected upload directory path (e.g., "/uploads", "uploads/")
 	// Empty string if not detected
-	UploadPath string
+	UploadPath string `json:"upload_path,omitempty"`
 
-	// MetricsLibraries is a list of detected Prometheus metrics libraries
-	// (e.g., "prom-client" for Node.js, "prometheus/client_golang" for Go)
-	MetricsLibraries []string
+	// FileProcessor holds file processor pipeline settings from .dockstart.yml or --processor-* flags
+	FileProcessor FileProcessorOptions `json:"file_processor"`
 
 	// MetricsPort is the detected or inferred port for the /metrics endpoint
 	// Default: same as app port (e.g., 3000 for Node.js, 8080 for Go)
-	MetricsPort int
+	MetricsPort int `json:"metrics_port,omitempty"`
 
 	// MetricsPath is the detected or inferred path for the metrics endpoint
 	// Default: "/metrics"
-	MetricsPath string
-
-	// TracingLibraries is a list of detected distributed tracing libraries
-	// (e.g., "@opentelemetry/sdk-node" for Node.js, "go.opentelemetry.io/otel" for Go)
-	TracingLibraries []string
+	MetricsPath string `json:"metrics_path,omitempty"`
 
 	// TracingProtocol is the detected or inferred tracing protocol
 	// Values: "otlp", "jaeger", "zipkin", "unknown"
-	TracingProtocol string
+	TracingProtocol string `json:"tracing_protocol,omitempty"`
+
+	// TypeScript indicates the project is written in TypeScript
+	// (tsconfig.json present or "typescript" in dependencies)
+	TypeScript bool `json:"typescript,omitempty"`
+
+	// BuildCommand is the command that compiles the project (e.g., "npm run build")
+	// Empty string if no build script was detected
+	BuildCommand string `json:"build_command,omitempty"`
+
+	// BuildOutputDir is the directory containing compiled output (e.g., "dist")
+	// Read from tsconfig.json compilerOptions.outDir when available
+	BuildOutputDir string `json:"build_output_dir,omitempty"`
+
+	// DevRunner is the TypeScript runner used in development when there is no build step
+	// (e.g., "tsx", "ts-node"). Empty string if none was detected
+	DevRunner string `json:"dev_runner,omitempty"`
+
+	// FrontendFramework is the detected frontend framework
+	// Values: "nextjs", "vite", "cra", "sveltekit", "astro", or empty if none
+	FrontendFramework string `json:"frontend_framework,omitempty"`
+
+	// FrontendDir is the frontend directory relative to the project root
+	// (e.g., "frontend", "." for a root package.json shared with a backend).
+	// Empty string when the frontend is the main app itself
+	FrontendDir string `json:"frontend_dir,omitempty"`
+
+	// FrontendPort is the port the frontend dev server listens on (e.g., 3000, 5173)
+	FrontendPort int `json:"frontend_port,omitempty"`
+
+	// GRPCPort is the port where the gRPC server listens.
+	// Zero means use the default (50051)
+	GRPCPort int `json:"grpc_port,omitempty"`
+
+	// ExternalAPIs are the environment variables in the project's .env files
+	// that point at third-party HTTP APIs (e.g., "PAYMENTS_API_URL")
+	ExternalAPIs []string `json:"external_apis,omitempty"`
+
+	// MockAPIs runs a WireMock sidecar that stubs the external APIs.
+	// Opted into with --wiremock
+	MockAPIs bool `json:"mock_apis,omitempty"`
+
+	// ChaosProxy puts a Toxiproxy sidecar between the app and its databases.
+	// Opted into with --toxiproxy
+	ChaosProxy bool `json:"chaos_proxy,omitempty"`
+
+	// LocalErrorTracking runs a self-hosted GlitchTip that receives the Sentry
+	// SDKs' events. Opted into with --glitchtip
+	LocalErrorTracking bool `json:"local_error_tracking,omitempty"`
+
+	// StatusPage runs a Gatus status page that checks the services of the stack.
+	// Opted into with --gatus or status_page in .dockstart.yml
+	StatusPage bool `json:"status_page,omitempty"`
+
+	// HealthPath is the app's health endpoint the status page checks (e.g., "/health")
+	HealthPath string `json:"health_path,omitempty"`
+
+	// Sidecars overrides which optional sidecars (logging, metrics, tracing,
+	// backups, file processing, dashboards, and admin UIs) are generated
+	Sidecars SidecarPolicy `json:"sidecars"`
+
+	// LocalLLM indicates that LLM calls should go to a local Ollama sidecar.
+	// Set automatically when an Ollama client is detected, or opted into with --ollama
+	LocalLLM bool `json:"local_llm,omitempty"`
+
+	// SchedulerCommand is the command that runs the scheduler as its own process
+	// (e.g., "celery -A app beat", "npm run scheduler").
+	// Empty string when jobs are scheduled in-process or triggered via app endpoints
+	SchedulerCommand string `json:"scheduler_command,omitempty"`
+
+	// NonRoot runs the generated containers as a non-root user whose UID/GID
+	// match the host (USER_UID/USER_GID build args), from non_root in
+	// .dockstart.yml or --non-root
+	NonRoot bool `json:"non_root,omitempty"`
+
+	// Hardened locks down the generated compose services (read-only root
+	// filesystem, no-new-privileges, dropped capabilities), from hardened in
+	// .dockstart.yml or --hardened
+	Hardened bool `json:"hardened,omitempty"`
+
+	// Windows adapts the generated files to Windows and WSL hosts (LF line
+	// endings, dependency directories in named volumes, long-syntax workspace
+	// mounts), from windows in .dockstart.yml or --windows, and on by default on Windows
+	Windows bool `json:"windows,omitempty"`
+
+	// RemoteDocker means the Docker engine runs on another machine (DOCKER_HOST
+	// or the docker context points at ssh:// or a remote tcp:// host), where
+	// bind mounts of local paths don't work: the workspace is synced into a
+	// named volume instead. From remote_docker in .dockstart.yml or
+	// --remote-docker, and detected from the docker CLI otherwise
+	RemoteDocker bool `json:"remote_docker,omitempty"`
+
+	// Prebuild orders the generated Dockerfile for layer caching, with the
+	// dependencies installed from the manifests alone, and caches image builds
+	// from a prebuilt image, from prebuild in .dockstart.yml or --prebuild.
+	// Nil unless enabled
+	Prebuild *PrebuildOptions `json:"prebuild,omitempty"`
+
+	// Target is the environment the files are generated for: empty or
+	// TargetLocal for Docker on the developer's machine, TargetCodespaces for
+	// GitHub Codespaces, from target in .dockstart.yml or --target
+	Target string `json:"target,omitempty"`
+
+	// ComposeVersion is the compose implementation the generated
+	// docker-compose.yml is adapted to: empty or ComposeV2 for Docker Compose
+	// v2, ComposeV1 for docker-compose 1.x, ComposeSwarm for docker stack
+	// deploy, from compose_version in .dockstart.yml or --compose-version
+	ComposeVersion string `json:"compose_version,omitempty"`
+
+	// Environments are the environments docker-compose.<env>.yml overrides
+	// are generated for (EnvDev, EnvTest, EnvStaging), from environments in
+	// .dockstart.yml or --env
+	Environments []string `json:"environments,omitempty"`
+
+	// Forward are the host credentials mounted into the dev container
+	// (ForwardSSH, ForwardGitConfig, ForwardGPG), from forward in
+	// .dockstart.yml or --forward
+	Forward []string `json:"forward,omitempty"`
+
+	// Persistence keeps shell history and tool caches in named volumes across
+	// rebuilds and clones a dotfiles repository, from persistence in
+	// .dockstart.yml or --persist
+	Persistence PersistenceOptions `json:"persistence"`
+
+	// Registries are the private package registries the package managers
+	// use, from registries in .dockstart.yml
+	Registries RegistryOptions `json:"registries"`
+
+	// Proxy is the corporate proxy the containers and image builds go
+	// through, from proxy in .dockstart.yml
+	Proxy ProxyOptions `json:"proxy"`
+
+	// Timezone is the IANA timezone the containers use instead of UTC, from
+	// timezone in .dockstart.yml
+	Timezone string `json:"timezone,omitempty"`
+
+	// Locale is the dev container's locale (e.g., "fr_FR.UTF-8"), from locale
+	// in .dockstart.yml
+	Locale string `json:"locale,omitempty"`
+
+	// Testing configures the databases test suites run against, from testing
+	// in .dockstart.yml
+	Testing TestingOptions `json:"testing"`
+
+	// Logging configures log rotation and the log sidecar's file output, from
+	// logging in .dockstart.yml
+	Logging LoggingOptions `json:"logging"`
 }
 
 // Project represents a fully analyzed project with all its detections.
@@ -84,6 +308,26 @@ func (d *Detection) HasService(service string) bool {
 	return false
 }
 
+// defaultServiceVersions are the versions run when none is inferred or configured.
+var defaultServiceVersions = map[string]string{
+	"postgres":   "16",
+	"redis":      "7",
+	"clickhouse": "24.8",
+	"memcached":  "1.6",
+	"nats":       "2.10",
+	"temporal":   "1.25",
+	"influxdb":   "2.7",
+}
+
+// GetServiceVersion returns the version to run for a backing service,
+// falling back to the default version for that service.
+func (d *Detection) GetServiceVersion(service string) string {
+	if version := d.ServiceVersions[service]; version != "" {
+		return version
+	}
+	return defaultServiceVersions[service]
+}
+
 // AddService adds a service to the detection if not already present.
 func (d *Detection) AddService(service string) {
 	if !d.HasService(service) {
@@ -91,43 +335,329 @@ func (d *Detection) AddService(service string) {
 	}
 }
 
-// HasLoggingLibrary checks if a specific logging library was detected.
-func (d *Detection) HasLoggingLibrary(library string) bool {
-	for _, l := range d.LoggingLibraries {
-		if l == library {
-			return true
-		}
+// HasStructuredLogging returns true if any structured logging library was detected.
+func (d *Detection) HasStructuredLogging() bool {
+	return len(d.LoggingLibraries) > 0
+}
+
+// NeedsLogSidecar returns true if the Fluent Bit log sidecar should be generated.
+func (d *Detection) NeedsLogSidecar() bool {
+	return d.Sidecars.Allows(SidecarLogging, d.HasStructuredLogging())
+}
+
+// LifecycleOptions overrides the generated devcontainer.json lifecycle commands.
+// Empty values keep the generated commands.
+type LifecycleOptions struct {
+	// PostCreate replaces the postCreateCommand (safe.directory setup and dependency install)
+	PostCreate string `json:"post_create,omitempty"`
+
+	// PostStart replaces the postStartCommand (waiting for the database and migrating)
+	PostStart string `json:"post_start,omitempty"`
+}
+
+// PersistenceOptions configures what survives a dev container rebuild. Off unless set.
+type PersistenceOptions struct {
+	// History keeps the shell history in a named volume
+	History bool `json:"history,omitempty"`
+
+	// Caches keeps package manager and build caches (npm, pip, Go, Cargo) in named volumes
+	Caches bool `json:"caches,omitempty"`
+
+	// Dotfiles is the git URL of a dotfiles repository cloned on container creation
+	Dotfiles string `json:"dotfiles,omitempty"`
+
+	// DotfilesInstall runs in the cloned repository instead of its install script
+	DotfilesInstall string `json:"dotfiles_install,omitempty"`
+}
+
+// RegistryOptions holds the private package registries of a project.
+type RegistryOptions struct {
+	// NPM is the npm registry URL
+	NPM string `json:"npm,omitempty"`
+
+	// PyPI is the pip index URL
+	PyPI string `json:"pypi,omitempty"`
+
+	// GoPrivate are the module path patterns fetched directly (GOPRIVATE)
+	GoPrivate []string `json:"go_private,omitempty"`
+
+	// GoProxy is the Go module proxy URL (GOPROXY)
+	GoProxy string `json:"go_proxy,omitempty"`
+
+	// Cargo are alternate Cargo registries' index URLs, by name
+	Cargo map[string]string `json:"cargo,omitempty"`
+}
+
+// PostgresRole is an extra login role in the generated PostgreSQL.
+type PostgresRole struct {
+	// Name is the role name (e.g., "reporting")
+	Name string `json:"name"`
+
+	// Password is the role's development password from .dockstart.yml, or
+	// empty to generate one in .devcontainer/.env
+	Password string `json:"password,omitempty"`
+
+	// Superuser makes the role a superuser
+	Superuser bool `json:"superuser,omitempty"`
+
+	// ReadOnly grants only SELECT on the tables, instead of every privilege
+	ReadOnly bool `json:"read_only,omitempty"`
+}
+
+// PasswordVar returns the .devcontainer/.env variable holding the role's
+// password (e.g., "POSTGRES_ROLE_REPORTING_PASSWORD").
+func (r PostgresRole) PasswordVar() string {
+	return "POSTGRES_ROLE_" + strings.ToUpper(r.Name) + "_PASSWORD"
+}
+
+// PrebuildOptions configures prebuilt dev container images.
+type PrebuildOptions struct {
+	// Image is the image CI prebuilds and pushes, which builds cache from
+	// (e.g., "ghcr.io/acme/shop-devcontainer")
+	Image string `json:"image"`
+}
+
+// Pushed returns true if Image names a repository in a registry, which CI can
+// push the prebuilt image to, rather than a local image name.
+func (o *PrebuildOptions) Pushed() bool {
+	return strings.Contains(o.Image, "/")
+}
+
+// ProdImportOptions configures the anonymized production database import.
+type ProdImportOptions struct {
+	// SourceEnv is the host environment variable holding the production database URL
+	SourceEnv string `json:"source_env"`
+
+	// Anonymize are the columns rewritten before the copy replaces the
+	// development database
+	Anonymize []AnonymizedColumn `json:"anonymize,omitempty"`
+
+	// ExcludeData are the tables copied without their rows
+	ExcludeData []string `json:"exclude_data,omitempty"`
+}
+
+// AnonymizedColumn is a column holding personal data in the production database.
+type AnonymizedColumn struct {
+	// Schema is the table's schema, or empty for the search path's
+	Schema string `json:"schema,omitempty"`
+
+	// Table is the table name (e.g., "users")
+	Table string `json:"table"`
+
+	// Column is the column name (e.g., "email")
+	Column string `json:"column"`
+
+	// Strategy is how the values are rewritten: "email", "name", "phone",
+	// "redact", "hash", or "clear"
+	Strategy string `json:"strategy"`
+}
+
+// HasPostgresExtension returns true if the extension is created in the
+// generated PostgreSQL.
+func (d *Detection) HasPostgresExtension(name string) bool {
+	return slices.Contains(d.PostgresExtensions, name)
+}
+
+// UsesPostGIS returns true if the generated PostgreSQL runs the PostGIS image
+// with the postgis extension: it is declared in .dockstart.yml, or geospatial
+// libraries are detected in a project using PostgreSQL whose database doesn't
+// already need the pgvector or TimescaleDB image.
+func (d *Detection) UsesPostGIS() bool {
+	if d.HasPostgresExtension("postgis") {
+		return true
 	}
-	return false
+	return len(d.GeoLibraries) > 0 && d.HasService("postgres") && !d.PostGISConflict()
+}
+
+// PostGISConflict returns true if PostgreSQL needs the pgvector or TimescaleDB
+// image, which don't ship PostGIS.
+func (d *Detection) PostGISConflict() bool {
+	return d.TimescaleDB || d.GetVectorStore() == "pgvector" || d.HasPostgresExtension("vector")
 }
 
-// AddLoggingLibrary adds a logging library to the detection if not already present.
-func (d *Detection) AddLoggingLibrary(library string) {
-	if !d.HasLoggingLibrary(library) {
-		d.LoggingLibraries = append(d.LoggingLibraries, library)
+// SetupExtensions returns the extensions created in every database of the
+// generated PostgreSQL: the declared ones, and postgis when UsesPostGIS.
+func (d *Detection) SetupExtensions() []string {
+	if d.UsesPostGIS() && !d.HasPostgresExtension("postgis") {
+		return append([]string{"postgis"}, d.PostgresExtensions...)
 	}
+	return d.PostgresExtensions
 }
 
-// HasStructuredLogging returns true if any structured logging library was detected.
-func (d *Detection) HasStructuredLogging() bool {
-	return len(d.LoggingLibraries) > 0
+// gdalLibraries are the geospatial libraries that link against the system GDAL.
+var gdalLibraries = []string{"gdal", "godal"}
+
+// NeedsGDAL returns true if a detected library builds against the system GDAL,
+// which the app's Dockerfile then installs.
+func (d *Detection) NeedsGDAL() bool {
+	return slices.ContainsFunc(d.GeoLibraries, func(lib string) bool {
+		return slices.Contains(gdalLibraries, lib)
+	})
 }
 
-// HasQueueLibrary checks if a specific queue library was detected.
-func (d *Detection) HasQueueLibrary(library string) bool {
-	for _, l := range d.QueueLibraries {
-		if l == library {
-			return true
+// ProxyOptions holds the HTTP proxy settings of a project.
+type ProxyOptions struct {
+	// HTTP is the proxy URL for HTTP requests
+	HTTP string `json:"http,omitempty"`
+
+	// HTTPS is the proxy URL for HTTPS requests
+	HTTPS string `json:"https,omitempty"`
+
+	// NoProxy are the extra hosts, domains, and networks reached directly
+	NoProxy []string `json:"no_proxy,omitempty"`
+}
+
+// Enabled returns true if a proxy is configured.
+func (p ProxyOptions) Enabled() bool {
+	return p.HTTP != "" || p.HTTPS != ""
+}
+
+// NeedsPersistentVolumes returns true if shell history or tool caches are kept in named volumes.
+func (d *Detection) NeedsPersistentVolumes() bool {
+	return d.Persistence.History || d.Persistence.Caches
+}
+
+// Test database isolation modes.
+const (
+	// TestIsolationService runs separate postgres-test and redis-test services
+	TestIsolationService = "service"
+
+	// TestIsolationDatabase creates a <project>_test database in the development
+	// PostgreSQL and uses another Redis database number
+	TestIsolationDatabase = "database"
+)
+
+// TestingOptions configures how test suites are kept away from development data.
+type TestingOptions struct {
+	// Isolation is TestIsolationService, TestIsolationDatabase, or empty for none
+	Isolation string `json:"isolation,omitempty"`
+}
+
+// NeedsTestDatabase returns true if test suites get their own PostgreSQL or Redis data.
+func (d *Detection) NeedsTestDatabase() bool {
+	return d.Testing.Isolation != "" && (d.HasService("postgres") || d.HasService("redis"))
+}
+
+// LoggingOptions holds the log retention settings of the generated services.
+type LoggingOptions struct {
+	// MaxSize is the size a log file is rotated at (e.g., "10m")
+	MaxSize string `json:"max_size,omitempty"`
+
+	// MaxSizeBytes is MaxSize in bytes
+	MaxSizeBytes int64 `json:"max_size_bytes,omitempty"`
+
+	// MaxFiles is the number of log files kept per service, the current one included
+	MaxFiles int `json:"max_files,omitempty"`
+
+	// FileOutput writes the logs collected by the log sidecar to per-service
+	// files in the fluent-bit-logs volume, rotated like the others
+	FileOutput bool `json:"file_output,omitempty"`
+}
+
+// GetLogMaxSize returns the size log files are rotated at, for the json-file
+// logging driver (e.g., "10m").
+func (d *Detection) GetLogMaxSize() string {
+	if d.Logging.MaxSize != "" {
+		return d.Logging.MaxSize
+	}
+	return "10m"
+}
+
+// GetLogMaxSizeBytes returns the size log files are rotated at, in bytes.
+func (d *Detection) GetLogMaxSizeBytes() int64 {
+	if d.Logging.MaxSizeBytes > 0 {
+		return d.Logging.MaxSizeBytes
+	}
+	return 10 << 20
+}
+
+// GetLogMaxFiles returns the number of log files kept per service.
+func (d *Detection) GetLogMaxFiles() int {
+	if d.Logging.MaxFiles > 0 {
+		return d.Logging.MaxFiles
+	}
+	return 3
+}
+
+// migrationDatabases are the backing services migrations run against, in preference order.
+var migrationDatabases = []string{"postgres", "mysql"}
+
+// MigrationDatabase returns the database service migrations run against,
+// or an empty string if no SQL database was detected.
+func (d *Detection) MigrationDatabase() string {
+	for _, service := range migrationDatabases {
+		if d.HasService(service) {
+			return service
 		}
 	}
-	return false
+	return ""
 }
 
-// AddQueueLibrary adds a queue library to the detection if not already present.
-func (d *Detection) AddQueueLibrary(library string) {
-	if !d.HasQueueLibrary(library) {
-		d.QueueLibraries = append(d.QueueLibraries, library)
+// NeedsMigrations returns true if migrations should be applied when the dev
+// container starts: a migrate command is known and a database runs next to the app.
+func (d *Detection) NeedsMigrations() bool {
+	return d.MigrateCommand != "" && d.MigrationDatabase() != ""
+}
+
+// ResourceLimits are the memory and CPU limits of a compose service.
+// Empty values keep the generated defaults.
+type ResourceLimits struct {
+	// Memory is the memory limit (e.g., "512m", "1g")
+	Memory string `json:"memory,omitempty"`
+
+	// CPUs is the CPU limit (e.g., "0.5", "2")
+	CPUs string `json:"cp_us,omitempty"`
+}
+
+// WorkerOptions configures how the worker sidecar runs.
+// Zero values keep the defaults (2 jobs at a time, one replica, no limits).
+type WorkerOptions struct {
+	// Concurrency is the number of jobs each worker processes at once (WORKER_CONCURRENCY)
+	Concurrency int `json:"concurrency,omitempty"`
+
+	// Replicas is the number of worker containers to run
+	Replicas int `json:"replicas,omitempty"`
+
+	// Memory is the memory limit per worker (e.g., "512m", "1g")
+	Memory string `json:"memory,omitempty"`
+
+	// CPUs is the CPU limit per worker (e.g., "0.5")
+	CPUs string `json:"cp_us,omitempty"`
+
+	// StopGracePeriod is how long a worker gets to finish jobs on shutdown (e.g., "30s")
+	StopGracePeriod string `json:"stop_grace_period,omitempty"`
+
+	// Target builds the worker from its own stage of the generated Dockerfile
+	Target bool `json:"target,omitempty"`
+
+	// Env holds variables set in the worker stage (e.g., GOFLAGS)
+	Env map[string]string `json:"env,omitempty"`
+
+	// DeadLetter configures retries and the dead-letter queue for failed jobs
+	DeadLetter DeadLetterOptions `json:"dead_letter"`
+}
+
+// DeadLetterOptions configures the retry policy and dead-letter queue scaffolding.
+type DeadLetterOptions struct {
+	// Enabled generates the dead-letter consumer or RabbitMQ dead-letter exchange
+	Enabled bool `json:"enabled,omitempty"`
+
+	// MaxRetries is how many times a job is retried before it is dead-lettered
+	MaxRetries int `json:"max_retries,omitempty"`
+
+	// Backoff is the delay before a failed job is retried (e.g., "5s")
+	Backoff string `json:"backoff,omitempty"`
+
+	// Command starts the dead-letter consumer; empty uses the worker command
+	Command string `json:"command,omitempty"`
+}
+
+// GetWorkerConcurrency returns the number of jobs each worker processes at once.
+func (d *Detection) GetWorkerConcurrency() int {
+	if d.Worker.Concurrency > 0 {
+		return d.Worker.Concurrency
 	}
+	return 2
 }
 
 // NeedsWorker returns true if any queue library was detected that requires a worker.
@@ -135,48 +665,162 @@ func (d *Detection) NeedsWorker() bool {
 	return len(d.QueueLibraries) > 0
 }
 
-// HasFileUploadLibrary checks if a specific file upload library was detected.
-func (d *Detection) HasFileUploadLibrary(library string) bool {
-	for _, l := range d.FileUploadLibraries {
-		if l == library {
-			return true
-		}
+// NeedsTemporal returns true if a Temporal SDK was detected, whose workers need
+// a Temporal server.
+func (d *Detection) NeedsTemporal() bool {
+	return d.HasQueueLibrary("temporal")
+}
+
+// NeedsDeadLetter returns true if dead-letter queue scaffolding was enabled for a worker.
+func (d *Detection) NeedsDeadLetter() bool {
+	return d.NeedsWorker() && d.Worker.DeadLetter.Enabled
+}
+
+// GetMaxRetries returns how many times a failed job is retried before it is dead-lettered.
+func (d *Detection) GetMaxRetries() int {
+	if d.Worker.DeadLetter.MaxRetries > 0 {
+		return d.Worker.DeadLetter.MaxRetries
 	}
-	return false
+	return 3
 }
 
-// AddFileUploadLibrary adds a file upload library to the detection if not already present.
-func (d *Detection) AddFileUploadLibrary(library string) {
-	if !d.HasFileUploadLibrary(library) {
-		d.FileUploadLibraries = append(d.FileUploadLibraries, library)
+// GetRetryBackoff returns the delay before a failed job is retried.
+func (d *Detection) GetRetryBackoff() string {
+	if d.Worker.DeadLetter.Backoff != "" {
+		return d.Worker.DeadLetter.Backoff
 	}
+	return "5s"
 }
 
-// NeedsFileProcessor returns true if any file upload library was detected.
-func (d *Detection) NeedsFileProcessor() bool {
-	return len(d.FileUploadLibraries) > 0
+// GetQueueDashboard returns the queue management UI to run next to the worker:
+// "bull-board" for Bull/BullMQ, "flower" for Celery, or "asynqmon" for Asynq.
+// Returns an empty string when no queue library with a dashboard was detected,
+// or the sidecar policy leaves the dashboard out.
+func (d *Detection) GetQueueDashboard() string {
+	switch {
+	case !d.Sidecars.Allows(SidecarDashboard, true):
+		return ""
+	case d.HasQueueLibrary("bull") || d.HasQueueLibrary("bullmq"):
+		return "bull-board"
+	case d.HasQueueLibrary("celery"):
+		return "flower"
+	case d.HasQueueLibrary("asynq"):
+		return "asynqmon"
+	}
+	return ""
 }
 
-// HasMetricsLibrary checks if a specific metrics library was detected.
-func (d *Detection) HasMetricsLibrary(library string) bool {
-	for _, l := range d.MetricsLibraries {
-		if l == library {
-			return true
-		}
+// GrafanaPort is the host port of the metrics stack's Grafana.
+const GrafanaPort = 3001
+
+// queueDashboardPorts are the default host ports of each queue dashboard,
+// chosen to avoid the default app ports (3000, 8080) and the other sidecars.
+var queueDashboardPorts = map[string]int{
+	"bull-board": 3002,
+	"flower":     5555,
+	"asynqmon":   8081,
+}
+
+// ReservedHostPorts returns the host ports the frontend dev server and the
+// queue dashboard are kept off: the backend app's and Grafana's.
+func ReservedHostPorts(appPort int) []int {
+	return []int{appPort, GrafanaPort}
+}
+
+// FreePort returns port, or the first port after it that isn't in used.
+func FreePort(port int, used []int) int {
+	for slices.Contains(used, port) {
+		port++
 	}
-	return false
+	return port
 }
 
-// AddMetricsLibrary adds a metrics library to the detection if not already present.
-func (d *Detection) AddMetricsLibrary(library string) {
-	if !d.HasMetricsLibrary(library) {
-		d.MetricsLibraries = append(d.MetricsLibraries, library)
+// GetQueueDashboardPort returns the host port of the queue dashboard, moved
+// off the app, Grafana, and frontend dev server ports, or 0 for none.
+func (d *Detection) GetQueueDashboardPort() int {
+	port, ok := queueDashboardPorts[d.GetQueueDashboard()]
+	if !ok {
+		return 0
+	}
+	used := ReservedHostPorts(d.GetAppPort())
+	if d.FrontendPort > 0 {
+		used = append(used, d.FrontendPort)
 	}
+	return FreePort(port, used)
 }
 
-// NeedsMetrics returns true if any Prometheus metrics library was detected.
+// FileProcessorOptions configures the file processor sidecar's pipeline.
+// Zero values keep the defaults (images only, 200x200 thumbnails, 50MB, polling).
+type FileProcessorOptions struct {
+	// Types are the kinds of files processed ("images", "documents", "video")
+	Types []string `json:"types,omitempty"`
+
+	// ThumbnailSizes are the image thumbnail sizes; the first is the main thumbnail
+	ThumbnailSizes []string `json:"thumbnail_sizes,omitempty"`
+
+	// AllowedMIMETypes restricts accepted uploads (e.g., "image/*"); empty accepts any type
+	AllowedMIMETypes []string `json:"allowed_mime_types,omitempty"`
+
+	// MaxFileSize is the largest file processed, in bytes
+	MaxFileSize int64 `json:"max_file_size,omitempty"`
+
+	// Watch is how new files are found ("poll" or "inotify")
+	Watch string `json:"watch,omitempty"`
+
+	// PollInterval is the polling interval in seconds
+	PollInterval int `json:"poll_interval,omitempty"`
+
+	// Scan enables the ClamAV virus-scanning stage before processing
+	Scan bool `json:"scan,omitempty"`
+
+	// SanitizeImages strips EXIF/GPS metadata and converts unsafe image formats
+	SanitizeImages bool `json:"sanitize_images,omitempty"`
+
+	// Notify is how the app learns a file was processed ("file", "webhook", or "queue")
+	Notify string `json:"notify,omitempty"`
+
+	// NotifyURL is the webhook URL used when Notify is "webhook"
+	NotifyURL string `json:"notify_url,omitempty"`
+
+	// NotifyQueue is the Redis list used when Notify is "queue"
+	NotifyQueue string `json:"notify_queue,omitempty"`
+
+	// Storage is where uploads are exchanged: "volume" or "s3"; empty picks
+	// "s3" when an S3 SDK was detected
+	Storage string `json:"storage,omitempty"`
+}
+
+// NeedsFileProcessor returns true if any file upload library was detected,
+// subject to the sidecar policy.
+func (d *Detection) NeedsFileProcessor() bool {
+	return d.Sidecars.Allows(SidecarProcessor, len(d.FileUploadLibraries) > 0)
+}
+
+// UsesS3Uploads returns true if the file processor exchanges uploads through
+// an S3 bucket instead of a shared volume.
+func (d *Detection) UsesS3Uploads() bool {
+	if !d.NeedsFileProcessor() {
+		return false
+	}
+	switch d.FileProcessor.Storage {
+	case "s3":
+		return true
+	case "volume":
+		return false
+	}
+	return d.HasAWSService("s3")
+}
+
+// NeedsMinIO returns true if S3 uploads need a MinIO sidecar, because
+// LocalStack is not already emulating S3.
+func (d *Detection) NeedsMinIO() bool {
+	return d.UsesS3Uploads() && !(d.NeedsLocalStack() && d.HasAWSService("s3"))
+}
+
+// NeedsMetrics returns true if any Prometheus metrics library was detected,
+// subject to the sidecar policy.
 func (d *Detection) NeedsMetrics() bool {
-	return len(d.MetricsLibraries) > 0
+	return d.Sidecars.Allows(SidecarMetrics, len(d.MetricsLibraries) > 0)
 }
 
 // GetMetricsPath returns the metrics endpoint path, defaulting to "/metrics".
@@ -192,7 +836,25 @@ func (d *Detection) GetMetricsPort() int {
 	if d.MetricsPort != 0 {
 		return d.MetricsPort
 	}
-	// Default ports by language
+	return d.GetAppPort()
+}
+
+// NeedsTracing returns true if any distributed tracing library was detected,
+// subject to the sidecar policy.
+func (d *Detection) NeedsTracing() bool {
+	return d.Sidecars.Allows(SidecarTracing, len(d.TracingLibraries) > 0)
+}
+
+// GetTracingProtocol returns the tracing protocol, defaulting to "otlp".
+func (d *Detection) GetTracingProtocol() string {
+	if d.TracingProtocol != "" && d.TracingProtocol != "unknown" {
+		return d.TracingProtocol
+	}
+	return "otlp"
+}
+
+// GetAppPort returns the standard app port for the language.
+func (d *Detection) GetAppPort() int {
 	switch d.Language {
 	case "node":
 		return 3000
@@ -207,34 +869,230 @@ func (d *Detection) GetMetricsPort() int {
 	}
 }
 
-// HasTracingLibrary checks if a specific tracing library was detected.
-func (d *Detection) HasTracingLibrary(library string) bool {
-	for _, l := range d.TracingLibraries {
-		if l == library {
+// NeedsWebService returns true if the frontend dev server should run as its own
+// service next to the backend app, rather than being the app itself.
+func (d *Detection) NeedsWebService() bool {
+	return d.FrontendFramework != "" && d.FrontendDir != ""
+}
+
+// NeedsWebsockets returns true if any WebSocket library was detected.
+func (d *Detection) NeedsWebsockets() bool {
+	return len(d.WebsocketLibraries) > 0
+}
+
+// NeedsGRPC returns true if any gRPC library was detected.
+func (d *Detection) NeedsGRPC() bool {
+	return len(d.GRPCLibraries) > 0
+}
+
+// GetGRPCPort returns the gRPC server port, defaulting to 50051.
+func (d *Detection) GetGRPCPort() int {
+	if d.GRPCPort > 0 {
+		return d.GRPCPort
+	}
+	return 50051
+}
+
+// NeedsAuthProvider returns true if any OIDC/OAuth library was detected.
+func (d *Detection) NeedsAuthProvider() bool {
+	return len(d.AuthLibraries) > 0
+}
+
+// NeedsSeleniumGrid returns true if a Selenium/WebDriver client was detected.
+func (d *Detection) NeedsSeleniumGrid() bool {
+	return len(d.WebDriverLibraries) > 0
+}
+
+// NeedsStripe returns true if a Stripe SDK was detected.
+func (d *Detection) NeedsStripe() bool {
+	return len(d.PaymentLibraries) > 0
+}
+
+// NeedsLocalStack returns true if AWS services beyond S3 are used.
+// S3-only projects are better served by a lighter S3 emulator.
+func (d *Detection) NeedsLocalStack() bool {
+	for _, s := range d.AWSServices {
+		if s != "s3" {
 			return true
 		}
 	}
 	return false
 }
 
-// AddTracingLibrary adds a tracing library to the detection if not already present.
-func (d *Detection) AddTracingLibrary(library string) {
-	if !d.HasTracingLibrary(library) {
-		d.TracingLibraries = append(d.TracingLibraries, library)
+// NeedsVectorStore returns true if any vector database client was detected.
+func (d *Detection) NeedsVectorStore() bool {
+	return len(d.VectorLibraries) > 0
+}
+
+// GetVectorStore returns the local vector store to run: "pgvector" when pgvector
+// clients are used, "chroma" for Chroma clients, otherwise "qdrant".
+// Returns an empty string when no vector database client was detected.
+func (d *Detection) GetVectorStore() string {
+	if !d.NeedsVectorStore() {
+		return ""
+	}
+	for _, l := range d.VectorLibraries {
+		if strings.Contains(l, "pgvector") || l == "langchain-postgres" {
+			return "pgvector"
+		}
 	}
+	for _, l := range d.VectorLibraries {
+		if strings.Contains(l, "chroma") {
+			return "chroma"
+		}
+	}
+	return "qdrant"
 }
 
-// NeedsTracing returns true if any distributed tracing library was detected.
-func (d *Detection) NeedsTracing() bool {
-	return len(d.TracingLibraries) > 0
+// NeedsSearch returns true if a Meilisearch or Typesense client was detected.
+func (d *Detection) NeedsSearch() bool {
+	return len(d.SearchLibraries) > 0
 }
 
-// GetTracingProtocol returns the tracing protocol, defaulting to "otlp".
-func (d *Detection) GetTracingProtocol() string {
-	if d.TracingProtocol != "" && d.TracingProtocol != "unknown" {
-		return d.TracingProtocol
+// NeedsMailpit returns true if an SMTP mail library was detected, so mail is
+// caught by the Mailpit sidecar.
+func (d *Detection) NeedsMailpit() bool {
+	return len(d.MailLibraries) > 0
+}
+
+// NeedsMailExample returns true if both a queue and a mail library were
+// detected: the worker sends mail through Mailpit, with an example job.
+func (d *Detection) NeedsMailExample() bool {
+	return d.NeedsMailpit() && d.NeedsWorker()
+}
+
+// GetSearchEngine returns the search engine to run: "meilisearch" for
+// Meilisearch clients, otherwise "typesense". Returns an empty string when no
+// search client was detected.
+func (d *Detection) GetSearchEngine() string {
+	if !d.NeedsSearch() {
+		return ""
 	}
-	return "otlp"
+	for _, l := range d.SearchLibraries {
+		if strings.HasPrefix(l, "meilisearch") {
+			return "meilisearch"
+		}
+	}
+	return "typesense"
+}
+
+// NeedsLLM returns true if any LLM client library was detected.
+func (d *Detection) NeedsLLM() bool {
+	return len(d.LLMLibraries) > 0
+}
+
+// CallsExternalAPIs returns true if an HTTP client was detected and the
+// project's configuration points at third-party APIs.
+func (d *Detection) CallsExternalAPIs() bool {
+	return len(d.HTTPClientLibraries) > 0 && len(d.ExternalAPIs) > 0
+}
+
+// NeedsWireMock returns true if the WireMock API-mocking sidecar should be generated.
+func (d *Detection) NeedsWireMock() bool {
+	return d.MockAPIs
+}
+
+// TracksErrors returns true if a Sentry SDK was detected.
+func (d *Detection) TracksErrors() bool {
+	return len(d.ErrorTrackingLibraries) > 0
+}
+
+// NeedsGlitchTip returns true if the GlitchTip error-tracking sidecar should be generated.
+func (d *Detection) NeedsGlitchTip() bool {
+	return d.LocalErrorTracking
+}
+
+// NeedsStatusPage returns true if the Gatus status page should be generated.
+func (d *Detection) NeedsStatusPage() bool {
+	return d.StatusPage
+}
+
+// GetHealthPath returns the app path the status page checks, "/" by default.
+func (d *Detection) GetHealthPath() string {
+	if d.HealthPath != "" {
+		return d.HealthPath
+	}
+	return "/"
+}
+
+// NeedsToxiproxy returns true if the Toxiproxy chaos proxy was requested.
+func (d *Detection) NeedsToxiproxy() bool {
+	return d.ChaosProxy
+}
+
+// NeedsOllama returns true if the local Ollama sidecar should be generated.
+func (d *Detection) NeedsOllama() bool {
+	return d.LocalLLM
+}
+
+// NeedsScheduler returns true if any cron/scheduled-job library was detected.
+func (d *Detection) NeedsScheduler() bool {
+	return len(d.SchedulerLibraries) > 0
+}
+
+// NeedsCompose returns true if a docker-compose.yml is generated: backing
+// services, sidecars, or services imported from the project's compose file.
+func (d *Detection) NeedsCompose() bool {
+	return len(d.Services) > 0 || d.NeedsMetrics() || d.NeedsWorker() ||
+		d.NeedsScheduler() || d.NeedsGRPC() || d.NeedsAuthProvider() ||
+		d.NeedsStripe() || d.NeedsLocalStack() || d.NeedsVectorStore() ||
+		d.NeedsSearch() || d.NeedsMailpit() || d.NeedsOllama() ||
+		d.NeedsFileProcessor() || d.NeedsWebService() || d.NeedsSeleniumGrid() ||
+		d.NeedsWireMock() || d.NeedsGlitchTip() || d.NeedsStatusPage() ||
+		d.ExistingCompose != nil
+}
+
+// HasBuildStep returns true if the project must be compiled before it can run.
+func (d *Detection) HasBuildStep() bool {
+	return d.BuildCommand != ""
+}
+
+// GetBuildOutputDir returns the compiled output directory, defaulting to "dist".
+func (d *Detection) GetBuildOutputDir() string {
+	if d.BuildOutputDir != "" {
+		return d.BuildOutputDir
+	}
+	return "dist"
+}
+
+// SidecarUser is the non-root user the generated sidecar images run as.
+const SidecarUser = "dockstart"
+
+// GetContainerUser returns the non-root user the app image runs as, or an
+// empty string when containers run as root. Node images reuse their "node"
+// user; other images get the "vscode" user devcontainer.json expects.
+func (d *Detection) GetContainerUser() string {
+	if !d.NonRoot {
+		return ""
+	}
+	if d.Language == "node" {
+		return "node"
+	}
+	return "vscode"
+}
+
+// dependencyDirs are the in-tree dependency and build output directories of each
+// language, relative to the workspace. Go keeps its modules outside the workspace.
+var dependencyDirs = map[string][]string{
+	"node":   {"node_modules"},
+	"python": {".venv"},
+	"rust":   {"target"},
+}
+
+// DependencyVolumeDirs returns the workspace directories kept in named volumes
+// instead of the bind-mounted workspace, which is slow on Windows hosts.
+// Empty unless Windows is set.
+func (d *Detection) DependencyVolumeDirs() []string {
+	if !d.Windows {
+		return nil
+	}
+	return d.DependencyDirs()
+}
+
+// DependencyDirs returns the language's in-tree dependency and build output
+// directories (e.g., "node_modules"), which are installed inside the container.
+func (d *Detection) DependencyDirs() []string {
+	return dependencyDirs[d.Language]
 }
 
 // BackupConfig represents the configuration for database backup sidecar.
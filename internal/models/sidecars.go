@@ -0,0 +1,122 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Optional sidecars, as named by --with and --without.
+const (
+	// SidecarLogging is the Fluent Bit log aggregator
+	SidecarLogging = "logging"
+
+	// SidecarMetrics is Prometheus and Grafana, with the database exporters
+	SidecarMetrics = "metrics"
+
+	// SidecarTracing is the Jaeger trace collector and UI
+	SidecarTracing = "tracing"
+
+	// SidecarBackup is the scheduled database backup container
+	SidecarBackup = "backup"
+
+	// SidecarProcessor is the file processor for uploads
+	SidecarProcessor = "processor"
+
+	// SidecarDashboard is the queue dashboard (Bull Board, Flower, or Asynqmon)
+	SidecarDashboard = "dashboard"
+
+	// SidecarAdmin is Adminer and RedisInsight in the tools profile
+	SidecarAdmin = "admin"
+
+	// SidecarGRPCUI is the grpcui web client
+	SidecarGRPCUI = "grpcui"
+)
+
+// Sidecars lists the optional sidecars, in the order they are reported.
+var Sidecars = []string{
+	SidecarLogging,
+	SidecarMetrics,
+	SidecarTracing,
+	SidecarBackup,
+	SidecarProcessor,
+	SidecarDashboard,
+	SidecarAdmin,
+	SidecarGRPCUI,
+}
+
+// SidecarPolicy decides which optional sidecars are generated, overriding the
+// ones detection adds. Without wins over With, and With over Minimal and Codespaces.
+type SidecarPolicy struct {
+	// Minimal leaves out every optional sidecar not listed in With.
+	// Set with --minimal
+	Minimal bool `json:"minimal,omitempty"`
+
+	// Codespaces leaves out the CodespacesOmitted sidecars not listed in With.
+	// Set with --target codespaces
+	Codespaces bool `json:"codespaces,omitempty"`
+
+	// With adds sidecars detection didn't, or keeps them in minimal mode.
+	// Set with --with
+	With []string `json:"with,omitempty"`
+
+	// Without leaves out sidecars detection added. Set with --without
+	Without []string `json:"without,omitempty"`
+}
+
+// Allows returns true if the sidecar is generated, given whether detection adds it.
+func (p SidecarPolicy) Allows(sidecar string, detected bool) bool {
+	switch {
+	case slices.Contains(p.Without, sidecar):
+		return false
+	case slices.Contains(p.With, sidecar):
+		return true
+	case p.Minimal:
+		return false
+	case p.Codespaces && slices.Contains(CodespacesOmitted, sidecar):
+		return false
+	}
+	return detected
+}
+
+// Validate checks that With and Without only name known sidecars, and don't
+// both name the same one.
+func (p SidecarPolicy) Validate() error {
+	for _, sidecar := range append(slices.Clone(p.With), p.Without...) {
+		if !slices.Contains(Sidecars, sidecar) {
+			return fmt.Errorf("unknown sidecar %q: expected one of %s", sidecar, strings.Join(Sidecars, ", "))
+		}
+	}
+	for _, sidecar := range p.With {
+		if slices.Contains(p.Without, sidecar) {
+			return fmt.Errorf("sidecar %q is both in --with and --without", sidecar)
+		}
+	}
+	return nil
+}
+
+// NeedsSidecar returns true if the optional sidecar is generated. Logging,
+// metrics, tracing, and the file processor can be added to any stack; the other
+// sidecars only run next to the services they work with.
+func (d *Detection) NeedsSidecar(sidecar string) bool {
+	switch sidecar {
+	case SidecarLogging:
+		return d.NeedsLogSidecar()
+	case SidecarMetrics:
+		return d.NeedsMetrics()
+	case SidecarTracing:
+		return d.NeedsTracing()
+	case SidecarProcessor:
+		return d.NeedsFileProcessor()
+	case SidecarBackup:
+		return d.Sidecars.Allows(SidecarBackup, true) &&
+			(d.HasService("postgres") || d.HasService("mysql") || d.HasService("redis"))
+	case SidecarDashboard:
+		return d.NeedsWorker() && d.GetQueueDashboard() != ""
+	case SidecarAdmin:
+		return d.Sidecars.Allows(SidecarAdmin, true) && (d.HasService("postgres") || d.HasService("redis"))
+	case SidecarGRPCUI:
+		return d.Sidecars.Allows(SidecarGRPCUI, true) && d.NeedsGRPC()
+	}
+	return false
+}
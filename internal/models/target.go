@@ -0,0 +1,38 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Environments the generated files can target, as named by --target.
+const (
+	// TargetLocal is Docker on the developer's machine (the default)
+	TargetLocal = "local"
+
+	// TargetCodespaces is GitHub Codespaces, whose machines start at 2 cores
+	// and 8 GB of memory and which prebuilds containers ahead of time
+	TargetCodespaces = "codespaces"
+)
+
+// Targets lists the valid targets.
+var Targets = []string{TargetLocal, TargetCodespaces}
+
+// CodespacesOmitted are the optional sidecars left out in Codespaces unless
+// named in --with: logs and backups are of little use in a disposable
+// codespace, and the database admin UIs are replaced by client features.
+var CodespacesOmitted = []string{SidecarLogging, SidecarBackup, SidecarAdmin}
+
+// ValidateTarget checks that target is empty (local) or a known target.
+func ValidateTarget(target string) error {
+	if target != "" && !slices.Contains(Targets, target) {
+		return fmt.Errorf("unknown target %q: expected one of %s", target, strings.Join(Targets, ", "))
+	}
+	return nil
+}
+
+// TargetsCodespaces returns true if the files are generated for GitHub Codespaces.
+func (d *Detection) TargetsCodespaces() bool {
+	return d.Target == TargetCodespaces
+}
@@ -0,0 +1,227 @@
+// Package policy loads organization policy packs: rules a platform team sets
+// for every environment dockstart generates (approved base images, non-root
+// containers, forbidden sidecars, resource limits), checked at plan time.
+package policy
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jpequegn/dockstart/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrSignature is returned when a policy's signature is missing or doesn't
+// match the policy key.
+var ErrSignature = errors.New("policy signature verification failed")
+
+// Policy is an organization policy pack.
+type Policy struct {
+	// Name identifies the policy in messages (e.g., "acme-platform")
+	Name string `yaml:"name"`
+
+	// Enforce fails generation on violations; otherwise they are warnings
+	Enforce bool `yaml:"enforce"`
+
+	// ApprovedImages are the images services and the app may use, as patterns
+	// where * matches any characters (e.g., "postgres:*", "registry.acme.dev/*").
+	// Empty approves every image
+	ApprovedImages []string `yaml:"approved_images"`
+
+	// RequireNonRoot makes every generated environment run as a non-root user
+	RequireNonRoot bool `yaml:"require_non_root"`
+
+	// ForbiddenSidecars are optional sidecars that are never generated
+	ForbiddenSidecars []string `yaml:"forbidden_sidecars"`
+
+	// RequireResourceLimits requires a memory limit on every service
+	// "docker compose up" starts
+	RequireResourceLimits bool `yaml:"require_resource_limits"`
+
+	// Source is where the policy was loaded from
+	Source string `yaml:"-"`
+
+	// Verified is set when the policy's signature was checked against a key
+	Verified bool `yaml:"-"`
+}
+
+// Violation is a policy rule a generation plan breaks.
+type Violation struct {
+	// Rule is the policy field that is broken (e.g., "approved_images")
+	Rule string `json:"rule"`
+
+	// Message describes the violation
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Rule, v.Message)
+}
+
+// Facts are what a generation plan produces, as the policy checks see them.
+type Facts struct {
+	// Images are the app's base image and the images the services pull
+	Images []string
+
+	// Unlimited are the services started by default without a memory limit
+	Unlimited []string
+}
+
+// client fetches policies served over HTTP.
+var client = &http.Client{Timeout: 30 * time.Second}
+
+// Load reads a policy from a path or an http(s) URL. With a base64 Ed25519
+// public key, the policy must be signed: source + ".sig" holds the base64
+// signature of the policy file. Without a key, the policy is loaded unverified.
+func Load(source, publicKey string) (*Policy, error) {
+	data, err := fetch(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy %s: %w", source, err)
+	}
+
+	verified := false
+	if publicKey != "" {
+		signature, err := fetch(source + ".sig")
+		if err != nil {
+			return nil, fmt.Errorf("%w: no signature for %s: %v", ErrSignature, source, err)
+		}
+		if err := verify(data, signature, publicKey); err != nil {
+			return nil, err
+		}
+		verified = true
+	}
+
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", source, err)
+	}
+	p.Source = source
+	p.Verified = verified
+	return p, nil
+}
+
+// Parse parses and validates a policy file. Unknown fields are errors, so a
+// misspelled rule doesn't silently go unenforced.
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	for _, sidecar := range p.ForbiddenSidecars {
+		if !slices.Contains(models.Sidecars, sidecar) {
+			return nil, fmt.Errorf("unknown sidecar %q in forbidden_sidecars: expected one of %s", sidecar, strings.Join(models.Sidecars, ", "))
+		}
+	}
+	if p.Name == "" {
+		p.Name = "organization policy"
+	}
+	return &p, nil
+}
+
+// fetch reads a local file, or downloads an http(s) URL.
+func fetch(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "https://") && !strings.HasPrefix(source, "http://") {
+		return os.ReadFile(source)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "dockstart")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("GET %s: %s", source, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// verify checks a base64 Ed25519 signature of a policy against a base64 public key.
+func verify(data, signature []byte, publicKey string) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid policy key: expected a base64 Ed25519 public key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil || !ed25519.Verify(ed25519.PublicKey(key), data, sig) {
+		return fmt.Errorf("%w: the policy was changed or not signed with the policy key", ErrSignature)
+	}
+	return nil
+}
+
+// Apply sets the defaults the policy enforces on a detection: non-root
+// containers, and leaving out forbidden sidecars. A forbidden sidecar added
+// with --with is kept, for Check to report.
+func (p *Policy) Apply(detection *models.Detection) {
+	if p.RequireNonRoot {
+		detection.NonRoot = true
+	}
+	for _, sidecar := range p.ForbiddenSidecars {
+		if !slices.Contains(detection.Sidecars.With, sidecar) && !slices.Contains(detection.Sidecars.Without, sidecar) {
+			detection.Sidecars.Without = append(detection.Sidecars.Without, sidecar)
+		}
+	}
+}
+
+// Check returns the violations of a generation plan: unapproved images,
+// containers running as root, forbidden sidecars, and services without limits.
+func (p *Policy) Check(detection *models.Detection, facts Facts) []Violation {
+	var violations []Violation
+	if len(p.ApprovedImages) > 0 {
+		for _, image := range facts.Images {
+			if !p.approves(image) {
+				violations = append(violations, Violation{"approved_images", fmt.Sprintf("%s is not an approved image", image)})
+			}
+		}
+	}
+	if p.RequireNonRoot && !detection.NonRoot {
+		violations = append(violations, Violation{"require_non_root", "containers run as root"})
+	}
+	for _, sidecar := range p.ForbiddenSidecars {
+		if !detection.NeedsSidecar(sidecar) {
+			continue
+		}
+		message := fmt.Sprintf("the %s sidecar is generated", sidecar)
+		if slices.Contains(detection.Sidecars.With, sidecar) {
+			message += " (added with --with)"
+		}
+		violations = append(violations, Violation{"forbidden_sidecars", message})
+	}
+	if p.RequireResourceLimits {
+		for _, service := range facts.Unlimited {
+			violations = append(violations, Violation{"require_resource_limits", fmt.Sprintf("%s has no memory limit", service)})
+		}
+	}
+	return violations
+}
+
+// approves reports whether an image matches one of the approved patterns.
+func (p *Policy) approves(image string) bool {
+	for _, pattern := range p.ApprovedImages {
+		if globRe(pattern).MatchString(image) {
+			return true
+		}
+	}
+	return false
+}
+
+// globRe compiles an image pattern, where * matches any characters, "/" included.
+func globRe(pattern string) *regexp.Regexp {
+	return regexp.MustCompile("^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$")
+}
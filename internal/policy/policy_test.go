@@ -0,0 +1,178 @@
+package policy
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+const testPolicy = `name: acme-platform
+enforce: true
+approved_images:
+  - "node:*"
+  - "postgres:16*"
+  - "registry.acme.dev/*"
+require_non_root: true
+forbidden_sidecars: [admin, metrics]
+require_resource_limits: true
+`
+
+// signedPolicy writes testPolicy and its signature to dir, and returns the
+// policy path and the base64 public key.
+func signedPolicy(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "policy.yml")
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte(testPolicy)))
+	if err := os.WriteFile(path, []byte(testPolicy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+".sig", []byte(signature+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path, base64.StdEncoding.EncodeToString(public)
+}
+
+// TestLoad tests loading a policy with and without a signature check.
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path, key := signedPolicy(t, dir)
+
+	p, err := Load(path, key)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !p.Verified || p.Source != path || p.Name != "acme-platform" || !p.Enforce {
+		t.Errorf("Load() = %+v", p)
+	}
+	if want := []string{"admin", "metrics"}; !reflect.DeepEqual(p.ForbiddenSidecars, want) {
+		t.Errorf("ForbiddenSidecars = %v, want %v", p.ForbiddenSidecars, want)
+	}
+
+	// Without a key the policy loads unverified
+	p, err = Load(path, "")
+	if err != nil {
+		t.Fatalf("Load() without key error = %v", err)
+	}
+	if p.Verified {
+		t.Error("Load() without key should not be verified")
+	}
+
+	// A changed policy no longer matches its signature
+	if err := os.WriteFile(path, []byte(strings.Replace(testPolicy, "enforce: true", "enforce: false", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path, key); !errors.Is(err, ErrSignature) {
+		t.Errorf("Load() of a changed policy error = %v, want ErrSignature", err)
+	}
+
+	// So does a policy without a signature
+	unsigned := filepath.Join(dir, "unsigned.yml")
+	if err := os.WriteFile(unsigned, []byte(testPolicy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(unsigned, key); !errors.Is(err, ErrSignature) {
+		t.Errorf("Load() of an unsigned policy error = %v, want ErrSignature", err)
+	}
+}
+
+// TestLoad_URL tests loading a signed policy over HTTP.
+func TestLoad_URL(t *testing.T) {
+	dir := t.TempDir()
+	path, key := signedPolicy(t, dir)
+	server := httptest.NewServer(http.FileServer(http.Dir(filepath.Dir(path))))
+	defer server.Close()
+
+	p, err := Load(server.URL+"/policy.yml", key)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !p.Verified || p.Name != "acme-platform" {
+		t.Errorf("Load() = %+v", p)
+	}
+
+	if _, err := Load(server.URL+"/missing.yml", ""); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Load() of a missing URL error = %v, want a 404", err)
+	}
+}
+
+// TestParse_Invalid tests that misspelled rules and unknown sidecars are rejected.
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr string
+	}{
+		{"unknown field", "require_nonroot: true\n", "field require_nonroot not found"},
+		{"unknown sidecar", "forbidden_sidecars: [kibana]\n", `unknown sidecar "kibana"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.policy))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	p, err := Parse(nil)
+	if err != nil || p.Name != "organization policy" {
+		t.Errorf("Parse() of an empty policy = %+v, %v", p, err)
+	}
+}
+
+// TestPolicy_ApplyAndCheck tests the defaults a policy sets and the
+// violations it reports.
+func TestPolicy_ApplyAndCheck(t *testing.T) {
+	p, err := Parse([]byte(testPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	detection := &models.Detection{
+		Language:     "node",
+		Version:      "20",
+		Services:     []string{"postgres", "redis"},
+		Capabilities: models.Capabilities{MetricsLibraries: []string{"prom-client"}},
+		Sidecars:     models.SidecarPolicy{With: []string{"admin"}},
+	}
+	p.Apply(detection)
+
+	if !detection.NonRoot {
+		t.Error("Apply() should turn on non-root containers")
+	}
+	if want := []string{"metrics"}; !reflect.DeepEqual(detection.Sidecars.Without, want) {
+		t.Errorf("Sidecars.Without = %v, want %v", detection.Sidecars.Without, want)
+	}
+
+	violations := p.Check(detection, Facts{
+		Images:    []string{"node:20", "postgres:16-alpine", "redis:7-alpine", "registry.acme.dev/tools/jaeger:1.57"},
+		Unlimited: []string{"app"},
+	})
+	want := []Violation{
+		{"approved_images", "redis:7-alpine is not an approved image"},
+		{"forbidden_sidecars", "the admin sidecar is generated (added with --with)"},
+		{"require_resource_limits", "app has no memory limit"},
+	}
+	if !reflect.DeepEqual(violations, want) {
+		t.Errorf("Check() = %v, want %v", violations, want)
+	}
+
+	// An empty policy allows everything
+	empty, _ := Parse(nil)
+	if violations := empty.Check(&models.Detection{Language: "go"}, Facts{Images: []string{"golang:1.22"}, Unlimited: []string{"app"}}); len(violations) != 0 {
+		t.Errorf("Check() with an empty policy = %v, want none", violations)
+	}
+}
@@ -0,0 +1,390 @@
+// Package project turns a detected project, its .dockstart.yml, and the
+// command-line flags into the generation plan dockstart runs. The dockstart
+// command and the golden file harness both build their plans here, so the
+// golden files are the files users get.
+package project
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/config"
+	"github.com/jpequegn/dockstart/internal/detector"
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// Flags are the command-line options, which override .dockstart.yml. The
+// zero value applies .dockstart.yml alone.
+type Flags struct {
+	// Language forces the primary language (--language)
+	Language string
+
+	// Lockfiles enables reading lockfiles (--lockfiles)
+	Lockfiles bool
+
+	// NonRoot and Hardened are --non-root and --hardened
+	NonRoot  bool
+	Hardened bool
+
+	// Target is the editor or environment targeted (--target)
+	Target string
+
+	// ComposeVersion is the Docker Compose version to generate for (--compose-version)
+	ComposeVersion string
+
+	// Environments are the docker-compose.<env>.yml overrides (--env)
+	Environments []string
+
+	// Minimal, WithSidecars, and WithoutSidecars are --minimal, --with, and --without
+	Minimal         bool
+	WithSidecars    []string
+	WithoutSidecars []string
+
+	// Windows, RemoteDocker, and Prebuild are --windows, --remote-docker, and --prebuild
+	Windows      bool
+	RemoteDocker bool
+	Prebuild     bool
+
+	// Forward are the host sockets and files forwarded (--forward)
+	Forward []string
+
+	// Persist persists shell history and caches (--persist)
+	Persist bool
+
+	// ForceDockerfile generates a Dockerfile even if the project has one (--force-dockerfile)
+	ForceDockerfile bool
+
+	// Ollama, WireMock, Toxiproxy, GlitchTip, and Gatus add their sidecars
+	// (--ollama, --wiremock, --toxiproxy, --glitchtip, --gatus)
+	Ollama    bool
+	WireMock  bool
+	Toxiproxy bool
+	GlitchTip bool
+	Gatus     bool
+
+	// Worker and FileProcessor are the --worker-* and --processor-* flags
+	Worker        config.Worker
+	FileProcessor config.FileProcessor
+
+	// HostWindows is true when dockstart runs on Windows, whose checkouts need
+	// LF line endings unless the project targets Codespaces
+	HostWindows bool
+
+	// RemoteHost returns the remote Docker engine the docker CLI talks to, or
+	// "" for a local one. It is only called when nothing else decides whether
+	// the engine is remote; nil means a local engine.
+	RemoteHost func() string
+}
+
+// Resolve detects the project at projectPath with registry, choosing the
+// language forced by --language or .dockstart.yml. It returns nil when no
+// supported language is detected.
+func Resolve(registry *detector.DetectorRegistry, projectPath string, cfg *config.Config, flags Flags) (*detector.Resolution, error) {
+	// --language overrides the language forced in .dockstart.yml
+	forced := cfg.Language
+	if flags.Language != "" {
+		forced = flags.Language
+	}
+	if flags.Lockfiles || cfg.Lockfiles {
+		registry.UseLockfiles()
+	}
+	registry.UseServiceVersions(cfg.Versions)
+	return registry.Resolve(projectPath, forced)
+}
+
+// Configure applies .dockstart.yml and the flags to detection, the flags
+// taking precedence. It returns warnings about settings that were adjusted,
+// and an error if a setting is invalid.
+func Configure(detection *models.Detection, cfg *config.Config, projectName string, flags Flags) ([]string, error) {
+	var warnings []string
+	var err error
+	if detection.Worker, err = workerSettings(cfg.Worker, flags.Worker); err != nil {
+		return nil, err
+	}
+	if cfg.Worker.Command != "" {
+		detection.WorkerCommand = cfg.Worker.Command
+	}
+	if detection.FileProcessor, err = processorSettings(cfg.FileProcessor, flags.FileProcessor); err != nil {
+		return nil, err
+	}
+	detection.NonRoot = flags.NonRoot || cfg.NonRoot
+	detection.Hardened = flags.Hardened || cfg.Hardened
+	detection.Target = cfg.Target
+	if flags.Target != "" {
+		detection.Target = flags.Target
+	}
+	if err := models.ValidateTarget(detection.Target); err != nil {
+		return nil, err
+	}
+	detection.ComposeVersion = cfg.ComposeVersion
+	if flags.ComposeVersion != "" {
+		detection.ComposeVersion = flags.ComposeVersion
+	}
+	if err := models.ValidateComposeVersion(detection.ComposeVersion); err != nil {
+		return nil, err
+	}
+	detection.Environments = cfg.Environments
+	if len(flags.Environments) > 0 {
+		detection.Environments = flags.Environments
+	}
+	if err := models.ValidateEnvironments(detection.Environments); err != nil {
+		return nil, err
+	}
+	if slices.Contains(detection.Environments, models.EnvStaging) && detection.ProductionDockerfile == nil {
+		return nil, generator.ErrNoProductionDockerfile
+	}
+	if slices.Contains(detection.Environments, models.EnvStaging) && detection.ComposeVersion != "" && detection.ComposeVersion != models.ComposeV2 {
+		warnings = append(warnings, "--env staging: docker-compose.staging.yml resets keys with !reset, which needs Docker Compose v2.24 or later")
+	}
+	detection.Sidecars = models.SidecarPolicy{
+		Minimal:    flags.Minimal || cfg.Minimal,
+		Codespaces: detection.TargetsCodespaces(),
+		With:       flags.WithSidecars,
+		Without:    flags.WithoutSidecars,
+	}
+	if err := detection.Sidecars.Validate(); err != nil {
+		return nil, err
+	}
+	// Codespaces run Linux whatever the host, so only --windows applies there
+	detection.Windows = flags.Windows || cfg.Windows || (flags.HostWindows && !detection.TargetsCodespaces())
+	// A remote engine can't bind-mount the project; Codespaces run their own engine
+	detection.RemoteDocker = flags.RemoteDocker || cfg.RemoteDocker
+	if !detection.RemoteDocker && !detection.TargetsCodespaces() && flags.RemoteHost != nil {
+		detection.RemoteDocker = flags.RemoteHost() != ""
+	}
+	if flags.Prebuild || cfg.Prebuild.Enabled {
+		detection.Prebuild = &models.PrebuildOptions{Image: cfg.Prebuild.Image}
+		if detection.Prebuild.Image == "" {
+			detection.Prebuild.Image = generator.PrebuildImage(projectName)
+		}
+	}
+	detection.Forward = cfg.Forward
+	if len(flags.Forward) > 0 {
+		detection.Forward = flags.Forward
+	}
+	if err := models.ValidateForwards(detection.Forward); err != nil {
+		return nil, err
+	}
+	// The forwards bind-mount the host's sockets and files, which only a local engine can reach
+	if len(detection.Forward) > 0 && (detection.RemoteDocker || detection.TargetsCodespaces()) {
+		warnings = append(warnings, "--forward ignored: the host's sockets and files can't be mounted on a remote Docker engine or in Codespaces (VS Code forwards the SSH agent and git config itself)")
+		detection.Forward = nil
+	}
+	if cfg.Lifecycle.Install != "" {
+		detection.InstallCommand = cfg.Lifecycle.Install
+	}
+	if cfg.Lifecycle.Migrate != "" {
+		detection.MigrateCommand = cfg.Lifecycle.Migrate
+	}
+	if cfg.Testing.Command != "" {
+		detection.TestCommand = cfg.Testing.Command
+	}
+	if cfg.Testing.Coverage != "" {
+		detection.CoverageCommand = cfg.Testing.Coverage
+	}
+	detection.Persistence = models.PersistenceOptions{
+		History:         flags.Persist || cfg.Persistence.History,
+		Caches:          flags.Persist || cfg.Persistence.Caches,
+		Dotfiles:        cfg.Persistence.DotfilesURL(),
+		DotfilesInstall: cfg.Persistence.DotfilesInstall,
+	}
+	detection.Registries = models.RegistryOptions{
+		NPM:       cfg.Registries.NPM,
+		PyPI:      cfg.Registries.PyPI,
+		GoPrivate: cfg.Registries.GoPrivate,
+		GoProxy:   cfg.Registries.GoProxy,
+		Cargo:     cfg.Registries.Cargo,
+	}
+	detection.Proxy = models.ProxyOptions{
+		HTTP:    cfg.Proxy.HTTP,
+		HTTPS:   cfg.Proxy.HTTPS,
+		NoProxy: cfg.Proxy.NoProxy,
+	}
+	if detection.Proxy.HTTPS == "" {
+		detection.Proxy.HTTPS = detection.Proxy.HTTP
+	}
+	detection.Timezone = cfg.Timezone
+	detection.Locale = cfg.Locale
+	detection.Testing = models.TestingOptions{Isolation: cfg.Testing.Isolation}
+	logMaxSizeBytes, _ := cfg.Logging.MaxSizeBytes() // validated by config.Load
+	detection.Logging = models.LoggingOptions{
+		MaxSize:      strings.ToLower(cfg.Logging.MaxSize),
+		MaxSizeBytes: logMaxSizeBytes,
+		MaxFiles:     cfg.Logging.MaxFiles,
+		FileOutput:   cfg.Logging.FileOutput,
+	}
+	if flags.Gatus || cfg.StatusPage.Enabled {
+		detection.StatusPage = true
+	}
+	detection.HealthPath = cfg.StatusPage.HealthPath
+	if cfg.Postgres.TimescaleDB {
+		detection.TimescaleDB = true
+		if !detection.HasService("postgres") {
+			detection.Services = append(detection.Services, "postgres")
+		}
+	}
+	if len(cfg.Postgres.Databases) > 0 {
+		detection.PostgresDatabases = cfg.Postgres.Databases
+		if !detection.HasService("postgres") {
+			detection.Services = append(detection.Services, "postgres")
+		}
+	}
+	detection.PostgresExtensions = cfg.Postgres.Extensions
+	for _, role := range cfg.Postgres.Roles {
+		detection.PostgresRoles = append(detection.PostgresRoles, models.PostgresRole{
+			Name:      role.Name,
+			Password:  role.Password,
+			Superuser: role.Superuser,
+			ReadOnly:  role.ReadOnly,
+		})
+	}
+	if cfg.Postgres.ProdImport.Enabled {
+		detection.ProdImport = prodImportOptions(cfg.Postgres.ProdImport)
+	}
+	if (len(detection.PostgresExtensions) > 0 || len(detection.PostgresRoles) > 0 || detection.ProdImport != nil) && !detection.HasService("postgres") {
+		detection.Services = append(detection.Services, "postgres")
+	}
+	if len(cfg.Resources) > 0 {
+		detection.Resources = make(map[string]models.ResourceLimits, len(cfg.Resources))
+		for service, resource := range cfg.Resources {
+			detection.Resources[service] = models.ResourceLimits{Memory: resource.Memory, CPUs: resource.CPUs}
+		}
+	}
+	detection.Lifecycle = models.LifecycleOptions{
+		PostCreate: cfg.Lifecycle.PostCreate,
+		PostStart:  cfg.Lifecycle.PostStart,
+	}
+	if flags.ForceDockerfile {
+		detection.ExistingDockerfile = nil
+	}
+	if flags.Ollama {
+		detection.LocalLLM = true
+	}
+	if flags.WireMock {
+		detection.MockAPIs = true
+	}
+	if flags.Toxiproxy {
+		detection.ChaosProxy = true
+	}
+	if flags.GlitchTip {
+		detection.LocalErrorTracking = true
+	}
+	return warnings, nil
+}
+
+// workerSettings merges the --worker-* flags over the worker settings in .dockstart.yml.
+func workerSettings(cfg, flags config.Worker) (models.WorkerOptions, error) {
+	if flags.Concurrency != 0 {
+		cfg.Concurrency = flags.Concurrency
+	}
+	if flags.Replicas != 0 {
+		cfg.Replicas = flags.Replicas
+	}
+	if flags.Memory != "" {
+		cfg.Memory = flags.Memory
+	}
+	if flags.CPUs != "" {
+		cfg.CPUs = flags.CPUs
+	}
+	if flags.StopGracePeriod != "" {
+		cfg.StopGracePeriod = flags.StopGracePeriod
+	}
+	if err := cfg.Validate(); err != nil {
+		return models.WorkerOptions{}, fmt.Errorf("invalid worker settings: %w", err)
+	}
+
+	return models.WorkerOptions{
+		Concurrency:     cfg.Concurrency,
+		Replicas:        cfg.Replicas,
+		Memory:          cfg.Memory,
+		CPUs:            cfg.CPUs,
+		StopGracePeriod: cfg.StopGracePeriod,
+		Target:          cfg.Target,
+		Env:             cfg.Env,
+		DeadLetter: models.DeadLetterOptions{
+			Enabled:    cfg.DeadLetter.Enabled,
+			MaxRetries: cfg.DeadLetter.MaxRetries,
+			Backoff:    cfg.DeadLetter.Backoff,
+			Command:    cfg.DeadLetter.Command,
+		},
+	}, nil
+}
+
+// processorSettings merges the --processor-* flags over the file_processor settings in .dockstart.yml.
+func processorSettings(cfg, flags config.FileProcessor) (models.FileProcessorOptions, error) {
+	if len(flags.Types) > 0 {
+		cfg.Types = flags.Types
+	}
+	if len(flags.ThumbnailSizes) > 0 {
+		cfg.ThumbnailSizes = flags.ThumbnailSizes
+	}
+	if len(flags.AllowedMIMETypes) > 0 {
+		cfg.AllowedMIMETypes = flags.AllowedMIMETypes
+	}
+	if flags.MaxFileSize != "" {
+		cfg.MaxFileSize = flags.MaxFileSize
+	}
+	if flags.Watch != "" {
+		cfg.Watch = flags.Watch
+	}
+	if flags.Scan {
+		cfg.Scan = true
+	}
+	if flags.SanitizeImages {
+		cfg.SanitizeImages = true
+	}
+	if flags.Notify != "" {
+		cfg.Notify = flags.Notify
+	}
+	if flags.NotifyURL != "" {
+		cfg.NotifyURL = flags.NotifyURL
+	}
+	if flags.NotifyQueue != "" {
+		cfg.NotifyQueue = flags.NotifyQueue
+	}
+	if flags.Storage != "" {
+		cfg.Storage = flags.Storage
+	}
+	if err := cfg.Validate(); err != nil {
+		return models.FileProcessorOptions{}, fmt.Errorf("invalid file processor settings: %w", err)
+	}
+
+	maxFileSize, _ := cfg.MaxFileSizeBytes()
+	return models.FileProcessorOptions{
+		Types:            cfg.Types,
+		ThumbnailSizes:   cfg.ThumbnailSizes,
+		AllowedMIMETypes: cfg.AllowedMIMETypes,
+		MaxFileSize:      maxFileSize,
+		Watch:            cfg.Watch,
+		PollInterval:     cfg.PollInterval,
+		Scan:             cfg.Scan,
+		SanitizeImages:   cfg.SanitizeImages,
+		Notify:           cfg.Notify,
+		NotifyURL:        cfg.NotifyURL,
+		NotifyQueue:      cfg.NotifyQueue,
+		Storage:          cfg.Storage,
+	}, nil
+}
+
+// prodImportOptions converts the prod_import settings, with the anonymized
+// columns sorted so the generated script doesn't change between runs.
+func prodImportOptions(cfg config.ProdImport) *models.ProdImportOptions {
+	options := &models.ProdImportOptions{SourceEnv: cfg.SourceEnv, ExcludeData: cfg.ExcludeData}
+	if options.SourceEnv == "" {
+		options.SourceEnv = generator.DefaultProdImportSource
+	}
+	columns := slices.Sorted(maps.Keys(cfg.Anonymize))
+	for _, name := range columns {
+		parts := strings.Split(name, ".") // validated by config.Load
+		column := models.AnonymizedColumn{Strategy: cfg.Anonymize[name]}
+		if len(parts) == 3 {
+			column.Schema, parts = parts[0], parts[1:]
+		}
+		column.Table, column.Column = parts[0], parts[1]
+		options.Anonymize = append(options.Anonymize, column)
+	}
+	return options
+}
@@ -0,0 +1,77 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/config"
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestConfigure_FlagsOverrideConfig tests that the flags take precedence over
+// .dockstart.yml, and that .dockstart.yml applies on its own.
+func TestConfigure_FlagsOverrideConfig(t *testing.T) {
+	cfg := &config.Config{
+		Target:   models.TargetCodespaces,
+		Timezone: "Europe/Paris",
+		Worker:   config.Worker{Concurrency: 4, Memory: "512m"},
+		Postgres: config.Postgres{Roles: []config.PostgresRole{{Name: "reporting", ReadOnly: true}}},
+	}
+	detection := &models.Detection{Language: "node", Version: "20"}
+
+	_, err := Configure(detection, cfg, "shop", Flags{
+		Target: models.TargetLocal,
+		Worker: config.Worker{Concurrency: 8},
+	})
+	if err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if detection.Target != models.TargetLocal {
+		t.Errorf("Target = %q, want --target to win", detection.Target)
+	}
+	if detection.Worker.Concurrency != 8 || detection.Worker.Memory != "512m" {
+		t.Errorf("Worker = %+v, want --worker-concurrency over .dockstart.yml's memory", detection.Worker)
+	}
+	if detection.Timezone != "Europe/Paris" {
+		t.Errorf("Timezone = %q, want it from .dockstart.yml", detection.Timezone)
+	}
+	if !detection.HasService("postgres") || len(detection.PostgresRoles) != 1 {
+		t.Errorf("a PostgreSQL role should add postgres, got services %v, roles %v", detection.Services, detection.PostgresRoles)
+	}
+}
+
+// TestConfigure_Host tests the host-dependent settings: Windows line endings
+// and a remote Docker engine, neither of which applies to Codespaces.
+func TestConfigure_Host(t *testing.T) {
+	remote := func() string { return "tcp://build.example.com:2376" }
+
+	local := &models.Detection{Language: "go"}
+	if _, err := Configure(local, &config.Config{}, "api", Flags{HostWindows: true, RemoteHost: remote}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if !local.Windows || !local.RemoteDocker {
+		t.Errorf("Windows = %v, RemoteDocker = %v, want both from the host", local.Windows, local.RemoteDocker)
+	}
+
+	codespaces := &models.Detection{Language: "go"}
+	called := false
+	flags := Flags{
+		Target:      models.TargetCodespaces,
+		HostWindows: true,
+		RemoteHost:  func() string { called = true; return remote() },
+	}
+	if _, err := Configure(codespaces, &config.Config{}, "api", flags); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if codespaces.Windows || codespaces.RemoteDocker || called {
+		t.Errorf("Codespaces shouldn't depend on the host: Windows = %v, RemoteDocker = %v, RemoteHost called = %v",
+			codespaces.Windows, codespaces.RemoteDocker, called)
+	}
+}
+
+// TestConfigure_Invalid tests that an invalid setting is an error.
+func TestConfigure_Invalid(t *testing.T) {
+	detection := &models.Detection{Language: "node"}
+	if _, err := Configure(detection, &config.Config{}, "shop", Flags{Target: "mainframe"}); err == nil {
+		t.Error("Configure() should reject an unknown --target")
+	}
+}
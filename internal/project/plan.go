@@ -0,0 +1,752 @@
+package project
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jpequegn/dockstart/internal/docker"
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// PlanOptions are the steps a generation plan adds besides the generators
+// dockstart runs for every project.
+type PlanOptions struct {
+	// Report writes the generation report through fsys once every other step
+	// has run, given the step owning each file; nil leaves the report out
+	Report func(fsys generator.FS, projectPath string, owners map[string]string) error
+
+	// CIProviders are the CI pipelines to generate, as dockstart ci does
+	// (e.g., "github")
+	CIProviders []string
+
+	// TaskFormats are the task files to generate, as dockstart tasks does
+	// (e.g., "just")
+	TaskFormats []string
+}
+
+// BuildPlan returns the generators that produce the project's files, the
+// files each one writes, and the steps that must run before it.
+func BuildPlan(detection *models.Detection, projectPath, projectName string, opts PlanOptions) (*generator.Plan, error) {
+	plan := generator.NewPlan()
+	composeProject := docker.ProjectName(projectPath)
+
+	// devcontainer.json: an existing one is updated, keeping the user's settings
+	gen := generator.NewDevcontainerGenerator()
+	plan.Add(generator.Step{
+		Name:    "devcontainer.json",
+		Title:   "Generating devcontainer.json...",
+		Files:   []string{".devcontainer/devcontainer.json"},
+		Updates: true,
+		Preview: func(string) ([]byte, error) {
+			content, err := gen.MergeContent(detection, projectPath, projectName)
+			if err != nil {
+				return nil, fmt.Errorf("generation failed: %w", err)
+			}
+			return content, nil
+		},
+		Generate: func(fsys generator.FS, projectPath string) error {
+			gen.SetFS(fsys)
+			if err := gen.Generate(detection, projectPath, projectName); err != nil {
+				return fmt.Errorf("generation failed: %w", err)
+			}
+			return nil
+		},
+	})
+
+	// docker-compose.yml (when services or sidecars are detected), which the
+	// sidecar configuration below runs in
+	var inCompose []string
+	if detection.NeedsCompose() {
+		composeGen := generator.NewComposeGenerator()
+
+		// Random per-project passwords for the services, kept across runs. This
+		// runs first: a docker-compose.yml without them means the data volumes
+		// have the previous fixed passwords
+		credentialsGen := generator.NewCredentialsGenerator()
+		credentialNames, err := credentialsGen.Names(detection, projectName)
+		if err != nil {
+			return nil, fmt.Errorf("credentials generation failed: %w", err)
+		}
+		if len(credentialNames) > 0 {
+			plan.Add(generator.Step{
+				Name:    "credentials",
+				Title:   "Generating .env passwords...",
+				Files:   []string{generator.CredentialsFile},
+				Summary: fmt.Sprintf("🔑 Would create %s (%s)", generator.CredentialsFile, strings.Join(credentialNames, ", ")),
+				Generate: func(fsys generator.FS, projectPath string) error {
+					credentialsGen.SetFS(fsys)
+					if err := credentialsGen.Generate(detection, projectPath, projectName); err != nil {
+						return fmt.Errorf("credentials generation failed: %w", err)
+					}
+					return nil
+				},
+			})
+		}
+
+		plan.Add(generator.Step{
+			Name:  "docker-compose.yml",
+			Title: "Generating docker-compose.yml...",
+			Files: []string{".devcontainer/docker-compose.yml"},
+			Preview: func(string) ([]byte, error) {
+				content, err := composeGen.GenerateContent(detection, projectName)
+				if err != nil {
+					return nil, fmt.Errorf("compose generation failed: %w", err)
+				}
+				return content, nil
+			},
+			Generate: func(fsys generator.FS, projectPath string) error {
+				composeGen.SetFS(fsys)
+				if err := composeGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("compose generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+		inCompose = []string{"docker-compose.yml"}
+	}
+
+	// Log sidecar configuration (Fluent Bit)
+	logGen := generator.NewLogSidecarGenerator()
+	if detection.NeedsCompose() && logGen.ShouldGenerate(detection) {
+		content, err := logGen.GenerateContent(detection, projectName)
+		if err != nil {
+			return nil, fmt.Errorf("log sidecar generation failed: %w", err)
+		}
+		parsers, err := logGen.GenerateParsersContent(detection, projectName)
+		if err != nil {
+			return nil, fmt.Errorf("log sidecar generation failed: %w", err)
+		}
+		rotate, err := logGen.GenerateRotateScriptContent(detection, projectName)
+		if err != nil {
+			return nil, fmt.Errorf("log sidecar generation failed: %w", err)
+		}
+		contents := map[string][]byte{".devcontainer/fluent-bit.conf": content}
+		files := []string{".devcontainer/fluent-bit.conf"}
+		if parsers != nil {
+			files = append(files, ".devcontainer/"+generator.LogParsersFile)
+			contents[".devcontainer/"+generator.LogParsersFile] = parsers
+		}
+		if rotate != nil {
+			files = append(files, ".devcontainer/"+generator.LogRotateScript)
+			contents[".devcontainer/"+generator.LogRotateScript] = rotate
+		}
+		plan.Add(generator.Step{
+			Name:    "fluent-bit",
+			Title:   "Generating Fluent Bit configuration...",
+			Files:   files,
+			After:   inCompose,
+			Preview: func(rel string) ([]byte, error) { return contents[rel], nil },
+			Generate: func(fsys generator.FS, projectPath string) error {
+				logGen.SetFS(fsys)
+				if err := logGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("log sidecar generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Metrics sidecar files (Prometheus + Grafana config)
+	metricsGen := generator.NewMetricsSidecarGenerator()
+	if metricsGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:  "metrics",
+			Title: "Generating metrics stack configuration...",
+			Files: []string{
+				".devcontainer/prometheus/prometheus.yml",
+				".devcontainer/grafana/provisioning/datasources/prometheus.yml",
+				".devcontainer/grafana/provisioning/dashboards/provider.yml",
+				".devcontainer/grafana/provisioning/dashboards/app-metrics.json",
+			},
+			After:   inCompose,
+			Summary: "📊 Would create Prometheus and Grafana configuration files",
+			Generate: func(fsys generator.FS, projectPath string) error {
+				metricsGen.SetFS(fsys)
+				if err := metricsGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("metrics sidecar generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Database backup sidecar image, scripts, and crontab
+	backupGen := generator.NewBackupSidecarGenerator()
+	if detection.NeedsCompose() && backupGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:    "backup",
+			Title:   "Generating backup sidecar...",
+			Files:   backupGen.Files(detection),
+			After:   inCompose,
+			Summary: "💾 Would create Dockerfile.backup and the backup and restore scripts",
+			Generate: func(fsys generator.FS, projectPath string) error {
+				backupGen.SetFS(fsys)
+				if err := backupGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("backup sidecar generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// File processor sidecar image and processing scripts
+	processorGen := generator.NewProcessorSidecarGenerator()
+	if processorGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:    "file-processor",
+			Title:   "Generating file processor sidecar...",
+			Files:   processorGen.Files(detection, projectName),
+			After:   inCompose,
+			Summary: "🖼️  Would create Dockerfile.processor and the processing scripts",
+			Generate: func(fsys generator.FS, projectPath string) error {
+				processorGen.SetFS(fsys)
+				if err := processorGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("file processor sidecar generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Scheduler sidecar files (Supercronic crontab)
+	schedulerGen := generator.NewSchedulerSidecarGenerator()
+	if schedulerGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:    "scheduler",
+			Title:   "Generating scheduler sidecar...",
+			Files:   []string{".devcontainer/Dockerfile.scheduler", ".devcontainer/crontab.scheduler"},
+			After:   inCompose,
+			Summary: "⏰ Would create Dockerfile.scheduler and crontab.scheduler",
+			Generate: func(fsys generator.FS, projectPath string) error {
+				schedulerGen.SetFS(fsys)
+				if err := schedulerGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("scheduler sidecar generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// docker-compose.<env>.yml overrides layered over docker-compose.yml
+	overridesGen := generator.NewOverridesGenerator()
+	if overridesGen.ShouldGenerate(detection) {
+		files := overridesGen.Files(detection)
+		plan.Add(generator.Step{
+			Name:  "environments",
+			Title: "Generating environment overrides...",
+			Files: files,
+			After: inCompose,
+			Preview: func(rel string) ([]byte, error) {
+				for _, env := range detection.OverrideEnvironments() {
+					if generator.OverrideFile(env) == rel {
+						content, err := overridesGen.GenerateContent(detection, projectName, env)
+						if err != nil {
+							return nil, fmt.Errorf("environment override generation failed: %w", err)
+						}
+						return content, nil
+					}
+				}
+				return nil, fmt.Errorf("no environment override for %s", rel)
+			},
+			Generate: func(fsys generator.FS, projectPath string) error {
+				overridesGen.SetFS(fsys)
+				if err := overridesGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("environment override generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Buildx bake file building the stack's images together
+	bakeGen := generator.NewBakeGenerator()
+	if bakeGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:  "bake",
+			Title: "Generating docker-bake.hcl...",
+			Files: []string{generator.BakeFile},
+			After: inCompose,
+			Preview: func(string) ([]byte, error) {
+				content, err := bakeGen.GenerateContent(detection, projectName, composeProject)
+				if err != nil {
+					return nil, fmt.Errorf("bake file generation failed: %w", err)
+				}
+				return content, nil
+			},
+			Generate: func(fsys generator.FS, projectPath string) error {
+				bakeGen.SetFS(fsys)
+				if err := bakeGen.Generate(detection, projectPath, projectName, composeProject); err != nil {
+					return fmt.Errorf("bake file generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Keycloak realm import
+	keycloakGen := generator.NewKeycloakSidecarGenerator()
+	if keycloakGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:    "keycloak",
+			Title:   "Generating Keycloak realm...",
+			Files:   []string{".devcontainer/keycloak/realm.json"},
+			After:   inCompose,
+			Summary: "🔐 Would create .devcontainer/keycloak/realm.json",
+			Generate: func(fsys generator.FS, projectPath string) error {
+				keycloakGen.SetFS(fsys)
+				if err := keycloakGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("keycloak sidecar generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// LocalStack init script
+	localstackGen := generator.NewLocalStackSidecarGenerator()
+	if localstackGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:    "localstack",
+			Title:   "Generating LocalStack init script...",
+			Files:   []string{".devcontainer/localstack/init-aws.sh"},
+			After:   inCompose,
+			Summary: "☁️  Would create .devcontainer/localstack/init-aws.sh",
+			Generate: func(fsys generator.FS, projectPath string) error {
+				localstackGen.SetFS(fsys)
+				if err := localstackGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("localstack sidecar generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// RabbitMQ dead-letter exchange init script
+	deadLetterGen := generator.NewDeadLetterSidecarGenerator()
+	if deadLetterGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:    "rabbitmq-dlx",
+			Title:   "Generating RabbitMQ dead-letter init script...",
+			Files:   []string{".devcontainer/rabbitmq/init-dlx.sh"},
+			After:   inCompose,
+			Summary: "📮 Would create .devcontainer/rabbitmq/init-dlx.sh",
+			Generate: func(fsys generator.FS, projectPath string) error {
+				deadLetterGen.SetFS(fsys)
+				if err := deadLetterGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("dead-letter sidecar generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Document the generated stack
+	readmeGen := generator.NewReadmeGenerator()
+	if readmeGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:  "readme",
+			Title: "Generating README.devcontainer.md...",
+			Files: []string{".devcontainer/README.devcontainer.md"},
+			Preview: func(string) ([]byte, error) {
+				content, err := readmeGen.GenerateContent(detection, projectName)
+				if err != nil {
+					return nil, fmt.Errorf("readme generation failed: %w", err)
+				}
+				return content, nil
+			},
+			Generate: func(fsys generator.FS, projectPath string) error {
+				readmeGen.SetFS(fsys)
+				if err := readmeGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("readme generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Keep generated files LF on Windows checkouts
+	lineEndingsGen := generator.NewLineEndingsGenerator()
+	if lineEndingsGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:    "gitattributes",
+			Title:   "Generating .gitattributes...",
+			Files:   []string{".devcontainer/.gitattributes"},
+			Summary: "🪟 Would create .devcontainer/.gitattributes (LF line endings)",
+			Generate: func(fsys generator.FS, projectPath string) error {
+				lineEndingsGen.SetFS(fsys)
+				if err := lineEndingsGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("gitattributes generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Wait for the database and migrate when the container starts
+	migrateGen := generator.NewMigrateGenerator()
+	if migrateGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:    "migrate",
+			Title:   "Generating migrate.sh...",
+			Files:   []string{generator.MigrateScript},
+			Summary: fmt.Sprintf("🗃️  Would create %s (%s)", generator.MigrateScript, detection.MigrateCommand),
+			Generate: func(fsys generator.FS, projectPath string) error {
+				migrateGen.SetFS(fsys)
+				if err := migrateGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("migrate script generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Clone the dotfiles repository on container creation
+	dotfilesGen := generator.NewDotfilesGenerator()
+	if dotfilesGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:    "dotfiles",
+			Title:   "Generating dotfiles.sh...",
+			Files:   []string{generator.DotfilesScript},
+			Summary: fmt.Sprintf("🏠 Would create %s (%s)", generator.DotfilesScript, detection.Persistence.Dotfiles),
+			Generate: func(fsys generator.FS, projectPath string) error {
+				dotfilesGen.SetFS(fsys)
+				if err := dotfilesGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("dotfiles script generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Copy the project into the workspace volume of a remote Docker engine
+	remoteWorkspaceGen := generator.NewRemoteWorkspaceGenerator()
+	if remoteWorkspaceGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:    "sync-workspace",
+			Title:   "Generating sync-workspace.sh...",
+			Files:   []string{generator.SyncWorkspaceScript},
+			Summary: fmt.Sprintf("🌐 Would create %s (volume %s)", generator.SyncWorkspaceScript, generator.RemoteWorkspaceVolume(projectName)),
+			Generate: func(fsys generator.FS, projectPath string) error {
+				remoteWorkspaceGen.SetFS(fsys)
+				if err := remoteWorkspaceGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("workspace sync script generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Create the test database in the development PostgreSQL
+	testDatabaseGen := generator.NewTestDatabaseGenerator()
+	if testDatabaseGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:    "test-database",
+			Title:   "Generating test database init script...",
+			Files:   []string{generator.TestDatabaseScript},
+			After:   inCompose,
+			Summary: fmt.Sprintf("🧫 Would create %s (%s)", generator.TestDatabaseScript, generator.TestDatabaseName(projectName)),
+			Generate: func(fsys generator.FS, projectPath string) error {
+				testDatabaseGen.SetFS(fsys)
+				if err := testDatabaseGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("test database script generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// ClickHouse settings
+	clickhouseGen := generator.NewClickHouseGenerator()
+	if clickhouseGen.ShouldGenerate(detection) {
+		files := []string{".devcontainer/clickhouse/users.xml"}
+		if detection.NeedsMetrics() {
+			files = append(files, ".devcontainer/clickhouse/prometheus.xml")
+		}
+		plan.Add(generator.Step{
+			Name:  "clickhouse",
+			Title: "Generating ClickHouse configuration...",
+			Files: files,
+			After: inCompose,
+			Generate: func(fsys generator.FS, projectPath string) error {
+				clickhouseGen.SetFS(fsys)
+				if err := clickhouseGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("clickhouse configuration generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Create the additional databases in the development PostgreSQL
+	postgresDatabasesGen := generator.NewPostgresDatabasesGenerator()
+	if postgresDatabasesGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:    "postgres-databases",
+			Title:   "Generating database init script...",
+			Files:   []string{generator.PostgresDatabasesScript},
+			After:   inCompose,
+			Summary: fmt.Sprintf("🗄️  Would create %s (%s)", generator.PostgresDatabasesScript, strings.Join(detection.PostgresDatabases, ", ")),
+			Generate: func(fsys generator.FS, projectPath string) error {
+				postgresDatabasesGen.SetFS(fsys)
+				if err := postgresDatabasesGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("database script generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Create the extensions and roles in the development PostgreSQL
+	postgresSetupGen := generator.NewPostgresSetupGenerator()
+	if postgresSetupGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:    "postgres-setup",
+			Title:   "Generating extensions and roles script...",
+			Files:   []string{generator.PostgresSetupScript},
+			After:   inCompose,
+			Summary: fmt.Sprintf("🧩 Would create %s (%s)", generator.PostgresSetupScript, postgresSetupList(detection)),
+			Generate: func(fsys generator.FS, projectPath string) error {
+				postgresSetupGen.SetFS(fsys)
+				if err := postgresSetupGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("extensions and roles script generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Named snapshots of the development databases
+	snapshotGen := generator.NewSnapshotGenerator()
+	if snapshotGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:  "snapshots",
+			Title: "Generating database snapshot scripts...",
+			Files: snapshotGen.Files(),
+			After: inCompose,
+			Preview: func(rel string) ([]byte, error) {
+				content, err := snapshotGen.GenerateContent(detection, projectName, rel)
+				if err != nil {
+					return nil, fmt.Errorf("snapshot scripts generation failed: %w", err)
+				}
+				return content, nil
+			},
+			Generate: func(fsys generator.FS, projectPath string) error {
+				snapshotGen.SetFS(fsys)
+				if err := snapshotGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("snapshot scripts generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Import an anonymized copy of production, opted into in .dockstart.yml
+	prodImportGen := generator.NewProdImportGenerator()
+	if prodImportGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:    "prod-import",
+			Title:   "Generating production import script...",
+			Files:   []string{generator.ProdImportScript},
+			After:   inCompose,
+			Summary: fmt.Sprintf("🕶️  Would create %s (from $%s)", generator.ProdImportScript, detection.ProdImport.SourceEnv),
+			Generate: func(fsys generator.FS, projectPath string) error {
+				prodImportGen.SetFS(fsys)
+				if err := prodImportGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("production import script generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Scaffold the WireMock stub mappings
+	wireMockGen := generator.NewWireMockGenerator()
+	if wireMockGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:  "wiremock",
+			Title: "Generating WireMock mappings...",
+			Files: wireMockGen.Files(projectPath),
+			After: inCompose,
+			Generate: func(fsys generator.FS, projectPath string) error {
+				wireMockGen.SetFS(fsys)
+				if err := wireMockGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("wiremock scaffold generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Toxiproxy proxies and fault injection script
+	toxiproxyGen := generator.NewToxiproxyGenerator()
+	if toxiproxyGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:  "toxiproxy",
+			Title: "Generating Toxiproxy proxies...",
+			Files: toxiproxyGen.Files(),
+			After: inCompose,
+			Generate: func(fsys generator.FS, projectPath string) error {
+				toxiproxyGen.SetFS(fsys)
+				if err := toxiproxyGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("toxiproxy generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Gatus status page checks
+	gatusGen := generator.NewGatusGenerator()
+	if gatusGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:  "gatus",
+			Title: "Generating Gatus status page...",
+			Files: []string{generator.GatusConfigFile},
+			After: inCompose,
+			Preview: func(string) ([]byte, error) {
+				content, err := gatusGen.GenerateContent(detection, projectName)
+				if err != nil {
+					return nil, fmt.Errorf("gatus generation failed: %w", err)
+				}
+				return content, nil
+			},
+			Generate: func(fsys generator.FS, projectPath string) error {
+				gatusGen.SetFS(fsys)
+				if err := gatusGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("gatus generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Example email job, sent by the worker through Mailpit
+	mailExampleGen := generator.NewMailExampleGenerator()
+	if mailExampleGen.ShouldGenerate(detection) {
+		plan.Add(generator.Step{
+			Name:  "email-example",
+			Title: "Generating example email job...",
+			Files: []string{generator.MailExampleFile(detection)},
+			After: inCompose,
+			Preview: func(string) ([]byte, error) {
+				content, err := mailExampleGen.GenerateContent(detection, projectName)
+				if err != nil {
+					return nil, fmt.Errorf("email example generation failed: %w", err)
+				}
+				return content, nil
+			},
+			Generate: func(fsys generator.FS, projectPath string) error {
+				mailExampleGen.SetFS(fsys)
+				if err := mailExampleGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("email example generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Dockerfile, unless the project's own is reused
+	if !detection.ReusesDockerfile() {
+		dockerfileGen := generator.NewDockerfileGenerator()
+		plan.Add(generator.Step{
+			Name:  "Dockerfile",
+			Title: "Generating Dockerfile...",
+			Files: []string{".devcontainer/Dockerfile"},
+			Preview: func(string) ([]byte, error) {
+				content, err := dockerfileGen.GenerateContent(detection, projectName)
+				if err != nil {
+					return nil, fmt.Errorf("dockerfile generation failed: %w", err)
+				}
+				return content, nil
+			},
+			Generate: func(fsys generator.FS, projectPath string) error {
+				dockerfileGen.SetFS(fsys)
+				if err := dockerfileGen.Generate(detection, projectPath, projectName); err != nil {
+					return fmt.Errorf("dockerfile generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// CI pipelines, as dockstart ci writes them
+	for _, provider := range opts.CIProviders {
+		ciGen := generator.NewCIGenerator()
+		plan.Add(generator.Step{
+			Name:  "ci-" + provider,
+			Title: fmt.Sprintf("Generating %s...", generator.CIFile(provider)),
+			Files: []string{generator.CIFile(provider)},
+			Generate: func(fsys generator.FS, projectPath string) error {
+				ciGen.SetFS(fsys)
+				if err := ciGen.Generate(detection, projectPath, projectName, provider); err != nil {
+					return fmt.Errorf("ci generation failed: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	// Task files, as dockstart tasks writes them; the tasks run docker compose
+	if detection.NeedsCompose() {
+		for _, format := range opts.TaskFormats {
+			tasksGen := generator.NewTasksGenerator()
+			plan.Add(generator.Step{
+				Name:  "tasks-" + format,
+				Title: fmt.Sprintf("Generating %s...", generator.TasksFile(format)),
+				Files: []string{generator.TasksFile(format)},
+				After: inCompose,
+				Generate: func(fsys generator.FS, projectPath string) error {
+					tasksGen.SetFS(fsys)
+					if err := tasksGen.Generate(detection, projectPath, projectName, composeProject, format); err != nil {
+						return fmt.Errorf("tasks generation failed: %w", err)
+					}
+					return nil
+				},
+			})
+		}
+	}
+
+	// Record how the files were generated, once all of them are written
+	if opts.Report != nil {
+		plan.Add(generator.Step{
+			Name:  "report",
+			Title: "Recording the generation report...",
+			Files: []string{generator.ReportFile},
+			After: plan.Names(),
+			// The report lists the files the run actually wrote
+			Rerun: true,
+			Generate: func(fsys generator.FS, projectPath string) error {
+				owners, err := plan.Owners()
+				if err != nil {
+					return err
+				}
+				return opts.Report(fsys, projectPath, owners)
+			},
+		})
+	}
+
+	// Catch dependency mistakes and generators writing the same file before
+	// anything is written
+	if _, err := plan.Steps(); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// postgresSetupList formats the extensions and roles setup.sh creates, as
+// "uuid-ossp, pg_trgm; roles app_user, reporting".
+func postgresSetupList(detection *models.Detection) string {
+	var parts []string
+	if extensions := detection.SetupExtensions(); len(extensions) > 0 {
+		parts = append(parts, strings.Join(extensions, ", "))
+	}
+	if len(detection.PostgresRoles) > 0 {
+		names := make([]string, len(detection.PostgresRoles))
+		for i, role := range detection.PostgresRoles {
+			names[i] = role.Name
+		}
+		parts = append(parts, "roles "+strings.Join(names, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
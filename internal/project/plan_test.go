@@ -0,0 +1,59 @@
+package project
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jpequegn/dockstart/internal/generator"
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// TestBuildPlan_Options tests that the report, CI pipeline, and task file
+// steps are only added when asked for.
+func TestBuildPlan_Options(t *testing.T) {
+	detection := &models.Detection{Language: "node", Version: "20", Services: []string{"postgres"}}
+
+	plan, err := BuildPlan(detection, t.TempDir(), "shop", PlanOptions{})
+	if err != nil {
+		t.Fatalf("BuildPlan() error = %v", err)
+	}
+	for _, name := range plan.Names() {
+		if name == "report" || name == "ci-github" || name == "tasks-just" {
+			t.Errorf("step %q was added without being asked for", name)
+		}
+	}
+
+	report := func(generator.FS, string, map[string]string) error { return nil }
+	plan, err = BuildPlan(detection, t.TempDir(), "shop", PlanOptions{
+		Report:      report,
+		CIProviders: []string{"github"},
+		TaskFormats: []string{generator.TasksJust},
+	})
+	if err != nil {
+		t.Fatalf("BuildPlan() error = %v", err)
+	}
+	names := plan.Names()
+	for _, want := range []string{"docker-compose.yml", "ci-github", "tasks-just", "report"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("Names() = %v, want %q", names, want)
+		}
+	}
+	if names[len(names)-1] != "report" {
+		t.Errorf("the report should be the last step, got %v", names)
+	}
+}
+
+// TestBuildPlan_TasksNeedCompose tests that no task file is generated for a
+// project without docker-compose.yml, as dockstart tasks refuses to.
+func TestBuildPlan_TasksNeedCompose(t *testing.T) {
+	detection := &models.Detection{Language: "go", Version: "1.23"}
+	plan, err := BuildPlan(detection, t.TempDir(), "cli", PlanOptions{TaskFormats: generator.TaskFormats})
+	if err != nil {
+		t.Fatalf("BuildPlan() error = %v", err)
+	}
+	for _, name := range plan.Names() {
+		if name == "docker-compose.yml" || name == "tasks-just" {
+			t.Errorf("step %q added for a project without services", name)
+		}
+	}
+}
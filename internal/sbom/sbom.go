@@ -0,0 +1,270 @@
+// Package sbom builds software bills of materials (CycloneDX and SPDX) for
+// the dev stacks dockstart generates.
+package sbom
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// Component types.
+const (
+	// TypeLibrary is an application dependency from the project's manifest
+	TypeLibrary = "library"
+
+	// TypeContainer is a container image used by the generated stack
+	TypeContainer = "container"
+)
+
+// purlTypes maps detected languages to their package URL types.
+var purlTypes = map[string]string{
+	"node":   "npm",
+	"go":     "golang",
+	"python": "pypi",
+	"rust":   "cargo",
+}
+
+// Component is a package or image in the bill of materials.
+type Component struct {
+	// Type is TypeLibrary or TypeContainer
+	Type string
+
+	// Name is the package name or image repository (e.g., "express", "postgres")
+	Name string
+
+	// Version is the locked package version or image tag. Empty when unknown
+	Version string
+
+	// PURL is the package URL (e.g., "pkg:npm/express@4.18.2")
+	PURL string
+}
+
+// BOM is the bill of materials of a generated dev stack.
+type BOM struct {
+	// Project is the project name
+	Project string
+
+	// ToolVersion is the dockstart version that produced the BOM
+	ToolVersion string
+
+	// Timestamp is when the BOM was created
+	Timestamp time.Time
+
+	// Serial is the UUID identifying this BOM
+	Serial string
+
+	// Components are the app's dependencies followed by the stack's images
+	Components []Component
+}
+
+// New builds the BOM of a project from its detected dependencies and the
+// images the generated stack pulls. Dependency versions are only known when
+// lockfile parsing pinned them.
+func New(project, toolVersion string, detection *models.Detection, images []string) *BOM {
+	bom := &BOM{
+		Project:     project,
+		ToolVersion: toolVersion,
+		Timestamp:   time.Now().UTC(),
+		Serial:      newUUID(),
+	}
+
+	purlType := purlTypes[detection.Language]
+	for _, dep := range detection.Dependencies {
+		component := Component{
+			Type:    TypeLibrary,
+			Name:    dep,
+			Version: detection.PinnedVersions[dep],
+		}
+		if purlType != "" {
+			component.PURL = packageURL(purlType, dep, component.Version)
+		}
+		bom.Components = append(bom.Components, component)
+	}
+
+	for _, image := range images {
+		bom.Components = append(bom.Components, imageComponent(image))
+	}
+
+	return bom
+}
+
+// packageURL builds a package URL. Each segment of the name is escaped, so
+// npm scopes become %40scope.
+func packageURL(purlType, name, version string) string {
+	segments := strings.Split(name, "/")
+	for i, s := range segments {
+		segments[i] = escapePURL(s)
+	}
+
+	purl := "pkg:" + purlType + "/" + strings.Join(segments, "/")
+	if version != "" {
+		purl += "@" + escapePURL(version)
+	}
+	return purl
+}
+
+// escapePURL percent-encodes a package URL segment, including the @ that
+// separates the version.
+func escapePURL(s string) string {
+	return strings.ReplaceAll(url.PathEscape(s), "@", "%40")
+}
+
+// imageComponent describes an image reference (e.g., "grafana/grafana:10.2.0",
+// "ghcr.io/org/app:1"). Images from other registries than Docker Hub carry
+// the registry in the purl's repository_url qualifier.
+func imageComponent(image string) Component {
+	name, tag := image, ""
+	if at := strings.Index(name, "@"); at >= 0 {
+		name, tag = name[:at], name[at+1:]
+	} else if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		name, tag = name[:colon], name[colon+1:]
+	}
+
+	registry := ""
+	if first, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry, name = first, rest
+	}
+
+	purl := packageURL("docker", name, tag)
+	if registry != "" {
+		purl += "?repository_url=" + url.QueryEscape(registry)
+	}
+
+	return Component{Type: TypeContainer, Name: name, Version: tag, PURL: purl}
+}
+
+// CycloneDX renders the BOM as a CycloneDX 1.5 JSON document.
+func (b *BOM) CycloneDX() ([]byte, error) {
+	type component struct {
+		Type    string `json:"type"`
+		BOMRef  string `json:"bom-ref,omitempty"`
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+		PURL    string `json:"purl,omitempty"`
+	}
+
+	doc := struct {
+		BOMFormat    string `json:"bomFormat"`
+		SpecVersion  string `json:"specVersion"`
+		SerialNumber string `json:"serialNumber"`
+		Version      int    `json:"version"`
+		Metadata     struct {
+			Timestamp string `json:"timestamp"`
+			Tools     struct {
+				Components []component `json:"components"`
+			} `json:"tools"`
+			Component component `json:"component"`
+		} `json:"metadata"`
+		Components []component `json:"components"`
+	}{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + b.Serial,
+		Version:      1,
+		Components:   []component{},
+	}
+	doc.Metadata.Timestamp = b.Timestamp.Format(time.RFC3339)
+	doc.Metadata.Tools.Components = []component{{Type: "application", Name: "dockstart", Version: b.ToolVersion}}
+	doc.Metadata.Component = component{Type: "application", BOMRef: b.Project, Name: b.Project}
+
+	for _, c := range b.Components {
+		entry := component{Type: c.Type, Name: c.Name, Version: c.Version, PURL: c.PURL}
+		if c.PURL != "" {
+			entry.BOMRef = c.PURL
+		}
+		doc.Components = append(doc.Components, entry)
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// SPDX renders the BOM as an SPDX 2.3 JSON document.
+func (b *BOM) SPDX() ([]byte, error) {
+	type externalRef struct {
+		Category string `json:"referenceCategory"`
+		Type     string `json:"referenceType"`
+		Locator  string `json:"referenceLocator"`
+	}
+	type pkg struct {
+		Name             string        `json:"name"`
+		SPDXID           string        `json:"SPDXID"`
+		VersionInfo      string        `json:"versionInfo,omitempty"`
+		DownloadLocation string        `json:"downloadLocation"`
+		FilesAnalyzed    bool          `json:"filesAnalyzed"`
+		Purpose          string        `json:"primaryPackagePurpose"`
+		ExternalRefs     []externalRef `json:"externalRefs,omitempty"`
+	}
+	type relationship struct {
+		Element string `json:"spdxElementId"`
+		Type    string `json:"relationshipType"`
+		Related string `json:"relatedSpdxElement"`
+	}
+
+	doc := struct {
+		SPDXVersion       string `json:"spdxVersion"`
+		DataLicense       string `json:"dataLicense"`
+		SPDXID            string `json:"SPDXID"`
+		Name              string `json:"name"`
+		DocumentNamespace string `json:"documentNamespace"`
+		CreationInfo      struct {
+			Created  string   `json:"created"`
+			Creators []string `json:"creators"`
+		} `json:"creationInfo"`
+		Packages      []pkg          `json:"packages"`
+		Relationships []relationship `json:"relationships"`
+	}{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              b.Project,
+		DocumentNamespace: fmt.Sprintf("https://github.com/jpequegn/dockstart/sbom/%s-%s", url.PathEscape(b.Project), b.Serial),
+	}
+	doc.CreationInfo.Created = b.Timestamp.Format(time.RFC3339)
+	doc.CreationInfo.Creators = []string{"Tool: dockstart-" + b.ToolVersion}
+
+	const root = "SPDXRef-Project"
+	doc.Packages = append(doc.Packages, pkg{
+		Name:             b.Project,
+		SPDXID:           root,
+		DownloadLocation: "NOASSERTION",
+		Purpose:          "APPLICATION",
+	})
+	doc.Relationships = append(doc.Relationships, relationship{Element: "SPDXRef-DOCUMENT", Type: "DESCRIBES", Related: root})
+
+	for i, c := range b.Components {
+		entry := pkg{
+			Name:             c.Name,
+			SPDXID:           fmt.Sprintf("SPDXRef-Package-%d", i+1),
+			VersionInfo:      c.Version,
+			DownloadLocation: "NOASSERTION",
+			Purpose:          "LIBRARY",
+		}
+		if c.Type == TypeContainer {
+			entry.Purpose = "CONTAINER"
+		}
+		if c.PURL != "" {
+			entry.ExternalRefs = []externalRef{{Category: "PACKAGE-MANAGER", Type: "purl", Locator: c.PURL}}
+		}
+		doc.Packages = append(doc.Packages, entry)
+		doc.Relationships = append(doc.Relationships, relationship{Element: root, Type: "DEPENDS_ON", Related: entry.SPDXID})
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,163 @@
+package sbom
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jpequegn/dockstart/internal/models"
+)
+
+// testBOM returns a BOM with a fixed timestamp and serial.
+func testBOM() *BOM {
+	detection := &models.Detection{
+		Language:       "node",
+		Dependencies:   []string{"@prisma/client", "express"},
+		PinnedVersions: map[string]string{"express": "4.18.2"},
+	}
+	bom := New("myapp", "1.2.0", detection, []string{"postgres:16-alpine", "grafana/grafana:latest", "ghcr.io/org/tool:1.0"})
+	bom.Timestamp = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	bom.Serial = "00000000-0000-4000-8000-000000000000"
+	return bom
+}
+
+// TestNew tests collecting dependencies and images into components.
+func TestNew(t *testing.T) {
+	want := []Component{
+		{Type: TypeLibrary, Name: "@prisma/client", PURL: "pkg:npm/%40prisma/client"},
+		{Type: TypeLibrary, Name: "express", Version: "4.18.2", PURL: "pkg:npm/express@4.18.2"},
+		{Type: TypeContainer, Name: "postgres", Version: "16-alpine", PURL: "pkg:docker/postgres@16-alpine"},
+		{Type: TypeContainer, Name: "grafana/grafana", Version: "latest", PURL: "pkg:docker/grafana/grafana@latest"},
+		{Type: TypeContainer, Name: "org/tool", Version: "1.0", PURL: "pkg:docker/org/tool@1.0?repository_url=ghcr.io"},
+	}
+
+	got := testBOM().Components
+	if len(got) != len(want) {
+		t.Fatalf("Components = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Components[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if serial := New("x", "dev", &models.Detection{}, nil).Serial; len(serial) != 36 || serial[14] != '4' {
+		t.Errorf("Serial = %q, want a version 4 UUID", serial)
+	}
+}
+
+// TestImageComponent tests parsing image references.
+func TestImageComponent(t *testing.T) {
+	tests := []struct {
+		image string
+		want  Component
+	}{
+		{"redis", Component{Type: TypeContainer, Name: "redis", PURL: "pkg:docker/redis"}},
+		{"localhost:5000/app:dev", Component{Type: TypeContainer, Name: "app", Version: "dev", PURL: "pkg:docker/app@dev?repository_url=localhost%3A5000"}},
+		{"postgres@sha256:abc", Component{Type: TypeContainer, Name: "postgres", Version: "sha256:abc", PURL: "pkg:docker/postgres@sha256:abc"}},
+	}
+
+	for _, tt := range tests {
+		if got := imageComponent(tt.image); got != tt.want {
+			t.Errorf("imageComponent(%q) = %+v, want %+v", tt.image, got, tt.want)
+		}
+	}
+}
+
+// TestCycloneDX tests the CycloneDX document structure.
+func TestCycloneDX(t *testing.T) {
+	data, err := testBOM().CycloneDX()
+	if err != nil {
+		t.Fatalf("CycloneDX() error = %v", err)
+	}
+
+	var doc struct {
+		BOMFormat    string `json:"bomFormat"`
+		SpecVersion  string `json:"specVersion"`
+		SerialNumber string `json:"serialNumber"`
+		Metadata     struct {
+			Timestamp string `json:"timestamp"`
+			Component struct {
+				Name string `json:"name"`
+			} `json:"component"`
+		} `json:"metadata"`
+		Components []struct {
+			Type    string `json:"type"`
+			Name    string `json:"name"`
+			Version string `json:"version"`
+			PURL    string `json:"purl"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("CycloneDX() produced invalid JSON: %v", err)
+	}
+
+	if doc.BOMFormat != "CycloneDX" || doc.SpecVersion != "1.5" {
+		t.Errorf("bomFormat/specVersion = %s/%s", doc.BOMFormat, doc.SpecVersion)
+	}
+	if doc.SerialNumber != "urn:uuid:00000000-0000-4000-8000-000000000000" {
+		t.Errorf("serialNumber = %s", doc.SerialNumber)
+	}
+	if doc.Metadata.Timestamp != "2026-01-02T03:04:05Z" || doc.Metadata.Component.Name != "myapp" {
+		t.Errorf("metadata = %+v", doc.Metadata)
+	}
+	if len(doc.Components) != 5 {
+		t.Fatalf("components = %d, want 5", len(doc.Components))
+	}
+	if c := doc.Components[2]; c.Type != "container" || c.PURL != "pkg:docker/postgres@16-alpine" {
+		t.Errorf("components[2] = %+v", c)
+	}
+	if strings.Contains(string(data), `"version": ""`) {
+		t.Error("unknown versions should be omitted")
+	}
+}
+
+// TestSPDX tests the SPDX document structure.
+func TestSPDX(t *testing.T) {
+	data, err := testBOM().SPDX()
+	if err != nil {
+		t.Fatalf("SPDX() error = %v", err)
+	}
+
+	var doc struct {
+		SPDXVersion       string `json:"spdxVersion"`
+		DocumentNamespace string `json:"documentNamespace"`
+		CreationInfo      struct {
+			Creators []string `json:"creators"`
+		} `json:"creationInfo"`
+		Packages []struct {
+			SPDXID  string `json:"SPDXID"`
+			Name    string `json:"name"`
+			Purpose string `json:"primaryPackagePurpose"`
+		} `json:"packages"`
+		Relationships []struct {
+			Type    string `json:"relationshipType"`
+			Related string `json:"relatedSpdxElement"`
+		} `json:"relationships"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("SPDX() produced invalid JSON: %v", err)
+	}
+
+	if doc.SPDXVersion != "SPDX-2.3" {
+		t.Errorf("spdxVersion = %s", doc.SPDXVersion)
+	}
+	if !strings.HasSuffix(doc.DocumentNamespace, "/myapp-00000000-0000-4000-8000-000000000000") {
+		t.Errorf("documentNamespace = %s", doc.DocumentNamespace)
+	}
+	if len(doc.CreationInfo.Creators) != 1 || doc.CreationInfo.Creators[0] != "Tool: dockstart-1.2.0" {
+		t.Errorf("creators = %v", doc.CreationInfo.Creators)
+	}
+
+	// The project package plus one per component
+	if len(doc.Packages) != 6 {
+		t.Fatalf("packages = %d, want 6", len(doc.Packages))
+	}
+	if p := doc.Packages[3]; p.Name != "postgres" || p.Purpose != "CONTAINER" {
+		t.Errorf("packages[3] = %+v", p)
+	}
+	if len(doc.Relationships) != 6 || doc.Relationships[0].Type != "DESCRIBES" || doc.Relationships[5].Type != "DEPENDS_ON" {
+		t.Errorf("relationships = %+v", doc.Relationships)
+	}
+}
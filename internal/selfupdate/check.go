@@ -0,0 +1,79 @@
+package selfupdate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CheckInterval is how often the background check asks GitHub for the latest release.
+const CheckInterval = 24 * time.Hour
+
+// checkState is what the background check remembers between runs.
+type checkState struct {
+	// CheckedAt is when GitHub was last asked
+	CheckedAt time.Time `json:"checked_at"`
+
+	// Latest is the latest release version it reported
+	Latest string `json:"latest"`
+}
+
+// Checker finds the latest release version for the "new version available"
+// notice, asking GitHub at most once per Interval and remembering the answer
+// in StateFile in between.
+type Checker struct {
+	// Client asks GitHub for the latest release
+	Client *Client
+
+	// StateFile remembers the last check
+	StateFile string
+
+	// Interval is how long a check stays fresh
+	Interval time.Duration
+
+	// Now returns the current time
+	Now func() time.Time
+}
+
+// NewChecker creates a checker that keeps its state in the user cache
+// directory (e.g., ~/.cache/dockstart/update-check.json).
+func NewChecker() (*Checker, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	client := NewClient()
+	client.HTTP.Timeout = 5 * time.Second
+	return &Checker{
+		Client:    client,
+		StateFile: filepath.Join(cacheDir, "dockstart", "update-check.json"),
+		Interval:  CheckInterval,
+		Now:       time.Now,
+	}, nil
+}
+
+// Latest returns the latest release version, from the state file while the
+// last check is fresh, from GitHub otherwise.
+func (c *Checker) Latest() (string, error) {
+	var state checkState
+	if data, err := os.ReadFile(c.StateFile); err == nil && json.Unmarshal(data, &state) == nil {
+		if state.Latest != "" && c.Now().Sub(state.CheckedAt) < c.Interval {
+			return state.Latest, nil
+		}
+	}
+
+	release, err := c.Client.Latest()
+	if err != nil {
+		return "", err
+	}
+
+	// Failing to remember the answer only means asking again next time
+	state = checkState{CheckedAt: c.Now(), Latest: release.Version}
+	if data, err := json.Marshal(state); err == nil {
+		if os.MkdirAll(filepath.Dir(c.StateFile), 0755) == nil {
+			_ = os.WriteFile(c.StateFile, data, 0644)
+		}
+	}
+	return release.Version, nil
+}
@@ -0,0 +1,289 @@
+// Package selfupdate checks GitHub for newer dockstart releases and replaces
+// the running binary with a verified download.
+package selfupdate
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Repository is the GitHub repository dockstart is released from.
+const Repository = "jpequegn/dockstart"
+
+// ChecksumsAsset lists the SHA-256 checksum of every binary of a release, in
+// sha256sum format. ChecksumsAsset + ".sig" is its base64 Ed25519 signature.
+const ChecksumsAsset = "checksums.txt"
+
+// PublicKey is the base64 Ed25519 public key release checksums are signed
+// with. It is set at build time for release builds:
+//
+//	go build -ldflags "-X github.com/jpequegn/dockstart/internal/selfupdate.PublicKey=<key>"
+//
+// Builds without it only verify checksums.
+var PublicKey = ""
+
+// ErrNoAsset is returned when a release has no binary for this platform.
+var ErrNoAsset = errors.New("no release binary for this platform")
+
+// Release is a published dockstart release.
+type Release struct {
+	// Version is the release tag (e.g., "v1.4.0")
+	Version string `json:"tag_name"`
+
+	// URL is the release page
+	URL string `json:"html_url"`
+
+	// Assets are the files attached to the release
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	// Name is the file name (e.g., "dockstart_linux_amd64")
+	Name string `json:"name"`
+
+	// URL downloads the file
+	URL string `json:"browser_download_url"`
+}
+
+// Asset returns the release file with the given name, or nil.
+func (r *Release) Asset(name string) *Asset {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i]
+		}
+	}
+	return nil
+}
+
+// BinaryName returns the release binary name for a platform
+// (e.g., "dockstart_linux_amd64", "dockstart_windows_amd64.exe").
+func BinaryName(goos, goarch string) string {
+	name := fmt.Sprintf("dockstart_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Client talks to the GitHub releases API.
+type Client struct {
+	// API is the GitHub API base URL
+	API string
+
+	// HTTP sends the requests
+	HTTP *http.Client
+}
+
+// NewClient creates a client for api.github.com.
+func NewClient() *Client {
+	return &Client{
+		API:  "https://api.github.com",
+		HTTP: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Latest returns the latest published release.
+func (c *Client) Latest() (*Release, error) {
+	body, err := c.get(fmt.Sprintf("%s/repos/%s/releases/latest", strings.TrimSuffix(c.API, "/"), Repository))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check the latest release: %w", err)
+	}
+
+	var release Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		return nil, fmt.Errorf("failed to parse the latest release: %w", err)
+	}
+	if release.Version == "" {
+		return nil, errors.New("failed to parse the latest release: no tag")
+	}
+	return &release, nil
+}
+
+// Download fetches the release binary for this platform and verifies it against
+// the release checksums, and their signature when PublicKey is set.
+func (c *Client) Download(release *Release) ([]byte, error) {
+	name := BinaryName(runtime.GOOS, runtime.GOARCH)
+	binaryAsset := release.Asset(name)
+	if binaryAsset == nil {
+		return nil, fmt.Errorf("%w: %s has no %s", ErrNoAsset, release.Version, name)
+	}
+	checksumsAsset := release.Asset(ChecksumsAsset)
+	if checksumsAsset == nil {
+		return nil, fmt.Errorf("%s has no %s to verify the download with", release.Version, ChecksumsAsset)
+	}
+
+	checksums, err := c.get(checksumsAsset.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", ChecksumsAsset, err)
+	}
+	if PublicKey != "" {
+		sigAsset := release.Asset(ChecksumsAsset + ".sig")
+		if sigAsset == nil {
+			return nil, fmt.Errorf("%s has no %s.sig to verify the checksums with", release.Version, ChecksumsAsset)
+		}
+		signature, err := c.get(sigAsset.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s.sig: %w", ChecksumsAsset, err)
+		}
+		if err := VerifySignature(checksums, signature, PublicKey); err != nil {
+			return nil, err
+		}
+	}
+
+	binary, err := c.get(binaryAsset.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	if err := VerifyChecksum(binary, name, checksums); err != nil {
+		return nil, err
+	}
+	return binary, nil
+}
+
+// get fetches a URL, failing on non-2xx responses.
+func (c *Client) get(url string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "dockstart")
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// VerifyChecksum checks a file against its entry in a sha256sum-format list.
+func VerifyChecksum(content []byte, name string, checksums []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// sha256sum marks binary-mode entries with a leading "*"
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		sum := sha256.Sum256(content)
+		if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("checksum mismatch for %s: the download is corrupt or was tampered with", name)
+		}
+		return nil
+	}
+	return fmt.Errorf("no checksum for %s in %s", name, ChecksumsAsset)
+}
+
+// VerifySignature checks a base64 Ed25519 signature of message against a
+// base64 public key.
+func VerifySignature(message, signature []byte, publicKey string) error {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid release public key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil || !ed25519.Verify(ed25519.PublicKey(key), message, sig) {
+		return fmt.Errorf("invalid signature on %s: the release was not signed by the dockstart maintainers", ChecksumsAsset)
+	}
+	return nil
+}
+
+// Replace atomically replaces the binary at path: the new binary is written next
+// to it and renamed over it. Windows can't replace a running executable, so the
+// old binary is moved aside to path + ".old" first.
+func Replace(path string, binary []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".dockstart-upgrade-*")
+	if err != nil {
+		return fmt.Errorf("failed to write the new binary next to %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write the new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write the new binary: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0111); err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		old := path + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(path, old); err != nil {
+			return fmt.Errorf("failed to move the old binary aside: %w", err)
+		}
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// Newer reports whether latest is a later version than current. Both are
+// "vMAJOR.MINOR.PATCH" tags; a current version that isn't one (a "dev" build)
+// is never outdated.
+func Newer(current, latest string) bool {
+	c, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	for i := range c {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+// parseVersion parses "v1.2.3" (or "1.2.3"), ignoring pre-release and build suffixes.
+func parseVersion(version string) ([3]int, bool) {
+	var parsed [3]int
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return parsed, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return parsed, false
+		}
+		parsed[i] = n
+	}
+	return parsed, true
+}
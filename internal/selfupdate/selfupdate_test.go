@@ -0,0 +1,179 @@
+package selfupdate
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// releaseServer serves a fake GitHub release of binary, with its checksums and
+// their signature by key.
+func releaseServer(t *testing.T, binary []byte, key ed25519.PrivateKey) (*httptest.Server, *int) {
+	t.Helper()
+	name := BinaryName(runtime.GOOS, runtime.GOARCH)
+	sum := sha256.Sum256(binary)
+	checksums := fmt.Sprintf("%s  %s\n%s  dockstart_plan9_386\n", hex.EncodeToString(sum[:]), name, hex.EncodeToString(make([]byte, 32)))
+
+	requests := 0
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc("/repos/jpequegn/dockstart/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, `{"tag_name": "v1.2.0", "html_url": "https://github.com/jpequegn/dockstart/releases/v1.2.0", "assets": [
+			{"name": %q, "browser_download_url": "%s/dl/binary"},
+			{"name": "checksums.txt", "browser_download_url": "%s/dl/checksums"},
+			{"name": "checksums.txt.sig", "browser_download_url": "%s/dl/sig"}]}`,
+			name, server.URL, server.URL, server.URL)
+	})
+	mux.HandleFunc("/dl/binary", func(w http.ResponseWriter, r *http.Request) { w.Write(binary) })
+	mux.HandleFunc("/dl/checksums", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(checksums)) })
+	mux.HandleFunc("/dl/sig", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, []byte(checksums))) + "\n"))
+	})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// TestClient_Download tests fetching the latest release and verifying its binary.
+func TestClient_Download(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, _ := releaseServer(t, []byte("new dockstart"), private)
+	client := &Client{API: server.URL, HTTP: server.Client()}
+
+	release, err := client.Latest()
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	if release.Version != "v1.2.0" {
+		t.Errorf("Version = %q, want v1.2.0", release.Version)
+	}
+
+	defer func(key string) { PublicKey = key }(PublicKey)
+	PublicKey = base64.StdEncoding.EncodeToString(public)
+	binary, err := client.Download(release)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	if string(binary) != "new dockstart" {
+		t.Errorf("Download() = %q, want the release binary", binary)
+	}
+
+	// Checksums signed with another key are rejected
+	other, _, _ := ed25519.GenerateKey(nil)
+	PublicKey = base64.StdEncoding.EncodeToString(other)
+	if _, err := client.Download(release); err == nil {
+		t.Error("Download() should reject a signature by another key")
+	}
+
+	// A platform without a binary
+	release.Assets = release.Assets[1:]
+	if _, err := client.Download(release); !errors.Is(err, ErrNoAsset) {
+		t.Errorf("Download() error = %v, want ErrNoAsset", err)
+	}
+}
+
+// TestVerifyChecksum tests checking downloads against sha256sum output.
+func TestVerifyChecksum(t *testing.T) {
+	sum := sha256.Sum256([]byte("binary"))
+	checksums := []byte(hex.EncodeToString(sum[:]) + " *dockstart_linux_amd64\n")
+
+	if err := VerifyChecksum([]byte("binary"), "dockstart_linux_amd64", checksums); err != nil {
+		t.Errorf("VerifyChecksum() error = %v", err)
+	}
+	if err := VerifyChecksum([]byte("tampered"), "dockstart_linux_amd64", checksums); err == nil {
+		t.Error("VerifyChecksum() should reject a mismatched binary")
+	}
+	if err := VerifyChecksum([]byte("binary"), "dockstart_darwin_arm64", checksums); err == nil {
+		t.Error("VerifyChecksum() should reject a binary without a checksum")
+	}
+}
+
+// TestReplace tests swapping the binary in place.
+func TestReplace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dockstart")
+	if err := os.WriteFile(path, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Replace(path, []byte("new")); err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil || string(content) != "new" {
+		t.Errorf("binary = %q, %v; want the new binary", content, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("binary should stay executable, mode = %v", info.Mode())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if runtime.GOOS != "windows" && len(entries) != 1 {
+		t.Errorf("Replace() should leave no temporary files, got %d entries", len(entries))
+	}
+}
+
+// TestNewer tests comparing release versions.
+func TestNewer(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"v1.2.0", "v1.3.0", true},
+		{"v1.2.9", "v1.10.0", true},
+		{"1.2.0", "v2.0.0", true},
+		{"v1.3.0", "v1.3.0", false},
+		{"v1.4.0", "v1.3.0", false},
+		{"v1.3.0-rc.1", "v1.3.0", false},
+		{"dev", "v1.3.0", false},
+		{"v1.3.0", "nightly", false},
+	}
+	for _, tt := range tests {
+		if got := Newer(tt.current, tt.latest); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
+
+// TestChecker tests remembering the latest version between checks.
+func TestChecker(t *testing.T) {
+	_, private, _ := ed25519.GenerateKey(nil)
+	server, requests := releaseServer(t, []byte("new dockstart"), private)
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	checker := &Checker{
+		Client:    &Client{API: server.URL, HTTP: server.Client()},
+		StateFile: filepath.Join(t.TempDir(), "dockstart", "update-check.json"),
+		Interval:  CheckInterval,
+		Now:       func() time.Time { return now },
+	}
+
+	for i, want := range []int{1, 1, 2} {
+		if i == 2 {
+			now = now.Add(CheckInterval)
+		}
+		latest, err := checker.Latest()
+		if err != nil {
+			t.Fatalf("Latest() error = %v", err)
+		}
+		if latest != "v1.2.0" {
+			t.Errorf("Latest() = %q, want v1.2.0", latest)
+		}
+		if *requests != want {
+			t.Errorf("check %d: %d requests to GitHub, want %d", i+1, *requests, want)
+		}
+	}
+}
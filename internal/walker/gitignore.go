@@ -0,0 +1,145 @@
+package walker
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// rule is one pattern from a .gitignore file.
+type rule struct {
+	// base is the directory holding the .gitignore, relative to the walk root ("" for root)
+	base string
+
+	// pattern is the glob, without negation or leading/trailing slashes
+	pattern string
+
+	// negate re-includes paths matched by earlier rules ("!pattern")
+	negate bool
+
+	// dirOnly matches directories only ("pattern/")
+	dirOnly bool
+
+	// anchored matches the full path from base instead of any path component
+	// (the pattern contains a slash)
+	anchored bool
+}
+
+// loadGitignore appends the rules of dir/.gitignore to the inherited rules.
+func loadGitignore(root, dir string, inherited []rule) []rule {
+	file, err := os.Open(filepath.Join(root, filepath.FromSlash(dir), ".gitignore"))
+	if err != nil {
+		return inherited
+	}
+	defer file.Close()
+
+	// Copy so sibling directories don't share appended rules
+	rules := append([]rule{}, inherited...)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if r, ok := parseRule(dir, scanner.Text()); ok {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// parseRule parses a .gitignore line. Returns false for blank lines and comments.
+func parseRule(base, line string) (rule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule{}, false
+	}
+
+	r := rule{base: base}
+	if strings.HasPrefix(line, "!") {
+		r.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimPrefix(line, `\`)
+
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		r.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return rule{}, false
+	}
+
+	r.pattern = line
+	return r, true
+}
+
+// ignored applies the rules in order; the last matching rule wins.
+func ignored(rules []rule, rel string, isDir bool) bool {
+	result := false
+	for _, r := range rules {
+		if r.matches(rel, isDir) {
+			result = !r.negate
+		}
+	}
+	return result
+}
+
+// matches reports whether the rule matches a path relative to the walk root.
+func (r rule) matches(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+
+	if r.base != "" {
+		if !strings.HasPrefix(rel, r.base+"/") {
+			return false
+		}
+		rel = strings.TrimPrefix(rel, r.base+"/")
+	}
+
+	if r.anchored {
+		return matchSegments(splitPath(r.pattern), splitPath(rel))
+	}
+
+	// Unanchored patterns match the last path component at any depth
+	matched, _ := path.Match(r.pattern, path.Base(rel))
+	return matched
+}
+
+// matchSegments matches path segments against pattern segments, where "**"
+// matches zero or more whole segments.
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(parts) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], parts[0]); !matched {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// splitPath splits a slash-separated path into its non-empty segments.
+func splitPath(p string) []string {
+	var parts []string
+	for _, part := range strings.Split(p, "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
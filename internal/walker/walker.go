@@ -0,0 +1,110 @@
+// Package walker walks project trees, skipping vendored, generated, and git-ignored directories.
+package walker
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// DenyList lists directory names that are never walked: dependency caches,
+// virtualenvs, build output, and tool state. They are skipped even when no
+// .gitignore mentions them.
+var DenyList = []string{
+	".git",
+	"node_modules",
+	"bower_components",
+	"vendor",
+	".venv",
+	"venv",
+	"__pycache__",
+	".tox",
+	".mypy_cache",
+	".pytest_cache",
+	"target",
+	"dist",
+	"build",
+	".next",
+	".nuxt",
+	".svelte-kit",
+	".turbo",
+	".gradle",
+	".idea",
+	".devcontainer",
+	".dockstart",
+}
+
+// Dirs returns the directories under root, up to maxDepth levels deep, as
+// slash-separated paths relative to root. Results are breadth-first (shallowest
+// first) and sorted by name within a level, so callers can take the first match.
+// Directories in DenyList or ignored by a .gitignore (at root or nested) are
+// skipped along with everything beneath them.
+func Dirs(root string, maxDepth int) []string {
+	type queued struct {
+		rel   string
+		depth int
+		rules []rule
+	}
+
+	denied := make(map[string]bool, len(DenyList))
+	for _, name := range DenyList {
+		denied[name] = true
+	}
+
+	var dirs []string
+	queue := []queued{{rel: "", depth: 0, rules: loadGitignore(root, "", nil)}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current.depth >= maxDepth {
+			continue
+		}
+
+		names, hasGitignore := subdirs(filepath.Join(root, filepath.FromSlash(current.rel)), denied)
+		if hasGitignore && current.rel != "" {
+			current.rules = loadGitignore(root, current.rel, current.rules)
+		}
+
+		for _, name := range names {
+			rel := path.Join(current.rel, name)
+			if ignored(current.rules, rel, true) {
+				continue
+			}
+			dirs = append(dirs, rel)
+			queue = append(queue, queued{rel: rel, depth: current.depth + 1, rules: current.rules})
+		}
+	}
+
+	return dirs
+}
+
+// subdirs returns the sorted names of the directories in dir that aren't
+// denied, and whether dir has a .gitignore. Entries are read unsorted, since
+// source directories often hold far more files than subdirectories.
+func subdirs(dir string, denied map[string]bool) (names []string, hasGitignore bool) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	entries, err := f.ReadDir(-1)
+	if err != nil {
+		return nil, false
+	}
+	for _, entry := range entries {
+		switch {
+		case entry.IsDir():
+			if !denied[entry.Name()] {
+				names = append(names, entry.Name())
+			}
+		case entry.Name() == ".gitignore":
+			hasGitignore = true
+		}
+	}
+	sort.Strings(names)
+	return names, hasGitignore
+}
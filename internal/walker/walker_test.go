@@ -0,0 +1,136 @@
+package walker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDirs tests directory walking with the deny-list and .gitignore rules.
+func TestDirs(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "dockstart-walker-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for _, dir := range []string{
+		"apps/web/src",
+		"apps/api/uploads",
+		"node_modules/pkg/uploads",
+		"services/billing/vendor/lib",
+		".venv/lib",
+		"tmp/cache",
+		"logs",
+		"docs/generated",
+		"docs/guide",
+		"keep/logs",
+	} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, dir), 0755); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+	}
+
+	files := map[string]string{
+		".gitignore":      "# build output\ntmp/\nlogs\n!keep/logs\n/docs/generated\n",
+		"apps/.gitignore": "web/src\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	got := Dirs(tmpDir, 3)
+	want := []string{
+		"apps",
+		"docs",
+		"keep",
+		"services",
+		"apps/api",
+		"apps/web",
+		"docs/guide",
+		"keep/logs",
+		"services/billing",
+		"apps/api/uploads",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Dirs() =\n  %v\nwant\n  %v", got, want)
+	}
+
+	// Depth limits the walk
+	if got := Dirs(tmpDir, 1); strings.Join(got, ",") != "apps,docs,keep,services" {
+		t.Errorf("Dirs(depth 1) = %v", got)
+	}
+}
+
+// TestRuleMatches tests .gitignore pattern semantics.
+func TestRuleMatches(t *testing.T) {
+	tests := []struct {
+		base    string
+		line    string
+		path    string
+		isDir   bool
+		matches bool
+	}{
+		{"", "uploads", "uploads", true, true},
+		{"", "uploads", "apps/api/uploads", true, true},
+		{"", "uploads/", "uploads", false, false},
+		{"", "/uploads", "apps/uploads", true, false},
+		{"", "/uploads", "uploads", true, true},
+		{"", "apps/*/tmp", "apps/web/tmp", true, true},
+		{"", "apps/*/tmp", "apps/web/src/tmp", true, false},
+		{"", "**/cache", "a/b/cache", true, true},
+		{"", "docs/**/out", "docs/out", true, true},
+		{"", "*.log", "server.log", false, true},
+		{"apps", "dist", "apps/web/dist", true, true},
+		{"apps", "dist", "dist", true, false},
+		{"apps", "/web", "apps/web", true, true},
+	}
+
+	for _, tt := range tests {
+		r, ok := parseRule(tt.base, tt.line)
+		if !ok {
+			t.Fatalf("parseRule(%q) failed", tt.line)
+		}
+		if got := r.matches(tt.path, tt.isDir); got != tt.matches {
+			t.Errorf("rule %q (base %q) matches(%q, dir=%v) = %v, want %v",
+				tt.line, tt.base, tt.path, tt.isDir, got, tt.matches)
+		}
+	}
+
+	for _, line := range []string{"", "   ", "# comment", "/"} {
+		if _, ok := parseRule("", line); ok {
+			t.Errorf("parseRule(%q) should be skipped", line)
+		}
+	}
+}
+
+// BenchmarkDirs measures walking a wide source tree: 100 packages of 100
+// files each, with a node_modules tree that must not be descended into.
+func BenchmarkDirs(b *testing.B) {
+	root := b.TempDir()
+	for p := 0; p < 100; p++ {
+		src := filepath.Join(root, "packages", fmt.Sprintf("pkg-%03d", p), "src")
+		if err := os.MkdirAll(src, 0755); err != nil {
+			b.Fatal(err)
+		}
+		for f := 0; f < 100; f++ {
+			if err := os.WriteFile(filepath.Join(src, fmt.Sprintf("module-%03d.js", f)), nil, 0644); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(root, "node_modules", "a", "node_modules", "b"), 0755); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if dirs := Dirs(root, 4); len(dirs) != 201 {
+			b.Fatalf("Dirs() returned %d directories, want 201", len(dirs))
+		}
+	}
+}